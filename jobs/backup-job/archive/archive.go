@@ -0,0 +1,256 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FormatVersion is the version of the archive layout, restores refuse archives of a newer version
+const FormatVersion = 1
+
+const manifestName = "manifest.json"
+
+type EntryKind string
+
+const (
+	EntryKindPostgresTable  EntryKind = "postgres_table"
+	EntryKindIndexSettings  EntryKind = "opensearch_index_settings"
+	EntryKindIndexDocuments EntryKind = "opensearch_index_documents"
+)
+
+type Entry struct {
+	Path     string    `json:"path"`
+	Kind     EntryKind `json:"kind"`
+	Class    string    `json:"class"`
+	Database string    `json:"database,omitempty"`
+	Table    string    `json:"table,omitempty"`
+	Columns  []string  `json:"columns,omitempty"`
+	Index    string    `json:"index,omitempty"`
+	Records  int64     `json:"records"`
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+}
+
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Entries       []Entry   `json:"entries"`
+}
+
+// Writer writes a gzipped tar of the entries and their manifest encrypted with the passphrase
+type Writer struct {
+	tmpDir   string
+	enc      io.WriteCloser
+	gz       *gzip.Writer
+	tw       *tar.Writer
+	manifest Manifest
+}
+
+func NewWriter(w io.Writer, passphrase, tmpDir string) (*Writer, error) {
+	enc, err := NewEncryptWriter(w, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(enc)
+	return &Writer{
+		tmpDir: tmpDir,
+		enc:    enc,
+		gz:     gz,
+		tw:     tar.NewWriter(gz),
+		manifest: Manifest{
+			FormatVersion: FormatVersion,
+			CreatedAt:     time.Now().UTC(),
+		},
+	}, nil
+}
+
+// Add stores the content produced by write as the entry, write returns the number of records it wrote.
+// The content is staged in a temporary file since tar needs the size upfront.
+func (a *Writer) Add(entry Entry, write func(w io.Writer) (int64, error)) error {
+	if !isSafePath(entry.Path) || entry.Path == manifestName {
+		return fmt.Errorf("invalid entry path %s", entry.Path)
+	}
+
+	f, err := os.CreateTemp(a.tmpDir, "entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	hash := sha256.New()
+	records, err := write(io.MultiWriter(f, hash))
+	if err != nil {
+		return err
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	err = a.tw.WriteHeader(&tar.Header{
+		Name:    entry.Path,
+		Mode:    0600,
+		Size:    size,
+		ModTime: a.manifest.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(a.tw, f); err != nil {
+		return err
+	}
+
+	entry.Records = records
+	entry.Size = size
+	entry.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	a.manifest.Entries = append(a.manifest.Entries, entry)
+	return nil
+}
+
+// Close writes the manifest and flushes the archive, it does not close the underlying writer
+func (a *Writer) Close() (*Manifest, error) {
+	manifestJson, err := json.MarshalIndent(a.manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = a.tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0600,
+		Size:    int64(len(manifestJson)),
+		ModTime: a.manifest.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = a.tw.Write(manifestJson); err != nil {
+		return nil, err
+	}
+	if err = a.tw.Close(); err != nil {
+		return nil, err
+	}
+	if err = a.gz.Close(); err != nil {
+		return nil, err
+	}
+	if err = a.enc.Close(); err != nil {
+		return nil, err
+	}
+	return &a.manifest, nil
+}
+
+// Extract decrypts the archive into dir and verifies every file against the checksums of the manifest.
+// Nothing should be restored from dir if Extract fails.
+func Extract(r io.Reader, passphrase, dir string) (*Manifest, error) {
+	dec, err := NewDecryptReader(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(dec)
+	if err != nil {
+		return nil, wrapReadError(err)
+	}
+	tr := tar.NewReader(gz)
+
+	type extracted struct {
+		size   int64
+		sha256 string
+	}
+	files := make(map[string]extracted)
+	var manifest *Manifest
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, wrapReadError(err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected archive entry %s", hdr.Name)
+		}
+
+		if hdr.Name == manifestName {
+			manifest = &Manifest{}
+			if err = json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("failed to read manifest: %w", wrapReadError(err))
+			}
+			continue
+		}
+		if !isSafePath(hdr.Name) {
+			return nil, fmt.Errorf("invalid archive entry path %s", hdr.Name)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if err = os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return nil, err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.New()
+		size, err := io.Copy(io.MultiWriter(f, hash), tr)
+		f.Close()
+		if err != nil {
+			return nil, wrapReadError(err)
+		}
+		files[hdr.Name] = extracted{size: size, sha256: hex.EncodeToString(hash.Sum(nil))}
+	}
+	// read to the end so the last chunk is authenticated and truncation is detected
+	if _, err = io.Copy(io.Discard, gz); err != nil {
+		return nil, wrapReadError(err)
+	}
+	if _, err = io.Copy(io.Discard, dec); err != nil {
+		return nil, wrapReadError(err)
+	}
+
+	if manifest == nil {
+		return nil, errors.New("archive has no manifest")
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than the supported version %d", manifest.FormatVersion, FormatVersion)
+	}
+	for _, entry := range manifest.Entries {
+		file, ok := files[entry.Path]
+		if !ok {
+			return nil, fmt.Errorf("archive entry %s is missing", entry.Path)
+		}
+		if file.size != entry.Size || file.sha256 != entry.SHA256 {
+			return nil, fmt.Errorf("archive entry %s does not match its checksum", entry.Path)
+		}
+		delete(files, entry.Path)
+	}
+	for name := range files {
+		return nil, fmt.Errorf("archive entry %s is not in the manifest", name)
+	}
+	return manifest, nil
+}
+
+func wrapReadError(err error) error {
+	if errors.Is(err, ErrAuthentication) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	return fmt.Errorf("failed to read archive: %w", err)
+}
+
+func isSafePath(p string) bool {
+	if p == "" || path.IsAbs(p) || strings.Contains(p, "\\") {
+		return false
+	}
+	clean := path.Clean(p)
+	return clean == p && clean != "." && !strings.HasPrefix(clean, "../") && clean != ".."
+}
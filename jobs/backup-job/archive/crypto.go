@@ -0,0 +1,267 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// The encrypted archive is a header followed by AES-256-GCM sealed chunks. The key is derived from the
+// passphrase with argon2id using the salt and parameters of the header, and the header is authenticated as
+// additional data of every chunk. The nonce of a chunk is the nonce prefix of the header, the chunk counter
+// and a flag marking the last chunk, so reordered, dropped or truncated chunks fail to open.
+
+const (
+	encryptionMagic   = "OCBK"
+	encryptionVersion = 1
+	kdfArgon2id       = 1
+
+	chunkSize      = 64 * 1024
+	saltSize       = 16
+	noncePrefixLen = 7
+	headerSize     = len(encryptionMagic) + 1 + 1 + 4 + 4 + 1 + saltSize + noncePrefixLen
+
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+)
+
+var ErrAuthentication = errors.New("archive authentication failed, the passphrase is wrong or the archive is corrupted")
+
+type encryptionHeader struct {
+	time        uint32
+	memory      uint32
+	threads     uint8
+	salt        [saltSize]byte
+	noncePrefix [noncePrefixLen]byte
+}
+
+func (h encryptionHeader) marshal() []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, headerSize))
+	buf.WriteString(encryptionMagic)
+	buf.WriteByte(encryptionVersion)
+	buf.WriteByte(kdfArgon2id)
+	_ = binary.Write(buf, binary.BigEndian, h.time)
+	_ = binary.Write(buf, binary.BigEndian, h.memory)
+	buf.WriteByte(h.threads)
+	buf.Write(h.salt[:])
+	buf.Write(h.noncePrefix[:])
+	return buf.Bytes()
+}
+
+func readEncryptionHeader(r io.Reader) (encryptionHeader, []byte, error) {
+	raw := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return encryptionHeader{}, nil, fmt.Errorf("failed to read archive header: %w", err)
+	}
+	if string(raw[:len(encryptionMagic)]) != encryptionMagic {
+		return encryptionHeader{}, nil, errors.New("not a backup archive")
+	}
+	rest := raw[len(encryptionMagic):]
+	if rest[0] != encryptionVersion {
+		return encryptionHeader{}, nil, fmt.Errorf("unsupported archive encryption version %d", rest[0])
+	}
+	if rest[1] != kdfArgon2id {
+		return encryptionHeader{}, nil, fmt.Errorf("unsupported archive key derivation %d", rest[1])
+	}
+	rest = rest[2:]
+
+	var h encryptionHeader
+	h.time = binary.BigEndian.Uint32(rest[0:4])
+	h.memory = binary.BigEndian.Uint32(rest[4:8])
+	h.threads = rest[8]
+	rest = rest[9:]
+	copy(h.salt[:], rest[:saltSize])
+	copy(h.noncePrefix[:], rest[saltSize:])
+	if h.time == 0 || h.time > 16 || h.memory == 0 || h.memory > 1024*1024 || h.threads == 0 {
+		return encryptionHeader{}, nil, errors.New("invalid archive key derivation parameters")
+	}
+	return h, raw, nil
+}
+
+func (h encryptionHeader) aead(passphrase string) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), h.salt[:], h.time, h.memory, h.threads, 32)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (h encryptionHeader) nonce(counter uint32, last bool) []byte {
+	nonce := make([]byte, noncePrefixLen+5)
+	copy(nonce, h.noncePrefix[:])
+	binary.BigEndian.PutUint32(nonce[noncePrefixLen:], counter)
+	if last {
+		nonce[noncePrefixLen+4] = 1
+	}
+	return nonce
+}
+
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  encryptionHeader
+	ad      []byte
+	buf     []byte
+	counter uint32
+	closed  bool
+}
+
+// NewEncryptWriter returns a writer encrypting everything written to it into w. Close must be called to
+// write the last chunk, it does not close w.
+func NewEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is required")
+	}
+	h := encryptionHeader{
+		time:    argon2Time,
+		memory:  argon2Memory,
+		threads: argon2Threads,
+	}
+	if _, err := rand.Read(h.salt[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(h.noncePrefix[:]); err != nil {
+		return nil, err
+	}
+	aead, err := h.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	ad := h.marshal()
+	if _, err = w.Write(ad); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		header: h,
+		ad:     ad,
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed encrypt writer")
+	}
+	n := 0
+	for len(p) > 0 {
+		// a full chunk is only sealed once more data arrives, so the last chunk is always sealed by Close
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return n, err
+			}
+		}
+		c := copy(e.buf[len(e.buf):chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	if e.counter == ^uint32(0) {
+		return errors.New("archive is too large")
+	}
+	sealed := e.aead.Seal(nil, e.header.nonce(e.counter, last), e.buf, e.ad)
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.counter++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	header  encryptionHeader
+	ad      []byte
+	sealed  []byte
+	plain   []byte
+	counter uint32
+	done    bool
+}
+
+// NewDecryptReader returns a reader decrypting and authenticating the archive read from r. Read returns
+// ErrAuthentication if any chunk was modified and io.ErrUnexpectedEOF if the archive is truncated.
+func NewDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	h, ad, err := readEncryptionHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := h.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		r:      bufio.NewReaderSize(r, chunkSize+2*aead.Overhead()),
+		aead:   aead,
+		header: h,
+		ad:     ad,
+		sealed: make([]byte, chunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.sealed)
+	switch {
+	case errors.Is(err, io.EOF):
+		return io.ErrUnexpectedEOF
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		// a short chunk is the last one
+		d.done = true
+	case err != nil:
+		return err
+	default:
+		if _, err := d.r.Peek(1); errors.Is(err, io.EOF) {
+			d.done = true
+		} else if err != nil {
+			return err
+		}
+	}
+	if n < d.aead.Overhead() {
+		return io.ErrUnexpectedEOF
+	}
+
+	plain, err := d.aead.Open(d.sealed[:0:0], d.header.nonce(d.counter, d.done), d.sealed[:n], d.ad)
+	if err != nil {
+		return ErrAuthentication
+	}
+	d.counter++
+	d.plain = plain
+	return nil
+}
@@ -0,0 +1,229 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+const testPassphrase = "correct horse battery staple"
+
+func encrypt(t *testing.T, plain []byte, passphrase string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, passphrase)
+	if err != nil {
+		t.Fatalf("failed to create encrypt writer: %v", err)
+	}
+	if _, err := w.Write(plain); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decrypt(encrypted []byte, passphrase string) ([]byte, error) {
+	r, err := NewDecryptReader(bytes.NewReader(encrypted), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("failed to generate data: %v", err)
+	}
+	return b
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty", size: 0},
+		{name: "one byte", size: 1},
+		{name: "less than a chunk", size: chunkSize - 1},
+		{name: "exactly one chunk", size: chunkSize},
+		{name: "one byte over a chunk", size: chunkSize + 1},
+		{name: "several chunks", size: 3*chunkSize + 17},
+		{name: "exactly several chunks", size: 2 * chunkSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain := randomBytes(t, tt.size)
+			encrypted := encrypt(t, plain, testPassphrase)
+			if tt.size > 0 && bytes.Contains(encrypted, plain) {
+				t.Fatal("encrypted archive contains the plain data")
+			}
+
+			decrypted, err := decrypt(encrypted, testPassphrase)
+			if err != nil {
+				t.Fatalf("failed to decrypt: %v", err)
+			}
+			if !bytes.Equal(decrypted, plain) {
+				t.Fatalf("decrypted %d bytes do not match the %d plain bytes", len(decrypted), len(plain))
+			}
+		})
+	}
+}
+
+func TestEncryptSmallWrites(t *testing.T) {
+	plain := randomBytes(t, 2*chunkSize+100)
+
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, testPassphrase)
+	if err != nil {
+		t.Fatalf("failed to create encrypt writer: %v", err)
+	}
+	for start := 0; start < len(plain); start += 1000 {
+		if _, err := w.Write(plain[start:min(start+1000, len(plain))]); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	decrypted, err := decrypt(buf.Bytes(), testPassphrase)
+	if err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+	if !bytes.Equal(decrypted, plain) {
+		t.Fatal("decrypted data does not match the plain data")
+	}
+}
+
+func TestEncryptUsesFreshSaltAndNonce(t *testing.T) {
+	plain := []byte("backup")
+	first := encrypt(t, plain, testPassphrase)
+	second := encrypt(t, plain, testPassphrase)
+	if bytes.Equal(first[:headerSize], second[:headerSize]) {
+		t.Fatal("archives encrypted with the same passphrase share their salt and nonce prefix")
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	plain := randomBytes(t, 2*chunkSize+100)
+	encrypted := encrypt(t, plain, testPassphrase)
+	sealedChunkSize := chunkSize + 16
+
+	flip := func(i int) []byte {
+		b := bytes.Clone(encrypted)
+		b[i] ^= 0x01
+		return b
+	}
+	swapChunks := func() []byte {
+		b := bytes.Clone(encrypted)
+		first := b[headerSize : headerSize+sealedChunkSize]
+		second := b[headerSize+sealedChunkSize : headerSize+2*sealedChunkSize]
+		swapped := append(bytes.Clone(second), first...)
+		copy(b[headerSize:], swapped)
+		return b
+	}
+
+	tests := []struct {
+		name       string
+		encrypted  []byte
+		passphrase string
+		wantErrs   []error
+	}{
+		{
+			name:       "wrong passphrase",
+			encrypted:  encrypted,
+			passphrase: "wrong passphrase",
+			wantErrs:   []error{ErrAuthentication},
+		},
+		{
+			name:       "modified chunk",
+			encrypted:  flip(headerSize + 10),
+			passphrase: testPassphrase,
+			wantErrs:   []error{ErrAuthentication},
+		},
+		{
+			name:       "modified salt",
+			encrypted:  flip(headerSize - noncePrefixLen - 1),
+			passphrase: testPassphrase,
+			wantErrs:   []error{ErrAuthentication},
+		},
+		{
+			name:       "reordered chunks",
+			encrypted:  swapChunks(),
+			passphrase: testPassphrase,
+			wantErrs:   []error{ErrAuthentication},
+		},
+		{
+			name:       "last chunk dropped",
+			encrypted:  encrypted[:headerSize+2*sealedChunkSize],
+			passphrase: testPassphrase,
+			wantErrs:   []error{ErrAuthentication, io.ErrUnexpectedEOF},
+		},
+		{
+			name:       "truncated inside a chunk",
+			encrypted:  encrypted[:headerSize+sealedChunkSize+100],
+			passphrase: testPassphrase,
+			wantErrs:   []error{ErrAuthentication, io.ErrUnexpectedEOF},
+		},
+		{
+			name:       "chunks dropped",
+			encrypted:  encrypted[:headerSize],
+			passphrase: testPassphrase,
+			wantErrs:   []error{ErrAuthentication, io.ErrUnexpectedEOF},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decrypt(tt.encrypted, tt.passphrase)
+			if err == nil {
+				t.Fatal("expected decryption to fail")
+			}
+			for _, want := range tt.wantErrs {
+				if errors.Is(err, want) {
+					return
+				}
+			}
+			t.Fatalf("expected one of %v, got %v", tt.wantErrs, err)
+		})
+	}
+}
+
+func TestDecryptInvalidHeader(t *testing.T) {
+	encrypted := encrypt(t, []byte("backup"), testPassphrase)
+
+	badMagic := bytes.Clone(encrypted)
+	copy(badMagic, "NOPE")
+	badVersion := bytes.Clone(encrypted)
+	badVersion[len(encryptionMagic)] = encryptionVersion + 1
+
+	tests := []struct {
+		name      string
+		encrypted []byte
+	}{
+		{name: "not an archive", encrypted: badMagic},
+		{name: "unsupported version", encrypted: badVersion},
+		{name: "short header", encrypted: encrypted[:headerSize-1]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDecryptReader(bytes.NewReader(tt.encrypted), testPassphrase); err == nil {
+				t.Fatal("expected the header to be rejected")
+			}
+		})
+	}
+}
+
+func TestEncryptRequiresPassphrase(t *testing.T) {
+	if _, err := NewEncryptWriter(io.Discard, ""); err == nil {
+		t.Fatal("expected an error for an empty passphrase")
+	}
+}
@@ -0,0 +1,260 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/config"
+	"github.com/opengovern/opencomply/jobs/backup-job/archive"
+	"github.com/opengovern/opencomply/jobs/backup-job/opensearch"
+	"github.com/opengovern/opencomply/jobs/backup-job/postgres"
+	"github.com/opengovern/opencomply/jobs/backup-job/storage"
+	"github.com/opengovern/opencomply/jobs/backup-job/types"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func Command() *cobra.Command {
+	var (
+		cnf types.BackupConfig
+	)
+	config.ReadFromEnv(&cnf, nil)
+	if cnf.WorkDir == "" {
+		cnf.WorkDir = types.DefaultWorkDir
+	}
+
+	cmd := &cobra.Command{
+		Use:   "backup-job",
+		Short: "Backs up and restores the platform databases and indices",
+	}
+	cmd.AddCommand(backupCommand(&cnf), restoreCommand(&cnf))
+	return cmd
+}
+
+func backupCommand(cnf *types.BackupConfig) *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup",
+		Short: "Writes an encrypted archive of the databases and indices to the configured storage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			logger, err := zap.NewProduction()
+			if err != nil {
+				return err
+			}
+			store, err := setup(*cnf)
+			if err != nil {
+				return err
+			}
+
+			name := fmt.Sprintf("opencomply-backup-%s%s", time.Now().UTC().Format("20060102T150405Z"), types.ArchiveExtension)
+			archivePath := filepath.Join(cnf.WorkDir, name)
+			defer os.Remove(archivePath)
+			if err = createArchive(cmd.Context(), logger, *cnf, archivePath); err != nil {
+				return err
+			}
+
+			if err = store.Upload(cmd.Context(), name, archivePath); err != nil {
+				return fmt.Errorf("failed to upload archive: %w", err)
+			}
+			logger.Info("backup completed", zap.String("archive", name))
+			return nil
+		},
+	}
+}
+
+func createArchive(ctx context.Context, logger *zap.Logger, cnf types.BackupConfig, archivePath string) error {
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := archive.NewWriter(f, cnf.Passphrase, cnf.WorkDir)
+	if err != nil {
+		return err
+	}
+
+	for _, database := range types.BackupDatabases(cnf.Databases) {
+		conn, err := postgres.Connect(ctx, cnf.PostgreSQL, database)
+		if err != nil {
+			return err
+		}
+		err = postgres.DumpDatabase(ctx, logger, conn, database, string(types.DatabaseClass(database)), w)
+		conn.Close(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to dump database %s: %w", database, err)
+		}
+	}
+
+	client, err := opensearch.NewClient(cnf.ElasticSearch)
+	if err != nil {
+		return err
+	}
+	indices, err := opensearch.ListIndices(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to list indices: %w", err)
+	}
+	for _, index := range indices {
+		if err = opensearch.DumpIndex(ctx, logger, client, index, string(types.IndexClass(index)), w); err != nil {
+			return err
+		}
+	}
+
+	manifest, err := w.Close()
+	if err != nil {
+		return err
+	}
+	logger.Info("archive created", zap.Int("entries", len(manifest.Entries)))
+	return f.Close()
+}
+
+func restoreCommand(cnf *types.BackupConfig) *cobra.Command {
+	var (
+		archiveName string
+		classes     string
+		verifyOnly  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restores the databases and indices of the data classes from an archive",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			logger, err := zap.NewProduction()
+			if err != nil {
+				return err
+			}
+			selected, err := types.ParseDataClasses(classes)
+			if err != nil {
+				return err
+			}
+			store, err := setup(*cnf)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			archivePath := filepath.Join(cnf.WorkDir, filepath.Base(archiveName))
+			defer os.Remove(archivePath)
+			if err = store.Download(ctx, archiveName, archivePath); err != nil {
+				return err
+			}
+
+			dir, err := os.MkdirTemp(cnf.WorkDir, "restore-*")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			f, err := os.Open(archivePath)
+			if err != nil {
+				return err
+			}
+			manifest, err := archive.Extract(f, cnf.Passphrase, dir)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("archive verification failed: %w", err)
+			}
+			logger.Info("archive verified", zap.Time("created_at", manifest.CreatedAt), zap.Int("entries", len(manifest.Entries)))
+			if verifyOnly {
+				return nil
+			}
+
+			return restoreArchive(ctx, logger, *cnf, manifest, selected, dir)
+		},
+	}
+	cmd.Flags().StringVar(&archiveName, "archive", "", "Name of the archive in the storage")
+	cmd.Flags().StringVar(&classes, "classes", "", "Comma separated data classes to restore, all of them if empty")
+	cmd.Flags().BoolVar(&verifyOnly, "verify-only", false, "Only verify the integrity of the archive")
+	_ = cmd.MarkFlagRequired("archive")
+	return cmd
+}
+
+type indexEntries struct {
+	definition *archive.Entry
+	documents  *archive.Entry
+}
+
+func restoreArchive(ctx context.Context, logger *zap.Logger, cnf types.BackupConfig, manifest *archive.Manifest,
+	selected map[types.DataClass]bool, dir string) error {
+	databases := make(map[string][]archive.Entry)
+	indices := make(map[string]*indexEntries)
+	for i := range manifest.Entries {
+		entry := manifest.Entries[i]
+		if !selected[types.DataClass(entry.Class)] {
+			continue
+		}
+		switch entry.Kind {
+		case archive.EntryKindPostgresTable:
+			databases[entry.Database] = append(databases[entry.Database], entry)
+		case archive.EntryKindIndexSettings, archive.EntryKindIndexDocuments:
+			if indices[entry.Index] == nil {
+				indices[entry.Index] = &indexEntries{}
+			}
+			if entry.Kind == archive.EntryKindIndexSettings {
+				indices[entry.Index].definition = &entry
+			} else {
+				indices[entry.Index].documents = &entry
+			}
+		default:
+			return fmt.Errorf("unknown archive entry kind %s", entry.Kind)
+		}
+	}
+	if len(databases) == 0 && len(indices) == 0 {
+		return errors.New("the archive has nothing to restore for the selected data classes")
+	}
+
+	for _, database := range sortedKeys(databases) {
+		conn, err := postgres.Connect(ctx, cnf.PostgreSQL, database)
+		if err != nil {
+			return err
+		}
+		err = postgres.RestoreDatabase(ctx, logger, conn, database, databases[database], dir)
+		conn.Close(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(indices) > 0 {
+		client, err := opensearch.NewClient(cnf.ElasticSearch)
+		if err != nil {
+			return err
+		}
+		for _, index := range sortedKeys(indices) {
+			entries := indices[index]
+			if entries.definition == nil || entries.documents == nil {
+				return fmt.Errorf("archive is missing the definition or documents of index %s", index)
+			}
+			if err = opensearch.RestoreIndex(ctx, logger, client, index, *entries.definition, *entries.documents, dir); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Info("restore completed", zap.Int("databases", len(databases)), zap.Int("indices", len(indices)))
+	return nil
+}
+
+func setup(cnf types.BackupConfig) (storage.Store, error) {
+	if cnf.Passphrase == "" {
+		return nil, errors.New("passphrase is required")
+	}
+	if err := os.MkdirAll(cnf.WorkDir, 0700); err != nil {
+		return nil, fmt.Errorf("failure creating work dir: %w", err)
+	}
+	return storage.New(cnf)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
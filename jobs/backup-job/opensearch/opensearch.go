@@ -0,0 +1,297 @@
+package opensearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/config"
+	"github.com/opengovern/opencomply/jobs/backup-job/archive"
+	"github.com/opensearch-project/opensearch-go/v4"
+	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"go.uber.org/zap"
+)
+
+const (
+	batchSize      = 1000
+	scrollDuration = 5 * time.Minute
+)
+
+// unrestorableSettings are the index settings set by the cluster at index creation
+var unrestorableSettings = []string{
+	"uuid", "creation_date", "version", "provided_name", "resize", "routing", "verified_before_close", "history", "replication",
+}
+
+type indexDefinition struct {
+	Settings json.RawMessage `json:"settings"`
+	Mappings json.RawMessage `json:"mappings"`
+}
+
+type document struct {
+	ID      string          `json:"_id"`
+	Routing string          `json:"_routing,omitempty"`
+	Source  json.RawMessage `json:"_source"`
+}
+
+func NewClient(cfg config.ElasticSearch) (*opensearchapi.Client, error) {
+	return opensearchapi.NewClient(opensearchapi.Config{
+		Client: opensearch.Config{
+			Addresses:           []string{cfg.Address},
+			Username:            cfg.Username,
+			Password:            cfg.Password,
+			CompressRequestBody: true,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+				},
+			},
+		},
+	})
+}
+
+// ListIndices returns the platform indices, the system and security audit indices are skipped
+func ListIndices(ctx context.Context, client *opensearchapi.Client) ([]string, error) {
+	resp, err := client.Cat.Indices(ctx, &opensearchapi.CatIndicesReq{})
+	if err != nil {
+		return nil, err
+	}
+	var indices []string
+	for _, index := range resp.Indices {
+		if strings.HasPrefix(index.Index, ".") || strings.HasPrefix(index.Index, "security-auditlog-") {
+			continue
+		}
+		indices = append(indices, index.Index)
+	}
+	return indices, nil
+}
+
+// DumpIndex adds the settings, mappings and documents of the index to the archive
+func DumpIndex(ctx context.Context, logger *zap.Logger, client *opensearchapi.Client, index, class string, w *archive.Writer) error {
+	definition, err := getIndexDefinition(ctx, client, index)
+	if err != nil {
+		return fmt.Errorf("failed to get definition of index %s: %w", index, err)
+	}
+	err = w.Add(archive.Entry{
+		Path:  fmt.Sprintf("opensearch/%s/index.json", index),
+		Kind:  archive.EntryKindIndexSettings,
+		Class: class,
+		Index: index,
+	}, func(out io.Writer) (int64, error) {
+		return 1, json.NewEncoder(out).Encode(definition)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = w.Add(archive.Entry{
+		Path:  fmt.Sprintf("opensearch/%s/documents.jsonl", index),
+		Kind:  archive.EntryKindIndexDocuments,
+		Class: class,
+		Index: index,
+	}, func(out io.Writer) (int64, error) {
+		return dumpDocuments(ctx, client, index, out)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to dump documents of index %s: %w", index, err)
+	}
+	logger.Info("index dumped", zap.String("index", index))
+	return nil
+}
+
+func getIndexDefinition(ctx context.Context, client *opensearchapi.Client, index string) (*indexDefinition, error) {
+	settingsResp, err := client.Indices.Settings.Get(ctx, &opensearchapi.SettingsGetReq{Indices: []string{index}})
+	if err != nil {
+		return nil, err
+	}
+	mappingResp, err := client.Indices.Mapping.Get(ctx, &opensearchapi.MappingGetReq{Indices: []string{index}})
+	if err != nil {
+		return nil, err
+	}
+
+	var settings map[string]map[string]any
+	if err = json.Unmarshal(settingsResp.Indices[index].Settings, &settings); err != nil {
+		return nil, err
+	}
+	for _, key := range unrestorableSettings {
+		delete(settings["index"], key)
+	}
+	settingsJson, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &indexDefinition{
+		Settings: settingsJson,
+		Mappings: mappingResp.Indices[index].Mappings,
+	}, nil
+}
+
+func dumpDocuments(ctx context.Context, client *opensearchapi.Client, index string, out io.Writer) (int64, error) {
+	resp, err := client.Search(ctx, &opensearchapi.SearchReq{
+		Indices: []string{index},
+		Body:    strings.NewReader(`{"query":{"match_all":{}}}`),
+		Params: opensearchapi.SearchParams{
+			Scroll: scrollDuration,
+			Size:   opensearchapi.ToPointer(batchSize),
+			Sort:   []string{"_doc"},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	scrollID := resp.ScrollID
+	defer func() {
+		if scrollID != nil {
+			_, _ = client.Scroll.Delete(context.Background(), opensearchapi.ScrollDeleteReq{ScrollIDs: []string{*scrollID}})
+		}
+	}()
+
+	encoder := json.NewEncoder(out)
+	var count int64
+	hits := resp.Hits.Hits
+	for len(hits) > 0 {
+		for _, hit := range hits {
+			if err = encoder.Encode(document{ID: hit.ID, Routing: hit.Routing, Source: hit.Source}); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if scrollID == nil {
+			break
+		}
+
+		scrollResp, err := client.Scroll.Get(ctx, opensearchapi.ScrollGetReq{
+			ScrollID: *scrollID,
+			Params:   opensearchapi.ScrollGetParams{Scroll: scrollDuration},
+		})
+		if err != nil {
+			return count, err
+		}
+		if scrollResp.ScrollID != nil {
+			scrollID = scrollResp.ScrollID
+		}
+		hits = scrollResp.Hits.Hits
+	}
+	return count, nil
+}
+
+// RestoreIndex recreates the index from its archived definition and documents, an existing index is deleted
+func RestoreIndex(ctx context.Context, logger *zap.Logger, client *opensearchapi.Client, index string, definitionEntry, documentsEntry archive.Entry, dir string) error {
+	definitionJson, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(definitionEntry.Path)))
+	if err != nil {
+		return err
+	}
+	var definition indexDefinition
+	if err = json.Unmarshal(definitionJson, &definition); err != nil {
+		return fmt.Errorf("failed to read definition of index %s: %w", index, err)
+	}
+
+	_, err = client.Indices.Delete(ctx, opensearchapi.IndicesDeleteReq{
+		Indices: []string{index},
+		Params:  opensearchapi.IndicesDeleteParams{IgnoreUnavailable: opensearchapi.ToPointer(true)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete index %s: %w", index, err)
+	}
+	_, err = client.Indices.Create(ctx, opensearchapi.IndicesCreateReq{
+		Index: index,
+		Body:  bytes.NewReader(definitionJson),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create index %s: %w", index, err)
+	}
+
+	count, err := restoreDocuments(ctx, client, index, filepath.Join(dir, filepath.FromSlash(documentsEntry.Path)))
+	if err != nil {
+		return fmt.Errorf("failed to restore documents of index %s: %w", index, err)
+	}
+	if count != documentsEntry.Records {
+		return fmt.Errorf("restored %d documents of index %s, the archive has %d", count, index, documentsEntry.Records)
+	}
+	if _, err = client.Indices.Refresh(ctx, &opensearchapi.IndicesRefreshReq{Indices: []string{index}}); err != nil {
+		return err
+	}
+	logger.Info("index restored", zap.String("index", index), zap.Int64("documents", count))
+	return nil
+}
+
+func restoreDocuments(ctx context.Context, client *opensearchapi.Client, index, path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var count int64
+	var body bytes.Buffer
+	batch := 0
+	flush := func() error {
+		if batch == 0 {
+			return nil
+		}
+		resp, err := client.Bulk(ctx, opensearchapi.BulkReq{Index: index, Body: bytes.NewReader(body.Bytes())})
+		if err != nil {
+			return err
+		}
+		if resp.Errors {
+			for _, item := range resp.Items {
+				for _, result := range item {
+					if result.Error != nil {
+						return fmt.Errorf("failed to index document %s: %s", result.ID, result.Error.Reason)
+					}
+				}
+			}
+			return errors.New("bulk request failed")
+		}
+		count += int64(batch)
+		body.Reset()
+		batch = 0
+		return nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 256*1024*1024)
+	for scanner.Scan() {
+		var doc document
+		if err = json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			return count, err
+		}
+		action := map[string]map[string]string{"index": {"_id": doc.ID}}
+		if doc.Routing != "" {
+			action["index"]["routing"] = doc.Routing
+		}
+		actionJson, err := json.Marshal(action)
+		if err != nil {
+			return count, err
+		}
+		body.Write(actionJson)
+		body.WriteByte('\n')
+		body.Write(doc.Source)
+		body.WriteByte('\n')
+		batch++
+
+		if batch >= batchSize {
+			if err = flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return count, err
+	}
+	if err = flush(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
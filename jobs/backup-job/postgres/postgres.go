@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/opengovern/og-util/pkg/config"
+	"github.com/opengovern/opencomply/jobs/backup-job/archive"
+	"go.uber.org/zap"
+)
+
+func Connect(ctx context.Context, cfg config.Postgres, database string) (*pgx.Conn, error) {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.Username, cfg.Password),
+		Host:   net.JoinHostPort(cfg.Host, fmt.Sprint(cfg.Port)),
+		Path:   "/" + database,
+	}
+	if cfg.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": []string{cfg.SSLMode}}.Encode()
+	}
+	conn, err := pgx.Connect(ctx, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database %s: %w", database, err)
+	}
+	return conn, nil
+}
+
+// DumpDatabase adds every table of the public schema to the archive as CSV. The tables are read in a single
+// repeatable read transaction so they are consistent with each other.
+func DumpDatabase(ctx context.Context, logger *zap.Logger, conn *pgx.Conn, database, class string, w *archive.Writer) error {
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tables, err := listTables(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		columns, err := listColumns(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+
+		entry := archive.Entry{
+			Path:     fmt.Sprintf("postgres/%s/%s.csv", database, table),
+			Kind:     archive.EntryKindPostgresTable,
+			Class:    class,
+			Database: database,
+			Table:    table,
+			Columns:  columns,
+		}
+		err = w.Add(entry, func(out io.Writer) (int64, error) {
+			tag, err := tx.Conn().PgConn().CopyTo(ctx, out, fmt.Sprintf("COPY %s (%s) TO STDOUT WITH (FORMAT csv)",
+				pgx.Identifier{table}.Sanitize(), columnList(columns)))
+			if err != nil {
+				return 0, err
+			}
+			return tag.RowsAffected(), nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to dump table %s.%s: %w", database, table, err)
+		}
+		logger.Info("table dumped", zap.String("database", database), zap.String("table", table))
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RestoreDatabase replaces the rows of the archived tables of the database in a single transaction.
+// The tables must exist already, they are created by the migrations of the services. Triggers and foreign
+// keys are not enforced while loading since the tables are loaded in any order, this requires a superuser.
+func RestoreDatabase(ctx context.Context, logger *zap.Logger, conn *pgx.Conn, database string, entries []archive.Entry, dir string) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, "SET LOCAL session_replication_role = replica"); err != nil {
+		return fmt.Errorf("failed to disable triggers, restore requires a superuser: %w", err)
+	}
+
+	tables, err := listTables(ctx, tx)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for _, table := range tables {
+		existing[table] = true
+	}
+	var identifiers []string
+	for _, entry := range entries {
+		if !existing[entry.Table] {
+			return fmt.Errorf("table %s does not exist in database %s, run the service migrations first", entry.Table, database)
+		}
+		identifiers = append(identifiers, pgx.Identifier{entry.Table}.Sanitize())
+	}
+	if len(identifiers) == 0 {
+		return nil
+	}
+	if _, err = tx.Exec(ctx, "TRUNCATE "+strings.Join(identifiers, ", ")); err != nil {
+		return fmt.Errorf("failed to truncate tables of database %s: %w", database, err)
+	}
+
+	for _, entry := range entries {
+		if err = restoreTable(ctx, tx, entry, dir); err != nil {
+			return fmt.Errorf("failed to restore table %s.%s: %w", database, entry.Table, err)
+		}
+		if err = resetSequences(ctx, tx, entry.Table); err != nil {
+			return fmt.Errorf("failed to reset sequences of table %s.%s: %w", database, entry.Table, err)
+		}
+		logger.Info("table restored", zap.String("database", database), zap.String("table", entry.Table),
+			zap.Int64("rows", entry.Records))
+	}
+
+	return tx.Commit(ctx)
+}
+
+func restoreTable(ctx context.Context, tx pgx.Tx, entry archive.Entry, dir string) error {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(entry.Path)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tag, err := tx.Conn().PgConn().CopyFrom(ctx, f, fmt.Sprintf("COPY %s (%s) FROM STDIN WITH (FORMAT csv)",
+		pgx.Identifier{entry.Table}.Sanitize(), columnList(entry.Columns)))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() != entry.Records {
+		return fmt.Errorf("restored %d rows, the archive has %d", tag.RowsAffected(), entry.Records)
+	}
+	return nil
+}
+
+// resetSequences moves the sequences of the serial and identity columns past the restored rows
+func resetSequences(ctx context.Context, tx pgx.Tx, table string) error {
+	rows, err := tx.Query(ctx, `SELECT column_name, pg_get_serial_sequence(quote_ident(table_name), column_name)
+FROM information_schema.columns
+WHERE table_schema = 'public' AND table_name = $1 AND pg_get_serial_sequence(quote_ident(table_name), column_name) IS NOT NULL`, table)
+	if err != nil {
+		return err
+	}
+	sequences, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) ([2]string, error) {
+		var column, sequence string
+		err := row.Scan(&column, &sequence)
+		return [2]string{column, sequence}, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sequences {
+		column := pgx.Identifier{s[0]}.Sanitize()
+		_, err = tx.Exec(ctx, fmt.Sprintf("SELECT setval($1, COALESCE(MAX(%s), 1), MAX(%s) IS NOT NULL) FROM %s",
+			column, column, pgx.Identifier{table}.Sanitize()), s[1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func listTables(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(ctx, `SELECT table_name FROM information_schema.tables
+WHERE table_schema = 'public' AND table_type = 'BASE TABLE' ORDER BY table_name`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func listColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(ctx, `SELECT column_name FROM information_schema.columns
+WHERE table_schema = 'public' AND table_name = $1 AND is_generated = 'NEVER' ORDER BY ordinal_position`, table)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func columnList(columns []string) string {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, pgx.Identifier{column}.Sanitize())
+	}
+	return strings.Join(quoted, ", ")
+}
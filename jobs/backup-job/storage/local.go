@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore keeps the archives in a directory, usually a mounted volume
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (s *LocalStore) Upload(_ context.Context, name, path string) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	// the archive is copied next to its destination first so a partial copy never has the archive name
+	target := filepath.Join(s.dir, filepath.Base(name))
+	if err := copyFile(path, target+".partial"); err != nil {
+		return err
+	}
+	return os.Rename(target+".partial", target)
+}
+
+func (s *LocalStore) Download(_ context.Context, name, path string) error {
+	return copyFile(filepath.Join(s.dir, filepath.Base(name)), path)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
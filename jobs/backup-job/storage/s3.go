@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/opengovern/opencomply/jobs/backup-job/types"
+)
+
+// partSize is the size of the multipart upload parts, S3 allows up to 10000 parts
+const partSize = 64 * 1024 * 1024
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Store keeps the archives in an S3 compatible bucket, requests use path style addressing so any
+// S3 compatible store works
+type S3Store struct {
+	endpoint    *url.URL
+	region      string
+	bucket      string
+	prefix      string
+	credentials aws.Credentials
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+func NewS3Store(cfg types.S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3 bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	return &S3Store{
+		endpoint: endpoint,
+		region:   cfg.Region,
+		bucket:   cfg.Bucket,
+		prefix:   cfg.Prefix,
+		credentials: aws.Credentials{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+		},
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			o.DisableURIPathEscaping = true
+		}),
+		client: &http.Client{Timeout: 30 * time.Minute},
+	}, nil
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (s *S3Store) Upload(ctx context.Context, name, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	key := s.key(name)
+	body, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": []string{""}}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start upload: %w", err)
+	}
+	var initiated initiateMultipartUploadResult
+	if err = xml.Unmarshal(body, &initiated); err != nil || initiated.UploadID == "" {
+		return fmt.Errorf("failed to start upload: invalid response")
+	}
+
+	parts, err := s.uploadParts(ctx, key, initiated.UploadID, f)
+	if err == nil {
+		err = s.completeUpload(ctx, key, initiated.UploadID, parts)
+	}
+	if err != nil {
+		_, _ = s.do(context.Background(), http.MethodDelete, key, url.Values{"uploadId": []string{initiated.UploadID}}, nil)
+		return err
+	}
+	return nil
+}
+
+func (s *S3Store) uploadParts(ctx context.Context, key, uploadID string, r io.Reader) ([]completedPart, error) {
+	var parts []completedPart
+	buf := make([]byte, partSize)
+	for partNumber := 1; ; partNumber++ {
+		n, err := io.ReadFull(r, buf)
+		if errors.Is(err, io.EOF) && partNumber > 1 {
+			break
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		etag, err := s.uploadPart(ctx, key, uploadID, partNumber, buf[:n])
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+		}
+		parts = append(parts, completedPart{PartNumber: partNumber, ETag: etag})
+		if n < partSize {
+			break
+		}
+	}
+	return parts, nil
+}
+
+func (s *S3Store) uploadPart(ctx context.Context, key, uploadID string, partNumber int, data []byte) (string, error) {
+	req, err := s.request(ctx, http.MethodPut, key, url.Values{
+		"partNumber": []string{strconv.Itoa(partNumber)},
+		"uploadId":   []string{uploadID},
+	}, data)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err = checkResponse(resp); err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+func (s *S3Store) completeUpload(ctx context.Context, key, uploadID string, parts []completedPart) error {
+	payload, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+	body, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": []string{uploadID}}, payload)
+	if err != nil {
+		return fmt.Errorf("failed to complete upload: %w", err)
+	}
+	// completing an upload may fail after the 200 status has been sent
+	var e s3Error
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("failed to complete upload: %s: %s", e.Code, e.Message)
+	}
+	return nil
+}
+
+func (s *S3Store) Download(ctx context.Context, name, filePath string) error {
+	req, err := s.request(ctx, http.MethodGet, s.key(name), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err = checkResponse(resp); err != nil {
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+
+	out, err := os.OpenFile(filePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func (s *S3Store) key(name string) string {
+	return strings.TrimPrefix(path.Join(s.prefix, path.Base(name)), "/")
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, payload []byte) ([]byte, error) {
+	req, err := s.request(ctx, method, key, query, payload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err = checkResponse(resp); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *S3Store) request(ctx context.Context, method, key string, query url.Values, payload []byte) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = path.Join("/", u.Path, s.bucket, key)
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "uploads=", "uploads")
+
+	var body io.Reader
+	payloadHash := emptyPayloadHash
+	if payload != nil {
+		body = bytes.NewReader(payload)
+		sum := sha256.Sum256(payload)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if err = s.signer.SignHTTP(ctx, s.credentials, req, payloadHash, "s3", s.region, time.Now()); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var e s3Error
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("%s: %s", e.Code, e.Message)
+	}
+	return fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opengovern/opencomply/jobs/backup-job/types"
+)
+
+// Store keeps the backup archives
+type Store interface {
+	// Upload stores the local file as the archive with the given name
+	Upload(ctx context.Context, name, path string) error
+	// Download writes the archive with the given name to the local file
+	Download(ctx context.Context, name, path string) error
+}
+
+func New(cfg types.BackupConfig) (Store, error) {
+	switch cfg.StorageType {
+	case types.StorageTypeLocal, "":
+		if cfg.LocalPath == "" {
+			return nil, fmt.Errorf("local path is required for %s storage", types.StorageTypeLocal)
+		}
+		return NewLocalStore(cfg.LocalPath), nil
+	case types.StorageTypeS3:
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("invalid storage type %s", cfg.StorageType)
+	}
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+// DataClass groups the databases and indices of a backup so they can be restored selectively
+type DataClass string
+
+const (
+	// DataClassConfiguration is the platform configuration: users, integrations, metadata and tasks
+	DataClassConfiguration DataClass = "configuration"
+	// DataClassJobs is the discovery, compliance and query job history of the scheduler
+	DataClassJobs DataClass = "jobs"
+	// DataClassCompliance is the compliance framework definitions and the compliance results
+	DataClassCompliance DataClass = "compliance"
+	// DataClassInventory is the discovered resources and the other platform indices
+	DataClassInventory DataClass = "inventory"
+	// DataClassAudit is the audit log
+	DataClassAudit DataClass = "audit"
+)
+
+var AllDataClasses = []DataClass{
+	DataClassConfiguration, DataClassJobs, DataClassCompliance, DataClassInventory, DataClassAudit,
+}
+
+var DefaultDatabases = []string{"describe", "integration", "metadata", "compliance", "auth", "task"}
+
+var databaseClasses = map[string]DataClass{
+	"describe":    DataClassJobs,
+	"integration": DataClassConfiguration,
+	"metadata":    DataClassConfiguration,
+	"auth":        DataClassConfiguration,
+	"task":        DataClassConfiguration,
+	"compliance":  DataClassCompliance,
+	"inventory":   DataClassInventory,
+}
+
+var complianceIndices = map[string]bool{
+	types.ComplianceResultsIndex:                 true,
+	types.ComplianceResultEventsIndex:            true,
+	types.ResourceFindingsIndex:                  true,
+	types.BenchmarkSummaryIndex:                  true,
+	types.ComplianceJobReportControlViewIndex:    true,
+	types.ComplianceJobReportControlSummaryIndex: true,
+	types.ComplianceJobReportResourceViewIndex:   true,
+	types.ComplianceSnapshotsIndex:               true,
+	types.ComplianceResultSnapshotsIndex:         true,
+	types.ResourceFindingSnapshotsIndex:          true,
+}
+
+func DatabaseClass(database string) DataClass {
+	if class, ok := databaseClasses[database]; ok {
+		return class
+	}
+	return DataClassConfiguration
+}
+
+func IndexClass(index string) DataClass {
+	switch {
+	case index == types.AuditLogIndex:
+		return DataClassAudit
+	case complianceIndices[index]:
+		return DataClassCompliance
+	default:
+		return DataClassInventory
+	}
+}
+
+// ParseDataClasses parses a comma separated list of data classes, an empty list selects all of them
+func ParseDataClasses(value string) (map[DataClass]bool, error) {
+	selected := make(map[DataClass]bool)
+	for _, c := range strings.Split(value, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		valid := false
+		for _, class := range AllDataClasses {
+			if DataClass(c) == class {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid data class %s", c)
+		}
+		selected[DataClass(c)] = true
+	}
+	if len(selected) == 0 {
+		for _, class := range AllDataClasses {
+			selected[class] = true
+		}
+	}
+	return selected, nil
+}
+
+// BackupDatabases returns the databases of the comma separated list, DefaultDatabases if it is empty
+func BackupDatabases(value string) []string {
+	var databases []string
+	for _, db := range strings.Split(value, ",") {
+		if db = strings.TrimSpace(db); db != "" {
+			databases = append(databases, db)
+		}
+	}
+	if len(databases) == 0 {
+		return DefaultDatabases
+	}
+	return databases
+}
@@ -0,0 +1,35 @@
+package types
+
+import "github.com/opengovern/og-util/pkg/config"
+
+type StorageType string
+
+const (
+	StorageTypeLocal StorageType = "local"
+	StorageTypeS3    StorageType = "s3"
+)
+
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	Bucket          string `yaml:"bucket"`
+	Prefix          string `yaml:"prefix"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+}
+
+type BackupConfig struct {
+	PostgreSQL    config.Postgres
+	ElasticSearch config.ElasticSearch
+
+	// Databases is a comma separated list of the databases to back up, defaults to DefaultDatabases
+	Databases string `yaml:"databases"`
+	// Passphrase is used to derive the archive encryption key
+	Passphrase string `yaml:"passphrase"`
+	// WorkDir holds the archive and its extracted files while a backup or restore runs
+	WorkDir string `yaml:"work_dir"`
+
+	StorageType StorageType `yaml:"storage_type"`
+	LocalPath   string      `yaml:"local_path"`
+	S3          S3Config    `yaml:"s3"`
+}
@@ -0,0 +1,6 @@
+package types
+
+const (
+	DefaultWorkDir   = "/tmp/backup"
+	ArchiveExtension = ".ocbk"
+)
@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"context"
 	"errors"
 
 	"github.com/opengovern/og-util/pkg/config"
@@ -51,3 +52,32 @@ func WorkerCommand() *cobra.Command {
 
 	return cmd
 }
+
+// RunInProcess runs a worker in the process of another service, e.g. with the local executor. The config is read
+// from the environment of the service, the NATS url of the worker env overrides it.
+func RunInProcess(ctx context.Context, env map[string]string) error {
+	var cnf Config
+	config.ReadFromEnv(&cnf, nil)
+	if natsURL, ok := env["NATS_URL"]; ok {
+		cnf.NATS.URL = natsURL
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+
+	w, err := NewWorker(
+		cnf,
+		logger,
+		cnf.PrometheusPushAddress,
+		ctx,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer w.Stop()
+
+	return w.Run(ctx)
+}
@@ -159,6 +159,7 @@ func (w *Job) ExtractComplianceResults(_ *zap.Logger, benchmarkCache map[string]
 			StateActive:        true,
 			ComplianceStatus:   status,
 			Severity:           severity,
+			OriginalSeverity:   severity,
 			IntegrationType:    integrationType,
 			PlatformResourceID: platformResourceID,
 			ResourceID:         resourceID,
@@ -30,6 +30,9 @@ type ExecutionPlan struct {
 
 	IntegrationID *string
 	ProviderID    *string
+
+	// ResourceCollectionID limits the query to the resources of the collection
+	ResourceCollectionID *string
 }
 
 type Job struct {
@@ -3,6 +3,7 @@ package runner
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	authApi "github.com/opengovern/og-util/pkg/api"
@@ -16,6 +17,7 @@ import (
 	"github.com/opengovern/og-util/pkg/steampipe"
 	"github.com/opengovern/opencomply/pkg/types"
 	es2 "github.com/opengovern/opencomply/services/compliance/es"
+	"github.com/opengovern/opencomply/services/compliance/sla"
 	"go.uber.org/zap"
 )
 
@@ -44,6 +46,25 @@ func (w *Worker) Initialize(ctx context.Context, j Job) error {
 		return err
 	}
 
+	if j.ExecutionPlan.ResourceCollectionID != nil {
+		rc, err := w.inventoryClient.GetResourceCollectionMetadata(&httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole},
+			*j.ExecutionPlan.ResourceCollectionID)
+		if err != nil {
+			w.logger.Error("failed to get resource collection", zap.Error(err), zap.String("resource_collection_id", *j.ExecutionPlan.ResourceCollectionID))
+			return err
+		}
+		filtersJson, err := json.Marshal(rc.Filters)
+		if err != nil {
+			w.logger.Error("failed to marshal resource collection filters", zap.Error(err))
+			return err
+		}
+		err = w.steampipeConn.SetConfigTableValue(ctx, steampipe.OpenGovernanceConfigKeyResourceCollectionFilters, base64.StdEncoding.EncodeToString(filtersJson))
+		if err != nil {
+			w.logger.Error("failed to set resource collection filters", zap.Error(err))
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -123,6 +144,9 @@ func (w *Worker) RunJob(ctx context.Context, j Job) (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	if err := w.applySeverityOverrides(ctx, j.ExecutionPlan.Callers[0].ControlID, complianceResults); err != nil {
+		return 0, err
+	}
 	w.logger.Info("Extracted complianceResults", zap.Int("count", len(complianceResults)),
 		zap.Uint("job_id", j.ID),
 		zap.String("benchmarkID", j.ExecutionPlan.Callers[0].RootBenchmark))
@@ -148,6 +172,14 @@ func (w *Worker) RunJob(ctx context.Context, j Job) (int, error) {
 		filters = append(filters, opengovernance.NewTermFilter("integrationID", *j.ExecutionPlan.IntegrationID))
 	}
 
+	slaPolicies, err := w.listSLAPolicies(ctx)
+	if err != nil {
+		return 0, err
+	}
+	previousComplianceResults := make(map[string]types.ComplianceResult)
+	// open results of resources dropped out of the query output, resolved by the run
+	resolvedComplianceResults := make([]types.ComplianceResult, 0)
+
 	newComplianceResults := make([]types.ComplianceResult, 0, len(complianceResults))
 	//complianceResultDriftEvents := make([]types.ComplianceResultDriftEvent, 0, len(complianceResults))
 
@@ -183,6 +215,21 @@ func (w *Worker) RunJob(ctx context.Context, j Job) (int, error) {
 		w.logger.Info("Old complianceResult", zap.Int("length", len(oldComplianceResults)))
 		for _, f := range oldComplianceResults {
 			f := f
+			if _, ok := complianceResultsMap[f.EsID]; ok {
+				previousComplianceResults[f.EsID] = f
+			}
+			// a scoped run only sees the members of the resource collection, results of the other resources are kept
+			if j.ExecutionPlan.ResourceCollectionID != nil {
+				continue
+			}
+			if _, ok := complianceResultsMap[f.EsID]; !ok && f.IsOpen() {
+				sla.Resolve(&f, j.CreatedAt.UnixMilli())
+				f.StateActive = false
+				f.LastUpdatedAt = j.CreatedAt.UnixMilli()
+				f.RunnerID = j.ID
+				f.ComplianceJobID = j.ParentJobID
+				resolvedComplianceResults = append(resolvedComplianceResults, f)
+			}
 			err = w.esClient.Delete(f.EsID, types.ComplianceResultsIndex)
 			if err != nil {
 				w.logger.Error("failed to remove old compliance result", zap.Error(err))
@@ -283,6 +330,11 @@ func (w *Worker) RunJob(ctx context.Context, j Job) (int, error) {
 	}
 	closePaginator()
 	for _, newComplianceResult := range complianceResultsMap {
+		var previous *types.ComplianceResult
+		if p, ok := previousComplianceResults[newComplianceResult.EsID]; ok {
+			previous = &p
+		}
+		sla.Track(&newComplianceResult, previous, slaPolicies)
 		newComplianceResult.LastUpdatedAt = j.CreatedAt.UnixMilli()
 		newComplianceResult.RunnerID = j.ID
 		newComplianceResult.ComplianceJobID = j.ParentJobID
@@ -330,6 +382,21 @@ func (w *Worker) RunJob(ctx context.Context, j Job) (int, error) {
 		f.EsID = es.HashOf(keys...)
 		f.EsIndex = idx
 		docs = append(docs, f)
+
+		// keep an immutable copy per compliance job so past states can be queried after the result is replaced
+		snapshot := types.ComplianceResultSnapshot{ComplianceResult: f}
+		keys, idx = snapshot.KeysAndIndex()
+		snapshot.EsID = es.HashOf(keys...)
+		snapshot.EsIndex = idx
+		docs = append(docs, snapshot)
+	}
+	// the results of the resolved ones are deleted, their resolution is only kept in the snapshots
+	for _, f := range resolvedComplianceResults {
+		snapshot := types.ComplianceResultSnapshot{ComplianceResult: f}
+		keys, idx := snapshot.KeysAndIndex()
+		snapshot.EsID = es.HashOf(keys...)
+		snapshot.EsIndex = idx
+		docs = append(docs, snapshot)
 	}
 	mapKey := strings.Builder{}
 	mapKey.WriteString(j.ExecutionPlan.Callers[0].RootBenchmark)
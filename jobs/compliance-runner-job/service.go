@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
@@ -17,6 +18,7 @@ import (
 	"github.com/opengovern/og-util/pkg/steampipe"
 	complianceApi "github.com/opengovern/opencomply/services/compliance/api"
 	complianceClient "github.com/opengovern/opencomply/services/compliance/client"
+	"github.com/opengovern/opencomply/services/compliance/sla"
 	integration_type "github.com/opengovern/opencomply/services/integration/integration-type"
 	inventoryClient "github.com/opengovern/opencomply/services/inventory/client"
 	metadataClient "github.com/opengovern/opencomply/services/metadata/client"
@@ -49,6 +51,10 @@ type Worker struct {
 	sinkClient       esSinkClient.EsSinkServiceClient
 
 	benchmarkCache map[string]complianceApi.Benchmark
+
+	slaPoliciesMu        sync.Mutex
+	slaPolicies          sla.Policies
+	slaPoliciesFetchedAt time.Time
 }
 
 var (
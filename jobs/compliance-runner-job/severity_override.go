@@ -0,0 +1,121 @@
+package runner
+
+import (
+	"context"
+
+	authApi "github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/pkg/utils"
+	"github.com/opengovern/opencomply/services/compliance/api"
+	es2 "github.com/opengovern/opencomply/services/compliance/es"
+	"go.uber.org/zap"
+)
+
+const severityOverrideLookupBatchSize = 1000
+
+// applySeverityOverrides replaces the severity of the results with the most specific override of the control
+// matching them, the latest updated override wins between overrides of the same scope type
+func (w *Worker) applySeverityOverrides(ctx context.Context, controlID string, complianceResults []types.ComplianceResult) error {
+	if len(complianceResults) == 0 {
+		return nil
+	}
+	overrides, err := w.complianceClient.ListResolvedControlSeverityOverrides(&httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole}, controlID)
+	if err != nil {
+		w.logger.Error("failed to list severity overrides", zap.String("control_id", controlID), zap.Error(err))
+		return err
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	matchers := make([]func(types.ComplianceResult) bool, len(overrides))
+	for i, o := range overrides {
+		switch o.ScopeType {
+		case api.SeverityOverrideScopeIntegrationGroup:
+			integrationIDs := make(map[string]bool, len(o.IntegrationIDs))
+			for _, id := range o.IntegrationIDs {
+				integrationIDs[id] = true
+			}
+			matchers[i] = func(r types.ComplianceResult) bool { return integrationIDs[r.IntegrationID] }
+		case api.SeverityOverrideScopeResourceCollection, api.SeverityOverrideScopeTag:
+			members, err := w.severityOverrideMembers(ctx, o, complianceResults)
+			if err != nil {
+				w.logger.Error("failed to get severity override members", zap.Uint("overrideId", o.ID), zap.Error(err))
+				return err
+			}
+			matchers[i] = func(r types.ComplianceResult) bool { return members[r.PlatformResourceID] }
+		default:
+			matchers[i] = func(types.ComplianceResult) bool { return true }
+		}
+	}
+
+	for i, r := range complianceResults {
+		best := -1
+		for j, o := range overrides {
+			if !matchers[j](r) {
+				continue
+			}
+			if best < 0 || moreSpecificSeverityOverride(o, overrides[best]) {
+				best = j
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		overrideID := overrides[best].ID
+		complianceResults[i].Severity = overrides[best].Severity
+		complianceResults[i].SeverityOverrideID = &overrideID
+	}
+	return nil
+}
+
+func moreSpecificSeverityOverride(a, b api.ResolvedSeverityOverride) bool {
+	if a.ScopeType.Specificity() != b.ScopeType.Specificity() {
+		return a.ScopeType.Specificity() > b.ScopeType.Specificity()
+	}
+	if !a.UpdatedAt.Equal(b.UpdatedAt) {
+		return a.UpdatedAt.After(b.UpdatedAt)
+	}
+	return a.ID > b.ID
+}
+
+// severityOverrideMembers returns the resources of the results within the resource collection or tag scope
+// of the override
+func (w *Worker) severityOverrideMembers(ctx context.Context, o api.ResolvedSeverityOverride, complianceResults []types.ComplianceResult) (map[string]bool, error) {
+	var filters any = o.ResourceCollectionFilters
+	if o.ScopeType == api.SeverityOverrideScopeTag {
+		key, value, ok := api.ParseSeverityOverrideTagScope(o.ScopeValue)
+		if !ok {
+			return nil, nil
+		}
+		filters = []utils.ResourceCollectionFilter{{Tags: map[string]string{key: value}}}
+	}
+	query, err := utils.ResourceCollectionQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var platformIDs []string
+	for _, r := range complianceResults {
+		if r.PlatformResourceID == "" || seen[r.PlatformResourceID] {
+			continue
+		}
+		seen[r.PlatformResourceID] = true
+		platformIDs = append(platformIDs, r.PlatformResourceID)
+	}
+
+	members := make(map[string]bool)
+	for start := 0; start < len(platformIDs); start += severityOverrideLookupBatchSize {
+		end := min(start+severityOverrideLookupBatchSize, len(platformIDs))
+		matched, err := es2.FilterLookupResourcesByQuery(ctx, w.esClient, platformIDs[start:end], query)
+		if err != nil {
+			return nil, err
+		}
+		for id := range matched {
+			members[id] = true
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"context"
+	"time"
+
+	authApi "github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/services/compliance/sla"
+	"go.uber.org/zap"
+)
+
+// slaPoliciesRefreshTTL is how long the worker reuses the sla policies before fetching them again
+const slaPoliciesRefreshTTL = 5 * time.Minute
+
+// listSLAPolicies returns the sla policies of the platform, cached by the worker. If they can't be fetched the last
+// known policies are used.
+func (w *Worker) listSLAPolicies(ctx context.Context) (sla.Policies, error) {
+	w.slaPoliciesMu.Lock()
+	defer w.slaPoliciesMu.Unlock()
+
+	if !w.slaPoliciesFetchedAt.IsZero() && time.Since(w.slaPoliciesFetchedAt) < slaPoliciesRefreshTTL {
+		return w.slaPolicies, nil
+	}
+
+	policies, err := w.complianceClient.ListSLAPolicies(&httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole})
+	if err != nil {
+		w.logger.Error("failed to list sla policies", zap.Error(err))
+		if w.slaPoliciesFetchedAt.IsZero() {
+			return nil, err
+		}
+		return w.slaPolicies, nil
+	}
+	result := make(sla.Policies, len(policies))
+	for _, p := range policies {
+		result[p.Severity] = p.ResolveWithinDays
+	}
+	w.slaPolicies = result
+	w.slaPoliciesFetchedAt = time.Now()
+	return result, nil
+}
@@ -28,9 +28,14 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 
 	// We have to sort by platformResourceID to be able to optimize memory usage for resourceFinding generations
 	// this way as soon as paginator switches to next resource we can send the previous resource to the queue and free up memory
-	paginator, err := es.NewComplianceResultPaginator(w.esClient, types.ComplianceResultsIndex, []opengovernance.BoolFilter{
+	filters := []opengovernance.BoolFilter{
 		opengovernance.NewTermFilter("stateActive", "true"),
-	}, nil, []map[string]any{
+	}
+	if j.ResourceCollectionID != nil {
+		// results of a scoped run carry its compliance job id, results out of the collection are left as is
+		filters = append(filters, opengovernance.NewTermFilter("complianceJobID", fmt.Sprintf("%d", j.ComplianceJobID)))
+	}
+	paginator, err := es.NewComplianceResultPaginator(w.esClient, types.ComplianceResultsIndex, filters, nil, []map[string]any{
 		{"platformResourceID": "asc"},
 		{"resourceType": "asc"},
 	})
@@ -45,6 +50,11 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 
 	w.logger.Info("ComplianceResultsIndex paginator ready")
 
+	ownershipRules, err := w.listOwnershipRules(ctx)
+	if err != nil {
+		return err
+	}
+
 	jd := types2.JobDocs{
 		BenchmarkSummary: types2.BenchmarkSummary{
 			BenchmarkID:      j.BenchmarkID,
@@ -76,6 +86,9 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 			FrameworkID:  j.BenchmarkID,
 			Auditable:    true,
 			JobStartedAt: time.Now(),
+
+			DataAsOf:             j.DataAsOf,
+			EvaluatedOnStaleData: j.EvaluatedOnStaleData,
 		},
 	}
 	controlSummary := &types.ComplianceJobReportControlSummary{
@@ -90,6 +103,9 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 			FrameworkID:  j.BenchmarkID,
 			Auditable:    true,
 			JobStartedAt: time.Now(),
+
+			DataAsOf:             j.DataAsOf,
+			EvaluatedOnStaleData: j.EvaluatedOnStaleData,
 		},
 	}
 	resourceView := &types.ComplianceJobReportResourceView{
@@ -100,6 +116,9 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 			FrameworkID:  j.BenchmarkID,
 			Auditable:    true,
 			JobStartedAt: time.Now(),
+
+			DataAsOf:             j.DataAsOf,
+			EvaluatedOnStaleData: j.EvaluatedOnStaleData,
 		},
 	}
 
@@ -130,6 +149,12 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 			return err
 		}
 
+		ownerChangedDocs, err := w.assignOwners(ctx, ownershipRules, page)
+		if err != nil {
+			w.logger.Error("failed to assign owners", zap.Error(err))
+			return err
+		}
+
 		w.logger.Info("resource lookup result", zap.Any("platformResourceIDs", platformResourceIDs),
 			zap.Any("lookupResourcesMap", lookupResourcesMap))
 		w.logger.Info("page size", zap.Int("pageSize", len(page)))
@@ -141,7 +166,11 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 			}
 			w.logger.Info("Before adding resource finding", zap.String("platform_resource_id", f.PlatformResourceID),
 				zap.Any("resource", resource))
-			jd.AddComplianceResult(w.logger, j, f, resource, jobIntegrations)
+			if j.ResourceCollectionID != nil {
+				jd.AddResourceCollectionComplianceResult(j, *j.ResourceCollectionID, f)
+			} else {
+				jd.AddComplianceResult(w.logger, j, f, resource, jobIntegrations)
+			}
 
 			if f.BenchmarkID == j.BenchmarkID {
 				if len(jobIntegrations) > 0 {
@@ -158,7 +187,7 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 			}
 		}
 
-		var docs []es2.Doc
+		docs := ownerChangedDocs
 		for resourceIdType, isReady := range jd.ResourcesFindingsIsDone {
 			if !isReady {
 				w.logger.Info("resource NOT DONE", zap.String("platform_resource_id", resourceIdType))
@@ -169,7 +198,7 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 			keys, idx := resourceFinding.KeysAndIndex()
 			resourceFinding.EsID = es2.HashOf(keys...)
 			resourceFinding.EsIndex = idx
-			docs = append(docs, resourceFinding)
+			docs = append(docs, resourceFinding, resourceFindingSnapshot(j, resourceFinding))
 			delete(jd.ResourcesFindings, resourceIdType)
 			delete(jd.ResourcesFindingsIsDone, resourceIdType)
 		}
@@ -193,6 +222,17 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 
 	jd.Summarize(w.logger)
 
+	if j.ResourceCollectionID != nil {
+		previousSummaries, err := es.ListBenchmarkSummariesAtTime(ctx, w.logger, w.esClient, []string{j.BenchmarkID}, nil, nil, time.Now(), true)
+		if err != nil {
+			w.logger.Error("failed to fetch previous benchmark summary", zap.Error(err), zap.String("benchmark_id", j.BenchmarkID))
+			return err
+		}
+		if previous, ok := previousSummaries[j.BenchmarkID]; ok {
+			jd.CarryForward(previous, *j.ResourceCollectionID)
+		}
+	}
+
 	keys, idx := jd.BenchmarkSummary.KeysAndIndex()
 	jd.BenchmarkSummary.EsID = es2.HashOf(keys...)
 	jd.BenchmarkSummary.EsIndex = idx
@@ -205,7 +245,10 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 		keys, idx := rf.KeysAndIndex()
 		rf.EsID = es2.HashOf(keys...)
 		rf.EsIndex = idx
-		docs = append(docs, rf)
+		docs = append(docs, rf, resourceFindingSnapshot(j, rf))
+	}
+	if j.ResourceCollectionID == nil {
+		docs = append(docs, complianceSnapshot(j, integrationsMap))
 	}
 	if _, err := w.esSinkClient.Ingest(&httpclient.Context{Ctx: ctx, UserRole: api.AdminRole}, docs); err != nil {
 		w.logger.Error("failed to send to ingest", zap.Error(err))
@@ -282,6 +325,45 @@ func (w *Worker) RunJob(ctx context.Context, j types2.Job) error {
 	return nil
 }
 
+// resourceFindingSnapshot keeps a copy of the resource finding as summarized for the compliance job
+func resourceFindingSnapshot(j types2.Job, rf types.ResourceFinding) types.ResourceFindingSnapshot {
+	snapshot := types.ResourceFindingSnapshot{
+		ResourceFinding: rf,
+		ComplianceJobID: j.ComplianceJobID,
+	}
+	if len(rf.ComplianceResults) > 0 {
+		snapshot.IntegrationID = rf.ComplianceResults[0].IntegrationID
+	}
+	keys, idx := snapshot.KeysAndIndex()
+	snapshot.EsID = es2.HashOf(keys...)
+	snapshot.EsIndex = idx
+	return snapshot
+}
+
+// complianceSnapshot records which integrations the compliance job evaluated, point in time queries
+// resolve the snapshots to read from these records
+func complianceSnapshot(j types2.Job, integrationsMap map[string]bool) types.ComplianceSnapshot {
+	integrationIDs := j.IntegrationIDs
+	if len(integrationIDs) == 0 {
+		for i := range integrationsMap {
+			integrationIDs = append(integrationIDs, i)
+		}
+	}
+	snapshot := types.ComplianceSnapshot{
+		ComplianceJobID:      j.ComplianceJobID,
+		SummarizerJobID:      j.ID,
+		FrameworkID:          j.BenchmarkID,
+		IntegrationIDs:       integrationIDs,
+		EvaluatedAtEpoch:     j.CreatedAt.Unix(),
+		DataAsOf:             j.DataAsOf,
+		EvaluatedOnStaleData: j.EvaluatedOnStaleData,
+	}
+	keys, idx := snapshot.KeysAndIndex()
+	snapshot.EsID = es2.HashOf(keys...)
+	snapshot.EsIndex = idx
+	return snapshot
+}
+
 func (w *Worker) deleteOldResourceFindings(ctx context.Context, j types2.Job, currentResourceIds []string) error {
 	// Delete old resource findings
 	filters := make([]opengovernance.BoolFilter, 0, 2)
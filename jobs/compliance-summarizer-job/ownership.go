@@ -0,0 +1,90 @@
+package summarizer
+
+import (
+	"context"
+
+	authApi "github.com/opengovern/og-util/pkg/api"
+	es2 "github.com/opengovern/og-util/pkg/es"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/compliance/es"
+	"github.com/opengovern/opencomply/services/compliance/ownership"
+	"go.uber.org/zap"
+)
+
+const ownershipLookupBatchSize = 1000
+
+func (w *Worker) listOwnershipRules(ctx context.Context) ([]ownership.Rule, error) {
+	resolved, err := w.complianceClient.ListResolvedOwnershipRules(&httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole})
+	if err != nil {
+		w.logger.Error("failed to list ownership rules", zap.Error(err))
+		return nil, err
+	}
+	rules := make([]ownership.Rule, 0, len(resolved))
+	for _, r := range resolved {
+		rule := ownership.Rule{
+			ID:        r.ID,
+			Owner:     r.Owner,
+			Priority:  r.Priority,
+			MatchType: ownership.MatchType(r.MatchType),
+			TagKey:    r.TagKey,
+			Pattern:   r.Pattern,
+		}
+		if len(r.IntegrationIDs) > 0 {
+			rule.IntegrationIDs = make(map[string]bool, len(r.IntegrationIDs))
+			for _, id := range r.IntegrationIDs {
+				rule.IntegrationIDs[id] = true
+			}
+		}
+		rules = append(rules, rule)
+	}
+	ownership.Sort(rules)
+	return rules, nil
+}
+
+// assignOwners sets the owner of the results from the ownership rules and returns the results whose owner
+// changed, to be written back so they can be filtered by owner
+func (w *Worker) assignOwners(ctx context.Context, rules []ownership.Rule, complianceResults []types.ComplianceResult) ([]es2.Doc, error) {
+	var tags map[string]map[string]string
+	if tagKeys := ownership.TagKeys(rules); len(tagKeys) > 0 {
+		seen := make(map[string]bool)
+		var platformIDs []string
+		for _, r := range complianceResults {
+			if r.PlatformResourceID == "" || seen[r.PlatformResourceID] {
+				continue
+			}
+			seen[r.PlatformResourceID] = true
+			platformIDs = append(platformIDs, r.PlatformResourceID)
+		}
+
+		tags = make(map[string]map[string]string)
+		for start := 0; start < len(platformIDs); start += ownershipLookupBatchSize {
+			end := min(start+ownershipLookupBatchSize, len(platformIDs))
+			batch, err := es.GetLookupResourceTags(ctx, w.esClient, platformIDs[start:end], tagKeys)
+			if err != nil {
+				w.logger.Error("failed to fetch resource tags", zap.Error(err))
+				return nil, err
+			}
+			for id, t := range batch {
+				tags[id] = t
+			}
+		}
+	}
+
+	var changed []es2.Doc
+	for i, r := range complianceResults {
+		owner := ownership.Resolve(rules, ownership.Resource{
+			IntegrationID: r.IntegrationID,
+			ResourceType:  r.ResourceType,
+			Tags:          tags[r.PlatformResourceID],
+		})
+		if owner == r.Owner {
+			continue
+		}
+		complianceResults[i].Owner = owner
+		if r.EsID != "" {
+			changed = append(changed, complianceResults[i])
+		}
+	}
+	return changed, nil
+}
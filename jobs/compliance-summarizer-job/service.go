@@ -14,6 +14,7 @@ import (
 	"github.com/opengovern/og-util/pkg/jq"
 	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
 	"github.com/opengovern/opencomply/jobs/compliance-summarizer-job/types"
+	complianceClient "github.com/opengovern/opencomply/services/compliance/client"
 	integrationClient "github.com/opengovern/opencomply/services/integration/client"
 	inventoryClient "github.com/opengovern/opencomply/services/inventory/client"
 	"go.uber.org/zap"
@@ -25,6 +26,7 @@ type Config struct {
 	PrometheusPushAddress string
 	Inventory             config.OpenGovernanceService
 	Integration           config.OpenGovernanceService
+	Compliance            config.OpenGovernanceService
 	EsSink                config.OpenGovernanceService
 }
 
@@ -36,6 +38,7 @@ type Worker struct {
 
 	inventoryClient   inventoryClient.InventoryServiceClient
 	integrationClient integrationClient.IntegrationServiceClient
+	complianceClient  complianceClient.ComplianceServiceClient
 	esSinkClient      esSinkClient.EsSinkServiceClient
 }
 
@@ -83,6 +86,7 @@ func NewWorker(
 		jq:                jq,
 		inventoryClient:   inventoryClient.NewInventoryServiceClient(config.Inventory.BaseURL),
 		integrationClient: integrationClient.NewIntegrationServiceClient(config.Integration.BaseURL),
+		complianceClient:  complianceClient.NewComplianceClient(config.Compliance.BaseURL),
 		esSinkClient:      esSinkClient.NewEsSinkServiceClient(logger, config.EsSink.BaseURL),
 	}
 
@@ -9,4 +9,11 @@ type Job struct {
 	BenchmarkID     string
 	IntegrationIDs  []string
 	CreatedAt       time.Time
+
+	DataAsOf             *time.Time
+	EvaluatedOnStaleData bool
+
+	// ResourceCollectionID is set when the compliance job was scoped to a resource collection,
+	// the results are then summarized only under that collection
+	ResourceCollectionID *string
 }
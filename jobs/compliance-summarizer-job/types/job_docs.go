@@ -92,6 +92,9 @@ func (jd *JobDocs) AddComplianceResult(logger *zap.Logger, job Job,
 	if resourceFinding.ResourceType == "" {
 		resourceFinding.ResourceType = resourceType
 	}
+	if resourceFinding.Owner == "" {
+		resourceFinding.Owner = complianceResult.Owner
+	}
 	resourceFinding.ComplianceResults = append(resourceFinding.ComplianceResults, complianceResult)
 
 	if _, ok := jd.ResourcesFindingsIsDone[platformResourceID]; !ok {
@@ -102,6 +105,53 @@ func (jd *JobDocs) AddComplianceResult(logger *zap.Logger, job Job,
 	jd.ResourcesFindings[platformResourceID] = resourceFinding
 }
 
+// AddResourceCollectionComplianceResult adds a compliance result of a run scoped to a resource collection,
+// these results only count towards the summary of the collection and do not produce resource findings
+func (jd *JobDocs) AddResourceCollectionComplianceResult(job Job, resourceCollectionID string, complianceResult types.ComplianceResult) {
+	if job.BenchmarkID != complianceResult.BenchmarkID {
+		return
+	}
+	if complianceResult.Severity == "" {
+		complianceResult.Severity = types.ComplianceResultSeverityNone
+	}
+	if complianceResult.ComplianceStatus == "" {
+		complianceResult.ComplianceStatus = types.ComplianceStatusERROR
+	}
+	if complianceResult.ResourceType == "" {
+		complianceResult.ResourceType = "-"
+	}
+
+	rc, ok := jd.BenchmarkSummary.ResourceCollections[resourceCollectionID]
+	if !ok {
+		rc = BenchmarkSummaryResult{
+			BenchmarkResult: ResultGroup{
+				Result: Result{
+					QueryResult:    map[types.ComplianceStatus]int{},
+					SeverityResult: map[types.ComplianceResultSeverity]int{},
+					SecurityScore:  0,
+				},
+				ResourceTypes: map[string]Result{},
+				Controls:      map[string]ControlResult{},
+			},
+			Integrations: map[string]ResultGroup{},
+		}
+	}
+	rc.addComplianceResult(complianceResult)
+	jd.BenchmarkSummary.ResourceCollections[resourceCollectionID] = rc
+}
+
+// CarryForward fills the parts of the summary a scoped run did not evaluate from the previous summary
+// of the benchmark, so the latest summary keeps reflecting the whole platform
+func (jd *JobDocs) CarryForward(previous BenchmarkSummary, resourceCollectionID string) {
+	jd.BenchmarkSummary.Integrations = previous.Integrations
+	for rcId, rc := range previous.ResourceCollections {
+		if rcId == resourceCollectionID {
+			continue
+		}
+		jd.BenchmarkSummary.ResourceCollections[rcId] = rc
+	}
+}
+
 func (jd *JobDocs) Summarize(logger *zap.Logger) {
 	jd.BenchmarkSummary.summarize()
 	for i, resourceFinding := range jd.ResourcesFindings {
@@ -572,8 +572,12 @@ func (g *GitParser) HandleSingleFramework(framework Framework) error {
 		}
 	}
 	tracksDriftEvents := false
+	var maxDataAgeHours *int
+	refreshStaleData := false
 	if framework.Metadata != nil {
 		tracksDriftEvents = framework.Metadata.Defaults.TracksDriftEvents
+		maxDataAgeHours = framework.Metadata.Defaults.MaxDataAgeHours
+		refreshStaleData = framework.Metadata.Defaults.RefreshStaleData
 	}
 
 	b := db.Benchmark{
@@ -583,6 +587,8 @@ func (g *GitParser) HandleSingleFramework(framework Framework) error {
 		Description:       framework.Description,
 		AutoAssign:        autoAssign,
 		TracksDriftEvents: tracksDriftEvents,
+		MaxDataAgeHours:   maxDataAgeHours,
+		RefreshStaleData:  refreshStaleData,
 		Tags:              tags,
 		Children:          nil,
 		Controls:          nil,
@@ -17,6 +17,8 @@ type FrameworkMetadata struct {
 		AutoAssign        *bool `json:"auto-assign"`
 		Enabled           bool  `json:"enabled"`
 		TracksDriftEvents bool  `json:"tracks-drift-events"`
+		MaxDataAgeHours   *int  `json:"max-data-age-hours"`
+		RefreshStaleData  bool  `json:"refresh-stale-data"`
 	} `json:"defaults"`
 	Tags map[string][]string `json:"tags"`
 }
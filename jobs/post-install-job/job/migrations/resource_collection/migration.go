@@ -51,6 +51,13 @@ func (m Migration) Run(ctx context.Context, conf config.MigratorConfig, logger *
 	}
 	dbm := db.Database{ORM: orm}
 
+	// the migration can run before the inventory service has migrated the user_managed column
+	err = dbm.ORM.AutoMigrate(&inventory.ResourceCollection{}, &inventory.ResourceCollectionTag{})
+	if err != nil {
+		logger.Error("failed to migrate resource collection tables", zap.Error(err))
+		return err
+	}
+
 	resourceCollections, err := ExtractResourceCollections(m.AttachmentFolderPath())
 	if err != nil {
 		logger.Error("failed to extract resource collections", zap.Error(err))
@@ -69,8 +76,21 @@ func (m Migration) Run(ctx context.Context, conf config.MigratorConfig, logger *
 			currentRcMap[rc.ID] = rc
 		}
 
-		tx.Model(&inventory.ResourceCollection{}).Where("1=1").Unscoped().Delete(&inventory.ResourceCollection{})
-		tx.Model(&inventory.ResourceCollectionTag{}).Where("1=1").Unscoped().Delete(&inventory.ResourceCollectionTag{})
+		// collections created through the api are kept, only the ones of the platform are replaced
+		var userManagedIDs []string
+		for _, rc := range currentRCs {
+			if rc.UserManaged {
+				userManagedIDs = append(userManagedIDs, rc.ID)
+			}
+		}
+		tagsTx := tx.Model(&inventory.ResourceCollectionTag{})
+		if len(userManagedIDs) > 0 {
+			tagsTx = tagsTx.Where("resource_collection_id NOT IN ?", userManagedIDs)
+		} else {
+			tagsTx = tagsTx.Where("1=1")
+		}
+		tagsTx.Unscoped().Delete(&inventory.ResourceCollectionTag{})
+		tx.Model(&inventory.ResourceCollection{}).Where("user_managed = ?", false).Unscoped().Delete(&inventory.ResourceCollection{})
 		for _, resourceCollection := range resourceCollections {
 			filtersJson, err := json.Marshal(resourceCollection.Filters)
 			if err != nil {
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	actionKey     = "audit_action"
+	targetTypeKey = "audit_target_type"
+	targetIDKey   = "audit_target_id"
+	beforeKey     = "audit_before"
+	afterKey      = "audit_after"
+
+	redacted = "[REDACTED]"
+)
+
+// sensitiveKeys are matched against the lowercase field names, without separators, of the recorded state
+var sensitiveKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"credential",
+	"privatekey",
+	"apikey",
+	"keyhash",
+	"bindpw",
+	"accesskey",
+	"certificate",
+}
+
+// SetAction names the action of the request, e.g. "integration.update"
+func SetAction(c echo.Context, action string) {
+	c.Set(actionKey, action)
+}
+
+func SetTarget(c echo.Context, targetType, targetID string) {
+	c.Set(targetTypeKey, targetType)
+	c.Set(targetIDKey, targetID)
+}
+
+// SetBefore records the state of the target before the change, secrets are redacted
+func SetBefore(c echo.Context, state any) {
+	c.Set(beforeKey, Redact(state))
+}
+
+// SetAfter records the state of the target after the change instead of the request body, secrets are redacted
+func SetAfter(c echo.Context, state any) {
+	c.Set(afterKey, Redact(state))
+}
+
+// Redact encodes the state as JSON replacing the values of the sensitive fields
+func Redact(state any) string {
+	if state == nil {
+		return ""
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return ""
+	}
+	return RedactJSON(b)
+}
+
+func RedactJSON(b []byte) string {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return ""
+	}
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func redactValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			if isSensitiveKey(k) && item != nil {
+				value[k] = redacted
+				continue
+			}
+			value[k] = redactValue(item)
+		}
+		return value
+	case []any:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+		return value
+	}
+	return v
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	authApi "github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/es"
+	esSinkClient "github.com/opengovern/og-util/pkg/es/ingest/client"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/og-util/pkg/httpserver"
+	"github.com/opengovern/opencomply/pkg/types"
+	"go.uber.org/zap"
+)
+
+const (
+	queueSize     = 1000
+	batchSize     = 100
+	flushInterval = 5 * time.Second
+	maxBodySize   = 1 << 20
+)
+
+// Recorder stores an audit event for every mutating API call of a service. Events are shipped to the es-sink
+// in the background, if no es-sink is configured they are only logged.
+type Recorder struct {
+	logger     *zap.Logger
+	service    string
+	sinkClient esSinkClient.EsSinkServiceClient
+	events     chan types.AuditEvent
+}
+
+func NewRecorder(logger *zap.Logger, service string, esSinkBaseURL string) *Recorder {
+	r := &Recorder{
+		logger:  logger.Named("audit"),
+		service: service,
+		events:  make(chan types.AuditEvent, queueSize),
+	}
+	if esSinkBaseURL != "" {
+		r.sinkClient = esSinkClient.NewEsSinkServiceClient(logger, esSinkBaseURL)
+	} else {
+		r.logger.Warn("es-sink is not configured, audit events are only logged")
+	}
+	go r.run()
+	return r
+}
+
+// Middleware records the requests of the mutating routes it is added to. Handlers describe the action and the
+// target of the request with SetAction, SetTarget, SetBefore and SetAfter, otherwise they are derived from the
+// route and the request body.
+func (r *Recorder) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if r == nil {
+				return next(c)
+			}
+
+			body := readBody(c)
+			err := next(c)
+
+			statusCode := c.Response().Status
+			if err != nil {
+				var httpErr *echo.HTTPError
+				if errors.As(err, &httpErr) {
+					statusCode = httpErr.Code
+				} else {
+					statusCode = http.StatusInternalServerError
+				}
+			}
+			r.record(c, statusCode, body)
+			return err
+		}
+	}
+}
+
+func (r *Recorder) record(c echo.Context, statusCode int, body []byte) {
+	req := c.Request()
+	event := types.AuditEvent{
+		EventID:    uuid.New().String(),
+		Timestamp:  time.Now().UnixMilli(),
+		Service:    r.service,
+		Method:     req.Method,
+		Path:       c.Path(),
+		ActorID:    httpserver.GetUserID(c),
+		ActorRole:  req.Header.Get(httpserver.XPlatformUserRoleHeader),
+		SourceIP:   c.RealIP(),
+		RequestID:  req.Header.Get(echo.HeaderXRequestID),
+		StatusCode: statusCode,
+	}
+
+	event.Action, _ = c.Get(actionKey).(string)
+	if event.Action == "" {
+		event.Action = strings.ToLower(req.Method) + " " + c.Path()
+	}
+	event.TargetType, _ = c.Get(targetTypeKey).(string)
+	event.TargetID, _ = c.Get(targetIDKey).(string)
+	if event.TargetType == "" && event.TargetID == "" {
+		event.TargetType, event.TargetID = targetFromRoute(c)
+	}
+
+	if before, ok := c.Get(beforeKey).(string); ok {
+		event.Before = before
+	}
+	if after, ok := c.Get(afterKey).(string); ok {
+		event.After = after
+	} else if len(body) > 0 {
+		event.After = RedactJSON(body)
+	}
+
+	keys, idx := event.KeysAndIndex()
+	event.EsID = es.HashOf(keys...)
+	event.EsIndex = idx
+
+	select {
+	case r.events <- event:
+	default:
+		// the before and after states may hold sensitive values, only the event identity is logged
+		r.logger.Error("audit queue is full, dropping event", zap.String("action", event.Action),
+			zap.String("targetType", event.TargetType), zap.String("targetID", event.TargetID),
+			zap.String("actorID", event.ActorID))
+	}
+}
+
+func (r *Recorder) run() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []es.Doc
+	for {
+		select {
+		case event := <-r.events:
+			batch = append(batch, event)
+			if len(batch) < batchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		r.flush(batch)
+		batch = nil
+	}
+}
+
+func (r *Recorder) flush(batch []es.Doc) {
+	if r.sinkClient == nil {
+		for _, event := range batch {
+			r.logger.Info("audit event", zap.Any("event", event))
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := r.sinkClient.Ingest(&httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole}, batch); err != nil {
+		// keep the events in the service logs so they are not lost
+		r.logger.Error("failed to ingest audit events", zap.Error(err))
+		for _, event := range batch {
+			r.logger.Info("audit event", zap.Any("event", event))
+		}
+	}
+}
+
+// readBody keeps a copy of json request bodies and hands the full body back to the handler
+func readBody(c echo.Context) []byte {
+	req := c.Request()
+	if req.Body == nil || !strings.Contains(req.Header.Get(echo.HeaderContentType), "json") {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodySize+1))
+	req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+	if err != nil || len(body) > maxBodySize {
+		return nil
+	}
+	return body
+}
+
+// targetFromRoute uses the last path param of the route as the target and the segment before it as its type
+func targetFromRoute(c echo.Context) (string, string) {
+	segments := strings.Split(strings.Trim(c.Path(), "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(segments[i], ":") {
+			continue
+		}
+		targetType := ""
+		if i > 0 && !strings.HasPrefix(segments[i-1], ":") {
+			targetType = segments[i-1]
+		}
+		return targetType, c.Param(strings.TrimPrefix(segments[i], ":"))
+	}
+	if len(segments) > 0 {
+		return segments[len(segments)-1], ""
+	}
+	return "", ""
+}
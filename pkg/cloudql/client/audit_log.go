@@ -0,0 +1,133 @@
+package opengovernance_client
+
+import (
+	"context"
+	"runtime"
+
+	es "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/config"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+type AuditEventHit struct {
+	ID      string           `json:"_id"`
+	Score   float64          `json:"_score"`
+	Index   string           `json:"_index"`
+	Type    string           `json:"_type"`
+	Version int64            `json:"_version,omitempty"`
+	Source  types.AuditEvent `json:"_source"`
+	Sort    []any            `json:"sort"`
+}
+
+type AuditEventHits struct {
+	Total es.SearchTotal  `json:"total"`
+	Hits  []AuditEventHit `json:"hits"`
+}
+
+type AuditEventSearchResponse struct {
+	PitID string         `json:"pit_id"`
+	Hits  AuditEventHits `json:"hits"`
+}
+
+type AuditEventPaginator struct {
+	paginator *es.BaseESPaginator
+}
+
+func (k Client) NewAuditEventPaginator(filters []es.BoolFilter, limit *int64) (AuditEventPaginator, error) {
+	paginator, err := es.NewPaginator(k.ES.ES(), types.AuditLogIndex, filters, limit)
+	if err != nil {
+		return AuditEventPaginator{}, err
+	}
+
+	p := AuditEventPaginator{
+		paginator: paginator,
+	}
+
+	return p, nil
+}
+
+func (p AuditEventPaginator) HasNext() bool {
+	return !p.paginator.Done()
+}
+
+func (p AuditEventPaginator) Close(ctx context.Context) error {
+	return p.paginator.Deallocate(ctx)
+}
+
+func (p AuditEventPaginator) NextPage(ctx context.Context) ([]types.AuditEvent, error) {
+	var response AuditEventSearchResponse
+	err := p.paginator.Search(ctx, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []types.AuditEvent
+	for _, hit := range response.Hits.Hits {
+		values = append(values, hit.Source)
+	}
+
+	hits := int64(len(response.Hits.Hits))
+	if hits > 0 {
+		p.paginator.UpdateState(hits, response.Hits.Hits[hits-1].Sort, response.PitID)
+	} else {
+		p.paginator.UpdateState(hits, nil, "")
+	}
+
+	return values, nil
+}
+
+var listAuditEventFilters = map[string]string{
+	"event_id":    "eventID",
+	"timestamp":   "timestamp",
+	"service":     "service",
+	"action":      "action",
+	"method":      "method",
+	"path":        "path",
+	"target_type": "targetType",
+	"target_id":   "targetID",
+	"actor_id":    "actorID",
+	"actor_role":  "actorRole",
+	"source_ip":   "sourceIP",
+	"request_id":  "requestID",
+	"status_code": "statusCode",
+}
+
+func ListAuditEvents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+	plugin.Logger(ctx).Trace("ListAuditEvents")
+	runtime.GC()
+	// create service
+	cfg := config.GetConfig(d.Connection)
+	ke, err := config.NewClientCached(cfg, d.ConnectionCache, ctx)
+	if err != nil {
+		plugin.Logger(ctx).Error("ListAuditEvents NewClientCached", "error", err)
+		return nil, err
+	}
+	k := Client{ES: ke}
+
+	paginator, err := k.NewAuditEventPaginator(es.BuildFilterWithDefaultFieldName(ctx, d.QueryContext, listAuditEventFilters,
+		nil, nil, nil, true), d.QueryContext.Limit)
+	if err != nil {
+		plugin.Logger(ctx).Error("ListAuditEvents NewAuditEventPaginator", "error", err)
+		return nil, err
+	}
+
+	for paginator.HasNext() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			plugin.Logger(ctx).Error("ListAuditEvents NextPage", "error", err)
+			return nil, err
+		}
+
+		for _, v := range page {
+			d.StreamListItem(ctx, v)
+		}
+	}
+
+	err = paginator.Close(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
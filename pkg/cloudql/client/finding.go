@@ -93,6 +93,10 @@ var listFindingFilters = map[string]string{
 	"resource_name":        "resourceName",
 	"resource_type":        "resourceType",
 	"reason":               "reason",
+	"first_seen_at":        "firstSeenAt",
+	"resolved_at":          "resolvedAt",
+	"sla_due_at":           "slaDueAt",
+	"owner":                "owner",
 }
 
 func ListFindings(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
@@ -0,0 +1,196 @@
+package opengovernance_client
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/config"
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/services"
+	schedulerApi "github.com/opengovern/opencomply/services/describe/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+)
+
+// qualStringValues returns the values of the equality quals on the column, both single values and lists
+func qualStringValues(d *plugin.QueryData, column string) []string {
+	q, ok := d.EqualsQuals[column]
+	if !ok || q == nil {
+		return nil
+	}
+	if q.GetListValue() != nil {
+		var values []string
+		for _, v := range q.GetListValue().Values {
+			values = append(values, v.GetStringValue())
+		}
+		return values
+	}
+	return []string{q.GetStringValue()}
+}
+
+// qualTimeRange converts the comparison quals on a timestamp column to an inclusive time range
+func qualTimeRange(d *plugin.QueryData, column string) (from, to *time.Time) {
+	if d.Quals[column] == nil {
+		return nil, nil
+	}
+	for _, q := range d.Quals[column].Quals {
+		t := q.Value.GetTimestampValue().AsTime()
+		switch q.Operator {
+		case quals.QualOperatorEqual:
+			from, to = &t, &t
+		case quals.QualOperatorGreater, quals.QualOperatorGreaterOrEqual:
+			from = &t
+		case quals.QualOperatorLess, quals.QualOperatorLessOrEqual:
+			to = &t
+		}
+	}
+	return from, to
+}
+
+// platformJobsPageSize is the number of jobs of each type requested from the scheduler at a time, the scheduler caps
+// its listings at 1000 jobs
+const platformJobsPageSize = 1000
+
+// pageSize returns the page size of the listing, smaller when the query has a lower limit
+func pageSize(d *plugin.QueryData) int64 {
+	if d.QueryContext.Limit != nil && *d.QueryContext.Limit > 0 {
+		return min(*d.QueryContext.Limit, platformJobsPageSize)
+	}
+	return platformJobsPageSize
+}
+
+func inTimeRange(t time.Time, from, to *time.Time) bool {
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	if to != nil && t.After(*to) {
+		return false
+	}
+	return true
+}
+
+func ListPlatformJobs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+	plugin.Logger(ctx).Trace("ListPlatformJobs")
+	runtime.GC()
+	cfg := config.GetConfig(d.Connection)
+	schedulerClient, err := services.NewSchedulerClientCached(cfg, d.ConnectionCache, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := schedulerApi.ListPlatformJobsRequest{
+		Statuses:       qualStringValues(d, "status"),
+		IntegrationIDs: qualStringValues(d, "integration_id"),
+	}
+	request.CreatedAfter, request.CreatedBefore = qualTimeRange(d, "created_at")
+
+	includeTasks := len(request.IntegrationIDs) == 0
+	jobTypes := qualStringValues(d, "job_type")
+	if len(jobTypes) > 0 {
+		includeTasks = false
+		for _, t := range jobTypes {
+			if schedulerApi.PlatformJobType(t) == schedulerApi.PlatformJobTypeTask {
+				includeTasks = len(request.IntegrationIDs) == 0
+				continue
+			}
+			request.JobTypes = append(request.JobTypes, schedulerApi.PlatformJobType(t))
+		}
+	}
+
+	clientCtx := &httpclient.Context{UserRole: api.AdminRole}
+	if len(jobTypes) == 0 || len(request.JobTypes) > 0 {
+		// the limit and offset apply to the jobs of each type, the listing is done once no type has jobs left
+		limit := pageSize(d)
+		request.Limit = &limit
+		for offset := int64(0); ; offset += limit {
+			request.Offset = &offset
+			jobs, err := schedulerClient.ListPlatformJobs(clientCtx, request)
+			if err != nil {
+				plugin.Logger(ctx).Error("ListPlatformJobs scheduler client call failed", "error", err)
+				return nil, err
+			}
+			if len(jobs) == 0 {
+				break
+			}
+			for _, job := range jobs {
+				d.StreamListItem(ctx, job)
+				if d.RowsRemaining(ctx) == 0 {
+					return nil, nil
+				}
+			}
+		}
+	}
+
+	if !includeTasks {
+		return nil, nil
+	}
+	tasksClient, err := services.NewTasksClientCached(cfg, d.ConnectionCache, ctx)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := tasksClient.ListTaskRuns(clientCtx, nil, request.Statuses)
+	if err != nil {
+		plugin.Logger(ctx).Error("ListPlatformJobs task client call failed", "error", err)
+		return nil, err
+	}
+	for _, run := range runs.Items {
+		if !inTimeRange(run.CreatedAt, request.CreatedAfter, request.CreatedBefore) {
+			continue
+		}
+		d.StreamListItem(ctx, schedulerApi.PlatformJob{
+			JobID:          run.ID,
+			JobType:        schedulerApi.PlatformJobTypeTask,
+			Status:         run.Status,
+			TaskID:         run.TaskID,
+			FailureMessage: run.FailureMessage,
+			CreatedAt:      run.CreatedAt,
+			UpdatedAt:      run.UpdatedAt,
+		})
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func ListPlatformComplianceRuns(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+	plugin.Logger(ctx).Trace("ListPlatformComplianceRuns")
+	runtime.GC()
+	cfg := config.GetConfig(d.Connection)
+	schedulerClient, err := services.NewSchedulerClientCached(cfg, d.ConnectionCache, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := schedulerApi.ListPlatformComplianceRunsRequest{
+		FrameworkIDs:   qualStringValues(d, "framework_id"),
+		Statuses:       qualStringValues(d, "status"),
+		IntegrationIDs: qualStringValues(d, "integration_id"),
+	}
+	request.CreatedAfter, request.CreatedBefore = qualTimeRange(d, "created_at")
+
+	limit := pageSize(d)
+	request.Limit = &limit
+	for offset := int64(0); ; offset += limit {
+		request.Offset = &offset
+		runs, err := schedulerClient.ListPlatformComplianceRuns(&httpclient.Context{UserRole: api.AdminRole}, request)
+		if err != nil {
+			plugin.Logger(ctx).Error("ListPlatformComplianceRuns scheduler client call failed", "error", err)
+			return nil, err
+		}
+		for _, run := range runs {
+			d.StreamListItem(ctx, run)
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		if int64(len(runs)) < limit {
+			break
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,132 @@
+package opengovernance_client
+
+import (
+	"context"
+	"runtime"
+
+	es "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/config"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+type ResourceRelationshipHit struct {
+	ID      string                     `json:"_id"`
+	Score   float64                    `json:"_score"`
+	Index   string                     `json:"_index"`
+	Type    string                     `json:"_type"`
+	Version int64                      `json:"_version,omitempty"`
+	Source  types.ResourceRelationship `json:"_source"`
+	Sort    []any                      `json:"sort"`
+}
+
+type ResourceRelationshipHits struct {
+	Total es.SearchTotal            `json:"total"`
+	Hits  []ResourceRelationshipHit `json:"hits"`
+}
+
+type ResourceRelationshipSearchResponse struct {
+	PitID string                   `json:"pit_id"`
+	Hits  ResourceRelationshipHits `json:"hits"`
+}
+
+type ResourceRelationshipPaginator struct {
+	paginator *es.BaseESPaginator
+}
+
+func (k Client) NewResourceRelationshipPaginator(filters []es.BoolFilter, limit *int64) (ResourceRelationshipPaginator, error) {
+	paginator, err := es.NewPaginator(k.ES.ES(), types.ResourceRelationshipsIndex, filters, limit)
+	if err != nil {
+		return ResourceRelationshipPaginator{}, err
+	}
+
+	p := ResourceRelationshipPaginator{
+		paginator: paginator,
+	}
+
+	return p, nil
+}
+
+func (p ResourceRelationshipPaginator) HasNext() bool {
+	return !p.paginator.Done()
+}
+
+func (p ResourceRelationshipPaginator) Close(ctx context.Context) error {
+	return p.paginator.Deallocate(ctx)
+}
+
+func (p ResourceRelationshipPaginator) NextPage(ctx context.Context) ([]types.ResourceRelationship, error) {
+	var response ResourceRelationshipSearchResponse
+	err := p.paginator.Search(ctx, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []types.ResourceRelationship
+	for _, hit := range response.Hits.Hits {
+		values = append(values, hit.Source)
+	}
+
+	hits := int64(len(response.Hits.Hits))
+	if hits > 0 {
+		p.paginator.UpdateState(hits, response.Hits.Hits[hits-1].Sort, response.PitID)
+	} else {
+		p.paginator.UpdateState(hits, nil, "")
+	}
+
+	return values, nil
+}
+
+var listResourceRelationshipFilters = map[string]string{
+	"relationship":            "relationship",
+	"source_platform_id":      "source.platformID",
+	"source_resource_id":      "source.resourceID",
+	"source_resource_type":    "source.resourceType",
+	"source_integration_id":   "source.integrationID",
+	"source_integration_type": "source.integrationType",
+	"target_platform_id":      "target.platformID",
+	"target_resource_id":      "target.resourceID",
+	"target_resource_type":    "target.resourceType",
+	"target_integration_id":   "target.integrationID",
+	"target_integration_type": "target.integrationType",
+	"extracted_at":            "extractedAt",
+}
+
+func ListResourceRelationships(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+	plugin.Logger(ctx).Trace("ListResourceRelationships")
+	runtime.GC()
+	// create service
+	cfg := config.GetConfig(d.Connection)
+	ke, err := config.NewClientCached(cfg, d.ConnectionCache, ctx)
+	if err != nil {
+		plugin.Logger(ctx).Error("ListResourceRelationships NewClientCached", "error", err)
+		return nil, err
+	}
+	k := Client{ES: ke}
+
+	paginator, err := k.NewResourceRelationshipPaginator(es.BuildFilterWithDefaultFieldName(ctx, d.QueryContext, listResourceRelationshipFilters,
+		nil, nil, nil, true), d.QueryContext.Limit)
+	if err != nil {
+		plugin.Logger(ctx).Error("ListResourceRelationships NewResourceRelationshipPaginator", "error", err)
+		return nil, err
+	}
+
+	for paginator.HasNext() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			plugin.Logger(ctx).Error("ListResourceRelationships NextPage", "error", err)
+			return nil, err
+		}
+
+		for _, v := range page {
+			d.StreamListItem(ctx, v)
+		}
+	}
+
+	err = paginator.Close(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
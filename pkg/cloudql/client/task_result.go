@@ -0,0 +1,121 @@
+package opengovernance_client
+
+import (
+	"context"
+	"runtime"
+
+	es "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/config"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+type TaskResultHit struct {
+	ID      string         `json:"_id"`
+	Score   float64        `json:"_score"`
+	Index   string         `json:"_index"`
+	Type    string         `json:"_type"`
+	Version int64          `json:"_version,omitempty"`
+	Source  map[string]any `json:"_source"`
+	Sort    []any          `json:"sort"`
+}
+
+type TaskResultHits struct {
+	Total es.SearchTotal  `json:"total"`
+	Hits  []TaskResultHit `json:"hits"`
+}
+
+type TaskResultSearchResponse struct {
+	PitID string         `json:"pit_id"`
+	Hits  TaskResultHits `json:"hits"`
+}
+
+type TaskResultPaginator struct {
+	paginator *es.BaseESPaginator
+}
+
+// NewTaskResultPaginator pages over the results of a task, the results of each result type are stored in the
+// index named after it
+func (k Client) NewTaskResultPaginator(resultType string, filters []es.BoolFilter, limit *int64) (TaskResultPaginator, error) {
+	paginator, err := es.NewPaginator(k.ES.ES(), resultType, filters, limit)
+	if err != nil {
+		return TaskResultPaginator{}, err
+	}
+
+	p := TaskResultPaginator{
+		paginator: paginator,
+	}
+
+	return p, nil
+}
+
+func (p TaskResultPaginator) HasNext() bool {
+	return !p.paginator.Done()
+}
+
+func (p TaskResultPaginator) Close(ctx context.Context) error {
+	return p.paginator.Deallocate(ctx)
+}
+
+func (p TaskResultPaginator) NextPage(ctx context.Context) ([]map[string]any, error) {
+	var response TaskResultSearchResponse
+	err := p.paginator.Search(ctx, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []map[string]any
+	for _, hit := range response.Hits.Hits {
+		values = append(values, hit.Source)
+	}
+
+	hits := int64(len(response.Hits.Hits))
+	if hits > 0 {
+		p.paginator.UpdateState(hits, response.Hits.Hits[hits-1].Sort, response.PitID)
+	} else {
+		p.paginator.UpdateState(hits, nil, "")
+	}
+
+	return values, nil
+}
+
+// ListTaskResults returns the list hydrate of the table of a task result type, filters maps the key columns
+// of the table to their field in the result documents
+func ListTaskResults(resultType string, filters map[string]string) plugin.HydrateFunc {
+	return func(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+		plugin.Logger(ctx).Trace("ListTaskResults", "result_type", resultType)
+		runtime.GC()
+		cfg := config.GetConfig(d.Connection)
+		ke, err := config.NewClientCached(cfg, d.ConnectionCache, ctx)
+		if err != nil {
+			plugin.Logger(ctx).Error("ListTaskResults NewClientCached", "error", err)
+			return nil, err
+		}
+		k := Client{ES: ke}
+
+		paginator, err := k.NewTaskResultPaginator(resultType, es.BuildFilterWithDefaultFieldName(ctx, d.QueryContext, filters,
+			nil, nil, nil, true), d.QueryContext.Limit)
+		if err != nil {
+			plugin.Logger(ctx).Error("ListTaskResults NewTaskResultPaginator", "error", err)
+			return nil, err
+		}
+
+		for paginator.HasNext() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				plugin.Logger(ctx).Error("ListTaskResults NextPage", "error", err)
+				return nil, err
+			}
+
+			for _, v := range page {
+				d.StreamListItem(ctx, v)
+			}
+		}
+
+		err = paginator.Close(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
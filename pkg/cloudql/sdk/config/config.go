@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"os"
+
 	essdk "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
 	"github.com/turbot/steampipe-plugin-sdk/v5/connection"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
@@ -23,6 +25,8 @@ type ClientConfig struct {
 	PgSslMode  *string `cty:"pg_ssl_mode"`
 
 	ComplianceServiceBaseURL *string `cty:"compliance_service_baseurl"`
+	SchedulerServiceBaseURL  *string `cty:"scheduler_service_baseurl"`
+	TaskServiceBaseURL       *string `cty:"task_service_baseurl"`
 }
 
 func Schema() map[string]*schema.Attribute {
@@ -67,6 +71,14 @@ func Schema() map[string]*schema.Attribute {
 			Type:     schema.TypeString,
 			Required: false,
 		},
+		"scheduler_service_baseurl": {
+			Type:     schema.TypeString,
+			Required: false,
+		},
+		"task_service_baseurl": {
+			Type:     schema.TypeString,
+			Required: false,
+		},
 	}
 }
 
@@ -75,10 +87,22 @@ func Instance() any {
 }
 
 func GetConfig(connection *plugin.Connection) ClientConfig {
-	if connection == nil || connection.Config == nil {
-		return ClientConfig{}
+	var config ClientConfig
+	if connection != nil && connection.Config != nil {
+		config, _ = connection.Config.(ClientConfig)
+	}
+	// the connection config of the plugin is generated without the scheduler and task service urls, the services
+	// running the plugin pass them with env vars instead
+	if config.SchedulerServiceBaseURL == nil || len(*config.SchedulerServiceBaseURL) == 0 {
+		if baseURL := os.Getenv("SCHEDULER_BASE_URL"); baseURL != "" {
+			config.SchedulerServiceBaseURL = &baseURL
+		}
+	}
+	if config.TaskServiceBaseURL == nil || len(*config.TaskServiceBaseURL) == 0 {
+		if baseURL := os.Getenv("TASKS_BASE_URL"); baseURL != "" {
+			config.TaskServiceBaseURL = &baseURL
+		}
 	}
-	config, _ := connection.Config.(ClientConfig)
 	return config
 }
 
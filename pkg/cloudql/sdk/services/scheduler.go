@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/config"
+	schedulerClient "github.com/opengovern/opencomply/services/describe/client"
+	"github.com/turbot/steampipe-plugin-sdk/v5/connection"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func NewSchedulerClientCached(c config.ClientConfig, cache *connection.ConnectionCache, ctx context.Context) (schedulerClient.SchedulerServiceClient, error) {
+	value, ok := cache.Get(ctx, "opengovernance-scheduler-service-client")
+	if ok {
+		return value.(schedulerClient.SchedulerServiceClient), nil
+	}
+
+	plugin.Logger(ctx).Warn("scheduler service client is not cached, creating a new one")
+
+	if c.SchedulerServiceBaseURL == nil {
+		plugin.Logger(ctx).Error("scheduler service base url is not set")
+		return nil, errors.New("scheduler service base url is not set")
+	}
+	client := schedulerClient.NewSchedulerServiceClient(*c.SchedulerServiceBaseURL)
+
+	cache.Set(ctx, "opengovernance-scheduler-service-client", client)
+
+	return client, nil
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/config"
+	tasksClient "github.com/opengovern/opencomply/services/tasks/client"
+	"github.com/turbot/steampipe-plugin-sdk/v5/connection"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func NewTasksClientCached(c config.ClientConfig, cache *connection.ConnectionCache, ctx context.Context) (tasksClient.TasksServiceClient, error) {
+	value, ok := cache.Get(ctx, "opengovernance-task-service-client")
+	if ok {
+		return value.(tasksClient.TasksServiceClient), nil
+	}
+
+	plugin.Logger(ctx).Warn("task service client is not cached, creating a new one")
+
+	if c.TaskServiceBaseURL == nil {
+		plugin.Logger(ctx).Error("task service base url is not set")
+		return nil, errors.New("task service base url is not set")
+	}
+	client := tasksClient.NewTasksClient(*c.TaskServiceBaseURL)
+
+	cache.Set(ctx, "opengovernance-task-service-client", client)
+
+	return client, nil
+}
@@ -2,6 +2,8 @@ package opengovernance
 
 import (
 	"context"
+	"sync"
+
 	"github.com/opengovern/opencomply/pkg/cloudql/sdk/config"
 	"github.com/opengovern/opencomply/pkg/cloudql/sdk/extra/utils"
 	"github.com/opengovern/opencomply/pkg/cloudql/sdk/extra/view-sync"
@@ -17,6 +19,8 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			NewInstance: config.Instance,
 			Schema:      config.Schema(),
 		},
+		// the tables of the task results depend on the tasks registered on the platform
+		SchemaMode: plugin.SchemaModeDynamic,
 		TableMap: map[string]*plugin.Table{
 			"platform_findings":                 tablePlatformFindings(ctx),
 			"platform_resources":                tablePlatformResources(ctx),
@@ -26,9 +30,34 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			"platform_api_benchmark_summary":    tablePlatformApiBenchmarkSummary(ctx),
 			"platform_api_benchmark_controls":   tablePlatformApiBenchmarkControls(ctx),
 			"platform_artifact_vulnerabilities": tablePlatformArtifactVulnerabilities(ctx),
+			"platform_audit_log":                tablePlatformAuditLog(ctx),
+			"platform_jobs":                     tablePlatformJobs(ctx),
+			"platform_compliance_runs":          tablePlatformComplianceRuns(ctx),
+			"platform_resource_relationships":   tablePlatformResourceRelationships(ctx),
 		},
 	}
 
+	pluginCtx := ctx
+	var refreshedConnections sync.Map
+	p.TableMapFunc = func(ctx context.Context, d *plugin.TableMapData) (map[string]*plugin.Table, error) {
+		// tasks created or updated after the connection is loaded are picked up by refreshing its tables
+		if d.Connection != nil && config.GetConfig(d.Connection).TaskServiceBaseURL != nil {
+			if _, ok := refreshedConnections.LoadOrStore(d.Connection.Name, true); !ok {
+				go refreshTaskResultTables(pluginCtx, p, d.Connection)
+			}
+		}
+
+		tables := make(map[string]*plugin.Table)
+		for name, table := range taskResultTables(ctx, d) {
+			tables[name] = table
+		}
+		// the static tables take precedence over the generated ones
+		for name, table := range p.TableMap {
+			tables[name] = table
+		}
+		return tables, nil
+	}
+
 	extraLogger, _ := utils.NewZapLogger()
 
 	viewSync := view_sync.NewViewSync(extraLogger)
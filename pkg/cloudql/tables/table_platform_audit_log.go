@@ -0,0 +1,39 @@
+package opengovernance
+
+import (
+	"context"
+
+	og_client "github.com/opengovern/opencomply/pkg/cloudql/client"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func tablePlatformAuditLog(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "platform_audit_log",
+		Description: "OpenGovernance audit log of the mutating API calls",
+		Cache: &plugin.TableCacheOptions{
+			Enabled: false,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: og_client.ListAuditEvents,
+		},
+		Columns: []*plugin.Column{
+			{Name: "event_id", Type: proto.ColumnType_STRING},
+			{Name: "timestamp", Type: proto.ColumnType_INT, Description: "Unix timestamp of the call in milliseconds"},
+			{Name: "service", Type: proto.ColumnType_STRING},
+			{Name: "action", Type: proto.ColumnType_STRING},
+			{Name: "method", Type: proto.ColumnType_STRING},
+			{Name: "path", Type: proto.ColumnType_STRING},
+			{Name: "target_type", Type: proto.ColumnType_STRING},
+			{Name: "target_id", Type: proto.ColumnType_STRING},
+			{Name: "actor_id", Type: proto.ColumnType_STRING},
+			{Name: "actor_role", Type: proto.ColumnType_STRING},
+			{Name: "source_ip", Type: proto.ColumnType_STRING},
+			{Name: "request_id", Type: proto.ColumnType_STRING},
+			{Name: "status_code", Type: proto.ColumnType_INT},
+			{Name: "before", Type: proto.ColumnType_STRING, Description: "JSON encoded state of the target before the call, secrets are redacted"},
+			{Name: "after", Type: proto.ColumnType_STRING, Description: "JSON encoded state of the target after the call, secrets are redacted"},
+		},
+	}
+}
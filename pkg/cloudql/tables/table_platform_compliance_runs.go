@@ -0,0 +1,53 @@
+package opengovernance
+
+import (
+	"context"
+
+	og_client "github.com/opengovern/opencomply/pkg/cloudql/client"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tablePlatformComplianceRuns(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "platform_compliance_runs",
+		Description: "OpenGovernance compliance jobs with the progress of their runners",
+		Cache: &plugin.TableCacheOptions{
+			Enabled: false,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: og_client.ListPlatformComplianceRuns,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "framework_id", Operators: []string{quals.QualOperatorEqual}, Require: plugin.Optional},
+				{Name: "status", Operators: []string{quals.QualOperatorEqual}, Require: plugin.Optional},
+				{Name: "integration_id", Operators: []string{quals.QualOperatorEqual}, Require: plugin.Optional},
+				{
+					Name:      "created_at",
+					Operators: []string{quals.QualOperatorEqual, quals.QualOperatorGreater, quals.QualOperatorGreaterOrEqual, quals.QualOperatorLess, quals.QualOperatorLessOrEqual},
+					Require:   plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "job_id", Type: proto.ColumnType_INT, Transform: transform.FromField("JobID")},
+			{Name: "framework_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("FrameworkID")},
+			{Name: "status", Type: proto.ColumnType_STRING, Transform: transform.FromField("Status")},
+			{Name: "integration_id", Type: proto.ColumnType_STRING, Description: "Filters the runs evaluating the integration", Transform: transform.FromQual("integration_id")},
+			{Name: "integration_ids", Type: proto.ColumnType_JSON, Transform: transform.FromField("IntegrationIDs")},
+			{Name: "resource_collection_id", Type: proto.ColumnType_STRING, Description: "The resource collection the run was scoped to", Transform: transform.FromField("ResourceCollectionID")},
+			{Name: "with_incidents", Type: proto.ColumnType_BOOL, Transform: transform.FromField("WithIncidents")},
+			{Name: "trigger_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("TriggerType")},
+			{Name: "created_by", Type: proto.ColumnType_STRING, Transform: transform.FromField("CreatedBy")},
+			{Name: "parent_job_id", Type: proto.ColumnType_INT, Transform: transform.FromField("ParentJobID")},
+			{Name: "failure_message", Type: proto.ColumnType_STRING, Transform: transform.FromField("FailureMessage")},
+			{Name: "data_as_of", Type: proto.ColumnType_TIMESTAMP, Description: "The oldest successful discovery of the evaluated resources", Transform: transform.FromField("DataAsOf")},
+			{Name: "evaluated_on_stale_data", Type: proto.ColumnType_BOOL, Transform: transform.FromField("EvaluatedOnStaleData")},
+			{Name: "runners_count", Type: proto.ColumnType_JSON, Description: "The number of runners per runner status", Transform: transform.FromField("RunnersCount")},
+			{Name: "total_runners_count", Type: proto.ColumnType_INT, Transform: transform.FromField("TotalRunnersCount")},
+			{Name: "created_at", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CreatedAt")},
+			{Name: "updated_at", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("UpdatedAt")},
+		},
+	}
+}
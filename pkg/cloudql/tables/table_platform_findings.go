@@ -2,10 +2,13 @@ package opengovernance
 
 import (
 	"context"
+	"time"
 
 	og_client "github.com/opengovern/opencomply/pkg/cloudql/client"
+	"github.com/opengovern/opencomply/pkg/types"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
 func tablePlatformFindings(_ context.Context) *plugin.Table {
@@ -34,6 +37,15 @@ func tablePlatformFindings(_ context.Context) *plugin.Table {
 			{Name: "resource_name", Type: proto.ColumnType_STRING},
 			{Name: "resource_type", Type: proto.ColumnType_STRING},
 			{Name: "reason", Type: proto.ColumnType_STRING},
+			{Name: "first_seen_at", Type: proto.ColumnType_INT, Description: "When the result started failing, in epoch milliseconds", Transform: transform.FromField("FirstSeenAt")},
+			{Name: "resolved_at", Type: proto.ColumnType_INT, Description: "When the failing result passed again, in epoch milliseconds", Transform: transform.FromField("ResolvedAt")},
+			{Name: "sla_due_at", Type: proto.ColumnType_INT, Description: "When the SLA policy of the severity expects the result resolved, in epoch milliseconds", Transform: transform.FromField("SLADueAt")},
+			{Name: "sla_status", Type: proto.ColumnType_STRING, Description: "One of within, at_risk and breached for the failing results, met and missed for the resolved ones, and none", Transform: transform.From(getFindingSLAStatus)},
+			{Name: "owner", Type: proto.ColumnType_STRING, Description: "The team the ownership rules assign the resource of the result to", Transform: transform.FromField("Owner")},
 		},
 	}
 }
+
+func getFindingSLAStatus(_ context.Context, d *transform.TransformData) (any, error) {
+	return string(d.HydrateItem.(types.ComplianceResult).SLAStatus(time.Now())), nil
+}
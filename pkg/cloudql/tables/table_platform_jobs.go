@@ -0,0 +1,53 @@
+package opengovernance
+
+import (
+	"context"
+
+	og_client "github.com/opengovern/opencomply/pkg/cloudql/client"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tablePlatformJobs(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "platform_jobs",
+		Description: "OpenGovernance discovery, compliance, compliance runner, query and task jobs",
+		Cache: &plugin.TableCacheOptions{
+			Enabled: false,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: og_client.ListPlatformJobs,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "job_type", Operators: []string{quals.QualOperatorEqual}, Require: plugin.Optional},
+				{Name: "status", Operators: []string{quals.QualOperatorEqual}, Require: plugin.Optional},
+				{Name: "integration_id", Operators: []string{quals.QualOperatorEqual}, Require: plugin.Optional},
+				{
+					Name:      "created_at",
+					Operators: []string{quals.QualOperatorEqual, quals.QualOperatorGreater, quals.QualOperatorGreaterOrEqual, quals.QualOperatorLess, quals.QualOperatorLessOrEqual},
+					Require:   plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "job_id", Type: proto.ColumnType_INT, Transform: transform.FromField("JobID")},
+			{Name: "job_type", Type: proto.ColumnType_STRING, Description: "One of discovery, compliance, compliance_runner, query and task", Transform: transform.FromField("JobType")},
+			{Name: "status", Type: proto.ColumnType_STRING, Transform: transform.FromField("Status")},
+			{Name: "integration_id", Type: proto.ColumnType_STRING, Description: "The integration of the job, set on compliance jobs only when they evaluate a single integration", Transform: transform.FromField("IntegrationID")},
+			{Name: "integration_ids", Type: proto.ColumnType_JSON, Description: "The integrations evaluated by a compliance job", Transform: transform.FromField("IntegrationIDs")},
+			{Name: "integration_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("IntegrationType")},
+			{Name: "resource_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("ResourceType")},
+			{Name: "framework_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("FrameworkID")},
+			{Name: "query_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("QueryID")},
+			{Name: "task_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("TaskID")},
+			{Name: "parent_job_id", Type: proto.ColumnType_INT, Transform: transform.FromField("ParentJobID")},
+			{Name: "trigger_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("TriggerType")},
+			{Name: "created_by", Type: proto.ColumnType_STRING, Transform: transform.FromField("CreatedBy")},
+			{Name: "retry_count", Type: proto.ColumnType_INT, Transform: transform.FromField("RetryCount")},
+			{Name: "failure_message", Type: proto.ColumnType_STRING, Transform: transform.FromField("FailureMessage")},
+			{Name: "created_at", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CreatedAt")},
+			{Name: "updated_at", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("UpdatedAt")},
+		},
+	}
+}
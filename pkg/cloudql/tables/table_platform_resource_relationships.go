@@ -0,0 +1,44 @@
+package opengovernance
+
+import (
+	"context"
+
+	og_client "github.com/opengovern/opencomply/pkg/cloudql/client"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tablePlatformResourceRelationships(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "platform_resource_relationships",
+		Description: "Typed relationships between resources, across integrations, extracted from the resource descriptions",
+		Cache: &plugin.TableCacheOptions{
+			Enabled: false,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: og_client.ListResourceRelationships,
+			KeyColumns: plugin.OptionalColumns([]string{
+				"relationship",
+				"source_platform_id", "source_resource_id", "source_resource_type", "source_integration_id", "source_integration_type",
+				"target_platform_id", "target_resource_id", "target_resource_type", "target_integration_id", "target_integration_type",
+			}),
+		},
+		Columns: []*plugin.Column{
+			{Name: "relationship", Type: proto.ColumnType_STRING, Description: "One of contains, attached-to, routes-to, assumes-role or deploys-to"},
+			{Name: "source_platform_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Source.PlatformID")},
+			{Name: "source_resource_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Source.ResourceID")},
+			{Name: "source_resource_name", Type: proto.ColumnType_STRING, Transform: transform.FromField("Source.ResourceName")},
+			{Name: "source_resource_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("Source.ResourceType")},
+			{Name: "source_integration_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Source.IntegrationID")},
+			{Name: "source_integration_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("Source.IntegrationType")},
+			{Name: "target_platform_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Target.PlatformID")},
+			{Name: "target_resource_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Target.ResourceID")},
+			{Name: "target_resource_name", Type: proto.ColumnType_STRING, Transform: transform.FromField("Target.ResourceName")},
+			{Name: "target_resource_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("Target.ResourceType")},
+			{Name: "target_integration_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("Target.IntegrationID")},
+			{Name: "target_integration_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("Target.IntegrationType")},
+			{Name: "extracted_at", Type: proto.ColumnType_INT, Description: "Unix timestamp of the extraction in milliseconds"},
+		},
+	}
+}
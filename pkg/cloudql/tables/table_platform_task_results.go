@@ -0,0 +1,143 @@
+package opengovernance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	og_client "github.com/opengovern/opencomply/pkg/cloudql/client"
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/config"
+	"github.com/opengovern/opencomply/pkg/cloudql/sdk/services"
+	tasksApi "github.com/opengovern/opencomply/services/tasks/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// taskResultTablesRefreshInterval is how often the tables of the task results are regenerated
+const taskResultTablesRefreshInterval = time.Minute
+
+var taskResultColumnTypes = map[string]proto.ColumnType{
+	"string":    proto.ColumnType_STRING,
+	"int":       proto.ColumnType_INT,
+	"double":    proto.ColumnType_DOUBLE,
+	"bool":      proto.ColumnType_BOOL,
+	"timestamp": proto.ColumnType_TIMESTAMP,
+	"json":      proto.ColumnType_JSON,
+	"ipaddr":    proto.ColumnType_IPADDR,
+	"cidr":      proto.ColumnType_CIDR,
+}
+
+// taskResultTables generates a platform_task_<result_type> table for each task declaring a result schema,
+// the plugin keeps its static tables when the task service is not configured or not reachable
+func taskResultTables(ctx context.Context, d *plugin.TableMapData) map[string]*plugin.Table {
+	tables := make(map[string]*plugin.Table)
+
+	cfg := config.GetConfig(d.Connection)
+	if cfg.TaskServiceBaseURL == nil {
+		return tables
+	}
+	tasksClient, err := services.NewTasksClientCached(cfg, d.ConnectionCache, ctx)
+	if err != nil {
+		plugin.Logger(ctx).Error("taskResultTables NewTasksClientCached", "error", err)
+		return tables
+	}
+	schemas, err := tasksClient.ListTaskResultSchemas(&httpclient.Context{Ctx: ctx, UserRole: api.AdminRole})
+	if err != nil {
+		plugin.Logger(ctx).Error("taskResultTables ListTaskResultSchemas", "error", err)
+		return tables
+	}
+
+	for _, schema := range schemas {
+		name := fmt.Sprintf("platform_task_%s", schema.ResultType)
+		if _, ok := tables[name]; ok {
+			plugin.Logger(ctx).Warn("taskResultTables result type is declared by more than one task", "result_type", schema.ResultType, "task_id", schema.TaskID)
+			continue
+		}
+		tables[name] = tablePlatformTaskResult(name, schema)
+	}
+	return tables
+}
+
+// refreshTaskResultTables regenerates the tables of the connection periodically so the result schemas of tasks
+// created or updated on the task service show up without restarting the plugin, the plugin manager is notified only
+// when the schema changed
+func refreshTaskResultTables(ctx context.Context, p *plugin.Plugin, connection *plugin.Connection) {
+	t := time.NewTicker(taskResultTablesRefreshInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		if err := p.ConnectionSchemaChanged(connection); err != nil {
+			plugin.Logger(ctx).Error("refreshTaskResultTables ConnectionSchemaChanged", "error", err, "connection", connection.Name)
+		}
+	}
+}
+
+func tablePlatformTaskResult(name string, schema tasksApi.TaskResultSchema) *plugin.Table {
+	filters := make(map[string]string)
+	var keyColumns []*plugin.KeyColumn
+	var columns []*plugin.Column
+	for column, field := range tasksApi.ReservedResultColumns {
+		filters[column] = field
+	}
+	columns = append(columns,
+		&plugin.Column{Name: "platform_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("platform_id")},
+		&plugin.Column{Name: "resource_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("resource_id")},
+		&plugin.Column{Name: "resource_name", Type: proto.ColumnType_STRING, Transform: transform.FromField("resource_name")},
+		&plugin.Column{Name: "task_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("task_type")},
+		&plugin.Column{Name: "result_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("result_type")},
+		&plugin.Column{Name: "metadata", Type: proto.ColumnType_JSON, Transform: transform.FromField("metadata")},
+		&plugin.Column{Name: "described_by", Type: proto.ColumnType_STRING, Transform: transform.FromField("described_by")},
+		&plugin.Column{Name: "described_at", Type: proto.ColumnType_INT, Transform: transform.FromField("described_at")},
+	)
+	keyColumns = append(keyColumns,
+		&plugin.KeyColumn{Name: "platform_id", Operators: []string{quals.QualOperatorEqual}, Require: plugin.Optional},
+		&plugin.KeyColumn{Name: "resource_id", Operators: []string{quals.QualOperatorEqual}, Require: plugin.Optional},
+	)
+
+	for _, c := range schema.Columns {
+		field := c.Field
+		if field == "" {
+			field = fmt.Sprintf("description.%s", c.Name)
+		}
+		columnType, ok := taskResultColumnTypes[c.Type]
+		if !ok {
+			columnType = proto.ColumnType_JSON
+		}
+		columns = append(columns, &plugin.Column{
+			Name:        c.Name,
+			Type:        columnType,
+			Description: c.Description,
+			Transform:   transform.FromField(field),
+		})
+		if c.KeyColumn {
+			filters[c.Name] = field
+			keyColumns = append(keyColumns, &plugin.KeyColumn{
+				Name:      c.Name,
+				Operators: []string{quals.QualOperatorEqual},
+				Require:   plugin.Optional,
+			})
+		}
+	}
+
+	return &plugin.Table{
+		Name:        name,
+		Description: fmt.Sprintf("Results of the %s task", schema.TaskID),
+		Cache: &plugin.TableCacheOptions{
+			Enabled: false,
+		},
+		List: &plugin.ListConfig{
+			Hydrate:    og_client.ListTaskResults(schema.ResultType, filters),
+			KeyColumns: keyColumns,
+		},
+		Columns: columns,
+	}
+}
@@ -80,8 +80,6 @@ func (g *DirectedAcyclicGraph) TopologicalSort() ([]string, error) {
 		return nil, ErrNotAcyclic
 	}
 
-	fmt.Println("rootNodes", rootNodes)
-
 	result := newOrderedSet()
 	visited := make(map[string]bool)
 	for nodeId, _ := range rootNodes {
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	TypeKubernetes = "kubernetes"
+	TypeLocal      = "local"
+)
+
+// Executor runs the workers consuming the NATS streams of the platform, e.g. the task workers, the describers and
+// the compliance runners
+type Executor interface {
+	// EnsureWorker starts the worker or updates the running worker to the spec
+	EnsureWorker(ctx context.Context, spec WorkerSpec) error
+	// DeleteWorker stops the worker, deleting a worker that is not running is not an error
+	DeleteWorker(ctx context.Context, name string) error
+}
+
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+type WorkerSpec struct {
+	Name    string
+	Image   string
+	Command []string
+	Env     []EnvVar
+	// Replicas is the number of replicas of the workers that are not autoscaled
+	Replicas int32
+	// Autoscale scales the worker by the lag of its NATS consumer
+	Autoscale *ScaleSpec
+
+	Kubernetes KubernetesSpec
+}
+
+type ScaleSpec struct {
+	Stream          string
+	Consumer        string
+	LagThreshold    string
+	MinReplica      int32
+	MaxReplica      int32
+	PollingInterval int32
+	CooldownPeriod  int32
+}
+
+// KubernetesSpec holds the settings only used by the Kubernetes executor
+type KubernetesSpec struct {
+	ServiceAccountName string
+	// DeploymentTemplatePath and ScaledObjectTemplatePath are the yaml files of the base of the created resources,
+	// the worker spec is applied on top of them. The files have to exist if set.
+	DeploymentTemplatePath   string
+	ScaledObjectTemplatePath string
+}
+
+type Config struct {
+	Type  string      `json:"type,omitempty" yaml:"type" koanf:"type"`
+	Local LocalConfig `json:"local,omitempty" yaml:"local" koanf:"local"`
+}
+
+type LocalConfig struct {
+	// BinDir is the directory the worker commands are looked up in by their base name, the commands are used as
+	// they are if empty
+	BinDir  string `json:"bin_dir,omitempty" yaml:"bin_dir" koanf:"bin_dir"`
+	WorkDir string `json:"work_dir,omitempty" yaml:"work_dir" koanf:"work_dir"`
+	// EmbeddedNATS starts a NATS server with JetStream enabled in the process of the service for the service and
+	// its workers
+	EmbeddedNATS bool   `json:"embedded_nats,omitempty" yaml:"embedded_nats" koanf:"embedded_nats"`
+	NatsPort     int    `json:"nats_port,omitempty" yaml:"nats_port" koanf:"nats_port"`
+	NatsStoreDir string `json:"nats_store_dir,omitempty" yaml:"nats_store_dir" koanf:"nats_store_dir"`
+}
+
+// ConfigFromEnv reads the executor config of the services configured by environment variables
+func ConfigFromEnv() Config {
+	natsPort, _ := strconv.Atoi(os.Getenv("EXECUTOR_LOCAL_NATS_PORT"))
+	return Config{
+		Type: os.Getenv("EXECUTOR_TYPE"),
+		Local: LocalConfig{
+			BinDir:       os.Getenv("EXECUTOR_LOCAL_BIN_DIR"),
+			WorkDir:      os.Getenv("EXECUTOR_LOCAL_WORK_DIR"),
+			EmbeddedNATS: strings.ToLower(os.Getenv("EXECUTOR_LOCAL_EMBEDDED_NATS")) == "true",
+			NatsPort:     natsPort,
+			NatsStoreDir: os.Getenv("EXECUTOR_LOCAL_NATS_STORE_DIR"),
+		},
+	}
+}
+
+func (c Config) IsLocal() bool {
+	return c.Type == TypeLocal
+}
+
+// New returns the executor of the config, the Kubernetes executor is the default. The kube client is not used by
+// the local executor and can be nil.
+func New(cfg Config, logger *zap.Logger, kubeClient client.Client, namespace string) (Executor, error) {
+	switch cfg.Type {
+	case "", TypeKubernetes:
+		if kubeClient == nil {
+			return nil, fmt.Errorf("kubernetes executor requires a kube client")
+		}
+		natsMonitoringURL, _ := os.LookupEnv("SCALED_OBJECT_NATS_URL")
+		return NewKubernetesExecutor(kubeClient, namespace, natsMonitoringURL), nil
+	case TypeLocal:
+		return NewLocalExecutor(logger, cfg.Local), nil
+	default:
+		return nil, fmt.Errorf("unsupported executor type: %s", cfg.Type)
+	}
+}
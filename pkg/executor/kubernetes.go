@@ -0,0 +1,278 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/goccy/go-yaml"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubernetesExecutor runs the workers as Deployments, autoscaled workers are scaled by a KEDA ScaledObject
+type KubernetesExecutor struct {
+	kubeClient        client.Client
+	namespace         string
+	natsMonitoringURL string
+}
+
+func NewKubernetesExecutor(kubeClient client.Client, namespace, natsMonitoringURL string) *KubernetesExecutor {
+	return &KubernetesExecutor{
+		kubeClient:        kubeClient,
+		namespace:         namespace,
+		natsMonitoringURL: natsMonitoringURL,
+	}
+}
+
+func (e *KubernetesExecutor) EnsureWorker(ctx context.Context, spec WorkerSpec) error {
+	if e.namespace == "" {
+		return errors.New("current namespace lookup failed")
+	}
+
+	desired, err := e.deployment(spec)
+	if err != nil {
+		return err
+	}
+	var deployment appsv1.Deployment
+	err = e.kubeClient.Get(ctx, client.ObjectKey{
+		Namespace: e.namespace,
+		Name:      spec.Name,
+	}, &deployment)
+	if err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		if err := e.kubeClient.Create(ctx, &desired); err != nil {
+			return err
+		}
+	} else {
+		deployment.Spec.Template = desired.Spec.Template
+		// replicas of autoscaled workers are left to keda
+		if spec.Autoscale == nil {
+			deployment.Spec.Replicas = desired.Spec.Replicas
+		}
+		if err := e.kubeClient.Update(ctx, &deployment); err != nil {
+			return err
+		}
+	}
+
+	if spec.Autoscale == nil {
+		return nil
+	}
+
+	desiredScaledObject, err := e.scaledObject(spec)
+	if err != nil {
+		return err
+	}
+	var scaledObject kedav1alpha1.ScaledObject
+	err = e.kubeClient.Get(ctx, client.ObjectKey{
+		Namespace: e.namespace,
+		Name:      scaledObjectName(spec.Name),
+	}, &scaledObject)
+	if err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		return e.kubeClient.Create(ctx, &desiredScaledObject)
+	}
+	scaledObject.Spec = desiredScaledObject.Spec
+	return e.kubeClient.Update(ctx, &scaledObject)
+}
+
+func (e *KubernetesExecutor) DeleteWorker(ctx context.Context, name string) error {
+	if e.namespace == "" {
+		return errors.New("current namespace lookup failed")
+	}
+
+	var scaledObject kedav1alpha1.ScaledObject
+	err := e.kubeClient.Get(ctx, client.ObjectKey{
+		Namespace: e.namespace,
+		Name:      scaledObjectName(name),
+	}, &scaledObject)
+	// the keda crds are not installed on clusters without autoscaling
+	if err == nil {
+		if err := e.kubeClient.Delete(ctx, &scaledObject); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	deployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: e.namespace,
+		},
+	}
+	if err := e.kubeClient.Delete(ctx, &deployment); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	return nil
+}
+
+func (e *KubernetesExecutor) deployment(spec WorkerSpec) (appsv1.Deployment, error) {
+	var template appsv1.Deployment
+	if err := readTemplate(spec.Kubernetes.DeploymentTemplatePath, &template); err != nil {
+		return appsv1.Deployment{}, err
+	}
+	podSpec := template.Spec.Template.Spec
+
+	var container corev1.Container
+	if len(podSpec.Containers) > 0 {
+		container = podSpec.Containers[0]
+	}
+	container.Name = spec.Name
+	container.Image = spec.Image
+	container.Command = spec.Command
+	if container.ImagePullPolicy == "" {
+		container.ImagePullPolicy = corev1.PullAlways
+	}
+	container.Env = mergeEnv(container.Env, spec.Env)
+	if len(podSpec.Containers) > 0 {
+		podSpec.Containers[0] = container
+	} else {
+		podSpec.Containers = []corev1.Container{container}
+	}
+	if spec.Kubernetes.ServiceAccountName != "" {
+		podSpec.ServiceAccountName = spec.Kubernetes.ServiceAccountName
+	}
+
+	replicas := spec.Replicas
+	if spec.Autoscale != nil {
+		replicas = 0
+	}
+	labels := map[string]string{
+		"app": spec.Name,
+	}
+	podLabels := template.Spec.Template.ObjectMeta.Labels
+	if podLabels == nil {
+		podLabels = map[string]string{}
+	}
+	podLabels["app"] = spec.Name
+
+	deploymentSpec := template.Spec
+	deploymentSpec.Replicas = aws.Int32(replicas)
+	deploymentSpec.Selector = &metav1.LabelSelector{
+		MatchLabels: labels,
+	}
+	deploymentSpec.Template.ObjectMeta.Labels = podLabels
+	deploymentSpec.Template.Spec = podSpec
+
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      spec.Name,
+			Namespace: e.namespace,
+			Labels:    labels,
+		},
+		Spec: deploymentSpec,
+	}, nil
+}
+
+func (e *KubernetesExecutor) scaledObject(spec WorkerSpec) (kedav1alpha1.ScaledObject, error) {
+	var template kedav1alpha1.ScaledObject
+	if err := readTemplate(spec.Kubernetes.ScaledObjectTemplatePath, &template); err != nil {
+		return kedav1alpha1.ScaledObject{}, err
+	}
+	scale := spec.Autoscale
+
+	scaledObjectSpec := template.Spec
+	scaledObjectSpec.ScaleTargetRef = &kedav1alpha1.ScaleTarget{
+		Name:       spec.Name,
+		Kind:       "Deployment",
+		APIVersion: "apps/v1",
+	}
+	if scale.PollingInterval != 0 {
+		scaledObjectSpec.PollingInterval = aws.Int32(scale.PollingInterval)
+	}
+	if scale.CooldownPeriod != 0 {
+		scaledObjectSpec.CooldownPeriod = aws.Int32(scale.CooldownPeriod)
+	}
+	scaledObjectSpec.MinReplicaCount = aws.Int32(scale.MinReplica)
+	if scale.MaxReplica != 0 {
+		scaledObjectSpec.MaxReplicaCount = aws.Int32(scale.MaxReplica)
+	}
+	if scaledObjectSpec.Fallback == nil {
+		scaledObjectSpec.Fallback = &kedav1alpha1.Fallback{
+			FailureThreshold: 1,
+			Replicas:         1,
+		}
+	}
+
+	trigger := kedav1alpha1.ScaleTriggers{
+		Type: "nats-jetstream",
+		Metadata: map[string]string{
+			"account":  "$G",
+			"useHttps": "false",
+		},
+	}
+	if len(scaledObjectSpec.Triggers) > 0 {
+		trigger = scaledObjectSpec.Triggers[0]
+	}
+	if trigger.Metadata == nil {
+		trigger.Metadata = map[string]string{}
+	}
+	trigger.Metadata["natsServerMonitoringEndpoint"] = e.natsMonitoringURL
+	trigger.Metadata["stream"] = scale.Stream
+	trigger.Metadata["consumer"] = scale.Consumer + "-service"
+	if scale.LagThreshold != "" {
+		trigger.Metadata["lagThreshold"] = scale.LagThreshold
+	}
+	if len(scaledObjectSpec.Triggers) > 0 {
+		scaledObjectSpec.Triggers[0] = trigger
+	} else {
+		scaledObjectSpec.Triggers = []kedav1alpha1.ScaleTriggers{trigger}
+	}
+
+	return kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scaledObjectName(spec.Name),
+			Namespace: e.namespace,
+		},
+		Spec: scaledObjectSpec,
+	}, nil
+}
+
+// readTemplate reads the yaml template file into v, an empty path leaves v empty. A missing file is an error, the
+// templates are shipped with the kubernetes deployments of the services.
+func readTemplate(path string, v any) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read template file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal template file %s: %w", path, err)
+	}
+	return nil
+}
+
+// mergeEnv adds the env vars of the spec to the env vars of the template, replacing the ones with the same name
+func mergeEnv(base []corev1.EnvVar, env []EnvVar) []corev1.EnvVar {
+	merged := make([]corev1.EnvVar, 0, len(base)+len(env))
+	overridden := make(map[string]bool)
+	for _, e := range env {
+		overridden[e.Name] = true
+	}
+	for _, e := range base {
+		if !overridden[e.Name] {
+			merged = append(merged, e)
+		}
+	}
+	for _, e := range env {
+		merged = append(merged, corev1.EnvVar{
+			Name:  e.Name,
+			Value: e.Value,
+		})
+	}
+	return merged
+}
+
+func scaledObjectName(name string) string {
+	return name + "-scaled-object"
+}
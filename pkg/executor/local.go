@@ -0,0 +1,174 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	minRestartBackoff = time.Second
+	maxRestartBackoff = 30 * time.Second
+)
+
+// WorkerFunc runs a worker in the process of the service until the context is done. In-process workers share the
+// environment of the service, the env vars of the worker spec are passed to them instead.
+type WorkerFunc func(ctx context.Context, env map[string]string) error
+
+// LocalExecutor runs each worker as a single subprocess, or in-process if a WorkerFunc is registered for its
+// command, and restarts it when it exits. Images and autoscaling are ignored, the worker commands have to be
+// available on the machine.
+type LocalExecutor struct {
+	logger *zap.Logger
+	cfg    LocalConfig
+
+	mu        sync.Mutex
+	workers   map[string]*localWorker
+	inProcess map[string]WorkerFunc
+}
+
+type localWorker struct {
+	spec   WorkerSpec
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewLocalExecutor(logger *zap.Logger, cfg LocalConfig) *LocalExecutor {
+	return &LocalExecutor{
+		logger:    logger.Named("local-executor"),
+		cfg:       cfg,
+		workers:   make(map[string]*localWorker),
+		inProcess: make(map[string]WorkerFunc),
+	}
+}
+
+// RegisterInProcess runs the workers whose command is the given command with the function instead of a subprocess
+func (e *LocalExecutor) RegisterInProcess(command string, fn WorkerFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.inProcess[command] = fn
+}
+
+func (e *LocalExecutor) EnsureWorker(ctx context.Context, spec WorkerSpec) error {
+	if len(spec.Command) == 0 {
+		return errors.New("worker command is empty")
+	}
+	// the kubernetes settings are not used
+	spec.Kubernetes = KubernetesSpec{}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if worker, ok := e.workers[spec.Name]; ok {
+		if reflect.DeepEqual(worker.spec, spec) {
+			return nil
+		}
+		if err := e.stop(ctx, worker); err != nil {
+			return err
+		}
+	}
+
+	// the worker outlives the request starting it
+	workerCtx, cancel := context.WithCancel(context.Background())
+	worker := &localWorker{
+		spec:   spec,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	e.workers[spec.Name] = worker
+	go e.run(workerCtx, worker, e.inProcess[spec.Command[0]])
+
+	e.logger.Info("started worker", zap.String("worker", spec.Name), zap.Strings("command", spec.Command))
+	return nil
+}
+
+func (e *LocalExecutor) DeleteWorker(ctx context.Context, name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	worker, ok := e.workers[name]
+	if !ok {
+		return nil
+	}
+	if err := e.stop(ctx, worker); err != nil {
+		return err
+	}
+	delete(e.workers, name)
+
+	e.logger.Info("stopped worker", zap.String("worker", name))
+	return nil
+}
+
+func (e *LocalExecutor) stop(ctx context.Context, worker *localWorker) error {
+	worker.cancel()
+	select {
+	case <-worker.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *LocalExecutor) run(ctx context.Context, worker *localWorker, fn WorkerFunc) {
+	defer close(worker.done)
+
+	backoff := minRestartBackoff
+	for {
+		startedAt := time.Now()
+		var err error
+		if fn != nil {
+			err = fn(ctx, envMap(worker.spec.Env))
+		} else {
+			err = e.runProcess(ctx, worker.spec)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		e.logger.Error("worker exited, restarting", zap.String("worker", worker.spec.Name), zap.Error(err),
+			zap.Duration("backoff", backoff))
+
+		// workers that ran for a while are restarted right away
+		if time.Since(startedAt) > maxRestartBackoff {
+			backoff = minRestartBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRestartBackoff)
+	}
+}
+
+func (e *LocalExecutor) runProcess(ctx context.Context, spec WorkerSpec) error {
+	command := spec.Command[0]
+	if e.cfg.BinDir != "" {
+		command = filepath.Join(e.cfg.BinDir, filepath.Base(command))
+	}
+
+	cmd := exec.CommandContext(ctx, command, spec.Command[1:]...)
+	cmd.Dir = e.cfg.WorkDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for _, env := range spec.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	return cmd.Run()
+}
+
+func envMap(env []EnvVar) map[string]string {
+	m := make(map[string]string, len(env))
+	for _, e := range env {
+		m[e.Name] = e.Value
+	}
+	return m
+}
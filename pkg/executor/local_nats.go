@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLocalNatsPort  = 4222
+	localNatsStartTimeout = 10 * time.Second
+)
+
+// StartLocalNATS runs an embedded NATS server with JetStream enabled until the context is done and returns its
+// url. It lets the local executor run a service and its workers without any other infrastructure.
+func StartLocalNATS(ctx context.Context, logger *zap.Logger, cfg LocalConfig) (string, error) {
+	port := cfg.NatsPort
+	if port == 0 {
+		port = defaultLocalNatsPort
+	}
+	storeDir := cfg.NatsStoreDir
+	if storeDir == "" {
+		var err error
+		storeDir, err = os.MkdirTemp("", "nats-jetstream")
+		if err != nil {
+			return "", err
+		}
+	}
+
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      port,
+		JetStream: true,
+		StoreDir:  storeDir,
+		// the service handles the signals
+		NoSigs: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create nats server: %w", err)
+	}
+	go ns.Start()
+	if !ns.ReadyForConnections(localNatsStartTimeout) {
+		ns.Shutdown()
+		return "", fmt.Errorf("nats server did not start listening on port %d", port)
+	}
+	go func() {
+		<-ctx.Done()
+		ns.Shutdown()
+	}()
+
+	logger.Info("started embedded nats server", zap.String("url", ns.ClientURL()), zap.String("storeDir", storeDir))
+	return ns.ClientURL(), nil
+}
@@ -0,0 +1,119 @@
+package leaderelection
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/ticker"
+	"go.uber.org/zap"
+)
+
+const (
+	LeaseDuration = 30 * time.Second
+	RenewInterval = 10 * time.Second
+)
+
+// LeaseStore keeps the leases of the scheduler loops, every service stores them in its own database
+type LeaseStore interface {
+	// TryAcquireSchedulerLease acquires or renews the lease of the loop, it returns false while another holder
+	// owns an unexpired lease
+	TryAcquireSchedulerLease(name, holderID string, duration time.Duration) (bool, error)
+	ReleaseSchedulerLease(name, holderID string) error
+}
+
+// Elector elects a leader per scheduler loop using leases stored in postgres, so scheduler replicas can run
+// side by side while every loop runs on a single replica. Loops are registered on their first IsLeader call.
+type Elector struct {
+	holderID string
+	store    LeaseStore
+	logger   *zap.Logger
+
+	mu    sync.RWMutex
+	loops map[string]bool
+}
+
+func New(holderID string, store LeaseStore, logger *zap.Logger) *Elector {
+	return &Elector{
+		holderID: holderID,
+		store:    store,
+		logger:   logger.With(zap.String("holderID", holderID)),
+		loops:    make(map[string]bool),
+	}
+}
+
+func (e *Elector) HolderID() string {
+	return e.holderID
+}
+
+// IsLeader reports whether this replica currently holds the lease of the loop.
+func (e *Elector) IsLeader(loop string) bool {
+	e.mu.RLock()
+	leading, ok := e.loops[loop]
+	e.mu.RUnlock()
+	if !ok {
+		return e.acquire(loop)
+	}
+	return leading
+}
+
+// Run renews the leases of the registered loops and takes over the expired ones until ctx is done.
+func (e *Elector) Run(ctx context.Context) {
+	t := ticker.NewTicker(RenewInterval, time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			e.mu.RLock()
+			loops := make([]string, 0, len(e.loops))
+			for loop := range e.loops {
+				loops = append(loops, loop)
+			}
+			e.mu.RUnlock()
+
+			for _, loop := range loops {
+				e.acquire(loop)
+			}
+		case <-ctx.Done():
+			e.Release()
+			return
+		}
+	}
+}
+
+// Release gives up all the leases held by this replica so other replicas can take over right away.
+func (e *Elector) Release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for loop, leading := range e.loops {
+		if !leading {
+			continue
+		}
+		if err := e.store.ReleaseSchedulerLease(loop, e.holderID); err != nil {
+			e.logger.Error("failed to release scheduler lease", zap.String("loop", loop), zap.Error(err))
+		}
+		e.loops[loop] = false
+	}
+}
+
+func (e *Elector) acquire(loop string) bool {
+	leading, err := e.store.TryAcquireSchedulerLease(loop, e.holderID, LeaseDuration)
+	if err != nil {
+		// without a renewed lease another replica may take over, stop leading to avoid running twice
+		e.logger.Error("failed to acquire scheduler lease", zap.String("loop", loop), zap.Error(err))
+		leading = false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if wasLeading, ok := e.loops[loop]; !ok || wasLeading != leading {
+		if leading {
+			e.logger.Info("became leader of scheduler loop", zap.String("loop", loop))
+		} else if ok {
+			e.logger.Info("lost leadership of scheduler loop", zap.String("loop", loop))
+		}
+	}
+	e.loops[loop] = leading
+	return leading
+}
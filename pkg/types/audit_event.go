@@ -0,0 +1,30 @@
+package types
+
+// AuditEvent records a mutating API call. Events are keyed by their unique ID so they are never overwritten.
+type AuditEvent struct {
+	EsID    string `json:"es_id"`
+	EsIndex string `json:"es_index"`
+
+	EventID    string `json:"eventID"`
+	Timestamp  int64  `json:"timestamp"`
+	Service    string `json:"service"`
+	Action     string `json:"action"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetID"`
+	ActorID    string `json:"actorID"`
+	ActorRole  string `json:"actorRole"`
+	SourceIP   string `json:"sourceIP"`
+	RequestID  string `json:"requestID"`
+	StatusCode int    `json:"statusCode"`
+	// Before and After are the JSON encoded state of the target with secrets redacted
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+func (r AuditEvent) KeysAndIndex() ([]string, string) {
+	return []string{
+		r.EventID,
+	}, AuditLogIndex
+}
@@ -38,4 +38,7 @@ type JobSummary struct {
 	FrameworkID    string    `json:"framework_id"`
 	JobStartedAt   time.Time `json:"job_started_at"`
 	IntegrationIDs []string  `json:"integration_ids"`
+
+	DataAsOf             *time.Time `json:"data_as_of,omitempty"`
+	EvaluatedOnStaleData bool       `json:"evaluated_on_stale_data"`
 }
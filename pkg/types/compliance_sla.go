@@ -0,0 +1,71 @@
+package types
+
+import "time"
+
+// SLAAtRiskShare is the share of the SLA time after which an open result is at risk of breaching it
+const SLAAtRiskShare = 0.75
+
+type ComplianceResultSLAStatus string
+
+const (
+	// ComplianceResultSLAStatusNone is the status of the results without an SLA policy for their severity
+	ComplianceResultSLAStatusNone     ComplianceResultSLAStatus = "none"
+	ComplianceResultSLAStatusWithin   ComplianceResultSLAStatus = "within"
+	ComplianceResultSLAStatusAtRisk   ComplianceResultSLAStatus = "at_risk"
+	ComplianceResultSLAStatusBreached ComplianceResultSLAStatus = "breached"
+	// ComplianceResultSLAStatusMet and ComplianceResultSLAStatusMissed are the statuses of the resolved results
+	ComplianceResultSLAStatusMet    ComplianceResultSLAStatus = "met"
+	ComplianceResultSLAStatusMissed ComplianceResultSLAStatus = "missed"
+)
+
+// IsOpen is true while the result is failing and was not resolved by its resource going away
+func (r ComplianceResult) IsOpen() bool {
+	return !r.ComplianceStatus.IsPassed() && r.ResolvedAt == nil
+}
+
+// Age is the time the result has been failing at the given time, or was failing until it got resolved
+func (r ComplianceResult) Age(now time.Time) time.Duration {
+	firstSeenAt := r.FirstSeenAt
+	if firstSeenAt == 0 {
+		firstSeenAt = r.EvaluatedAt
+	}
+	if firstSeenAt == 0 {
+		return 0
+	}
+	end := now
+	if !r.IsOpen() && r.ResolvedAt != nil {
+		end = time.UnixMilli(*r.ResolvedAt)
+	}
+	if age := end.Sub(time.UnixMilli(firstSeenAt)); age > 0 {
+		return age
+	}
+	return 0
+}
+
+// SLAStatus compares the result with its SLA due time at the given time, passing results which never failed
+// have no SLA
+func (r ComplianceResult) SLAStatus(now time.Time) ComplianceResultSLAStatus {
+	if r.SLADueAt == nil || r.FirstSeenAt == 0 {
+		return ComplianceResultSLAStatusNone
+	}
+	dueAt := time.UnixMilli(*r.SLADueAt)
+	if !r.IsOpen() {
+		if r.ResolvedAt == nil {
+			return ComplianceResultSLAStatusNone
+		}
+		if time.UnixMilli(*r.ResolvedAt).After(dueAt) {
+			return ComplianceResultSLAStatusMissed
+		}
+		return ComplianceResultSLAStatusMet
+	}
+
+	if now.After(dueAt) {
+		return ComplianceResultSLAStatusBreached
+	}
+	firstSeenAt := time.UnixMilli(r.FirstSeenAt)
+	atRiskAt := firstSeenAt.Add(time.Duration(float64(dueAt.Sub(firstSeenAt)) * SLAAtRiskShare))
+	if now.After(atRiskAt) {
+		return ComplianceResultSLAStatusAtRisk
+	}
+	return ComplianceResultSLAStatusWithin
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// ComplianceSnapshot is written once a compliance job is summarized and lists what the job evaluated.
+// The snapshot of a framework at a point in time is made of the latest snapshot of every integration.
+type ComplianceSnapshot struct {
+	EsID    string `json:"es_id"`
+	EsIndex string `json:"es_index"`
+
+	ComplianceJobID      uint       `json:"complianceJobID"`
+	SummarizerJobID      uint       `json:"summarizerJobID"`
+	FrameworkID          string     `json:"frameworkID"`
+	IntegrationIDs       []string   `json:"integrationIDs"`
+	EvaluatedAtEpoch     int64      `json:"evaluatedAtEpoch"`
+	DataAsOf             *time.Time `json:"dataAsOf,omitempty"`
+	EvaluatedOnStaleData bool       `json:"evaluatedOnStaleData"`
+}
+
+func (r ComplianceSnapshot) KeysAndIndex() ([]string, string) {
+	return []string{
+		fmt.Sprintf("%d", r.ComplianceJobID),
+	}, ComplianceSnapshotsIndex
+}
+
+// ComplianceResultSnapshot is an immutable copy of a compliance result as evaluated by a compliance job.
+type ComplianceResultSnapshot struct {
+	ComplianceResult
+}
+
+func (r ComplianceResultSnapshot) KeysAndIndex() ([]string, string) {
+	keys, _ := r.ComplianceResult.KeysAndIndex()
+	keys = append(keys, fmt.Sprintf("%d", r.ComplianceJobID))
+	return keys, ComplianceResultSnapshotsIndex
+}
+
+// ResourceFindingSnapshot is an immutable copy of a resource finding as summarized for a compliance job.
+type ResourceFindingSnapshot struct {
+	ResourceFinding
+	IntegrationID   string `json:"integrationID"`
+	ComplianceJobID uint   `json:"complianceJobID"`
+}
+
+func (r ResourceFindingSnapshot) KeysAndIndex() ([]string, string) {
+	keys, _ := r.ResourceFinding.KeysAndIndex()
+	keys = append(keys, fmt.Sprintf("%d", r.ComplianceJobID))
+	return keys, ResourceFindingSnapshotsIndex
+}
@@ -48,6 +48,8 @@ type ComplianceResult struct {
 	StateActive        bool                     `json:"stateActive" example:"true"`
 	ComplianceStatus   ComplianceStatus         `json:"complianceStatus" example:"alarm"`
 	Severity           ComplianceResultSeverity `json:"severity" example:"low"`
+	OriginalSeverity   ComplianceResultSeverity `json:"originalSeverity,omitempty" example:"medium"`
+	SeverityOverrideID *uint                    `json:"severityOverrideID,omitempty" example:"1"`
 	IntegrationType    integration.Type         `json:"integrationType" example:"Azure"`
 	PlatformResourceID string                   `json:"platformResourceID" example:"/subscriptions/123/resourceGroups/rg-1/providers/Microsoft.Compute/virtualMachines/vm-1"`
 	ResourceID         string                   `json:"resourceID" example:"/subscriptions/123/resourceGroups/rg-1/providers/Microsoft.Compute/virtualMachines/vm-1"`
@@ -60,6 +62,17 @@ type ComplianceResult struct {
 	ComplianceJobID    uint                     `json:"complianceJobID" example:"1"`
 	LastUpdatedAt      int64                    `json:"lastUpdatedAt" example:"1589395200"`
 
+	// FirstSeenAt is when the result started failing and is carried over the runs while it keeps failing,
+	// ResolvedAt is when it passed again or its resource dropped out of the query output and SLADueAt is when the
+	// SLA policy of its severity expects it resolved
+	FirstSeenAt int64  `json:"firstSeenAt,omitempty" example:"1589395200"`
+	ResolvedAt  *int64 `json:"resolvedAt,omitempty" example:"1589395200"`
+	SLADueAt    *int64 `json:"slaDueAt,omitempty" example:"1589395200"`
+
+	// Owner is the team accountable for the result as assigned by the ownership rules when the results are
+	// summarized, empty when no rule matches its resource
+	Owner string `json:"owner,omitempty" example:"platform-team"`
+
 	ParentBenchmarks []string `json:"-"`
 }
 
@@ -100,6 +113,8 @@ type ResourceFinding struct {
 	ResourceCollection    []string        `json:"resourceCollection" example:"azure_cis_v140_7_5"`
 	ResourceCollectionMap map[string]bool `json:"-"` // for creation of the slice only
 
+	Owner string `json:"owner,omitempty" example:"platform-team"`
+
 	JobId       uint  `json:"jobId" example:"1"`
 	EvaluatedAt int64 `json:"evaluatedAt" example:"1589395200"`
 }
@@ -9,4 +9,9 @@ const (
 	ComplianceJobReportControlViewIndex    = "compliance_job_report_control_view"
 	ComplianceJobReportControlSummaryIndex = "compliance_job_report_control_summary"
 	ComplianceJobReportResourceViewIndex   = "compliance_job_report_resource_view"
+	ComplianceSnapshotsIndex               = "compliance_snapshots"
+	ComplianceResultSnapshotsIndex         = "compliance_result_snapshots"
+	ResourceFindingSnapshotsIndex          = "resource_finding_snapshots"
+	AuditLogIndex                          = "audit_log"
+	ResourceRelationshipsIndex             = "resource_relationships"
 )
@@ -0,0 +1,45 @@
+package types
+
+type ResourceRelationshipType string
+
+const (
+	ResourceRelationshipContains    ResourceRelationshipType = "contains"
+	ResourceRelationshipAttachedTo  ResourceRelationshipType = "attached-to"
+	ResourceRelationshipRoutesTo    ResourceRelationshipType = "routes-to"
+	ResourceRelationshipAssumesRole ResourceRelationshipType = "assumes-role"
+	ResourceRelationshipDeploysTo   ResourceRelationshipType = "deploys-to"
+)
+
+// ResourceRelationshipEndpoint identifies a resource on one side of a relationship
+type ResourceRelationshipEndpoint struct {
+	PlatformID      string `json:"platformID"`
+	ResourceID      string `json:"resourceID"`
+	ResourceName    string `json:"resourceName"`
+	ResourceType    string `json:"resourceType"`
+	IntegrationID   string `json:"integrationID"`
+	IntegrationType string `json:"integrationType"`
+}
+
+// ResourceRelationship is a typed, directed edge between two resources, possibly of different integrations.
+// ExtractedFrom is the integration and resource type whose descriptions produced the edge, the edges of an
+// integration and resource type are replaced every time they are extracted again.
+type ResourceRelationship struct {
+	EsID    string `json:"es_id"`
+	EsIndex string `json:"es_index"`
+
+	Relationship ResourceRelationshipType     `json:"relationship"`
+	Source       ResourceRelationshipEndpoint `json:"source"`
+	Target       ResourceRelationshipEndpoint `json:"target"`
+
+	ExtractedFromIntegrationID string `json:"extractedFromIntegrationID"`
+	ExtractedFromResourceType  string `json:"extractedFromResourceType"`
+	ExtractedAt                int64  `json:"extractedAt"`
+}
+
+func (r ResourceRelationship) KeysAndIndex() ([]string, string) {
+	return []string{
+		r.Source.PlatformID,
+		string(r.Relationship),
+		r.Target.PlatformID,
+	}, ResourceRelationshipsIndex
+}
@@ -0,0 +1,86 @@
+package utils
+
+import "encoding/json"
+
+// ResourceCollectionFilter is the serialized form of opengovernance.ResourceCollectionFilter
+type ResourceCollectionFilter struct {
+	Connectors    []string          `json:"connectors"`
+	AccountIDs    []string          `json:"account_ids"`
+	Regions       []string          `json:"regions"`
+	ResourceTypes []string          `json:"resource_types"`
+	Tags          map[string]string `json:"tags"`
+}
+
+// ResourceCollectionQuery returns the query matching the lookup resources of any of the filters, a resource
+// matches a filter when it matches all the fields set on it. The filters are taken in their serialized form
+// so both opengovernance.ResourceCollectionFilter and ResourceCollectionFilter are accepted.
+func ResourceCollectionQuery(filters any) (map[string]any, error) {
+	filtersJson, err := json.Marshal(filters)
+	if err != nil {
+		return nil, err
+	}
+	var rcFilters []ResourceCollectionFilter
+	if err := json.Unmarshal(filtersJson, &rcFilters); err != nil {
+		return nil, err
+	}
+
+	should := make([]any, 0, len(rcFilters))
+	for _, f := range rcFilters {
+		must := make([]any, 0)
+		if len(f.Connectors) > 0 {
+			must = append(must, map[string]any{
+				"terms": map[string][]string{
+					"integration_type": f.Connectors,
+				},
+			})
+		}
+		if len(f.AccountIDs) > 0 {
+			must = append(must, map[string]any{
+				"terms": map[string][]string{
+					"integration_id": f.AccountIDs,
+				},
+			})
+		}
+		if len(f.Regions) > 0 {
+			must = append(must, map[string]any{
+				"terms": map[string][]string{
+					"location": f.Regions,
+				},
+			})
+		}
+		if len(f.ResourceTypes) > 0 {
+			must = append(must, map[string]any{
+				"terms": map[string][]string{
+					"resource_type": ToLowerStringSlice(f.ResourceTypes),
+				},
+			})
+		}
+		for key, value := range f.Tags {
+			must = append(must, map[string]any{
+				"nested": map[string]any{
+					"path": "canonical_tags",
+					"query": map[string]any{
+						"bool": map[string]any{
+							"filter": []any{
+								map[string]any{"term": map[string]string{"canonical_tags.key": key}},
+								map[string]any{"term": map[string]string{"canonical_tags.value": value}},
+							},
+						},
+					},
+				},
+			})
+		}
+		should = append(should, map[string]any{
+			"bool": map[string]any{
+				"filter": must,
+			},
+		})
+	}
+
+	return map[string]any{
+		"bool": map[string]any{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}, nil
+}
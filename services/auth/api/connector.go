@@ -4,26 +4,64 @@ package api
 // CreateConnectorRequest represents the expected payload for creating or updating a connector.
 type CreateConnectorRequest struct {
 
-	ConnectorType    string `json:"connector_type" validate:"required,oneof=oidc"`                                  // 'oidc' is supported for now
-	ConnectorSubType string `json:"connector_sub_type" validate:"omitempty,oneof=general google-workspace entraid"` // Optional sub-type
+	ConnectorType    string `json:"connector_type" validate:"required,oneof=oidc saml ldap"`
+	ConnectorSubType string `json:"connector_sub_type" validate:"omitempty,oneof=general google-workspace entraid okta adfs active-directory"` // Optional sub-type
 	Issuer           string `json:"issuer,omitempty" validate:"omitempty,url"`
 	TenantID         string `json:"tenant_id,omitempty" validate:"omitempty,uuid"`
-	ClientID         string `json:"client_id" validate:"required"`
-	ClientSecret     string `json:"client_secret" validate:"required"`
+	ClientID         string `json:"client_id" validate:"required_if=ConnectorType oidc"`
+	ClientSecret     string `json:"client_secret" validate:"required_if=ConnectorType oidc"`
 	ID               string `json:"id,omitempty"`   // Optional
 	Name             string `json:"name,omitempty"` // Optional
+
+	SAMLConnectorParams
+	LDAPConnectorParams
 }
 type UpdateConnectorRequest struct {
 	ConnectorID 	string `json:"connector_id" validate:"required"`
-	ConnectorType    string `json:"connector_type" validate:"required,oneof=oidc"`                                  // 'oidc' is supported for now
-	ConnectorSubType string `json:"connector_sub_type" validate:"omitempty,oneof=general google-workspace entraid"` // Optional sub-type
+	ConnectorType    string `json:"connector_type" validate:"required,oneof=oidc saml ldap"`
+	ConnectorSubType string `json:"connector_sub_type" validate:"omitempty,oneof=general google-workspace entraid okta adfs active-directory"` // Optional sub-type
 	Issuer           string `json:"issuer,omitempty" validate:"omitempty,url"`
 	TenantID         string `json:"tenant_id,omitempty" validate:"omitempty,uuid"`
-	ClientID         string `json:"client_id" validate:"required"`
-	ClientSecret     string `json:"client_secret" validate:"required"`
+	ClientID         string `json:"client_id" validate:"required_if=ConnectorType oidc"`
+	ClientSecret     string `json:"client_secret" validate:"required_if=ConnectorType oidc"`
 	ID               uint `json:"id,omitempty"`   // Optional
 	Name             string `json:"name,omitempty"` // Optional
 
+	SAMLConnectorParams
+	LDAPConnectorParams
+}
+
+// SAMLConnectorParams are the fields of saml connectors. Either metadata_url or sso_url with ca_data is required.
+type SAMLConnectorParams struct {
+	MetadataURL  string `json:"metadata_url,omitempty" validate:"omitempty,url"`
+	SSOURL       string `json:"sso_url,omitempty" validate:"omitempty,url"`
+	SSOIssuer    string `json:"sso_issuer,omitempty"`
+	CAData       string `json:"ca_data,omitempty"` // PEM encoded certificate of the identity provider
+	EntityIssuer string `json:"entity_issuer,omitempty"`
+	UsernameAttr string `json:"username_attr,omitempty"`
+	EmailAttr    string `json:"email_attr,omitempty"`
+	GroupsAttr   string `json:"groups_attr,omitempty"`
+	NameIDPolicy string `json:"name_id_policy_format,omitempty"`
+}
+
+// LDAPConnectorParams are the fields of ldap connectors
+type LDAPConnectorParams struct {
+	Host               string `json:"host,omitempty" validate:"omitempty,hostname_port"`
+	InsecureNoSSL      bool   `json:"insecure_no_ssl,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	StartTLS           bool   `json:"start_tls,omitempty"`
+	RootCAData         string `json:"root_ca_data,omitempty"` // PEM encoded certificate
+	BindDN             string `json:"bind_dn,omitempty"`
+	BindPassword       string `json:"bind_password,omitempty"`
+	UserSearchBaseDN   string `json:"user_search_base_dn,omitempty"`
+	UserSearchFilter   string `json:"user_search_filter,omitempty"`
+	UsernameAttribute  string `json:"username_attribute,omitempty"`
+	EmailAttribute     string `json:"email_attribute,omitempty"`
+	NameAttribute      string `json:"name_attribute,omitempty"`
+	GroupSearchBaseDN  string `json:"group_search_base_dn,omitempty"`
+	GroupSearchFilter  string `json:"group_search_filter,omitempty"`
+	GroupMemberAttr    string `json:"group_member_attribute,omitempty"`
+	GroupNameAttribute string `json:"group_name_attribute,omitempty"`
 }
 
 type OIDCConfig struct {
@@ -42,6 +80,8 @@ type GetConnectorsResponse struct {
 		Issuer   string `json:"issuer,omitempty"`
 		ClientID string `json:"client_id,omitempty"`
 		TenantID string `json:"tenant_id,omitempty"`
+		SSOURL   string `json:"sso_url,omitempty"`
+		Host     string `json:"host,omitempty"`
 		UserCount uint `json:"user_count"`
 		CreatedAt any `json:"created_at"`
 		LastUpdate any `json:"last_update"`
@@ -0,0 +1,24 @@
+package api
+
+import "github.com/opengovern/og-util/pkg/api"
+
+type RoleMappingRule struct {
+	Priority  int      `json:"priority" example:"1"`                                                               // Rules are evaluated by ascending priority, the first match wins
+	MatchType string   `json:"match_type" validate:"required,oneof=group email_domain" enums:"group,email_domain"` // Match the group claims or the email domain of the user
+	Pattern   string   `json:"pattern" validate:"required" example:"platform-*"`                                   // Case-insensitive glob pattern
+	Role      api.Role `json:"role" validate:"required" enums:"admin,editor,viewer" example:"viewer"`
+	Scopes    []string `json:"scopes,omitempty"` // Reserved for scoping the access of the mapped users
+}
+
+type ConnectorRoleMapping struct {
+	ConnectorID     string            `json:"connector_id"`
+	JITProvisioning bool              `json:"jit_provisioning"`                                   // Create users on their first login
+	DefaultRole     *api.Role         `json:"default_role,omitempty" enums:"admin,editor,viewer"` // Role of the users not matching any rule, users without a role are denied
+	Rules           []RoleMappingRule `json:"rules"`
+}
+
+type UpdateConnectorRoleMappingRequest struct {
+	JITProvisioning bool              `json:"jit_provisioning"`
+	DefaultRole     *api.Role         `json:"default_role,omitempty" enums:"admin,editor,viewer"`
+	Rules           []RoleMappingRule `json:"rules" validate:"dive"`
+}
@@ -14,7 +14,9 @@ import (
 	config2 "github.com/opengovern/og-util/pkg/config"
 	"github.com/opengovern/og-util/pkg/httpserver"
 	"github.com/opengovern/og-util/pkg/postgres"
+	"github.com/opengovern/opencomply/pkg/audit"
 	"github.com/opengovern/opencomply/services/auth/db"
+	metadataClient "github.com/opengovern/opencomply/services/metadata/client"
 
 	"crypto/rand"
 
@@ -32,6 +34,8 @@ var (
 	platformKeyEnabledStr = os.Getenv("PLATFORM_KEY_ENABLED")
 	platformPublicKeyStr  = os.Getenv("PLATFORM_PUBLIC_KEY")
 	platformPrivateKeyStr = os.Getenv("PLATFORM_PRIVATE_KEY")
+	metadataBaseURL       = os.Getenv("METADATA_BASE_URL")
+	esSinkBaseURL         = os.Getenv("ESSINK_BASEURL")
 )
 
 func Command() *cobra.Command {
@@ -222,6 +226,7 @@ func start(ctx context.Context) error {
 		db:                  adb,
 		updateLoginUserList: nil,
 		updateLogin:         make(chan User, 100000),
+		metadataClient:      metadataClient.NewMetadataServiceClient(metadataBaseURL),
 	}
 
 	go authServer.UpdateLastLoginLoop()
@@ -233,6 +238,7 @@ func start(ctx context.Context) error {
 			platformPrivateKey: platformPrivateKey,
 			db:                 adb,
 			authServer:         authServer,
+			auditRecorder:      audit.NewRecorder(logger, "auth", esSinkBaseURL),
 		}
 		errors <- fmt.Errorf("http server: %w", httpserver.RegisterAndStart(ctx, logger, httpServerAddress, &routes))
 	}()
@@ -21,6 +21,7 @@ func (db Database) Initialize() error {
 		&User{},
 		&Configuration{},
 		&Connector{},
+		&RoleMappingRule{},
 	)
 	if err != nil {
 		return err
@@ -263,6 +264,16 @@ func (db Database) UserPasswordUpdate(id uint) error {
 	return nil
 }
 
+func (db Database) UpdateUserRole(id uint, role api.Role) error {
+	tx := db.Orm.Model(&User{}).
+		Where("id = ? ", id).
+		Update("role", role)
+
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
 
 func (db Database) DisableUser(id uuid.UUID) error {
 	tx := db.Orm.Model(&User{}).
@@ -390,6 +401,12 @@ func (db Database) DeleteConnector(connectorID string) error {
 	if tx.Error != nil {
 		return tx.Error
 	}
+	tx = db.Orm.Unscoped().
+		Where("connector_id = ?", connectorID).
+		Delete(&RoleMappingRule{})
+	if tx.Error != nil {
+		return tx.Error
+	}
 	return nil
 }
 
@@ -408,6 +425,59 @@ func (db Database) GetConnectorByConnectorID(connectorID string) (*Connector, er
 	return &s, nil
 }
 
+func (db Database) IncreaseConnectorUserCount(connectorID string) error {
+	tx := db.Orm.Model(&Connector{}).
+		Where("connector_id = ?", connectorID).
+		Update("user_count", gorm.Expr("user_count + 1"))
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+func (db Database) ListRoleMappingRules(connectorID string) ([]RoleMappingRule, error) {
+	var s []RoleMappingRule
+	tx := db.Orm.Model(&RoleMappingRule{}).
+		Where("connector_id = ?", connectorID).
+		Order("priority asc, id asc").
+		Find(&s)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return s, nil
+}
+
+// UpdateConnectorRoleMapping replaces the role mapping rules and provisioning settings of the connector
+func (db Database) UpdateConnectorRoleMapping(connectorID string, jitProvisioning bool, defaultRole *api.Role, rules []RoleMappingRule) error {
+	return db.Orm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Connector{}).
+			Where("connector_id = ?", connectorID).
+			Updates(map[string]any{
+				"jit_provisioning": jitProvisioning,
+				"default_role":     defaultRole,
+				"last_update":      time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().
+			Where("connector_id = ?", connectorID).
+			Delete(&RoleMappingRule{}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(rules) == 0 {
+			return nil
+		}
+		for i := range rules {
+			rules[i].ConnectorID = connectorID
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
 // get Connector by connector type 
 
 func (db Database) GetConnectorByConnectorType(connectorType string) (*Connector, error) {
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"github.com/lib/pq"
 	"github.com/opengovern/og-util/pkg/api"
 	"gorm.io/gorm"
 	"time"
@@ -29,7 +30,27 @@ type Connector struct {
 	ConnectorType string
 	ConnectorSubType string	
 	LastUpdate 		time.Time
-	
+	// JITProvisioning creates the users of the connector on their first login
+	JITProvisioning bool `gorm:"default:false"`
+	// DefaultRole is given to the users of the connector not matching any role mapping rule
+	DefaultRole *api.Role
+}
+
+const (
+	RoleMappingMatchTypeGroup       = "group"
+	RoleMappingMatchTypeEmailDomain = "email_domain"
+)
+
+// RoleMappingRule maps the users of a connector with a matching group claim or email domain to a role.
+// Rules are evaluated by ascending priority and the first match wins.
+type RoleMappingRule struct {
+	gorm.Model
+	ConnectorID string `gorm:"index"`
+	Priority    int
+	MatchType   string
+	Pattern     string
+	Role        api.Role
+	Scopes      pq.StringArray `gorm:"type:text[]"`
 }
 
 type User struct {
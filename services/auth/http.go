@@ -19,6 +19,7 @@ import (
 	envoyauth "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
 	api2 "github.com/opengovern/og-util/pkg/api"
 	"github.com/opengovern/og-util/pkg/httpserver"
+	"github.com/opengovern/opencomply/pkg/audit"
 	"github.com/opengovern/opencomply/services/auth/utils"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
@@ -45,33 +46,37 @@ type httpRoutes struct {
 	platformPrivateKey *rsa.PrivateKey
 	db                 db.Database
 	authServer         *Server
+	auditRecorder      *audit.Recorder
 }
 
 func (r *httpRoutes) Register(e *echo.Echo) {
 	v1 := e.Group("/api/v1")
+	auditLog := r.auditRecorder.Middleware()
 	// VAlidate token
 	v1.GET("/check", r.Check)
 	// USERS
 	v1.GET("/users", httpserver.AuthorizeHandler(r.GetUsers, api2.EditorRole))                                      //checked
 	v1.GET("/user/:id", httpserver.AuthorizeHandler(r.GetUserDetails, api2.EditorRole))                             //checked
 	v1.GET("/me", httpserver.AuthorizeHandler(r.GetMe, api2.EditorRole))                                            //checked
-	v1.POST("/user", httpserver.AuthorizeHandler(r.CreateUser, api2.EditorRole))                                    //checked
-	v1.PUT("/user", httpserver.AuthorizeHandler(r.UpdateUser, api2.EditorRole))                                     //checked
+	v1.POST("/user", httpserver.AuthorizeHandler(r.CreateUser, api2.EditorRole), auditLog)                          //checked
+	v1.PUT("/user", httpserver.AuthorizeHandler(r.UpdateUser, api2.EditorRole), auditLog)                           //checked
 	v1.GET("/user/password/check", httpserver.AuthorizeHandler(r.CheckUserPasswordChangeRequired, api2.ViewerRole)) //checked
-	v1.POST("/user/password/reset", httpserver.AuthorizeHandler(r.ResetUserPassword, api2.ViewerRole))              //checked
-	v1.DELETE("/user/:id", httpserver.AuthorizeHandler(r.DeleteUser, api2.AdminRole))                               //checked
+	v1.POST("/user/password/reset", httpserver.AuthorizeHandler(r.ResetUserPassword, api2.ViewerRole), auditLog)    //checked
+	v1.DELETE("/user/:id", httpserver.AuthorizeHandler(r.DeleteUser, api2.AdminRole), auditLog)                     //checked
 	// API KEYS
-	v1.POST("/keys", httpserver.AuthorizeHandler(r.CreateAPIKey, api2.AdminRole)) //checked
-	v1.GET("/keys", httpserver.AuthorizeHandler(r.ListAPIKeys, api2.AdminRole))   //checked
-	v1.DELETE("/key/:id", httpserver.AuthorizeHandler(r.DeleteAPIKey, api2.AdminRole))
-	v1.PUT("/key/:id", httpserver.AuthorizeHandler(r.EditAPIKey, api2.AdminRole))
+	v1.POST("/keys", httpserver.AuthorizeHandler(r.CreateAPIKey, api2.AdminRole), auditLog) //checked
+	v1.GET("/keys", httpserver.AuthorizeHandler(r.ListAPIKeys, api2.AdminRole))             //checked
+	v1.DELETE("/key/:id", httpserver.AuthorizeHandler(r.DeleteAPIKey, api2.AdminRole), auditLog)
+	v1.PUT("/key/:id", httpserver.AuthorizeHandler(r.EditAPIKey, api2.AdminRole), auditLog)
 	// connectors
 	v1.GET("/connectors", httpserver.AuthorizeHandler(r.GetConnectors, api2.AdminRole))
 	v1.GET("/connectors/supported-connector-types", httpserver.AuthorizeHandler(r.GetSupportedType, api2.AdminRole))
 	v1.GET("/connector/:type", httpserver.AuthorizeHandler(r.GetConnectors, api2.AdminRole))
-	v1.POST("/connector", httpserver.AuthorizeHandler(r.CreateConnector, api2.AdminRole))
-	v1.PUT("/connector", httpserver.AuthorizeHandler(r.UpdateConnector, api2.AdminRole))
-	v1.DELETE("/connector/:id", httpserver.AuthorizeHandler(r.DeleteConnector, api2.AdminRole))
+	v1.POST("/connector", httpserver.AuthorizeHandler(r.CreateConnector, api2.AdminRole), auditLog)
+	v1.PUT("/connector", httpserver.AuthorizeHandler(r.UpdateConnector, api2.AdminRole), auditLog)
+	v1.DELETE("/connector/:id", httpserver.AuthorizeHandler(r.DeleteConnector, api2.AdminRole), auditLog)
+	v1.GET("/connector/:id/role-mapping", httpserver.AuthorizeHandler(r.GetConnectorRoleMapping, api2.AdminRole))
+	v1.PUT("/connector/:id/role-mapping", httpserver.AuthorizeHandler(r.UpdateConnectorRoleMapping, api2.AdminRole), auditLog)
 
 }
 
@@ -600,6 +605,9 @@ func (r *httpRoutes) UpdateUser(ctx echo.Context) error {
 	if user == nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "user not found")
 	}
+	audit.SetAction(ctx, "user.update")
+	audit.SetTarget(ctx, "user", user.Email)
+	audit.SetBefore(ctx, user)
 
 	if req.Password != nil && req.ConnectorId == "local" {
 		dexClient, err := newDexClient(dexGrpcAddress)
@@ -684,6 +692,11 @@ func (r *httpRoutes) DeleteUser(ctx echo.Context) error {
 	if id == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "id is required")
 	}
+	if user, err := r.db.GetUser(id); err == nil && user != nil {
+		audit.SetAction(ctx, "user.delete")
+		audit.SetTarget(ctx, "user", user.Email)
+		audit.SetBefore(ctx, user)
+	}
 
 	err := r.DoDeleteUser(id)
 	if err != nil {
@@ -930,6 +943,24 @@ func (r *httpRoutes) GetConnectors(ctx echo.Context) error {
 				// Note: Omitting ClientSecret for security reasons
 			}
 		}
+		switch strings.ToLower(connector.Type) {
+		case "saml":
+			var config utils.SAMLConfig
+			if err := json.Unmarshal(connector.Config, &config); err != nil {
+				r.logger.Error("Failed to unmarshal SAML config for connector", zap.Error(err))
+			} else {
+				info.Issuer = config.SSOIssuer
+				info.SSOURL = config.SSOURL
+			}
+		case "ldap":
+			var config utils.LDAPConfig
+			if err := json.Unmarshal(connector.Config, &config); err != nil {
+				r.logger.Error("Failed to unmarshal LDAP config for connector", zap.Error(err))
+			} else {
+				// Note: Omitting bind password for security reasons
+				info.Host = config.Host
+			}
+		}
 
 		resp = append(resp, info)
 	}
@@ -949,20 +980,22 @@ func (r *httpRoutes) GetConnectors(ctx echo.Context) error {
 func (r *httpRoutes) GetSupportedType(ctx echo.Context) error {
 	var connectors []api.GetSupportedConnectorTypeResponse
 
-	subTypes := utils.SupportedConnectors["oidc"]
-	subTypesNames := utils.SupportedConnectorsNames["oidc"]
+	for _, connectorType := range utils.SupportedConnectorTypes {
+		subTypes := utils.SupportedConnectors[connectorType]
+		subTypesNames := utils.SupportedConnectorsNames[connectorType]
 
-	var types []api.ConnectorSubTypes
-	for i, key := range subTypes {
-		types = append(types, api.ConnectorSubTypes{
-			ID:   key,
-			Name: subTypesNames[i],
+		var types []api.ConnectorSubTypes
+		for i, key := range subTypes {
+			types = append(types, api.ConnectorSubTypes{
+				ID:   key,
+				Name: subTypesNames[i],
+			})
+		}
+		connectors = append(connectors, api.GetSupportedConnectorTypeResponse{
+			ConnectorType: connectorType,
+			SubTypes:      types,
 		})
 	}
-	connectors = append(connectors, api.GetSupportedConnectorTypeResponse{
-		ConnectorType: "oidc",
-		SubTypes:      types,
-	})
 
 	return ctx.JSON(http.StatusOK, connectors)
 
@@ -971,7 +1004,7 @@ func (r *httpRoutes) GetSupportedType(ctx echo.Context) error {
 // CreateConnector godoc
 //
 //	@Summary		Create Connector
-//	@Description	Creates new OIDC connector.
+//	@Description	Creates new OIDC, SAML or LDAP connector.
 //	@Security		BearerToken
 //	@Tags			connectors
 //	@Produce		json
@@ -1005,8 +1038,26 @@ func (r *httpRoutes) CreateConnector(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 
 	}
-	switch connectorSubTypeLower {
-	case "general":
+	switch {
+	case connectorTypeLower == "saml":
+		// Required: metadata_url, or sso_url and ca_data. Validated while creating the dex config
+		if strings.TrimSpace(req.ID) == "" {
+			req.ID = connectorSubTypeLower + "-saml"
+		}
+		if strings.TrimSpace(req.Name) == "" {
+			req.Name = "SAML"
+		}
+
+	case connectorTypeLower == "ldap":
+		// Required: host, user_search_base_dn. Validated while creating the dex config
+		if strings.TrimSpace(req.ID) == "" {
+			req.ID = connectorSubTypeLower + "-ldap"
+		}
+		if strings.TrimSpace(req.Name) == "" {
+			req.Name = "LDAP"
+		}
+
+	case connectorSubTypeLower == "general":
 		// Required: issuer, client_id, client_secret
 		if strings.TrimSpace(req.Issuer) == "" {
 			r.logger.Warn("Missing 'issuer' for 'general' OIDC connector")
@@ -1024,7 +1075,7 @@ func (r *httpRoutes) CreateConnector(ctx echo.Context) error {
 			req.Name = "General OIDC"
 		}
 
-	case "entraid":
+	case connectorSubTypeLower == "entraid":
 		// Required: tenant_id, client_id, client_secret
 		if strings.TrimSpace(req.TenantID) == "" {
 			err := "Missing 'tenant_id' for 'entraid' OIDC connector"
@@ -1044,7 +1095,7 @@ func (r *httpRoutes) CreateConnector(ctx echo.Context) error {
 
 		}
 
-	case "google-workspace":
+	case connectorSubTypeLower == "google-workspace":
 		// Required: client_id, client_secret
 
 		// Set default id and name if not provided
@@ -1058,14 +1109,16 @@ func (r *httpRoutes) CreateConnector(ctx echo.Context) error {
 		}
 	}
 	dexRequest := utils.CreateConnectorRequest{
-		ConnectorType:    req.ConnectorType,
-		ConnectorSubType: req.ConnectorSubType,
-		Issuer:           req.Issuer,
-		TenantID:         req.TenantID,
-		ClientID:         req.ClientID,
-		ClientSecret:     req.ClientSecret,
-		ID:               req.ID,
-		Name:             req.Name,
+		ConnectorType:       req.ConnectorType,
+		ConnectorSubType:    req.ConnectorSubType,
+		Issuer:              req.Issuer,
+		TenantID:            req.TenantID,
+		ClientID:            req.ClientID,
+		ClientSecret:        req.ClientSecret,
+		ID:                  req.ID,
+		Name:                req.Name,
+		SAMLConnectorParams: utils.SAMLConnectorParams(req.SAMLConnectorParams),
+		LDAPConnectorParams: utils.LDAPConnectorParams(req.LDAPConnectorParams),
 	}
 	dexreq, err := creator(dexRequest)
 	if err != nil {
@@ -1108,7 +1161,7 @@ func (r *httpRoutes) CreateConnector(ctx echo.Context) error {
 // UpdateConnector godoc
 //
 //	@Summary		Update Connector
-//	@Description	Update new OIDC connector.
+//	@Description	Update OIDC, SAML or LDAP connector.
 //	@Security		BearerToken
 //	@Tags			connectors
 //	@Produce		json
@@ -1131,8 +1184,11 @@ func (r *httpRoutes) UpdateConnector(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 
 	}
-	switch req.ConnectorSubType {
-	case "general":
+	switch {
+	case req.ConnectorType == "saml", req.ConnectorType == "ldap":
+		// validated while creating the dex config
+
+	case req.ConnectorSubType == "general":
 		// Required: issuer, client_id, client_secret
 		if strings.TrimSpace(req.Issuer) == "" {
 			err := "Missing 'issuer' for 'general' OIDC connector update"
@@ -1142,7 +1198,7 @@ func (r *httpRoutes) UpdateConnector(ctx echo.Context) error {
 		}
 		// client_id and client_secret are already validated as required in the struct
 
-	case "entraid":
+	case req.ConnectorSubType == "entraid":
 		// Required: tenant_id, client_id, client_secret
 		if strings.TrimSpace(req.TenantID) == "" {
 			err := "Missing 'tenant_id' for 'entraid' OIDC connector update"
@@ -1152,7 +1208,7 @@ func (r *httpRoutes) UpdateConnector(ctx echo.Context) error {
 		}
 		// client_id and client_secret are already validated as required in the struct
 
-	case "google-workspace":
+	case req.ConnectorSubType == "google-workspace":
 		// Required: client_id, client_secret
 		// No additional fields needed
 		// client_id and client_secret are already validated as required in the struct
@@ -1164,16 +1220,23 @@ func (r *httpRoutes) UpdateConnector(ctx echo.Context) error {
 
 	}
 	dexRequest := utils.UpdateConnectorRequest{
-		ConnectorType:    req.ConnectorType,
-		ConnectorSubType: req.ConnectorSubType,
-		Issuer:           req.Issuer,
-		TenantID:         req.TenantID,
-		ClientID:         req.ClientID,
-		ClientSecret:     req.ClientSecret,
-		ID:               req.ConnectorID,
+		ConnectorType:       req.ConnectorType,
+		ConnectorSubType:    req.ConnectorSubType,
+		Issuer:              req.Issuer,
+		TenantID:            req.TenantID,
+		ClientID:            req.ClientID,
+		ClientSecret:        req.ClientSecret,
+		ID:                  req.ConnectorID,
+		Name:                req.Name,
+		SAMLConnectorParams: utils.SAMLConnectorParams(req.SAMLConnectorParams),
+		LDAPConnectorParams: utils.LDAPConnectorParams(req.LDAPConnectorParams),
+	}
+
+	updater := utils.GetConnectorUpdater(strings.ToLower(req.ConnectorType))
+	if updater == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "connector type is not supported")
 	}
-
-	dexreq, err := utils.UpdateOIDCConnector(dexRequest)
+	dexreq, err := updater(dexRequest)
 	if err != nil {
 		r.logger.Error("Error on Creating dex request", zap.Error(err))
 		return echo.NewHTTPError(http.StatusBadRequest, err)
@@ -1195,6 +1258,11 @@ func (r *httpRoutes) UpdateConnector(ctx echo.Context) error {
 	if res.NotFound {
 		return echo.NewHTTPError(http.StatusNotFound, "connector not found")
 	}
+	if before, err := r.db.GetConnector(strconv.FormatUint(uint64(req.ID), 10)); err == nil && before != nil {
+		audit.SetAction(ctx, "connector.update")
+		audit.SetTarget(ctx, "connector", before.ConnectorID)
+		audit.SetBefore(ctx, before)
+	}
 	err = r.db.UpdateConnector(&db.Connector{
 		Model: gorm.Model{
 			ID: req.ID,
@@ -1254,3 +1322,124 @@ func (r *httpRoutes) DeleteConnector(ctx echo.Context) error {
 
 	return ctx.NoContent(http.StatusAccepted)
 }
+
+// GetConnectorRoleMapping godoc
+//
+//	@Summary		Get connector role mapping
+//	@Description	Returns the role mapping rules and provisioning settings of the connector
+//	@Security		BearerToken
+//	@Tags			connectors
+//	@Produce		json
+//	@Param			id	path		string	true	"Connector ID"
+//	@Success		200	{object}	api.ConnectorRoleMapping
+//	@Router			/auth/api/v1/connector/{id}/role-mapping [get]
+func (r *httpRoutes) GetConnectorRoleMapping(ctx echo.Context) error {
+	connectorID := ctx.Param("id")
+
+	connector, err := r.db.GetConnectorByConnectorID(connectorID)
+	if err != nil {
+		r.logger.Error("failed to get connector", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get connector")
+	}
+	if connector == nil || connector.ID == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "connector not found")
+	}
+
+	rules, err := r.db.ListRoleMappingRules(connectorID)
+	if err != nil {
+		r.logger.Error("failed to list role mapping rules", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list role mapping rules")
+	}
+
+	resp := api.ConnectorRoleMapping{
+		ConnectorID:     connector.ConnectorID,
+		JITProvisioning: connector.JITProvisioning,
+		DefaultRole:     connector.DefaultRole,
+		Rules:           make([]api.RoleMappingRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, api.RoleMappingRule{
+			Priority:  rule.Priority,
+			MatchType: rule.MatchType,
+			Pattern:   rule.Pattern,
+			Role:      rule.Role,
+			Scopes:    rule.Scopes,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// UpdateConnectorRoleMapping godoc
+//
+//	@Summary		Update connector role mapping
+//	@Description	Replaces the role mapping rules and provisioning settings of the connector.
+//	@Description	Roles of the connector users are re-synced from their group claims and email domain on every login.
+//	@Security		BearerToken
+//	@Tags			connectors
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string									true	"Connector ID"
+//	@Param			request	body	api.UpdateConnectorRoleMappingRequest	true	"Role mapping"
+//	@Success		200		{object}	api.ConnectorRoleMapping
+//	@Router			/auth/api/v1/connector/{id}/role-mapping [put]
+func (r *httpRoutes) UpdateConnectorRoleMapping(ctx echo.Context) error {
+	connectorID := ctx.Param("id")
+	audit.SetAction(ctx, "connector.role_mapping.update")
+	audit.SetTarget(ctx, "connector", connectorID)
+
+	var req api.UpdateConnectorRoleMappingRequest
+	if err := bindValidate(ctx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if connectorID == "local" {
+		return echo.NewHTTPError(http.StatusBadRequest, "role mapping is not supported for local users")
+	}
+	connector, err := r.db.GetConnectorByConnectorID(connectorID)
+	if err != nil {
+		r.logger.Error("failed to get connector", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get connector")
+	}
+	if connector == nil || connector.ID == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "connector not found")
+	}
+
+	if req.DefaultRole != nil {
+		if err := utils.ValidateRole(*req.DefaultRole); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+	rules := make([]db.RoleMappingRule, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		if err := utils.ValidateRoleMappingRule(rule.MatchType, rule.Pattern, rule.Role); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		rules = append(rules, db.RoleMappingRule{
+			Priority:  rule.Priority,
+			MatchType: rule.MatchType,
+			Pattern:   strings.TrimSpace(rule.Pattern),
+			Role:      rule.Role,
+			Scopes:    rule.Scopes,
+		})
+	}
+	if req.JITProvisioning && req.DefaultRole == nil && len(rules) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "jit provisioning requires a default role or role mapping rules")
+	}
+
+	if before, err := r.db.ListRoleMappingRules(connectorID); err == nil {
+		audit.SetBefore(ctx, map[string]any{
+			"jit_provisioning": connector.JITProvisioning,
+			"default_role":     connector.DefaultRole,
+			"rules":            before,
+		})
+	}
+
+	err = r.db.UpdateConnectorRoleMapping(connectorID, req.JITProvisioning, req.DefaultRole, rules)
+	if err != nil {
+		r.logger.Error("failed to update connector role mapping", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update connector role mapping")
+	}
+
+	return r.GetConnectorRoleMapping(ctx)
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/services/auth/db"
+	"github.com/opengovern/opencomply/services/auth/utils"
+	metadataClient "github.com/opengovern/opencomply/services/metadata/client"
+	"github.com/opengovern/opencomply/services/metadata/models"
+	"go.uber.org/zap"
+)
+
+const (
+	localConnectorID              = "local"
+	allowedEmailDomainsRefreshTTL = time.Minute
+)
+
+type syncedLogin struct {
+	expiresAt time.Time
+}
+
+// syncIdentityProviderUser applies the allowed email domains and the role mapping of the connector the user logged
+// in with. Users are created on their first login if the connector has just-in-time provisioning enabled and their
+// role is re-synced on every login, so users losing their groups in the identity provider lose access here too.
+// Users of connectors without role mapping keep the role given to them by an admin.
+// The sync runs once per issued token, later requests with the same token skip it.
+func (s *Server) syncIdentityProviderUser(claim *userClaim) error {
+	if claim.ConnectorID == "" || claim.ConnectorID == localConnectorID {
+		return nil
+	}
+	if s.isLoginSynced(claim.LoginKey) {
+		return nil
+	}
+	if err := s.syncLogin(claim); err != nil {
+		return err
+	}
+	s.markLoginSynced(claim.LoginKey, claim.TokenExpiry)
+	return nil
+}
+
+func (s *Server) isLoginSynced(key string) bool {
+	if key == "" {
+		return false
+	}
+	s.syncedLoginsMu.Lock()
+	defer s.syncedLoginsMu.Unlock()
+
+	login, ok := s.syncedLogins[key]
+	return ok && time.Now().Before(login.expiresAt)
+}
+
+// markLoginSynced records a successful sync of the login until its token expires, expired logins are dropped
+func (s *Server) markLoginSynced(key string, expiresAt time.Time) {
+	if key == "" || expiresAt.IsZero() {
+		return
+	}
+	s.syncedLoginsMu.Lock()
+	defer s.syncedLoginsMu.Unlock()
+
+	now := time.Now()
+	if s.syncedLogins == nil {
+		s.syncedLogins = make(map[string]syncedLogin)
+	}
+	for k, login := range s.syncedLogins {
+		if !now.Before(login.expiresAt) {
+			delete(s.syncedLogins, k)
+		}
+	}
+	s.syncedLogins[key] = syncedLogin{expiresAt: expiresAt}
+}
+
+func (s *Server) syncLogin(claim *userClaim) error {
+	allowed, err := s.isEmailDomainAllowed(claim.Email, claim.EmailVerified)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.New("email domain is not allowed or the email is not verified")
+	}
+
+	connector, err := s.db.GetConnectorByConnectorID(claim.ConnectorID)
+	if err != nil {
+		return fmt.Errorf("failed to get connector: %w", err)
+	}
+	if connector == nil || connector.ID == 0 {
+		return nil
+	}
+	rules, err := s.db.ListRoleMappingRules(claim.ConnectorID)
+	if err != nil {
+		return fmt.Errorf("failed to list role mapping rules: %w", err)
+	}
+
+	user, err := s.db.GetUserByEmail(claim.Email)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	// email domain rules only match verified emails, the rules can't match an empty email
+	mappedEmail := claim.Email
+	if !claim.EmailVerified {
+		mappedEmail = ""
+	}
+	role := utils.ResolveConnectorRole(*connector, rules, mappedEmail, claim.Groups)
+
+	if user == nil || user.ID == 0 {
+		if !connector.JITProvisioning {
+			return nil
+		}
+		// the email is the identity of the provisioned user, an unverified one could claim someone else's
+		if !claim.EmailVerified {
+			return errors.New("users with unverified emails are not provisioned")
+		}
+		if role == nil {
+			return errors.New("no role mapping rule matched the user")
+		}
+		user = &db.User{
+			Email:                 claim.Email,
+			EmailVerified:         claim.EmailVerified,
+			FullName:              claim.Name,
+			Role:                  *role,
+			ConnectorId:           claim.ConnectorID,
+			ExternalId:            fmt.Sprintf("%s|%s", claim.ConnectorID, claim.Email),
+			Username:              claim.Email,
+			RequirePasswordChange: false,
+			IsActive:              true,
+		}
+		if err := s.db.CreateUser(user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		if err := s.db.IncreaseConnectorUserCount(claim.ConnectorID); err != nil {
+			s.logger.Error("failed to increase connector user count", zap.String("connectorID", claim.ConnectorID), zap.Error(err))
+		}
+		s.logger.Info("provisioned user on first login", zap.String("email", claim.Email),
+			zap.String("connectorID", claim.ConnectorID), zap.String("role", string(*role)))
+		return nil
+	}
+
+	// only the users assigned to the connector are managed by its role mapping
+	if user.ConnectorId != claim.ConnectorID || !user.IsActive || !utils.HasRoleMapping(*connector, rules) {
+		return nil
+	}
+	if role == nil {
+		return errors.New("no role mapping rule matched the user")
+	}
+	if user.Role != *role {
+		if err := s.db.UpdateUserRole(user.ID, *role); err != nil {
+			return fmt.Errorf("failed to update user role: %w", err)
+		}
+		s.logger.Info("synced user role from identity provider", zap.String("email", claim.Email),
+			zap.String("connectorID", claim.ConnectorID), zap.String("from", string(user.Role)), zap.String("to", string(*role)))
+	}
+	return nil
+}
+
+// isEmailDomainAllowed checks the email against the allowed email domains of the platform metadata. The domains are
+// cached for a minute, if they can't be fetched the last known domains are used and logins are denied without them.
+// The domain of an unverified email can't be trusted, they are only allowed if no domains are configured.
+func (s *Server) isEmailDomainAllowed(email string, emailVerified bool) (bool, error) {
+	s.allowedEmailDomainsMu.Lock()
+	defer s.allowedEmailDomainsMu.Unlock()
+
+	if s.allowedEmailDomainsFetchedAt.IsZero() || time.Since(s.allowedEmailDomainsFetchedAt) > allowedEmailDomainsRefreshTTL {
+		domains, err := s.fetchAllowedEmailDomains()
+		if err != nil {
+			s.logger.Error("failed to get allowed email domains", zap.Error(err))
+			if s.allowedEmailDomainsFetchedAt.IsZero() {
+				return false, err
+			}
+		} else {
+			s.allowedEmailDomains = domains
+			s.allowedEmailDomainsFetchedAt = time.Now()
+		}
+	}
+
+	if len(s.allowedEmailDomains) > 0 && !emailVerified {
+		return false, nil
+	}
+	return utils.IsEmailDomainAllowed(email, s.allowedEmailDomains), nil
+}
+
+func (s *Server) fetchAllowedEmailDomains() ([]string, error) {
+	if s.metadataClient == nil {
+		return nil, nil
+	}
+	value, err := s.metadataClient.GetConfigMetadata(&httpclient.Context{UserRole: api.AdminRole}, models.MetadataKeyAllowedEmailDomains)
+	if err != nil {
+		if errors.Is(err, metadataClient.ErrConfigNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	raw, ok := value.GetValue().(string)
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	var domains []string
+	if err := json.Unmarshal([]byte(raw), &domains); err != nil {
+		return nil, fmt.Errorf("failed to parse allowed email domains: %w", err)
+	}
+	return domains, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -21,6 +22,7 @@ import (
 	"github.com/opengovern/og-util/pkg/httpserver"
 	"github.com/opengovern/opencomply/services/auth/db"
 	"github.com/opengovern/opencomply/services/auth/utils"
+	metadataClient "github.com/opengovern/opencomply/services/metadata/client"
 	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/status"
 )
@@ -42,6 +44,14 @@ type Server struct {
 	db                  db.Database
 	updateLoginUserList []User
 	updateLogin         chan User
+	metadataClient      metadataClient.MetadataServiceClient
+
+	allowedEmailDomainsMu        sync.Mutex
+	allowedEmailDomains          []string
+	allowedEmailDomainsFetchedAt time.Time
+
+	syncedLoginsMu sync.Mutex
+	syncedLogins   map[string]syncedLogin
 }
 
 type DexClaims struct {
@@ -175,6 +185,15 @@ func (s *Server) Check(ctx context.Context, req *envoyauth.CheckRequest) (*envoy
 		return unAuth, nil
 	}
 
+	if err := s.syncIdentityProviderUser(user); err != nil {
+		s.logger.Warn("denied access due to identity provider user sync",
+			zap.String("reqId", httpRequest.Id),
+			zap.String("email", user.Email),
+			zap.String("connectorID", user.ConnectorID),
+			zap.Error(err))
+		return unAuth, nil
+	}
+
 	theUser, err := utils.GetUserByEmail(user.Email, s.db)
 	if err != nil {
 		s.logger.Warn("failed to get user",
@@ -251,6 +270,15 @@ type userClaim struct {
 	ConnectionIDs  map[string][]string
 	ExternalUserID string `json:"sub"`
 	EmailVerified  bool
+
+	// ConnectorID, Groups and Name are only read from dex tokens
+	ConnectorID string   `json:"-"`
+	Groups      []string `json:"-"`
+	Name        string   `json:"-"`
+	// LoginKey identifies the login the dex token was issued for by its subject and issue time, TokenExpiry is
+	// when the token expires
+	LoginKey    string    `json:"-"`
+	TokenExpiry time.Time `json:"-"`
 }
 
 func (u userClaim) Valid() error {
@@ -286,9 +314,15 @@ func (s *Server) Verify(ctx context.Context, authToken string) (*userClaim, erro
 		}
 		s.logger.Info("dex verifier claims", zap.Any("claims", claimsMap))
 
+		connectorID, _ := claimsMap.FederatedClaims["connector_id"].(string)
 		return &userClaim{
 			Email:         claimsMap.Email,
 			EmailVerified: claimsMap.EmailVerified,
+			ConnectorID:   connectorID,
+			Groups:        claimsMap.Groups,
+			Name:          claimsMap.Name,
+			LoginKey:      fmt.Sprintf("%s|%d", dv.Subject, dv.IssuedAt.Unix()),
+			TokenExpiry:   dv.Expiry,
 		}, nil
 	} else {
 		s.logger.Error("dex verifier verify error", zap.Error(err))
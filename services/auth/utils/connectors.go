@@ -2,12 +2,15 @@ package utils
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	dexapi "github.com/dexidp/dex/api/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,25 +19,31 @@ import (
 )
 type CreateConnectorRequest struct {
 
-	ConnectorType    string `json:"connector_type" validate:"required,oneof=oidc"`                                  // 'oidc' is supported for now
-	ConnectorSubType string `json:"connector_sub_type" validate:"omitempty,oneof=general google-workspace entraid"` // Optional sub-type
+	ConnectorType    string `json:"connector_type" validate:"required,oneof=oidc saml ldap"`
+	ConnectorSubType string `json:"connector_sub_type" validate:"omitempty,oneof=general google-workspace entraid okta adfs active-directory"` // Optional sub-type
 	Issuer           string `json:"issuer,omitempty" validate:"omitempty,url"`
 	TenantID         string `json:"tenant_id,omitempty" validate:"omitempty,uuid"`
-	ClientID         string `json:"client_id" validate:"required"`
-	ClientSecret     string `json:"client_secret" validate:"required"`
+	ClientID         string `json:"client_id" validate:"required_if=ConnectorType oidc"`
+	ClientSecret     string `json:"client_secret" validate:"required_if=ConnectorType oidc"`
 	ID               string `json:"id,omitempty"`   // Optional
 	Name             string `json:"name,omitempty"` // Optional
+
+	SAMLConnectorParams
+	LDAPConnectorParams
 }
 type UpdateConnectorRequest struct {
 	ConnectorID 	string `json:"connector_id" validate:"required"`
-	ConnectorType    string `json:"connector_type" validate:"required,oneof=oidc"`                                  // 'oidc' is supported for now
-	ConnectorSubType string `json:"connector_sub_type" validate:"omitempty,oneof=general google-workspace entraid"` // Optional sub-type
+	ConnectorType    string `json:"connector_type" validate:"required,oneof=oidc saml ldap"`
+	ConnectorSubType string `json:"connector_sub_type" validate:"omitempty,oneof=general google-workspace entraid okta adfs active-directory"` // Optional sub-type
 	Issuer           string `json:"issuer,omitempty" validate:"omitempty,url"`
 	TenantID         string `json:"tenant_id,omitempty" validate:"omitempty,uuid"`
-	ClientID         string `json:"client_id" validate:"required"`
-	ClientSecret     string `json:"client_secret" validate:"required"`
+	ClientID         string `json:"client_id" validate:"required_if=ConnectorType oidc"`
+	ClientSecret     string `json:"client_secret" validate:"required_if=ConnectorType oidc"`
 	ID               string `json:"id,omitempty"`   // Optional
 	Name             string `json:"name,omitempty"` // Optional
+
+	SAMLConnectorParams
+	LDAPConnectorParams
 }
 
 type OIDCConfig struct {
@@ -53,18 +62,29 @@ type OIDCConfig struct {
 }
 
 type ConnectorCreator func( params CreateConnectorRequest) (*dexapi.CreateConnectorReq, error)
+type ConnectorUpdater func(params UpdateConnectorRequest) (*dexapi.UpdateConnectorReq, error)
 
 var  connectorCreators = map[string]ConnectorCreator{
 	"oidc": CreateOIDCConnector,
-	// Future connector types can be added here, e.g., "saml": (*DexClient).CreateSAMLConnector
+	"saml": CreateSAMLConnector,
+	"ldap": CreateLDAPConnector,
 }
+var connectorUpdaters = map[string]ConnectorUpdater{
+	"oidc": UpdateOIDCConnector,
+	"saml": UpdateSAMLConnector,
+	"ldap": UpdateLDAPConnector,
+}
+// SupportedConnectorTypes keeps the order connector types are listed in
+var SupportedConnectorTypes = []string{"oidc", "saml", "ldap"}
 var SupportedConnectors = map[string][]string{
 	"oidc": {"general", "google-workspace", "entraid"},
-	// Add more connector types and their sub-types here as needed.
+	"saml": {"general", "okta", "adfs"},
+	"ldap": {"general", "active-directory"},
 }
 var SupportedConnectorsNames = map[string][]string{
 	"oidc": {"General OIDC", "Google Workspaces", "AzureAD/EntraID"},
-
+	"saml": {"General SAML 2.0", "Okta", "ADFS"},
+	"ldap": {"General LDAP", "Active Directory"},
 }
 
 func  CreateOIDCConnector(params CreateConnectorRequest) (*dexapi.CreateConnectorReq, error) {
@@ -264,6 +284,9 @@ func IsSupportedSubType(connectorType, subType string) bool {
 func GetConnectorCreator(connectorType string) ConnectorCreator {
 	return connectorCreators[connectorType]
 }
+func GetConnectorUpdater(connectorType string) ConnectorUpdater {
+	return connectorUpdaters[connectorType]
+}
 func GetSupportedConnectors(connectorType string) ([]string ) {
 	return SupportedConnectors[connectorType]
 }
@@ -302,4 +325,34 @@ func RestartDexPod() error {
 	return nil
 
 
-}
\ No newline at end of file
+}
+// validateCertificatesPEM makes sure data holds at least one PEM encoded certificate and none of them has expired
+func validateCertificatesPEM(data []byte) error {
+	var count int
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		if time.Now().After(cert.NotAfter) {
+			return fmt.Errorf("certificate %s expired at %s", cert.Subject.String(), cert.NotAfter.Format(time.RFC3339))
+		}
+		count++
+	}
+	if count == 0 {
+		return fmt.Errorf("no PEM encoded certificate found")
+	}
+	return nil
+}
+
+func dexCallbackURL() string {
+	return strings.Split(os.Getenv("DEX_CALLBACK_URL"), ",")[0]
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	dexapi "github.com/dexidp/dex/api/v2"
+)
+
+type LDAPConnectorParams struct {
+	Host               string `json:"host,omitempty" validate:"omitempty,hostname_port"`
+	InsecureNoSSL      bool   `json:"insecure_no_ssl,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	StartTLS           bool   `json:"start_tls,omitempty"`
+	RootCAData         string `json:"root_ca_data,omitempty"` // PEM encoded certificate
+	BindDN             string `json:"bind_dn,omitempty"`
+	BindPassword       string `json:"bind_password,omitempty"`
+	UserSearchBaseDN   string `json:"user_search_base_dn,omitempty"`
+	UserSearchFilter   string `json:"user_search_filter,omitempty"`
+	UsernameAttribute  string `json:"username_attribute,omitempty"`
+	EmailAttribute     string `json:"email_attribute,omitempty"`
+	NameAttribute      string `json:"name_attribute,omitempty"`
+	GroupSearchBaseDN  string `json:"group_search_base_dn,omitempty"`
+	GroupSearchFilter  string `json:"group_search_filter,omitempty"`
+	GroupMemberAttr    string `json:"group_member_attribute,omitempty"`
+	GroupNameAttribute string `json:"group_name_attribute,omitempty"`
+}
+
+// LDAPConfig is the config of the dex ldap connector
+type LDAPConfig struct {
+	Host               string `json:"host"`
+	InsecureNoSSL      bool   `json:"insecureNoSSL"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	StartTLS           bool   `json:"startTLS"`
+	RootCAData         []byte `json:"rootCAData,omitempty"`
+	BindDN             string `json:"bindDN,omitempty"`
+	BindPW             string `json:"bindPW,omitempty"`
+	UsernamePrompt     string `json:"usernamePrompt,omitempty"`
+
+	UserSearch  LDAPUserSearch   `json:"userSearch"`
+	GroupSearch *LDAPGroupSearch `json:"groupSearch,omitempty"`
+}
+
+type LDAPUserSearch struct {
+	BaseDN    string `json:"baseDN"`
+	Filter    string `json:"filter,omitempty"`
+	Username  string `json:"username"`
+	IDAttr    string `json:"idAttr"`
+	EmailAttr string `json:"emailAttr"`
+	NameAttr  string `json:"nameAttr,omitempty"`
+}
+
+type LDAPGroupSearch struct {
+	BaseDN       string            `json:"baseDN"`
+	Filter       string            `json:"filter,omitempty"`
+	UserMatchers []LDAPUserMatcher `json:"userMatchers"`
+	NameAttr     string            `json:"nameAttr"`
+}
+
+type LDAPUserMatcher struct {
+	UserAttr  string `json:"userAttr"`
+	GroupAttr string `json:"groupAttr"`
+}
+
+func CreateLDAPConnector(params CreateConnectorRequest) (*dexapi.CreateConnectorReq, error) {
+	configBytes, err := buildLDAPConfig(params.ConnectorSubType, params.LDAPConnectorParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dexapi.CreateConnectorReq{
+		Connector: &dexapi.Connector{
+			Id:     params.ID,
+			Type:   "ldap",
+			Name:   params.Name,
+			Config: configBytes,
+		},
+	}, nil
+}
+
+func UpdateLDAPConnector(params UpdateConnectorRequest) (*dexapi.UpdateConnectorReq, error) {
+	configBytes, err := buildLDAPConfig(params.ConnectorSubType, params.LDAPConnectorParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dexapi.UpdateConnectorReq{
+		Id:        params.ID,
+		NewName:   params.Name,
+		NewConfig: configBytes,
+	}, nil
+}
+
+// buildLDAPConfig validates the connection and search params and builds the dex config, applying the attribute
+// names of the sub-type where they are not set. Groups are only searched when a group base DN is given.
+func buildLDAPConfig(subType string, params LDAPConnectorParams) ([]byte, error) {
+	if params.Host == "" {
+		return nil, fmt.Errorf("host is required for ldap connector")
+	}
+	if _, _, err := net.SplitHostPort(params.Host); err != nil {
+		return nil, fmt.Errorf("host should be in host:port format: %w", err)
+	}
+	if params.UserSearchBaseDN == "" {
+		return nil, fmt.Errorf("user_search_base_dn is required for ldap connector")
+	}
+	if (params.BindDN == "") != (params.BindPassword == "") {
+		return nil, fmt.Errorf("bind_dn and bind_password should be set together")
+	}
+
+	config := LDAPConfig{
+		Host:               params.Host,
+		InsecureNoSSL:      params.InsecureNoSSL,
+		InsecureSkipVerify: params.InsecureSkipVerify,
+		StartTLS:           params.StartTLS,
+		BindDN:             params.BindDN,
+		BindPW:             params.BindPassword,
+		UserSearch: LDAPUserSearch{
+			BaseDN:    params.UserSearchBaseDN,
+			Filter:    params.UserSearchFilter,
+			Username:  params.UsernameAttribute,
+			IDAttr:    "DN",
+			EmailAttr: params.EmailAttribute,
+			NameAttr:  params.NameAttribute,
+		},
+	}
+	if params.RootCAData != "" {
+		if err := validateCertificatesPEM([]byte(params.RootCAData)); err != nil {
+			return nil, fmt.Errorf("invalid ldap root certificate: %w", err)
+		}
+		config.RootCAData = []byte(params.RootCAData)
+	}
+	if params.GroupSearchBaseDN != "" {
+		config.GroupSearch = &LDAPGroupSearch{
+			BaseDN: params.GroupSearchBaseDN,
+			Filter: params.GroupSearchFilter,
+			UserMatchers: []LDAPUserMatcher{
+				{UserAttr: "DN", GroupAttr: params.GroupMemberAttr},
+			},
+			NameAttr: params.GroupNameAttribute,
+		}
+		setDefault(&config.GroupSearch.UserMatchers[0].GroupAttr, "member")
+		setDefault(&config.GroupSearch.NameAttr, "cn")
+	}
+
+	switch subType {
+	case "active-directory":
+		config.UsernamePrompt = "Email Address"
+		setDefault(&config.UserSearch.Filter, "(objectClass=person)")
+		setDefault(&config.UserSearch.Username, "userPrincipalName")
+		setDefault(&config.UserSearch.EmailAttr, "userPrincipalName")
+		setDefault(&config.UserSearch.NameAttr, "cn")
+		if config.GroupSearch != nil {
+			setDefault(&config.GroupSearch.Filter, "(objectClass=group)")
+		}
+	case "general", "":
+		config.UsernamePrompt = "Username"
+		setDefault(&config.UserSearch.Filter, "(objectClass=person)")
+		setDefault(&config.UserSearch.Username, "uid")
+		setDefault(&config.UserSearch.EmailAttr, "mail")
+		setDefault(&config.UserSearch.NameAttr, "cn")
+		if config.GroupSearch != nil {
+			setDefault(&config.GroupSearch.Filter, "(objectClass=groupOfNames)")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported connector_sub_type: %s", subType)
+	}
+
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal LDAP config: %w", err)
+	}
+	return configBytes, nil
+}
@@ -0,0 +1,233 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	dexapi "github.com/dexidp/dex/api/v2"
+)
+
+const (
+	samlHTTPPostBinding = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlMetadataTimeout = 10 * time.Second
+	samlMetadataMaxSize = 1 << 20
+)
+
+type SAMLConnectorParams struct {
+	MetadataURL  string `json:"metadata_url,omitempty" validate:"omitempty,url"`
+	SSOURL       string `json:"sso_url,omitempty" validate:"omitempty,url"`
+	SSOIssuer    string `json:"sso_issuer,omitempty"`
+	CAData       string `json:"ca_data,omitempty"` // PEM encoded certificate of the identity provider
+	EntityIssuer string `json:"entity_issuer,omitempty"`
+	UsernameAttr string `json:"username_attr,omitempty"`
+	EmailAttr    string `json:"email_attr,omitempty"`
+	GroupsAttr   string `json:"groups_attr,omitempty"`
+	NameIDPolicy string `json:"name_id_policy_format,omitempty"`
+}
+
+// SAMLConfig is the config of the dex saml connector
+type SAMLConfig struct {
+	SSOURL             string `json:"ssoURL"`
+	CAData             []byte `json:"caData"`
+	EntityIssuer       string `json:"entityIssuer,omitempty"`
+	SSOIssuer          string `json:"ssoIssuer,omitempty"`
+	RedirectURI        string `json:"redirectURI"`
+	UsernameAttr       string `json:"usernameAttr"`
+	EmailAttr          string `json:"emailAttr"`
+	GroupsAttr         string `json:"groupsAttr,omitempty"`
+	NameIDPolicyFormat string `json:"nameIDPolicyFormat,omitempty"`
+}
+
+type samlEntityDescriptor struct {
+	EntityID         string `xml:"entityID,attr"`
+	IDPSSODescriptor struct {
+		KeyDescriptors []struct {
+			Use     string `xml:"use,attr"`
+			KeyInfo struct {
+				X509Data struct {
+					X509Certificates []string `xml:"X509Certificate"`
+				} `xml:"X509Data"`
+			} `xml:"KeyInfo"`
+		} `xml:"KeyDescriptor"`
+		SingleSignOnServices []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"SingleSignOnService"`
+	} `xml:"IDPSSODescriptor"`
+}
+
+func CreateSAMLConnector(params CreateConnectorRequest) (*dexapi.CreateConnectorReq, error) {
+	configBytes, err := buildSAMLConfig(params.ConnectorSubType, params.SAMLConnectorParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dexapi.CreateConnectorReq{
+		Connector: &dexapi.Connector{
+			Id:     params.ID,
+			Type:   "saml",
+			Name:   params.Name,
+			Config: configBytes,
+		},
+	}, nil
+}
+
+func UpdateSAMLConnector(params UpdateConnectorRequest) (*dexapi.UpdateConnectorReq, error) {
+	configBytes, err := buildSAMLConfig(params.ConnectorSubType, params.SAMLConnectorParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dexapi.UpdateConnectorReq{
+		Id:        params.ID,
+		NewName:   params.Name,
+		NewConfig: configBytes,
+	}, nil
+}
+
+// buildSAMLConfig builds the dex config from the metadata of the identity provider, or from the given sso url
+// and certificate, applying the attribute names of the sub-type where they are not set.
+func buildSAMLConfig(subType string, params SAMLConnectorParams) ([]byte, error) {
+	config := SAMLConfig{
+		SSOURL:             params.SSOURL,
+		SSOIssuer:          params.SSOIssuer,
+		EntityIssuer:       params.EntityIssuer,
+		RedirectURI:        dexCallbackURL(),
+		UsernameAttr:       params.UsernameAttr,
+		EmailAttr:          params.EmailAttr,
+		GroupsAttr:         params.GroupsAttr,
+		NameIDPolicyFormat: params.NameIDPolicy,
+	}
+	if params.CAData != "" {
+		config.CAData = []byte(params.CAData)
+	}
+
+	if params.MetadataURL != "" {
+		metadata, err := fetchSAMLMetadata(params.MetadataURL)
+		if err != nil {
+			return nil, err
+		}
+		if config.SSOURL == "" {
+			config.SSOURL = metadata.ssoURL
+		}
+		if config.SSOIssuer == "" {
+			config.SSOIssuer = metadata.entityID
+		}
+		if len(config.CAData) == 0 {
+			config.CAData = metadata.caData
+		}
+	}
+
+	if config.SSOURL == "" {
+		return nil, fmt.Errorf("either metadata_url or sso_url is required for saml connector")
+	}
+	if u, err := url.Parse(config.SSOURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("sso_url should be a valid https url")
+	}
+	if len(config.CAData) == 0 {
+		return nil, fmt.Errorf("ca_data is required for saml connector without metadata_url")
+	}
+	if err := validateCertificatesPEM(config.CAData); err != nil {
+		return nil, fmt.Errorf("invalid saml certificate: %w", err)
+	}
+
+	switch subType {
+	case "adfs":
+		setDefault(&config.UsernameAttr, "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name")
+		setDefault(&config.EmailAttr, "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress")
+		setDefault(&config.GroupsAttr, "http://schemas.xmlsoap.org/claims/Group")
+	case "general", "okta", "":
+		setDefault(&config.UsernameAttr, "name")
+		setDefault(&config.EmailAttr, "email")
+		setDefault(&config.GroupsAttr, "groups")
+	default:
+		return nil, fmt.Errorf("unsupported connector_sub_type: %s", subType)
+	}
+
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SAML config: %w", err)
+	}
+	return configBytes, nil
+}
+
+type samlMetadata struct {
+	entityID string
+	ssoURL   string
+	caData   []byte
+}
+
+// fetchSAMLMetadata reads the sso url, issuer and signing certificates from the metadata of the identity provider
+func fetchSAMLMetadata(metadataURL string) (*samlMetadata, error) {
+	u, err := url.Parse(metadataURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("metadata_url should be a valid https url")
+	}
+
+	client := http.Client{Timeout: samlMetadataTimeout}
+	resp, err := client.Get(metadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SAML metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d when fetching SAML metadata", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, samlMetadataMaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SAML metadata response: %w", err)
+	}
+
+	var descriptor samlEntityDescriptor
+	if err := xml.Unmarshal(body, &descriptor); err != nil {
+		return nil, fmt.Errorf("failed to parse SAML metadata: %w", err)
+	}
+
+	metadata := samlMetadata{
+		entityID: descriptor.EntityID,
+	}
+	for _, sso := range descriptor.IDPSSODescriptor.SingleSignOnServices {
+		// dex sends the authentication request with the http-post binding
+		if sso.Binding == samlHTTPPostBinding {
+			metadata.ssoURL = sso.Location
+			break
+		}
+	}
+	if metadata.ssoURL == "" {
+		return nil, fmt.Errorf("no HTTP-POST single sign-on service found in SAML metadata")
+	}
+
+	for _, key := range descriptor.IDPSSODescriptor.KeyDescriptors {
+		if key.Use != "" && key.Use != "signing" {
+			continue
+		}
+		for _, cert := range key.KeyInfo.X509Data.X509Certificates {
+			der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(cert), ""))
+			if err != nil {
+				return nil, fmt.Errorf("invalid signing certificate in SAML metadata: %w", err)
+			}
+			metadata.caData = append(metadata.caData, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+		}
+	}
+	if len(metadata.caData) == 0 {
+		return nil, fmt.Errorf("no signing certificate found in SAML metadata")
+	}
+
+	return &metadata, nil
+}
+
+func setDefault(value *string, defaultValue string) {
+	if *value == "" {
+		*value = defaultValue
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/opencomply/services/auth/db"
+)
+
+// MatchRoleMappingRule returns the first rule, by priority, matching one of the groups or the email domain of
+// the user. Patterns are case-insensitive globs, e.g. "platform-*" or "*.example.com".
+func MatchRoleMappingRule(rules []db.RoleMappingRule, email string, groups []string) *db.RoleMappingRule {
+	domain := EmailDomain(email)
+	for i, rule := range rules {
+		pattern := strings.ToLower(rule.Pattern)
+		switch rule.MatchType {
+		case db.RoleMappingMatchTypeGroup:
+			for _, group := range groups {
+				if matched, _ := path.Match(pattern, strings.ToLower(group)); matched {
+					return &rules[i]
+				}
+			}
+		case db.RoleMappingMatchTypeEmailDomain:
+			if domain == "" {
+				continue
+			}
+			if matched, _ := path.Match(pattern, domain); matched {
+				return &rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// ResolveConnectorRole returns the role of a user logging in through the connector, falling back to the default
+// role of the connector. Returns nil if the user should not have access.
+func ResolveConnectorRole(connector db.Connector, rules []db.RoleMappingRule, email string, groups []string) *api.Role {
+	if rule := MatchRoleMappingRule(rules, email, groups); rule != nil {
+		role := rule.Role
+		return &role
+	}
+	return connector.DefaultRole
+}
+
+// HasRoleMapping reports whether the roles of the connector users are managed by the identity provider
+func HasRoleMapping(connector db.Connector, rules []db.RoleMappingRule) bool {
+	return len(rules) > 0 || connector.DefaultRole != nil
+}
+
+func ValidateRoleMappingRule(matchType, pattern string, role api.Role) error {
+	switch matchType {
+	case db.RoleMappingMatchTypeGroup, db.RoleMappingMatchTypeEmailDomain:
+	default:
+		return fmt.Errorf("unsupported match_type: %s", matchType)
+	}
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+		return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	return ValidateRole(role)
+}
+
+func ValidateRole(role api.Role) error {
+	switch role {
+	case api.AdminRole, api.EditorRole, api.ViewerRole:
+		return nil
+	}
+	return fmt.Errorf("unsupported role: %s", role)
+}
+
+// IsEmailDomainAllowed checks the domain of the email against the allowed domains, an empty list allows all domains
+func IsEmailDomainAllowed(email string, allowedDomains []string) bool {
+	if len(allowedDomains) == 0 {
+		return true
+	}
+	domain := EmailDomain(email)
+	if domain == "" {
+		return false
+	}
+	for _, allowed := range allowedDomains {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "" {
+			continue
+		}
+		if matched, _ := path.Match(allowed, domain); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func EmailDomain(email string) string {
+	idx := strings.LastIndex(email, "@")
+	if idx < 0 || idx == len(email)-1 {
+		return ""
+	}
+	return strings.ToLower(email[idx+1:])
+}
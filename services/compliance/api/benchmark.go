@@ -24,6 +24,8 @@ type Benchmark struct {
 	DocumentURI       string              `json:"documentURI" example:"benchmarks/azure_cis_v140.md"`                                                                                                                                // Benchmark document URI
 	AutoAssign        bool                `json:"autoAssign" example:"true"`                                                                                                                                                         // Whether the benchmark is auto assigned or not
 	TracksDriftEvents bool                `json:"tracksDriftEvents" example:"true"`                                                                                                                                                  // Whether the benchmark tracks drift events or not
+	MaxDataAgeHours   *int                `json:"maxDataAgeHours,omitempty" example:"48"`                                                                                                                                            // Maximum age of discovered data the benchmark can be evaluated on
+	RefreshStaleData  bool                `json:"refreshStaleData" example:"true"`                                                                                                                                                   // Whether stale data is discovered again before evaluation
 	Tags              map[string][]string `json:"tags" `                                                                                                                                                                             // Benchmark tags
 	IntegrationTypes  []string            `json:"integrationTypes"`                                                                                                                                                                  // Benchmark connectors
 	Children          []string            `json:"children"`                                                                                                                                                                          // Benchmark children
@@ -393,3 +395,15 @@ type GetBenchmarkTrendV3Response struct {
 	MaximumValues BenchmarkTrendDatapointV3   `json:"maximum_values"`
 	MinimumValues BenchmarkTrendDatapointV3   `json:"minimum_values"`
 }
+
+// BenchmarkTree is a benchmark with all of its sub benchmarks, controls and their queries, keyed by ID.
+type BenchmarkTree struct {
+	RootBenchmarkID string               `json:"rootBenchmarkId"`
+	Version         string               `json:"version"`
+	Benchmarks      map[string]Benchmark `json:"benchmarks"`
+	Controls        map[string]Control   `json:"controls"`
+}
+
+type FrameworksVersionResponse struct {
+	Version string `json:"version"`
+}
@@ -28,7 +28,8 @@ type ComplianceResultFilters struct {
 		From *int64 `json:"from"`
 		To   *int64 `json:"to"`
 	} `json:"evaluatedAt"`
-	Interval *string `json:"interval" example:"5m"`
+	Interval *string  `json:"interval" example:"5m"`
+	Owner    []string `json:"owner" example:"platform-team"`
 }
 
 type ComplianceResultSummaryFilters struct {
@@ -66,6 +67,33 @@ type GetComplianceResultsRequest struct {
 	Sort         []ComplianceResultsSort `json:"sort"`
 	Limit        int                     `json:"limit" example:"100"`
 	AfterSortKey []any                   `json:"afterSortKey"`
+	AsOf         *int64                  `json:"asOf,omitempty" example:"1589395200"` // epoch seconds, returns the results as they were at that time
+	AsOfJobID    *uint                   `json:"asOfJobID,omitempty" example:"1"`     // returns the results as evaluated by the compliance job
+}
+
+// ComplianceSnapshotRef points to the state of compliance results at a time or as evaluated by a compliance job
+type ComplianceSnapshotRef struct {
+	AsOf  *int64 `json:"asOf,omitempty" example:"1589395200"`
+	JobID *uint  `json:"jobID,omitempty" example:"1"`
+}
+
+type CompareComplianceSnapshotsRequest struct {
+	BenchmarkID   string                `json:"benchmarkID" example:"azure_cis_v140"`
+	IntegrationID []string              `json:"integrationID" example:"8e0f8e7a-1b1c-4e6f-b7e4-9c6af9d2b1c8"`
+	Base          ComplianceSnapshotRef `json:"base"`
+	Target        ComplianceSnapshotRef `json:"target"`
+	Limit         int                   `json:"limit" example:"100"`
+}
+
+type ComplianceSnapshotDiff struct {
+	Count   int                `json:"count" example:"10"`
+	Results []ComplianceResult `json:"results"`
+}
+
+type CompareComplianceSnapshotsResponse struct {
+	NewlyFailed     ComplianceSnapshotDiff `json:"newlyFailed"`
+	Resolved        ComplianceSnapshotDiff `json:"resolved"`
+	UnchangedFailed ComplianceSnapshotDiff `json:"unchangedFailed"`
 }
 
 type GetSingleComplianceResultRequest struct {
@@ -124,6 +152,8 @@ type ComplianceResult struct {
 	StateActive        bool                           `json:"stateActive" example:"true"`
 	ComplianceStatus   ComplianceStatus               `json:"complianceStatus" example:"alarm"`
 	Severity           types.ComplianceResultSeverity `json:"severity" example:"low"`
+	OriginalSeverity   types.ComplianceResultSeverity `json:"originalSeverity" example:"medium"`
+	SeverityOverrideID *uint                          `json:"severityOverrideID,omitempty" example:"1"`
 	IntegrationType    integration.Type               `json:"integrationType" example:"Azure"`
 	PlatformResourceID string                         `json:"platformResourceID" example:"/subscriptions/123/resourceGroups/rg-1/providers/Microsoft.Compute/virtualMachines/vm-1"`
 	ResourceID         string                         `json:"resourceID" example:"/subscriptions/123/resourceGroups/rg-1/providers/Microsoft.Compute/virtualMachines/vm-1"`
@@ -136,6 +166,13 @@ type ComplianceResult struct {
 	ControlPath        string                         `json:"controlPath" example:"aws_cis2/aws_cis2_1/unsecure_http"`
 	LastEvent          time.Time                      `json:"lastEvent" example:"1589395200"`
 
+	FirstSeenAt *time.Time                      `json:"firstSeenAt,omitempty" example:"1589395200"`
+	ResolvedAt  *time.Time                      `json:"resolvedAt,omitempty" example:"1589395200"`
+	SLADueAt    *time.Time                      `json:"slaDueAt,omitempty" example:"1589395200"`
+	SLAStatus   types.ComplianceResultSLAStatus `json:"slaStatus" example:"within"`
+
+	Owner string `json:"owner,omitempty" example:"platform-team"`
+
 	ResourceTypeName     string   `json:"resourceTypeName" example:"Virtual Machine"`
 	ParentBenchmarkNames []string `json:"parentBenchmarkNames" example:"Azure CIS v1.4.0"`
 	ControlTitle         string   `json:"controlTitle"`
@@ -155,6 +192,8 @@ func GetAPIComplianceResultFromESComplianceResult(complianceResult types.Complia
 		StateActive:        complianceResult.StateActive,
 		ComplianceStatus:   "",
 		Severity:           complianceResult.Severity,
+		OriginalSeverity:   complianceResult.OriginalSeverity,
+		SeverityOverrideID: complianceResult.SeverityOverrideID,
 		IntegrationType:    complianceResult.IntegrationType,
 		PlatformResourceID: complianceResult.PlatformResourceID,
 		ResourceID:         complianceResult.ResourceID,
@@ -166,12 +205,30 @@ func GetAPIComplianceResultFromESComplianceResult(complianceResult types.Complia
 		ComplianceJobID:    complianceResult.ComplianceJobID,
 		ControlPath:        complianceResult.ControlPath,
 		LastEvent:          time.UnixMilli(complianceResult.LastUpdatedAt),
+		Owner:              complianceResult.Owner,
 	}
 	if complianceResult.ComplianceStatus.IsPassed() {
 		f.ComplianceStatus = ComplianceStatusPassed
 	} else {
 		f.ComplianceStatus = ComplianceStatusFailed
 	}
+	if complianceResult.FirstSeenAt != 0 {
+		firstSeenAt := time.UnixMilli(complianceResult.FirstSeenAt)
+		f.FirstSeenAt = &firstSeenAt
+	}
+	if complianceResult.ResolvedAt != nil {
+		resolvedAt := time.UnixMilli(*complianceResult.ResolvedAt)
+		f.ResolvedAt = &resolvedAt
+	}
+	if complianceResult.SLADueAt != nil {
+		slaDueAt := time.UnixMilli(*complianceResult.SLADueAt)
+		f.SLADueAt = &slaDueAt
+	}
+	f.SLAStatus = complianceResult.SLAStatus(time.Now())
+	// results from before the severity overrides carry the severity of the control only
+	if f.OriginalSeverity == "" {
+		f.OriginalSeverity = f.Severity
+	}
 	if f.ResourceType == "" {
 		f.ResourceType = "Unknown"
 		f.ResourceTypeName = "Unknown"
@@ -43,7 +43,8 @@ type BenchmarkAssignedResourceCollection struct {
 }
 
 type BenchmarkAssignedEntities struct {
-	Integrations []BenchmarkAssignedIntegration `json:"integrations"`
+	Integrations        []BenchmarkAssignedIntegration        `json:"integrations"`
+	ResourceCollections []BenchmarkAssignedResourceCollection `json:"resourceCollections"`
 }
 
 type TopFieldRecord struct {
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"time"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+type OwnershipRuleRequest struct {
+	// Owner is the team the matched resources are assigned to, tag rules without an owner assign them to the tag value
+	Owner string `json:"owner" example:"platform-team"`
+	// Priority orders the rules by ascending priority, the first matching rule assigns the owner
+	Priority  int    `json:"priority" example:"1"`
+	MatchType string `json:"match_type" validate:"required,oneof=tag integration integration_group resource_type" enums:"tag,integration,integration_group,resource_type"`
+	TagKey    string `json:"tag_key,omitempty" example:"team"`
+	// Pattern is a case-insensitive glob on the tag value, integration id or resource type, "*" also matches "/".
+	// Integration group rules take the name of the integration group instead.
+	Pattern     string `json:"pattern" example:"aws::ec2::*"`
+	Description string `json:"description"`
+}
+
+type OwnershipRule struct {
+	ID          uint      `json:"id"`
+	Owner       string    `json:"owner,omitempty"`
+	Priority    int       `json:"priority"`
+	MatchType   string    `json:"match_type"`
+	TagKey      string    `json:"tag_key,omitempty"`
+	Pattern     string    `json:"pattern,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedBy   string    `json:"updated_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ListOwnershipRulesResponse struct {
+	Items []OwnershipRule `json:"items"`
+}
+
+// ResolvedOwnershipRule is a rule with its integration group resolved for the compliance summarizer
+type ResolvedOwnershipRule struct {
+	ID        uint   `json:"id"`
+	Owner     string `json:"owner,omitempty"`
+	Priority  int    `json:"priority"`
+	MatchType string `json:"match_type"`
+	TagKey    string `json:"tag_key,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	// IntegrationIDs are the members of the integration group of integration_group rules
+	IntegrationIDs []string `json:"integration_ids,omitempty"`
+}
+
+type OwnerScorecardRequest struct {
+	IntegrationIDs []string `json:"integration_ids"`
+	BenchmarkIDs   []string `json:"benchmark_ids"`
+	// Owners limits the scorecard to the given owners, unassigned selects the results without an owner
+	Owners []string `json:"owners"`
+}
+
+type OwnerScorecard struct {
+	Owner                 string                                 `json:"owner"`
+	ResourceCount         int                                    `json:"resource_count"`
+	PassedCount           int                                    `json:"passed_count"`
+	FailedCount           int                                    `json:"failed_count"`
+	FailedCountBySeverity map[types.ComplianceResultSeverity]int `json:"failed_count_by_severity"`
+	SLABreachedCount      int                                    `json:"sla_breached_count"`
+	// SecurityScore is the share of passing results in percents
+	SecurityScore float64 `json:"security_score"`
+}
+
+type OwnerScorecardResponse struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Owners      []OwnerScorecard `json:"owners"`
+}
@@ -0,0 +1,73 @@
+package api
+
+import "time"
+
+type ReportStatus string
+
+const (
+	ReportStatusGenerating ReportStatus = "GENERATING"
+	ReportStatusSucceeded  ReportStatus = "SUCCEEDED"
+	ReportStatusFailed     ReportStatus = "FAILED"
+)
+
+type GenerateReportRequest struct {
+	// ComplianceJobID is the compliance job to report, if empty the latest compliance job of the framework is reported
+	ComplianceJobID *uint    `json:"compliance_job_id"`
+	FrameworkID     string   `json:"framework_id"`
+	Formats         []string `json:"formats" validate:"required,min=1"`
+}
+
+type Report struct {
+	ID              uint         `json:"id"`
+	ComplianceJobID uint         `json:"compliance_job_id"`
+	FrameworkID     string       `json:"framework_id"`
+	Format          string       `json:"format"`
+	ScheduleID      *uint        `json:"schedule_id,omitempty"`
+	Status          ReportStatus `json:"status"`
+	FailureMessage  string       `json:"failure_message,omitempty"`
+	FileName        string       `json:"file_name"`
+	Size            int64        `json:"size"`
+	CreatedBy       string       `json:"created_by"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+type ListReportsResponse struct {
+	Items      []Report `json:"items"`
+	TotalCount int64    `json:"total_count"`
+}
+
+type ReportTemplateRequest struct {
+	Title        string `json:"title"`
+	Introduction string `json:"introduction"`
+	Footer       string `json:"footer"`
+	// HTMLTemplate is a Go html/template rendered with the report, empty uses the default template
+	HTMLTemplate string `json:"html_template"`
+}
+
+type ReportTemplate struct {
+	FrameworkID  string     `json:"framework_id"`
+	Title        string     `json:"title"`
+	Introduction string     `json:"introduction"`
+	Footer       string     `json:"footer"`
+	HTMLTemplate string     `json:"html_template"`
+	IsDefault    bool       `json:"is_default"`
+	UpdatedBy    string     `json:"updated_by,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+type ReportScheduleRequest struct {
+	FrameworkID string   `json:"framework_id" validate:"required"`
+	Formats     []string `json:"formats" validate:"required,min=1"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+type ReportSchedule struct {
+	ID          uint      `json:"id"`
+	FrameworkID string    `json:"framework_id"`
+	Formats     []string  `json:"formats"`
+	Enabled     bool      `json:"enabled"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
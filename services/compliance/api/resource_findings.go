@@ -15,6 +15,7 @@ type ResourceFinding struct {
 	ResourceTypeLabel  string           `json:"resourceTypeLabel"`
 	IntegrationType    integration.Type `json:"integrationType"`
 	ComplianceJobID    string           `json:"complianceJobID"`
+	Owner              string           `json:"owner,omitempty" example:"platform-team"`
 
 	FailedCount int `json:"failedCount"`
 	TotalCount  int `json:"totalCount"`
@@ -38,6 +39,7 @@ func GetAPIResourceFinding(resourceFinding types.ResourceFinding) ResourceFindin
 		ResourceLocation:   resourceFinding.ResourceLocation,
 		ResourceType:       resourceFinding.ResourceType,
 		IntegrationType:    resourceFinding.IntegrationType,
+		Owner:              resourceFinding.Owner,
 
 		FailedCount: 0,
 		TotalCount:  len(resourceFinding.ComplianceResults),
@@ -84,7 +86,8 @@ type ResourceFindingFilters struct {
 		From *int64 `json:"from"`
 		To   *int64 `json:"to"`
 	}
-	Interval *string `json:"interval"`
+	Interval *string  `json:"interval"`
+	Owner    []string `json:"owner" example:"platform-team"`
 }
 
 type ResourceFindingsSort struct {
@@ -101,6 +104,8 @@ type ListResourceFindingsRequest struct {
 	Sort         []ResourceFindingsSort `json:"sort"`
 	Limit        int                    `json:"limit" example:"100"`
 	AfterSortKey []any                  `json:"afterSortKey"`
+	AsOf         *int64                 `json:"asOf,omitempty" example:"1589395200"` // epoch seconds, returns the findings as they were at that time
+	AsOfJobID    *uint                  `json:"asOfJobID,omitempty" example:"1"`     // returns the findings as summarized for the compliance job
 }
 
 type ListResourceFindingsResponse struct {
@@ -0,0 +1,66 @@
+package api
+
+import "github.com/opengovern/opencomply/pkg/types"
+
+type RiskAnalysisRequest struct {
+	IntegrationIDs []string `json:"integration_ids"`
+	BenchmarkIDs   []string `json:"benchmark_ids"`
+	// MaxDepth is the maximum number of relationships in an attack path, defaults to 4
+	MaxDepth *int `json:"max_depth" validate:"omitempty,min=1,max=6"`
+	// Limit is the number of attack paths or findings returned, defaults to 10
+	Limit *int `json:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+type RiskFinding struct {
+	ComplianceResultID string                         `json:"compliance_result_id"`
+	BenchmarkID        string                         `json:"benchmark_id"`
+	ControlID          string                         `json:"control_id"`
+	Severity           types.ComplianceResultSeverity `json:"severity"`
+	Reason             string                         `json:"reason"`
+	// Categories are the parts the finding plays in attack paths: exposure, privilege or data
+	Categories []string `json:"categories"`
+}
+
+type RiskResource struct {
+	PlatformResourceID string        `json:"platform_resource_id"`
+	ResourceID         string        `json:"resource_id"`
+	ResourceName       string        `json:"resource_name"`
+	ResourceType       string        `json:"resource_type"`
+	IntegrationID      string        `json:"integration_id"`
+	IntegrationType    string        `json:"integration_type"`
+	Findings           []RiskFinding `json:"findings,omitempty"`
+}
+
+type AttackPathStep struct {
+	// Relationship is the relationship leading to the resource, empty for the entry point
+	Relationship string       `json:"relationship,omitempty"`
+	Resource     RiskResource `json:"resource"`
+}
+
+type AttackPath struct {
+	Score      float64          `json:"score"`
+	Categories []string         `json:"categories"`
+	Steps      []AttackPathStep `json:"steps"`
+}
+
+type ListAttackPathsResponse struct {
+	TotalCount int          `json:"total_count"`
+	Paths      []AttackPath `json:"paths"`
+	// Truncated is set when the failing results or relationships loaded for the analysis or the enumeration of the
+	// attack paths hit their limits
+	Truncated bool `json:"truncated"`
+}
+
+type FindingPriority struct {
+	Finding         RiskFinding  `json:"finding"`
+	Resource        RiskResource `json:"resource"`
+	BaseScore       float64      `json:"base_score"`
+	ContextualScore float64      `json:"contextual_score"`
+	AttackPathCount int          `json:"attack_path_count"`
+}
+
+type ListFindingPrioritiesResponse struct {
+	TotalCount int               `json:"total_count"`
+	Items      []FindingPriority `json:"items"`
+	Truncated  bool              `json:"truncated"`
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+type SeverityOverrideScopeType string
+
+const (
+	// SeverityOverrideScopeAll applies the override to every result of the control
+	SeverityOverrideScopeAll                SeverityOverrideScopeType = ""
+	SeverityOverrideScopeIntegrationGroup   SeverityOverrideScopeType = "integration_group"
+	SeverityOverrideScopeResourceCollection SeverityOverrideScopeType = "resource_collection"
+	// SeverityOverrideScopeTag scope values are written key=value
+	SeverityOverrideScopeTag SeverityOverrideScopeType = "tag"
+)
+
+// Specificity orders the scopes, the most specific override matching a result is applied
+func (t SeverityOverrideScopeType) Specificity() int {
+	switch t {
+	case SeverityOverrideScopeTag:
+		return 3
+	case SeverityOverrideScopeResourceCollection:
+		return 2
+	case SeverityOverrideScopeIntegrationGroup:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ParseSeverityOverrideTagScope splits a tag scope value into the tag key and value
+func ParseSeverityOverrideTagScope(scopeValue string) (string, string, bool) {
+	key, value, ok := strings.Cut(scopeValue, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+type SeverityOverrideAction string
+
+const (
+	SeverityOverrideActionCreated SeverityOverrideAction = "created"
+	SeverityOverrideActionUpdated SeverityOverrideAction = "updated"
+	SeverityOverrideActionDeleted SeverityOverrideAction = "deleted"
+)
+
+type SeverityOverrideRequest struct {
+	Severity   string                    `json:"severity" validate:"required"`
+	ScopeType  SeverityOverrideScopeType `json:"scope_type"`
+	ScopeValue string                    `json:"scope_value"`
+	Reason     string                    `json:"reason"`
+}
+
+type SeverityOverride struct {
+	ID               uint                           `json:"id"`
+	ControlID        string                         `json:"control_id"`
+	ScopeType        SeverityOverrideScopeType      `json:"scope_type"`
+	ScopeValue       string                         `json:"scope_value,omitempty"`
+	Severity         types.ComplianceResultSeverity `json:"severity"`
+	OriginalSeverity types.ComplianceResultSeverity `json:"original_severity"`
+	Reason           string                         `json:"reason,omitempty"`
+	CreatedBy        string                         `json:"created_by"`
+	UpdatedBy        string                         `json:"updated_by"`
+	CreatedAt        time.Time                      `json:"created_at"`
+	UpdatedAt        time.Time                      `json:"updated_at"`
+}
+
+type SeverityOverrideHistoryEntry struct {
+	ID               uint                           `json:"id"`
+	OverrideID       uint                           `json:"override_id"`
+	ControlID        string                         `json:"control_id"`
+	Action           SeverityOverrideAction         `json:"action"`
+	ScopeType        SeverityOverrideScopeType      `json:"scope_type"`
+	ScopeValue       string                         `json:"scope_value,omitempty"`
+	Severity         types.ComplianceResultSeverity `json:"severity"`
+	PreviousSeverity types.ComplianceResultSeverity `json:"previous_severity,omitempty"`
+	Reason           string                         `json:"reason,omitempty"`
+	ChangedBy        string                         `json:"changed_by"`
+	ChangedAt        time.Time                      `json:"changed_at"`
+}
+
+type ListSeverityOverridesResponse struct {
+	Items []SeverityOverride `json:"items"`
+}
+
+type ListSeverityOverrideHistoryResponse struct {
+	Items []SeverityOverrideHistoryEntry `json:"items"`
+}
+
+// ResolvedSeverityOverride is an override with its scope resolved for the compliance runner
+type ResolvedSeverityOverride struct {
+	ID         uint                           `json:"id"`
+	ScopeType  SeverityOverrideScopeType      `json:"scope_type"`
+	ScopeValue string                         `json:"scope_value,omitempty"`
+	Severity   types.ComplianceResultSeverity `json:"severity"`
+	UpdatedAt  time.Time                      `json:"updated_at"`
+	// IntegrationIDs are the members of the integration group of integration_group overrides
+	IntegrationIDs []string `json:"integration_ids,omitempty"`
+	// ResourceCollectionFilters are the filters of the resource collection of resource_collection overrides
+	ResourceCollectionFilters []opengovernance.ResourceCollectionFilter `json:"resource_collection_filters,omitempty"`
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"time"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+type SLAPolicy struct {
+	Severity types.ComplianceResultSeverity `json:"severity"`
+	// ResolveWithinDays is the number of days a failing result of the severity must be resolved in, zero disables the SLA
+	ResolveWithinDays int        `json:"resolve_within_days"`
+	IsDefault         bool       `json:"is_default"`
+	UpdatedBy         string     `json:"updated_by,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+type SLAPolicyRequest struct {
+	Severity          string `json:"severity" validate:"required"`
+	ResolveWithinDays int    `json:"resolve_within_days" validate:"min=0"`
+}
+
+type PutSLAPoliciesRequest struct {
+	Policies []SLAPolicyRequest `json:"policies" validate:"required,min=1,dive"`
+}
+
+type AgeingReportRequest struct {
+	IntegrationIDs []string `json:"integration_ids"`
+	BenchmarkIDs   []string `json:"benchmark_ids"`
+	// OwnerTagKey is the resource tag holding the owner of the results, owner if empty
+	OwnerTagKey string `json:"owner_tag_key"`
+}
+
+type AgeingReportGroup struct {
+	Key            string  `json:"key"`
+	OpenCount      int     `json:"open_count"`
+	WithinSLACount int     `json:"within_sla_count"`
+	AtRiskCount    int     `json:"at_risk_count"`
+	BreachedCount  int     `json:"breached_count"`
+	NoSLACount     int     `json:"no_sla_count"`
+	AverageAgeDays float64 `json:"average_age_days"`
+	OldestAgeDays  float64 `json:"oldest_age_days"`
+}
+
+type AgeingReportBucket struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"min_days"`
+	// MaxDays is empty for the last bucket
+	MaxDays *int `json:"max_days,omitempty"`
+	Count   int  `json:"count"`
+}
+
+type AgeingReportResponse struct {
+	OwnerTagKey   string               `json:"owner_tag_key"`
+	GeneratedAt   time.Time            `json:"generated_at"`
+	Total         AgeingReportGroup    `json:"total"`
+	BySeverity    []AgeingReportGroup  `json:"by_severity"`
+	ByIntegration []AgeingReportGroup  `json:"by_integration"`
+	ByOwner       []AgeingReportGroup  `json:"by_owner"`
+	ByBenchmark   []AgeingReportGroup  `json:"by_benchmark"`
+	AgeBuckets    []AgeingReportBucket `json:"age_buckets"`
+	// Truncated is set when there were more open results than the report reads
+	Truncated bool `json:"truncated"`
+}
@@ -0,0 +1,72 @@
+package api
+
+import "time"
+
+type GetBenchmarkTrendAnalyticsRequest struct {
+	Integration []IntegrationFilter `json:"integration"`
+	ControlIDs  []string            `json:"control_ids"`
+	StartTime   *int64              `json:"start_time"`
+	EndTime     *int64              `json:"end_time"`
+	// MaxJobs is the number of latest compliance jobs in the time range to analyze, defaults to 30
+	MaxJobs *int `json:"max_jobs" validate:"omitempty,min=2,max=200"`
+	// Window is the number of compliance jobs of the moving average and of the remediation rate, defaults to 3
+	Window *int `json:"window" validate:"omitempty,min=1"`
+	// SignificanceLevel is the p-value under which a score drop is flagged as a regression, defaults to 0.05
+	SignificanceLevel *float64 `json:"significance_level" validate:"omitempty,gt=0,lt=1"`
+	// TargetScore is the compliance score, between 0 and 1, to project the time to
+	TargetScore *float64 `json:"target_score" validate:"omitempty,gte=0,lte=1"`
+	// TopContributors is the number of controls and integrations returned per change, defaults to 5
+	TopContributors *int `json:"top_contributors" validate:"omitempty,min=1,max=100"`
+}
+
+type ComplianceTrendCounts struct {
+	Passed int64 `json:"passed"`
+	Failed int64 `json:"failed"`
+}
+
+type ComplianceTrendPoint struct {
+	ComplianceJobID uint                  `json:"compliance_job_id"`
+	EvaluatedAt     time.Time             `json:"evaluated_at"`
+	Results         ComplianceTrendCounts `json:"results"`
+	ComplianceScore float64               `json:"compliance_score"`
+	MovingAverage   float64               `json:"moving_average"`
+}
+
+type ComplianceTrendContributor struct {
+	ID           string                `json:"id"`
+	Contribution float64               `json:"contribution"`
+	Before       ComplianceTrendCounts `json:"before"`
+	After        ComplianceTrendCounts `json:"after"`
+}
+
+type ComplianceTrendChange struct {
+	FromComplianceJobID uint                         `json:"from_compliance_job_id"`
+	ToComplianceJobID   uint                         `json:"to_compliance_job_id"`
+	From                time.Time                    `json:"from"`
+	To                  time.Time                    `json:"to"`
+	ScoreDelta          float64                      `json:"score_delta"`
+	ZScore              float64                      `json:"z_score"`
+	PValue              float64                      `json:"p_value"`
+	Regression          bool                         `json:"regression"`
+	Controls            []ComplianceTrendContributor `json:"controls"`
+	Integrations        []ComplianceTrendContributor `json:"integrations"`
+}
+
+type ComplianceTrendProjection struct {
+	TargetScore  float64    `json:"target_score"`
+	RatePerDay   float64    `json:"rate_per_day"`
+	Reached      bool       `json:"reached"`
+	Reachable    bool       `json:"reachable"`
+	DaysToTarget *float64   `json:"days_to_target,omitempty"`
+	ETA          *time.Time `json:"eta,omitempty"`
+}
+
+type GetBenchmarkTrendAnalyticsResponse struct {
+	BenchmarkID    string                     `json:"benchmark_id"`
+	IntegrationIDs []string                   `json:"integration_ids,omitempty"`
+	ControlIDs     []string                   `json:"control_ids,omitempty"`
+	Datapoints     []ComplianceTrendPoint     `json:"datapoints"`
+	Changes        []ComplianceTrendChange    `json:"changes"`
+	Regressions    []ComplianceTrendChange    `json:"regressions"`
+	Projection     *ComplianceTrendProjection `json:"projection,omitempty"`
+}
@@ -16,6 +16,8 @@ import (
 type ComplianceServiceClient interface {
 	ListAssignmentsByBenchmark(ctx *httpclient.Context, benchmarkID string) (*compliance.BenchmarkAssignedEntities, error)
 	GetBenchmark(ctx *httpclient.Context, benchmarkID string) (*compliance.Benchmark, error)
+	GetBenchmarkTree(ctx *httpclient.Context, benchmarkID string) (*compliance.BenchmarkTree, error)
+	GetFrameworksVersion(ctx *httpclient.Context) (string, error)
 	GetBenchmarkSummary(ctx *httpclient.Context, benchmarkID string, connectionId []string, timeAt *time.Time) (*compliance.BenchmarkEvaluationSummary, error)
 	GetBenchmarkControls(ctx *httpclient.Context, benchmarkID string, connectionId []string, timeAt *time.Time) (*compliance.BenchmarkControlSummary, error)
 	GetControl(ctx *httpclient.Context, controlID string) (*compliance.Control, error)
@@ -25,10 +27,15 @@ type ComplianceServiceClient interface {
 	ListAllBenchmarks(ctx *httpclient.Context, isBare bool) ([]compliance.Benchmark, error)
 	GetAccountsComplianceResultsSummary(ctx *httpclient.Context, benchmarkId string, connectionId []string, connector []source.Type) (compliance.GetAccountsComplianceResultsSummaryResponse, error)
 	CreateBenchmarkAssignment(ctx *httpclient.Context, benchmarkID, connectionId string) ([]compliance.BenchmarkAssignment, error)
+	ListResourceCollectionAssignments(ctx *httpclient.Context, resourceCollectionID string) ([]compliance.BenchmarkAssignment, error)
 	ListQueries(ctx *httpclient.Context) ([]compliance.Query, error)
 	ListControl(ctx *httpclient.Context, controlIDs []string, tags map[string][]string) ([]compliance.Control, error)
 	GetControlDetails(ctx *httpclient.Context, controlID string) (*compliance.GetControlDetailsResponse, error)
 	SyncQueries(ctx *httpclient.Context) error
+	GenerateScheduledReports(ctx *httpclient.Context, complianceJobID uint) ([]compliance.Report, error)
+	ListResolvedControlSeverityOverrides(ctx *httpclient.Context, controlID string) ([]compliance.ResolvedSeverityOverride, error)
+	ListSLAPolicies(ctx *httpclient.Context) ([]compliance.SLAPolicy, error)
+	ListResolvedOwnershipRules(ctx *httpclient.Context) ([]compliance.ResolvedOwnershipRule, error)
 }
 
 type complianceClient struct {
@@ -77,6 +84,32 @@ func (s *complianceClient) GetBenchmark(ctx *httpclient.Context, benchmarkID str
 	return &response, nil
 }
 
+func (s *complianceClient) GetBenchmarkTree(ctx *httpclient.Context, benchmarkID string) (*compliance.BenchmarkTree, error) {
+	url := fmt.Sprintf("%s/api/v1/benchmarks/%s/tree", s.baseURL, benchmarkID)
+
+	var response compliance.BenchmarkTree
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodGet, url, ctx.ToHeaders(), nil, &response); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *complianceClient) GetFrameworksVersion(ctx *httpclient.Context) (string, error) {
+	url := fmt.Sprintf("%s/api/v1/benchmarks/version", s.baseURL)
+
+	var response compliance.FrameworksVersionResponse
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodGet, url, ctx.ToHeaders(), nil, &response); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return "", echo.NewHTTPError(statusCode, err.Error())
+		}
+		return "", err
+	}
+	return response.Version, nil
+}
+
 func (s *complianceClient) GetControlDetails(ctx *httpclient.Context, controlID string) (*compliance.GetControlDetailsResponse, error) {
 	url := fmt.Sprintf("%s/api/v3/control/%s", s.baseURL, controlID)
 
@@ -376,3 +409,68 @@ func (s *complianceClient) CreateBenchmarkAssignment(ctx *httpclient.Context, be
 	}
 	return assignments, nil
 }
+
+func (s *complianceClient) ListResourceCollectionAssignments(ctx *httpclient.Context, resourceCollectionID string) ([]compliance.BenchmarkAssignment, error) {
+	url := fmt.Sprintf("%s/api/v3/resource-collection/%s/assignments", s.baseURL, resourceCollectionID)
+
+	var assignments []compliance.BenchmarkAssignment
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodGet, url, ctx.ToHeaders(), nil, &assignments); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return assignments, nil
+}
+
+func (s *complianceClient) GenerateScheduledReports(ctx *httpclient.Context, complianceJobID uint) ([]compliance.Report, error) {
+	url := fmt.Sprintf("%s/api/v3/reports/compliance-jobs/%d/scheduled", s.baseURL, complianceJobID)
+
+	var reports []compliance.Report
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodPost, url, ctx.ToHeaders(), nil, &reports); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (s *complianceClient) ListResolvedControlSeverityOverrides(ctx *httpclient.Context, controlID string) ([]compliance.ResolvedSeverityOverride, error) {
+	url := fmt.Sprintf("%s/api/v3/controls/%s/severity-overrides/resolved", s.baseURL, controlID)
+
+	var overrides []compliance.ResolvedSeverityOverride
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodGet, url, ctx.ToHeaders(), nil, &overrides); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func (s *complianceClient) ListSLAPolicies(ctx *httpclient.Context) ([]compliance.SLAPolicy, error) {
+	url := fmt.Sprintf("%s/api/v3/sla/policies", s.baseURL)
+
+	var policies []compliance.SLAPolicy
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodGet, url, ctx.ToHeaders(), nil, &policies); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (s *complianceClient) ListResolvedOwnershipRules(ctx *httpclient.Context) ([]compliance.ResolvedOwnershipRule, error) {
+	url := fmt.Sprintf("%s/api/v3/ownership/rules/resolved", s.baseURL)
+
+	var rules []compliance.ResolvedOwnershipRule
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodGet, url, ctx.ToHeaders(), nil, &rules); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return rules, nil
+}
@@ -27,6 +27,7 @@ type ServerConfig struct {
 	Integration   config.OpenGovernanceService
 	Inventory     config.OpenGovernanceService
 	Metadata      config.OpenGovernanceService
+	EsSink        config.OpenGovernanceService
 	OpenAI        OpenAI
 	Http          config.HttpServer
 
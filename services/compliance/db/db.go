@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/opengovern/og-util/pkg/model"
@@ -25,6 +26,13 @@ func (db Database) Initialize(ctx context.Context) error {
 		&Benchmark{},
 		&BenchmarkTag{},
 		&BenchmarkAssignment{},
+		&ReportTemplate{},
+		&ReportSchedule{},
+		&Report{},
+		&ControlSeverityOverride{},
+		&ControlSeverityOverrideHistory{},
+		&SLAPolicy{},
+		&OwnershipRule{},
 	)
 	if err != nil {
 		return err
@@ -701,6 +709,93 @@ func (db Database) GetQueriesIdAndIntegrationType(ctx context.Context, queryIDs
 	return s, nil
 }
 
+// GetBenchmarkTree loads a benchmark with all of its descendants and their controls and queries
+// with a handful of queries per tree level instead of one per node.
+func (db Database) GetBenchmarkTree(ctx context.Context, benchmarkId string) ([]Benchmark, []Control, error) {
+	var benchmarks []Benchmark
+	seen := map[string]bool{}
+	controlIDsMap := map[string]bool{}
+
+	level := []string{benchmarkId}
+	for len(level) > 0 {
+		var s []Benchmark
+		tx := db.Orm.WithContext(ctx).Model(&Benchmark{}).
+			Preload("Tags").Preload("Children").Preload("Controls").
+			Where("id IN ?", level).
+			Find(&s)
+		if tx.Error != nil {
+			return nil, nil, tx.Error
+		}
+
+		level = nil
+		for _, b := range s {
+			if seen[b.ID] {
+				continue
+			}
+			seen[b.ID] = true
+			benchmarks = append(benchmarks, b)
+			for _, child := range b.Children {
+				if !seen[child.ID] {
+					level = append(level, child.ID)
+				}
+			}
+			for _, control := range b.Controls {
+				controlIDsMap[control.ID] = true
+			}
+		}
+	}
+	if len(benchmarks) == 0 {
+		return nil, nil, nil
+	}
+
+	if len(controlIDsMap) == 0 {
+		return benchmarks, nil, nil
+	}
+	controlIDs := make([]string, 0, len(controlIDsMap))
+	for id := range controlIDsMap {
+		controlIDs = append(controlIDs, id)
+	}
+	controls, err := db.GetControls(ctx, controlIDs, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return benchmarks, controls, nil
+}
+
+type frameworksVersion struct {
+	BenchmarksCount        int64
+	ControlsCount          int64
+	QueriesCount           int64
+	BenchmarkChildrenCount int64
+	BenchmarkControlsCount int64
+	UpdatedAt              *time.Time
+}
+
+// GetFrameworksVersion returns a version string that changes whenever benchmarks, controls or queries are synced.
+func (db Database) GetFrameworksVersion(ctx context.Context) (string, error) {
+	var v frameworksVersion
+	tx := db.Orm.WithContext(ctx).Raw(`
+SELECT
+	(SELECT count(*) FROM benchmarks) AS benchmarks_count,
+	(SELECT count(*) FROM controls) AS controls_count,
+	(SELECT count(*) FROM queries) AS queries_count,
+	(SELECT count(*) FROM benchmark_children) AS benchmark_children_count,
+	(SELECT count(*) FROM benchmark_controls) AS benchmark_controls_count,
+	GREATEST((SELECT max(updated_at) FROM benchmarks), (SELECT max(updated_at) FROM controls), (SELECT max(updated_at) FROM queries)) AS updated_at
+`).Scan(&v)
+	if tx.Error != nil {
+		return "", tx.Error
+	}
+
+	var updatedAt int64
+	if v.UpdatedAt != nil {
+		updatedAt = v.UpdatedAt.UnixMilli()
+	}
+	return fmt.Sprintf("%d-%d-%d-%d-%d-%d", updatedAt, v.BenchmarksCount, v.ControlsCount, v.QueriesCount,
+		v.BenchmarkChildrenCount, v.BenchmarkControlsCount), nil
+}
+
 // =========== BenchmarkAssignment ===========
 
 func (db Database) CleanupAllBenchmarkAssignments() error {
@@ -713,8 +808,9 @@ func (db Database) CleanupAllBenchmarkAssignments() error {
 
 func (db Database) AddBenchmarkAssignment(ctx context.Context, assignment *BenchmarkAssignment) error {
 	tx := db.Orm.WithContext(ctx).Where(BenchmarkAssignment{
-		BenchmarkId:   assignment.BenchmarkId,
-		IntegrationID: assignment.IntegrationID,
+		BenchmarkId:        assignment.BenchmarkId,
+		IntegrationID:      assignment.IntegrationID,
+		ResourceCollection: assignment.ResourceCollection,
 	}).FirstOrCreate(assignment)
 
 	if tx.Error != nil {
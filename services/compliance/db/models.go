@@ -55,6 +55,8 @@ type Benchmark struct {
 	Enabled           bool
 	AutoAssign        bool
 	TracksDriftEvents bool
+	MaxDataAgeHours   *int
+	RefreshStaleData  bool
 	Metadata          pgtype.JSONB
 
 	Tags    []BenchmarkTag      `gorm:"foreignKey:BenchmarkID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
@@ -77,6 +79,8 @@ func (b Benchmark) ToApi() api.Benchmark {
 		DocumentURI:       b.DocumentURI,
 		AutoAssign:        b.AutoAssign,
 		TracksDriftEvents: b.TracksDriftEvents,
+		MaxDataAgeHours:   b.MaxDataAgeHours,
+		RefreshStaleData:  b.RefreshStaleData,
 		CreatedAt:         b.CreatedAt,
 		UpdatedAt:         b.UpdatedAt,
 		Tags:              b.GetTagsMap(),
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"gorm.io/gorm"
+)
+
+// OwnershipRule assigns the resources it matches, and their results, to an owner. Rules are evaluated by
+// ascending priority and the first match wins.
+type OwnershipRule struct {
+	ID          uint `gorm:"primaryKey"`
+	Owner       string
+	Priority    int
+	MatchType   string
+	TagKey      string
+	Pattern     string
+	Description string
+	CreatedBy   string
+	UpdatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (r OwnershipRule) ToApi() api.OwnershipRule {
+	return api.OwnershipRule{
+		ID:          r.ID,
+		Owner:       r.Owner,
+		Priority:    r.Priority,
+		MatchType:   r.MatchType,
+		TagKey:      r.TagKey,
+		Pattern:     r.Pattern,
+		Description: r.Description,
+		CreatedBy:   r.CreatedBy,
+		UpdatedBy:   r.UpdatedBy,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func (db Database) ListOwnershipRules(ctx context.Context) ([]OwnershipRule, error) {
+	var rules []OwnershipRule
+	tx := db.Orm.WithContext(ctx).Model(&OwnershipRule{}).
+		Order("priority asc, id asc").Find(&rules)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return rules, nil
+}
+
+func (db Database) GetOwnershipRule(ctx context.Context, id uint) (*OwnershipRule, error) {
+	var r OwnershipRule
+	tx := db.Orm.WithContext(ctx).Model(&OwnershipRule{}).
+		Where("id = ?", id).First(&r)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &r, nil
+}
+
+func (db Database) CreateOwnershipRule(ctx context.Context, r *OwnershipRule) error {
+	return db.Orm.WithContext(ctx).Create(r).Error
+}
+
+func (db Database) UpdateOwnershipRule(ctx context.Context, r *OwnershipRule) error {
+	r.UpdatedAt = time.Now()
+	return db.Orm.WithContext(ctx).Model(&OwnershipRule{}).Where("id = ?", r.ID).
+		Updates(map[string]any{
+			"owner":       r.Owner,
+			"priority":    r.Priority,
+			"match_type":  r.MatchType,
+			"tag_key":     r.TagKey,
+			"pattern":     r.Pattern,
+			"description": r.Description,
+			"updated_by":  r.UpdatedBy,
+			"updated_at":  r.UpdatedAt,
+		}).Error
+}
+
+func (db Database) DeleteOwnershipRule(ctx context.Context, id uint) error {
+	return db.Orm.WithContext(ctx).Where("id = ?", id).Delete(&OwnershipRule{}).Error
+}
@@ -0,0 +1,257 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReportTemplate customises the reports of a framework
+type ReportTemplate struct {
+	FrameworkID  string `gorm:"primaryKey"`
+	Title        string
+	Introduction string
+	Footer       string
+	HTMLTemplate string
+	UpdatedBy    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (t ReportTemplate) ToApi() api.ReportTemplate {
+	return api.ReportTemplate{
+		FrameworkID:  t.FrameworkID,
+		Title:        t.Title,
+		Introduction: t.Introduction,
+		Footer:       t.Footer,
+		HTMLTemplate: t.HTMLTemplate,
+		UpdatedBy:    t.UpdatedBy,
+		UpdatedAt:    &t.UpdatedAt,
+	}
+}
+
+// ReportSchedule generates the reports of a framework after each of its compliance jobs succeeds
+type ReportSchedule struct {
+	ID          uint           `gorm:"primaryKey"`
+	FrameworkID string         `gorm:"index"`
+	Formats     pq.StringArray `gorm:"type:text[]"`
+	Enabled     bool
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (s ReportSchedule) ToApi() api.ReportSchedule {
+	return api.ReportSchedule{
+		ID:          s.ID,
+		FrameworkID: s.FrameworkID,
+		Formats:     s.Formats,
+		Enabled:     s.Enabled,
+		CreatedBy:   s.CreatedBy,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+type Report struct {
+	ID              uint   `gorm:"primaryKey"`
+	ComplianceJobID uint   `gorm:"index"`
+	FrameworkID     string `gorm:"index"`
+	Format          string
+	ScheduleID      *uint `gorm:"index"`
+	Status          api.ReportStatus
+	FailureMessage  string
+	FileName        string
+	Size            int64
+	Content         []byte
+	CreatedBy       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (r Report) ToApi() api.Report {
+	return api.Report{
+		ID:              r.ID,
+		ComplianceJobID: r.ComplianceJobID,
+		FrameworkID:     r.FrameworkID,
+		Format:          r.Format,
+		ScheduleID:      r.ScheduleID,
+		Status:          r.Status,
+		FailureMessage:  r.FailureMessage,
+		FileName:        r.FileName,
+		Size:            r.Size,
+		CreatedBy:       r.CreatedBy,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
+
+// =========== Report Templates ===========
+
+func (db Database) GetReportTemplate(ctx context.Context, frameworkID string) (*ReportTemplate, error) {
+	var t ReportTemplate
+	tx := db.Orm.WithContext(ctx).Model(&ReportTemplate{}).Where("framework_id = ?", frameworkID).First(&t)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &t, nil
+}
+
+func (db Database) ListReportTemplates(ctx context.Context) ([]ReportTemplate, error) {
+	var templates []ReportTemplate
+	tx := db.Orm.WithContext(ctx).Model(&ReportTemplate{}).Order("framework_id").Find(&templates)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return templates, nil
+}
+
+func (db Database) UpsertReportTemplate(ctx context.Context, t *ReportTemplate) error {
+	return db.Orm.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "framework_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "introduction", "footer", "html_template", "updated_by", "updated_at"}),
+	}).Create(t).Error
+}
+
+func (db Database) DeleteReportTemplate(ctx context.Context, frameworkID string) error {
+	return db.Orm.WithContext(ctx).Where("framework_id = ?", frameworkID).Delete(&ReportTemplate{}).Error
+}
+
+// =========== Report Schedules ===========
+
+func (db Database) CreateReportSchedule(ctx context.Context, s *ReportSchedule) error {
+	return db.Orm.WithContext(ctx).Create(s).Error
+}
+
+func (db Database) UpdateReportSchedule(ctx context.Context, s *ReportSchedule) error {
+	return db.Orm.WithContext(ctx).Model(&ReportSchedule{}).Where("id = ?", s.ID).
+		Updates(map[string]any{
+			"framework_id": s.FrameworkID,
+			"formats":      s.Formats,
+			"enabled":      s.Enabled,
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+func (db Database) DeleteReportSchedule(ctx context.Context, id uint) error {
+	return db.Orm.WithContext(ctx).Where("id = ?", id).Delete(&ReportSchedule{}).Error
+}
+
+func (db Database) GetReportSchedule(ctx context.Context, id uint) (*ReportSchedule, error) {
+	var s ReportSchedule
+	tx := db.Orm.WithContext(ctx).Model(&ReportSchedule{}).Where("id = ?", id).First(&s)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &s, nil
+}
+
+func (db Database) ListReportSchedules(ctx context.Context, frameworkID string, enabledOnly bool) ([]ReportSchedule, error) {
+	var schedules []ReportSchedule
+	tx := db.Orm.WithContext(ctx).Model(&ReportSchedule{})
+	if frameworkID != "" {
+		tx = tx.Where("framework_id = ?", frameworkID)
+	}
+	if enabledOnly {
+		tx = tx.Where("enabled = ?", true)
+	}
+	tx = tx.Order("id").Find(&schedules)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return schedules, nil
+}
+
+// =========== Reports ===========
+
+func (db Database) CreateReport(ctx context.Context, r *Report) error {
+	return db.Orm.WithContext(ctx).Create(r).Error
+}
+
+func (db Database) UpdateReportResult(ctx context.Context, id uint, status api.ReportStatus, failureMessage string, content []byte) error {
+	return db.Orm.WithContext(ctx).Model(&Report{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          status,
+			"failure_message": failureMessage,
+			"content":         content,
+			"size":            len(content),
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// FailInterruptedReports fails the reports that are generating since before the given time, their generation
+// was interrupted by a restart
+func (db Database) FailInterruptedReports(ctx context.Context, before time.Time) error {
+	return db.Orm.WithContext(ctx).Model(&Report{}).
+		Where("status = ? AND updated_at < ?", api.ReportStatusGenerating, before).
+		Updates(map[string]any{
+			"status":          api.ReportStatusFailed,
+			"failure_message": "report generation was interrupted",
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// GetReport returns the report, its content is only loaded if withContent is set
+func (db Database) GetReport(ctx context.Context, id uint, withContent bool) (*Report, error) {
+	var r Report
+	tx := db.Orm.WithContext(ctx).Model(&Report{})
+	if !withContent {
+		tx = tx.Omit("content")
+	}
+	tx = tx.Where("id = ?", id).First(&r)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &r, nil
+}
+
+// ListReports returns the reports without their content, newest first
+func (db Database) ListReports(ctx context.Context, complianceJobID *uint, frameworkID string, limit, offset int) ([]Report, int64, error) {
+	tx := db.Orm.WithContext(ctx).Model(&Report{})
+	if complianceJobID != nil {
+		tx = tx.Where("compliance_job_id = ?", *complianceJobID)
+	}
+	if frameworkID != "" {
+		tx = tx.Where("framework_id = ?", frameworkID)
+	}
+	var count int64
+	if err := tx.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reports []Report
+	err := tx.Omit("content").Order("id DESC").Limit(limit).Offset(offset).Find(&reports).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, count, nil
+}
+
+// HasScheduledReport returns true if the schedule already generated a report of the compliance job
+func (db Database) HasScheduledReport(ctx context.Context, scheduleID, complianceJobID uint) (bool, error) {
+	var count int64
+	err := db.Orm.WithContext(ctx).Model(&Report{}).
+		Where("schedule_id = ? AND compliance_job_id = ?", scheduleID, complianceJobID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (db Database) DeleteReport(ctx context.Context, id uint) error {
+	return db.Orm.WithContext(ctx).Where("id = ?", id).Delete(&Report{}).Error
+}
@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"gorm.io/gorm"
+)
+
+// ControlSeverityOverride replaces the severity of the results of a control, optionally only for the results
+// within a scope
+type ControlSeverityOverride struct {
+	ID         uint                          `gorm:"primaryKey"`
+	ControlID  string                        `gorm:"uniqueIndex:idx_control_severity_override_scope"`
+	ScopeType  api.SeverityOverrideScopeType `gorm:"uniqueIndex:idx_control_severity_override_scope"`
+	ScopeValue string                        `gorm:"uniqueIndex:idx_control_severity_override_scope"`
+	Severity   types.ComplianceResultSeverity
+	Reason     string
+	CreatedBy  string
+	UpdatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (o ControlSeverityOverride) ToApi(originalSeverity types.ComplianceResultSeverity) api.SeverityOverride {
+	return api.SeverityOverride{
+		ID:               o.ID,
+		ControlID:        o.ControlID,
+		ScopeType:        o.ScopeType,
+		ScopeValue:       o.ScopeValue,
+		Severity:         o.Severity,
+		OriginalSeverity: originalSeverity,
+		Reason:           o.Reason,
+		CreatedBy:        o.CreatedBy,
+		UpdatedBy:        o.UpdatedBy,
+		CreatedAt:        o.CreatedAt,
+		UpdatedAt:        o.UpdatedAt,
+	}
+}
+
+// ControlSeverityOverrideHistory records every change of the severity overrides, it is kept once the override
+// is deleted
+type ControlSeverityOverrideHistory struct {
+	ID               uint   `gorm:"primaryKey"`
+	OverrideID       uint   `gorm:"index"`
+	ControlID        string `gorm:"index"`
+	Action           api.SeverityOverrideAction
+	ScopeType        api.SeverityOverrideScopeType
+	ScopeValue       string
+	Severity         types.ComplianceResultSeverity
+	PreviousSeverity types.ComplianceResultSeverity
+	Reason           string
+	ChangedBy        string
+	ChangedAt        time.Time
+}
+
+func (h ControlSeverityOverrideHistory) ToApi() api.SeverityOverrideHistoryEntry {
+	return api.SeverityOverrideHistoryEntry{
+		ID:               h.ID,
+		OverrideID:       h.OverrideID,
+		ControlID:        h.ControlID,
+		Action:           h.Action,
+		ScopeType:        h.ScopeType,
+		ScopeValue:       h.ScopeValue,
+		Severity:         h.Severity,
+		PreviousSeverity: h.PreviousSeverity,
+		Reason:           h.Reason,
+		ChangedBy:        h.ChangedBy,
+		ChangedAt:        h.ChangedAt,
+	}
+}
+
+func newSeverityOverrideHistory(o ControlSeverityOverride, action api.SeverityOverrideAction,
+	previousSeverity types.ComplianceResultSeverity, changedBy string) ControlSeverityOverrideHistory {
+	return ControlSeverityOverrideHistory{
+		OverrideID:       o.ID,
+		ControlID:        o.ControlID,
+		Action:           action,
+		ScopeType:        o.ScopeType,
+		ScopeValue:       o.ScopeValue,
+		Severity:         o.Severity,
+		PreviousSeverity: previousSeverity,
+		Reason:           o.Reason,
+		ChangedBy:        changedBy,
+		ChangedAt:        time.Now(),
+	}
+}
+
+func (db Database) ListControlSeverityOverrides(ctx context.Context, controlID string) ([]ControlSeverityOverride, error) {
+	var overrides []ControlSeverityOverride
+	tx := db.Orm.WithContext(ctx).Model(&ControlSeverityOverride{}).
+		Where("control_id = ?", controlID).Order("id").Find(&overrides)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return overrides, nil
+}
+
+func (db Database) GetControlSeverityOverride(ctx context.Context, controlID string, id uint) (*ControlSeverityOverride, error) {
+	var o ControlSeverityOverride
+	tx := db.Orm.WithContext(ctx).Model(&ControlSeverityOverride{}).
+		Where("control_id = ? AND id = ?", controlID, id).First(&o)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &o, nil
+}
+
+// CreateControlSeverityOverride creates the override and records it in the history
+func (db Database) CreateControlSeverityOverride(ctx context.Context, o *ControlSeverityOverride,
+	originalSeverity types.ComplianceResultSeverity) error {
+	return db.Orm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(o).Error; err != nil {
+			return err
+		}
+		history := newSeverityOverrideHistory(*o, api.SeverityOverrideActionCreated, originalSeverity, o.CreatedBy)
+		return tx.Create(&history).Error
+	})
+}
+
+// UpdateControlSeverityOverride replaces the severity and reason of the override and records the change in
+// the history
+func (db Database) UpdateControlSeverityOverride(ctx context.Context, o *ControlSeverityOverride,
+	previousSeverity types.ComplianceResultSeverity) error {
+	return db.Orm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		o.UpdatedAt = time.Now()
+		err := tx.Model(&ControlSeverityOverride{}).Where("id = ?", o.ID).
+			Updates(map[string]any{
+				"severity":   o.Severity,
+				"reason":     o.Reason,
+				"updated_by": o.UpdatedBy,
+				"updated_at": o.UpdatedAt,
+			}).Error
+		if err != nil {
+			return err
+		}
+		history := newSeverityOverrideHistory(*o, api.SeverityOverrideActionUpdated, previousSeverity, o.UpdatedBy)
+		return tx.Create(&history).Error
+	})
+}
+
+// DeleteControlSeverityOverride deletes the override and records the deletion in the history, as a change
+// back to the original severity of the control
+func (db Database) DeleteControlSeverityOverride(ctx context.Context, o ControlSeverityOverride,
+	originalSeverity types.ComplianceResultSeverity, deletedBy string) error {
+	return db.Orm.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", o.ID).Delete(&ControlSeverityOverride{}).Error; err != nil {
+			return err
+		}
+		history := newSeverityOverrideHistory(o, api.SeverityOverrideActionDeleted, o.Severity, deletedBy)
+		history.Severity = originalSeverity
+		return tx.Create(&history).Error
+	})
+}
+
+func (db Database) ListControlSeverityOverrideHistory(ctx context.Context, controlID string) ([]ControlSeverityOverrideHistory, error) {
+	var history []ControlSeverityOverrideHistory
+	tx := db.Orm.WithContext(ctx).Model(&ControlSeverityOverrideHistory{}).
+		Where("control_id = ?", controlID).Order("changed_at DESC, id DESC").Find(&history)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return history, nil
+}
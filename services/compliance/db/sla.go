@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/opengovern/opencomply/pkg/types"
+	"gorm.io/gorm/clause"
+)
+
+// SLAPolicy is the number of days the failing results of a severity must be resolved in
+type SLAPolicy struct {
+	Severity          types.ComplianceResultSeverity `gorm:"primaryKey"`
+	ResolveWithinDays int
+	UpdatedBy         string
+	UpdatedAt         time.Time
+}
+
+func (db Database) ListSLAPolicies(ctx context.Context) ([]SLAPolicy, error) {
+	var policies []SLAPolicy
+	tx := db.Orm.WithContext(ctx).Model(&SLAPolicy{}).Find(&policies)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return policies, nil
+}
+
+func (db Database) UpsertSLAPolicies(ctx context.Context, policies []SLAPolicy) error {
+	return db.Orm.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "severity"}},
+		DoUpdates: clause.AssignmentColumns([]string{"resolve_within_days", "updated_by", "updated_at"}),
+	}).Create(&policies).Error
+}
@@ -220,8 +220,11 @@ func ComplianceResultsQuery(ctx context.Context, logger *zap.Logger, client open
 	integrationID []string, notIntegrationID []string, resourceTypes []string, benchmarkID []string, controlID []string,
 	severity []types.ComplianceResultSeverity, lastTransitionFrom *time.Time, lastTransitionTo *time.Time,
 	evaluatedAtFrom *time.Time, evaluatedAtTo *time.Time, stateActive []bool, complianceStatuses []types.ComplianceStatus,
-	sorts []api.ComplianceResultsSort, pageSizeLimit int, searchAfter []any, jobIDs []string) ([]ComplianceResultsQueryHit, int64, error) {
+	sorts []api.ComplianceResultsSort, pageSizeLimit int, searchAfter []any, jobIDs []string, owners []string, snapshot *ComplianceSnapshotScope) ([]ComplianceResultsQueryHit, int64, error) {
 	idx := types.ComplianceResultsIndex
+	if snapshot != nil {
+		idx = types.ComplianceResultSnapshotsIndex
+	}
 
 	requestSort := make([]map[string]any, 0, len(sorts)+1)
 	for _, sort := range sorts {
@@ -388,11 +391,23 @@ func ComplianceResultsQuery(ctx context.Context, logger *zap.Logger, client open
 	}
 
 	query := make(map[string]any)
+	boolQuery := make(map[string]any)
 	if len(filters) > 0 {
+		boolQuery["filter"] = filters
+	}
+	var must []map[string]any
+	if snapshot != nil {
+		must = append(must, snapshot.filter())
+	}
+	if len(owners) > 0 {
+		must = append(must, ownerFilter(owners))
+	}
+	if len(must) > 0 {
+		boolQuery["must"] = must
+	}
+	if len(boolQuery) > 0 {
 		query["query"] = map[string]any{
-			"bool": map[string]any{
-				"filter": filters,
-			},
+			"bool": boolQuery,
 		}
 	}
 	query["sort"] = requestSort
@@ -722,10 +737,14 @@ type ComplianceResultsTopFieldResponse struct {
 func ComplianceResultsTopFieldQuery(ctx context.Context, logger *zap.Logger, client opengovernance.Client,
 	field string, integrationTypes []string, resourceTypeID []string, integrationIDs []string, notIntegrationIDs []string, jobIDs []string,
 	benchmarkID []string, controlID []string, severity []types.ComplianceResultSeverity, complianceStatuses []types.ComplianceStatus, stateActives []bool,
-	size int, startTime, endTime *time.Time) (*ComplianceResultsTopFieldResponse, error) {
+	size int, startTime, endTime *time.Time, snapshot *ComplianceSnapshotScope) (*ComplianceResultsTopFieldResponse, error) {
 	filters := make([]map[string]any, 0)
 
 	idx := types.ComplianceResultsIndex
+	if snapshot != nil {
+		idx = types.ComplianceResultSnapshotsIndex
+		filters = append(filters, snapshot.filter())
+	}
 	if len(benchmarkID) > 0 {
 		filters = append(filters, map[string]any{
 			"terms": map[string]any{
@@ -0,0 +1,228 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"go.uber.org/zap"
+)
+
+// ComplianceSnapshotPoint is the compliance job holding the state of an integration at a point in time
+type ComplianceSnapshotPoint struct {
+	FrameworkID      string
+	IntegrationID    string
+	ComplianceJobID  uint
+	EvaluatedAtEpoch int64
+}
+
+// ComplianceSnapshotScope selects the snapshot documents of the resolved compliance jobs instead of the live ones
+type ComplianceSnapshotScope struct {
+	Points []ComplianceSnapshotPoint
+}
+
+type complianceSnapshotHits struct {
+	Hits struct {
+		Hits []struct {
+			Source types.ComplianceSnapshot `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type ResolveComplianceSnapshotResponse struct {
+	Aggregations struct {
+		Integrations struct {
+			Buckets []struct {
+				Key        string                 `json:"key"`
+				LastResult complianceSnapshotHits `json:"last_result"`
+				Frameworks struct {
+					Buckets []struct {
+						Key        string                 `json:"key"`
+						LastResult complianceSnapshotHits `json:"last_result"`
+					} `json:"buckets"`
+				} `json:"frameworks"`
+			} `json:"buckets"`
+		} `json:"integrations"`
+	} `json:"aggregations"`
+}
+
+// ResolveComplianceSnapshot finds the latest compliance job of every integration evaluated at or before asOf.
+// If jobID is set the integrations evaluated by that job are returned instead. With perFramework the latest job is
+// resolved per framework and integration, otherwise only per integration.
+func ResolveComplianceSnapshot(ctx context.Context, logger *zap.Logger, client opengovernance.Client,
+	frameworkIDs []string, integrationIDs []string, asOf *time.Time, jobID *uint, perFramework bool) (*ComplianceSnapshotScope, error) {
+	idx := types.ComplianceSnapshotsIndex
+
+	lastResult := map[string]any{
+		"top_hits": map[string]any{
+			"sort": []map[string]any{
+				{"evaluatedAtEpoch": "desc"},
+				{"complianceJobID": "desc"},
+			},
+			"size": 1,
+		},
+	}
+	integrationAggs := map[string]any{
+		"last_result": lastResult,
+	}
+	if perFramework {
+		integrationAggs = map[string]any{
+			"frameworks": map[string]any{
+				"terms": map[string]any{
+					"field": "frameworkID",
+					"size":  10000,
+				},
+				"aggs": map[string]any{
+					"last_result": lastResult,
+				},
+			},
+		}
+	}
+
+	request := map[string]any{
+		"aggs": map[string]any{
+			"integrations": map[string]any{
+				"terms": map[string]any{
+					"field": "integrationIDs",
+					"size":  10000,
+				},
+				"aggs": integrationAggs,
+			},
+		},
+		"size": 0,
+	}
+
+	filters := make([]any, 0)
+	if jobID != nil {
+		filters = append(filters, map[string]any{
+			"term": map[string]any{
+				"complianceJobID": *jobID,
+			},
+		})
+	} else if asOf != nil {
+		filters = append(filters, map[string]any{
+			"range": map[string]any{
+				"evaluatedAtEpoch": map[string]any{
+					"lte": asOf.Unix(),
+				},
+			},
+		})
+	}
+	if len(frameworkIDs) > 0 {
+		filters = append(filters, map[string]any{
+			"terms": map[string][]string{
+				"frameworkID": frameworkIDs,
+			},
+		})
+	}
+	if len(integrationIDs) > 0 {
+		filters = append(filters, map[string]any{
+			"terms": map[string][]string{
+				"integrationIDs": integrationIDs,
+			},
+		})
+	}
+	request["query"] = map[string]any{
+		"bool": map[string]any{
+			"filter": filters,
+		},
+	}
+
+	query, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("ResolveComplianceSnapshot", zap.String("query", string(query)), zap.String("index", idx))
+
+	var response ResolveComplianceSnapshotResponse
+	err = client.Search(ctx, idx, string(query), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedIntegrations := make(map[string]bool)
+	for _, integrationID := range integrationIDs {
+		requestedIntegrations[integrationID] = true
+	}
+
+	var points []ComplianceSnapshotPoint
+	addPoint := func(integrationID string, hits complianceSnapshotHits) {
+		for _, hit := range hits.Hits.Hits {
+			points = append(points, ComplianceSnapshotPoint{
+				FrameworkID:      hit.Source.FrameworkID,
+				IntegrationID:    integrationID,
+				ComplianceJobID:  hit.Source.ComplianceJobID,
+				EvaluatedAtEpoch: hit.Source.EvaluatedAtEpoch,
+			})
+		}
+	}
+	for _, integration := range response.Aggregations.Integrations.Buckets {
+		if len(requestedIntegrations) > 0 && !requestedIntegrations[integration.Key] {
+			continue
+		}
+		if perFramework {
+			for _, framework := range integration.Frameworks.Buckets {
+				addPoint(integration.Key, framework.LastResult)
+			}
+		} else {
+			addPoint(integration.Key, integration.LastResult)
+		}
+	}
+	return &ComplianceSnapshotScope{Points: points}, nil
+}
+
+// filter matches the snapshot documents written for the resolved compliance jobs, an empty scope matches nothing
+func (s ComplianceSnapshotScope) filter() map[string]any {
+	if len(s.Points) == 0 {
+		return map[string]any{
+			"bool": map[string]any{
+				"must_not": map[string]any{
+					"match_all": map[string]any{},
+				},
+			},
+		}
+	}
+
+	should := make([]any, 0, len(s.Points))
+	for _, point := range s.Points {
+		should = append(should, map[string]any{
+			"bool": map[string]any{
+				"filter": []any{
+					map[string]any{"term": map[string]any{"integrationID": point.IntegrationID}},
+					map[string]any{"term": map[string]any{"complianceJobID": point.ComplianceJobID}},
+				},
+			},
+		})
+	}
+	return map[string]any{
+		"bool": map[string]any{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}
+}
+
+// ListComplianceSnapshotResults returns all the active compliance results of the benchmark in the snapshot
+func ListComplianceSnapshotResults(ctx context.Context, logger *zap.Logger, client opengovernance.Client,
+	snapshot *ComplianceSnapshotScope, benchmarkIDs []string, integrationIDs []string) ([]types.ComplianceResult, error) {
+	var results []types.ComplianceResult
+	var searchAfter []any
+	for {
+		hits, _, err := ComplianceResultsQuery(ctx, logger, client, nil, nil, integrationIDs, nil, nil, benchmarkIDs,
+			nil, nil, nil, nil, nil, nil, []bool{true}, nil, nil, 1000, searchAfter, nil, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range hits {
+			results = append(results, hit.Source)
+		}
+		if len(hits) < 1000 {
+			break
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+	return results, nil
+}
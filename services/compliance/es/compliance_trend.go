@@ -0,0 +1,211 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"go.uber.org/zap"
+)
+
+// ListFrameworkComplianceSnapshots returns the latest compliance snapshots of the framework evaluated in the time range,
+// oldest first. If integrationIDs is set only the snapshots that evaluated one of them are returned.
+func ListFrameworkComplianceSnapshots(ctx context.Context, logger *zap.Logger, client opengovernance.Client,
+	frameworkID string, integrationIDs []string, startTime, endTime time.Time, size int) ([]types.ComplianceSnapshot, error) {
+	idx := types.ComplianceSnapshotsIndex
+
+	filters := []any{
+		map[string]any{
+			"term": map[string]any{
+				"frameworkID": frameworkID,
+			},
+		},
+		map[string]any{
+			"range": map[string]any{
+				"evaluatedAtEpoch": map[string]any{
+					"gte": startTime.Unix(),
+					"lte": endTime.Unix(),
+				},
+			},
+		},
+	}
+	if len(integrationIDs) > 0 {
+		filters = append(filters, map[string]any{
+			"terms": map[string][]string{
+				"integrationIDs": integrationIDs,
+			},
+		})
+	}
+	request := map[string]any{
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": filters,
+			},
+		},
+		"sort": []map[string]any{
+			{"evaluatedAtEpoch": "desc"},
+			{"complianceJobID": "desc"},
+		},
+		"size": size,
+	}
+
+	query, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("ListFrameworkComplianceSnapshots", zap.String("query", string(query)), zap.String("index", idx))
+
+	var response complianceSnapshotHits
+	err = client.Search(ctx, idx, string(query), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]types.ComplianceSnapshot, 0, len(response.Hits.Hits))
+	for i := len(response.Hits.Hits) - 1; i >= 0; i-- {
+		snapshots = append(snapshots, response.Hits.Hits[i].Source)
+	}
+	return snapshots, nil
+}
+
+// ComplianceStatusCounts is the number of passed and failed results of a control or an integration
+type ComplianceStatusCounts struct {
+	Passed int64
+	Failed int64
+}
+
+// ComplianceJobTrendBreakdown is the result of a compliance job broken down by control and by integration
+type ComplianceJobTrendBreakdown struct {
+	Controls     map[string]ComplianceStatusCounts
+	Integrations map[string]ComplianceStatusCounts
+}
+
+type complianceStatusBuckets struct {
+	Buckets []struct {
+		Key      string `json:"key"`
+		DocCount int64  `json:"doc_count"`
+	} `json:"buckets"`
+}
+
+func (b complianceStatusBuckets) counts() ComplianceStatusCounts {
+	var counts ComplianceStatusCounts
+	for _, status := range b.Buckets {
+		switch types.ComplianceStatus(status.Key) {
+		case types.ComplianceStatusOK:
+			counts.Passed += status.DocCount
+		case types.ComplianceStatusALARM:
+			counts.Failed += status.DocCount
+		}
+	}
+	return counts
+}
+
+type ComplianceJobTrendBreakdownResponse struct {
+	Aggregations struct {
+		Controls struct {
+			Buckets []struct {
+				Key      string                  `json:"key"`
+				Statuses complianceStatusBuckets `json:"statuses"`
+			} `json:"buckets"`
+		} `json:"controls"`
+		Integrations struct {
+			Buckets []struct {
+				Key      string                  `json:"key"`
+				Statuses complianceStatusBuckets `json:"statuses"`
+			} `json:"buckets"`
+		} `json:"integrations"`
+	} `json:"aggregations"`
+}
+
+// GetComplianceJobTrendBreakdown counts the snapshot results of the compliance job per control and per integration
+func GetComplianceJobTrendBreakdown(ctx context.Context, logger *zap.Logger, client opengovernance.Client,
+	complianceJobID uint, integrationIDs []string, controlIDs []string) (*ComplianceJobTrendBreakdown, error) {
+	idx := types.ComplianceResultSnapshotsIndex
+
+	statuses := map[string]any{
+		"statuses": map[string]any{
+			"terms": map[string]any{
+				"field": "complianceStatus",
+				"size":  10,
+			},
+		},
+	}
+	filters := []any{
+		map[string]any{
+			"term": map[string]any{
+				"complianceJobID": complianceJobID,
+			},
+		},
+		map[string]any{
+			"term": map[string]any{
+				"stateActive": true,
+			},
+		},
+	}
+	if len(integrationIDs) > 0 {
+		filters = append(filters, map[string]any{
+			"terms": map[string][]string{
+				"integrationID": integrationIDs,
+			},
+		})
+	}
+	if len(controlIDs) > 0 {
+		filters = append(filters, map[string]any{
+			"terms": map[string][]string{
+				"controlID": controlIDs,
+			},
+		})
+	}
+	request := map[string]any{
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": filters,
+			},
+		},
+		"aggs": map[string]any{
+			"controls": map[string]any{
+				"terms": map[string]any{
+					"field": "controlID",
+					"size":  10000,
+				},
+				"aggs": statuses,
+			},
+			"integrations": map[string]any{
+				"terms": map[string]any{
+					"field": "integrationID",
+					"size":  10000,
+				},
+				"aggs": statuses,
+			},
+		},
+		"size": 0,
+	}
+
+	query, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("GetComplianceJobTrendBreakdown", zap.String("query", string(query)), zap.String("index", idx))
+
+	var response ComplianceJobTrendBreakdownResponse
+	err = client.Search(ctx, idx, string(query), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := ComplianceJobTrendBreakdown{
+		Controls:     make(map[string]ComplianceStatusCounts),
+		Integrations: make(map[string]ComplianceStatusCounts),
+	}
+	for _, control := range response.Aggregations.Controls.Buckets {
+		breakdown.Controls[control.Key] = control.Statuses.counts()
+	}
+	for _, integration := range response.Aggregations.Integrations.Buckets {
+		breakdown.Integrations[integration.Key] = integration.Statuses.counts()
+	}
+	return &breakdown, nil
+}
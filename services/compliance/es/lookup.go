@@ -144,3 +144,185 @@ func FetchResourceByResourceIdAndType(ctx context.Context, client opengovernance
 
 	return &response.Hits.Hits[0].Source, nil
 }
+
+type LookupPlatformIDsResponse struct {
+	Aggregations struct {
+		Resources struct {
+			Buckets []struct {
+				Key string `json:"key"`
+			} `json:"buckets"`
+		} `json:"resources"`
+	} `json:"aggregations"`
+}
+
+// FilterLookupResourcesByQuery returns the platform ids among platformResourceIDs of the lookup resources
+// matching the query
+func FilterLookupResourcesByQuery(ctx context.Context, client opengovernance.Client, platformResourceIDs []string, query map[string]any) (map[string]bool, error) {
+	if len(platformResourceIDs) == 0 {
+		return nil, nil
+	}
+	request := make(map[string]any)
+	request["size"] = 0
+	request["query"] = map[string]any{
+		"bool": map[string]any{
+			"filter": []any{
+				map[string]any{
+					"terms": map[string]any{
+						"platform_id": platformResourceIDs,
+					},
+				},
+				query,
+			},
+		},
+	}
+	request["aggs"] = map[string]any{
+		"resources": map[string]any{
+			"terms": map[string]any{
+				"field": "platform_id",
+				"size":  len(platformResourceIDs),
+			},
+		},
+	}
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response LookupPlatformIDsResponse
+	err = client.Search(ctx, InventorySummaryIndex, string(b), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[string]bool, len(response.Aggregations.Resources.Buckets))
+	for _, bucket := range response.Aggregations.Resources.Buckets {
+		matched[bucket.Key] = true
+	}
+	return matched, nil
+}
+
+type LookupTagsResponse struct {
+	Hits struct {
+		Hits []struct {
+			Source struct {
+				PlatformID    string `json:"platform_id"`
+				CanonicalTags []struct {
+					Key   string `json:"key"`
+					Value string `json:"value"`
+				} `json:"canonical_tags"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// GetLookupResourceTagValues returns the value of a tag on the lookup resources among platformResourceIDs
+// having it
+func GetLookupResourceTagValues(ctx context.Context, client opengovernance.Client, platformResourceIDs []string, tagKey string) (map[string]string, error) {
+	if len(platformResourceIDs) == 0 {
+		return nil, nil
+	}
+	request := make(map[string]any)
+	request["size"] = len(platformResourceIDs)
+	request["_source"] = []string{"platform_id", "canonical_tags"}
+	request["query"] = map[string]any{
+		"bool": map[string]any{
+			"filter": []any{
+				map[string]any{
+					"terms": map[string]any{
+						"platform_id": platformResourceIDs,
+					},
+				},
+				map[string]any{
+					"nested": map[string]any{
+						"path": "canonical_tags",
+						"query": map[string]any{
+							"term": map[string]string{"canonical_tags.key": tagKey},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response LookupTagsResponse
+	err = client.Search(ctx, InventorySummaryIndex, string(b), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		for _, tag := range hit.Source.CanonicalTags {
+			if tag.Key == tagKey {
+				values[hit.Source.PlatformID] = tag.Value
+				break
+			}
+		}
+	}
+	return values, nil
+}
+
+// GetLookupResourceTags returns the tags with one of the keys of the lookup resources among platformResourceIDs
+// having any of them
+func GetLookupResourceTags(ctx context.Context, client opengovernance.Client, platformResourceIDs []string, tagKeys []string) (map[string]map[string]string, error) {
+	if len(platformResourceIDs) == 0 || len(tagKeys) == 0 {
+		return nil, nil
+	}
+	request := make(map[string]any)
+	request["size"] = len(platformResourceIDs)
+	request["_source"] = []string{"platform_id", "canonical_tags"}
+	request["query"] = map[string]any{
+		"bool": map[string]any{
+			"filter": []any{
+				map[string]any{
+					"terms": map[string]any{
+						"platform_id": platformResourceIDs,
+					},
+				},
+				map[string]any{
+					"nested": map[string]any{
+						"path": "canonical_tags",
+						"query": map[string]any{
+							"terms": map[string][]string{"canonical_tags.key": tagKeys},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response LookupTagsResponse
+	err = client.Search(ctx, InventorySummaryIndex, string(b), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(tagKeys))
+	for _, key := range tagKeys {
+		keys[key] = true
+	}
+	tags := make(map[string]map[string]string, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		for _, tag := range hit.Source.CanonicalTags {
+			if !keys[tag.Key] {
+				continue
+			}
+			if _, ok := tags[hit.Source.PlatformID]; !ok {
+				tags[hit.Source.PlatformID] = make(map[string]string)
+			}
+			tags[hit.Source.PlatformID][tag.Key] = tag.Value
+		}
+	}
+	return tags, nil
+}
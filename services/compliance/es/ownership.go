@@ -0,0 +1,194 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/compliance/ownership"
+	"go.uber.org/zap"
+)
+
+type keyCountBuckets struct {
+	Buckets []struct {
+		Key      string `json:"key"`
+		DocCount int    `json:"doc_count"`
+	} `json:"buckets"`
+}
+
+type OwnerScoresResponse struct {
+	Aggregations struct {
+		Owners struct {
+			Buckets []struct {
+				Key       string `json:"key"`
+				Resources struct {
+					Value int `json:"value"`
+				} `json:"resources"`
+				Statuses keyCountBuckets `json:"statuses"`
+				Failed   struct {
+					Severities keyCountBuckets `json:"severities"`
+					Breached   struct {
+						DocCount int `json:"doc_count"`
+					} `json:"breached"`
+				} `json:"failed"`
+			} `json:"buckets"`
+		} `json:"owners"`
+	} `json:"aggregations"`
+}
+
+// GetOwnerScores counts the active results of every owner, results without an owner are counted as unassigned
+func GetOwnerScores(ctx context.Context, logger *zap.Logger, client opengovernance.Client, integrationIDs []string,
+	benchmarkIDs []string, now time.Time) ([]ownership.Score, error) {
+	idx := types.ComplianceResultsIndex
+
+	var failedStatuses []string
+	for _, status := range types.GetFailedComplianceStatuses() {
+		failedStatuses = append(failedStatuses, string(status))
+	}
+	filters := []any{
+		map[string]any{
+			"term": map[string]any{
+				"stateActive": true,
+			},
+		},
+	}
+	if len(integrationIDs) > 0 {
+		filters = append(filters, map[string]any{
+			"terms": map[string][]string{
+				"integrationID": integrationIDs,
+			},
+		})
+	}
+	if len(benchmarkIDs) > 0 {
+		filters = append(filters, map[string]any{
+			"terms": map[string][]string{
+				"benchmarkID": benchmarkIDs,
+			},
+		})
+	}
+	request := map[string]any{
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": filters,
+			},
+		},
+		"aggs": map[string]any{
+			"owners": map[string]any{
+				"terms": map[string]any{
+					"field":   "owner",
+					"size":    10000,
+					"missing": ownership.Unassigned,
+				},
+				"aggs": map[string]any{
+					"resources": map[string]any{
+						"cardinality": map[string]any{
+							"field": "platformResourceID",
+						},
+					},
+					"statuses": map[string]any{
+						"terms": map[string]any{
+							"field": "complianceStatus",
+							"size":  10,
+						},
+					},
+					"failed": map[string]any{
+						"filter": map[string]any{
+							"terms": map[string][]string{
+								"complianceStatus": failedStatuses,
+							},
+						},
+						"aggs": map[string]any{
+							"severities": map[string]any{
+								"terms": map[string]any{
+									"field": "severity",
+									"size":  10,
+								},
+							},
+							"breached": map[string]any{
+								"filter": map[string]any{
+									"range": map[string]any{
+										"slaDueAt": map[string]any{
+											"lt": now.UnixMilli(),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"size": 0,
+	}
+
+	query, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("GetOwnerScores", zap.String("query", string(query)), zap.String("index", idx))
+
+	var response OwnerScoresResponse
+	err = client.Search(ctx, idx, string(query), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]ownership.Score, 0, len(response.Aggregations.Owners.Buckets))
+	for _, bucket := range response.Aggregations.Owners.Buckets {
+		score := ownership.Score{
+			Owner:            bucket.Key,
+			Resources:        bucket.Resources.Value,
+			FailedBySeverity: make(map[types.ComplianceResultSeverity]int),
+			SLABreached:      bucket.Failed.Breached.DocCount,
+		}
+		for _, status := range bucket.Statuses.Buckets {
+			if types.ComplianceStatus(status.Key).IsPassed() {
+				score.Passed += status.DocCount
+			} else {
+				score.Failed += status.DocCount
+			}
+		}
+		for _, severity := range bucket.Failed.Severities.Buckets {
+			score.FailedBySeverity[types.ComplianceResultSeverity(severity.Key)] += severity.DocCount
+		}
+		scores = append(scores, score)
+	}
+	return scores, nil
+}
+
+// ownerFilter matches the docs of the owners, ownership.Unassigned matches the docs without an owner
+func ownerFilter(owners []string) map[string]any {
+	var assigned []string
+	var should []map[string]any
+	for _, owner := range owners {
+		if owner == ownership.Unassigned {
+			should = append(should, map[string]any{
+				"bool": map[string]any{
+					"must_not": map[string]any{
+						"exists": map[string]any{
+							"field": "owner",
+						},
+					},
+				},
+			})
+			continue
+		}
+		assigned = append(assigned, owner)
+	}
+	if len(assigned) > 0 {
+		should = append(should, map[string]any{
+			"terms": map[string]any{
+				"owner": assigned,
+			},
+		})
+	}
+	return map[string]any{
+		"bool": map[string]any{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}
+}
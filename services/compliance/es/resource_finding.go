@@ -83,7 +83,11 @@ func (p ResourceFindingPaginator) NextPage(ctx context.Context) ([]types.Resourc
 func ResourceFindingsQuery(ctx context.Context, logger *zap.Logger, client opengovernance.Client, integrationType []integration.Type, integrationID []string,
 	notIntegrationID []string, resourceCollection []string, resourceTypes []string, benchmarkID []string, controlID []string,
 	severity []types.ComplianceResultSeverity, evaluatedAtFrom *time.Time, evaluatedAtTo *time.Time, complianceStatuses []types.ComplianceStatus,
-	sorts []api.ResourceFindingsSort, pageSizeLimit int, searchAfter []any, summaryJobIDs []string) ([]ResourceFindingsQueryHit, int64, error) {
+	sorts []api.ResourceFindingsSort, pageSizeLimit int, searchAfter []any, summaryJobIDs []string, owners []string, snapshot *ComplianceSnapshotScope) ([]ResourceFindingsQueryHit, int64, error) {
+	idx := types.ResourceFindingsIndex
+	if snapshot != nil {
+		idx = types.ResourceFindingSnapshotsIndex
+	}
 
 	nestedFilters := make([]map[string]any, 0)
 	if len(integrationType) > 0 {
@@ -143,6 +147,9 @@ func ResourceFindingsQuery(ctx context.Context, logger *zap.Logger, client openg
 	}
 
 	filters := make([]map[string]any, 0)
+	if snapshot != nil {
+		filters = append(filters, snapshot.filter())
+	}
 	if len(resourceTypes) > 0 {
 		filters = append(filters, map[string]any{
 			"terms": map[string]any{
@@ -157,6 +164,9 @@ func ResourceFindingsQuery(ctx context.Context, logger *zap.Logger, client openg
 			},
 		})
 	}
+	if len(owners) > 0 {
+		filters = append(filters, ownerFilter(owners))
+	}
 	if evaluatedAtFrom != nil && evaluatedAtTo != nil {
 		filters = append(filters, map[string]any{
 			"range": map[string]any{
@@ -263,10 +273,10 @@ return total;`, types.ComplianceStatusOK, types.ComplianceStatusINFO, types.Comp
 		logger.Error("resourceFindingsQuery - failed to marshal request", zap.Error(err), zap.Any("request", requestMap))
 		return nil, 0, err
 	}
-	logger.Info("ResourceFindingsQuery", zap.String("request", string(request)), zap.String("index", types.ResourceFindingsIndex))
+	logger.Info("ResourceFindingsQuery", zap.String("request", string(request)), zap.String("index", idx))
 
 	var response ResourceFindingsQueryResponse
-	err = client.SearchWithTrackTotalHits(ctx, types.ResourceFindingsIndex, string(request), nil, &response, true)
+	err = client.SearchWithTrackTotalHits(ctx, idx, string(request), nil, &response, true)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -298,9 +308,14 @@ type GetPerBenchmarkResourceSeverityResultResponse struct {
 
 func GetPerBenchmarkResourceSeverityResult(ctx context.Context, logger *zap.Logger, client opengovernance.Client,
 	benchmarkIDs []string, integrationIDs []string, resourceCollections []string,
-	severities []types.ComplianceResultSeverity, complianceStatuses []types.ComplianceStatus) (map[string]types.SeverityResultWithTotal, error) {
+	severities []types.ComplianceResultSeverity, complianceStatuses []types.ComplianceStatus, snapshot *ComplianceSnapshotScope) (map[string]types.SeverityResultWithTotal, error) {
+	idx := types.ResourceFindingsIndex
 	request := make(map[string]any)
 	filters := make([]map[string]any, 0)
+	if snapshot != nil {
+		idx = types.ResourceFindingSnapshotsIndex
+		filters = append(filters, snapshot.filter())
+	}
 	nestedFilters := make([]map[string]any, 0)
 	if len(benchmarkIDs) > 0 {
 		nestedFilters = append(nestedFilters, map[string]any{
@@ -401,11 +416,11 @@ func GetPerBenchmarkResourceSeverityResult(ctx context.Context, logger *zap.Logg
 		logger.Error("GetPerBenchmarkResourceSeverityResult", zap.Error(err), zap.Any("request", request))
 	}
 
-	logger.Info("GetPerBenchmarkResourceSeverityResult", zap.String("query", string(query)), zap.String("index", types.ResourceFindingsIndex))
+	logger.Info("GetPerBenchmarkResourceSeverityResult", zap.String("query", string(query)), zap.String("index", idx))
 	var response GetPerBenchmarkResourceSeverityResultResponse
-	err = client.Search(ctx, types.ResourceFindingsIndex, string(query), &response)
+	err = client.Search(ctx, idx, string(query), &response)
 	if err != nil {
-		logger.Error("GetPerBenchmarkResourceSeverityResult", zap.Error(err), zap.String("query", string(query)), zap.String("index", types.ResourceFindingsIndex))
+		logger.Error("GetPerBenchmarkResourceSeverityResult", zap.Error(err), zap.String("query", string(query)), zap.String("index", idx))
 		return nil, err
 	}
 
@@ -0,0 +1,53 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"go.uber.org/zap"
+)
+
+type ResourceRelationshipsQueryResponse struct {
+	Hits struct {
+		Total opengovernance.SearchTotal `json:"total"`
+		Hits  []struct {
+			ID     string                     `json:"_id"`
+			Source types.ResourceRelationship `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// ListResourceRelationshipsTouching returns the edges of which any of the resources is the source or the target
+func ListResourceRelationshipsTouching(ctx context.Context, logger *zap.Logger, client opengovernance.Client, platformIDs []string, size int) ([]types.ResourceRelationship, error) {
+	root := map[string]any{}
+	root["query"] = map[string]any{
+		"bool": map[string]any{
+			"should": []map[string]any{
+				{"terms": map[string][]string{"source.platformID": platformIDs}},
+				{"terms": map[string][]string{"target.platformID": platformIDs}},
+			},
+			"minimum_should_match": 1,
+		},
+	}
+	root["size"] = size
+
+	queryBytes, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ResourceRelationshipsQueryResponse
+	err = client.Search(ctx, types.ResourceRelationshipsIndex, string(queryBytes), &response)
+	if err != nil {
+		logger.Error("failed to query resource relationships", zap.Error(err), zap.String("query", string(queryBytes)))
+		return nil, err
+	}
+
+	edges := make([]types.ResourceRelationship, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		edges = append(edges, hit.Source)
+	}
+	return edges, nil
+}
@@ -3,9 +3,11 @@ package compliance
 import (
 	"context"
 	"fmt"
+	"time"
 
 	helmv2 "github.com/fluxcd/helm-controller/api/v2beta1"
 	"github.com/opengovern/opencomply/jobs/post-install-job/db/model"
+	"github.com/opengovern/opencomply/pkg/audit"
 	integrationClient "github.com/opengovern/opencomply/services/integration/client"
 	metadataClient "github.com/opengovern/opencomply/services/metadata/client"
 	"github.com/sashabaranov/go-openai"
@@ -39,6 +41,7 @@ type HttpHandler struct {
 	metadataClient    metadataClient.MetadataServiceClient
 	openAIClient      *openai.Client
 	kubeClient        client.Client
+	auditRecorder     *audit.Recorder
 }
 
 func NewKubeClient() (client.Client, error) {
@@ -111,6 +114,11 @@ func InitializeHttpHandler(
 	}
 	fmt.Println("Initialized postgres database: ", conf.PostgreSQL.DB)
 
+	// reports still generating past the generation timeout were interrupted by a restart
+	if err := h.db.FailInterruptedReports(ctx, time.Now().Add(-reportGenerationTimeout)); err != nil {
+		return nil, fmt.Errorf("fail interrupted reports: %w", err)
+	}
+
 	h.client, err = opengovernance.NewClient(opengovernance.ClientConfig{
 		Addresses:     []string{conf.ElasticSearch.Address},
 		Username:      &conf.ElasticSearch.Username,
@@ -146,6 +154,7 @@ func InitializeHttpHandler(
 	h.inventoryClient = inventoryClient.NewInventoryServiceClient(conf.Inventory.BaseURL)
 	h.metadataClient = metadataClient.NewMetadataServiceClient(conf.Metadata.BaseURL)
 	h.openAIClient = openai.NewClient(conf.OpenAI.Token)
+	h.auditRecorder = audit.NewRecorder(logger, "compliance", conf.EsSink.BaseURL)
 
 	kubeClient, err := NewKubeClient()
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -26,12 +27,17 @@ import (
 	runner "github.com/opengovern/opencomply/jobs/compliance-runner-job"
 	"github.com/opengovern/opencomply/jobs/compliance-summarizer-job/types"
 	model2 "github.com/opengovern/opencomply/jobs/post-install-job/db/model"
+	"github.com/opengovern/opencomply/pkg/audit"
 	opengovernanceTypes "github.com/opengovern/opencomply/pkg/types"
 	types2 "github.com/opengovern/opencomply/pkg/types"
 	"github.com/opengovern/opencomply/pkg/utils"
 	"github.com/opengovern/opencomply/services/compliance/api"
 	"github.com/opengovern/opencomply/services/compliance/db"
 	"github.com/opengovern/opencomply/services/compliance/es"
+	"github.com/opengovern/opencomply/services/compliance/ownership"
+	"github.com/opengovern/opencomply/services/compliance/report"
+	"github.com/opengovern/opencomply/services/compliance/risk"
+	"github.com/opengovern/opencomply/services/compliance/trend"
 	schedulerapi "github.com/opengovern/opencomply/services/describe/api"
 	integrationapi "github.com/opengovern/opencomply/services/integration/api/models"
 	integration_type "github.com/opengovern/opencomply/services/integration/integration-type"
@@ -55,14 +61,18 @@ const (
 )
 
 func (h *HttpHandler) Register(e *echo.Echo) {
+	auditLog := h.auditRecorder.Middleware()
+
 	v1 := e.Group("/api/v1")
 
 	benchmarks := v1.Group("/benchmarks")
 
 	benchmarks.GET("", httpserver2.AuthorizeHandler(h.ListBenchmarks, authApi.ViewerRole))
 	benchmarks.GET("/all", httpserver2.AuthorizeHandler(h.ListAllBenchmarks, authApi.AdminRole))
+	benchmarks.GET("/version", httpserver2.AuthorizeHandler(h.GetFrameworksVersion, authApi.ViewerRole))
+	benchmarks.GET("/:benchmark_id/tree", httpserver2.AuthorizeHandler(h.GetBenchmarkTree, authApi.ViewerRole))
 	benchmarks.GET("/:benchmark_id", httpserver2.AuthorizeHandler(h.GetBenchmark, authApi.ViewerRole))
-	benchmarks.POST("/:benchmark_id/settings", httpserver2.AuthorizeHandler(h.ChangeBenchmarkSettings, authApi.AdminRole))
+	benchmarks.POST("/:benchmark_id/settings", httpserver2.AuthorizeHandler(h.ChangeBenchmarkSettings, authApi.AdminRole), auditLog)
 	benchmarks.GET("/controls/:control_id", httpserver2.AuthorizeHandler(h.GetControl, authApi.ViewerRole))
 	benchmarks.GET("/controls", httpserver2.AuthorizeHandler(h.ListControls, authApi.AdminRole))
 	benchmarks.GET("/queries", httpserver2.AuthorizeHandler(h.ListQueries, authApi.AdminRole))
@@ -102,14 +112,24 @@ func (h *HttpHandler) Register(e *echo.Echo) {
 	v3.GET("/benchmarks/filters", httpserver2.AuthorizeHandler(h.ListBenchmarksFilters, authApi.ViewerRole))
 	v3.POST("/benchmark/:benchmark_id", httpserver2.AuthorizeHandler(h.GetBenchmarkDetails, authApi.ViewerRole))
 	v3.GET("/benchmark/:benchmark_id/assignments", httpserver2.AuthorizeHandler(h.GetBenchmarkAssignments, authApi.ViewerRole))
-	v3.POST("/benchmark/:benchmark_id/assign", httpserver2.AuthorizeHandler(h.AssignBenchmarkToIntegration, authApi.ViewerRole))
+	v3.POST("/benchmark/:benchmark_id/assign", httpserver2.AuthorizeHandler(h.AssignBenchmarkToIntegration, authApi.ViewerRole), auditLog)
+	v3.POST("/benchmark/:benchmark_id/assign/resource-collection/:resource_collection_id", httpserver2.AuthorizeHandler(h.AssignBenchmarkToResourceCollection, authApi.EditorRole), auditLog)
+	v3.DELETE("/benchmark/:benchmark_id/assign/resource-collection/:resource_collection_id", httpserver2.AuthorizeHandler(h.UnassignBenchmarkFromResourceCollection, authApi.EditorRole), auditLog)
+	v3.GET("/resource-collection/:resource_collection_id/assignments", httpserver2.AuthorizeHandler(h.ListResourceCollectionAssignments, authApi.ViewerRole))
 	v3.POST("/compliance/summary/benchmark", httpserver2.AuthorizeHandler(h.ComplianceSummaryOfBenchmark, authApi.ViewerRole))
 	v3.POST("/benchmarks/:benchmark_id/trend", httpserver2.AuthorizeHandler(h.GetBenchmarkTrendV3, authApi.ViewerRole))
+	v3.POST("/benchmarks/:benchmark_id/trend/analytics", httpserver2.AuthorizeHandler(h.GetBenchmarkTrendAnalytics, authApi.ViewerRole))
 
 	v3.POST("/controls", httpserver2.AuthorizeHandler(h.ListControlsFiltered, authApi.ViewerRole))
 	v3.GET("/parameters/controls", httpserver2.AuthorizeHandler(h.GetParametersControls, authApi.ViewerRole))
 	v3.GET("/controls/filters", httpserver2.AuthorizeHandler(h.ListControlsFilters, authApi.ViewerRole))
 	v3.GET("/control/:control_id", httpserver2.AuthorizeHandler(h.GetControlDetails, authApi.ViewerRole))
+	v3.GET("/controls/:control_id/severity-overrides", httpserver2.AuthorizeHandler(h.ListControlSeverityOverrides, authApi.ViewerRole))
+	v3.POST("/controls/:control_id/severity-overrides", httpserver2.AuthorizeHandler(h.CreateControlSeverityOverride, authApi.EditorRole), auditLog)
+	v3.GET("/controls/:control_id/severity-overrides/history", httpserver2.AuthorizeHandler(h.ListControlSeverityOverrideHistory, authApi.ViewerRole))
+	v3.GET("/controls/:control_id/severity-overrides/resolved", httpserver2.AuthorizeHandler(h.ListResolvedControlSeverityOverrides, authApi.AdminRole))
+	v3.PUT("/controls/:control_id/severity-overrides/:override_id", httpserver2.AuthorizeHandler(h.UpdateControlSeverityOverride, authApi.EditorRole), auditLog)
+	v3.DELETE("/controls/:control_id/severity-overrides/:override_id", httpserver2.AuthorizeHandler(h.DeleteControlSeverityOverride, authApi.EditorRole), auditLog)
 
 
 	v3.GET("/benchmarks/:benchmark_id/nested", httpserver2.AuthorizeHandler(h.ListBenchmarksNestedForBenchmark, authApi.ViewerRole))
@@ -119,6 +139,38 @@ func (h *HttpHandler) Register(e *echo.Echo) {
 
 	v3.GET("/job-report/:run_id/details/by-control", httpserver2.AuthorizeHandler(h.GetComplianceJobReport, authApi.ViewerRole))
 	v3.GET("/job-report/:run_id/summary", httpserver2.AuthorizeHandler(h.GetJobReportSummary, authApi.ViewerRole))
+
+	v3.POST("/compliance/snapshots/compare", httpserver2.AuthorizeHandler(h.CompareComplianceSnapshots, authApi.ViewerRole))
+
+	v3.POST("/risk/attack-paths", httpserver2.AuthorizeHandler(h.ListAttackPaths, authApi.ViewerRole))
+	v3.POST("/risk/finding-priorities", httpserver2.AuthorizeHandler(h.ListFindingPriorities, authApi.ViewerRole))
+
+	v3.GET("/sla/policies", httpserver2.AuthorizeHandler(h.ListSLAPolicies, authApi.ViewerRole))
+	v3.PUT("/sla/policies", httpserver2.AuthorizeHandler(h.PutSLAPolicies, authApi.AdminRole), auditLog)
+	v3.POST("/sla/ageing-report", httpserver2.AuthorizeHandler(h.GetAgeingReport, authApi.ViewerRole))
+
+	v3.GET("/ownership/rules", httpserver2.AuthorizeHandler(h.ListOwnershipRules, authApi.ViewerRole))
+	v3.POST("/ownership/rules", httpserver2.AuthorizeHandler(h.CreateOwnershipRule, authApi.AdminRole), auditLog)
+	v3.GET("/ownership/rules/resolved", httpserver2.AuthorizeHandler(h.ListResolvedOwnershipRules, authApi.AdminRole))
+	v3.PUT("/ownership/rules/:rule_id", httpserver2.AuthorizeHandler(h.UpdateOwnershipRule, authApi.AdminRole), auditLog)
+	v3.DELETE("/ownership/rules/:rule_id", httpserver2.AuthorizeHandler(h.DeleteOwnershipRule, authApi.AdminRole), auditLog)
+	v3.POST("/ownership/scorecard", httpserver2.AuthorizeHandler(h.GetOwnerScorecard, authApi.ViewerRole))
+
+	reports := v3.Group("/reports")
+	reports.POST("", httpserver2.AuthorizeHandler(h.GenerateReport, authApi.ViewerRole), auditLog)
+	reports.GET("", httpserver2.AuthorizeHandler(h.ListReports, authApi.ViewerRole))
+	reports.GET("/templates", httpserver2.AuthorizeHandler(h.ListReportTemplates, authApi.ViewerRole))
+	reports.GET("/templates/:framework_id", httpserver2.AuthorizeHandler(h.GetReportTemplate, authApi.ViewerRole))
+	reports.PUT("/templates/:framework_id", httpserver2.AuthorizeHandler(h.PutReportTemplate, authApi.AdminRole), auditLog)
+	reports.DELETE("/templates/:framework_id", httpserver2.AuthorizeHandler(h.DeleteReportTemplate, authApi.AdminRole), auditLog)
+	reports.GET("/schedules", httpserver2.AuthorizeHandler(h.ListReportSchedules, authApi.ViewerRole))
+	reports.POST("/schedules", httpserver2.AuthorizeHandler(h.CreateReportSchedule, authApi.AdminRole), auditLog)
+	reports.PUT("/schedules/:schedule_id", httpserver2.AuthorizeHandler(h.UpdateReportSchedule, authApi.AdminRole), auditLog)
+	reports.DELETE("/schedules/:schedule_id", httpserver2.AuthorizeHandler(h.DeleteReportSchedule, authApi.AdminRole), auditLog)
+	reports.POST("/compliance-jobs/:job_id/scheduled", httpserver2.AuthorizeHandler(h.GenerateScheduledReports, authApi.AdminRole))
+	reports.GET("/:report_id", httpserver2.AuthorizeHandler(h.GetReport, authApi.ViewerRole))
+	reports.GET("/:report_id/download", httpserver2.AuthorizeHandler(h.DownloadReport, authApi.ViewerRole))
+	reports.DELETE("/:report_id", httpserver2.AuthorizeHandler(h.DeleteReport, authApi.AdminRole), auditLog)
 }
 
 func bindValidate(ctx echo.Context, i any) error {
@@ -264,10 +316,15 @@ func (h *HttpHandler) GetComplianceResults(echoCtx echo.Context) error {
 		allSourcesMap[src.IntegrationID] = &src
 	}
 
+	snapshot, err := h.resolveComplianceSnapshot(ctx, req.Filters.BenchmarkID, req.Filters.IntegrationID, req.AsOf, req.AsOfJobID, true)
+	if err != nil {
+		return err
+	}
+
 	res, totalCount, err := es.ComplianceResultsQuery(ctx, h.logger, h.client, req.Filters.ResourceID, req.Filters.IntegrationType,
 		req.Filters.IntegrationID, req.Filters.NotIntegrationID, req.Filters.ResourceTypeID, req.Filters.BenchmarkID,
 		req.Filters.ControlID, req.Filters.Severity, lastEventFrom, lastEventTo, evaluatedAtFrom, evaluatedAtTo,
-		req.Filters.StateActive, esComplianceStatuses, req.Sort, req.Limit, req.AfterSortKey, req.Filters.JobID)
+		req.Filters.StateActive, esComplianceStatuses, req.Sort, req.Limit, req.AfterSortKey, req.Filters.JobID, req.Filters.Owner, snapshot)
 	if err != nil {
 		h.logger.Error("failed to get compliacne results", zap.Error(err))
 		return err
@@ -980,14 +1037,14 @@ func (h *HttpHandler) GetTopFieldByComplianceResultCount(echoCtx echo.Context) e
 	var response api.GetTopFieldResponse
 	topFieldResponse, err := es.ComplianceResultsTopFieldQuery(ctx, h.logger, h.client, esField, integrationTypes,
 		nil, integrationIDs, notIntegrationIDs, jobIDs,
-		benchmarkIDs, controlIDs, severities, esComplianceStatuses, stateActives, min(10000, esCount), startTime, endTime)
+		benchmarkIDs, controlIDs, severities, esComplianceStatuses, stateActives, min(10000, esCount), startTime, endTime, nil)
 	if err != nil {
 		h.logger.Error("failed to get top field", zap.Error(err))
 		return err
 	}
 	topFieldTotalResponse, err := es.ComplianceResultsTopFieldQuery(ctx, h.logger, h.client, esField, integrationTypes,
 		nil, integrationIDs, notIntegrationIDs, jobIDs,
-		benchmarkIDs, controlIDs, severities, nil, stateActives, 10000, startTime, endTime)
+		benchmarkIDs, controlIDs, severities, nil, stateActives, 10000, startTime, endTime, nil)
 	if err != nil {
 		h.logger.Error("failed to get top field total", zap.Error(err))
 		return err
@@ -1550,6 +1607,9 @@ func (h *HttpHandler) ChangeBenchmarkSettings(echoCtx echo.Context) error {
 	tracksDriftEvents := echoCtx.QueryParam("tracksDriftEvents") == "true"
 	if len(echoCtx.QueryParam("tracksDriftEvents")) > 0 {
 		benchmarkID := echoCtx.Param("benchmark_id")
+		audit.SetAction(echoCtx, "benchmark.settings.update")
+		audit.SetTarget(echoCtx, "benchmark", benchmarkID)
+		audit.SetAfter(echoCtx, map[string]any{"tracks_drift_events": tracksDriftEvents})
 		err := h.db.UpdateBenchmarkTrackDriftEvents(ctx, benchmarkID, tracksDriftEvents)
 		if err != nil {
 			return err
@@ -1657,9 +1717,14 @@ func (h *HttpHandler) ListResourceFindings(echoCtx echo.Context) error {
 		return echoCtx.JSON(http.StatusInternalServerError, "could not get Summary Job IDs")
 	}
 
+	snapshot, err := h.resolveComplianceSnapshot(ctx, req.Filters.BenchmarkID, req.Filters.IntegrationID, req.AsOf, req.AsOfJobID, false)
+	if err != nil {
+		return err
+	}
+
 	resourceFindings, totalCount, err := es.ResourceFindingsQuery(ctx, h.logger, h.client, req.Filters.IntegrationType, req.Filters.IntegrationID,
 		req.Filters.NotIntegrationID, req.Filters.ResourceCollection, req.Filters.ResourceTypeID, req.Filters.BenchmarkID,
-		req.Filters.ControlID, req.Filters.Severity, evaluatedAtFrom, evaluatedAtTo, esComplianceStatuses, req.Sort, req.Limit, req.AfterSortKey, summaryJobs)
+		req.Filters.ControlID, req.Filters.Severity, evaluatedAtFrom, evaluatedAtTo, esComplianceStatuses, req.Sort, req.Limit, req.AfterSortKey, summaryJobs, req.Filters.Owner, snapshot)
 	if err != nil {
 		h.logger.Error("failed to get resource findings", zap.Error(err))
 		return err
@@ -1809,6 +1874,8 @@ func addToControlSeverityResultV2(controlSeverityResult api.BenchmarkControlsSev
 //	@Param			resourceCollection	query		[]string			false	"Resource collection IDs to filter by"
 //	@Param			integrationTypes	query		[]integration.Type	false	"Integration type to filter by"
 //	@Param			timeAt				query		int					false	"timestamp for values in epoch seconds"
+//	@Param			asOf				query		int					false	"timestamp in epoch seconds to return the state of the benchmark at"
+//	@Param			asOfJobId			query		int					false	"compliance job ID to return the state of the benchmark as evaluated by"
 //	@Param			topAccountCount		query		int					false	"Top account count"	default(3)
 //	@Success		200					{object}	api.BenchmarkEvaluationSummary
 //	@Router			/compliance/api/v1/benchmarks/{benchmark_id}/summary [get]
@@ -1841,7 +1908,36 @@ func (h *HttpHandler) GetBenchmarkSummary(echoCtx echo.Context) error {
 		}
 		timeAt = time.Unix(timeAtInt, 0)
 	}
+	var asOf *int64
+	if asOfStr := echoCtx.QueryParam("asOf"); asOfStr != "" {
+		asOfInt, err := strconv.ParseInt(asOfStr, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid asOf")
+		}
+		asOf = &asOfInt
+	}
+	var asOfJobID *uint
+	if asOfJobIDStr := echoCtx.QueryParam("asOfJobId"); asOfJobIDStr != "" {
+		jobID, err := strconv.ParseUint(asOfJobIDStr, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid asOfJobId")
+		}
+		asOfJobID = utils.GetPointer(uint(jobID))
+	}
 	benchmarkID := echoCtx.Param("benchmark_id")
+	resultsSnapshot, err := h.resolveComplianceSnapshot(ctx, []string{benchmarkID}, integrationIDs, asOf, asOfJobID, true)
+	if err != nil {
+		return err
+	}
+	findingsSnapshot, err := h.resolveComplianceSnapshot(ctx, []string{benchmarkID}, integrationIDs, asOf, asOfJobID, false)
+	if err != nil {
+		return err
+	}
+	if asOfJobID != nil && resultsSnapshot != nil && len(resultsSnapshot.Points) > 0 {
+		timeAt = time.Unix(resultsSnapshot.Points[0].EvaluatedAtEpoch, 0)
+	} else if asOf != nil {
+		timeAt = time.Unix(*asOf, 0)
+	}
 	// tracer :
 	ctx, span1 := tracer.Start(ctx, "new_GetBenchmark", trace.WithSpanKind(trace.SpanKindServer))
 	span1.SetName("new_GetBenchmark")
@@ -1885,13 +1981,13 @@ func (h *HttpHandler) GetBenchmarkSummary(echoCtx echo.Context) error {
 		return err
 	}
 
-	passedResourcesResult, err := es.GetPerBenchmarkResourceSeverityResult(ctx, h.logger, h.client, []string{benchmarkID}, integrationIDs, resourceCollections, nil, opengovernanceTypes.GetPassedComplianceStatuses())
+	passedResourcesResult, err := es.GetPerBenchmarkResourceSeverityResult(ctx, h.logger, h.client, []string{benchmarkID}, integrationIDs, resourceCollections, nil, opengovernanceTypes.GetPassedComplianceStatuses(), findingsSnapshot)
 	if err != nil {
 		h.logger.Error("failed to fetch per benchmark resource severity result for passed", zap.Error(err))
 		return err
 	}
 
-	allResourcesResult, err := es.GetPerBenchmarkResourceSeverityResult(ctx, h.logger, h.client, []string{benchmarkID}, integrationIDs, resourceCollections, nil, nil)
+	allResourcesResult, err := es.GetPerBenchmarkResourceSeverityResult(ctx, h.logger, h.client, []string{benchmarkID}, integrationIDs, resourceCollections, nil, nil, findingsSnapshot)
 	if err != nil {
 		h.logger.Error("failed to fetch per benchmark resource severity result for all", zap.Error(err))
 		return err
@@ -1966,7 +2062,7 @@ func (h *HttpHandler) GetBenchmarkSummary(echoCtx echo.Context) error {
 	if topAccountCount > 0 {
 		res, err := es.ComplianceResultsTopFieldQuery(ctx, h.logger, h.client, "integrationID", integrationTypes,
 			nil, integrationIDs, nil, nil, []string{benchmark.ID}, nil, nil,
-			opengovernanceTypes.GetFailedComplianceStatuses(), []bool{true}, topAccountCount, nil, nil)
+			opengovernanceTypes.GetFailedComplianceStatuses(), []bool{true}, topAccountCount, nil, nil, resultsSnapshot)
 		if err != nil {
 			h.logger.Error("failed to fetch complianceResults top field", zap.Error(err))
 			return err
@@ -1974,7 +2070,7 @@ func (h *HttpHandler) GetBenchmarkSummary(echoCtx echo.Context) error {
 
 		topFieldTotalResponse, err := es.ComplianceResultsTopFieldQuery(ctx, h.logger, h.client, "integrationID", integrationTypes,
 			nil, integrationIDs, nil, nil, []string{benchmark.ID}, nil, nil,
-			opengovernanceTypes.GetFailedComplianceStatuses(), []bool{true}, topAccountCount, nil, nil)
+			opengovernanceTypes.GetFailedComplianceStatuses(), []bool{true}, topAccountCount, nil, nil, resultsSnapshot)
 		if err != nil {
 			h.logger.Error("failed to fetch complianceResults top field total", zap.Error(err))
 			return err
@@ -2915,6 +3011,27 @@ func (h *HttpHandler) ListAssignmentsByBenchmark(echoCtx echo.Context) error {
 		resp.Integrations[idx] = conn
 	}
 
+	var resourceCollectionIDs []string
+	for _, assignment := range dbAssignments {
+		if assignment.ResourceCollection != nil {
+			resourceCollectionIDs = append(resourceCollectionIDs, *assignment.ResourceCollection)
+		}
+	}
+	if len(resourceCollectionIDs) > 0 {
+		resourceCollections, err := h.inventoryClient.ListResourceCollectionsMetadata(hctx, resourceCollectionIDs)
+		if err != nil {
+			h.logger.Error("failed to get resource collections", zap.Error(err))
+			return err
+		}
+		for _, rc := range resourceCollections {
+			resp.ResourceCollections = append(resp.ResourceCollections, api.BenchmarkAssignedResourceCollection{
+				ResourceCollectionID:   rc.ID,
+				ResourceCollectionName: rc.Name,
+				Status:                 rc.Status == inventoryApi.ResourceCollectionStatusActive,
+			})
+		}
+	}
+
 	return echoCtx.JSON(http.StatusOK, resp)
 }
 
@@ -3302,6 +3419,81 @@ func (h *HttpHandler) GetBenchmark(echoCtx echo.Context) error {
 	return echoCtx.JSON(http.StatusOK, benchmark.ToApi())
 }
 
+// GetBenchmarkTree godoc
+//
+//	@Summary		Get benchmark tree
+//	@Description	Returns the benchmark with all of its sub benchmarks, controls and queries in one response
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			benchmark_id	path		string	true	"Benchmark ID"
+//	@Success		200				{object}	api.BenchmarkTree
+//	@Router			/compliance/api/v1/benchmarks/{benchmark_id}/tree [get]
+func (h *HttpHandler) GetBenchmarkTree(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+
+	benchmarkId := echoCtx.Param("benchmark_id")
+	// trace :
+	ctx, span1 := tracer.Start(ctx, "new_GetBenchmarkTree", trace.WithSpanKind(trace.SpanKindServer))
+	span1.SetName("new_GetBenchmarkTree")
+	defer span1.End()
+
+	version, err := h.db.GetFrameworksVersion(ctx)
+	if err != nil {
+		span1.RecordError(err)
+		span1.SetStatus(codes.Error, err.Error())
+		h.logger.Error("failed to get frameworks version", zap.Error(err))
+		return err
+	}
+
+	benchmarks, controls, err := h.db.GetBenchmarkTree(ctx, benchmarkId)
+	if err != nil {
+		span1.RecordError(err)
+		span1.SetStatus(codes.Error, err.Error())
+		h.logger.Error("failed to get benchmark tree", zap.Error(err), zap.String("benchmarkId", benchmarkId))
+		return err
+	}
+	span1.End()
+
+	if len(benchmarks) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "benchmark not found")
+	}
+
+	response := api.BenchmarkTree{
+		RootBenchmarkID: benchmarkId,
+		Version:         version,
+		Benchmarks:      make(map[string]api.Benchmark, len(benchmarks)),
+		Controls:        make(map[string]api.Control, len(controls)),
+	}
+	for _, b := range benchmarks {
+		response.Benchmarks[b.ID] = b.ToApi()
+	}
+	for _, c := range controls {
+		response.Controls[c.ID] = c.ToApi()
+	}
+
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// GetFrameworksVersion godoc
+//
+//	@Summary		Get frameworks version
+//	@Description	Returns a version that changes every time benchmarks, controls or queries are synced
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Success		200	{object}	api.FrameworksVersionResponse
+//	@Router			/compliance/api/v1/benchmarks/version [get]
+func (h *HttpHandler) GetFrameworksVersion(echoCtx echo.Context) error {
+	version, err := h.db.GetFrameworksVersion(echoCtx.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to get frameworks version", zap.Error(err))
+		return err
+	}
+
+	return echoCtx.JSON(http.StatusOK, api.FrameworksVersionResponse{Version: version})
+}
+
 func (h *HttpHandler) getBenchmarkControls(ctx context.Context, benchmarkID string) ([]db.Control, error) {
 	//trace :
 	ctx, span1 := tracer.Start(ctx, "new_GetBenchmark", trace.WithSpanKind(trace.SpanKindServer))
@@ -3807,6 +3999,18 @@ func (h *HttpHandler) AssignBenchmarkToIntegration(echoCtx echo.Context) error {
 	))
 	span1.End()
 
+	audit.SetAction(echoCtx, "benchmark.assign")
+	audit.SetTarget(echoCtx, "benchmark", benchmarkId)
+	if assignments, err := h.db.GetBenchmarkAssignmentsByBenchmarkId(ctx, benchmarkId); err == nil {
+		var assignedIntegrations []string
+		for _, assignment := range assignments {
+			if assignment.IntegrationID != nil {
+				assignedIntegrations = append(assignedIntegrations, *assignment.IntegrationID)
+			}
+		}
+		audit.SetBefore(echoCtx, map[string]any{"auto_assign": benchmark.AutoAssign, "integration_ids": assignedIntegrations})
+	}
+
 	ctx, span4 := tracer.Start(ctx, "new_AddBenchmarkAssignment(loop)", trace.WithSpanKind(trace.SpanKindServer))
 	span4.SetName("new_AddBenchmarkAssignment(loop)")
 	defer span4.End()
@@ -3861,6 +4065,122 @@ func (h *HttpHandler) AssignBenchmarkToIntegration(echoCtx echo.Context) error {
 	return echoCtx.NoContent(http.StatusOK)
 }
 
+// AssignBenchmarkToResourceCollection godoc
+//
+//	@Summary		Assign benchmark to resource collection
+//	@Description	Assigning a benchmark to a resource collection, the benchmark is then evaluated on the resources of the collection only
+//	@Security		BearerToken
+//	@Tags			benchmarks_assignment
+//	@Produce		json
+//	@Param			benchmark_id			path		string	true	"Benchmark ID to assign"
+//	@Param			resource_collection_id	path		string	true	"Resource collection ID"
+//	@Success		200						{object}	api.BenchmarkAssignment
+//	@Router			/compliance/api/v3/benchmark/{benchmark_id}/assign/resource-collection/{resource_collection_id} [post]
+func (h *HttpHandler) AssignBenchmarkToResourceCollection(echoCtx echo.Context) error {
+	clientCtx := &httpclient.Context{UserRole: authApi.AdminRole}
+	ctx := echoCtx.Request().Context()
+
+	benchmarkId := echoCtx.Param("benchmark_id")
+	resourceCollectionId := echoCtx.Param("resource_collection_id")
+
+	benchmark, err := h.db.GetBenchmarkBare(ctx, benchmarkId)
+	if err != nil {
+		h.logger.Error("failed to get benchmark", zap.String("benchmark_id", benchmarkId), zap.Error(err))
+		return err
+	}
+	if benchmark == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("benchmark %s not found", benchmarkId))
+	}
+	if _, err := h.inventoryClient.GetResourceCollectionMetadata(clientCtx, resourceCollectionId); err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("resource collection %s not found", resourceCollectionId))
+		}
+		h.logger.Error("failed to get resource collection", zap.String("resource_collection_id", resourceCollectionId), zap.Error(err))
+		return err
+	}
+	audit.SetAction(echoCtx, "benchmark.assign_resource_collection")
+	audit.SetTarget(echoCtx, "benchmark", benchmarkId)
+
+	assignment := &db.BenchmarkAssignment{
+		BenchmarkId:        benchmarkId,
+		ResourceCollection: &resourceCollectionId,
+		AssignedAt:         time.Now(),
+	}
+	if err := h.db.AddBenchmarkAssignment(ctx, assignment); err != nil {
+		h.logger.Error("failed to add benchmark assignment", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to add benchmark assignment")
+	}
+	res := api.BenchmarkAssignment{
+		BenchmarkId:          assignment.BenchmarkId,
+		ResourceCollectionId: assignment.ResourceCollection,
+		AssignedAt:           assignment.AssignedAt,
+	}
+	audit.SetAfter(echoCtx, res)
+
+	return echoCtx.JSON(http.StatusOK, res)
+}
+
+// UnassignBenchmarkFromResourceCollection godoc
+//
+//	@Summary		Unassign benchmark from resource collection
+//	@Description	Removing the assignment of a benchmark to a resource collection
+//	@Security		BearerToken
+//	@Tags			benchmarks_assignment
+//	@Param			benchmark_id			path	string	true	"Benchmark ID"
+//	@Param			resource_collection_id	path	string	true	"Resource collection ID"
+//	@Success		200
+//	@Router			/compliance/api/v3/benchmark/{benchmark_id}/assign/resource-collection/{resource_collection_id} [delete]
+func (h *HttpHandler) UnassignBenchmarkFromResourceCollection(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+
+	benchmarkId := echoCtx.Param("benchmark_id")
+	resourceCollectionId := echoCtx.Param("resource_collection_id")
+	audit.SetAction(echoCtx, "benchmark.unassign_resource_collection")
+	audit.SetTarget(echoCtx, "benchmark", benchmarkId)
+	audit.SetBefore(echoCtx, map[string]any{"resource_collection_id": resourceCollectionId})
+
+	err := h.db.DeleteBenchmarkAssignmentByIds(ctx, benchmarkId, nil, &resourceCollectionId)
+	if err != nil {
+		h.logger.Error("failed to delete benchmark assignment", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete benchmark assignment")
+	}
+
+	return echoCtx.NoContent(http.StatusOK)
+}
+
+// ListResourceCollectionAssignments godoc
+//
+//	@Summary		List resource collection assignments
+//	@Description	Retrieving the benchmarks assigned to a resource collection
+//	@Security		BearerToken
+//	@Tags			benchmarks_assignment
+//	@Produce		json
+//	@Param			resource_collection_id	path		string	true	"Resource collection ID"
+//	@Success		200						{object}	[]api.BenchmarkAssignment
+//	@Router			/compliance/api/v3/resource-collection/{resource_collection_id}/assignments [get]
+func (h *HttpHandler) ListResourceCollectionAssignments(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+
+	resourceCollectionId := echoCtx.Param("resource_collection_id")
+	assignments, err := h.db.GetBenchmarkAssignmentsByResourceCollectionId(ctx, resourceCollectionId)
+	if err != nil {
+		h.logger.Error("failed to get benchmark assignments", zap.String("resource_collection_id", resourceCollectionId), zap.Error(err))
+		return err
+	}
+
+	res := make([]api.BenchmarkAssignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		res = append(res, api.BenchmarkAssignment{
+			BenchmarkId:          assignment.BenchmarkId,
+			ResourceCollectionId: assignment.ResourceCollection,
+			AssignedAt:           assignment.AssignedAt,
+		})
+	}
+
+	return echoCtx.JSON(http.StatusOK, res)
+}
+
 
 
 // ComplianceSummaryOfBenchmark godoc
@@ -3937,13 +4257,13 @@ func (h *HttpHandler) ComplianceSummaryOfBenchmark(echoCtx echo.Context) error {
 			return err
 		}
 
-		passedResourcesResult, err := es.GetPerBenchmarkResourceSeverityResult(ctx, h.logger, h.client, []string{benchmark.ID}, nil, nil, nil, opengovernanceTypes.GetPassedComplianceStatuses())
+		passedResourcesResult, err := es.GetPerBenchmarkResourceSeverityResult(ctx, h.logger, h.client, []string{benchmark.ID}, nil, nil, nil, opengovernanceTypes.GetPassedComplianceStatuses(), nil)
 		if err != nil {
 			h.logger.Error("failed to fetch per benchmark resource severity result for passed", zap.Error(err))
 			return err
 		}
 
-		allResourcesResult, err := es.GetPerBenchmarkResourceSeverityResult(ctx, h.logger, h.client, []string{benchmark.ID}, nil, nil, nil, nil)
+		allResourcesResult, err := es.GetPerBenchmarkResourceSeverityResult(ctx, h.logger, h.client, []string{benchmark.ID}, nil, nil, nil, nil, nil)
 		if err != nil {
 			h.logger.Error("failed to fetch per benchmark resource severity result for all", zap.Error(err))
 			return err
@@ -3983,7 +4303,7 @@ func (h *HttpHandler) ComplianceSummaryOfBenchmark(echoCtx echo.Context) error {
 		if req.ShowTop > 0 {
 			res, err := es.ComplianceResultsTopFieldQuery(ctx, h.logger, h.client, "integrationID", nil,
 				nil, nil, nil, nil, []string{benchmark.ID}, nil, nil,
-				opengovernanceTypes.GetFailedComplianceStatuses(), []bool{true}, req.ShowTop, nil, nil)
+				opengovernanceTypes.GetFailedComplianceStatuses(), []bool{true}, req.ShowTop, nil, nil, nil)
 			if err != nil {
 				h.logger.Error("failed to fetch complianceResults top field", zap.Error(err))
 				return err
@@ -3991,7 +4311,7 @@ func (h *HttpHandler) ComplianceSummaryOfBenchmark(echoCtx echo.Context) error {
 
 			topFieldTotalResponse, err := es.ComplianceResultsTopFieldQuery(ctx, h.logger, h.client, "integrationID", nil,
 				nil, nil, nil, nil, []string{benchmark.ID}, nil, nil,
-				opengovernanceTypes.GetFailedComplianceStatuses(), []bool{true}, req.ShowTop, nil, nil)
+				opengovernanceTypes.GetFailedComplianceStatuses(), []bool{true}, req.ShowTop, nil, nil, nil)
 			if err != nil {
 				h.logger.Error("failed to fetch complianceResults top field total", zap.Error(err))
 				return err
@@ -4584,6 +4904,102 @@ func (h *HttpHandler) GetBenchmarkTrendV3(echoCtx echo.Context) error {
 	return echoCtx.JSON(http.StatusOK, response)
 }
 
+// GetBenchmarkTrendAnalytics godoc
+//
+//	@Summary		Get benchmark trend analytics
+//	@Description	Analyzes the compliance score across the latest compliance jobs of a benchmark, optionally scoped to integrations and controls.
+//	@Description	Returns the moving average, the significant drops between consecutive jobs with the controls and integrations contributing the most, and the projected time to the target score.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			benchmark_id	path		string									true	"Benchmark ID"
+//	@Param			request			body		api.GetBenchmarkTrendAnalyticsRequest	false	"Request Body"
+//	@Success		200				{object}	api.GetBenchmarkTrendAnalyticsResponse
+//	@Router			/compliance/api/v3/benchmarks/{benchmark_id}/trend/analytics [post]
+func (h *HttpHandler) GetBenchmarkTrendAnalytics(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	clientCtx := &httpclient.Context{UserRole: authApi.AdminRole}
+
+	var req api.GetBenchmarkTrendAnalyticsRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	endTime := time.Now()
+	if req.EndTime != nil {
+		endTime = time.Unix(*req.EndTime, 0)
+	}
+	startTime := endTime.AddDate(0, 0, -90)
+	if req.StartTime != nil {
+		startTime = time.Unix(*req.StartTime, 0)
+	}
+	if startTime.After(endTime) {
+		return echo.NewHTTPError(http.StatusBadRequest, "start_time must be before end_time")
+	}
+	maxJobs := defaultTrendMaxJobs
+	if req.MaxJobs != nil {
+		maxJobs = *req.MaxJobs
+	}
+
+	benchmarkID := echoCtx.Param("benchmark_id")
+	benchmark, err := h.db.GetBenchmarkBare(ctx, benchmarkID)
+	if err != nil {
+		h.logger.Error("failed to get benchmark", zap.String("benchmarkId", benchmarkID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get benchmark")
+	}
+	if benchmark == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "benchmark not found")
+	}
+
+	var integrationIDs []string
+	for _, info := range req.Integration {
+		if info.IntegrationID != nil {
+			integrationIDs = append(integrationIDs, *info.IntegrationID)
+			continue
+		}
+		var integrationTypes []string
+		if info.IntegrationType != nil {
+			integrationTypes = []string{*info.IntegrationType}
+		}
+		integrations, err := h.integrationClient.ListIntegrationsByFilters(clientCtx,
+			integrationapi.ListIntegrationsRequest{
+				IntegrationType: integrationTypes,
+				NameRegex:       info.Name,
+				ProviderIDRegex: info.ProviderID,
+			})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		for _, integration := range integrations.Integrations {
+			integrationIDs = append(integrationIDs, integration.IntegrationID)
+		}
+	}
+	if len(req.Integration) > 0 && len(integrationIDs) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "no integration matches the filters")
+	}
+
+	samples, err := h.loadTrendSamples(ctx, benchmark.ID, integrationIDs, req.ControlIDs, startTime, endTime, maxJobs)
+	if err != nil {
+		h.logger.Error("failed to load trend samples", zap.String("benchmarkId", benchmark.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to load compliance trend")
+	}
+
+	opts := trend.Options{TargetScore: req.TargetScore}
+	if req.Window != nil {
+		opts.Window = *req.Window
+	}
+	if req.SignificanceLevel != nil {
+		opts.SignificanceLevel = *req.SignificanceLevel
+	}
+	if req.TopContributors != nil {
+		opts.TopContributors = *req.TopContributors
+	}
+	analysis := trend.Analyze(samples, opts)
+
+	return echoCtx.JSON(http.StatusOK, toApiTrendAnalytics(benchmark.ID, integrationIDs, req.ControlIDs, analysis))
+}
+
 func parseTimeInterval(intervalStr string) (*time.Time, *time.Time, error) {
 	// Define regex patterns to extract the time components
 	patterns := map[string]*regexp.Regexp{
@@ -4981,3 +5397,1261 @@ func (h HttpHandler) GetJobReportSummary(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, response)
 }
+
+// CompareComplianceSnapshots godoc
+//
+//	@Summary		Compare compliance snapshots
+//	@Description	Diffs the compliance results of a benchmark between two points in time or two compliance jobs
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.CompareComplianceSnapshotsRequest	true	"Request Body"
+//	@Success		200		{object}	api.CompareComplianceSnapshotsResponse
+//	@Router			/compliance/api/v3/compliance/snapshots/compare [post]
+func (h *HttpHandler) CompareComplianceSnapshots(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+
+	var req api.CompareComplianceSnapshotsRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if req.BenchmarkID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "benchmarkID is required")
+	}
+	if (req.Base.AsOf == nil && req.Base.JobID == nil) || (req.Target.AsOf == nil && req.Target.JobID == nil) {
+		return echo.NewHTTPError(http.StatusBadRequest, "base and target should have asOf or jobID")
+	}
+	if req.Limit <= 0 {
+		req.Limit = 100
+	}
+
+	listResults := func(ref api.ComplianceSnapshotRef) (map[string]opengovernanceTypes.ComplianceResult, error) {
+		snapshot, err := h.resolveComplianceSnapshot(ctx, []string{req.BenchmarkID}, req.IntegrationID, ref.AsOf, ref.JobID, true)
+		if err != nil {
+			return nil, err
+		}
+		results, err := es.ListComplianceSnapshotResults(ctx, h.logger, h.client, snapshot, []string{req.BenchmarkID}, req.IntegrationID)
+		if err != nil {
+			h.logger.Error("failed to list compliance snapshot results", zap.Error(err))
+			return nil, err
+		}
+		resultsMap := make(map[string]opengovernanceTypes.ComplianceResult)
+		for _, r := range results {
+			key := strings.Join([]string{r.PlatformResourceID, r.ResourceID, r.IntegrationID, r.ControlID, r.BenchmarkID}, "|")
+			resultsMap[key] = r
+		}
+		return resultsMap, nil
+	}
+
+	base, err := listResults(req.Base)
+	if err != nil {
+		return err
+	}
+	target, err := listResults(req.Target)
+	if err != nil {
+		return err
+	}
+
+	var response api.CompareComplianceSnapshotsResponse
+	addToDiff := func(diff *api.ComplianceSnapshotDiff, r opengovernanceTypes.ComplianceResult) {
+		diff.Count++
+		if len(diff.Results) < req.Limit {
+			diff.Results = append(diff.Results, api.GetAPIComplianceResultFromESComplianceResult(r))
+		}
+	}
+
+	keys := make([]string, 0, len(target))
+	for key := range target {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		t := target[key]
+		if t.ComplianceStatus.IsPassed() {
+			continue
+		}
+		if b, ok := base[key]; ok && !b.ComplianceStatus.IsPassed() {
+			addToDiff(&response.UnchangedFailed, t)
+		} else {
+			addToDiff(&response.NewlyFailed, t)
+		}
+	}
+
+	keys = make([]string, 0, len(base))
+	for key := range base {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b := base[key]
+		if b.ComplianceStatus.IsPassed() {
+			continue
+		}
+		// results missing from the target are not failing anymore, e.g. the resource is deleted
+		if t, ok := target[key]; !ok || t.ComplianceStatus.IsPassed() {
+			addToDiff(&response.Resolved, b)
+		}
+	}
+
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// GenerateReport godoc
+//
+//	@Summary		Generate compliance report
+//	@Description	Generates the report of a compliance job in the requested formats. The reports are rendered in the background,
+//	@Description	if no compliance job is given the latest compliance job of the framework is reported.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.GenerateReportRequest	true	"Request Body"
+//	@Success		200		{object}	[]api.Report
+//	@Router			/compliance/api/v3/reports [post]
+func (h *HttpHandler) GenerateReport(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+
+	var req api.GenerateReportRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	formats, err := parseReportFormats(req.Formats)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	jobID := req.ComplianceJobID
+	if jobID == nil {
+		if req.FrameworkID == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "compliance_job_id or framework_id is required")
+		}
+		lastJob, err := h.schedulerClient.GetLatestComplianceJobForBenchmark(&httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole}, req.FrameworkID)
+		if err != nil {
+			h.logger.Error("failed to get latest compliance job", zap.String("framework", req.FrameworkID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to get latest compliance job")
+		}
+		if lastJob == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "framework has no compliance job")
+		}
+		jobID = &lastJob.ID
+	}
+
+	complianceJob, err := h.getReportableComplianceJob(ctx, *jobID)
+	if err != nil {
+		return err
+	}
+	if req.FrameworkID != "" && req.FrameworkID != complianceJob.FrameworkId {
+		return echo.NewHTTPError(http.StatusBadRequest, "compliance job is not a job of the framework")
+	}
+
+	audit.SetAction(echoCtx, "report.generate")
+	audit.SetTarget(echoCtx, "compliance_job", strconv.FormatUint(uint64(complianceJob.JobId), 10))
+
+	reports, err := h.generateReports(ctx, complianceJob, formats, nil, httpserver2.GetUserID(echoCtx))
+	if err != nil {
+		h.logger.Error("failed to create reports", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create reports")
+	}
+	response := make([]api.Report, 0, len(reports))
+	for _, r := range reports {
+		response = append(response, r.ToApi())
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// GenerateScheduledReports godoc
+//
+//	@Summary		Generate scheduled compliance reports
+//	@Description	Generates the reports of the enabled report schedules of the framework of a finished compliance job.
+//	@Description	Schedules that already reported the job are skipped.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			job_id	path		string	true	"Compliance job ID"
+//	@Success		200		{object}	[]api.Report
+//	@Router			/compliance/api/v3/reports/compliance-jobs/{job_id}/scheduled [post]
+func (h *HttpHandler) GenerateScheduledReports(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+
+	jobID, err := strconv.ParseUint(echoCtx.Param("job_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid job id")
+	}
+	complianceJob, err := h.getReportableComplianceJob(ctx, uint(jobID))
+	if err != nil {
+		return err
+	}
+
+	schedules, err := h.db.ListReportSchedules(ctx, complianceJob.FrameworkId, true)
+	if err != nil {
+		h.logger.Error("failed to list report schedules", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list report schedules")
+	}
+	response := make([]api.Report, 0)
+	for _, schedule := range schedules {
+		exists, err := h.db.HasScheduledReport(ctx, schedule.ID, complianceJob.JobId)
+		if err != nil {
+			h.logger.Error("failed to check scheduled reports", zap.Uint("scheduleId", schedule.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to check scheduled reports")
+		}
+		if exists {
+			continue
+		}
+		formats, err := parseReportFormats(schedule.Formats)
+		if err != nil {
+			h.logger.Error("invalid report schedule", zap.Uint("scheduleId", schedule.ID), zap.Error(err))
+			continue
+		}
+		scheduleID := schedule.ID
+		reports, err := h.generateReports(ctx, complianceJob, formats, &scheduleID, schedule.CreatedBy)
+		if err != nil {
+			h.logger.Error("failed to create reports", zap.Uint("scheduleId", schedule.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to create reports")
+		}
+		for _, r := range reports {
+			response = append(response, r.ToApi())
+		}
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// ListReports godoc
+//
+//	@Summary		List compliance reports
+//	@Description	Lists the generated compliance reports newest first, without their content
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			compliance_job_id	query		int		false	"Compliance job ID"
+//	@Param			framework_id		query		string	false	"Framework ID"
+//	@Param			cursor				query		int		false	"Cursor"
+//	@Param			per_page			query		int		false	"Per page"
+//	@Success		200					{object}	api.ListReportsResponse
+//	@Router			/compliance/api/v3/reports [get]
+func (h *HttpHandler) ListReports(echoCtx echo.Context) error {
+	var jobID *uint
+	if jobIDStr := echoCtx.QueryParam("compliance_job_id"); jobIDStr != "" {
+		id, err := strconv.ParseUint(jobIDStr, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid compliance_job_id")
+		}
+		v := uint(id)
+		jobID = &v
+	}
+
+	var err error
+	cursor, perPage := int64(1), int64(20)
+	if cursorStr := echoCtx.QueryParam("cursor"); cursorStr != "" {
+		cursor, err = strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || cursor < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid cursor")
+		}
+	}
+	if perPageStr := echoCtx.QueryParam("per_page"); perPageStr != "" {
+		perPage, err = strconv.ParseInt(perPageStr, 10, 64)
+		if err != nil || perPage < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid per_page")
+		}
+	}
+
+	reports, total, err := h.db.ListReports(echoCtx.Request().Context(), jobID, echoCtx.QueryParam("framework_id"),
+		int(perPage), int((cursor-1)*perPage))
+	if err != nil {
+		h.logger.Error("failed to list reports", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list reports")
+	}
+	response := api.ListReportsResponse{
+		Items:      make([]api.Report, 0, len(reports)),
+		TotalCount: total,
+	}
+	for _, r := range reports {
+		response.Items = append(response.Items, r.ToApi())
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// GetReport godoc
+//
+//	@Summary		Get compliance report
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			report_id	path		string	true	"Report ID"
+//	@Success		200			{object}	api.Report
+//	@Router			/compliance/api/v3/reports/{report_id} [get]
+func (h *HttpHandler) GetReport(echoCtx echo.Context) error {
+	r, err := h.getReport(echoCtx, false)
+	if err != nil {
+		return err
+	}
+	return echoCtx.JSON(http.StatusOK, r.ToApi())
+}
+
+// DownloadReport godoc
+//
+//	@Summary		Download compliance report
+//	@Description	Returns the rendered report document as an attachment
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		octet-stream
+//	@Param			report_id	path	string	true	"Report ID"
+//	@Success		200
+//	@Router			/compliance/api/v3/reports/{report_id}/download [get]
+func (h *HttpHandler) DownloadReport(echoCtx echo.Context) error {
+	r, err := h.getReport(echoCtx, true)
+	if err != nil {
+		return err
+	}
+	switch r.Status {
+	case api.ReportStatusGenerating:
+		return echo.NewHTTPError(http.StatusConflict, "report is being generated")
+	case api.ReportStatusFailed:
+		return echo.NewHTTPError(http.StatusConflict, "report generation failed: "+r.FailureMessage)
+	}
+
+	echoCtx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", r.FileName))
+	return echoCtx.Blob(http.StatusOK, report.Format(r.Format).ContentType(), r.Content)
+}
+
+// DeleteReport godoc
+//
+//	@Summary		Delete compliance report
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Param			report_id	path	string	true	"Report ID"
+//	@Success		200
+//	@Router			/compliance/api/v3/reports/{report_id} [delete]
+func (h *HttpHandler) DeleteReport(echoCtx echo.Context) error {
+	r, err := h.getReport(echoCtx, false)
+	if err != nil {
+		return err
+	}
+	audit.SetAction(echoCtx, "report.delete")
+	audit.SetTarget(echoCtx, "report", strconv.FormatUint(uint64(r.ID), 10))
+	audit.SetBefore(echoCtx, r.ToApi())
+
+	if err = h.db.DeleteReport(echoCtx.Request().Context(), r.ID); err != nil {
+		h.logger.Error("failed to delete report", zap.Uint("reportId", r.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete report")
+	}
+	return echoCtx.NoContent(http.StatusOK)
+}
+
+func (h *HttpHandler) getReport(echoCtx echo.Context, withContent bool) (*db.Report, error) {
+	id, err := strconv.ParseUint(echoCtx.Param("report_id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid report id")
+	}
+	r, err := h.db.GetReport(echoCtx.Request().Context(), uint(id), withContent)
+	if err != nil {
+		h.logger.Error("failed to get report", zap.Uint64("reportId", id), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to get report")
+	}
+	if r == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "report not found")
+	}
+	return r, nil
+}
+
+// ListReportTemplates godoc
+//
+//	@Summary		List report templates
+//	@Description	Lists the customised report templates of the frameworks
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Success		200	{object}	[]api.ReportTemplate
+//	@Router			/compliance/api/v3/reports/templates [get]
+func (h *HttpHandler) ListReportTemplates(echoCtx echo.Context) error {
+	templates, err := h.db.ListReportTemplates(echoCtx.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to list report templates", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list report templates")
+	}
+	response := make([]api.ReportTemplate, 0, len(templates))
+	for _, t := range templates {
+		response = append(response, t.ToApi())
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// GetReportTemplate godoc
+//
+//	@Summary		Get report template
+//	@Description	Returns the report template of the framework, the default template if it is not customised
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			framework_id	path		string	true	"Framework ID"
+//	@Success		200				{object}	api.ReportTemplate
+//	@Router			/compliance/api/v3/reports/templates/{framework_id} [get]
+func (h *HttpHandler) GetReportTemplate(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	frameworkID := echoCtx.Param("framework_id")
+
+	t, err := h.db.GetReportTemplate(ctx, frameworkID)
+	if err != nil {
+		h.logger.Error("failed to get report template", zap.String("framework", frameworkID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get report template")
+	}
+	if t != nil {
+		return echoCtx.JSON(http.StatusOK, t.ToApi())
+	}
+
+	framework, err := h.db.GetBenchmarkBare(ctx, frameworkID)
+	if err != nil {
+		h.logger.Error("failed to get framework", zap.String("framework", frameworkID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get framework")
+	}
+	if framework == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "framework not found")
+	}
+	return echoCtx.JSON(http.StatusOK, api.ReportTemplate{
+		FrameworkID:  frameworkID,
+		HTMLTemplate: report.DefaultHTMLTemplate,
+		IsDefault:    true,
+	})
+}
+
+// PutReportTemplate godoc
+//
+//	@Summary		Customise report template
+//	@Description	Sets the report template of the framework. The HTML template is a Go html/template executed with the report,
+//	@Description	it is validated by rendering a sample report.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			framework_id	path		string						true	"Framework ID"
+//	@Param			request			body		api.ReportTemplateRequest	true	"Request Body"
+//	@Success		200				{object}	api.ReportTemplate
+//	@Router			/compliance/api/v3/reports/templates/{framework_id} [put]
+func (h *HttpHandler) PutReportTemplate(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	frameworkID := echoCtx.Param("framework_id")
+
+	var req api.ReportTemplateRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	framework, err := h.db.GetBenchmarkBare(ctx, frameworkID)
+	if err != nil {
+		h.logger.Error("failed to get framework", zap.String("framework", frameworkID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get framework")
+	}
+	if framework == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "framework not found")
+	}
+
+	sample := report.Build(report.Template{Title: req.Title, Introduction: req.Introduction, Footer: req.Footer},
+		report.Metadata{FrameworkID: framework.ID, FrameworkTitle: framework.Title}, nil, nil, nil)
+	if err = report.RenderHTML(io.Discard, sample, req.HTMLTemplate); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	before, err := h.db.GetReportTemplate(ctx, frameworkID)
+	if err != nil {
+		h.logger.Error("failed to get report template", zap.String("framework", frameworkID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get report template")
+	}
+	audit.SetAction(echoCtx, "report_template.update")
+	audit.SetTarget(echoCtx, "framework", frameworkID)
+	if before != nil {
+		audit.SetBefore(echoCtx, before.ToApi())
+	}
+
+	t := db.ReportTemplate{
+		FrameworkID:  frameworkID,
+		Title:        req.Title,
+		Introduction: req.Introduction,
+		Footer:       req.Footer,
+		HTMLTemplate: req.HTMLTemplate,
+		UpdatedBy:    httpserver2.GetUserID(echoCtx),
+		UpdatedAt:    time.Now(),
+	}
+	if err = h.db.UpsertReportTemplate(ctx, &t); err != nil {
+		h.logger.Error("failed to store report template", zap.String("framework", frameworkID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to store report template")
+	}
+	return echoCtx.JSON(http.StatusOK, t.ToApi())
+}
+
+// DeleteReportTemplate godoc
+//
+//	@Summary		Reset report template
+//	@Description	Deletes the customised report template of the framework, its reports use the default template again
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Param			framework_id	path	string	true	"Framework ID"
+//	@Success		200
+//	@Router			/compliance/api/v3/reports/templates/{framework_id} [delete]
+func (h *HttpHandler) DeleteReportTemplate(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	frameworkID := echoCtx.Param("framework_id")
+
+	t, err := h.db.GetReportTemplate(ctx, frameworkID)
+	if err != nil {
+		h.logger.Error("failed to get report template", zap.String("framework", frameworkID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get report template")
+	}
+	if t == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "report template not found")
+	}
+	audit.SetAction(echoCtx, "report_template.delete")
+	audit.SetTarget(echoCtx, "framework", frameworkID)
+	audit.SetBefore(echoCtx, t.ToApi())
+
+	if err = h.db.DeleteReportTemplate(ctx, frameworkID); err != nil {
+		h.logger.Error("failed to delete report template", zap.String("framework", frameworkID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete report template")
+	}
+	return echoCtx.NoContent(http.StatusOK)
+}
+
+// ListReportSchedules godoc
+//
+//	@Summary		List report schedules
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			framework_id	query		string	false	"Framework ID"
+//	@Success		200				{object}	[]api.ReportSchedule
+//	@Router			/compliance/api/v3/reports/schedules [get]
+func (h *HttpHandler) ListReportSchedules(echoCtx echo.Context) error {
+	schedules, err := h.db.ListReportSchedules(echoCtx.Request().Context(), echoCtx.QueryParam("framework_id"), false)
+	if err != nil {
+		h.logger.Error("failed to list report schedules", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list report schedules")
+	}
+	response := make([]api.ReportSchedule, 0, len(schedules))
+	for _, s := range schedules {
+		response = append(response, s.ToApi())
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// CreateReportSchedule godoc
+//
+//	@Summary		Create report schedule
+//	@Description	Generates the reports of the framework in the formats after each of its compliance jobs finishes
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.ReportScheduleRequest	true	"Request Body"
+//	@Success		200		{object}	api.ReportSchedule
+//	@Router			/compliance/api/v3/reports/schedules [post]
+func (h *HttpHandler) CreateReportSchedule(echoCtx echo.Context) error {
+	schedule, err := h.newReportSchedule(echoCtx)
+	if err != nil {
+		return err
+	}
+	schedule.CreatedBy = httpserver2.GetUserID(echoCtx)
+
+	if err = h.db.CreateReportSchedule(echoCtx.Request().Context(), schedule); err != nil {
+		h.logger.Error("failed to create report schedule", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create report schedule")
+	}
+	audit.SetAction(echoCtx, "report_schedule.create")
+	audit.SetTarget(echoCtx, "report_schedule", strconv.FormatUint(uint64(schedule.ID), 10))
+	return echoCtx.JSON(http.StatusOK, schedule.ToApi())
+}
+
+// UpdateReportSchedule godoc
+//
+//	@Summary		Update report schedule
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			schedule_id	path		string						true	"Schedule ID"
+//	@Param			request		body		api.ReportScheduleRequest	true	"Request Body"
+//	@Success		200			{object}	api.ReportSchedule
+//	@Router			/compliance/api/v3/reports/schedules/{schedule_id} [put]
+func (h *HttpHandler) UpdateReportSchedule(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	existing, err := h.getReportSchedule(echoCtx)
+	if err != nil {
+		return err
+	}
+	schedule, err := h.newReportSchedule(echoCtx)
+	if err != nil {
+		return err
+	}
+	audit.SetAction(echoCtx, "report_schedule.update")
+	audit.SetTarget(echoCtx, "report_schedule", strconv.FormatUint(uint64(existing.ID), 10))
+	audit.SetBefore(echoCtx, existing.ToApi())
+
+	schedule.ID = existing.ID
+	if err = h.db.UpdateReportSchedule(ctx, schedule); err != nil {
+		h.logger.Error("failed to update report schedule", zap.Uint("scheduleId", existing.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update report schedule")
+	}
+	updated, err := h.db.GetReportSchedule(ctx, existing.ID)
+	if err != nil || updated == nil {
+		h.logger.Error("failed to get report schedule", zap.Uint("scheduleId", existing.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get report schedule")
+	}
+	return echoCtx.JSON(http.StatusOK, updated.ToApi())
+}
+
+// DeleteReportSchedule godoc
+//
+//	@Summary		Delete report schedule
+//	@Description	Deletes the report schedule, the reports it generated are kept
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Param			schedule_id	path	string	true	"Schedule ID"
+//	@Success		200
+//	@Router			/compliance/api/v3/reports/schedules/{schedule_id} [delete]
+func (h *HttpHandler) DeleteReportSchedule(echoCtx echo.Context) error {
+	schedule, err := h.getReportSchedule(echoCtx)
+	if err != nil {
+		return err
+	}
+	audit.SetAction(echoCtx, "report_schedule.delete")
+	audit.SetTarget(echoCtx, "report_schedule", strconv.FormatUint(uint64(schedule.ID), 10))
+	audit.SetBefore(echoCtx, schedule.ToApi())
+
+	if err = h.db.DeleteReportSchedule(echoCtx.Request().Context(), schedule.ID); err != nil {
+		h.logger.Error("failed to delete report schedule", zap.Uint("scheduleId", schedule.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete report schedule")
+	}
+	return echoCtx.NoContent(http.StatusOK)
+}
+
+func (h *HttpHandler) newReportSchedule(echoCtx echo.Context) (*db.ReportSchedule, error) {
+	var req api.ReportScheduleRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	formats, err := parseReportFormats(req.Formats)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	framework, err := h.db.GetBenchmarkBare(echoCtx.Request().Context(), req.FrameworkID)
+	if err != nil {
+		h.logger.Error("failed to get framework", zap.String("framework", req.FrameworkID), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to get framework")
+	}
+	if framework == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "framework not found")
+	}
+
+	schedule := db.ReportSchedule{
+		FrameworkID: req.FrameworkID,
+		Enabled:     true,
+	}
+	for _, f := range formats {
+		schedule.Formats = append(schedule.Formats, string(f))
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+	return &schedule, nil
+}
+
+func (h *HttpHandler) getReportSchedule(echoCtx echo.Context) (*db.ReportSchedule, error) {
+	id, err := strconv.ParseUint(echoCtx.Param("schedule_id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid schedule id")
+	}
+	schedule, err := h.db.GetReportSchedule(echoCtx.Request().Context(), uint(id))
+	if err != nil {
+		h.logger.Error("failed to get report schedule", zap.Uint64("scheduleId", id), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to get report schedule")
+	}
+	if schedule == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "report schedule not found")
+	}
+	return schedule, nil
+}
+
+// ListAttackPaths godoc
+//
+//	@Summary		List attack paths
+//	@Description	Combines the active failing results along the relationships of their resources into attack paths, from a public exposure through privilege findings to data findings.
+//	@Description	Paths are ranked by the scores of the findings along them, raised for every finding category they cover.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.RiskAnalysisRequest	false	"Request Body"
+//	@Success		200		{object}	api.ListAttackPathsResponse
+//	@Router			/compliance/api/v3/risk/attack-paths [post]
+func (h *HttpHandler) ListAttackPaths(echoCtx echo.Context) error {
+	var req api.RiskAnalysisRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	analysis, truncated, err := h.loadRiskAnalysis(echoCtx.Request().Context(), req)
+	if err != nil {
+		h.logger.Error("failed to analyze attack paths", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to analyze attack paths")
+	}
+
+	return echoCtx.JSON(http.StatusOK, api.ListAttackPathsResponse{
+		TotalCount: analysis.TotalPaths,
+		Paths:      toApiAttackPaths(analysis.Paths),
+		Truncated:  truncated,
+	})
+}
+
+// ListFindingPriorities godoc
+//
+//	@Summary		List findings by contextual priority
+//	@Description	Scores the active failing results by their severity raised by the findings on the resources related to theirs, and the attack paths going through their resource.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.RiskAnalysisRequest	false	"Request Body"
+//	@Success		200		{object}	api.ListFindingPrioritiesResponse
+//	@Router			/compliance/api/v3/risk/finding-priorities [post]
+func (h *HttpHandler) ListFindingPriorities(echoCtx echo.Context) error {
+	var req api.RiskAnalysisRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	analysis, truncated, err := h.loadRiskAnalysis(echoCtx.Request().Context(), req)
+	if err != nil {
+		h.logger.Error("failed to analyze finding priorities", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to analyze finding priorities")
+	}
+
+	limit := risk.DefaultLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	priorities := analysis.Priorities
+	if len(priorities) > limit {
+		priorities = priorities[:limit]
+	}
+
+	return echoCtx.JSON(http.StatusOK, api.ListFindingPrioritiesResponse{
+		TotalCount: len(analysis.Priorities),
+		Items:      toApiFindingPriorities(priorities),
+		Truncated:  truncated,
+	})
+}
+
+// ListControlSeverityOverrides godoc
+//
+//	@Summary		List control severity overrides
+//	@Description	Returns the severity overrides of the control with its original severity
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			control_id	path		string	true	"Control ID"
+//	@Success		200			{object}	api.ListSeverityOverridesResponse
+//	@Router			/compliance/api/v3/controls/{control_id}/severity-overrides [get]
+func (h *HttpHandler) ListControlSeverityOverrides(echoCtx echo.Context) error {
+	control, err := h.getSeverityOverrideControl(echoCtx)
+	if err != nil {
+		return err
+	}
+	overrides, err := h.db.ListControlSeverityOverrides(echoCtx.Request().Context(), control.ID)
+	if err != nil {
+		h.logger.Error("failed to list severity overrides", zap.String("control_id", control.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list severity overrides")
+	}
+	response := api.ListSeverityOverridesResponse{Items: make([]api.SeverityOverride, 0, len(overrides))}
+	for _, o := range overrides {
+		response.Items = append(response.Items, o.ToApi(control.Severity))
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// CreateControlSeverityOverride godoc
+//
+//	@Summary		Create control severity override
+//	@Description	Overrides the severity of the results of the control, optionally only within an integration group, a resource collection or the resources with a tag (scope_value key=value).
+//	@Description	The most specific override matching a result is applied by the compliance runner on the next run of the control, the original severity is kept on the results.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			control_id	path		string						true	"Control ID"
+//	@Param			request		body		api.SeverityOverrideRequest	true	"Request Body"
+//	@Success		200			{object}	api.SeverityOverride
+//	@Router			/compliance/api/v3/controls/{control_id}/severity-overrides [post]
+func (h *HttpHandler) CreateControlSeverityOverride(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	control, err := h.getSeverityOverrideControl(echoCtx)
+	if err != nil {
+		return err
+	}
+	override, err := h.newSeverityOverride(echoCtx, control.ID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := h.db.ListControlSeverityOverrides(ctx, control.ID)
+	if err != nil {
+		h.logger.Error("failed to list severity overrides", zap.String("control_id", control.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list severity overrides")
+	}
+	for _, o := range existing {
+		if o.ScopeType == override.ScopeType && o.ScopeValue == override.ScopeValue {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("severity override %d already covers this scope", o.ID))
+		}
+	}
+
+	override.CreatedBy = httpserver2.GetUserID(echoCtx)
+	override.UpdatedBy = override.CreatedBy
+	if err = h.db.CreateControlSeverityOverride(ctx, override, control.Severity); err != nil {
+		h.logger.Error("failed to create severity override", zap.String("control_id", control.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create severity override")
+	}
+	audit.SetAction(echoCtx, "control_severity_override.create")
+	audit.SetTarget(echoCtx, "control", control.ID)
+	audit.SetAfter(echoCtx, override.ToApi(control.Severity))
+	return echoCtx.JSON(http.StatusOK, override.ToApi(control.Severity))
+}
+
+// UpdateControlSeverityOverride godoc
+//
+//	@Summary		Update control severity override
+//	@Description	Replaces the severity and reason of the override, its scope cannot be changed
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			control_id	path		string						true	"Control ID"
+//	@Param			override_id	path		string						true	"Override ID"
+//	@Param			request		body		api.SeverityOverrideRequest	true	"Request Body"
+//	@Success		200			{object}	api.SeverityOverride
+//	@Router			/compliance/api/v3/controls/{control_id}/severity-overrides/{override_id} [put]
+func (h *HttpHandler) UpdateControlSeverityOverride(echoCtx echo.Context) error {
+	control, err := h.getSeverityOverrideControl(echoCtx)
+	if err != nil {
+		return err
+	}
+	existing, err := h.getSeverityOverride(echoCtx, control.ID)
+	if err != nil {
+		return err
+	}
+	override, err := h.newSeverityOverride(echoCtx, control.ID)
+	if err != nil {
+		return err
+	}
+	if override.ScopeType != existing.ScopeType || override.ScopeValue != existing.ScopeValue {
+		return echo.NewHTTPError(http.StatusBadRequest, "the scope of a severity override cannot be changed")
+	}
+	audit.SetAction(echoCtx, "control_severity_override.update")
+	audit.SetTarget(echoCtx, "control", control.ID)
+	audit.SetBefore(echoCtx, existing.ToApi(control.Severity))
+
+	previousSeverity := existing.Severity
+	existing.Severity = override.Severity
+	existing.Reason = override.Reason
+	existing.UpdatedBy = httpserver2.GetUserID(echoCtx)
+	if err = h.db.UpdateControlSeverityOverride(echoCtx.Request().Context(), existing, previousSeverity); err != nil {
+		h.logger.Error("failed to update severity override", zap.Uint("overrideId", existing.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update severity override")
+	}
+	audit.SetAfter(echoCtx, existing.ToApi(control.Severity))
+	return echoCtx.JSON(http.StatusOK, existing.ToApi(control.Severity))
+}
+
+// DeleteControlSeverityOverride godoc
+//
+//	@Summary		Delete control severity override
+//	@Description	Deletes the override, the results get back the original severity of the control on the next run. The override history is kept.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Param			control_id	path	string	true	"Control ID"
+//	@Param			override_id	path	string	true	"Override ID"
+//	@Success		200
+//	@Router			/compliance/api/v3/controls/{control_id}/severity-overrides/{override_id} [delete]
+func (h *HttpHandler) DeleteControlSeverityOverride(echoCtx echo.Context) error {
+	control, err := h.getSeverityOverrideControl(echoCtx)
+	if err != nil {
+		return err
+	}
+	override, err := h.getSeverityOverride(echoCtx, control.ID)
+	if err != nil {
+		return err
+	}
+	audit.SetAction(echoCtx, "control_severity_override.delete")
+	audit.SetTarget(echoCtx, "control", control.ID)
+	audit.SetBefore(echoCtx, override.ToApi(control.Severity))
+
+	if err = h.db.DeleteControlSeverityOverride(echoCtx.Request().Context(), *override, control.Severity, httpserver2.GetUserID(echoCtx)); err != nil {
+		h.logger.Error("failed to delete severity override", zap.Uint("overrideId", override.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete severity override")
+	}
+	return echoCtx.NoContent(http.StatusOK)
+}
+
+// ListControlSeverityOverrideHistory godoc
+//
+//	@Summary		List control severity override history
+//	@Description	Returns the changes of the severity overrides of the control, latest first, including the deleted overrides
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			control_id	path		string	true	"Control ID"
+//	@Success		200			{object}	api.ListSeverityOverrideHistoryResponse
+//	@Router			/compliance/api/v3/controls/{control_id}/severity-overrides/history [get]
+func (h *HttpHandler) ListControlSeverityOverrideHistory(echoCtx echo.Context) error {
+	controlID := echoCtx.Param("control_id")
+	history, err := h.db.ListControlSeverityOverrideHistory(echoCtx.Request().Context(), controlID)
+	if err != nil {
+		h.logger.Error("failed to list severity override history", zap.String("control_id", controlID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list severity override history")
+	}
+	response := api.ListSeverityOverrideHistoryResponse{Items: make([]api.SeverityOverrideHistoryEntry, 0, len(history))}
+	for _, entry := range history {
+		response.Items = append(response.Items, entry.ToApi())
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// ListResolvedControlSeverityOverrides godoc
+//
+//	@Summary		List resolved control severity overrides
+//	@Description	Returns the severity overrides of the control with the members of their integration group or the filters of their resource collection, for the compliance runner
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Param			control_id	path		string	true	"Control ID"
+//	@Success		200			{object}	[]api.ResolvedSeverityOverride
+//	@Router			/compliance/api/v3/controls/{control_id}/severity-overrides/resolved [get]
+func (h *HttpHandler) ListResolvedControlSeverityOverrides(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	controlID := echoCtx.Param("control_id")
+	overrides, err := h.db.ListControlSeverityOverrides(ctx, controlID)
+	if err != nil {
+		h.logger.Error("failed to list severity overrides", zap.String("control_id", controlID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list severity overrides")
+	}
+	response := make([]api.ResolvedSeverityOverride, 0, len(overrides))
+	for _, o := range overrides {
+		resolved, err := h.resolveSeverityOverride(ctx, o)
+		if err != nil {
+			h.logger.Error("failed to resolve severity override", zap.Uint("overrideId", o.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to resolve severity override")
+		}
+		if resolved != nil {
+			response = append(response, *resolved)
+		}
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// ListSLAPolicies godoc
+//
+//	@Summary		List SLA policies
+//	@Description	Returns the number of days the failing results of every severity must be resolved in, severities without a configured policy get the default one
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Success		200	{object}	[]api.SLAPolicy
+//	@Router			/compliance/api/v3/sla/policies [get]
+func (h *HttpHandler) ListSLAPolicies(echoCtx echo.Context) error {
+	policies, err := h.listSLAPolicies(echoCtx.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to list sla policies", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list sla policies")
+	}
+	return echoCtx.JSON(http.StatusOK, policies)
+}
+
+// PutSLAPolicies godoc
+//
+//	@Summary		Set SLA policies
+//	@Description	Sets the number of days the failing results of the severities must be resolved in, zero disables the SLA of a severity.
+//	@Description	The SLA due times of the results are updated on the next run of their control.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.PutSLAPoliciesRequest	true	"Request Body"
+//	@Success		200		{object}	[]api.SLAPolicy
+//	@Router			/compliance/api/v3/sla/policies [put]
+func (h *HttpHandler) PutSLAPolicies(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	var req api.PutSLAPoliciesRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	before, err := h.listSLAPolicies(ctx)
+	if err != nil {
+		h.logger.Error("failed to list sla policies", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list sla policies")
+	}
+	audit.SetAction(echoCtx, "sla_policies.update")
+	audit.SetTarget(echoCtx, "sla_policies", "")
+	audit.SetBefore(echoCtx, before)
+
+	userID := httpserver2.GetUserID(echoCtx)
+	policies := make([]db.SLAPolicy, 0, len(req.Policies))
+	for _, p := range req.Policies {
+		severity := types2.ParseComplianceResultSeverity(p.Severity)
+		if severity == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid severity %s", p.Severity))
+		}
+		policies = append(policies, db.SLAPolicy{
+			Severity:          severity,
+			ResolveWithinDays: p.ResolveWithinDays,
+			UpdatedBy:         userID,
+			UpdatedAt:         time.Now(),
+		})
+	}
+	if err = h.db.UpsertSLAPolicies(ctx, policies); err != nil {
+		h.logger.Error("failed to update sla policies", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update sla policies")
+	}
+
+	after, err := h.listSLAPolicies(ctx)
+	if err != nil {
+		h.logger.Error("failed to list sla policies", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list sla policies")
+	}
+	audit.SetAfter(echoCtx, after)
+	return echoCtx.JSON(http.StatusOK, after)
+}
+
+// GetAgeingReport godoc
+//
+//	@Summary		Get ageing report
+//	@Description	Ages the active failing results from when they started failing and counts them by SLA status: within, at risk (past three quarters of their SLA time) and breached.
+//	@Description	The counts are given in total and per severity, integration, owner and benchmark, the owner being the value of a resource tag.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.AgeingReportRequest	false	"Request Body"
+//	@Success		200		{object}	api.AgeingReportResponse
+//	@Router			/compliance/api/v3/sla/ageing-report [post]
+func (h *HttpHandler) GetAgeingReport(echoCtx echo.Context) error {
+	var req api.AgeingReportRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	ownerTagKey := req.OwnerTagKey
+	if ownerTagKey == "" {
+		ownerTagKey = ownership.DefaultOwnerTagKey
+	}
+
+	now := time.Now()
+	report, truncated, err := h.loadAgeingReport(echoCtx.Request().Context(), req, ownerTagKey, now)
+	if err != nil {
+		h.logger.Error("failed to build ageing report", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to build ageing report")
+	}
+
+	return echoCtx.JSON(http.StatusOK, api.AgeingReportResponse{
+		OwnerTagKey:   ownerTagKey,
+		GeneratedAt:   now,
+		Total:         toApiAgeingReportGroup(report.Total),
+		BySeverity:    toApiAgeingReportGroups(report.BySeverity),
+		ByIntegration: toApiAgeingReportGroups(report.ByIntegration),
+		ByOwner:       toApiAgeingReportGroups(report.ByOwner),
+		ByBenchmark:   toApiAgeingReportGroups(report.ByBenchmark),
+		AgeBuckets:    toApiAgeingReportBuckets(report.Buckets),
+		Truncated:     truncated,
+	})
+}
+
+// ListOwnershipRules godoc
+//
+//	@Summary		List ownership rules
+//	@Description	Returns the ownership rules in the order they are evaluated, by ascending priority
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Success		200	{object}	api.ListOwnershipRulesResponse
+//	@Router			/compliance/api/v3/ownership/rules [get]
+func (h *HttpHandler) ListOwnershipRules(echoCtx echo.Context) error {
+	rules, err := h.db.ListOwnershipRules(echoCtx.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to list ownership rules", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list ownership rules")
+	}
+	response := api.ListOwnershipRulesResponse{Items: make([]api.OwnershipRule, 0, len(rules))}
+	for _, rule := range rules {
+		response.Items = append(response.Items, rule.ToApi())
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// CreateOwnershipRule godoc
+//
+//	@Summary		Create ownership rule
+//	@Description	Assigns the resources matching the rule to an owner, by tag, integration, integration group or resource type.
+//	@Description	Tag rules without an owner assign the resources to the value of the tag. Rules are evaluated by ascending priority and the first match wins.
+//	@Description	The owners of the results are updated when the results are next summarized.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.OwnershipRuleRequest	true	"Request Body"
+//	@Success		200		{object}	api.OwnershipRule
+//	@Router			/compliance/api/v3/ownership/rules [post]
+func (h *HttpHandler) CreateOwnershipRule(echoCtx echo.Context) error {
+	rule, err := h.newOwnershipRule(echoCtx)
+	if err != nil {
+		return err
+	}
+	rule.CreatedBy = httpserver2.GetUserID(echoCtx)
+	rule.UpdatedBy = rule.CreatedBy
+	if err = h.db.CreateOwnershipRule(echoCtx.Request().Context(), rule); err != nil {
+		h.logger.Error("failed to create ownership rule", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create ownership rule")
+	}
+	audit.SetAction(echoCtx, "ownership_rule.create")
+	audit.SetTarget(echoCtx, "ownership_rule", strconv.FormatUint(uint64(rule.ID), 10))
+	audit.SetAfter(echoCtx, rule.ToApi())
+	return echoCtx.JSON(http.StatusOK, rule.ToApi())
+}
+
+// UpdateOwnershipRule godoc
+//
+//	@Summary		Update ownership rule
+//	@Description	Replaces the ownership rule
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			rule_id	path		string						true	"Rule ID"
+//	@Param			request	body		api.OwnershipRuleRequest	true	"Request Body"
+//	@Success		200		{object}	api.OwnershipRule
+//	@Router			/compliance/api/v3/ownership/rules/{rule_id} [put]
+func (h *HttpHandler) UpdateOwnershipRule(echoCtx echo.Context) error {
+	existing, err := h.getOwnershipRule(echoCtx)
+	if err != nil {
+		return err
+	}
+	rule, err := h.newOwnershipRule(echoCtx)
+	if err != nil {
+		return err
+	}
+	audit.SetAction(echoCtx, "ownership_rule.update")
+	audit.SetTarget(echoCtx, "ownership_rule", strconv.FormatUint(uint64(existing.ID), 10))
+	audit.SetBefore(echoCtx, existing.ToApi())
+
+	existing.Owner = rule.Owner
+	existing.Priority = rule.Priority
+	existing.MatchType = rule.MatchType
+	existing.TagKey = rule.TagKey
+	existing.Pattern = rule.Pattern
+	existing.Description = rule.Description
+	existing.UpdatedBy = httpserver2.GetUserID(echoCtx)
+	if err = h.db.UpdateOwnershipRule(echoCtx.Request().Context(), existing); err != nil {
+		h.logger.Error("failed to update ownership rule", zap.Uint("ruleId", existing.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update ownership rule")
+	}
+	audit.SetAfter(echoCtx, existing.ToApi())
+	return echoCtx.JSON(http.StatusOK, existing.ToApi())
+}
+
+// DeleteOwnershipRule godoc
+//
+//	@Summary		Delete ownership rule
+//	@Description	Deletes the ownership rule, the resources it assigned go to the next matching rule when the results are next summarized
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Param			rule_id	path	string	true	"Rule ID"
+//	@Success		200
+//	@Router			/compliance/api/v3/ownership/rules/{rule_id} [delete]
+func (h *HttpHandler) DeleteOwnershipRule(echoCtx echo.Context) error {
+	rule, err := h.getOwnershipRule(echoCtx)
+	if err != nil {
+		return err
+	}
+	audit.SetAction(echoCtx, "ownership_rule.delete")
+	audit.SetTarget(echoCtx, "ownership_rule", strconv.FormatUint(uint64(rule.ID), 10))
+	audit.SetBefore(echoCtx, rule.ToApi())
+
+	if err = h.db.DeleteOwnershipRule(echoCtx.Request().Context(), rule.ID); err != nil {
+		h.logger.Error("failed to delete ownership rule", zap.Uint("ruleId", rule.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete ownership rule")
+	}
+	return echoCtx.NoContent(http.StatusOK)
+}
+
+// ListResolvedOwnershipRules godoc
+//
+//	@Summary		List resolved ownership rules
+//	@Description	Returns the ownership rules with the members of their integration group, for the compliance summarizer
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Produce		json
+//	@Success		200	{object}	[]api.ResolvedOwnershipRule
+//	@Router			/compliance/api/v3/ownership/rules/resolved [get]
+func (h *HttpHandler) ListResolvedOwnershipRules(echoCtx echo.Context) error {
+	ctx := echoCtx.Request().Context()
+	rules, err := h.db.ListOwnershipRules(ctx)
+	if err != nil {
+		h.logger.Error("failed to list ownership rules", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list ownership rules")
+	}
+	response := make([]api.ResolvedOwnershipRule, 0, len(rules))
+	for _, rule := range rules {
+		resolved, err := h.resolveOwnershipRule(ctx, rule)
+		if err != nil {
+			h.logger.Error("failed to resolve ownership rule", zap.Uint("ruleId", rule.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to resolve ownership rule")
+		}
+		if resolved != nil {
+			response = append(response, *resolved)
+		}
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
+
+// GetOwnerScorecard godoc
+//
+//	@Summary		Get owner scorecard
+//	@Description	Counts the active results of every owner: resources, passed and failed results, failed results per severity and breached SLAs.
+//	@Description	Owners are sorted by breached SLAs then by ascending security score, results without an owner are counted as unassigned.
+//	@Security		BearerToken
+//	@Tags			compliance
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.OwnerScorecardRequest	false	"Request Body"
+//	@Success		200		{object}	api.OwnerScorecardResponse
+//	@Router			/compliance/api/v3/ownership/scorecard [post]
+func (h *HttpHandler) GetOwnerScorecard(echoCtx echo.Context) error {
+	var req api.OwnerScorecardRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	now := time.Now()
+	scores, err := es.GetOwnerScores(echoCtx.Request().Context(), h.logger, h.client, req.IntegrationIDs, req.BenchmarkIDs, now)
+	if err != nil {
+		h.logger.Error("failed to get owner scores", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get owner scores")
+	}
+	if len(req.Owners) > 0 {
+		owners := make(map[string]bool, len(req.Owners))
+		for _, owner := range req.Owners {
+			owners[owner] = true
+		}
+		filtered := scores[:0]
+		for _, s := range scores {
+			if owners[s.Owner] {
+				filtered = append(filtered, s)
+			}
+		}
+		scores = filtered
+	}
+	ownership.SortScores(scores)
+
+	response := api.OwnerScorecardResponse{
+		GeneratedAt: now,
+		Owners:      make([]api.OwnerScorecard, 0, len(scores)),
+	}
+	for _, s := range scores {
+		response.Owners = append(response.Owners, toApiOwnerScorecard(s))
+	}
+	return echoCtx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,110 @@
+package compliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	authApi "github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"github.com/opengovern/opencomply/services/compliance/db"
+	"github.com/opengovern/opencomply/services/compliance/ownership"
+	"go.uber.org/zap"
+)
+
+func (h *HttpHandler) getOwnershipRule(echoCtx echo.Context) (*db.OwnershipRule, error) {
+	id, err := strconv.ParseUint(echoCtx.Param("rule_id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid rule id")
+	}
+	rule, err := h.db.GetOwnershipRule(echoCtx.Request().Context(), uint(id))
+	if err != nil {
+		h.logger.Error("failed to get ownership rule", zap.Uint64("ruleId", id), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to get ownership rule")
+	}
+	if rule == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "ownership rule not found")
+	}
+	return rule, nil
+}
+
+// newOwnershipRule validates the request of an ownership rule, the integration group of integration_group rules
+// must exist
+func (h *HttpHandler) newOwnershipRule(echoCtx echo.Context) (*db.OwnershipRule, error) {
+	var req api.OwnershipRuleRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	rule := db.OwnershipRule{
+		Owner:       strings.TrimSpace(req.Owner),
+		Priority:    req.Priority,
+		MatchType:   req.MatchType,
+		Pattern:     strings.TrimSpace(req.Pattern),
+		Description: req.Description,
+	}
+	if ownership.MatchType(req.MatchType) == ownership.MatchTypeTag {
+		rule.TagKey = strings.TrimSpace(req.TagKey)
+	}
+	if err := ownership.Validate(ownership.MatchType(rule.MatchType), rule.TagKey, rule.Pattern, rule.Owner); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if ownership.MatchType(rule.MatchType) == ownership.MatchTypeIntegrationGroup {
+		clientCtx := &httpclient.Context{Ctx: echoCtx.Request().Context(), UserRole: authApi.AdminRole}
+		if _, err := h.integrationClient.GetIntegrationGroup(clientCtx, rule.Pattern); err != nil {
+			var httpErr *echo.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("integration group %s not found", rule.Pattern))
+			}
+			h.logger.Error("failed to get integration group", zap.String("integration_group", rule.Pattern), zap.Error(err))
+			return nil, err
+		}
+	}
+	return &rule, nil
+}
+
+// resolveOwnershipRule resolves the integrations of the group of integration_group rules, rules whose group no
+// longer exists are returned as nil
+func (h *HttpHandler) resolveOwnershipRule(ctx context.Context, r db.OwnershipRule) (*api.ResolvedOwnershipRule, error) {
+	resolved := api.ResolvedOwnershipRule{
+		ID:        r.ID,
+		Owner:     r.Owner,
+		Priority:  r.Priority,
+		MatchType: r.MatchType,
+		TagKey:    r.TagKey,
+		Pattern:   r.Pattern,
+	}
+	if ownership.MatchType(r.MatchType) != ownership.MatchTypeIntegrationGroup {
+		return &resolved, nil
+	}
+
+	group, err := h.integrationClient.GetIntegrationGroup(&httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole}, r.Pattern)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound {
+			h.logger.Warn("ownership rule integration group not found", zap.Uint("ruleId", r.ID),
+				zap.String("integration_group", r.Pattern))
+			return nil, nil
+		}
+		return nil, err
+	}
+	resolved.IntegrationIDs = group.IntegrationIds
+	return &resolved, nil
+}
+
+func toApiOwnerScorecard(s ownership.Score) api.OwnerScorecard {
+	return api.OwnerScorecard{
+		Owner:                 s.Owner,
+		ResourceCount:         s.Resources,
+		PassedCount:           s.Passed,
+		FailedCount:           s.Failed,
+		FailedCountBySeverity: s.FailedBySeverity,
+		SLABreachedCount:      s.SLABreached,
+		SecurityScore:         s.SecurityScore(),
+	}
+}
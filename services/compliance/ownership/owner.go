@@ -0,0 +1,8 @@
+package ownership
+
+const (
+	// Unassigned is the owner of the results on resources without an owner
+	Unassigned = "unassigned"
+	// DefaultOwnerTagKey is the resource tag read for the owner of the results when none is given
+	DefaultOwnerTagKey = "owner"
+)
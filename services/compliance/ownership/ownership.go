@@ -0,0 +1,170 @@
+package ownership
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+type MatchType string
+
+const (
+	// MatchTypeTag matches the value of a resource tag, rules without an owner assign the resources to the
+	// tag value
+	MatchTypeTag              MatchType = "tag"
+	MatchTypeIntegration      MatchType = "integration"
+	MatchTypeIntegrationGroup MatchType = "integration_group"
+	MatchTypeResourceType     MatchType = "resource_type"
+)
+
+// Rule assigns the resources it matches to its owner. Patterns are case-insensitive globs, e.g. "aws::ec2::*",
+// in which "*" also matches "/", e.g. "microsoft.compute/*". Integration group rules match the integrations of the
+// group named by the pattern instead.
+type Rule struct {
+	ID        uint
+	Owner     string
+	Priority  int
+	MatchType MatchType
+	TagKey    string
+	Pattern   string
+	// IntegrationIDs are the members of the group of integration_group rules
+	IntegrationIDs map[string]bool
+}
+
+// Resource is what the rules know of a resource, Tags only need the keys of the tag rules
+type Resource struct {
+	IntegrationID string
+	ResourceType  string
+	Tags          map[string]string
+}
+
+func Validate(matchType MatchType, tagKey, pattern, owner string) error {
+	switch matchType {
+	case MatchTypeTag:
+		if strings.TrimSpace(tagKey) == "" {
+			return fmt.Errorf("tag_key is required for tag rules")
+		}
+		if pattern == "" {
+			return nil
+		}
+	case MatchTypeIntegration, MatchTypeIntegrationGroup, MatchTypeResourceType:
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("pattern is required")
+		}
+		if strings.TrimSpace(owner) == "" {
+			return fmt.Errorf("owner is required")
+		}
+	default:
+		return fmt.Errorf("unsupported match_type: %s", matchType)
+	}
+	if _, err := path.Match(globOperand(pattern), ""); err != nil {
+		return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	return nil
+}
+
+// Sort orders the rules by ascending priority, rules of the same priority are evaluated in their creation order
+func Sort(rules []Rule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].ID < rules[j].ID
+	})
+}
+
+// TagKeys returns the tag keys the rules read
+func TagKeys(rules []Rule) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, rule := range rules {
+		if rule.MatchType != MatchTypeTag || seen[rule.TagKey] {
+			continue
+		}
+		seen[rule.TagKey] = true
+		keys = append(keys, rule.TagKey)
+	}
+	return keys
+}
+
+// Resolve returns the owner of the resource from the first matching rule of the sorted rules, empty if no rule
+// matches it
+func Resolve(rules []Rule, r Resource) string {
+	for _, rule := range rules {
+		switch rule.MatchType {
+		case MatchTypeTag:
+			value, ok := r.Tags[rule.TagKey]
+			if !ok || strings.TrimSpace(value) == "" {
+				continue
+			}
+			if rule.Pattern != "" && !match(rule.Pattern, value) {
+				continue
+			}
+			if rule.Owner == "" {
+				return strings.TrimSpace(value)
+			}
+			return rule.Owner
+		case MatchTypeIntegration:
+			if r.IntegrationID != "" && match(rule.Pattern, r.IntegrationID) {
+				return rule.Owner
+			}
+		case MatchTypeIntegrationGroup:
+			if rule.IntegrationIDs[r.IntegrationID] {
+				return rule.Owner
+			}
+		case MatchTypeResourceType:
+			if r.ResourceType != "" && match(rule.Pattern, r.ResourceType) {
+				return rule.Owner
+			}
+		}
+	}
+	return ""
+}
+
+func match(pattern, value string) bool {
+	matched, _ := path.Match(globOperand(pattern), globOperand(value))
+	return matched
+}
+
+// globOperand lowercases s and swaps out its slashes, path.Match does not let "*" match "/" which would keep the
+// patterns from matching the Azure resource types
+func globOperand(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "/", "\x00")
+}
+
+// Score is the state of the active results of an owner
+type Score struct {
+	Owner            string
+	Resources        int
+	Passed           int
+	Failed           int
+	FailedBySeverity map[types.ComplianceResultSeverity]int
+	SLABreached      int
+}
+
+// SecurityScore is the share of passing results in percents, owners without failing or passing results score 100
+func (s Score) SecurityScore() float64 {
+	if s.Passed+s.Failed == 0 {
+		return 100
+	}
+	return float64(s.Passed) / float64(s.Passed+s.Failed) * 100
+}
+
+// SortScores puts the owners with the most breached SLAs first, then the ones with the lowest security score
+func SortScores(scores []Score) {
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].SLABreached != scores[j].SLABreached {
+			return scores[i].SLABreached > scores[j].SLABreached
+		}
+		if scores[i].SecurityScore() != scores[j].SecurityScore() {
+			return scores[i].SecurityScore() < scores[j].SecurityScore()
+		}
+		if scores[i].Failed != scores[j].Failed {
+			return scores[i].Failed > scores[j].Failed
+		}
+		return scores[i].Owner < scores[j].Owner
+	})
+}
@@ -0,0 +1,146 @@
+package ownership
+
+import (
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	rules := []Rule{
+		{ID: 5, Priority: 2, MatchType: MatchTypeResourceType, Pattern: "aws::rds::*", Owner: "data-team"},
+		{ID: 1, Priority: 1, MatchType: MatchTypeTag, TagKey: "team"},
+		{ID: 2, Priority: 1, MatchType: MatchTypeTag, TagKey: "cost-center", Pattern: "cc-1*", Owner: "finance"},
+		{ID: 3, Priority: 3, MatchType: MatchTypeIntegrationGroup, Pattern: "production",
+			IntegrationIDs: map[string]bool{"prod-account": true}, Owner: "sre"},
+		{ID: 4, Priority: 4, MatchType: MatchTypeIntegration, Pattern: "sandbox-*", Owner: "developers"},
+		{ID: 6, Priority: 5, MatchType: MatchTypeResourceType, Pattern: "microsoft.compute/*", Owner: "platform"},
+	}
+	Sort(rules)
+
+	tests := []struct {
+		name     string
+		resource Resource
+		want     string
+	}{
+		{
+			name:     "tag value is the owner of tag rules without an owner",
+			resource: Resource{ResourceType: "AWS::RDS::DBInstance", Tags: map[string]string{"team": " payments "}},
+			want:     "payments",
+		},
+		{
+			name:     "empty tag values do not match",
+			resource: Resource{ResourceType: "AWS::RDS::DBInstance", Tags: map[string]string{"team": " "}},
+			want:     "data-team",
+		},
+		{
+			name:     "tag pattern",
+			resource: Resource{ResourceType: "AWS::S3::Bucket", Tags: map[string]string{"cost-center": "CC-1042"}},
+			want:     "finance",
+		},
+		{
+			name:     "tag pattern not matching",
+			resource: Resource{ResourceType: "AWS::S3::Bucket", Tags: map[string]string{"cost-center": "cc-2001"}},
+			want:     "",
+		},
+		{
+			name:     "resource type pattern is case insensitive",
+			resource: Resource{ResourceType: "AWS::RDS::DBCluster", IntegrationID: "prod-account"},
+			want:     "data-team",
+		},
+		{
+			name:     "integration group",
+			resource: Resource{ResourceType: "AWS::EC2::Instance", IntegrationID: "prod-account"},
+			want:     "sre",
+		},
+		{
+			name:     "integration pattern",
+			resource: Resource{ResourceType: "AWS::EC2::Instance", IntegrationID: "sandbox-42"},
+			want:     "developers",
+		},
+		{
+			name:     "resource type pattern matches across slashes",
+			resource: Resource{ResourceType: "Microsoft.Compute/virtualMachines/extensions"},
+			want:     "platform",
+		},
+		{
+			name:     "no rule matches",
+			resource: Resource{ResourceType: "AWS::EC2::Instance", IntegrationID: "staging"},
+			want:     "",
+		},
+		{
+			name:     "empty resource",
+			resource: Resource{},
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Resolve(rules, tt.resource); got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	rules := []Rule{{ID: 3, Priority: 2}, {ID: 2, Priority: 1}, {ID: 1, Priority: 2}, {ID: 4, Priority: 0}}
+	Sort(rules)
+	var ids []uint
+	for _, r := range rules {
+		ids = append(ids, r.ID)
+	}
+	want := []uint{4, 2, 1, 3}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("sorted rules %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		matchType MatchType
+		tagKey    string
+		pattern   string
+		owner     string
+		wantErr   bool
+	}{
+		{name: "tag rule owned by the tag value", matchType: MatchTypeTag, tagKey: "team"},
+		{name: "tag rule without tag key", matchType: MatchTypeTag, owner: "sre", wantErr: true},
+		{name: "tag rule with invalid pattern", matchType: MatchTypeTag, tagKey: "team", pattern: "[", wantErr: true},
+		{name: "integration rule", matchType: MatchTypeIntegration, pattern: "prod-*", owner: "sre"},
+		{name: "integration rule without owner", matchType: MatchTypeIntegration, pattern: "prod-*", wantErr: true},
+		{name: "resource type rule without pattern", matchType: MatchTypeResourceType, owner: "sre", wantErr: true},
+		{name: "unknown match type", matchType: "account", pattern: "*", owner: "sre", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.matchType, tt.tagKey, tt.pattern, tt.owner)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSortScores(t *testing.T) {
+	scores := []Score{
+		{Owner: "clean", Passed: 10},
+		{Owner: "breaching", Passed: 9, Failed: 1, SLABreached: 1},
+		{Owner: "failing", Passed: 1, Failed: 9},
+		{Owner: "empty"},
+	}
+	SortScores(scores)
+
+	want := []string{"breaching", "failing", "clean", "empty"}
+	for i, s := range scores {
+		if s.Owner != want[i] {
+			t.Fatalf("owner %d is %s, want the order %v", i, s.Owner, want)
+		}
+	}
+	if scores[2].SecurityScore() != 100 || scores[3].SecurityScore() != 100 {
+		t.Errorf("expected owners without failing results to score 100")
+	}
+}
@@ -0,0 +1,206 @@
+package compliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	authApi "github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/pkg/utils"
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"github.com/opengovern/opencomply/services/compliance/db"
+	"github.com/opengovern/opencomply/services/compliance/es"
+	"github.com/opengovern/opencomply/services/compliance/report"
+	schedulerapi "github.com/opengovern/opencomply/services/describe/api"
+	"go.uber.org/zap"
+)
+
+const reportGenerationTimeout = 30 * time.Minute
+
+// getReportableComplianceJob returns the compliance job if it has finished and its results can be reported
+func (h *HttpHandler) getReportableComplianceJob(ctx context.Context, jobID uint) (*schedulerapi.GetComplianceJobStatusResponse, error) {
+	clientCtx := &httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole}
+	complianceJob, err := h.schedulerClient.GetComplianceJobStatus(clientCtx, strconv.FormatUint(uint64(jobID), 10))
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound {
+			return nil, echo.NewHTTPError(http.StatusNotFound, "compliance job not found")
+		}
+		h.logger.Error("failed to get compliance job", zap.Uint("jobId", jobID), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to get compliance job")
+	}
+	switch schedulerapi.ComplianceJobStatus(complianceJob.JobStatus) {
+	case schedulerapi.ComplianceJobTimeout:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "job has been timed out")
+	case schedulerapi.ComplianceJobCreated, schedulerapi.ComplianceJobRunnersInProgress, schedulerapi.ComplianceJobSummarizerInProgress:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "job is in progress")
+	}
+	if complianceJob.WithIncidents && complianceJob.SummaryJobId == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "compliance job not summarized yet")
+	}
+	return complianceJob, nil
+}
+
+// generateReports stores a report of the compliance job per format and renders them in the background
+func (h *HttpHandler) generateReports(ctx context.Context, complianceJob *schedulerapi.GetComplianceJobStatusResponse,
+	formats []report.Format, scheduleID *uint, createdBy string) ([]db.Report, error) {
+	reports := make([]db.Report, 0, len(formats))
+	for _, format := range formats {
+		r := db.Report{
+			ComplianceJobID: complianceJob.JobId,
+			FrameworkID:     complianceJob.FrameworkId,
+			Format:          string(format),
+			ScheduleID:      scheduleID,
+			Status:          api.ReportStatusGenerating,
+			FileName:        fmt.Sprintf("%s-compliance-job-%d.%s", complianceJob.FrameworkId, complianceJob.JobId, format),
+			CreatedBy:       createdBy,
+		}
+		if err := h.db.CreateReport(ctx, &r); err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+
+	utils.EnsureRunGoroutine(func() {
+		h.renderReports(complianceJob, reports)
+	})
+	return reports, nil
+}
+
+func (h *HttpHandler) renderReports(complianceJob *schedulerapi.GetComplianceJobStatusResponse, reports []db.Report) {
+	ctx, cancel := context.WithTimeout(context.Background(), reportGenerationTimeout)
+	defer cancel()
+
+	r, htmlTemplate, buildErr := h.buildReport(ctx, complianceJob)
+	for _, dbReport := range reports {
+		status, failureMessage := api.ReportStatusSucceeded, ""
+		var content []byte
+		if buildErr != nil {
+			status, failureMessage = api.ReportStatusFailed, buildErr.Error()
+		} else {
+			var err error
+			content, err = report.Render(report.Format(dbReport.Format), r, htmlTemplate)
+			if err != nil {
+				status, failureMessage = api.ReportStatusFailed, err.Error()
+			}
+		}
+		if status == api.ReportStatusFailed {
+			h.logger.Error("failed to generate report", zap.Uint("reportId", dbReport.ID), zap.String("error", failureMessage))
+		}
+
+		err := h.db.UpdateReportResult(ctx, dbReport.ID, status, failureMessage, content)
+		if err != nil {
+			h.logger.Error("failed to store report", zap.Uint("reportId", dbReport.ID), zap.Error(err))
+		}
+	}
+}
+
+// buildReport collects the results of the compliance job and returns the report and the HTML template of its framework
+func (h *HttpHandler) buildReport(ctx context.Context, complianceJob *schedulerapi.GetComplianceJobStatusResponse) (*report.Report, string, error) {
+	framework, err := h.db.GetBenchmark(ctx, complianceJob.FrameworkId)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get framework: %w", err)
+	}
+	if framework == nil {
+		return nil, "", fmt.Errorf("framework %s not found", complianceJob.FrameworkId)
+	}
+
+	controlsMap, err := h.getControlsUnderBenchmark(ctx, framework.ID, make(map[string]BenchmarkControlsCache))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get controls of framework: %w", err)
+	}
+	controlIDs := make([]string, 0, len(controlsMap))
+	for id := range controlsMap {
+		controlIDs = append(controlIDs, id)
+	}
+	var controls []db.Control
+	if len(controlIDs) > 0 {
+		controls, err = h.db.ListControls(ctx, controlIDs, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list controls: %w", err)
+		}
+	}
+	definitions := make([]report.ControlDefinition, 0, len(controls))
+	for _, c := range controls {
+		definition := report.ControlDefinition{
+			ID:          c.ID,
+			Title:       c.Title,
+			Description: c.Description,
+			Severity:    c.Severity,
+			DocumentURI: c.DocumentURI,
+		}
+		if c.Query != nil {
+			definition.QueryID = c.Query.ID
+			definition.Query = c.Query.QueryToExecute
+		}
+		definitions = append(definitions, definition)
+	}
+
+	jobID := strconv.FormatUint(uint64(complianceJob.JobId), 10)
+	if complianceJob.WithIncidents && complianceJob.SummaryJobId != nil {
+		jobID = strconv.FormatUint(uint64(*complianceJob.SummaryJobId), 10)
+	}
+	summary, err := es.GetJobReportControlSummaryByJobID(ctx, h.logger, h.client, jobID, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get job report control summary: %w", err)
+	}
+	if summary == nil {
+		return nil, "", errors.New("compliance job has no results")
+	}
+	view, err := es.GetJobReportControlViewByJobID(ctx, h.logger, h.client, jobID, true, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get job report control view: %w", err)
+	}
+
+	var template report.Template
+	dbTemplate, err := h.db.GetReportTemplate(ctx, framework.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get report template: %w", err)
+	}
+	if dbTemplate != nil {
+		template = report.Template{
+			Title:        dbTemplate.Title,
+			Introduction: dbTemplate.Introduction,
+			Footer:       dbTemplate.Footer,
+			HTML:         dbTemplate.HTMLTemplate,
+		}
+	}
+
+	var integrationIDs []string
+	for _, ii := range complianceJob.IntegrationInfo {
+		integrationIDs = append(integrationIDs, ii.IntegrationID)
+	}
+	metadata := report.Metadata{
+		FrameworkID:          framework.ID,
+		FrameworkTitle:       framework.Title,
+		FrameworkDescription: framework.Description,
+		JobID:                complianceJob.JobId,
+		JobStatus:            complianceJob.JobStatus,
+		JobStartedAt:         complianceJob.CreatedAt,
+		DataAsOf:             complianceJob.DataAsOf,
+		EvaluatedOnStaleData: complianceJob.EvaluatedOnStaleData,
+		IntegrationIDs:       integrationIDs,
+	}
+	return report.Build(template, metadata, definitions, summary, view), template.HTML, nil
+}
+
+func parseReportFormats(formats []string) ([]report.Format, error) {
+	seen := make(map[report.Format]bool)
+	var result []report.Format
+	for _, f := range formats {
+		format := report.Format(f)
+		if !format.IsValid() {
+			return nil, fmt.Errorf("invalid report format %s, valid formats are %v", f, report.Formats)
+		}
+		if !seen[format] {
+			seen[format] = true
+			result = append(result, format)
+		}
+	}
+	return result, nil
+}
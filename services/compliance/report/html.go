@@ -0,0 +1,84 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DocumentRowLimit is the number of rows of a list shown in the HTML and PDF reports, the XLSX report has all of them
+const DocumentRowLimit = 100
+
+//go:embed templates/default.html
+var DefaultHTMLTemplate string
+
+var templateFuncs = template.FuncMap{
+	"percent": func(v float64) string {
+		return fmt.Sprintf("%.1f%%", v)
+	},
+	"formatTime": func(v any) string {
+		switch t := v.(type) {
+		case time.Time:
+			return formatTime(t)
+		case *time.Time:
+			if t != nil {
+				return formatTime(*t)
+			}
+		}
+		return "-"
+	},
+	"statusTitle": statusTitle,
+	"join":        strings.Join,
+	"limit": func(list any) any {
+		v := reflect.ValueOf(list)
+		if v.Kind() == reflect.Slice && v.Len() > DocumentRowLimit {
+			return v.Slice(0, DocumentRowLimit).Interface()
+		}
+		return list
+	},
+	"remaining": func(list any) int {
+		v := reflect.ValueOf(list)
+		if v.Kind() == reflect.Slice && v.Len() > DocumentRowLimit {
+			return v.Len() - DocumentRowLimit
+		}
+		return 0
+	},
+}
+
+// ParseHTMLTemplate parses a report template, an empty template is the default one
+func ParseHTMLTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultHTMLTemplate
+	}
+	return template.New("report").Funcs(templateFuncs).Parse(text)
+}
+
+func RenderHTML(w io.Writer, r *Report, text string) error {
+	tmpl, err := ParseHTMLTemplate(text)
+	if err != nil {
+		return fmt.Errorf("invalid report template: %w", err)
+	}
+	return tmpl.Execute(w, r)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+func statusTitle(status ControlStatus) string {
+	switch status {
+	case ControlStatusPassed:
+		return "Passed"
+	case ControlStatusFailed:
+		return "Failed"
+	default:
+		return "Not evaluated"
+	}
+}
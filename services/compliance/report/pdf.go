@@ -0,0 +1,405 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The PDF report is laid out with the standard Helvetica fonts, which every PDF reader has, so no font is
+// embedded. Text is encoded as WinAnsi, characters outside of it are replaced.
+
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	pageMargin   = 50.0
+	contentWidth = pageWidth - 2*pageMargin
+	cellPadding  = 3.0
+	lineSpacing  = 1.3
+)
+
+type pdfFont string
+
+const (
+	fontRegular pdfFont = "F1"
+	fontBold    pdfFont = "F2"
+)
+
+// helveticaWidths and helveticaBoldWidths are the glyph widths of the characters 32 to 126 in 1/1000 of the font size
+var helveticaWidths = []int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = []int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+func textWidth(s string, font pdfFont, size float64) float64 {
+	widths := helveticaWidths
+	if font == fontBold {
+		widths = helveticaBoldWidths
+	}
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			total += widths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// wrapText splits the text into lines that fit the width, words longer than the width are broken
+func wrapText(s string, font pdfFont, size, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(s, "\r", ""), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for textWidth(word, font, size) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				n := len(runes)
+				for n > 1 && textWidth(string(runes[:n]), font, size) > width {
+					n--
+				}
+				lines = append(lines, string(runes[:n]))
+				word = string(runes[n:])
+			}
+			if word == "" {
+				continue
+			}
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if textWidth(candidate, font, size) > width && line != "" {
+				lines = append(lines, line)
+				line = word
+			} else {
+				line = candidate
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func encodePDFText(s string) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			buf.WriteByte('\\')
+			buf.WriteByte(byte(r))
+		case r >= 32 && r <= 126:
+			buf.WriteByte(byte(r))
+		case r >= 160 && r <= 255:
+			buf.WriteString(fmt.Sprintf("\\%03o", r))
+		case r == '\t':
+			buf.WriteByte(' ')
+		default:
+			buf.WriteByte('?')
+		}
+	}
+	return buf.String()
+}
+
+type pdfColumn struct {
+	title string
+	width float64
+}
+
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func (d *pdfDocument) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pageHeight - pageMargin
+}
+
+// reserve starts a new page if the height does not fit the current one
+func (d *pdfDocument) reserve(height float64) {
+	if d.page == nil || d.y-height < pageMargin {
+		d.newPage()
+	}
+}
+
+func (d *pdfDocument) text(x, y float64, font pdfFont, size float64, s string) {
+	fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, encodePDFText(s))
+}
+
+func (d *pdfDocument) paragraph(font pdfFont, size float64, s string) {
+	for _, line := range wrapText(s, font, size, contentWidth) {
+		d.reserve(size * lineSpacing)
+		d.y -= size * lineSpacing
+		d.text(pageMargin, d.y, font, size, line)
+	}
+}
+
+func (d *pdfDocument) space(height float64) {
+	d.y -= height
+}
+
+func (d *pdfDocument) heading(s string, size float64) {
+	d.reserve(size*lineSpacing*3 + 20)
+	d.space(size * 0.8)
+	d.paragraph(fontBold, size, s)
+	d.y -= 4
+	fmt.Fprintf(d.page, "0.85 0.89 0.93 RG 0.8 w %.2f %.2f m %.2f %.2f l S 0 0 0 RG\n",
+		pageMargin, d.y, pageWidth-pageMargin, d.y)
+	d.y -= 4
+}
+
+func (d *pdfDocument) table(columns []pdfColumn, rows [][]string) {
+	const size = 8.0
+	var total float64
+	for _, c := range columns {
+		total += c.width
+	}
+	scale := contentWidth / total
+
+	layout := func(cells []string, font pdfFont) ([][]string, float64) {
+		wrapped := make([][]string, len(columns))
+		lines := 1
+		for i, c := range columns {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			wrapped[i] = wrapText(cell, font, size, c.width*scale-2*cellPadding)
+			if len(wrapped[i]) > lines {
+				lines = len(wrapped[i])
+			}
+		}
+		// a row never spans pages, the lines that do not fit a page are cut
+		height := float64(lines)*size*lineSpacing + 2*cellPadding
+		if height > pageHeight-2*pageMargin-20 {
+			height = pageHeight - 2*pageMargin - 20
+		}
+		return wrapped, height
+	}
+	draw := func(wrapped [][]string, height float64, font pdfFont, header bool) {
+		top := d.y
+		if header {
+			fmt.Fprintf(d.page, "0.94 0.96 0.97 rg %.2f %.2f %.2f %.2f re f 0 0 0 rg\n", pageMargin, top-height, contentWidth, height)
+		}
+		x := pageMargin
+		for i, c := range columns {
+			for j, line := range wrapped[i] {
+				y := top - cellPadding - float64(j+1)*size*lineSpacing + size*0.25
+				if y < top-height {
+					break
+				}
+				d.text(x+cellPadding, y, font, size, line)
+			}
+			fmt.Fprintf(d.page, "0.85 0.89 0.93 RG 0.5 w %.2f %.2f %.2f %.2f re S 0 0 0 RG\n", x, top-height, c.width*scale, height)
+			x += c.width * scale
+		}
+		d.y = top - height
+	}
+
+	titles := make([]string, len(columns))
+	for i, c := range columns {
+		titles[i] = c.title
+	}
+	header, headerHeight := layout(titles, fontBold)
+	d.reserve(headerHeight + 2*size*lineSpacing + 2*cellPadding)
+	draw(header, headerHeight, fontBold, true)
+	for _, cells := range rows {
+		wrapped, height := layout(cells, fontRegular)
+		// repeat the header on every page of the table
+		if d.y-height < pageMargin {
+			d.newPage()
+			draw(header, headerHeight, fontBold, true)
+		}
+		draw(wrapped, height, fontRegular, false)
+	}
+	d.space(8)
+}
+
+func (d *pdfDocument) write(w io.Writer, title, footer string) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// catalog, pages, fonts and info come first, then a page and its content per page
+	const firstPageObject = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObject+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (opencomply) >>", encodePDFText(title)))
+
+	for i, page := range d.pages {
+		d.page = page
+		pageFooter := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		d.text(pageWidth-pageMargin-textWidth(pageFooter, fontRegular, 8), pageMargin/2, fontRegular, 8, pageFooter)
+		if footer != "" {
+			line := wrapText(footer, fontRegular, 8, contentWidth-80)[0]
+			d.text(pageMargin, pageMargin/2, fontRegular, 8, line)
+		}
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPageObject+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// RenderPDF writes the report as a PDF document, the lists are truncated to DocumentRowLimit rows
+func RenderPDF(w io.Writer, r *Report) error {
+	d := &pdfDocument{}
+	d.newPage()
+
+	d.paragraph(fontBold, 20, r.Title)
+	d.space(4)
+	d.paragraph(fontRegular, 9, fmt.Sprintf("%s (%s) - compliance job %d - generated %s",
+		r.FrameworkTitle, r.FrameworkID, r.JobID, formatTime(r.GeneratedAt)))
+	if r.Introduction != "" {
+		d.space(8)
+		d.paragraph(fontRegular, 10, r.Introduction)
+	}
+
+	d.heading("Executive Summary", 14)
+	d.paragraph(fontBold, 28, fmt.Sprintf("%.1f%%", r.Score.Percentage))
+	d.paragraph(fontRegular, 9, "of the evaluated controls passed")
+	d.space(8)
+	d.table([]pdfColumn{{"Controls", 1}, {"Passed", 1}, {"Failed", 1}, {"Not evaluated", 1}, {"Passing results", 1}, {"Failing results", 1}},
+		[][]string{{
+			fmt.Sprint(r.Score.TotalControls), fmt.Sprint(r.Score.PassedControls), fmt.Sprint(r.Score.FailedControls),
+			fmt.Sprint(r.Score.NotEvaluatedControls), fmt.Sprint(r.Score.OkResults), fmt.Sprint(r.Score.AlarmResults),
+		}})
+	var severities [][]string
+	for _, s := range r.Score.BySeverity {
+		severities = append(severities, []string{string(s.Severity), fmt.Sprint(s.TotalControls), fmt.Sprint(s.FailedControls)})
+	}
+	d.table([]pdfColumn{{"Severity", 2}, {"Controls", 1}, {"Failed", 1}}, severities)
+	dataAsOf := "-"
+	if r.DataAsOf != nil {
+		dataAsOf = formatTime(*r.DataAsOf)
+	}
+	d.table([]pdfColumn{{"Job status", 1}, {"Started at", 1}, {"Data as of", 1}, {"Integrations", 2}},
+		[][]string{{r.JobStatus, formatTime(r.JobStartedAt), dataAsOf, strings.Join(r.IntegrationIDs, ", ")}})
+	if r.EvaluatedOnStaleData {
+		d.paragraph(fontBold, 9, "This job was evaluated on discovery data older than the freshness limit of the framework.")
+	}
+
+	d.heading("Controls", 14)
+	var controls [][]string
+	for _, c := range r.Controls {
+		controls = append(controls, []string{c.Title + "\n" + c.ID, string(c.Severity), statusTitle(c.Status), fmt.Sprint(c.Oks), fmt.Sprint(c.Alarms)})
+	}
+	d.table([]pdfColumn{{"Control", 6}, {"Severity", 1.2}, {"Status", 1.4}, {"Passing", 1}, {"Failing", 1}}, controls)
+
+	d.heading("Failing Resources", 14)
+	failed := r.FailedControls()
+	if len(failed) == 0 {
+		d.paragraph(fontRegular, 10, "No control failed.")
+	}
+	for _, c := range failed {
+		d.reserve(60)
+		d.paragraph(fontBold, 10, fmt.Sprintf("%s (%s, %s)", c.Title, c.ID, c.Severity))
+		if c.Description != "" {
+			d.paragraph(fontRegular, 9, c.Description)
+		}
+		d.space(4)
+		if len(c.FailingResources) == 0 {
+			d.paragraph(fontRegular, 9, fmt.Sprintf("%d failing results, the per resource results are not available for this job.", c.Alarms))
+			d.space(8)
+			continue
+		}
+		var rows [][]string
+		for i, resource := range c.FailingResources {
+			if i == DocumentRowLimit {
+				break
+			}
+			rows = append(rows, []string{resource.ResourceID, resource.ResourceType, resource.Reason})
+		}
+		d.table([]pdfColumn{{"Resource", 3}, {"Type", 2}, {"Reason", 4}}, rows)
+		if n := len(c.FailingResources) - DocumentRowLimit; n > 0 {
+			d.paragraph(fontRegular, 8, fmt.Sprintf("%d more failing resources are listed in the XLSX report.", n))
+		}
+	}
+
+	d.heading("Exceptions", 14)
+	if len(r.Exceptions) == 0 {
+		d.paragraph(fontRegular, 10, "No exceptions were raised.")
+	} else {
+		var rows [][]string
+		for i, e := range r.Exceptions {
+			if i == DocumentRowLimit {
+				break
+			}
+			rows = append(rows, []string{e.ControlTitle + "\n" + e.ControlID, string(e.Status), e.ResourceID, e.Reason})
+		}
+		d.table([]pdfColumn{{"Control", 3}, {"Status", 1}, {"Resource", 3}, {"Reason", 3}}, rows)
+		if n := len(r.Exceptions) - DocumentRowLimit; n > 0 {
+			d.paragraph(fontRegular, 8, fmt.Sprintf("%d more exceptions are listed in the XLSX report.", n))
+		}
+	}
+
+	d.heading("Evidence", 14)
+	for _, c := range r.Controls {
+		if c.Query == "" {
+			continue
+		}
+		d.reserve(40)
+		d.paragraph(fontBold, 9, fmt.Sprintf("%s (%s)", c.Title, c.ID))
+		if c.DocumentURI != "" {
+			d.paragraph(fontRegular, 8, c.DocumentURI)
+		}
+		d.paragraph(fontRegular, 7, c.Query)
+		d.space(6)
+	}
+	if len(r.Evidence) > 0 {
+		var rows [][]string
+		for i, e := range r.Evidence {
+			if i == DocumentRowLimit {
+				break
+			}
+			rows = append(rows, []string{e.ControlID, e.ResourceID, e.ResourceType, e.Reason})
+		}
+		d.table([]pdfColumn{{"Control", 2}, {"Passing resource", 3}, {"Type", 2}, {"Reason", 3}}, rows)
+		if n := len(r.Evidence) - DocumentRowLimit; n > 0 {
+			d.paragraph(fontRegular, 8, fmt.Sprintf("%d more passing results are listed in the XLSX report.", n))
+		}
+	}
+
+	return d.write(w, r.Title, r.Footer)
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type Format string
+
+const (
+	FormatHTML Format = "html"
+	FormatPDF  Format = "pdf"
+	FormatXLSX Format = "xlsx"
+)
+
+var Formats = []Format{FormatHTML, FormatPDF, FormatXLSX}
+
+func (f Format) IsValid() bool {
+	for _, format := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatPDF:
+		return "application/pdf"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Render renders the report in the format, the HTML template is only used by the HTML format
+func Render(format Format, r *Report, htmlTemplate string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatHTML:
+		err = RenderHTML(&buf, r, htmlTemplate)
+	case FormatPDF:
+		err = RenderPDF(&buf, r)
+	case FormatXLSX:
+		err = RenderXLSX(&buf, r)
+	default:
+		return nil, fmt.Errorf("invalid report format %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,247 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+type ControlStatus string
+
+const (
+	ControlStatusPassed       ControlStatus = "passed"
+	ControlStatusFailed       ControlStatus = "failed"
+	ControlStatusNotEvaluated ControlStatus = "not_evaluated"
+)
+
+// exceptionStatuses are the results that are neither passing nor failing, they are reported as exceptions
+var exceptionStatuses = []types.ComplianceStatus{
+	types.ComplianceStatusERROR, types.ComplianceStatusSKIP, types.ComplianceStatusINFO,
+}
+
+// Template customises the report of a framework, an empty field falls back to the default
+type Template struct {
+	Title        string
+	Introduction string
+	Footer       string
+	HTML         string
+}
+
+type Metadata struct {
+	FrameworkID          string
+	FrameworkTitle       string
+	FrameworkDescription string
+	JobID                uint
+	JobStatus            string
+	JobStartedAt         time.Time
+	DataAsOf             *time.Time
+	EvaluatedOnStaleData bool
+	IntegrationIDs       []string
+}
+
+// ControlDefinition is the part of the control definition shown in the report
+type ControlDefinition struct {
+	ID          string
+	Title       string
+	Description string
+	Severity    types.ComplianceResultSeverity
+	DocumentURI string
+	QueryID     string
+	Query       string
+}
+
+type Resource struct {
+	ResourceID   string
+	ResourceType string
+	Reason       string
+}
+
+type Control struct {
+	ControlDefinition
+	Status           ControlStatus
+	Oks              int64
+	Alarms           int64
+	FailingResources []Resource
+}
+
+// Exception is a result that could not be evaluated as passing or failing, e.g. an errored or skipped check
+type Exception struct {
+	ControlID    string
+	ControlTitle string
+	Status       types.ComplianceStatus
+	Resource
+}
+
+// Evidence is a passing result backing a passed control
+type Evidence struct {
+	ControlID    string
+	ControlTitle string
+	Resource
+}
+
+type SeverityScore struct {
+	Severity       types.ComplianceResultSeverity
+	TotalControls  int
+	FailedControls int
+}
+
+type Score struct {
+	TotalControls        int
+	PassedControls       int
+	FailedControls       int
+	NotEvaluatedControls int
+	// Percentage is the share of the evaluated controls that passed
+	Percentage   float64
+	OkResults    uint64
+	AlarmResults uint64
+	BySeverity   []SeverityScore
+}
+
+type Report struct {
+	Title        string
+	Introduction string
+	Footer       string
+	GeneratedAt  time.Time
+	Metadata
+	Score      Score
+	Controls   []Control
+	Exceptions []Exception
+	Evidence   []Evidence
+}
+
+// Build assembles the report of a compliance job from the control summary and the control view of the job.
+// The control view holds the per resource results, it may be nil for jobs that are not auditable.
+func Build(template Template, metadata Metadata, definitions []ControlDefinition,
+	summary *types.ComplianceJobReportControlSummary, view *types.ComplianceJobReportControlView) *Report {
+	r := &Report{
+		Title:        template.Title,
+		Introduction: template.Introduction,
+		Footer:       template.Footer,
+		GeneratedAt:  time.Now().UTC(),
+		Metadata:     metadata,
+	}
+	if r.Title == "" {
+		r.Title = metadata.FrameworkTitle + " Compliance Report"
+	}
+	if r.Introduction == "" {
+		r.Introduction = metadata.FrameworkDescription
+	}
+
+	if summary != nil {
+		r.Score.OkResults = summary.ComplianceSummary[types.ComplianceStatusOK]
+		r.Score.AlarmResults = summary.ComplianceSummary[types.ComplianceStatusALARM]
+	}
+
+	bySeverity := make(map[types.ComplianceResultSeverity]*SeverityScore)
+	for _, definition := range definitions {
+		control := Control{ControlDefinition: definition, Status: ControlStatusNotEvaluated}
+		if summary != nil {
+			if cs, ok := summary.Controls[definition.ID]; ok && cs != nil {
+				control.Oks = cs.Oks
+				control.Alarms = cs.Alarms
+			}
+		}
+		switch {
+		case control.Alarms > 0:
+			control.Status = ControlStatusFailed
+		case control.Oks > 0:
+			control.Status = ControlStatusPassed
+		}
+
+		if view != nil {
+			if result, ok := view.Controls[definition.ID]; ok {
+				control.FailingResources = toResources(result.Results[types.ComplianceStatusALARM])
+				for _, status := range exceptionStatuses {
+					for _, resource := range toResources(result.Results[status]) {
+						r.Exceptions = append(r.Exceptions, Exception{
+							ControlID:    definition.ID,
+							ControlTitle: definition.Title,
+							Status:       status,
+							Resource:     resource,
+						})
+					}
+				}
+				for _, resource := range toResources(result.Results[types.ComplianceStatusOK]) {
+					r.Evidence = append(r.Evidence, Evidence{
+						ControlID:    definition.ID,
+						ControlTitle: definition.Title,
+						Resource:     resource,
+					})
+				}
+			}
+		}
+
+		score, ok := bySeverity[definition.Severity]
+		if !ok {
+			score = &SeverityScore{Severity: definition.Severity}
+			bySeverity[definition.Severity] = score
+		}
+		score.TotalControls++
+		r.Score.TotalControls++
+		switch control.Status {
+		case ControlStatusPassed:
+			r.Score.PassedControls++
+		case ControlStatusFailed:
+			r.Score.FailedControls++
+			score.FailedControls++
+		default:
+			r.Score.NotEvaluatedControls++
+		}
+		r.Controls = append(r.Controls, control)
+	}
+	if evaluated := r.Score.PassedControls + r.Score.FailedControls; evaluated > 0 {
+		r.Score.Percentage = float64(r.Score.PassedControls) * 100 / float64(evaluated)
+	}
+
+	for _, score := range bySeverity {
+		r.Score.BySeverity = append(r.Score.BySeverity, *score)
+	}
+	sort.Slice(r.Score.BySeverity, func(i, j int) bool {
+		return r.Score.BySeverity[i].Severity.Level() > r.Score.BySeverity[j].Severity.Level()
+	})
+	// failed controls first, the most severe first
+	sort.SliceStable(r.Controls, func(i, j int) bool {
+		if statusOrder(r.Controls[i].Status) != statusOrder(r.Controls[j].Status) {
+			return statusOrder(r.Controls[i].Status) < statusOrder(r.Controls[j].Status)
+		}
+		if r.Controls[i].Severity.Level() != r.Controls[j].Severity.Level() {
+			return r.Controls[i].Severity.Level() > r.Controls[j].Severity.Level()
+		}
+		return r.Controls[i].ID < r.Controls[j].ID
+	})
+	return r
+}
+
+// FailedControls returns the failed controls, in the order of the report
+func (r *Report) FailedControls() []Control {
+	var controls []Control
+	for _, c := range r.Controls {
+		if c.Status == ControlStatusFailed {
+			controls = append(controls, c)
+		}
+	}
+	return controls
+}
+
+func toResources(findings []types.AuditResourceFinding) []Resource {
+	resources := make([]Resource, 0, len(findings))
+	for _, f := range findings {
+		resources = append(resources, Resource{ResourceID: f.ResourceID, ResourceType: f.ResourceType, Reason: f.Reason})
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].ResourceID < resources[j].ResourceID
+	})
+	return resources
+}
+
+func statusOrder(status ControlStatus) int {
+	switch status {
+	case ControlStatusFailed:
+		return 0
+	case ControlStatusNotEvaluated:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,212 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// sheet is a worksheet of the workbook, the first row is the header
+type sheet struct {
+	name string
+	rows [][]any
+}
+
+func (s *sheet) add(cells ...any) {
+	s.rows = append(s.rows, cells)
+}
+
+// RenderXLSX writes the report as a workbook with a sheet per section, the lists are not truncated
+func RenderXLSX(w io.Writer, r *Report) error {
+	summary := &sheet{name: "Summary"}
+	summary.add("Field", "Value")
+	summary.add("Report", r.Title)
+	summary.add("Framework", r.FrameworkTitle)
+	summary.add("Framework ID", r.FrameworkID)
+	summary.add("Compliance job", int64(r.JobID))
+	summary.add("Job status", r.JobStatus)
+	summary.add("Job started at", formatTime(r.JobStartedAt))
+	if r.DataAsOf != nil {
+		summary.add("Data as of", formatTime(*r.DataAsOf))
+	}
+	summary.add("Evaluated on stale data", strconv.FormatBool(r.EvaluatedOnStaleData))
+	summary.add("Integrations", strings.Join(r.IntegrationIDs, ", "))
+	summary.add("Generated at", formatTime(r.GeneratedAt))
+	summary.add("Score", fmt.Sprintf("%.1f%%", r.Score.Percentage))
+	summary.add("Controls", int64(r.Score.TotalControls))
+	summary.add("Passed controls", int64(r.Score.PassedControls))
+	summary.add("Failed controls", int64(r.Score.FailedControls))
+	summary.add("Not evaluated controls", int64(r.Score.NotEvaluatedControls))
+	summary.add("Passing results", int64(r.Score.OkResults))
+	summary.add("Failing results", int64(r.Score.AlarmResults))
+	for _, s := range r.Score.BySeverity {
+		summary.add(fmt.Sprintf("Failed %s severity controls", s.Severity), fmt.Sprintf("%d / %d", s.FailedControls, s.TotalControls))
+	}
+
+	controls := &sheet{name: "Controls"}
+	controls.add("Control ID", "Title", "Severity", "Status", "Passing", "Failing", "Description", "Document")
+	for _, c := range r.Controls {
+		controls.add(c.ID, c.Title, string(c.Severity), statusTitle(c.Status), c.Oks, c.Alarms, c.Description, c.DocumentURI)
+	}
+
+	failing := &sheet{name: "Failing Resources"}
+	failing.add("Control ID", "Control", "Severity", "Resource ID", "Resource Type", "Reason")
+	for _, c := range r.Controls {
+		for _, resource := range c.FailingResources {
+			failing.add(c.ID, c.Title, string(c.Severity), resource.ResourceID, resource.ResourceType, resource.Reason)
+		}
+	}
+
+	exceptions := &sheet{name: "Exceptions"}
+	exceptions.add("Control ID", "Control", "Status", "Resource ID", "Resource Type", "Reason")
+	for _, e := range r.Exceptions {
+		exceptions.add(e.ControlID, e.ControlTitle, string(e.Status), e.ResourceID, e.ResourceType, e.Reason)
+	}
+
+	evidence := &sheet{name: "Evidence"}
+	evidence.add("Control ID", "Control", "Resource ID", "Resource Type", "Reason")
+	for _, e := range r.Evidence {
+		evidence.add(e.ControlID, e.ControlTitle, e.ResourceID, e.ResourceType, e.Reason)
+	}
+
+	queries := &sheet{name: "Queries"}
+	queries.add("Control ID", "Query ID", "Query")
+	for _, c := range r.Controls {
+		if c.Query != "" {
+			queries.add(c.ID, c.QueryID, c.Query)
+		}
+	}
+
+	return writeWorkbook(w, []*sheet{summary, controls, failing, exceptions, evidence, queries})
+}
+
+func writeWorkbook(w io.Writer, sheets []*sheet) error {
+	zw := zip.NewWriter(w)
+
+	var contentTypes, workbook, workbookRels strings.Builder
+	contentTypes.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	for i, s := range sheets {
+		contentTypes.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1))
+		workbook.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(s.name), i+1, i+1))
+		workbookRels.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1))
+	}
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	workbookRels.WriteString(`</Relationships>`)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+			`</styleSheet>`},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+
+	for i, s := range sheets {
+		fw, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err = writeSheet(fw, s); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeSheet(w io.Writer, s *sheet) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(s.rows) > 1 {
+		buf.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+	buf.WriteString(`<sheetData>`)
+	for i, row := range s.rows {
+		buf.WriteString(fmt.Sprintf(`<row r="%d">`, i+1))
+		for j, cell := range row {
+			ref := columnName(j) + strconv.Itoa(i+1)
+			style := ""
+			if i == 0 {
+				style = ` s="1"`
+			}
+			switch v := cell.(type) {
+			case int64:
+				buf.WriteString(fmt.Sprintf(`<c r="%s"%s><v>%d</v></c>`, ref, style, v))
+			case string:
+				buf.WriteString(fmt.Sprintf(`<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escapeXML(truncateCell(v))))
+			default:
+				return fmt.Errorf("unsupported cell type %T", cell)
+			}
+		}
+		buf.WriteString(`</row>`)
+		// flush large sheets as they are written
+		if buf.Len() > 1024*1024 {
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+	}
+	buf.WriteString(`</sheetData></worksheet>`)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// truncateCell keeps the cell under the 32767 characters limit of spreadsheets
+func truncateCell(s string) string {
+	const maxCellLength = 32000
+	if utf8.RuneCountInString(s) <= maxCellLength {
+		return s
+	}
+	return string([]rune(s)[:maxCellLength]) + "..."
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
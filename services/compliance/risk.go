@@ -0,0 +1,207 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"github.com/opengovern/opencomply/services/compliance/es"
+	"github.com/opengovern/opencomply/services/compliance/risk"
+)
+
+const (
+	maxRiskFindings          = 50000
+	maxRiskResources         = 100000
+	riskRelationshipBatch    = 1000
+	riskRelationshipPageSize = 10000
+)
+
+// loadRiskAnalysis analyzes the active failing results with the relationships around their resources, the
+// returned flag is set when the results, the relationships or the attack paths were cut at their limits
+func (h *HttpHandler) loadRiskAnalysis(ctx context.Context, req api.RiskAnalysisRequest) (risk.Analysis, bool, error) {
+	opts := risk.Options{}
+	if req.MaxDepth != nil {
+		opts.MaxDepth = *req.MaxDepth
+	}
+	if req.Limit != nil {
+		opts.Limit = *req.Limit
+	}
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = risk.DefaultMaxDepth
+	}
+
+	controls, err := h.db.ListControlsBare(ctx)
+	if err != nil {
+		return risk.Analysis{}, false, fmt.Errorf("failed to list controls: %w", err)
+	}
+	controlCategories := make(map[string][]risk.Category, len(controls))
+	for _, control := range controls {
+		controlCategories[control.ID] = risk.ControlCategories(control.ID, control.Title, control.GetTagsMap())
+	}
+
+	var failedStatuses []string
+	for _, status := range types.GetFailedComplianceStatuses() {
+		failedStatuses = append(failedStatuses, string(status))
+	}
+	filters := []opengovernance.BoolFilter{
+		opengovernance.NewTermFilter("stateActive", "true"),
+		opengovernance.NewTermsFilter("complianceStatus", failedStatuses),
+	}
+	if len(req.IntegrationIDs) > 0 {
+		filters = append(filters, opengovernance.NewTermsFilter("integrationID", req.IntegrationIDs))
+	}
+	if len(req.BenchmarkIDs) > 0 {
+		filters = append(filters, opengovernance.NewTermsFilter("benchmarkID", req.BenchmarkIDs))
+	}
+	paginator, err := es.NewComplianceResultPaginator(h.client, types.ComplianceResultsIndex, filters, nil, nil)
+	if err != nil {
+		return risk.Analysis{}, false, fmt.Errorf("failed to query compliance results: %w", err)
+	}
+	defer paginator.Close(ctx)
+
+	truncated := false
+	resources := make(map[string]*risk.Resource)
+	findingCount := 0
+	for paginator.HasNext() && !truncated {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return risk.Analysis{}, false, fmt.Errorf("failed to query compliance results: %w", err)
+		}
+		for _, result := range page {
+			if findingCount >= maxRiskFindings {
+				truncated = true
+				break
+			}
+			findingCount++
+			r, ok := resources[result.PlatformResourceID]
+			if !ok {
+				r = &risk.Resource{ResourceRelationshipEndpoint: types.ResourceRelationshipEndpoint{
+					PlatformID:      result.PlatformResourceID,
+					ResourceID:      result.ResourceID,
+					ResourceName:    result.ResourceName,
+					ResourceType:    result.ResourceType,
+					IntegrationID:   result.IntegrationID,
+					IntegrationType: result.IntegrationType.String(),
+				}}
+				resources[result.PlatformResourceID] = r
+			}
+			r.Findings = append(r.Findings, risk.Finding{Result: result, Categories: controlCategories[result.ControlID]})
+		}
+	}
+
+	seenResources := make(map[string]bool, len(resources))
+	frontier := make([]string, 0, len(resources))
+	for id := range resources {
+		seenResources[id] = true
+		frontier = append(frontier, id)
+	}
+	seenEdges := make(map[string]bool)
+	var edges []types.ResourceRelationship
+	for hop := 0; hop < maxDepth && len(frontier) > 0 && !truncated; hop++ {
+		var next []string
+		for start := 0; start < len(frontier); start += riskRelationshipBatch {
+			end := min(start+riskRelationshipBatch, len(frontier))
+			batch, err := es.ListResourceRelationshipsTouching(ctx, h.logger, h.client, frontier[start:end], riskRelationshipPageSize)
+			if err != nil {
+				return risk.Analysis{}, false, fmt.Errorf("failed to query resource relationships: %w", err)
+			}
+			if len(batch) >= riskRelationshipPageSize {
+				truncated = true
+			}
+			for _, edge := range batch {
+				keys, _ := edge.KeysAndIndex()
+				key := fmt.Sprint(keys)
+				if seenEdges[key] {
+					continue
+				}
+				seenEdges[key] = true
+				edges = append(edges, edge)
+				for _, id := range []string{edge.Source.PlatformID, edge.Target.PlatformID} {
+					if !seenResources[id] {
+						seenResources[id] = true
+						next = append(next, id)
+					}
+				}
+			}
+		}
+		if len(seenResources) > maxRiskResources {
+			truncated = true
+		}
+		frontier = next
+	}
+
+	analysis := risk.Analyze(resources, edges, opts)
+	return analysis, truncated || analysis.Truncated, nil
+}
+
+func toApiRiskFinding(f risk.Finding) api.RiskFinding {
+	finding := api.RiskFinding{
+		ComplianceResultID: f.Result.EsID,
+		BenchmarkID:        f.Result.BenchmarkID,
+		ControlID:          f.Result.ControlID,
+		Severity:           f.Result.Severity,
+		Reason:             f.Result.Reason,
+		Categories:         make([]string, 0, len(f.Categories)),
+	}
+	for _, c := range f.Categories {
+		finding.Categories = append(finding.Categories, string(c))
+	}
+	return finding
+}
+
+func toApiRiskResource(r risk.Resource, withFindings bool) api.RiskResource {
+	resource := api.RiskResource{
+		PlatformResourceID: r.PlatformID,
+		ResourceID:         r.ResourceID,
+		ResourceName:       r.ResourceName,
+		ResourceType:       r.ResourceType,
+		IntegrationID:      r.IntegrationID,
+		IntegrationType:    r.IntegrationType,
+	}
+	if withFindings {
+		resource.Findings = make([]api.RiskFinding, 0, len(r.Findings))
+		for _, f := range r.Findings {
+			resource.Findings = append(resource.Findings, toApiRiskFinding(f))
+		}
+	}
+	return resource
+}
+
+func toApiAttackPaths(paths []risk.Path) []api.AttackPath {
+	result := make([]api.AttackPath, 0, len(paths))
+	for _, p := range paths {
+		path := api.AttackPath{
+			Score:      p.Score,
+			Categories: make([]string, 0, len(p.Categories)),
+			Steps:      make([]api.AttackPathStep, 0, len(p.Steps)),
+		}
+		for _, c := range p.Categories {
+			path.Categories = append(path.Categories, string(c))
+		}
+		for _, s := range p.Steps {
+			path.Steps = append(path.Steps, api.AttackPathStep{
+				Relationship: string(s.Relationship),
+				Resource:     toApiRiskResource(s.Resource, true),
+			})
+		}
+		result = append(result, path)
+	}
+	return result
+}
+
+func toApiFindingPriorities(priorities []risk.FindingPriority) []api.FindingPriority {
+	result := make([]api.FindingPriority, 0, len(priorities))
+	for _, p := range priorities {
+		result = append(result, api.FindingPriority{
+			Finding:         toApiRiskFinding(p.Finding),
+			Resource:        toApiRiskResource(p.Resource, false),
+			BaseScore:       p.BaseScore,
+			ContextualScore: p.ContextualScore,
+			AttackPathCount: p.AttackPathCount,
+		})
+	}
+	return result
+}
@@ -0,0 +1,382 @@
+package risk
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+const (
+	DefaultMaxDepth = 4
+	DefaultLimit    = 10
+
+	// CategoryTagKey is the control tag overriding the categories guessed from the control id and title
+	CategoryTagKey = "attack-path-category"
+
+	// hopDecay is the share of the score of a neighbour carried over every hop
+	hopDecay = 0.5
+	// maxExploredPaths bounds the path enumeration from every entry on dense graphs
+	maxExploredPaths = 200000
+)
+
+// networkAttachmentTypes are the attached-to targets placing a resource in a network, an attacker on the resource
+// does not gain anything on them so they are not attack steps
+var networkAttachmentTypes = map[string]bool{
+	"aws::ec2::subnet":                          true,
+	"aws::ec2::securitygroup":                   true,
+	"aws::ec2::networkinterface":                true,
+	"microsoft.network/networkinterfaces":       true,
+	"microsoft.network/networksecuritygroups":   true,
+	"microsoft.network/virtualnetworks/subnets": true,
+}
+
+// Category is the part a finding plays in an attack path
+type Category string
+
+const (
+	CategoryExposure  Category = "exposure"
+	CategoryPrivilege Category = "privilege"
+	CategoryData      Category = "data"
+)
+
+var categoryKeywords = map[Category][]string{
+	CategoryExposure:  {"public", "internet", "0.0.0.0", "exposed", "unrestricted", "anonymous", "ingress"},
+	CategoryPrivilege: {"admin", "privilege", "wildcard", "root", "iam", "assume", "mfa", "access_key", "access key"},
+	CategoryData:      {"encrypt", "bucket", "s3", "rds", "database", "snapshot", "backup", "kms", "secret", "storage", "sensitive"},
+}
+
+// ControlCategories returns the categories of a control, taken from its attack-path-category tag if set and
+// guessed from its id and title otherwise
+func ControlCategories(id, title string, tags map[string][]string) []Category {
+	if values, ok := tags[CategoryTagKey]; ok {
+		var categories []Category
+		for _, v := range values {
+			switch c := Category(strings.ToLower(v)); c {
+			case CategoryExposure, CategoryPrivilege, CategoryData:
+				categories = append(categories, c)
+			}
+		}
+		return categories
+	}
+
+	text := strings.ToLower(id + " " + title)
+	var categories []Category
+	for _, c := range []Category{CategoryExposure, CategoryPrivilege, CategoryData} {
+		for _, keyword := range categoryKeywords[c] {
+			if strings.Contains(text, keyword) {
+				categories = append(categories, c)
+				break
+			}
+		}
+	}
+	return categories
+}
+
+// SeverityWeight is the base score of a failing result
+func SeverityWeight(s types.ComplianceResultSeverity) float64 {
+	switch s {
+	case types.ComplianceResultSeverityCritical:
+		return 10
+	case types.ComplianceResultSeverityHigh:
+		return 7
+	case types.ComplianceResultSeverityMedium:
+		return 4
+	case types.ComplianceResultSeverityLow:
+		return 2
+	default:
+		return 0.5
+	}
+}
+
+// Finding is a failing compliance result with the categories of its control
+type Finding struct {
+	Result     types.ComplianceResult
+	Categories []Category
+}
+
+// Resource is a node of the graph, resources without findings only connect the others
+type Resource struct {
+	types.ResourceRelationshipEndpoint
+	Findings []Finding
+}
+
+// Score is the weight of the worst finding of the resource plus a tenth of the others
+func (r Resource) Score() float64 {
+	var worst, sum float64
+	for _, f := range r.Findings {
+		w := SeverityWeight(f.Result.Severity)
+		sum += w
+		if w > worst {
+			worst = w
+		}
+	}
+	return worst + (sum-worst)/10
+}
+
+func (r Resource) categories() map[Category]bool {
+	categories := make(map[Category]bool)
+	for _, f := range r.Findings {
+		for _, c := range f.Categories {
+			categories[c] = true
+		}
+	}
+	return categories
+}
+
+type Options struct {
+	// MaxDepth is the maximum number of relationships in an attack path and the reach of the context of a finding
+	MaxDepth int
+	// Limit is the number of attack paths returned
+	Limit int
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = DefaultMaxDepth
+	}
+	if o.Limit <= 0 {
+		o.Limit = DefaultLimit
+	}
+	return o
+}
+
+// Step is a resource of an attack path, Relationship is the edge leading to it and is empty for the entry point
+type Step struct {
+	Resource     Resource
+	Relationship types.ResourceRelationshipType
+}
+
+// Path starts at a resource with an exposure finding and ends at a resource with a privilege or data finding
+type Path struct {
+	Steps      []Step
+	Categories []Category
+	Score      float64
+}
+
+type FindingPriority struct {
+	Finding         Finding
+	Resource        Resource
+	BaseScore       float64
+	ContextualScore float64
+	AttackPathCount int
+}
+
+type Analysis struct {
+	Paths      []Path
+	TotalPaths int
+	Priorities []FindingPriority
+	// Truncated is set when the path enumeration from an entry hit its budget, the paths are incomplete
+	Truncated bool
+}
+
+type neighbour struct {
+	platformID   string
+	relationship types.ResourceRelationshipType
+}
+
+// Analyze combines the findings along the relationships of their resources, the resources only known from the
+// edges are added to the map. Attack paths follow the direction of the relationships, e.g. from a load balancer to
+// the instances it routes to and on to the roles they assume. Containment and network attachments are too broad
+// to be attack steps, they only connect resources for the context of a finding.
+func Analyze(resources map[string]*Resource, edges []types.ResourceRelationship, opts Options) Analysis {
+	opts = opts.withDefaults()
+
+	adjacency := make(map[string][]neighbour)
+	attackAdjacency := make(map[string][]neighbour)
+	for _, e := range edges {
+		for _, endpoint := range []types.ResourceRelationshipEndpoint{e.Source, e.Target} {
+			if _, ok := resources[endpoint.PlatformID]; !ok {
+				resources[endpoint.PlatformID] = &Resource{ResourceRelationshipEndpoint: endpoint}
+			}
+		}
+		if e.Source.PlatformID == e.Target.PlatformID {
+			continue
+		}
+		adjacency[e.Source.PlatformID] = append(adjacency[e.Source.PlatformID], neighbour{e.Target.PlatformID, e.Relationship})
+		adjacency[e.Target.PlatformID] = append(adjacency[e.Target.PlatformID], neighbour{e.Source.PlatformID, e.Relationship})
+		if !isAttackStep(e) {
+			continue
+		}
+		attackAdjacency[e.Source.PlatformID] = append(attackAdjacency[e.Source.PlatformID], neighbour{e.Target.PlatformID, e.Relationship})
+	}
+
+	paths, truncated := attackPaths(resources, attackAdjacency, opts.MaxDepth)
+	pathCounts := make(map[string]int)
+	for _, p := range paths {
+		for _, s := range p.Steps {
+			pathCounts[s.Resource.PlatformID]++
+		}
+	}
+
+	var priorities []FindingPriority
+	for id, r := range resources {
+		if len(r.Findings) == 0 {
+			continue
+		}
+		contextScore, reachable := neighbourhood(resources, adjacency, id, opts.MaxDepth)
+		for _, f := range r.Findings {
+			base := SeverityWeight(f.Result.Severity)
+			categories := make(map[Category]bool)
+			for _, c := range f.Categories {
+				categories[c] = true
+			}
+			for c := range reachable {
+				categories[c] = true
+			}
+			multiplier := 1.0
+			if len(categories) > 1 {
+				multiplier += float64(len(categories)-1) / 2
+			}
+			priorities = append(priorities, FindingPriority{
+				Finding:         f,
+				Resource:        *r,
+				BaseScore:       base,
+				ContextualScore: (base + contextScore) * multiplier,
+				AttackPathCount: pathCounts[id],
+			})
+		}
+	}
+	sort.SliceStable(priorities, func(i, j int) bool {
+		if priorities[i].ContextualScore != priorities[j].ContextualScore {
+			return priorities[i].ContextualScore > priorities[j].ContextualScore
+		}
+		return priorities[i].Finding.Result.EsID < priorities[j].Finding.Result.EsID
+	})
+
+	analysis := Analysis{
+		TotalPaths: len(paths),
+		Priorities: priorities,
+		Truncated:  truncated,
+	}
+	if len(paths) > opts.Limit {
+		paths = paths[:opts.Limit]
+	}
+	analysis.Paths = paths
+	return analysis
+}
+
+func isAttackStep(e types.ResourceRelationship) bool {
+	switch e.Relationship {
+	case types.ResourceRelationshipContains:
+		return false
+	case types.ResourceRelationshipAttachedTo:
+		return !networkAttachmentTypes[strings.ToLower(e.Target.ResourceType)]
+	}
+	return true
+}
+
+// neighbourhood returns the decayed score of the resources around a resource and the finding categories among them
+func neighbourhood(resources map[string]*Resource, adjacency map[string][]neighbour, start string, maxDepth int) (float64, map[Category]bool) {
+	var score float64
+	categories := make(map[Category]bool)
+	visited := map[string]bool{start: true}
+	frontier := []string{start}
+	weight := 1.0
+	for hop := 0; hop < maxDepth && len(frontier) > 0; hop++ {
+		weight *= hopDecay
+		var next []string
+		for _, id := range frontier {
+			for _, n := range adjacency[id] {
+				if visited[n.platformID] {
+					continue
+				}
+				visited[n.platformID] = true
+				next = append(next, n.platformID)
+				r := resources[n.platformID]
+				score += r.Score() * weight
+				for c := range r.categories() {
+					categories[c] = true
+				}
+			}
+		}
+		frontier = next
+	}
+	return score, categories
+}
+
+// attackPaths enumerates the simple paths from the exposed resources, keeping the best path per entry and target.
+// It reports whether the enumeration from an entry was cut short by maxExploredPaths.
+func attackPaths(resources map[string]*Resource, adjacency map[string][]neighbour, maxDepth int) ([]Path, bool) {
+	type pair struct{ from, to string }
+	best := make(map[pair]Path)
+	explored := 0
+	truncated := false
+
+	var walk func(steps []Step, visited map[string]bool)
+	walk = func(steps []Step, visited map[string]bool) {
+		explored++
+		current := steps[len(steps)-1].Resource
+		if len(steps) > 1 {
+			categories := current.categories()
+			if categories[CategoryPrivilege] || categories[CategoryData] {
+				p := newPath(steps)
+				k := pair{steps[0].Resource.PlatformID, current.PlatformID}
+				if existing, ok := best[k]; !ok || p.Score > existing.Score {
+					best[k] = p
+				}
+			}
+		}
+		if len(steps)-1 >= maxDepth {
+			return
+		}
+		if explored >= maxExploredPaths {
+			truncated = true
+			return
+		}
+		for _, n := range adjacency[current.PlatformID] {
+			if visited[n.platformID] {
+				continue
+			}
+			visited[n.platformID] = true
+			walk(append(steps, Step{Resource: *resources[n.platformID], Relationship: n.relationship}), visited)
+			delete(visited, n.platformID)
+		}
+	}
+
+	var entries []string
+	for id, r := range resources {
+		if r.categories()[CategoryExposure] {
+			entries = append(entries, id)
+		}
+	}
+	sort.Strings(entries)
+	for _, id := range entries {
+		explored = 0
+		walk([]Step{{Resource: *resources[id]}}, map[string]bool{id: true})
+	}
+
+	paths := make([]Path, 0, len(best))
+	for _, p := range best {
+		paths = append(paths, p)
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		if paths[i].Score != paths[j].Score {
+			return paths[i].Score > paths[j].Score
+		}
+		if len(paths[i].Steps) != len(paths[j].Steps) {
+			return len(paths[i].Steps) < len(paths[j].Steps)
+		}
+		return paths[i].Steps[0].Resource.PlatformID < paths[j].Steps[0].Resource.PlatformID
+	})
+	return paths, truncated
+}
+
+// newPath scores a path with the scores of its resources, raised by half for every finding category past the first
+func newPath(steps []Step) Path {
+	p := Path{Steps: append([]Step(nil), steps...)}
+	categories := make(map[Category]bool)
+	for _, s := range steps {
+		p.Score += s.Resource.Score()
+		for c := range s.Resource.categories() {
+			categories[c] = true
+		}
+	}
+	for _, c := range []Category{CategoryExposure, CategoryPrivilege, CategoryData} {
+		if categories[c] {
+			p.Categories = append(p.Categories, c)
+		}
+	}
+	p.Score *= 1 + float64(len(p.Categories)-1)/2
+	return p
+}
@@ -0,0 +1,316 @@
+package risk
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+func TestControlCategories(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		title string
+		tags  map[string][]string
+		want  []Category
+	}{
+		{
+			name:  "guessed from the id",
+			id:    "aws_s3_bucket_public_access_blocked",
+			title: "S3 buckets should block public access",
+			want:  []Category{CategoryExposure, CategoryData},
+		},
+		{
+			name:  "guessed from the title",
+			id:    "control_1",
+			title: "IAM policies should not allow full admin privileges",
+			want:  []Category{CategoryPrivilege},
+		},
+		{
+			name:  "no category",
+			id:    "aws_ec2_instance_detailed_monitoring_enabled",
+			title: "EC2 instances should have detailed monitoring enabled",
+			want:  nil,
+		},
+		{
+			name:  "tag overrides the guess",
+			id:    "aws_s3_bucket_public_access_blocked",
+			title: "S3 buckets should block public access",
+			tags:  map[string][]string{CategoryTagKey: {"Privilege"}},
+			want:  []Category{CategoryPrivilege},
+		},
+		{
+			name: "unknown tag values are dropped",
+			id:   "aws_s3_bucket_public_access_blocked",
+			tags: map[string][]string{CategoryTagKey: {"lateral-movement"}},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ControlCategories(tt.id, tt.title, tt.tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ControlCategories() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		severities []types.ComplianceResultSeverity
+		want       float64
+	}{
+		{name: "no findings", want: 0},
+		{name: "single finding", severities: []types.ComplianceResultSeverity{types.ComplianceResultSeverityCritical}, want: 10},
+		{
+			name: "worst finding plus a tenth of the others",
+			severities: []types.ComplianceResultSeverity{types.ComplianceResultSeverityLow,
+				types.ComplianceResultSeverityCritical, types.ComplianceResultSeverityHigh},
+			want: 10.9,
+		},
+		{name: "unknown severity", severities: []types.ComplianceResultSeverity{""}, want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Resource
+			for _, s := range tt.severities {
+				r.Findings = append(r.Findings, Finding{Result: types.ComplianceResult{Severity: s}})
+			}
+			if got := r.Score(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// graph is the findings and resource types of every platform id and the edges as source, relationship and target
+// of a test case
+type graph struct {
+	findings      map[string][]Finding
+	resourceTypes map[string]string
+	edges         [][3]string
+}
+
+func (g graph) endpoint(id string) types.ResourceRelationshipEndpoint {
+	return types.ResourceRelationshipEndpoint{PlatformID: id, ResourceType: g.resourceTypes[id]}
+}
+
+func (g graph) build() (map[string]*Resource, []types.ResourceRelationship) {
+	resources := make(map[string]*Resource)
+	for id, findings := range g.findings {
+		resources[id] = &Resource{
+			ResourceRelationshipEndpoint: g.endpoint(id),
+			Findings:                     findings,
+		}
+	}
+	var edges []types.ResourceRelationship
+	for _, e := range g.edges {
+		edges = append(edges, types.ResourceRelationship{
+			Source:       g.endpoint(e[0]),
+			Relationship: types.ResourceRelationshipType(e[1]),
+			Target:       g.endpoint(e[2]),
+		})
+	}
+	return resources, edges
+}
+
+func finding(esID string, severity types.ComplianceResultSeverity, categories ...Category) Finding {
+	return Finding{Result: types.ComplianceResult{EsID: esID, Severity: severity}, Categories: categories}
+}
+
+func TestAnalyzeAttackPaths(t *testing.T) {
+	exposedLB := []Finding{finding("lb-open", types.ComplianceResultSeverityHigh, CategoryExposure)}
+	adminRole := []Finding{finding("role-admin", types.ComplianceResultSeverityCritical, CategoryPrivilege)}
+
+	tests := []struct {
+		name           string
+		graph          graph
+		opts           Options
+		wantPaths      [][]string
+		wantTotalPaths int
+		wantFirstScore float64
+	}{
+		{
+			name: "exposure reaches a privilege through a resource without findings",
+			graph: graph{
+				findings: map[string][]Finding{"lb": exposedLB, "role": adminRole},
+				edges: [][3]string{
+					{"lb", "routes-to", "instance"},
+					{"instance", "assumes-role", "role"},
+				},
+			},
+			wantPaths:      [][]string{{"lb", "instance", "role"}},
+			wantTotalPaths: 1,
+			wantFirstScore: (7 + 10) * 1.5,
+		},
+		{
+			name: "containment is not an attack step",
+			graph: graph{
+				findings: map[string][]Finding{"lb": exposedLB, "role": adminRole},
+				edges:    [][3]string{{"lb", "contains", "role"}},
+			},
+			wantPaths:      [][]string{},
+			wantTotalPaths: 0,
+		},
+		{
+			name: "relationships are followed in their direction",
+			graph: graph{
+				findings: map[string][]Finding{"lb": exposedLB, "role": adminRole},
+				edges: [][3]string{
+					{"instance", "routes-to", "lb"},
+					{"instance", "assumes-role", "role"},
+				},
+			},
+			wantPaths:      [][]string{},
+			wantTotalPaths: 0,
+		},
+		{
+			name: "network attachments are not attack steps",
+			graph: graph{
+				findings: map[string][]Finding{
+					"lb":     exposedLB,
+					"sg":     {finding("sg-admin-ports", types.ComplianceResultSeverityHigh, CategoryPrivilege)},
+					"volume": {finding("volume-unencrypted", types.ComplianceResultSeverityLow, CategoryData)},
+				},
+				resourceTypes: map[string]string{
+					"instance": "AWS::EC2::Instance",
+					"sg":       "AWS::EC2::SecurityGroup",
+					"volume":   "AWS::EC2::Volume",
+				},
+				edges: [][3]string{
+					{"lb", "routes-to", "instance"},
+					{"instance", "attached-to", "sg"},
+					{"instance", "attached-to", "volume"},
+				},
+			},
+			wantPaths:      [][]string{{"lb", "instance", "volume"}},
+			wantTotalPaths: 1,
+			wantFirstScore: (7 + 2) * 1.5,
+		},
+		{
+			name: "paths are bounded by the max depth",
+			graph: graph{
+				findings: map[string][]Finding{"lb": exposedLB, "role": adminRole},
+				edges: [][3]string{
+					{"lb", "routes-to", "a"},
+					{"a", "routes-to", "b"},
+					{"b", "assumes-role", "role"},
+				},
+			},
+			opts:           Options{MaxDepth: 2},
+			wantPaths:      [][]string{},
+			wantTotalPaths: 0,
+		},
+		{
+			name: "the best path per entry and target is kept",
+			graph: graph{
+				findings: map[string][]Finding{
+					"lb":    exposedLB,
+					"role":  adminRole,
+					"vuln":  {finding("vuln-cve", types.ComplianceResultSeverityMedium)},
+					"plain": nil,
+				},
+				edges: [][3]string{
+					{"lb", "routes-to", "plain"},
+					{"lb", "routes-to", "vuln"},
+					{"plain", "assumes-role", "role"},
+					{"vuln", "assumes-role", "role"},
+				},
+			},
+			wantPaths:      [][]string{{"lb", "vuln", "role"}},
+			wantTotalPaths: 1,
+			wantFirstScore: (7 + 4 + 10) * 1.5,
+		},
+		{
+			name: "limit keeps the best paths and counts all of them",
+			graph: graph{
+				findings: map[string][]Finding{
+					"lb-1":   exposedLB,
+					"lb-2":   exposedLB,
+					"role":   adminRole,
+					"volume": {finding("volume-unencrypted", types.ComplianceResultSeverityLow, CategoryData)},
+				},
+				edges: [][3]string{
+					{"lb-1", "routes-to", "instance"},
+					{"lb-2", "routes-to", "instance"},
+					{"instance", "assumes-role", "role"},
+					{"instance", "attached-to", "volume"},
+				},
+			},
+			opts:           Options{Limit: 2},
+			wantPaths:      [][]string{{"lb-1", "instance", "role"}, {"lb-2", "instance", "role"}},
+			wantTotalPaths: 4,
+			wantFirstScore: (7 + 10) * 1.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, edges := tt.graph.build()
+			analysis := Analyze(resources, edges, tt.opts)
+
+			paths := make([][]string, 0, len(analysis.Paths))
+			for _, p := range analysis.Paths {
+				var ids []string
+				for _, s := range p.Steps {
+					ids = append(ids, s.Resource.PlatformID)
+				}
+				paths = append(paths, ids)
+			}
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("paths %v, want %v", paths, tt.wantPaths)
+			}
+			if analysis.TotalPaths != tt.wantTotalPaths {
+				t.Errorf("total paths %d, want %d", analysis.TotalPaths, tt.wantTotalPaths)
+			}
+			if len(analysis.Paths) > 0 && math.Abs(analysis.Paths[0].Score-tt.wantFirstScore) > 1e-9 {
+				t.Errorf("score of the first path %v, want %v", analysis.Paths[0].Score, tt.wantFirstScore)
+			}
+		})
+	}
+}
+
+func TestAnalyzePriorities(t *testing.T) {
+	resources, edges := graph{
+		findings: map[string][]Finding{
+			"sg":     {finding("sg-open", types.ComplianceResultSeverityHigh, CategoryExposure)},
+			"db":     {finding("db-unencrypted", types.ComplianceResultSeverityCritical, CategoryData)},
+			"lonely": {finding("lonely-untagged", types.ComplianceResultSeverityLow)},
+		},
+		edges: [][3]string{{"sg", "contains", "db"}},
+	}.build()
+
+	analysis := Analyze(resources, edges, Options{})
+
+	want := []struct {
+		esID       string
+		base       float64
+		contextual float64
+	}{
+		// the categories of the neighbours raise the score by half per extra category
+		{esID: "db-unencrypted", base: 10, contextual: (10 + 7*hopDecay) * 1.5},
+		{esID: "sg-open", base: 7, contextual: (7 + 10*hopDecay) * 1.5},
+		{esID: "lonely-untagged", base: 2, contextual: 2},
+	}
+	if len(analysis.Priorities) != len(want) {
+		t.Fatalf("expected %d priorities, got %d", len(want), len(analysis.Priorities))
+	}
+	for i, w := range want {
+		p := analysis.Priorities[i]
+		if p.Finding.Result.EsID != w.esID {
+			t.Errorf("priority %d: got %s, want %s", i, p.Finding.Result.EsID, w.esID)
+			continue
+		}
+		if p.BaseScore != w.base || math.Abs(p.ContextualScore-w.contextual) > 1e-9 {
+			t.Errorf("%s: scores %v/%v, want %v/%v", w.esID, p.BaseScore, p.ContextualScore, w.base, w.contextual)
+		}
+		if p.AttackPathCount != 0 {
+			t.Errorf("%s: expected no attack paths over containment, got %d", w.esID, p.AttackPathCount)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package compliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	authApi "github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"github.com/opengovern/opencomply/services/compliance/db"
+	"go.uber.org/zap"
+)
+
+func (h *HttpHandler) getSeverityOverrideControl(echoCtx echo.Context) (*db.Control, error) {
+	controlID := echoCtx.Param("control_id")
+	control, err := h.db.GetControl(echoCtx.Request().Context(), controlID)
+	if err != nil {
+		h.logger.Error("failed to get control", zap.String("control_id", controlID), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to get control")
+	}
+	if control == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("control %s not found", controlID))
+	}
+	return control, nil
+}
+
+func (h *HttpHandler) getSeverityOverride(echoCtx echo.Context, controlID string) (*db.ControlSeverityOverride, error) {
+	id, err := strconv.ParseUint(echoCtx.Param("override_id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid override id")
+	}
+	override, err := h.db.GetControlSeverityOverride(echoCtx.Request().Context(), controlID, uint(id))
+	if err != nil {
+		h.logger.Error("failed to get severity override", zap.Uint64("overrideId", id), zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to get severity override")
+	}
+	if override == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "severity override not found")
+	}
+	return override, nil
+}
+
+// newSeverityOverride validates the request of a severity override, the scope must exist
+func (h *HttpHandler) newSeverityOverride(echoCtx echo.Context, controlID string) (*db.ControlSeverityOverride, error) {
+	var req api.SeverityOverrideRequest
+	if err := bindValidate(echoCtx, &req); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	severity := types.ParseComplianceResultSeverity(req.Severity)
+	if severity == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid severity %s", req.Severity))
+	}
+	if err := h.validateSeverityOverrideScope(echoCtx.Request().Context(), req.ScopeType, req.ScopeValue); err != nil {
+		return nil, err
+	}
+	return &db.ControlSeverityOverride{
+		ControlID:  controlID,
+		ScopeType:  req.ScopeType,
+		ScopeValue: req.ScopeValue,
+		Severity:   severity,
+		Reason:     req.Reason,
+	}, nil
+}
+
+func (h *HttpHandler) validateSeverityOverrideScope(ctx context.Context, scopeType api.SeverityOverrideScopeType, scopeValue string) error {
+	clientCtx := &httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole}
+	switch scopeType {
+	case api.SeverityOverrideScopeAll:
+		if scopeValue != "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "scope_value requires a scope_type")
+		}
+	case api.SeverityOverrideScopeIntegrationGroup:
+		if _, err := h.integrationClient.GetIntegrationGroup(clientCtx, scopeValue); err != nil {
+			var httpErr *echo.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("integration group %s not found", scopeValue))
+			}
+			h.logger.Error("failed to get integration group", zap.String("integration_group", scopeValue), zap.Error(err))
+			return err
+		}
+	case api.SeverityOverrideScopeResourceCollection:
+		if _, err := h.inventoryClient.GetResourceCollectionMetadata(clientCtx, scopeValue); err != nil {
+			var httpErr *echo.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("resource collection %s not found", scopeValue))
+			}
+			h.logger.Error("failed to get resource collection", zap.String("resource_collection_id", scopeValue), zap.Error(err))
+			return err
+		}
+	case api.SeverityOverrideScopeTag:
+		if _, _, ok := api.ParseSeverityOverrideTagScope(scopeValue); !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, "tag scope_value must be written key=value")
+		}
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid scope_type %s", scopeType))
+	}
+	return nil
+}
+
+// resolveSeverityOverride resolves the members of the scope of an override, overrides whose integration group
+// or resource collection no longer exists are returned as nil
+func (h *HttpHandler) resolveSeverityOverride(ctx context.Context, o db.ControlSeverityOverride) (*api.ResolvedSeverityOverride, error) {
+	clientCtx := &httpclient.Context{Ctx: ctx, UserRole: authApi.AdminRole}
+	resolved := api.ResolvedSeverityOverride{
+		ID:         o.ID,
+		ScopeType:  o.ScopeType,
+		ScopeValue: o.ScopeValue,
+		Severity:   o.Severity,
+		UpdatedAt:  o.UpdatedAt,
+	}
+
+	var err error
+	var httpErr *echo.HTTPError
+	switch o.ScopeType {
+	case api.SeverityOverrideScopeIntegrationGroup:
+		group, groupErr := h.integrationClient.GetIntegrationGroup(clientCtx, o.ScopeValue)
+		if err = groupErr; err == nil {
+			resolved.IntegrationIDs = group.IntegrationIds
+		}
+	case api.SeverityOverrideScopeResourceCollection:
+		rc, rcErr := h.inventoryClient.GetResourceCollectionMetadata(clientCtx, o.ScopeValue)
+		if err = rcErr; err == nil {
+			resolved.ResourceCollectionFilters = rc.Filters
+		}
+	}
+	if err != nil {
+		if errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound {
+			h.logger.Warn("severity override scope not found", zap.Uint("overrideId", o.ID),
+				zap.String("scope_type", string(o.ScopeType)), zap.String("scope_value", o.ScopeValue))
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &resolved, nil
+}
@@ -0,0 +1,165 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"github.com/opengovern/opencomply/services/compliance/es"
+	"github.com/opengovern/opencomply/services/compliance/sla"
+)
+
+const (
+	maxAgeingReportResults = 200000
+	ageingReportOwnerBatch = 1000
+)
+
+var slaPolicySeverities = []types.ComplianceResultSeverity{
+	types.ComplianceResultSeverityCritical,
+	types.ComplianceResultSeverityHigh,
+	types.ComplianceResultSeverityMedium,
+	types.ComplianceResultSeverityLow,
+	types.ComplianceResultSeverityNone,
+}
+
+// listSLAPolicies returns the policy of every severity, the severities without a configured policy get the
+// default one
+func (h *HttpHandler) listSLAPolicies(ctx context.Context) ([]api.SLAPolicy, error) {
+	configured, err := h.db.ListSLAPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bySeverity := make(map[types.ComplianceResultSeverity]api.SLAPolicy, len(configured))
+	for _, p := range configured {
+		updatedAt := p.UpdatedAt
+		bySeverity[p.Severity] = api.SLAPolicy{
+			Severity:          p.Severity,
+			ResolveWithinDays: p.ResolveWithinDays,
+			UpdatedBy:         p.UpdatedBy,
+			UpdatedAt:         &updatedAt,
+		}
+	}
+
+	policies := make([]api.SLAPolicy, 0, len(slaPolicySeverities))
+	for _, severity := range slaPolicySeverities {
+		policy, ok := bySeverity[severity]
+		if !ok {
+			policy = api.SLAPolicy{
+				Severity:          severity,
+				ResolveWithinDays: sla.DefaultResolveWithinDays[severity],
+				IsDefault:         true,
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// loadAgeingReport ages the active failing results with the owner tag of their resources, the returned flag is
+// set when the results were cut at their limit
+func (h *HttpHandler) loadAgeingReport(ctx context.Context, req api.AgeingReportRequest, ownerTagKey string, now time.Time) (sla.Report, bool, error) {
+	var failedStatuses []string
+	for _, status := range types.GetFailedComplianceStatuses() {
+		failedStatuses = append(failedStatuses, string(status))
+	}
+	filters := []opengovernance.BoolFilter{
+		opengovernance.NewTermFilter("stateActive", "true"),
+		opengovernance.NewTermsFilter("complianceStatus", failedStatuses),
+	}
+	if len(req.IntegrationIDs) > 0 {
+		filters = append(filters, opengovernance.NewTermsFilter("integrationID", req.IntegrationIDs))
+	}
+	if len(req.BenchmarkIDs) > 0 {
+		filters = append(filters, opengovernance.NewTermsFilter("benchmarkID", req.BenchmarkIDs))
+	}
+	paginator, err := es.NewComplianceResultPaginator(h.client, types.ComplianceResultsIndex, filters, nil, nil)
+	if err != nil {
+		return sla.Report{}, false, fmt.Errorf("failed to query compliance results: %w", err)
+	}
+	defer paginator.Close(ctx)
+
+	truncated := false
+	var entries []sla.Entry
+	for paginator.HasNext() && !truncated {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return sla.Report{}, false, fmt.Errorf("failed to query compliance results: %w", err)
+		}
+		for _, result := range page {
+			if len(entries) >= maxAgeingReportResults {
+				truncated = true
+				break
+			}
+			entries = append(entries, sla.Entry{Result: result})
+		}
+	}
+
+	seen := make(map[string]bool)
+	var platformIDs []string
+	for _, e := range entries {
+		if e.Result.PlatformResourceID == "" || seen[e.Result.PlatformResourceID] {
+			continue
+		}
+		seen[e.Result.PlatformResourceID] = true
+		platformIDs = append(platformIDs, e.Result.PlatformResourceID)
+	}
+	owners := make(map[string]string)
+	for start := 0; start < len(platformIDs); start += ageingReportOwnerBatch {
+		end := min(start+ageingReportOwnerBatch, len(platformIDs))
+		values, err := es.GetLookupResourceTagValues(ctx, h.client, platformIDs[start:end], ownerTagKey)
+		if err != nil {
+			return sla.Report{}, false, fmt.Errorf("failed to query resource owners: %w", err)
+		}
+		for id, owner := range values {
+			owners[id] = owner
+		}
+	}
+	for i := range entries {
+		entries[i].Owner = owners[entries[i].Result.PlatformResourceID]
+	}
+
+	return sla.Build(entries, now), truncated, nil
+}
+
+func toApiAgeingReportGroup(g sla.Group) api.AgeingReportGroup {
+	return api.AgeingReportGroup{
+		Key:            g.Key,
+		OpenCount:      g.Open,
+		WithinSLACount: g.Within,
+		AtRiskCount:    g.AtRisk,
+		BreachedCount:  g.Breached,
+		NoSLACount:     g.NoSLA,
+		AverageAgeDays: g.AverageAge.Hours() / 24,
+		OldestAgeDays:  g.OldestAge.Hours() / 24,
+	}
+}
+
+func toApiAgeingReportGroups(groups []sla.Group) []api.AgeingReportGroup {
+	result := make([]api.AgeingReportGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, toApiAgeingReportGroup(g))
+	}
+	return result
+}
+
+func toApiAgeingReportBuckets(buckets []sla.Bucket) []api.AgeingReportBucket {
+	result := make([]api.AgeingReportBucket, 0, len(buckets))
+	for _, b := range buckets {
+		bucket := api.AgeingReportBucket{
+			MinDays: b.MinDays,
+			Count:   b.Count,
+		}
+		if b.MaxDays > 0 {
+			maxDays := b.MaxDays
+			bucket.MaxDays = &maxDays
+			bucket.Label = fmt.Sprintf("%d-%d days", b.MinDays, b.MaxDays)
+		} else {
+			bucket.Label = fmt.Sprintf("%d+ days", b.MinDays)
+		}
+		result = append(result, bucket)
+	}
+	return result
+}
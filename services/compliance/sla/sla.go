@@ -0,0 +1,213 @@
+package sla
+
+import (
+	"sort"
+	"time"
+
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/compliance/ownership"
+)
+
+const day = 24 * time.Hour
+
+// DefaultResolveWithinDays are the SLA policies of the severities without a configured policy
+var DefaultResolveWithinDays = map[types.ComplianceResultSeverity]int{
+	types.ComplianceResultSeverityCritical: 7,
+	types.ComplianceResultSeverityHigh:     30,
+	types.ComplianceResultSeverityMedium:   90,
+	types.ComplianceResultSeverityLow:      180,
+}
+
+// Policies are the number of days the failing results of each severity must be resolved in, severities
+// missing or set to zero have no SLA
+type Policies map[types.ComplianceResultSeverity]int
+
+// Track carries the first seen and resolved times of the previous state of a result over to its new state
+// and sets its SLA due time from the policy of its severity. Results failing for the first time are first
+// seen at their evaluation, previous states from before the tracking are taken as first seen at their own
+// evaluation.
+func Track(result *types.ComplianceResult, previous *types.ComplianceResult, policies Policies) {
+	var previousFirstSeenAt int64
+	if previous != nil && previous.IsOpen() {
+		previousFirstSeenAt = previous.FirstSeenAt
+		if previousFirstSeenAt == 0 {
+			previousFirstSeenAt = previous.EvaluatedAt
+		}
+	}
+
+	if result.IsOpen() {
+		result.FirstSeenAt = previousFirstSeenAt
+		if result.FirstSeenAt == 0 {
+			result.FirstSeenAt = result.EvaluatedAt
+		}
+		result.ResolvedAt = nil
+		result.SLADueAt = nil
+		if days := policies[result.Severity]; days > 0 {
+			dueAt := time.UnixMilli(result.FirstSeenAt).Add(time.Duration(days) * day).UnixMilli()
+			result.SLADueAt = &dueAt
+		}
+		return
+	}
+
+	if previous == nil {
+		return
+	}
+	if previous.IsOpen() {
+		resolvedAt := result.EvaluatedAt
+		result.FirstSeenAt = previousFirstSeenAt
+		result.ResolvedAt = &resolvedAt
+		result.SLADueAt = previous.SLADueAt
+		return
+	}
+	result.FirstSeenAt = previous.FirstSeenAt
+	result.ResolvedAt = previous.ResolvedAt
+	result.SLADueAt = previous.SLADueAt
+}
+
+// Resolve marks an open result as resolved at the given time, for the results no longer evaluated because
+// their resource dropped out of the query output. The SLA due time is kept to tell if the SLA was met.
+func Resolve(result *types.ComplianceResult, at int64) {
+	if !result.IsOpen() {
+		return
+	}
+	if result.FirstSeenAt == 0 {
+		result.FirstSeenAt = result.EvaluatedAt
+	}
+	result.ResolvedAt = &at
+}
+
+// Entry is an open result with the owner of its resource
+type Entry struct {
+	Result types.ComplianceResult
+	Owner  string
+}
+
+type Group struct {
+	Key        string
+	Open       int
+	Within     int
+	AtRisk     int
+	Breached   int
+	NoSLA      int
+	AverageAge time.Duration
+	OldestAge  time.Duration
+
+	totalAge time.Duration
+}
+
+func (g *Group) add(status types.ComplianceResultSLAStatus, age time.Duration) {
+	g.Open++
+	switch status {
+	case types.ComplianceResultSLAStatusWithin:
+		g.Within++
+	case types.ComplianceResultSLAStatusAtRisk:
+		g.AtRisk++
+	case types.ComplianceResultSLAStatusBreached:
+		g.Breached++
+	default:
+		g.NoSLA++
+	}
+	g.totalAge += age
+	g.AverageAge = g.totalAge / time.Duration(g.Open)
+	if age > g.OldestAge {
+		g.OldestAge = age
+	}
+}
+
+// Bucket counts the open results aged between MinDays included and MaxDays excluded, MaxDays is zero for the
+// last bucket
+type Bucket struct {
+	MinDays int
+	MaxDays int
+	Count   int
+}
+
+// BucketBounds are the lower bounds in days of the age buckets
+var BucketBounds = []int{0, 7, 30, 90, 180}
+
+type Report struct {
+	Total         Group
+	BySeverity    []Group
+	ByIntegration []Group
+	ByOwner       []Group
+	ByBenchmark   []Group
+	Buckets       []Bucket
+}
+
+// Build ages the open results at the given time and counts them by SLA status in total and per severity,
+// integration, owner and benchmark. Groups are sorted by breached, then at risk, then open results.
+func Build(entries []Entry, now time.Time) Report {
+	bySeverity := make(map[string]*Group)
+	byIntegration := make(map[string]*Group)
+	byOwner := make(map[string]*Group)
+	byBenchmark := make(map[string]*Group)
+	add := func(groups map[string]*Group, key string, status types.ComplianceResultSLAStatus, age time.Duration) {
+		g, ok := groups[key]
+		if !ok {
+			g = &Group{Key: key}
+			groups[key] = g
+		}
+		g.add(status, age)
+	}
+
+	report := Report{Total: Group{Key: "all"}}
+	for i, lower := range BucketBounds {
+		b := Bucket{MinDays: lower}
+		if i+1 < len(BucketBounds) {
+			b.MaxDays = BucketBounds[i+1]
+		}
+		report.Buckets = append(report.Buckets, b)
+	}
+
+	for _, e := range entries {
+		if !e.Result.IsOpen() {
+			continue
+		}
+		status := e.Result.SLAStatus(now)
+		age := e.Result.Age(now)
+		owner := e.Owner
+		if owner == "" {
+			owner = ownership.Unassigned
+		}
+
+		report.Total.add(status, age)
+		add(bySeverity, string(e.Result.Severity), status, age)
+		add(byIntegration, e.Result.IntegrationID, status, age)
+		add(byOwner, owner, status, age)
+		add(byBenchmark, e.Result.BenchmarkID, status, age)
+
+		days := int(age / day)
+		for i := len(report.Buckets) - 1; i >= 0; i-- {
+			if days >= report.Buckets[i].MinDays {
+				report.Buckets[i].Count++
+				break
+			}
+		}
+	}
+
+	report.BySeverity = sortedGroups(bySeverity)
+	report.ByIntegration = sortedGroups(byIntegration)
+	report.ByOwner = sortedGroups(byOwner)
+	report.ByBenchmark = sortedGroups(byBenchmark)
+	return report
+}
+
+func sortedGroups(groups map[string]*Group) []Group {
+	result := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Breached != result[j].Breached {
+			return result[i].Breached > result[j].Breached
+		}
+		if result[i].AtRisk != result[j].AtRisk {
+			return result[i].AtRisk > result[j].AtRisk
+		}
+		if result[i].Open != result[j].Open {
+			return result[i].Open > result[j].Open
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
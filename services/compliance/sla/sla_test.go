@@ -0,0 +1,231 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+// nov is the epoch milliseconds of noon of the given day of November 2023, the tests count in days of the
+// month and days past the end of the month run on into December
+func nov(dayOfMonth int) int64 {
+	return time.Date(2023, time.November, dayOfMonth, 12, 0, 0, 0, time.UTC).UnixMilli()
+}
+
+// tracking is the SLA state of a result in days of November, zero days are unset
+type tracking struct {
+	firstSeen int
+	resolved  int
+	due       int
+}
+
+func (s tracking) on(r types.ComplianceResult) *types.ComplianceResult {
+	dayPointer := func(d int) *int64 {
+		if d == 0 {
+			return nil
+		}
+		v := nov(d)
+		return &v
+	}
+	r.FirstSeenAt = 0
+	if s.firstSeen != 0 {
+		r.FirstSeenAt = nov(s.firstSeen)
+	}
+	r.ResolvedAt = dayPointer(s.resolved)
+	r.SLADueAt = dayPointer(s.due)
+	return &r
+}
+
+func trackingOf(r types.ComplianceResult) tracking {
+	dayOf := func(ms int64) int {
+		if ms == 0 {
+			return 0
+		}
+		return int(time.UnixMilli(ms).Sub(time.UnixMilli(nov(0))) / day)
+	}
+	s := tracking{firstSeen: dayOf(r.FirstSeenAt)}
+	if r.ResolvedAt != nil {
+		s.resolved = dayOf(*r.ResolvedAt)
+	}
+	if r.SLADueAt != nil {
+		s.due = dayOf(*r.SLADueAt)
+	}
+	return s
+}
+
+func evaluated(status types.ComplianceStatus, severity types.ComplianceResultSeverity, dayOfMonth int) types.ComplianceResult {
+	return types.ComplianceResult{
+		ResourceID:       "arn:aws:s3:::audit-logs",
+		ControlID:        "aws_s3_bucket_versioning_enabled",
+		ComplianceStatus: status,
+		Severity:         severity,
+		EvaluatedAt:      nov(dayOfMonth),
+	}
+}
+
+func TestTrack(t *testing.T) {
+	policies := Policies{
+		types.ComplianceResultSeverityCritical: 7,
+		types.ComplianceResultSeverityHigh:     30,
+	}
+	critical, high, low := types.ComplianceResultSeverityCritical, types.ComplianceResultSeverityHigh,
+		types.ComplianceResultSeverityLow
+	ok, alarm := types.ComplianceStatusOK, types.ComplianceStatusALARM
+
+	tests := []struct {
+		name     string
+		result   types.ComplianceResult
+		previous *types.ComplianceResult
+		want     tracking
+	}{
+		{
+			name:   "failing for the first time",
+			result: evaluated(alarm, critical, 1),
+			want:   tracking{firstSeen: 1, due: 8},
+		},
+		{
+			name:   "failing with an error",
+			result: evaluated(types.ComplianceStatusERROR, high, 1),
+			want:   tracking{firstSeen: 1, due: 31},
+		},
+		{
+			name:   "failing without a policy for the severity",
+			result: evaluated(alarm, low, 1),
+			want:   tracking{firstSeen: 1},
+		},
+		{
+			name:     "still failing keeps the first seen time and deadline",
+			result:   evaluated(alarm, critical, 4),
+			previous: tracking{firstSeen: 1, due: 8}.on(evaluated(alarm, critical, 2)),
+			want:     tracking{firstSeen: 1, due: 8},
+		},
+		{
+			name:     "still failing from before the tracking",
+			result:   evaluated(alarm, critical, 4),
+			previous: tracking{}.on(evaluated(alarm, critical, 2)),
+			want:     tracking{firstSeen: 2, due: 9},
+		},
+		{
+			name:     "severity raised while failing moves the deadline",
+			result:   evaluated(alarm, critical, 6),
+			previous: tracking{firstSeen: 1, due: 31}.on(evaluated(alarm, high, 5)),
+			want:     tracking{firstSeen: 1, due: 8},
+		},
+		{
+			name:     "passing again resolves the result",
+			result:   evaluated(ok, critical, 6),
+			previous: tracking{firstSeen: 1, due: 8}.on(evaluated(alarm, critical, 5)),
+			want:     tracking{firstSeen: 1, resolved: 6, due: 8},
+		},
+		{
+			name:     "passing again from before the tracking",
+			result:   evaluated(ok, critical, 6),
+			previous: tracking{}.on(evaluated(alarm, critical, 5)),
+			want:     tracking{firstSeen: 5, resolved: 6},
+		},
+		{
+			name:     "still passing keeps the resolution",
+			result:   evaluated(ok, critical, 21),
+			previous: tracking{firstSeen: 1, resolved: 10, due: 8}.on(evaluated(ok, critical, 11)),
+			want:     tracking{firstSeen: 1, resolved: 10, due: 8},
+		},
+		{
+			name:   "passing without previous state",
+			result: evaluated(ok, critical, 1),
+		},
+		{
+			name:     "passing and never failed",
+			result:   evaluated(ok, critical, 2),
+			previous: tracking{}.on(evaluated(ok, critical, 1)),
+		},
+		{
+			name:     "failing again after a resolution starts over",
+			result:   evaluated(alarm, critical, 21),
+			previous: tracking{firstSeen: 1, resolved: 10, due: 8}.on(evaluated(ok, critical, 11)),
+			want:     tracking{firstSeen: 21, due: 28},
+		},
+		{
+			name:     "failing again after its resource dropped out starts over",
+			result:   evaluated(alarm, critical, 21),
+			previous: tracking{firstSeen: 1, resolved: 16, due: 8}.on(evaluated(alarm, critical, 11)),
+			want:     tracking{firstSeen: 21, due: 28},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.result
+			Track(&r, tt.previous, policies)
+			if got := trackingOf(r); got != tt.want {
+				t.Errorf("expected the days %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	critical := types.ComplianceResultSeverityCritical
+	ok, alarm := types.ComplianceStatusOK, types.ComplianceStatusALARM
+
+	tests := []struct {
+		name          string
+		result        *types.ComplianceResult
+		resolveOn     int
+		want          tracking
+		wantSLAStatus types.ComplianceResultSLAStatus
+	}{
+		{
+			name:          "resolved within the sla",
+			result:        tracking{firstSeen: 1, due: 8}.on(evaluated(alarm, critical, 4)),
+			resolveOn:     6,
+			want:          tracking{firstSeen: 1, resolved: 6, due: 8},
+			wantSLAStatus: types.ComplianceResultSLAStatusMet,
+		},
+		{
+			name:          "resolved after the sla",
+			result:        tracking{firstSeen: 1, due: 8}.on(evaluated(alarm, critical, 9)),
+			resolveOn:     11,
+			want:          tracking{firstSeen: 1, resolved: 11, due: 8},
+			wantSLAStatus: types.ComplianceResultSLAStatusMissed,
+		},
+		{
+			name:          "resolved from before the tracking",
+			result:        tracking{}.on(evaluated(alarm, critical, 4)),
+			resolveOn:     6,
+			want:          tracking{firstSeen: 4, resolved: 6},
+			wantSLAStatus: types.ComplianceResultSLAStatusNone,
+		},
+		{
+			name:          "passing results are left alone",
+			result:        tracking{firstSeen: 1, resolved: 3, due: 8}.on(evaluated(ok, critical, 4)),
+			resolveOn:     6,
+			want:          tracking{firstSeen: 1, resolved: 3, due: 8},
+			wantSLAStatus: types.ComplianceResultSLAStatusMet,
+		},
+		{
+			name:          "resolved results keep their resolution",
+			result:        tracking{firstSeen: 1, resolved: 5, due: 8}.on(evaluated(alarm, critical, 4)),
+			resolveOn:     11,
+			want:          tracking{firstSeen: 1, resolved: 5, due: 8},
+			wantSLAStatus: types.ComplianceResultSLAStatusMet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := *tt.result
+			Resolve(&r, nov(tt.resolveOn))
+			if r.IsOpen() {
+				t.Errorf("expected the result to be resolved")
+			}
+			if got := trackingOf(r); got != tt.want {
+				t.Errorf("expected the days %+v, got %+v", tt.want, got)
+			}
+			endOfMonth := time.UnixMilli(nov(30))
+			if status := r.SLAStatus(endOfMonth); status != tt.wantSLAStatus {
+				t.Errorf("expected sla status %s, got %s", tt.wantSLAStatus, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opengovern/opencomply/services/compliance/api"
+	"github.com/opengovern/opencomply/services/compliance/es"
+	"github.com/opengovern/opencomply/services/compliance/trend"
+)
+
+const defaultTrendMaxJobs = 30
+
+// loadTrendSamples breaks down the latest compliance jobs of the framework in the time range by control and integration
+func (h *HttpHandler) loadTrendSamples(ctx context.Context, frameworkID string, integrationIDs, controlIDs []string,
+	startTime, endTime time.Time, maxJobs int) ([]trend.Sample, error) {
+	snapshots, err := es.ListFrameworkComplianceSnapshots(ctx, h.logger, h.client, frameworkID, integrationIDs, startTime, endTime, maxJobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compliance snapshots: %w", err)
+	}
+
+	samples := make([]trend.Sample, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		breakdown, err := es.GetComplianceJobTrendBreakdown(ctx, h.logger, h.client, snapshot.ComplianceJobID, integrationIDs, controlIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get results of compliance job %d: %w", snapshot.ComplianceJobID, err)
+		}
+		sample := trend.Sample{
+			JobID:        snapshot.ComplianceJobID,
+			EvaluatedAt:  time.Unix(snapshot.EvaluatedAtEpoch, 0),
+			Controls:     make(map[string]trend.Counts, len(breakdown.Controls)),
+			Integrations: make(map[string]trend.Counts, len(breakdown.Integrations)),
+		}
+		for id, c := range breakdown.Controls {
+			sample.Controls[id] = trend.Counts{Passed: c.Passed, Failed: c.Failed}
+		}
+		for id, c := range breakdown.Integrations {
+			sample.Integrations[id] = trend.Counts{Passed: c.Passed, Failed: c.Failed}
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+func toApiTrendCounts(c trend.Counts) api.ComplianceTrendCounts {
+	return api.ComplianceTrendCounts{Passed: c.Passed, Failed: c.Failed}
+}
+
+func toApiTrendContributors(contributors []trend.Contributor) []api.ComplianceTrendContributor {
+	result := make([]api.ComplianceTrendContributor, 0, len(contributors))
+	for _, c := range contributors {
+		result = append(result, api.ComplianceTrendContributor{
+			ID:           c.ID,
+			Contribution: c.Contribution,
+			Before:       toApiTrendCounts(c.Before),
+			After:        toApiTrendCounts(c.After),
+		})
+	}
+	return result
+}
+
+func toApiTrendChanges(changes []trend.Change) []api.ComplianceTrendChange {
+	result := make([]api.ComplianceTrendChange, 0, len(changes))
+	for _, c := range changes {
+		result = append(result, api.ComplianceTrendChange{
+			FromComplianceJobID: c.FromJobID,
+			ToComplianceJobID:   c.ToJobID,
+			From:                c.From,
+			To:                  c.To,
+			ScoreDelta:          c.Delta,
+			ZScore:              c.ZScore,
+			PValue:              c.PValue,
+			Regression:          c.Regression,
+			Controls:            toApiTrendContributors(c.Controls),
+			Integrations:        toApiTrendContributors(c.Integrations),
+		})
+	}
+	return result
+}
+
+func toApiTrendAnalytics(benchmarkID string, integrationIDs, controlIDs []string, analysis trend.Analysis) api.GetBenchmarkTrendAnalyticsResponse {
+	response := api.GetBenchmarkTrendAnalyticsResponse{
+		BenchmarkID:    benchmarkID,
+		IntegrationIDs: integrationIDs,
+		ControlIDs:     controlIDs,
+		Datapoints:     make([]api.ComplianceTrendPoint, 0, len(analysis.Points)),
+		Changes:        toApiTrendChanges(analysis.Changes),
+		Regressions:    toApiTrendChanges(analysis.Regressions),
+	}
+	for _, p := range analysis.Points {
+		response.Datapoints = append(response.Datapoints, api.ComplianceTrendPoint{
+			ComplianceJobID: p.JobID,
+			EvaluatedAt:     p.EvaluatedAt,
+			Results:         toApiTrendCounts(p.Counts),
+			ComplianceScore: p.Score,
+			MovingAverage:   p.MovingAverage,
+		})
+	}
+	if p := analysis.Projection; p != nil {
+		response.Projection = &api.ComplianceTrendProjection{
+			TargetScore:  p.TargetScore,
+			RatePerDay:   p.RatePerDay,
+			Reached:      p.Reached,
+			Reachable:    p.Reachable,
+			DaysToTarget: p.DaysToTarget,
+			ETA:          p.ETA,
+		}
+	}
+	return response
+}
@@ -0,0 +1,292 @@
+package trend
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultWindow            = 3
+	DefaultSignificanceLevel = 0.05
+	DefaultTopContributors   = 5
+
+	maxProjectionDays = 100 * 365
+)
+
+// Counts is the number of passed and failed results, the score is the share of passed results
+type Counts struct {
+	Passed int64
+	Failed int64
+}
+
+func (c Counts) Total() int64 {
+	return c.Passed + c.Failed
+}
+
+func (c Counts) Score() float64 {
+	if c.Total() == 0 {
+		return 0
+	}
+	return float64(c.Passed) / float64(c.Total())
+}
+
+// Sample is the result of a compliance job broken down by control and by integration
+type Sample struct {
+	JobID        uint
+	EvaluatedAt  time.Time
+	Controls     map[string]Counts
+	Integrations map[string]Counts
+}
+
+// Total sums the results of the controls of the sample
+func (s Sample) Total() Counts {
+	var total Counts
+	for _, c := range s.Controls {
+		total.Passed += c.Passed
+		total.Failed += c.Failed
+	}
+	return total
+}
+
+type Options struct {
+	// Window is the number of compliance jobs averaged by the moving average and used to measure the remediation rate
+	Window int
+	// SignificanceLevel is the p-value under which a drop between two consecutive jobs is flagged as a regression
+	SignificanceLevel float64
+	// TopContributors is the number of controls and integrations reported per change
+	TopContributors int
+	// TargetScore is the score to project the time to, between 0 and 1, nil skips the projection
+	TargetScore *float64
+}
+
+func (o Options) withDefaults() Options {
+	if o.Window <= 0 {
+		o.Window = DefaultWindow
+	}
+	if o.SignificanceLevel <= 0 || o.SignificanceLevel >= 1 {
+		o.SignificanceLevel = DefaultSignificanceLevel
+	}
+	if o.TopContributors <= 0 {
+		o.TopContributors = DefaultTopContributors
+	}
+	return o
+}
+
+type Point struct {
+	JobID         uint
+	EvaluatedAt   time.Time
+	Counts        Counts
+	Score         float64
+	MovingAverage float64
+}
+
+// Contributor is a control or an integration and its share of the score change between two jobs,
+// the contributions of all controls (or all integrations) sum up to the score change
+type Contributor struct {
+	ID           string
+	Contribution float64
+	Before       Counts
+	After        Counts
+}
+
+type Change struct {
+	FromJobID uint
+	ToJobID   uint
+	From      time.Time
+	To        time.Time
+	Delta     float64
+	ZScore    float64
+	// PValue is the one-sided p-value of the score having dropped
+	PValue       float64
+	Regression   bool
+	Controls     []Contributor
+	Integrations []Contributor
+}
+
+type Projection struct {
+	TargetScore float64
+	// RatePerDay is the score change per day over the window, from a least squares fit
+	RatePerDay float64
+	Reached    bool
+	// Reachable is false when the score is not improving, ETA and DaysToTarget are then empty
+	Reachable    bool
+	DaysToTarget *float64
+	ETA          *time.Time
+}
+
+type Analysis struct {
+	Points      []Point
+	Changes     []Change
+	Regressions []Change
+	Projection  *Projection
+}
+
+// Analyze computes the trend analytics of the samples, they are sorted by evaluation time first
+func Analyze(samples []Sample, opts Options) Analysis {
+	opts = opts.withDefaults()
+	samples = append([]Sample(nil), samples...)
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].EvaluatedAt.Before(samples[j].EvaluatedAt)
+	})
+
+	analysis := Analysis{
+		Points:      make([]Point, 0, len(samples)),
+		Changes:     make([]Change, 0),
+		Regressions: make([]Change, 0),
+	}
+	var windowSum float64
+	for i, sample := range samples {
+		total := sample.Total()
+		score := total.Score()
+		windowSum += score
+		if i >= opts.Window {
+			windowSum -= analysis.Points[i-opts.Window].Score
+		}
+		analysis.Points = append(analysis.Points, Point{
+			JobID:         sample.JobID,
+			EvaluatedAt:   sample.EvaluatedAt,
+			Counts:        total,
+			Score:         score,
+			MovingAverage: windowSum / float64(min(i+1, opts.Window)),
+		})
+	}
+
+	for i := 1; i < len(samples); i++ {
+		change := compare(samples[i-1], samples[i], opts)
+		analysis.Changes = append(analysis.Changes, change)
+		if change.Regression {
+			analysis.Regressions = append(analysis.Regressions, change)
+		}
+	}
+
+	if opts.TargetScore != nil {
+		analysis.Projection = project(analysis.Points, *opts.TargetScore, opts.Window)
+	}
+	return analysis
+}
+
+func compare(before, after Sample, opts Options) Change {
+	totalBefore, totalAfter := before.Total(), after.Total()
+	change := Change{
+		FromJobID: before.JobID,
+		ToJobID:   after.JobID,
+		From:      before.EvaluatedAt,
+		To:        after.EvaluatedAt,
+		Delta:     totalAfter.Score() - totalBefore.Score(),
+		PValue:    1,
+	}
+
+	// two-proportion z-test of the share of passed results
+	if totalBefore.Total() > 0 && totalAfter.Total() > 0 {
+		n1, n2 := float64(totalBefore.Total()), float64(totalAfter.Total())
+		pooled := float64(totalBefore.Passed+totalAfter.Passed) / (n1 + n2)
+		se := math.Sqrt(pooled * (1 - pooled) * (1/n1 + 1/n2))
+		if se > 0 {
+			change.ZScore = change.Delta / se
+			change.PValue = normalCDF(change.ZScore)
+		}
+	}
+	change.Regression = change.Delta < 0 && change.PValue < opts.SignificanceLevel
+
+	change.Controls = contributors(before.Controls, after.Controls, totalBefore.Score(), totalAfter.Total(), opts.TopContributors)
+	change.Integrations = contributors(before.Integrations, after.Integrations, totalBefore.Score(), totalAfter.Total(), opts.TopContributors)
+	return change
+}
+
+// contributors decomposes the score change into the items, the contribution of an item is
+// ((passedAfter - passedBefore) - scoreBefore * (totalAfter - totalBefore)) / total after.
+// The largest contributions in absolute value are returned.
+func contributors(before, after map[string]Counts, scoreBefore float64, totalAfter int64, top int) []Contributor {
+	if totalAfter == 0 {
+		return []Contributor{}
+	}
+	ids := make(map[string]bool)
+	for id := range before {
+		ids[id] = true
+	}
+	for id := range after {
+		ids[id] = true
+	}
+
+	result := make([]Contributor, 0, len(ids))
+	for id := range ids {
+		b, a := before[id], after[id]
+		contribution := (float64(a.Passed-b.Passed) - scoreBefore*float64(a.Total()-b.Total())) / float64(totalAfter)
+		if contribution == 0 {
+			continue
+		}
+		result = append(result, Contributor{
+			ID:           id,
+			Contribution: contribution,
+			Before:       b,
+			After:        a,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ci, cj := math.Abs(result[i].Contribution), math.Abs(result[j].Contribution)
+		if ci != cj {
+			return ci > cj
+		}
+		return result[i].ID < result[j].ID
+	})
+	if len(result) > top {
+		result = result[:top]
+	}
+	return result
+}
+
+// project fits the score of the last window points against time and extrapolates when the target score is reached
+func project(points []Point, target float64, window int) *Projection {
+	projection := &Projection{TargetScore: target}
+	if len(points) == 0 {
+		return projection
+	}
+	last := points[len(points)-1]
+	if last.Score >= target {
+		projection.Reached = true
+		projection.Reachable = true
+		return projection
+	}
+
+	window = max(window, 2)
+	if len(points) > window {
+		points = points[len(points)-window:]
+	}
+	if len(points) < 2 {
+		return projection
+	}
+
+	origin := points[0].EvaluatedAt
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.EvaluatedAt.Sub(origin).Hours() / 24
+		sumX += x
+		sumY += p.Score
+		sumXY += x * p.Score
+		sumXX += x * x
+	}
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return projection
+	}
+	projection.RatePerDay = (n*sumXY - sumX*sumY) / denominator
+	if projection.RatePerDay <= 0 {
+		return projection
+	}
+
+	days := (target - last.Score) / projection.RatePerDay
+	projection.Reachable = true
+	projection.DaysToTarget = &days
+	// time.Duration overflows after about 290 years
+	if days < maxProjectionDays {
+		eta := last.EvaluatedAt.Add(time.Duration(days * 24 * float64(time.Hour)))
+		projection.ETA = &eta
+	}
+	return projection
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
@@ -0,0 +1,371 @@
+package trend
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// daily returns a sample of a single control per day, the job ids count from one
+func daily(counts ...Counts) []Sample {
+	first := time.Date(2023, time.October, 2, 9, 0, 0, 0, time.UTC)
+	samples := make([]Sample, 0, len(counts))
+	for i, c := range counts {
+		samples = append(samples, Sample{
+			JobID:        uint(i + 1),
+			EvaluatedAt:  first.AddDate(0, 0, i),
+			Controls:     map[string]Counts{"aws_s3_bucket_versioning_enabled": c},
+			Integrations: map[string]Counts{"123456789012": c},
+		})
+	}
+	return samples
+}
+
+func reversed(samples []Sample) []Sample {
+	out := make([]Sample, 0, len(samples))
+	for i := len(samples) - 1; i >= 0; i-- {
+		out = append(out, samples[i])
+	}
+	return out
+}
+
+func within(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestAnalyzePoints(t *testing.T) {
+	tests := []struct {
+		name         string
+		samples      []Sample
+		window       int
+		wantScores   []float64
+		wantAverages []float64
+	}{
+		{
+			name:         "no samples",
+			wantScores:   []float64{},
+			wantAverages: []float64{},
+		},
+		{
+			name:         "single sample",
+			samples:      daily(Counts{3, 1}),
+			wantScores:   []float64{0.75},
+			wantAverages: []float64{0.75},
+		},
+		{
+			name:         "sample without results",
+			samples:      daily(Counts{}),
+			wantScores:   []float64{0},
+			wantAverages: []float64{0},
+		},
+		{
+			name:         "sorted by evaluation time with a window of two",
+			samples:      reversed(daily(Counts{1, 1}, Counts{2, 0}, Counts{0, 2}, Counts{1, 1})),
+			window:       2,
+			wantScores:   []float64{0.5, 1, 0, 0.5},
+			wantAverages: []float64{0.5, 0.75, 0.5, 0.25},
+		},
+		{
+			name:         "default window of three",
+			samples:      daily(Counts{0, 4}, Counts{1, 3}, Counts{2, 2}, Counts{3, 1}),
+			wantScores:   []float64{0, 0.25, 0.5, 0.75},
+			wantAverages: []float64{0, 0.125, 0.25, 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := Analyze(tt.samples, Options{Window: tt.window})
+			if len(analysis.Points) != len(tt.wantScores) {
+				t.Fatalf("expected %d points, got %d", len(tt.wantScores), len(analysis.Points))
+			}
+			for i, p := range analysis.Points {
+				if p.JobID != uint(i+1) {
+					t.Errorf("point %d: expected job %d, got %d", i, i+1, p.JobID)
+				}
+				if !within(p.Score, tt.wantScores[i], 1e-12) {
+					t.Errorf("point %d: expected score %v, got %v", i, tt.wantScores[i], p.Score)
+				}
+				if !within(p.MovingAverage, tt.wantAverages[i], 1e-12) {
+					t.Errorf("point %d: expected moving average %v, got %v", i, tt.wantAverages[i], p.MovingAverage)
+				}
+			}
+			if len(analysis.Changes) != max(len(tt.samples)-1, 0) {
+				t.Errorf("expected a change between every two jobs, got %d", len(analysis.Changes))
+			}
+			if analysis.Projection != nil {
+				t.Errorf("expected no projection without a target score")
+			}
+		})
+	}
+}
+
+func TestAnalyzeRegressions(t *testing.T) {
+	tests := []struct {
+		name              string
+		before, after     Counts
+		significanceLevel float64
+		wantDelta         float64
+		wantRegression    bool
+		wantPValueOne     bool
+	}{
+		{
+			name:           "significant drop",
+			before:         Counts{900, 100},
+			after:          Counts{800, 200},
+			wantDelta:      -0.1,
+			wantRegression: true,
+		},
+		{
+			name:      "drop on too few results",
+			before:    Counts{9, 1},
+			after:     Counts{8, 2},
+			wantDelta: -0.1,
+		},
+		{
+			name:              "drop on few results with a loose significance level",
+			before:            Counts{9, 1},
+			after:             Counts{8, 2},
+			significanceLevel: 0.5,
+			wantDelta:         -0.1,
+			wantRegression:    true,
+		},
+		{
+			name:      "improvement",
+			before:    Counts{800, 200},
+			after:     Counts{900, 100},
+			wantDelta: 0.1,
+		},
+		{
+			name:   "no change",
+			before: Counts{50, 50},
+			after:  Counts{50, 50},
+		},
+		{
+			name:          "job without results",
+			before:        Counts{900, 100},
+			after:         Counts{},
+			wantDelta:     -0.9,
+			wantPValueOne: true,
+		},
+		{
+			name:          "all results passing in both jobs",
+			before:        Counts{10, 0},
+			after:         Counts{20, 0},
+			wantPValueOne: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := daily(tt.before, tt.after)
+			analysis := Analyze(reversed(samples), Options{SignificanceLevel: tt.significanceLevel})
+			if len(analysis.Changes) != 1 {
+				t.Fatalf("expected 1 change, got %d", len(analysis.Changes))
+			}
+			change := analysis.Changes[0]
+			if change.FromJobID != 1 || change.ToJobID != 2 {
+				t.Errorf("expected change from job 1 to 2, got %d to %d", change.FromJobID, change.ToJobID)
+			}
+			if !change.From.Equal(samples[0].EvaluatedAt) || !change.To.Equal(samples[1].EvaluatedAt) {
+				t.Errorf("expected change from %v to %v, got %v to %v", samples[0].EvaluatedAt, samples[1].EvaluatedAt,
+					change.From, change.To)
+			}
+			if !within(change.Delta, tt.wantDelta, 1e-12) {
+				t.Errorf("expected delta %v, got %v", tt.wantDelta, change.Delta)
+			}
+			if change.Regression != tt.wantRegression {
+				t.Errorf("expected regression %v, got %v (p-value %v)", tt.wantRegression, change.Regression, change.PValue)
+			}
+			if tt.wantPValueOne && change.PValue != 1 {
+				t.Errorf("expected p-value 1, got %v", change.PValue)
+			}
+			if change.PValue < 0 || change.PValue > 1 {
+				t.Errorf("p-value %v out of range", change.PValue)
+			}
+			if tt.wantRegression != (len(analysis.Regressions) == 1) {
+				t.Errorf("expected regression %v, got %d regressions", tt.wantRegression, len(analysis.Regressions))
+			}
+		})
+	}
+}
+
+func TestAnalyzeContributors(t *testing.T) {
+	monday := time.Date(2023, time.October, 2, 9, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{
+			JobID:       7,
+			EvaluatedAt: monday,
+			Controls: map[string]Counts{
+				"a": {Passed: 5, Failed: 5},
+				"b": {Passed: 5, Failed: 5},
+				"d": {Passed: 1, Failed: 1},
+			},
+		},
+		{
+			JobID:       8,
+			EvaluatedAt: monday.AddDate(0, 0, 1),
+			Controls: map[string]Counts{
+				"a": {Passed: 10, Failed: 0},
+				"b": {Passed: 4, Failed: 6},
+				"c": {Passed: 0, Failed: 10},
+				"d": {Passed: 1, Failed: 1},
+			},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		top               int
+		wantIDs           []string
+		wantContributions []float64
+		// unchanged controls contribute nothing, so the contributions of all controls sum up to the delta
+		wantSumIsDelta bool
+	}{
+		{
+			name:              "all contributors",
+			top:               10,
+			wantIDs:           []string{"a", "c", "b"},
+			wantContributions: []float64{5.0 / 32, -5.0 / 32, -1.0 / 32},
+			wantSumIsDelta:    true,
+		},
+		{
+			name:              "top contributors",
+			top:               2,
+			wantIDs:           []string{"a", "c"},
+			wantContributions: []float64{5.0 / 32, -5.0 / 32},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := Analyze(samples, Options{TopContributors: tt.top}).Changes[0]
+			if len(change.Controls) != len(tt.wantIDs) {
+				t.Fatalf("expected %d contributors, got %+v", len(tt.wantIDs), change.Controls)
+			}
+			if len(change.Integrations) != 0 {
+				t.Errorf("expected no integration contributors, got %+v", change.Integrations)
+			}
+			var sum float64
+			for i, c := range change.Controls {
+				if c.ID != tt.wantIDs[i] {
+					t.Errorf("contributor %d: expected %s, got %s", i, tt.wantIDs[i], c.ID)
+				}
+				if !within(c.Contribution, tt.wantContributions[i], 1e-12) {
+					t.Errorf("contributor %s: expected contribution %v, got %v", c.ID, tt.wantContributions[i], c.Contribution)
+				}
+				sum += c.Contribution
+			}
+			if tt.wantSumIsDelta && !within(sum, change.Delta, 1e-12) {
+				t.Errorf("contributions sum to %v, expected the delta %v", sum, change.Delta)
+			}
+		})
+	}
+}
+
+func TestAnalyzeProjection(t *testing.T) {
+	simultaneous := daily(Counts{5, 5}, Counts{6, 4})
+	simultaneous[1].EvaluatedAt = simultaneous[0].EvaluatedAt
+
+	tests := []struct {
+		name          string
+		samples       []Sample
+		window        int
+		wantReached   bool
+		wantReachable bool
+		wantRate      float64
+		// wantDays is zero when no days to the target are expected
+		wantDays float64
+		wantETA  bool
+	}{
+		{
+			name: "no samples",
+		},
+		{
+			name:          "target reached",
+			samples:       daily(Counts{5, 5}, Counts{9, 1}),
+			wantReached:   true,
+			wantReachable: true,
+		},
+		{
+			name:    "single sample below the target",
+			samples: daily(Counts{5, 5}),
+		},
+		{
+			name:          "improving",
+			samples:       daily(Counts{5, 5}, Counts{6, 4}, Counts{7, 3}),
+			wantReachable: true,
+			wantRate:      0.1,
+			wantDays:      2,
+			wantETA:       true,
+		},
+		{
+			name:          "fit over the last window only",
+			samples:       daily(Counts{9, 1}, Counts{2, 8}, Counts{3, 7}),
+			window:        2,
+			wantReachable: true,
+			wantRate:      0.1,
+			wantDays:      6,
+			wantETA:       true,
+		},
+		{
+			name:    "flat",
+			samples: daily(Counts{5, 5}, Counts{5, 5}, Counts{5, 5}),
+		},
+		{
+			name:     "declining",
+			samples:  daily(Counts{7, 3}, Counts{6, 4}, Counts{5, 5}),
+			wantRate: -0.1,
+		},
+		{
+			name:    "samples at the same time",
+			samples: simultaneous,
+		},
+		{
+			name:          "too far away for an eta",
+			samples:       daily(Counts{500000, 500000}, Counts{500001, 499999}),
+			wantReachable: true,
+			wantRate:      1e-6,
+			wantDays:      (0.9 - 0.500001) / 1e-6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := 0.9
+			projection := Analyze(tt.samples, Options{Window: tt.window, TargetScore: &target}).Projection
+			if projection == nil {
+				t.Fatal("expected a projection")
+			}
+			if projection.TargetScore != target {
+				t.Errorf("expected target %v, got %v", target, projection.TargetScore)
+			}
+			if projection.Reached != tt.wantReached {
+				t.Errorf("expected reached %v, got %v", tt.wantReached, projection.Reached)
+			}
+			if projection.Reachable != tt.wantReachable {
+				t.Errorf("expected reachable %v, got %v", tt.wantReachable, projection.Reachable)
+			}
+			if !within(projection.RatePerDay, tt.wantRate, 1e-9) {
+				t.Errorf("expected rate %v, got %v", tt.wantRate, projection.RatePerDay)
+			}
+			switch {
+			case tt.wantDays == 0 && projection.DaysToTarget != nil:
+				t.Errorf("expected no days to target, got %v", *projection.DaysToTarget)
+			case tt.wantDays != 0 && projection.DaysToTarget == nil:
+				t.Errorf("expected %v days to target, got none", tt.wantDays)
+			case tt.wantDays != 0 && !within(*projection.DaysToTarget, tt.wantDays, 1e-3*tt.wantDays):
+				t.Errorf("expected %v days to target, got %v", tt.wantDays, *projection.DaysToTarget)
+			}
+			if (projection.ETA != nil) != tt.wantETA {
+				t.Fatalf("expected eta %v, got %v", tt.wantETA, projection.ETA)
+			}
+			if projection.ETA != nil {
+				last := tt.samples[len(tt.samples)-1].EvaluatedAt
+				if got := projection.ETA.Sub(last).Hours() / 24; !within(got, tt.wantDays, 1e-3) {
+					t.Errorf("expected the eta %v days after the last job, got %v", tt.wantDays, got)
+				}
+			}
+		})
+	}
+}
@@ -323,3 +323,51 @@ func filterTagsByRegex(regexPattern *string, tags map[string][]string) map[strin
 	}
 	return resultsMap
 }
+
+// getBenchmarkFramework returns the root benchmark of a benchmark, the framework its compliance jobs run
+func (h *HttpHandler) getBenchmarkFramework(ctx context.Context, benchmarkId string) (string, error) {
+	for {
+		parent, err := h.db.GetBenchmarkParent(ctx, benchmarkId)
+		if err != nil {
+			return "", err
+		}
+		if parent == "" {
+			return benchmarkId, nil
+		}
+		benchmarkId = parent
+	}
+}
+
+// resolveComplianceSnapshot resolves the point in time asked by a request, nil means the live state.
+// The job ID takes precedence over the timestamp. Only the jobs of the frameworks of the benchmarks are
+// considered, all frameworks if none are given.
+func (h *HttpHandler) resolveComplianceSnapshot(ctx context.Context, benchmarkIDs []string, integrationIDs []string,
+	asOf *int64, jobID *uint, perFramework bool) (*es.ComplianceSnapshotScope, error) {
+	if asOf == nil && jobID == nil {
+		return nil, nil
+	}
+	var asOfTime *time.Time
+	if asOf != nil {
+		t := time.Unix(*asOf, 0)
+		asOfTime = &t
+	}
+	var frameworkIDs []string
+	seen := make(map[string]bool)
+	for _, benchmarkID := range benchmarkIDs {
+		frameworkID, err := h.getBenchmarkFramework(ctx, benchmarkID)
+		if err != nil {
+			h.logger.Error("failed to get benchmark framework", zap.String("benchmarkID", benchmarkID), zap.Error(err))
+			return nil, err
+		}
+		if !seen[frameworkID] {
+			seen[frameworkID] = true
+			frameworkIDs = append(frameworkIDs, frameworkID)
+		}
+	}
+	snapshot, err := es.ResolveComplianceSnapshot(ctx, h.logger, h.client, frameworkIDs, integrationIDs, asOfTime, jobID, perFramework)
+	if err != nil {
+		h.logger.Error("failed to resolve compliance snapshot", zap.Error(err))
+		return nil, err
+	}
+	return snapshot, nil
+}
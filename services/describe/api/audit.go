@@ -0,0 +1,20 @@
+package api
+
+import "github.com/opengovern/opencomply/pkg/types"
+
+type ListAuditEventsRequest struct {
+	Services    []string `json:"services"`
+	Actions     []string `json:"actions"`
+	ActorIDs    []string `json:"actor_ids"`
+	TargetTypes []string `json:"target_types"`
+	TargetIDs   []string `json:"target_ids"`
+	StartTime   *int64   `json:"start_time"` // Unix timestamp in milliseconds
+	EndTime     *int64   `json:"end_time"`   // Unix timestamp in milliseconds
+	PerPage     *int     `json:"per_page"`
+	Cursor      *int     `json:"cursor"`
+}
+
+type ListAuditEventsResponse struct {
+	Events     []types.AuditEvent `json:"events"`
+	TotalCount int64              `json:"total_count"`
+}
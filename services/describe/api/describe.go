@@ -1,6 +1,10 @@
 package api
 
-import "github.com/opengovern/og-util/pkg/source"
+import (
+	"time"
+
+	"github.com/opengovern/og-util/pkg/source"
+)
 
 type DescribeSingleResourceRequest struct {
 	Provider         source.Type `json:"provider"`
@@ -34,8 +38,11 @@ const (
 )
 
 type ComplianceJob struct {
-	ID             uint
-	BenchmarkID    string
-	Status         ComplianceJobStatus
-	FailureMessage string
+	ID                   uint
+	BenchmarkID          string
+	Status               ComplianceJobStatus
+	FailureMessage       string
+	DataAsOf             *time.Time
+	EvaluatedOnStaleData bool
+	ResourceCollectionID *string
 }
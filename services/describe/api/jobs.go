@@ -183,10 +183,16 @@ type IntegrationInfoFilter struct {
 }
 
 type RunBenchmarkItem struct {
-	JobId           uint              `json:"job_id"`
-	WithIncident    bool              `json:"with_incident"`
-	BenchmarkId     string            `json:"benchmark_id"`
-	IntegrationInfo []IntegrationInfo `json:"integration_info"`
+	JobId                uint              `json:"job_id"`
+	WithIncident         bool              `json:"with_incident"`
+	BenchmarkId          string            `json:"benchmark_id"`
+	IntegrationInfo      []IntegrationInfo `json:"integration_info"`
+	ResourceCollectionID *string           `json:"resource_collection_id,omitempty"`
+}
+
+type RunResourceCollectionComplianceRequest struct {
+	// BenchmarkIDs limits the run to some of the benchmarks assigned to the collection
+	BenchmarkIDs []string `json:"benchmark_ids"`
 }
 
 type RunBenchmarkResponse struct {
@@ -235,6 +241,9 @@ type GetComplianceJobStatusResponse struct {
 	FrameworkId     string            `json:"framework_id"`
 	CreatedAt       time.Time         `json:"created_at"`
 	UpdatedAt       time.Time         `json:"updated_at"`
+
+	DataAsOf             *time.Time `json:"data_as_of,omitempty"`
+	EvaluatedOnStaleData bool       `json:"evaluated_on_stale_data"`
 }
 
 type GetAsyncQueryRunJobStatusResponse struct {
@@ -0,0 +1,79 @@
+package api
+
+import "time"
+
+type PlatformJobType string
+
+const (
+	PlatformJobTypeDiscovery        PlatformJobType = "discovery"
+	PlatformJobTypeCompliance       PlatformJobType = "compliance"
+	PlatformJobTypeComplianceRunner PlatformJobType = "compliance_runner"
+	PlatformJobTypeQuery            PlatformJobType = "query"
+	PlatformJobTypeTask             PlatformJobType = "task"
+)
+
+// ListPlatformJobsRequest filters the platform jobs, the limit and offset apply to the jobs of each type and the
+// limit is capped at 1000
+type ListPlatformJobsRequest struct {
+	JobTypes       []PlatformJobType `json:"job_types"`
+	Statuses       []string          `json:"statuses"`
+	IntegrationIDs []string          `json:"integration_ids"`
+	CreatedAfter   *time.Time        `json:"created_after"`
+	CreatedBefore  *time.Time        `json:"created_before"`
+	Limit          *int64            `json:"limit"`
+	Offset         *int64            `json:"offset"`
+}
+
+// PlatformJob is a job of the platform flattened to be listed alongside jobs of other types
+type PlatformJob struct {
+	JobID           uint            `json:"job_id"`
+	JobType         PlatformJobType `json:"job_type"`
+	Status          string          `json:"status"`
+	IntegrationID   string          `json:"integration_id,omitempty"`
+	IntegrationIDs  []string        `json:"integration_ids,omitempty"`
+	IntegrationType string          `json:"integration_type,omitempty"`
+	ResourceType    string          `json:"resource_type,omitempty"`
+	FrameworkID     string          `json:"framework_id,omitempty"`
+	QueryID         string          `json:"query_id,omitempty"`
+	TaskID          string          `json:"task_id,omitempty"`
+	ParentJobID     *uint           `json:"parent_job_id,omitempty"`
+	TriggerType     string          `json:"trigger_type,omitempty"`
+	CreatedBy       string          `json:"created_by,omitempty"`
+	RetryCount      int             `json:"retry_count"`
+	FailureMessage  string          `json:"failure_message,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// ListPlatformComplianceRunsRequest filters the compliance runs, the limit is capped at 1000
+type ListPlatformComplianceRunsRequest struct {
+	FrameworkIDs   []string   `json:"framework_ids"`
+	Statuses       []string   `json:"statuses"`
+	IntegrationIDs []string   `json:"integration_ids"`
+	CreatedAfter   *time.Time `json:"created_after"`
+	CreatedBefore  *time.Time `json:"created_before"`
+	Limit          *int64     `json:"limit"`
+	Offset         *int64     `json:"offset"`
+}
+
+type PlatformComplianceRun struct {
+	JobID                uint       `json:"job_id"`
+	FrameworkID          string     `json:"framework_id"`
+	Status               string     `json:"status"`
+	IntegrationIDs       []string   `json:"integration_ids"`
+	ResourceCollectionID *string    `json:"resource_collection_id,omitempty"`
+	WithIncidents        bool       `json:"with_incidents"`
+	TriggerType          string     `json:"trigger_type"`
+	CreatedBy            string     `json:"created_by"`
+	ParentJobID          *uint      `json:"parent_job_id,omitempty"`
+	FailureMessage       string     `json:"failure_message,omitempty"`
+	DataAsOf             *time.Time `json:"data_as_of,omitempty"`
+	EvaluatedOnStaleData bool       `json:"evaluated_on_stale_data"`
+
+	// RunnersCount is the number of runners of the job per runner status
+	RunnersCount      map[string]int64 `json:"runners_count"`
+	TotalRunnersCount int64            `json:"total_runners_count"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type QueryScheduleThresholdMetric string
+
+const (
+	// QueryScheduleThresholdMetricRowCount is the number of rows of the run
+	QueryScheduleThresholdMetricRowCount QueryScheduleThresholdMetric = "row_count"
+	// QueryScheduleThresholdMetricAddedCount is the number of rows added since the previous run
+	QueryScheduleThresholdMetricAddedCount QueryScheduleThresholdMetric = "added_count"
+	// QueryScheduleThresholdMetricRemovedCount is the number of rows removed since the previous run
+	QueryScheduleThresholdMetricRemovedCount QueryScheduleThresholdMetric = "removed_count"
+	// QueryScheduleThresholdMetricValue is the sum of a numeric column over all rows of the run
+	QueryScheduleThresholdMetricValue QueryScheduleThresholdMetric = "value"
+)
+
+type QueryScheduleThresholdOperator string
+
+const (
+	QueryScheduleThresholdOperatorGreaterThan        QueryScheduleThresholdOperator = ">"
+	QueryScheduleThresholdOperatorGreaterThanOrEqual QueryScheduleThresholdOperator = ">="
+	QueryScheduleThresholdOperatorLessThan           QueryScheduleThresholdOperator = "<"
+	QueryScheduleThresholdOperatorLessThanOrEqual    QueryScheduleThresholdOperator = "<="
+	QueryScheduleThresholdOperatorEqual              QueryScheduleThresholdOperator = "=="
+	QueryScheduleThresholdOperatorNotEqual           QueryScheduleThresholdOperator = "!="
+	// QueryScheduleThresholdOperatorIncreased compares the metric with the previous run, the value is ignored
+	QueryScheduleThresholdOperatorIncreased QueryScheduleThresholdOperator = "increased"
+	// QueryScheduleThresholdOperatorDecreased compares the metric with the previous run, the value is ignored
+	QueryScheduleThresholdOperatorDecreased QueryScheduleThresholdOperator = "decreased"
+	// QueryScheduleThresholdOperatorChanged compares the metric with the previous run, the value is ignored
+	QueryScheduleThresholdOperatorChanged QueryScheduleThresholdOperator = "changed"
+)
+
+type QueryScheduleThreshold struct {
+	Name     string                         `json:"name"`
+	Metric   QueryScheduleThresholdMetric   `json:"metric"`
+	Column   string                         `json:"column,omitempty"`
+	Operator QueryScheduleThresholdOperator `json:"operator"`
+	Value    float64                        `json:"value"`
+}
+
+func (t QueryScheduleThreshold) Validate() error {
+	switch t.Metric {
+	case QueryScheduleThresholdMetricRowCount, QueryScheduleThresholdMetricAddedCount, QueryScheduleThresholdMetricRemovedCount:
+	case QueryScheduleThresholdMetricValue:
+		if t.Column == "" {
+			return errors.New("column is required for the value metric")
+		}
+	default:
+		return fmt.Errorf("invalid metric %q", t.Metric)
+	}
+	switch t.Operator {
+	case QueryScheduleThresholdOperatorGreaterThan, QueryScheduleThresholdOperatorGreaterThanOrEqual,
+		QueryScheduleThresholdOperatorLessThan, QueryScheduleThresholdOperatorLessThanOrEqual,
+		QueryScheduleThresholdOperatorEqual, QueryScheduleThresholdOperatorNotEqual,
+		QueryScheduleThresholdOperatorIncreased, QueryScheduleThresholdOperatorDecreased, QueryScheduleThresholdOperatorChanged:
+	default:
+		return fmt.Errorf("invalid operator %q", t.Operator)
+	}
+	return nil
+}
+
+type QueryScheduleThresholdResult struct {
+	QueryScheduleThreshold
+	Current   *float64 `json:"current,omitempty"`
+	Previous  *float64 `json:"previous,omitempty"`
+	Triggered bool     `json:"triggered"`
+	Error     string   `json:"error,omitempty"`
+}
+
+type QueryScheduleRequest struct {
+	Name           string                   `json:"name"`
+	QueryID        string                   `json:"query_id"`
+	CronExpression string                   `json:"cron_expression"`
+	Parameters     map[string]string        `json:"parameters"`
+	KeyColumns     []string                 `json:"key_columns"`
+	Thresholds     []QueryScheduleThreshold `json:"thresholds"`
+	Enabled        *bool                    `json:"enabled"`
+}
+
+type QuerySchedule struct {
+	ID             uint                     `json:"id"`
+	Name           string                   `json:"name"`
+	QueryID        string                   `json:"query_id"`
+	CronExpression string                   `json:"cron_expression"`
+	Parameters     map[string]string        `json:"parameters"`
+	KeyColumns     []string                 `json:"key_columns"`
+	Thresholds     []QueryScheduleThreshold `json:"thresholds"`
+	Enabled        bool                     `json:"enabled"`
+	CreatedBy      string                   `json:"created_by"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+	NextRunAt      time.Time                `json:"next_run_at"`
+	LastRunAt      *time.Time               `json:"last_run_at"`
+}
+
+type ListQuerySchedulesResponse struct {
+	Items      []QuerySchedule `json:"items"`
+	TotalCount int             `json:"total_count"`
+}
+
+type QueryScheduleRunStatus string
+
+const (
+	QueryScheduleRunRunning   QueryScheduleRunStatus = "RUNNING"
+	QueryScheduleRunSucceeded QueryScheduleRunStatus = "SUCCEEDED"
+	QueryScheduleRunFailed    QueryScheduleRunStatus = "FAILED"
+)
+
+type QueryScheduleRun struct {
+	ID             uint                           `json:"id"`
+	ScheduleID     uint                           `json:"schedule_id"`
+	JobID          uint                           `json:"job_id"`
+	PreviousRunID  *uint                          `json:"previous_run_id"`
+	Status         QueryScheduleRunStatus         `json:"status"`
+	FailureMessage string                         `json:"failure_message"`
+	CreatedAt      time.Time                      `json:"created_at"`
+	UpdatedAt      time.Time                      `json:"updated_at"`
+	ColumnNames    []string                       `json:"column_names"`
+	RowCount       int                            `json:"row_count"`
+	AddedCount     int                            `json:"added_count"`
+	RemovedCount   int                            `json:"removed_count"`
+	Thresholds     []QueryScheduleThresholdResult `json:"thresholds"`
+	Triggered      bool                           `json:"triggered"`
+
+	// the row snapshots are kept for the latest runs of the schedule only
+	Rows        [][]string `json:"rows,omitempty"`
+	AddedRows   [][]string `json:"added_rows,omitempty"`
+	RemovedRows [][]string `json:"removed_rows,omitempty"`
+}
+
+type ListQueryScheduleRunsResponse struct {
+	Items      []QueryScheduleRun `json:"items"`
+	TotalCount int64              `json:"total_count"`
+}
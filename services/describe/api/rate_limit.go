@@ -0,0 +1,34 @@
+package api
+
+import "time"
+
+type DescribeRateLimitScope string
+
+const (
+	DescribeRateLimitScopeResourceType    DescribeRateLimitScope = "resource_type"
+	DescribeRateLimitScopeIntegration     DescribeRateLimitScope = "integration"
+	DescribeRateLimitScopeIntegrationType DescribeRateLimitScope = "integration_type"
+)
+
+type DescribeRateLimit struct {
+	ID             uint                   `json:"id"`
+	Scope          DescribeRateLimitScope `json:"scope"`
+	Key            string                 `json:"key"`
+	MaxConcurrency int                    `json:"max_concurrency"`
+	MaxPerMinute   int                    `json:"max_per_minute"`
+	Weight         int                    `json:"weight"`
+	UpdatedBy      string                 `json:"updated_by"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+type SetDescribeRateLimitRequest struct {
+	Scope          DescribeRateLimitScope `json:"scope"`
+	Key            string                 `json:"key"`
+	MaxConcurrency int                    `json:"max_concurrency"`
+	MaxPerMinute   int                    `json:"max_per_minute"`
+	Weight         int                    `json:"weight"`
+}
+
+type ListDescribeRateLimitsResponse struct {
+	RateLimits []DescribeRateLimit `json:"rate_limits"`
+}
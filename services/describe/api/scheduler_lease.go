@@ -0,0 +1,17 @@
+package api
+
+import "time"
+
+type SchedulerLease struct {
+	Loop       string    `json:"loop"`
+	HolderID   string    `json:"holder_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Active     bool      `json:"active"`
+}
+
+type GetSchedulerLeadersResponse struct {
+	ReplicaID string           `json:"replica_id"`
+	Leases    []SchedulerLease `json:"leases"`
+}
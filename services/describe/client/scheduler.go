@@ -35,6 +35,8 @@ type SchedulerServiceClient interface {
 	GetIntegrationLastDiscoveryJob(ctx *httpclient.Context, request api.GetIntegrationLastDiscoveryJobRequest) (*model.DescribeIntegrationJob, error)
 	GetComplianceQuickSequence(ctx *httpclient.Context, jobID string) (*api.QuickScanSequence, error)
 	GetComplianceJobStatus(ctx *httpclient.Context, jobId string) (*api.GetComplianceJobStatusResponse, error)
+	ListPlatformJobs(ctx *httpclient.Context, request api.ListPlatformJobsRequest) ([]api.PlatformJob, error)
+	ListPlatformComplianceRuns(ctx *httpclient.Context, request api.ListPlatformComplianceRunsRequest) ([]api.PlatformComplianceRun, error)
 }
 
 type schedulerClient struct {
@@ -291,3 +293,39 @@ func (s *schedulerClient) PurgeSampleData(ctx *httpclient.Context, integrations
 	}
 	return nil
 }
+
+func (s *schedulerClient) ListPlatformJobs(ctx *httpclient.Context, request api.ListPlatformJobsRequest) ([]api.PlatformJob, error) {
+	url := fmt.Sprintf("%s/api/v3/platform/jobs", s.baseURL)
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []api.PlatformJob
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodPost, url, ctx.ToHeaders(), payload, &jobs); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (s *schedulerClient) ListPlatformComplianceRuns(ctx *httpclient.Context, request api.ListPlatformComplianceRunsRequest) ([]api.PlatformComplianceRun, error) {
+	url := fmt.Sprintf("%s/api/v3/platform/compliance-runs", s.baseURL)
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []api.PlatformComplianceRun
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodPost, url, ctx.ToHeaders(), payload, &runs); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return runs, nil
+}
@@ -23,4 +23,6 @@ type SchedulerConfig struct {
 	NATS                    config.NATS
 	Vault                   vault.Config `yaml:"vault" koanf:"vault"`
 	QueryValidatorEnabled   string       `yaml:"query_validator_enabled" koanf:"query_validator_enabled"`
+	// ComplianceSnapshotRetentionDays is how long the compliance snapshots of point-in-time queries are kept
+	ComplianceSnapshotRetentionDays int `yaml:"compliance_snapshot_retention_days" koanf:"compliance_snapshot_retention_days"`
 }
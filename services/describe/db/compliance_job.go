@@ -69,6 +69,33 @@ func (db Database) UpdateComplianceJobAreAllRunnersQueued(id uint, areAllRunners
 	return nil
 }
 
+func (db Database) UpdateComplianceJobDiscoveryRequestedAt(id uint, requestedAt time.Time) error {
+	tx := db.ORM.
+		Model(&model.ComplianceJob{}).
+		Where("id = ?", id).
+		Update("discovery_requested_at", requestedAt)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	return nil
+}
+
+func (db Database) UpdateComplianceJobDataFreshness(id uint, dataAsOf *time.Time, evaluatedOnStaleData bool) error {
+	tx := db.ORM.
+		Model(&model.ComplianceJob{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"data_as_of":              dataAsOf,
+			"evaluated_on_stale_data": evaluatedOnStaleData,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	return nil
+}
+
 func (db Database) UpdateComplianceJobsTimedOut(withIncidents bool, complianceIntervalHours int64) error {
 	tx := db.ORM.
 		Model(&model.ComplianceJob{}).
@@ -119,7 +146,23 @@ func (db Database) GetLastComplianceJob(withIncidents bool, frameworkID string)
 	var job model.ComplianceJob
 	tx := db.ORM.Model(&model.ComplianceJob{}).
 		Where("with_incidents = ?", withIncidents).
-		Where("framework_id = ?", frameworkID).Order("created_at DESC").First(&job)
+		Where("framework_id = ?", frameworkID).
+		Where("resource_collection_id IS NULL").Order("created_at DESC").First(&job)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &job, nil
+}
+
+func (db Database) GetLastResourceCollectionComplianceJob(withIncidents bool, frameworkID, resourceCollectionID string) (*model.ComplianceJob, error) {
+	var job model.ComplianceJob
+	tx := db.ORM.Model(&model.ComplianceJob{}).
+		Where("with_incidents = ?", withIncidents).
+		Where("framework_id = ?", frameworkID).
+		Where("resource_collection_id = ?", resourceCollectionID).Order("created_at DESC").First(&job)
 	if tx.Error != nil {
 		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -14,6 +14,7 @@ func (db Database) Initialize() error {
 		&model.ComplianceJob{}, &model.ComplianceSummarizer{}, &model.ComplianceRunner{}, &model.CheckupJob{},
 		&model.DescribeIntegrationJob{}, &model.IntegrationDiscovery{},
 		&model.JobSequencer{}, &model.QueryRunnerJob{}, &model.QueryValidatorJob{},
-		&model.QuickScanSequence{},
+		&model.QuickScanSequence{}, &model.DescribeRateLimit{}, &model.SchedulerLease{},
+		&model.QuerySchedule{}, &model.QueryScheduleRun{}, &model.ResourceRelationshipExtraction{},
 	)
 }
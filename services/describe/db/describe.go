@@ -76,44 +76,38 @@ func (db Database) CountDescribeIntegrationJobsRunOverLast10Minutes(manuals bool
 	return count, nil
 }
 
-type ResourceTypeCount struct {
-	ResourceType string
-	Count        int
-}
-
-func (db Database) CountRunningDescribeJobsPerResourceType(manuals bool) ([]ResourceTypeCount, error) {
-	var count []ResourceTypeCount
-	runningJobs := []api.DescribeResourceJobStatus{api.DescribeResourceJobQueued, api.DescribeResourceJobInProgress, api.DescribeResourceJobOldResourceDeletion}
-	query := `select resource_type, count(*) as count from describe_integration_jobs where status in ?`
-	if manuals {
-		query = query + ` AND trigger_type = ?`
-	} else {
-		query = query + ` AND trigger_type <> ?`
-	}
-	query = query + ` group by 1`
-	tx := db.ORM.Raw(query, runningJobs, enums.DescribeTriggerTypeManual)
-
-	tx = tx.Find(&count)
+func (db Database) GetLastDescribeIntegrationJob(integrationId, resourceType string) (*model.DescribeIntegrationJob, error) {
+	var job model.DescribeIntegrationJob
+	tx := db.ORM.Preload(clause.Associations).Where("integration_id = ? AND resource_type = ?", integrationId, resourceType).Order("updated_at DESC").First(&job)
 	if tx.Error != nil {
 		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
 		return nil, tx.Error
 	}
-	return count, nil
+
+	return &job, nil
 }
 
-func (db Database) GetLastDescribeIntegrationJob(integrationId, resourceType string) (*model.DescribeIntegrationJob, error) {
-	var job model.DescribeIntegrationJob
-	tx := db.ORM.Preload(clause.Associations).Where("integration_id = ? AND resource_type = ?", integrationId, resourceType).Order("updated_at DESC").First(&job)
+type LastSuccessfulDescribe struct {
+	IntegrationID string
+	ResourceType  string
+	SucceededAt   time.Time
+}
+
+// ListLastSuccessfulDescribes returns the last successful describe of each resource type of the given integrations
+func (db Database) ListLastSuccessfulDescribes(integrationIDs []string, resourceTypes []string) ([]LastSuccessfulDescribe, error) {
+	var res []LastSuccessfulDescribe
+	tx := db.ORM.Raw(`select integration_id, resource_type, max(updated_at) as succeeded_at from describe_integration_jobs
+where status = ? AND integration_id IN ? AND resource_type IN ? AND deleted_at IS NULL group by 1, 2`,
+		api.DescribeResourceJobSucceeded, integrationIDs, resourceTypes).Find(&res)
 	if tx.Error != nil {
 		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
 		return nil, tx.Error
 	}
-
-	return &job, nil
+	return res, nil
 }
 
 func (db Database) GetDescribeIntegrationJobByIntegrationID(integrationId string) ([]model.DescribeIntegrationJob, error) {
@@ -168,39 +162,6 @@ func (db Database) UpdateDescribeIntegrationJobNatsSeqNum(id uint, seqNum uint64
 	return nil
 }
 
-func (db Database) ListRandomCreatedDescribeIntegrationJobs(ctx context.Context, limit int, manuals bool) ([]model.DescribeIntegrationJob, error) {
-	ctx, span := otel.Tracer(opengovernanceTrace.JaegerTracerName).Start(ctx, opengovernanceTrace.GetCurrentFuncName())
-	defer span.End()
-
-	var job []model.DescribeIntegrationJob
-
-	query := `
-SELECT
-	*, random() as r
-FROM
-	describe_integration_jobs dr
-WHERE
-	status = ?`
-
-	if manuals {
-		query = query + ` AND trigger_type = ?`
-	} else {
-		query = query + ` AND trigger_type <> ?`
-	}
-
-	query = query + ` ORDER BY r DESC
-LIMIT ?
-`
-	tx := db.ORM.Raw(query, api.DescribeResourceJobCreated, enums.DescribeTriggerTypeManual, limit).Find(&job)
-	if tx.Error != nil {
-		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, tx.Error
-	}
-	return job, nil
-}
-
 func (db Database) ListAllJobs(pageStart, pageEnd int, interval *string, from *time.Time, to *time.Time, typeFilter []string,
 	statusFilter []string, sortBy, sortOrder string) ([]model.Job, error) {
 	var job []model.Job
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/describe/enums"
+	opengovernanceTrace "github.com/opengovern/og-util/pkg/trace"
+	"github.com/opengovern/opencomply/services/describe/api"
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (db Database) ListDescribeRateLimits() ([]model.DescribeRateLimit, error) {
+	var limits []model.DescribeRateLimit
+	tx := db.ORM.Model(&model.DescribeRateLimit{}).Order("scope, key").Find(&limits)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return limits, nil
+}
+
+func (db Database) GetDescribeRateLimit(id uint) (*model.DescribeRateLimit, error) {
+	var limit model.DescribeRateLimit
+	tx := db.ORM.Model(&model.DescribeRateLimit{}).Where("id = ?", id).First(&limit)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &limit, nil
+}
+
+func (db Database) UpsertDescribeRateLimit(limit *model.DescribeRateLimit) error {
+	tx := db.ORM.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "scope"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_concurrency", "max_per_minute", "weight", "updated_by", "updated_at"}),
+	}).Create(limit)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+func (db Database) DeleteDescribeRateLimit(id uint) error {
+	tx := db.ORM.Where("id = ?", id).Delete(&model.DescribeRateLimit{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// ListCreatedDescribeIntegrationJobsForScheduling returns up to perIntegration of the oldest created jobs
+// of every integration, so that a single large integration can not fill the whole scheduling window.
+func (db Database) ListCreatedDescribeIntegrationJobsForScheduling(ctx context.Context, perIntegration, limit int, manuals bool) ([]model.DescribeIntegrationJob, error) {
+	ctx, span := otel.Tracer(opengovernanceTrace.JaegerTracerName).Start(ctx, opengovernanceTrace.GetCurrentFuncName())
+	defer span.End()
+
+	var jobs []model.DescribeIntegrationJob
+
+	query := `
+SELECT * FROM (
+	SELECT
+		*, row_number() OVER (PARTITION BY integration_id ORDER BY created_at ASC, id ASC) as rn
+	FROM
+		describe_integration_jobs
+	WHERE
+		status = ? AND deleted_at IS NULL`
+
+	if manuals {
+		query = query + ` AND trigger_type = ?`
+	} else {
+		query = query + ` AND trigger_type <> ?`
+	}
+
+	query = query + `
+) dr
+WHERE rn <= ?
+ORDER BY rn ASC, created_at ASC
+LIMIT ?
+`
+	tx := db.ORM.Raw(query, api.DescribeResourceJobCreated, enums.DescribeTriggerTypeManual, perIntegration, limit).Find(&jobs)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return jobs, nil
+}
+
+type DescribeJobLoad struct {
+	IntegrationID   string
+	IntegrationType string
+	ResourceType    string
+	Count           int
+}
+
+// CountRunningDescribeJobsLoad returns the running jobs of both trigger types grouped by integration
+// and resource type, since rate limits are shared between manual and scheduled jobs.
+func (db Database) CountRunningDescribeJobsLoad() ([]DescribeJobLoad, error) {
+	var load []DescribeJobLoad
+	runningJobs := []api.DescribeResourceJobStatus{api.DescribeResourceJobQueued, api.DescribeResourceJobInProgress, api.DescribeResourceJobOldResourceDeletion}
+	tx := db.ORM.Raw(`select integration_id, integration_type, resource_type, count(*) as count from describe_integration_jobs
+where status in ? AND deleted_at IS NULL group by 1, 2, 3`, runningJobs).Find(&load)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return load, nil
+}
+
+// CountQueuedDescribeJobsLoadSince returns the jobs queued after the given time grouped by integration
+// and resource type, used to enforce the per minute limits.
+func (db Database) CountQueuedDescribeJobsLoadSince(since time.Time) ([]DescribeJobLoad, error) {
+	var load []DescribeJobLoad
+	tx := db.ORM.Raw(`select integration_id, integration_type, resource_type, count(*) as count from describe_integration_jobs
+where queued_at > ? AND deleted_at IS NULL group by 1, 2, 3`, since).Find(&load)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return load, nil
+}
+
+// CountCreatedManualDescribeJobsLoad returns the manual jobs waiting to be queued. Scheduled cycles
+// keep this capacity free so manual triggers preempt scheduled ones.
+func (db Database) CountCreatedManualDescribeJobsLoad() ([]DescribeJobLoad, error) {
+	var load []DescribeJobLoad
+	tx := db.ORM.Raw(`select integration_id, integration_type, resource_type, count(*) as count from describe_integration_jobs
+where status = ? AND trigger_type = ? AND deleted_at IS NULL group by 1, 2, 3`, api.DescribeResourceJobCreated, enums.DescribeTriggerTypeManual).Find(&load)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return load, nil
+}
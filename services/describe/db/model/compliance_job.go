@@ -46,14 +46,24 @@ type ComplianceJob struct {
 	TriggerType         ComplianceTriggerType
 	ParentID            *uint
 	CreatedBy           string
+	// ResourceCollectionID scopes the job to the resources of the collection
+	ResourceCollectionID *string
+
+	// DataAsOf is the oldest successful discovery of the resources the framework was evaluated on
+	DataAsOf             *time.Time
+	EvaluatedOnStaleData bool
+	DiscoveryRequestedAt *time.Time
 }
 
 func (c ComplianceJob) ToApi() api.ComplianceJob {
 	return api.ComplianceJob{
-		ID:             c.ID,
-		BenchmarkID:    c.FrameworkID,
-		Status:         c.Status.ToApi(),
-		FailureMessage: c.FailureMessage,
+		ID:                   c.ID,
+		BenchmarkID:          c.FrameworkID,
+		Status:               c.Status.ToApi(),
+		FailureMessage:       c.FailureMessage,
+		DataAsOf:             c.DataAsOf,
+		EvaluatedOnStaleData: c.EvaluatedOnStaleData,
+		ResourceCollectionID: c.ResourceCollectionID,
 	}
 }
 
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/opengovern/opencomply/services/describe/api"
+)
+
+type DescribeRateLimitScope string
+
+const (
+	DescribeRateLimitScopeResourceType    DescribeRateLimitScope = "resource_type"
+	DescribeRateLimitScopeIntegration     DescribeRateLimitScope = "integration"
+	DescribeRateLimitScopeIntegrationType DescribeRateLimitScope = "integration_type"
+)
+
+// DescribeRateLimit caps how many describe jobs of a resource type, integration or integration type
+// may run at the same time and how many of them may be queued per minute.
+// Zero values mean no limit. Weight is the fair-share weight used when picking jobs to run.
+type DescribeRateLimit struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Scope DescribeRateLimitScope `gorm:"uniqueIndex:idx_describe_rate_limit_scope_key"`
+	Key   string                 `gorm:"uniqueIndex:idx_describe_rate_limit_scope_key"`
+
+	MaxConcurrency int
+	MaxPerMinute   int
+	Weight         int
+
+	UpdatedBy string
+}
+
+func (r DescribeRateLimit) ToAPI() api.DescribeRateLimit {
+	return api.DescribeRateLimit{
+		ID:             r.ID,
+		Scope:          api.DescribeRateLimitScope(r.Scope),
+		Key:            r.Key,
+		MaxConcurrency: r.MaxConcurrency,
+		MaxPerMinute:   r.MaxPerMinute,
+		Weight:         r.Weight,
+		UpdatedBy:      r.UpdatedBy,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/jackc/pgtype"
 	queryrunner "github.com/opengovern/opencomply/jobs/query-runner-job"
 	"gorm.io/gorm"
 )
@@ -13,4 +14,5 @@ type QueryRunnerJob struct {
 	Status             queryrunner.QueryRunnerStatus
 	FailureMessage     string
 	NatsSequenceNumber uint64
+	Parameters         *pgtype.JSONB // map[string]string, overrides the platform query parameters
 }
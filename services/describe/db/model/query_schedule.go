@@ -0,0 +1,129 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/lib/pq"
+	"github.com/opengovern/opencomply/services/describe/api"
+	"gorm.io/gorm"
+)
+
+// QuerySchedule runs a named query on a cron schedule with fixed parameter values.
+// Every run keeps a snapshot of the result which is diffed against the previous run by the key columns.
+type QuerySchedule struct {
+	gorm.Model
+	Name           string `gorm:"uniqueIndex:idx_query_schedule_name,where:deleted_at IS NULL"`
+	QueryID        string
+	CronExpression string
+	Parameters     pgtype.JSONB   // map[string]string, overrides the platform query parameters
+	KeyColumns     pq.StringArray `gorm:"type:text[]"`
+	Thresholds     pgtype.JSONB   // []api.QueryScheduleThreshold
+	Enabled        bool
+	CreatedBy      string
+
+	NextRunAt time.Time `gorm:"index"`
+	LastRunAt *time.Time
+}
+
+type QueryScheduleRunStatus string
+
+const (
+	QueryScheduleRunRunning   QueryScheduleRunStatus = "RUNNING"
+	QueryScheduleRunSucceeded QueryScheduleRunStatus = "SUCCEEDED"
+	QueryScheduleRunFailed    QueryScheduleRunStatus = "FAILED"
+)
+
+// QueryScheduleRun is a single run of a query schedule, backed by a query runner job.
+// PreviousRunID is nil for the first successful run, which is the baseline and has no added or removed rows.
+// Only the latest succeeded runs of a schedule keep their row snapshots, older runs keep their counts.
+type QueryScheduleRun struct {
+	gorm.Model
+	ScheduleID       uint `gorm:"index"`
+	QueryRunnerJobID uint `gorm:"index"`
+	PreviousRunID    *uint
+	Status           QueryScheduleRunStatus
+	FailureMessage   string
+
+	ColumnNames  pq.StringArray `gorm:"type:text[]"`
+	Rows         pgtype.JSONB   // [][]string
+	RowCount     int
+	AddedRows    pgtype.JSONB // [][]string
+	AddedCount   int
+	RemovedRows  pgtype.JSONB // [][]string
+	RemovedCount int
+
+	Thresholds pgtype.JSONB // []api.QueryScheduleThresholdResult
+	Triggered  bool
+}
+
+func (s QuerySchedule) ToAPI() (api.QuerySchedule, error) {
+	schedule := api.QuerySchedule{
+		ID:             s.ID,
+		Name:           s.Name,
+		QueryID:        s.QueryID,
+		CronExpression: s.CronExpression,
+		KeyColumns:     s.KeyColumns,
+		Enabled:        s.Enabled,
+		CreatedBy:      s.CreatedBy,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+		NextRunAt:      s.NextRunAt,
+		LastRunAt:      s.LastRunAt,
+	}
+	if s.Parameters.Status == pgtype.Present {
+		if err := json.Unmarshal(s.Parameters.Bytes, &schedule.Parameters); err != nil {
+			return api.QuerySchedule{}, err
+		}
+	}
+	if s.Thresholds.Status == pgtype.Present {
+		if err := json.Unmarshal(s.Thresholds.Bytes, &schedule.Thresholds); err != nil {
+			return api.QuerySchedule{}, err
+		}
+	}
+	return schedule, nil
+}
+
+// ToAPI converts the run, the row snapshots are only included if withRows is set
+func (r QueryScheduleRun) ToAPI(withRows bool) (api.QueryScheduleRun, error) {
+	run := api.QueryScheduleRun{
+		ID:             r.ID,
+		ScheduleID:     r.ScheduleID,
+		JobID:          r.QueryRunnerJobID,
+		PreviousRunID:  r.PreviousRunID,
+		Status:         api.QueryScheduleRunStatus(r.Status),
+		FailureMessage: r.FailureMessage,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+		ColumnNames:    r.ColumnNames,
+		RowCount:       r.RowCount,
+		AddedCount:     r.AddedCount,
+		RemovedCount:   r.RemovedCount,
+		Triggered:      r.Triggered,
+	}
+	if r.Thresholds.Status == pgtype.Present {
+		if err := json.Unmarshal(r.Thresholds.Bytes, &run.Thresholds); err != nil {
+			return api.QueryScheduleRun{}, err
+		}
+	}
+	if !withRows {
+		return run, nil
+	}
+	for _, rows := range []struct {
+		src pgtype.JSONB
+		dst *[][]string
+	}{
+		{r.Rows, &run.Rows},
+		{r.AddedRows, &run.AddedRows},
+		{r.RemovedRows, &run.RemovedRows},
+	} {
+		if rows.src.Status != pgtype.Present {
+			continue
+		}
+		if err := json.Unmarshal(rows.src.Bytes, rows.dst); err != nil {
+			return api.QueryScheduleRun{}, err
+		}
+	}
+	return run, nil
+}
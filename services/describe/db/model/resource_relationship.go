@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ResourceRelationshipExtraction tracks the last describe job of an integration and resource type whose
+// resources had their relationships extracted
+type ResourceRelationshipExtraction struct {
+	IntegrationID string `gorm:"primaryKey"`
+	ResourceType  string `gorm:"primaryKey"`
+	DescribeJobID uint
+	EdgeCount     int64
+	ExtractedAt   time.Time
+}
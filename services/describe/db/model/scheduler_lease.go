@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/opengovern/opencomply/services/describe/api"
+)
+
+// SchedulerLease is held by the scheduler replica that runs a scheduler loop. The holder renews it
+// periodically, and any replica may take it over once it has expired.
+type SchedulerLease struct {
+	Name       string `gorm:"primarykey"`
+	HolderID   string
+	AcquiredAt time.Time
+	RenewedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+func (l SchedulerLease) ToAPI() api.SchedulerLease {
+	return api.SchedulerLease{
+		Loop:       l.Name,
+		HolderID:   l.HolderID,
+		AcquiredAt: l.AcquiredAt,
+		RenewedAt:  l.RenewedAt,
+		ExpiresAt:  l.ExpiresAt,
+		Active:     l.ExpiresAt.After(time.Now()),
+	}
+}
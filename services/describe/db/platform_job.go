@@ -0,0 +1,127 @@
+package db
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	"gorm.io/gorm"
+)
+
+// maxPlatformJobsLimit caps the number of jobs of each type returned by the platform jobs listings, the callers
+// page through the rest with the offset
+const maxPlatformJobsLimit = 1000
+
+// PlatformJobsFilter holds the filters pushed down by the platform jobs listings, empty fields are ignored
+type PlatformJobsFilter struct {
+	Statuses       []string
+	IntegrationIDs []string
+	FrameworkIDs   []string
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	Limit          *int64
+	Offset         *int64
+}
+
+func (f PlatformJobsFilter) apply(tx *gorm.DB) *gorm.DB {
+	if len(f.Statuses) > 0 {
+		tx = tx.Where("status IN ?", f.Statuses)
+	}
+	if f.CreatedAfter != nil {
+		tx = tx.Where("created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		tx = tx.Where("created_at <= ?", *f.CreatedBefore)
+	}
+	// the id breaks the ties of the creation time so the pages do not overlap
+	tx = tx.Order("created_at DESC, id DESC")
+	limit := int64(maxPlatformJobsLimit)
+	if f.Limit != nil && *f.Limit > 0 {
+		limit = min(limit, *f.Limit)
+	}
+	tx = tx.Limit(int(limit))
+	if f.Offset != nil && *f.Offset > 0 {
+		tx = tx.Offset(int(*f.Offset))
+	}
+	return tx
+}
+
+func (db Database) ListPlatformDescribeJobs(filter PlatformJobsFilter) ([]model.DescribeIntegrationJob, error) {
+	var jobs []model.DescribeIntegrationJob
+	tx := db.ORM.Model(&model.DescribeIntegrationJob{})
+	if len(filter.IntegrationIDs) > 0 {
+		tx = tx.Where("integration_id IN ?", filter.IntegrationIDs)
+	}
+	tx = filter.apply(tx).Find(&jobs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return jobs, nil
+}
+
+func (db Database) ListPlatformComplianceJobs(filter PlatformJobsFilter) ([]model.ComplianceJob, error) {
+	var jobs []model.ComplianceJob
+	tx := db.ORM.Model(&model.ComplianceJob{})
+	if len(filter.IntegrationIDs) > 0 {
+		tx = tx.Where("integration_ids && ?", pq.Array(filter.IntegrationIDs))
+	}
+	if len(filter.FrameworkIDs) > 0 {
+		tx = tx.Where("framework_id IN ?", filter.FrameworkIDs)
+	}
+	tx = filter.apply(tx).Find(&jobs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return jobs, nil
+}
+
+func (db Database) ListPlatformComplianceRunners(filter PlatformJobsFilter) ([]model.ComplianceRunner, error) {
+	var jobs []model.ComplianceRunner
+	tx := db.ORM.Model(&model.ComplianceRunner{})
+	if len(filter.IntegrationIDs) > 0 {
+		tx = tx.Where("integration_id IN ?", filter.IntegrationIDs)
+	}
+	if len(filter.FrameworkIDs) > 0 {
+		tx = tx.Where("framework_id IN ?", filter.FrameworkIDs)
+	}
+	tx = filter.apply(tx).Find(&jobs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return jobs, nil
+}
+
+func (db Database) ListPlatformQueryRunnerJobs(filter PlatformJobsFilter) ([]model.QueryRunnerJob, error) {
+	var jobs []model.QueryRunnerJob
+	tx := filter.apply(db.ORM.Model(&model.QueryRunnerJob{})).Find(&jobs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return jobs, nil
+}
+
+// CountComplianceRunnersByStatus returns the number of runners of each compliance job per runner status
+func (db Database) CountComplianceRunnersByStatus(complianceJobIDs []uint) (map[uint]map[string]int64, error) {
+	var rows []struct {
+		ParentJobID uint
+		Status      string
+		Count       int64
+	}
+	tx := db.ORM.Model(&model.ComplianceRunner{}).
+		Select("parent_job_id, status, count(*) as count").
+		Where("parent_job_id IN ?", complianceJobIDs).
+		Group("parent_job_id, status").
+		Scan(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	res := make(map[uint]map[string]int64)
+	for _, r := range rows {
+		if _, ok := res[r.ParentJobID]; !ok {
+			res[r.ParentJobID] = make(map[string]int64)
+		}
+		res[r.ParentJobID][r.Status] = r.Count
+	}
+	return res, nil
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	"gorm.io/gorm"
+)
+
+func (db Database) CreateQuerySchedule(schedule *model.QuerySchedule) error {
+	tx := db.ORM.Create(schedule)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// UpdateQuerySchedule replaces the definition of the schedule, the run history is kept
+func (db Database) UpdateQuerySchedule(id uint, schedule *model.QuerySchedule) error {
+	tx := db.ORM.
+		Model(&model.QuerySchedule{}).
+		Where("id = ?", id).
+		Select("name", "query_id", "cron_expression", "parameters", "key_columns", "thresholds", "enabled", "next_run_at").
+		Updates(schedule)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// DeleteQuerySchedule removes the schedule and its run history
+func (db Database) DeleteQuerySchedule(id uint) error {
+	return db.ORM.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("schedule_id = ?", id).Delete(&model.QueryScheduleRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.QuerySchedule{}).Error
+	})
+}
+
+// GetQuerySchedule returns nil if the schedule does not exist
+func (db Database) GetQuerySchedule(id uint) (*model.QuerySchedule, error) {
+	var schedule model.QuerySchedule
+	tx := db.ORM.Model(&model.QuerySchedule{}).Where("id = ?", id).First(&schedule)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &schedule, nil
+}
+
+func (db Database) ListQuerySchedules() ([]model.QuerySchedule, error) {
+	var schedules []model.QuerySchedule
+	tx := db.ORM.Model(&model.QuerySchedule{}).Order("name").Find(&schedules)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return schedules, nil
+}
+
+func (db Database) ListDueQuerySchedules(now time.Time) ([]model.QuerySchedule, error) {
+	var schedules []model.QuerySchedule
+	tx := db.ORM.Model(&model.QuerySchedule{}).
+		Where("enabled = ?", true).
+		Where("next_run_at <= ?", now).
+		Order("next_run_at").
+		Find(&schedules)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return schedules, nil
+}
+
+func (db Database) UpdateQueryScheduleNextRun(id uint, nextRunAt time.Time, lastRunAt *time.Time) error {
+	updates := map[string]any{"next_run_at": nextRunAt}
+	if lastRunAt != nil {
+		updates["last_run_at"] = *lastRunAt
+	}
+	tx := db.ORM.Model(&model.QuerySchedule{}).Where("id = ?", id).Updates(updates)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// CreateQueryScheduleRun creates the query runner job of the run along with the run
+func (db Database) CreateQueryScheduleRun(job *model.QueryRunnerJob, run *model.QueryScheduleRun) error {
+	return db.ORM.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		run.QueryRunnerJobID = job.ID
+		return tx.Create(run).Error
+	})
+}
+
+// GetQueryScheduleRun returns nil if the run does not exist
+func (db Database) GetQueryScheduleRun(scheduleID, id uint) (*model.QueryScheduleRun, error) {
+	var run model.QueryScheduleRun
+	tx := db.ORM.Model(&model.QueryScheduleRun{}).
+		Where("schedule_id = ?", scheduleID).
+		Where("id = ?", id).
+		First(&run)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &run, nil
+}
+
+// ListQueryScheduleRuns returns a page of the runs of the schedule newest first, without the row snapshots
+func (db Database) ListQueryScheduleRuns(scheduleID uint, limit, offset int) ([]model.QueryScheduleRun, int64, error) {
+	var runs []model.QueryScheduleRun
+	var total int64
+	tx := db.ORM.Model(&model.QueryScheduleRun{}).Where("schedule_id = ?", scheduleID).Count(&total)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	tx = db.ORM.Model(&model.QueryScheduleRun{}).
+		Where("schedule_id = ?", scheduleID).
+		Omit("rows", "added_rows", "removed_rows").
+		Order("id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&runs)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	return runs, total, nil
+}
+
+func (db Database) ListRunningQueryScheduleRuns() ([]model.QueryScheduleRun, error) {
+	var runs []model.QueryScheduleRun
+	tx := db.ORM.Model(&model.QueryScheduleRun{}).
+		Where("status = ?", model.QueryScheduleRunRunning).
+		Order("id").
+		Find(&runs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return runs, nil
+}
+
+func (db Database) CountRunningQueryScheduleRuns(scheduleID uint) (int64, error) {
+	var count int64
+	tx := db.ORM.Model(&model.QueryScheduleRun{}).
+		Where("schedule_id = ?", scheduleID).
+		Where("status = ?", model.QueryScheduleRunRunning).
+		Count(&count)
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	return count, nil
+}
+
+// GetLastSucceededQueryScheduleRun returns the latest successful run of the schedule before the given run,
+// nil if there is none
+func (db Database) GetLastSucceededQueryScheduleRun(scheduleID, beforeID uint) (*model.QueryScheduleRun, error) {
+	var run model.QueryScheduleRun
+	tx := db.ORM.Model(&model.QueryScheduleRun{}).
+		Where("schedule_id = ?", scheduleID).
+		Where("status = ?", model.QueryScheduleRunSucceeded).
+		Where("id < ?", beforeID).
+		Order("id desc").
+		First(&run)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+	return &run, nil
+}
+
+// UpdateQueryScheduleRun stores the result of the run, zero values included
+func (db Database) UpdateQueryScheduleRun(run *model.QueryScheduleRun) error {
+	tx := db.ORM.
+		Model(&model.QueryScheduleRun{}).
+		Where("id = ?", run.ID).
+		Select("previous_run_id", "status", "failure_message", "column_names", "rows", "row_count",
+			"added_rows", "added_count", "removed_rows", "removed_count", "thresholds", "triggered").
+		Updates(run)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// PruneQueryScheduleRunRows drops the row snapshots of the runs of the schedule except the latest keep succeeded
+// ones, the latest succeeded run is the base of the diff of the next run
+func (db Database) PruneQueryScheduleRunRows(scheduleID uint, keep int) error {
+	latest := db.ORM.Model(&model.QueryScheduleRun{}).
+		Select("id").
+		Where("schedule_id = ?", scheduleID).
+		Where("status = ?", model.QueryScheduleRunSucceeded).
+		Order("id desc").
+		Limit(keep)
+	tx := db.ORM.
+		Model(&model.QueryScheduleRun{}).
+		Where("schedule_id = ?", scheduleID).
+		Where("id NOT IN (?)", latest).
+		Where("(rows IS NOT NULL OR added_rows IS NOT NULL OR removed_rows IS NOT NULL)").
+		Updates(map[string]any{"rows": nil, "added_rows": nil, "removed_rows": nil})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
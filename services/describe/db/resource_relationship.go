@@ -0,0 +1,39 @@
+package db
+
+import (
+	"github.com/opengovern/opencomply/services/describe/api"
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	"gorm.io/gorm/clause"
+)
+
+// ListDescribeJobsPendingRelationshipExtraction returns the latest succeeded describe job of every integration
+// and resource type that has not had its relationships extracted since, resourceTypes are lower case
+func (db Database) ListDescribeJobsPendingRelationshipExtraction(resourceTypes []string, limit int) ([]model.DescribeIntegrationJob, error) {
+	var jobs []model.DescribeIntegrationJob
+	tx := db.ORM.Raw(`
+SELECT latest.* FROM (
+	SELECT DISTINCT ON (j.integration_id, j.resource_type) j.* FROM describe_integration_jobs j
+	WHERE j.deleted_at IS NULL AND j.status = ? AND lower(j.resource_type) IN ?
+	ORDER BY j.integration_id, j.resource_type, j.id DESC
+) latest
+LEFT JOIN resource_relationship_extractions e
+	ON e.integration_id = latest.integration_id AND e.resource_type = latest.resource_type
+WHERE e.describe_job_id IS NULL OR latest.id > e.describe_job_id
+ORDER BY latest.updated_at
+LIMIT ?`, api.DescribeResourceJobSucceeded, resourceTypes, limit).Scan(&jobs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return jobs, nil
+}
+
+func (db Database) UpsertResourceRelationshipExtraction(extraction *model.ResourceRelationshipExtraction) error {
+	tx := db.ORM.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "integration_id"}, {Name: "resource_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"describe_job_id", "edge_count", "extracted_at"}),
+	}).Create(extraction)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"time"
+
+	"github.com/opengovern/opencomply/services/describe/db/model"
+)
+
+// TryAcquireSchedulerLease acquires or renews the lease of the given loop for the holder. It returns false
+// while another holder owns an unexpired lease. Times are taken from the database to avoid clock skew between replicas.
+func (db Database) TryAcquireSchedulerLease(name, holderID string, duration time.Duration) (bool, error) {
+	tx := db.ORM.Exec(`INSERT INTO scheduler_leases (name, holder_id, acquired_at, renewed_at, expires_at)
+VALUES (?, ?, now(), now(), now() + make_interval(secs => ?))
+ON CONFLICT (name) DO UPDATE SET
+	holder_id = EXCLUDED.holder_id,
+	acquired_at = CASE WHEN scheduler_leases.holder_id = EXCLUDED.holder_id THEN scheduler_leases.acquired_at ELSE now() END,
+	renewed_at = now(),
+	expires_at = EXCLUDED.expires_at
+WHERE scheduler_leases.holder_id = EXCLUDED.holder_id OR scheduler_leases.expires_at < now()`,
+		name, holderID, duration.Seconds())
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected > 0, nil
+}
+
+func (db Database) ReleaseSchedulerLease(name, holderID string) error {
+	tx := db.ORM.Where("name = ? AND holder_id = ?", name, holderID).Delete(&model.SchedulerLease{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+func (db Database) ListSchedulerLeases() ([]model.SchedulerLease, error) {
+	var leases []model.SchedulerLease
+	tx := db.ORM.Model(&model.SchedulerLease{}).Order("name").Find(&leases)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return leases, nil
+}
@@ -0,0 +1,101 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"go.uber.org/zap"
+)
+
+type AuditEventsQueryResponse struct {
+	Hits AuditEventsQueryHits `json:"hits"`
+}
+type AuditEventsQueryHits struct {
+	Total opengovernance.SearchTotal `json:"total"`
+	Hits  []AuditEventsQueryHit      `json:"hits"`
+}
+type AuditEventsQueryHit struct {
+	ID      string           `json:"_id"`
+	Score   float64          `json:"_score"`
+	Index   string           `json:"_index"`
+	Type    string           `json:"_type"`
+	Version int64            `json:"_version,omitempty"`
+	Source  types.AuditEvent `json:"_source"`
+	Sort    []any            `json:"sort"`
+}
+
+type AuditEventsFilters struct {
+	Services    []string
+	Actions     []string
+	ActorIDs    []string
+	TargetTypes []string
+	TargetIDs   []string
+	StartTime   *int64
+	EndTime     *int64
+}
+
+// ListAuditEvents returns the audit events matching the filters, newest first
+func ListAuditEvents(ctx context.Context, logger *zap.Logger, client opengovernance.Client, filters AuditEventsFilters, from, size int) ([]types.AuditEvent, int64, error) {
+	var filter []map[string]any
+	for field, values := range map[string][]string{
+		"service":    filters.Services,
+		"action":     filters.Actions,
+		"actorID":    filters.ActorIDs,
+		"targetType": filters.TargetTypes,
+		"targetID":   filters.TargetIDs,
+	} {
+		if len(values) > 0 {
+			filter = append(filter, map[string]any{
+				"terms": map[string][]string{field: values},
+			})
+		}
+	}
+	if filters.StartTime != nil || filters.EndTime != nil {
+		timestampRange := map[string]any{}
+		if filters.StartTime != nil {
+			timestampRange["gte"] = *filters.StartTime
+		}
+		if filters.EndTime != nil {
+			timestampRange["lte"] = *filters.EndTime
+		}
+		filter = append(filter, map[string]any{
+			"range": map[string]any{"timestamp": timestampRange},
+		})
+	}
+
+	root := map[string]any{}
+	if len(filter) > 0 {
+		root["query"] = map[string]any{
+			"bool": map[string]any{
+				"filter": filter,
+			},
+		}
+	}
+	root["from"] = from
+	root["size"] = size
+	root["track_total_hits"] = true
+	root["sort"] = []map[string]any{
+		{"timestamp": "desc"},
+		{"_id": "desc"},
+	}
+
+	queryBytes, err := json.Marshal(root)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var response AuditEventsQueryResponse
+	err = client.Search(ctx, types.AuditLogIndex, string(queryBytes), &response)
+	if err != nil {
+		logger.Error("failed to query audit events", zap.Error(err), zap.String("query", string(queryBytes)))
+		return nil, 0, err
+	}
+
+	events := make([]types.AuditEvent, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		events = append(events, hit.Source)
+	}
+	return events, response.Hits.Total.Value, nil
+}
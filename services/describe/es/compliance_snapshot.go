@@ -0,0 +1,41 @@
+package es
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+// DefaultComplianceSnapshotRetention is how long compliance snapshots are kept when no retention is configured
+const DefaultComplianceSnapshotRetention = 90 * 24 * time.Hour
+
+// DeleteComplianceSnapshotsOlderThan removes the compliance snapshots and the result and finding snapshots of the
+// jobs evaluated before the given time, point-in-time queries before it see no data afterwards
+func DeleteComplianceSnapshotsOlderThan(ctx context.Context, client opengovernance.Client, before time.Time) error {
+	deletes := []struct {
+		index string
+		field string
+		value int64
+	}{
+		{index: types.ComplianceSnapshotsIndex, field: "evaluatedAtEpoch", value: before.Unix()},
+		{index: types.ComplianceResultSnapshotsIndex, field: "evaluatedAt", value: before.UnixMilli()},
+		{index: types.ResourceFindingSnapshotsIndex, field: "evaluatedAt", value: before.UnixMilli()},
+	}
+	for _, d := range deletes {
+		query := map[string]any{
+			"query": map[string]any{
+				"range": map[string]any{
+					d.field: map[string]any{"lt": d.value},
+				},
+			},
+		}
+		_, err := opengovernance.DeleteByQuery(ctx, client.ES(), []string{d.index}, query)
+		if err != nil && !strings.Contains(err.Error(), "index_not_found_exception") {
+			return err
+		}
+	}
+	return nil
+}
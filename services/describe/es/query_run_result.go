@@ -0,0 +1,46 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/opengovern/og-util/pkg/es"
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"go.uber.org/zap"
+)
+
+type QueryRunResultResponse struct {
+	Found  bool                 `json:"found"`
+	Source types.QueryRunResult `json:"_source"`
+}
+
+// GetQueryRunResult returns the result ingested by the query runner job, nil if it is not ingested yet. The result
+// is read by its id, unlike a search the get is realtime.
+func GetQueryRunResult(ctx context.Context, logger *zap.Logger, client opengovernance.Client, runID string) (*types.QueryRunResult, error) {
+	keys, index := types.QueryRunResult{RunId: runID}.KeysAndIndex()
+	res, err := client.ES().Get(index, es.HashOf(keys...), client.ES().Get.WithContext(ctx))
+	if err != nil {
+		logger.Error("failed to fetch query run result", zap.Error(err), zap.String("runID", runID))
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		logger.Error("failed to fetch query run result", zap.String("response", res.String()), zap.String("runID", runID))
+		return nil, fmt.Errorf("failed to fetch query run result: %s", res.String())
+	}
+
+	var resp QueryRunResultResponse
+	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, err
+	}
+	if !resp.Found {
+		return nil, nil
+	}
+	return &resp.Source, nil
+}
@@ -1,5 +1,9 @@
 package es
 
+// DefaultResourceRateLimit is the concurrency limit for resource types that have no limit configured.
+const DefaultResourceRateLimit = 25
+
+// ResourceRateLimit holds the built-in concurrency limits, overridable through the describe rate limits API.
 var ResourceRateLimit = map[string]int{
 	"Microsoft.Management/groups":                 5,
 	"Microsoft.CostManagement/CostByResourceType": 3,
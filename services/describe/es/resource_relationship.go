@@ -0,0 +1,137 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/opengovern/og-util/pkg/es"
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+type ResourceFetchResponse struct {
+	Hits ResourceFetchHits `json:"hits"`
+}
+type ResourceFetchHits struct {
+	Total opengovernance.SearchTotal `json:"total"`
+	Hits  []ResourceFetchHit         `json:"hits"`
+}
+type ResourceFetchHit struct {
+	ID      string      `json:"_id"`
+	Score   float64     `json:"_score"`
+	Index   string      `json:"_index"`
+	Type    string      `json:"_type"`
+	Version int64       `json:"_version,omitempty"`
+	Source  es.Resource `json:"_source"`
+	Sort    []any       `json:"sort"`
+}
+
+// GetResourcesForIntegrationResourceTypeFromES pages over the full resources, including their descriptions,
+// of a resource type in an integration
+func GetResourcesForIntegrationResourceTypeFromES(ctx context.Context, client opengovernance.Client, integrationID, resourceType string, searchAfter []any, size int) (*ResourceFetchResponse, error) {
+	root := map[string]any{}
+	root["query"] = map[string]any{
+		"bool": map[string]any{
+			"filter": []map[string]any{
+				{"term": map[string]string{"integration_id": integrationID}},
+				{"term": map[string]string{"resource_type": strings.ToLower(resourceType)}},
+			},
+		},
+	}
+	if searchAfter != nil {
+		root["search_after"] = searchAfter
+	}
+	root["size"] = size
+	root["sort"] = []map[string]any{
+		{"_id": "desc"},
+	}
+
+	queryBytes, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ResourceFetchResponse
+	err = client.Search(ctx, es.ResourceTypeToESIndex(resourceType), string(queryBytes), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// lookupResourcesPageSize is the page size of the lookup resources matched by a batch of values, a value may match
+// the resources of several integrations
+const lookupResourcesPageSize = 10000
+
+// GetLookupResourcesByField returns the lookup resources whose field holds one of the values, of any integration,
+// optionally narrowed to a resource type
+func GetLookupResourcesByField(ctx context.Context, client opengovernance.Client, field string, values []string, resourceType string) ([]es.LookupResource, error) {
+	filters := []map[string]any{
+		{"terms": map[string][]string{field: values}},
+	}
+	if resourceType != "" {
+		filters = append(filters, map[string]any{
+			"term": map[string]string{"resource_type": strings.ToLower(resourceType)},
+		})
+	}
+
+	var resources []es.LookupResource
+	var searchAfter []any
+	for {
+		root := map[string]any{}
+		root["query"] = map[string]any{
+			"bool": map[string]any{
+				"filter": filters,
+			},
+		}
+		if searchAfter != nil {
+			root["search_after"] = searchAfter
+		}
+		root["size"] = lookupResourcesPageSize
+		root["sort"] = []map[string]any{
+			{"_id": "desc"},
+		}
+
+		queryBytes, err := json.Marshal(root)
+		if err != nil {
+			return nil, err
+		}
+
+		var response ResourceIdentifierFetchResponse
+		err = client.Search(ctx, es.InventorySummaryIndex, string(queryBytes), &response)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hit := range response.Hits.Hits {
+			resources = append(resources, hit.Source)
+			searchAfter = hit.Sort
+		}
+		if len(response.Hits.Hits) < lookupResourcesPageSize {
+			return resources, nil
+		}
+	}
+}
+
+// DeleteStaleResourceRelationships removes the edges extracted from an integration and resource type before
+// the given time, which were not produced again by the latest extraction
+func DeleteStaleResourceRelationships(ctx context.Context, client opengovernance.Client, integrationID, resourceType string, before int64) error {
+	query := map[string]any{
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": []map[string]any{
+					{"term": map[string]string{"extractedFromIntegrationID": integrationID}},
+					{"term": map[string]string{"extractedFromResourceType": resourceType}},
+					{"range": map[string]any{"extractedAt": map[string]any{"lt": before}}},
+				},
+			},
+		},
+	}
+	_, err := opengovernance.DeleteByQuery(ctx, client.ES(), []string{types.ResourceRelationshipsIndex}, query)
+	if err != nil && !strings.Contains(err.Error(), "index_not_found_exception") {
+		return err
+	}
+	return nil
+}
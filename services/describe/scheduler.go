@@ -10,6 +10,7 @@ import (
 	"github.com/opengovern/opencomply/services/describe/schedulers/compliance-quick-run"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -36,14 +37,18 @@ import (
 	checkupAPI "github.com/opengovern/opencomply/jobs/checkup-job/api"
 	runner "github.com/opengovern/opencomply/jobs/compliance-runner-job"
 	summarizer "github.com/opengovern/opencomply/jobs/compliance-summarizer-job"
+	"github.com/opengovern/opencomply/pkg/executor"
+	"github.com/opengovern/opencomply/pkg/leaderelection"
 	"github.com/opengovern/opencomply/pkg/utils"
 	"github.com/opengovern/opencomply/services/compliance/client"
 	"github.com/opengovern/opencomply/services/describe/api"
 	"github.com/opengovern/opencomply/services/describe/config"
 	"github.com/opengovern/opencomply/services/describe/db"
 	"github.com/opengovern/opencomply/services/describe/db/model"
+	"github.com/opengovern/opencomply/services/describe/es"
 	"github.com/opengovern/opencomply/services/describe/schedulers/compliance"
 	"github.com/opengovern/opencomply/services/describe/schedulers/discovery"
+	"github.com/opengovern/opencomply/services/describe/schedulers/relationship"
 	integrationClient "github.com/opengovern/opencomply/services/integration/client"
 	inventoryClient "github.com/opengovern/opencomply/services/inventory/client"
 	metadataClient "github.com/opengovern/opencomply/services/metadata/client"
@@ -127,9 +132,13 @@ type Scheduler struct {
 	auditScheduler          *compliance_quick_run.JobScheduler
 	complianceScheduler     *compliance.JobScheduler
 	discoveryScheduler      *discovery.Scheduler
+	relationshipScheduler   *relationship.Scheduler
 	queryRunnerScheduler    *queryrunnerscheduler.JobScheduler
 	queryValidatorScheduler *queryrvalidatorscheduler.JobScheduler
 	conf                    config.SchedulerConfig
+	executorConfig          executor.Config
+
+	leader *leaderelection.Elector
 }
 
 func InitializeScheduler(
@@ -192,6 +201,16 @@ func InitializeScheduler(
 		return nil, fmt.Errorf("new postgres client: %w", err)
 	}
 
+	s.executorConfig = executor.ConfigFromEnv()
+	if s.executorConfig.IsLocal() && s.executorConfig.Local.EmbeddedNATS {
+		conf.NATS.URL, err = executor.StartLocalNATS(ctx, s.logger, s.executorConfig.Local)
+		if err != nil {
+			s.logger.Error("Failed to start embedded nats server", zap.Error(err))
+			return nil, err
+		}
+		s.conf = conf
+	}
+
 	jq, err := jq.New(conf.NATS.URL, s.logger)
 	if err != nil {
 		s.logger.Error("Failed to create job queue", zap.Error(err))
@@ -208,6 +227,13 @@ func InitializeScheduler(
 	s.logger.Info("Connected to the postgres database: ", zap.String("db", postgresDb))
 	s.db = db.Database{ORM: orm}
 
+	// replicas share the same id, the pod hostname tells them apart
+	holderID := id
+	if hostname, err := os.Hostname(); err == nil {
+		holderID = fmt.Sprintf("%s-%s", id, hostname)
+	}
+	s.leader = leaderelection.New(holderID, s.db, s.logger)
+
 	s.es, err = opengovernance.NewClient(opengovernance.ClientConfig{
 		Addresses:     []string{conf.ElasticSearch.Address},
 		Username:      &conf.ElasticSearch.Username,
@@ -285,12 +311,20 @@ func InitializeScheduler(
 	}
 
 	s.discoveryScheduler = discovery.New(
+		s.leader.IsLeader,
 		conf,
 		s.logger,
 		s.complianceClient,
 		s.db,
 		s.es,
 	)
+	s.relationshipScheduler = relationship.New(
+		s.leader.IsLeader,
+		s.logger,
+		s.db,
+		s.es,
+		s.sinkClient,
+	)
 	return s, nil
 }
 
@@ -417,7 +451,11 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		}
 	}
 
-	s.logger.Info("starting scheduler")
+	s.logger.Info("starting scheduler", zap.String("holderID", s.leader.HolderID()))
+
+	utils.EnsureRunGoroutine(func() {
+		s.leader.Run(ctx)
+	})
 
 	// Describe
 	utils.EnsureRunGoroutine(func() {
@@ -430,6 +468,7 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		s.RunDescribeResourceJobs(ctx, true)
 	})
 	s.discoveryScheduler.Run(ctx)
+	s.relationshipScheduler.Run(ctx)
 
 	// Inventory summarizer
 
@@ -438,6 +477,7 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		func(ctx context.Context) error {
 			return s.SetupNats(ctx)
 		},
+		s.leader.IsLeader,
 		s.conf,
 		s.logger,
 		s.db,
@@ -453,6 +493,7 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		func(ctx context.Context) error {
 			return s.SetupNats(ctx)
 		},
+		s.leader.IsLeader,
 		s.conf,
 		s.logger,
 		s.db,
@@ -470,6 +511,7 @@ func (s *Scheduler) Run(ctx context.Context) error {
 			func(ctx context.Context) error {
 				return s.SetupNats(ctx)
 			},
+			s.leader.IsLeader,
 			s.conf,
 			s.logger,
 			s.db,
@@ -487,6 +529,8 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		func(ctx context.Context) error {
 			return s.SetupNats(ctx)
 		},
+		s.leader.IsLeader,
+		s.describeForStaleData,
 		s.conf,
 		s.logger,
 		s.complianceClient,
@@ -497,6 +541,11 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		s.complianceIntervalHours,
 	)
 	s.complianceScheduler.Run(ctx)
+	if s.executorConfig.IsLocal() {
+		if err := s.runLocalComplianceWorkers(ctx); err != nil {
+			return err
+		}
+	}
 	utils.EnsureRunGoroutine(func() {
 		s.RunJobSequencer(ctx)
 	})
@@ -557,6 +606,9 @@ func (s *Scheduler) RunDeletedIntegrationsResourcesCleanup(ctx context.Context)
 	defer ticker.Stop()
 
 	for range ticker.C {
+		if !s.leader.IsLeader("deleted-integrations-resources-cleanup") {
+			continue
+		}
 		integrations, err := s.integrationClient.ListIntegrations(&httpclient.Context{UserRole: authAPI.AdminRole}, nil)
 		if err != nil {
 			s.logger.Error("Failed to list sources", zap.Error(err))
@@ -575,6 +627,9 @@ func (s *Scheduler) RunRemoveResourcesConnectionJobsCleanup() {
 	defer ticker.Stop()
 
 	for range ticker.C {
+		if !s.leader.IsLeader("remove-resources-jobs-cleanup") {
+			continue
+		}
 		jobs, err := s.db.ListDescribeJobsByStatus(api.DescribeResourceJobRemovingResources)
 		if err != nil {
 			s.logger.Error("Failed to list jobs", zap.Error(err))
@@ -601,6 +656,9 @@ func (s *Scheduler) RunScheduledJobCleanup() {
 	ticker := ticker.NewTicker(time.Hour, time.Second*10)
 	defer ticker.Stop()
 	for range ticker.C {
+		if !s.leader.IsLeader("scheduled-jobs-cleanup") {
+			continue
+		}
 		tOlder := time.Now().AddDate(0, 0, -7)
 		err := s.db.CleanupScheduledDescribeIntegrationJobsOlderThan(tOlder)
 		if err != nil {
@@ -615,10 +673,21 @@ func (s *Scheduler) RunScheduledJobCleanup() {
 		if err != nil {
 			s.logger.Error("Failed to cleanup compliance report jobs", zap.Error(err))
 		}
+		snapshotRetention := es.DefaultComplianceSnapshotRetention
+		if s.conf.ComplianceSnapshotRetentionDays > 0 {
+			snapshotRetention = time.Duration(s.conf.ComplianceSnapshotRetentionDays) * 24 * time.Hour
+		}
+		err = es.DeleteComplianceSnapshotsOlderThan(context.Background(), s.es, time.Now().Add(-snapshotRetention))
+		if err != nil {
+			s.logger.Error("Failed to cleanup compliance snapshots", zap.Error(err))
+		}
 	}
 }
 
 func (s *Scheduler) Stop() {
+	if s.leader != nil {
+		s.leader.Release()
+	}
 }
 
 func (s *Scheduler) RunCheckupJobScheduler(ctx context.Context) {
@@ -628,6 +697,9 @@ func (s *Scheduler) RunCheckupJobScheduler(ctx context.Context) {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.leader.IsLeader("checkup-job-scheduler") {
+			continue
+		}
 		s.scheduleCheckupJob(ctx)
 	}
 }
@@ -738,6 +810,9 @@ func (s *Scheduler) RunCheckupJobResultsConsumer(ctx context.Context) error {
 	for {
 		select {
 		case <-t.C:
+			if !s.leader.IsLeader("checkup-jobs-timeout") {
+				continue
+			}
 			if err := s.db.UpdateCheckupJobsTimedOut(s.checkupIntervalHours); err != nil {
 				s.logger.Error("Failed to update timed out CheckupJob", zap.Error(err))
 			}
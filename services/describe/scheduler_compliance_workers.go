@@ -0,0 +1,48 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+
+	runner "github.com/opengovern/opencomply/jobs/compliance-runner-job"
+	summarizer "github.com/opengovern/opencomply/jobs/compliance-summarizer-job"
+	"github.com/opengovern/opencomply/pkg/executor"
+	"go.uber.org/zap"
+)
+
+const (
+	complianceRunnerWorkerCommand     = "compliance-report-job"
+	complianceSummarizerWorkerCommand = "compliance-summarizer-job"
+)
+
+// runLocalComplianceWorkers runs the compliance runner and summarizer workers in the process of the scheduler with
+// the local executor. On Kubernetes they are deployed with the helm chart.
+func (s *Scheduler) runLocalComplianceWorkers(ctx context.Context) error {
+	localExecutor := executor.NewLocalExecutor(s.logger, s.executorConfig.Local)
+	localExecutor.RegisterInProcess(complianceRunnerWorkerCommand, runner.RunInProcess)
+	localExecutor.RegisterInProcess(complianceSummarizerWorkerCommand, summarizer.RunInProcess)
+
+	commands := []string{complianceRunnerWorkerCommand, complianceSummarizerWorkerCommand}
+	for _, command := range commands {
+		err := localExecutor.EnsureWorker(ctx, executor.WorkerSpec{
+			Name:    command,
+			Command: []string{command, "--id", command},
+			Env: []executor.EnvVar{
+				{Name: "NATS_URL", Value: s.conf.NATS.URL},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start worker %s: %w", command, err)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		for _, command := range commands {
+			if err := localExecutor.DeleteWorker(context.Background(), command); err != nil {
+				s.logger.Error("failed to stop worker", zap.String("worker", command), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
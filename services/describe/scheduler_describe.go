@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"github.com/jackc/pgtype"
 	"github.com/opengovern/opencomply/services/integration/api/models"
-	"math/rand"
 	"time"
 
 	apiAuth "github.com/opengovern/og-util/pkg/api"
@@ -21,7 +20,6 @@ import (
 	"github.com/opengovern/opencomply/services/describe/api"
 	apiDescribe "github.com/opengovern/opencomply/services/describe/api"
 	"github.com/opengovern/opencomply/services/describe/db/model"
-	"github.com/opengovern/opencomply/services/describe/es"
 	integrationapi "github.com/opengovern/opencomply/services/integration/api/models"
 	integration_type "github.com/opengovern/opencomply/services/integration/integration-type"
 	"go.opentelemetry.io/otel"
@@ -31,6 +29,11 @@ import (
 const (
 	MaxQueued      = 5000
 	MaxIn10Minutes = 5000
+
+	// MaxJobsPerIntegrationInCycle caps the candidates a single integration contributes to a scheduling cycle
+	MaxJobsPerIntegrationInCycle = 500
+	// FairQueueCandidatesFactor is how many candidates are fetched for each job slot in a cycle
+	FairQueueCandidatesFactor = 4
 )
 
 var ErrJobInProgress = errors.New("job already in progress")
@@ -48,6 +51,9 @@ func (s *Scheduler) RunDescribeJobScheduler(ctx context.Context) {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.leader.IsLeader("describe-job-scheduler") {
+			continue
+		}
 		s.scheduleDescribeJob(ctx)
 	}
 }
@@ -86,47 +92,47 @@ func (s *Scheduler) RunDescribeResourceJobCycle(ctx context.Context, manuals boo
 		DescribePublishingBlocked.WithLabelValues("hour queued").Set(0)
 	}
 
-	dcs, err := s.db.ListRandomCreatedDescribeIntegrationJobs(ctx, int(s.MaxConcurrentCall), manuals)
+	dcs, err := s.db.ListCreatedDescribeIntegrationJobsForScheduling(ctx, MaxJobsPerIntegrationInCycle, int(s.MaxConcurrentCall)*FairQueueCandidatesFactor, manuals)
 	if err != nil {
-		s.logger.Error("failed to fetch describe resource jobs", zap.String("spot", "ListRandomCreatedDescribeResourceJobs"), zap.Error(err))
+		s.logger.Error("failed to fetch describe resource jobs", zap.String("spot", "ListCreatedDescribeIntegrationJobsForScheduling"), zap.Error(err))
 		DescribeResourceJobsCount.WithLabelValues("failure", "fetch_error").Inc()
 		return err
 	}
 	s.logger.Info("got the jobs", zap.Int("length", len(dcs)), zap.Int("limit", int(s.MaxConcurrentCall)))
 
-	counts, err := s.db.CountRunningDescribeJobsPerResourceType(manuals)
+	rateLimits, err := s.db.ListDescribeRateLimits()
 	if err != nil {
-		s.logger.Error("failed to resource type count", zap.String("spot", "CountRunningDescribeJobsPerResourceType"), zap.Error(err))
-		DescribeResourceJobsCount.WithLabelValues("failure", "resource_type_count").Inc()
+		s.logger.Error("failed to list rate limits", zap.String("spot", "ListDescribeRateLimits"), zap.Error(err))
+		DescribeResourceJobsCount.WithLabelValues("failure", "rate_limits").Inc()
 		return err
 	}
 
-	rand.Shuffle(len(dcs), func(i, j int) {
-		dcs[i], dcs[j] = dcs[j], dcs[i]
-	})
-
-	rtCount := map[string]int{}
-	for i := 0; i < len(dcs); i++ {
-		dc := dcs[i]
-		rtCount[dc.ResourceType]++
-
-		maxCount := 25
-		if m, ok := es.ResourceRateLimit[dc.ResourceType]; ok {
-			maxCount = m
-		}
-
-		currentCount := 0
-		for _, c := range counts {
-			if c.ResourceType == dc.ResourceType {
-				currentCount = c.Count
-			}
-		}
-		if rtCount[dc.ResourceType]+currentCount > maxCount {
-			dcs = append(dcs[:i], dcs[i+1:]...)
-			i--
+	runningLoad, err := s.db.CountRunningDescribeJobsLoad()
+	if err != nil {
+		s.logger.Error("failed to count running jobs", zap.String("spot", "CountRunningDescribeJobsLoad"), zap.Error(err))
+		DescribeResourceJobsCount.WithLabelValues("failure", "resource_type_count").Inc()
+		return err
+	}
+	queuedLoad, err := s.db.CountQueuedDescribeJobsLoadSince(time.Now().Add(-time.Minute))
+	if err != nil {
+		s.logger.Error("failed to count queued jobs", zap.String("spot", "CountQueuedDescribeJobsLoadSince"), zap.Error(err))
+		DescribeResourceJobsCount.WithLabelValues("failure", "queued_count").Inc()
+		return err
+	}
+	running := newDescribeLoad(runningLoad)
+	if !manuals {
+		// manual triggers take precedence, keep room for the ones waiting to be queued
+		pendingManuals, err := s.db.CountCreatedManualDescribeJobsLoad()
+		if err != nil {
+			s.logger.Error("failed to count pending manual jobs", zap.String("spot", "CountCreatedManualDescribeJobsLoad"), zap.Error(err))
+			DescribeResourceJobsCount.WithLabelValues("failure", "manuals_count").Inc()
+			return err
 		}
+		running = newDescribeLoad(runningLoad, pendingManuals)
 	}
 
+	dcs = pickFairShareDescribeJobs(dcs, newDescribeLimits(rateLimits), running, newDescribeLoad(queuedLoad), int(s.MaxConcurrentCall))
+
 	s.logger.Info("preparing resource jobs to run", zap.Int("length", len(dcs)))
 
 	wp := concurrency.NewWorkPool(len(dcs))
@@ -180,13 +186,20 @@ func (s *Scheduler) RunDescribeResourceJobCycle(ctx context.Context, manuals boo
 }
 
 func (s *Scheduler) RunDescribeResourceJobs(ctx context.Context, manuals bool) {
+	loop := "describe-resource-jobs"
+	if manuals {
+		loop = "describe-resource-jobs-manual"
+	}
+
 	t := ticker.NewTicker(time.Second*30, time.Second*10)
 	defer t.Stop()
 	for {
 		select {
 		case <-t.C:
-			if err := s.RunDescribeResourceJobCycle(ctx, manuals); err != nil {
-				s.logger.Error("failure while RunDescribeResourceJobCycle", zap.Error(err))
+			if s.leader.IsLeader(loop) {
+				if err := s.RunDescribeResourceJobCycle(ctx, manuals); err != nil {
+					s.logger.Error("failure while RunDescribeResourceJobCycle", zap.Error(err))
+				}
 			}
 			t.Reset(time.Second*30, time.Second*10)
 		case <-ctx.Done():
@@ -0,0 +1,192 @@
+package describe
+
+import (
+	"sort"
+
+	"github.com/opengovern/opencomply/services/describe/db"
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	"github.com/opengovern/opencomply/services/describe/es"
+)
+
+// describeLimits is the effective set of rate limits for a scheduling cycle.
+// Resource types without a configured limit fall back to es.ResourceRateLimit, everything else is unlimited unless
+// configured. Zero limits mean no limit.
+type describeLimits struct {
+	resourceType    map[string]model.DescribeRateLimit
+	integration     map[string]model.DescribeRateLimit
+	integrationType map[string]model.DescribeRateLimit
+}
+
+func newDescribeLimits(limits []model.DescribeRateLimit) describeLimits {
+	l := describeLimits{
+		resourceType:    map[string]model.DescribeRateLimit{},
+		integration:     map[string]model.DescribeRateLimit{},
+		integrationType: map[string]model.DescribeRateLimit{},
+	}
+	for _, limit := range limits {
+		switch limit.Scope {
+		case model.DescribeRateLimitScopeResourceType:
+			l.resourceType[limit.Key] = limit
+		case model.DescribeRateLimitScopeIntegration:
+			l.integration[limit.Key] = limit
+		case model.DescribeRateLimitScopeIntegrationType:
+			l.integrationType[limit.Key] = limit
+		}
+	}
+	return l
+}
+
+func (l describeLimits) resourceTypeConcurrency(resourceType string) int {
+	if limit, ok := l.resourceType[resourceType]; ok {
+		return limit.MaxConcurrency
+	}
+	if m, ok := es.ResourceRateLimit[resourceType]; ok {
+		return m
+	}
+	return es.DefaultResourceRateLimit
+}
+
+func weightOf(limit model.DescribeRateLimit, ok bool) int {
+	if !ok || limit.Weight <= 0 {
+		return 1
+	}
+	return limit.Weight
+}
+
+// describeLoad counts jobs per resource type, integration and integration type.
+type describeLoad struct {
+	resourceType    map[string]int
+	integration     map[string]int
+	integrationType map[string]int
+}
+
+func newDescribeLoad(loads ...[]db.DescribeJobLoad) describeLoad {
+	l := describeLoad{
+		resourceType:    map[string]int{},
+		integration:     map[string]int{},
+		integrationType: map[string]int{},
+	}
+	for _, load := range loads {
+		for _, c := range load {
+			l.resourceType[c.ResourceType] += c.Count
+			l.integration[c.IntegrationID] += c.Count
+			l.integrationType[c.IntegrationType] += c.Count
+		}
+	}
+	return l
+}
+
+func (l describeLoad) add(dc model.DescribeIntegrationJob) {
+	l.resourceType[dc.ResourceType]++
+	l.integration[dc.IntegrationID]++
+	l.integrationType[string(dc.IntegrationType)]++
+}
+
+func exceeds(current, limit int) bool {
+	return limit > 0 && current >= limit
+}
+
+type fairQueueIntegration struct {
+	id      string
+	weight  int
+	served  int
+	pending []model.DescribeIntegrationJob
+}
+
+type fairQueueIntegrationType struct {
+	name         string
+	weight       int
+	served       int
+	integrations []*fairQueueIntegration
+}
+
+// pickFairShareDescribeJobs selects up to maxJobs jobs using weighted fair queueing: integration types are
+// served in proportion to their weight, and inside each type every integration gets its weighted share,
+// so a single large integration can not starve the others. Jobs are taken in the given order per
+// integration and skipped when a concurrency or per minute limit would be exceeded.
+func pickFairShareDescribeJobs(jobs []model.DescribeIntegrationJob, limits describeLimits,
+	running describeLoad, queuedLastMinute describeLoad, maxJobs int) []model.DescribeIntegrationJob {
+	typesMap := map[string]*fairQueueIntegrationType{}
+	integrationsMap := map[string]*fairQueueIntegration{}
+	for _, dc := range jobs {
+		itName := string(dc.IntegrationType)
+		it, ok := typesMap[itName]
+		if !ok {
+			limit, hasLimit := limits.integrationType[itName]
+			it = &fairQueueIntegrationType{name: itName, weight: weightOf(limit, hasLimit)}
+			typesMap[itName] = it
+		}
+		i, ok := integrationsMap[dc.IntegrationID]
+		if !ok {
+			limit, hasLimit := limits.integration[dc.IntegrationID]
+			i = &fairQueueIntegration{id: dc.IntegrationID, weight: weightOf(limit, hasLimit)}
+			integrationsMap[dc.IntegrationID] = i
+			it.integrations = append(it.integrations, i)
+		}
+		i.pending = append(i.pending, dc)
+	}
+
+	var queue []*fairQueueIntegrationType
+	for _, it := range typesMap {
+		queue = append(queue, it)
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		return queue[i].name < queue[j].name
+	})
+
+	var picked []model.DescribeIntegrationJob
+	for len(picked) < maxJobs && len(queue) > 0 {
+		itIdx := nextFairQueueIndex(len(queue), func(i int) (int, int) { return queue[i].served, queue[i].weight })
+		it := queue[itIdx]
+
+		iIdx := nextFairQueueIndex(len(it.integrations), func(i int) (int, int) {
+			return it.integrations[i].served, it.integrations[i].weight
+		})
+		integration := it.integrations[iIdx]
+		dc := integration.pending[0]
+		integration.pending = integration.pending[1:]
+
+		itLimit := limits.integrationType[it.name]
+		iLimit := limits.integration[integration.id]
+		rtLimit := limits.resourceType[dc.ResourceType]
+		switch {
+		case exceeds(running.integrationType[it.name], itLimit.MaxConcurrency),
+			exceeds(queuedLastMinute.integrationType[it.name], itLimit.MaxPerMinute):
+			queue = append(queue[:itIdx], queue[itIdx+1:]...)
+			continue
+		case exceeds(running.integration[integration.id], iLimit.MaxConcurrency),
+			exceeds(queuedLastMinute.integration[integration.id], iLimit.MaxPerMinute):
+			integration.pending = nil
+		case exceeds(running.resourceType[dc.ResourceType], limits.resourceTypeConcurrency(dc.ResourceType)),
+			exceeds(queuedLastMinute.resourceType[dc.ResourceType], rtLimit.MaxPerMinute):
+		default:
+			picked = append(picked, dc)
+			running.add(dc)
+			queuedLastMinute.add(dc)
+			integration.served++
+			it.served++
+		}
+
+		if len(integration.pending) == 0 {
+			it.integrations = append(it.integrations[:iIdx], it.integrations[iIdx+1:]...)
+		}
+		if len(it.integrations) == 0 {
+			queue = append(queue[:itIdx], queue[itIdx+1:]...)
+		}
+	}
+
+	return picked
+}
+
+// nextFairQueueIndex returns the index with the lowest served/weight ratio, the first one on ties.
+func nextFairQueueIndex(n int, share func(i int) (served int, weight int)) int {
+	best := 0
+	bestServed, bestWeight := share(0)
+	for i := 1; i < n; i++ {
+		served, weight := share(i)
+		if served*bestWeight < bestServed*weight {
+			best, bestServed, bestWeight = i, served, weight
+		}
+	}
+	return best
+}
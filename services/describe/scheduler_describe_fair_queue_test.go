@@ -0,0 +1,185 @@
+package describe
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/integration"
+	"github.com/opengovern/opencomply/services/describe/db"
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	"github.com/opengovern/opencomply/services/describe/es"
+)
+
+const (
+	testAWS   integration.Type = "aws_cloud_account"
+	testAzure integration.Type = "azure_subscription"
+)
+
+// describeJobs returns count jobs of the integration with ids starting at firstID
+func describeJobs(firstID uint, count int, integrationType integration.Type, integrationID, resourceType string) []model.DescribeIntegrationJob {
+	jobs := make([]model.DescribeIntegrationJob, 0, count)
+	for i := 0; i < count; i++ {
+		jobs = append(jobs, model.DescribeIntegrationJob{
+			ID:              firstID + uint(i),
+			IntegrationID:   integrationID,
+			IntegrationType: integrationType,
+			ResourceType:    resourceType,
+		})
+	}
+	return jobs
+}
+
+func concat(groups ...[]model.DescribeIntegrationJob) []model.DescribeIntegrationJob {
+	var jobs []model.DescribeIntegrationJob
+	for _, g := range groups {
+		jobs = append(jobs, g...)
+	}
+	return jobs
+}
+
+func TestPickFairShareDescribeJobs(t *testing.T) {
+	tests := []struct {
+		name             string
+		jobs             []model.DescribeIntegrationJob
+		limits           []model.DescribeRateLimit
+		running          []db.DescribeJobLoad
+		queuedLastMinute []db.DescribeJobLoad
+		maxJobs          int
+		wantIDs          []uint
+	}{
+		{
+			name:    "no jobs",
+			maxJobs: 10,
+		},
+		{
+			name:    "no capacity",
+			jobs:    describeJobs(1, 3, testAWS, "a", "AWS::EC2::Instance"),
+			maxJobs: 0,
+		},
+		{
+			name: "large backlog does not starve other integrations",
+			jobs: concat(
+				describeJobs(1, 5, testAWS, "a", "AWS::EC2::Instance"),
+				describeJobs(6, 1, testAWS, "b", "AWS::EC2::Instance"),
+			),
+			maxJobs: 3,
+			wantIDs: []uint{1, 6, 2},
+		},
+		{
+			name: "integrations are served by weight",
+			jobs: concat(
+				describeJobs(1, 4, testAWS, "a", "AWS::EC2::Instance"),
+				describeJobs(11, 4, testAWS, "b", "AWS::EC2::Instance"),
+			),
+			limits: []model.DescribeRateLimit{
+				{Scope: model.DescribeRateLimitScopeIntegration, Key: "a", Weight: 2},
+			},
+			maxJobs: 6,
+			wantIDs: []uint{1, 11, 2, 3, 12, 4},
+		},
+		{
+			name: "integration types are served by weight",
+			jobs: concat(
+				describeJobs(1, 4, testAWS, "a", "AWS::EC2::Instance"),
+				describeJobs(11, 4, testAzure, "b", "Microsoft.Compute/virtualMachines"),
+			),
+			limits: []model.DescribeRateLimit{
+				{Scope: model.DescribeRateLimitScopeIntegrationType, Key: string(testAWS), Weight: 3},
+			},
+			maxJobs: 4,
+			wantIDs: []uint{1, 11, 2, 3},
+		},
+		{
+			name: "integration concurrency counts the running jobs",
+			jobs: concat(
+				describeJobs(1, 3, testAWS, "a", "AWS::EC2::Instance"),
+				describeJobs(11, 2, testAWS, "b", "AWS::EC2::Instance"),
+			),
+			limits: []model.DescribeRateLimit{
+				{Scope: model.DescribeRateLimitScopeIntegration, Key: "a", MaxConcurrency: 2},
+			},
+			running: []db.DescribeJobLoad{
+				{IntegrationID: "a", IntegrationType: string(testAWS), ResourceType: "AWS::EC2::Instance", Count: 1},
+			},
+			maxJobs: 10,
+			wantIDs: []uint{1, 11, 12},
+		},
+		{
+			name: "integration type per minute limit skips the type",
+			jobs: concat(
+				describeJobs(1, 2, testAWS, "a", "AWS::EC2::Instance"),
+				describeJobs(11, 2, testAzure, "b", "Microsoft.Compute/virtualMachines"),
+			),
+			limits: []model.DescribeRateLimit{
+				{Scope: model.DescribeRateLimitScopeIntegrationType, Key: string(testAWS), MaxPerMinute: 5},
+			},
+			queuedLastMinute: []db.DescribeJobLoad{
+				{IntegrationID: "a", IntegrationType: string(testAWS), ResourceType: "AWS::EC2::Instance", Count: 5},
+			},
+			maxJobs: 10,
+			wantIDs: []uint{11, 12},
+		},
+		{
+			name: "configured resource type concurrency",
+			jobs: concat(
+				describeJobs(1, 3, testAWS, "a", "AWS::S3::Bucket"),
+				describeJobs(11, 1, testAWS, "a", "AWS::EC2::Instance"),
+			),
+			limits: []model.DescribeRateLimit{
+				{Scope: model.DescribeRateLimitScopeResourceType, Key: "AWS::S3::Bucket", MaxConcurrency: 1},
+			},
+			maxJobs: 10,
+			wantIDs: []uint{1, 11},
+		},
+		{
+			name: "resource types without a limit use the default",
+			jobs: concat(
+				describeJobs(1, 1, testAWS, "a", "AWS::Custom::Resource"),
+				describeJobs(11, 1, testAWS, "b", "AWS::EC2::Instance"),
+			),
+			running: []db.DescribeJobLoad{
+				{IntegrationID: "c", IntegrationType: string(testAWS), ResourceType: "AWS::Custom::Resource", Count: es.DefaultResourceRateLimit},
+			},
+			maxJobs: 10,
+			wantIDs: []uint{11},
+		},
+		{
+			name: "configured resource types without a concurrency limit are unlimited",
+			jobs: describeJobs(1, 2, testAWS, "a", "AWS::Custom::Resource"),
+			limits: []model.DescribeRateLimit{
+				{Scope: model.DescribeRateLimitScopeResourceType, Key: "AWS::Custom::Resource", Weight: 2},
+			},
+			running: []db.DescribeJobLoad{
+				{IntegrationID: "c", IntegrationType: string(testAWS), ResourceType: "AWS::Custom::Resource", Count: es.DefaultResourceRateLimit},
+			},
+			maxJobs: 10,
+			wantIDs: []uint{1, 2},
+		},
+		{
+			name: "resource type per minute limit",
+			jobs: describeJobs(1, 3, testAWS, "a", "AWS::EC2::Instance"),
+			limits: []model.DescribeRateLimit{
+				{Scope: model.DescribeRateLimitScopeResourceType, Key: "AWS::EC2::Instance", MaxPerMinute: 2},
+			},
+			queuedLastMinute: []db.DescribeJobLoad{
+				{IntegrationID: "b", IntegrationType: string(testAWS), ResourceType: "AWS::EC2::Instance", Count: 1},
+			},
+			maxJobs: 10,
+			wantIDs: []uint{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picked := pickFairShareDescribeJobs(tt.jobs, newDescribeLimits(tt.limits), newDescribeLoad(tt.running),
+				newDescribeLoad(tt.queuedLastMinute), tt.maxJobs)
+			var ids []uint
+			for _, dc := range picked {
+				ids = append(ids, dc.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("picked %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
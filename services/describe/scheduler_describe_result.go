@@ -111,7 +111,9 @@ func (s *Scheduler) RunDescribeJobResultsConsumer(ctx context.Context) error {
 	for {
 		select {
 		case <-t.C:
-			s.handleTimeoutForDiscoveryJobs()
+			if s.leader.IsLeader("describe-jobs-timeout") {
+				s.handleTimeoutForDiscoveryJobs()
+			}
 		case <-ctx.Done():
 			consumeCtx.Drain()
 			consumeCtx.Stop()
@@ -24,6 +24,9 @@ func (s *Scheduler) RunJobSequencer(ctx context.Context) {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.leader.IsLeader("job-sequencer") {
+			continue
+		}
 		err := s.checkJobSequences(ctx)
 		if err != nil {
 			s.logger.Error("failed to run checkJobSequences", zap.Error(err))
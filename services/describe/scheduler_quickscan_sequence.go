@@ -1,6 +1,7 @@
 package describe
 
 import (
+	"errors"
 	"fmt"
 	flow "github.com/Azure/go-workflow"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -8,6 +9,7 @@ import (
 	"github.com/opengovern/og-util/pkg/httpclient"
 	"github.com/opengovern/og-util/pkg/ticker"
 	"github.com/opengovern/opencomply/services/describe/db/model"
+	"github.com/opengovern/opencomply/services/describe/schedulers/compliance"
 	"github.com/opengovern/opencomply/services/integration/api/models"
 	integration_type "github.com/opengovern/opencomply/services/integration/integration-type"
 	"go.uber.org/zap"
@@ -22,6 +24,9 @@ func (s *Scheduler) ScheduleQuickScanSequence(ctx context.Context) {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.leader.IsLeader("quick-scan-sequencer") {
+			continue
+		}
 		err := s.checkQuickScanSequence(ctx)
 		if err != nil {
 			s.logger.Error("failed to run checkJobSequences", zap.Error(err))
@@ -96,7 +101,7 @@ type RunQuickComplianceScan struct {
 }
 
 func (s *RunQuickComplianceScan) Do(ctx context.Context) error {
-	jobs, err := s.s.complianceScheduler.CreateComplianceReportJobs(false, s.job.FrameworkID, nil, s.job.IntegrationIDs, true, "QuickScanSequencer", &s.job.ID)
+	jobs, err := s.s.complianceScheduler.CreateComplianceReportJobs(false, s.job.FrameworkID, nil, s.job.IntegrationIDs, true, "QuickScanSequencer", &s.job.ID, nil)
 	if err != nil {
 		return fmt.Errorf("error while creating compliance job: %v", err)
 	}
@@ -191,6 +196,17 @@ func (s *DescribeDependencies) Do(ctx context.Context) error {
 	return nil
 }
 
+// describeForStaleData triggers discovery of the given resource types on behalf of a compliance job waiting for fresh data
+func (s *Scheduler) describeForStaleData(integration models.Integration, resourceTypes []string, parentJobID uint) error {
+	for _, resourceType := range resourceTypes {
+		_, err := s.describe(integration, resourceType, false, false, false, &parentJobID, compliance.StalenessGuardCreatedBy, nil)
+		if err != nil && !errors.Is(err, ErrJobInProgress) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Scheduler) getFrameworkDependencies(frameworkID string) ([]string, error) {
 	var clientCtx = &httpclient.Context{UserRole: apiAuth.AdminRole}
 	framework, err := s.complianceClient.GetBenchmark(clientCtx, frameworkID)
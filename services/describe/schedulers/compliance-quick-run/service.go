@@ -21,6 +21,7 @@ const JobSchedulingInterval = 10 * time.Second
 
 type JobScheduler struct {
 	runSetupNatsStreams func(context.Context) error
+	isLeader            func(loop string) bool
 	conf                config.SchedulerConfig
 	logger              *zap.Logger
 	db                  db.Database
@@ -33,6 +34,7 @@ type JobScheduler struct {
 
 func New(
 	runSetupNatsStreams func(context.Context) error,
+	isLeader func(loop string) bool,
 	conf config.SchedulerConfig,
 	logger *zap.Logger,
 	db db.Database,
@@ -44,6 +46,7 @@ func New(
 ) *JobScheduler {
 	return &JobScheduler{
 		runSetupNatsStreams: runSetupNatsStreams,
+		isLeader:            isLeader,
 		conf:                conf,
 		logger:              logger,
 		db:                  db,
@@ -71,6 +74,9 @@ func (s *JobScheduler) RunPublisher(ctx context.Context) {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.isLeader("compliance-quick-run-publisher") {
+			continue
+		}
 		if err := s.runPublisher(ctx); err != nil {
 			s.logger.Error("failed to run compliance publisher", zap.Error(err))
 			continue
@@ -0,0 +1,76 @@
+package compliance
+
+import (
+	"sync"
+
+	"github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	complianceApi "github.com/opengovern/opencomply/services/compliance/api"
+	"github.com/opengovern/opencomply/services/compliance/client"
+	"go.uber.org/zap"
+)
+
+// frameworkCache keeps the benchmark trees used to plan runners in memory.
+// Entries are tagged with the frameworks version of the compliance service and are dropped
+// as soon as the version changes, i.e. after every framework sync.
+type frameworkCache struct {
+	logger           *zap.Logger
+	complianceClient client.ComplianceServiceClient
+
+	mu      sync.Mutex
+	version string
+	trees   map[string]*complianceApi.BenchmarkTree
+}
+
+func newFrameworkCache(logger *zap.Logger, complianceClient client.ComplianceServiceClient) *frameworkCache {
+	return &frameworkCache{
+		logger:           logger,
+		complianceClient: complianceClient,
+		trees:            make(map[string]*complianceApi.BenchmarkTree),
+	}
+}
+
+// Refresh checks the frameworks version and invalidates the cache if it has changed.
+func (c *frameworkCache) Refresh() error {
+	version, err := c.complianceClient.GetFrameworksVersion(&httpclient.Context{UserRole: api.AdminRole})
+	if err != nil {
+		c.logger.Error("failed to get frameworks version", zap.Error(err))
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version != c.version {
+		c.logger.Info("frameworks version changed, invalidating framework cache",
+			zap.String("oldVersion", c.version), zap.String("newVersion", version))
+		c.version = version
+		c.trees = make(map[string]*complianceApi.BenchmarkTree)
+	}
+	return nil
+}
+
+// Get returns the benchmark tree of the framework, fetching it in a single call on a cache miss.
+func (c *frameworkCache) Get(frameworkID string) (*complianceApi.BenchmarkTree, error) {
+	c.mu.Lock()
+	tree, ok := c.trees[frameworkID]
+	c.mu.Unlock()
+	if ok {
+		return tree, nil
+	}
+
+	tree, err := c.complianceClient.GetBenchmarkTree(&httpclient.Context{UserRole: api.AdminRole}, frameworkID)
+	if err != nil {
+		c.logger.Error("failed to get benchmark tree", zap.String("frameworkID", frameworkID), zap.Error(err))
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// a tree fetched while a sync was happening belongs to a newer version, keep the cache consistent
+	if tree.Version != c.version {
+		c.version = tree.Version
+		c.trees = make(map[string]*complianceApi.BenchmarkTree)
+	}
+	c.trees[frameworkID] = tree
+	return tree, nil
+}
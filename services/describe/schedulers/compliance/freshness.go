@@ -0,0 +1,148 @@
+package compliance
+
+import (
+	"time"
+
+	complianceApi "github.com/opengovern/opencomply/services/compliance/api"
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	integrationapi "github.com/opengovern/opencomply/services/integration/api/models"
+	integration_type "github.com/opengovern/opencomply/services/integration/integration-type"
+	"go.uber.org/zap"
+)
+
+// StalenessGuardCreatedBy is the creator of the describe jobs triggered to refresh stale data of a compliance job
+const StalenessGuardCreatedBy = "ComplianceStalenessGuard"
+
+// MaxFreshDiscoveryWait is how long a compliance job waits for the discovery it triggered before running on stale data
+const MaxFreshDiscoveryWait = 2 * time.Hour
+
+// checkDataFreshness compares the last successful discovery of every resource type the framework queries with
+// the maximum data age of the framework. If the framework refreshes stale data, discovery is triggered once and
+// the job waits for it. Returns false while the job has to wait, otherwise stores the data timestamp on the job.
+func (s *JobScheduler) checkDataFreshness(job model.ComplianceJob, tree *complianceApi.BenchmarkTree,
+	integrations []integrationapi.Integration) (bool, error) {
+	if job.DataAsOf != nil || job.EvaluatedOnStaleData {
+		return true, nil
+	}
+
+	framework, ok := tree.Benchmarks[tree.RootBenchmarkID]
+	if !ok {
+		return true, nil
+	}
+
+	tables := make(map[string]bool)
+	for _, control := range tree.Controls {
+		if control.Query == nil || control.Query.Global {
+			continue
+		}
+		for _, table := range control.Query.ListOfTables {
+			tables[table] = true
+		}
+	}
+
+	var integrationIDs []string
+	resourceTypesMap := make(map[string]bool)
+	integrationResourceTypes := make(map[string][]string)
+	for _, i := range integrations {
+		integrationType, ok := integration_type.IntegrationTypes[i.IntegrationType]
+		if !ok {
+			continue
+		}
+		validResourceTypes, err := integrationType.GetResourceTypesByLabels(i.Labels)
+		if err != nil {
+			s.logger.Error("failed to get integration resource types", zap.String("integrationID", i.IntegrationID), zap.Error(err))
+			return false, err
+		}
+		for table := range tables {
+			resourceType := integrationType.GetResourceTypeFromTableName(table)
+			if resourceType == "" {
+				continue
+			}
+			if _, ok := validResourceTypes[resourceType]; !ok {
+				continue
+			}
+			integrationResourceTypes[i.IntegrationID] = append(integrationResourceTypes[i.IntegrationID], resourceType)
+			resourceTypesMap[resourceType] = true
+		}
+		integrationIDs = append(integrationIDs, i.IntegrationID)
+	}
+	if len(resourceTypesMap) == 0 {
+		return true, nil
+	}
+
+	var resourceTypes []string
+	for rt := range resourceTypesMap {
+		resourceTypes = append(resourceTypes, rt)
+	}
+	lastDescribes, err := s.db.ListLastSuccessfulDescribes(integrationIDs, resourceTypes)
+	if err != nil {
+		s.logger.Error("failed to list last successful describes", zap.Error(err))
+		return false, err
+	}
+	succeededAt := make(map[string]time.Time)
+	for _, d := range lastDescribes {
+		succeededAt[d.IntegrationID+"|"+d.ResourceType] = d.SucceededAt
+	}
+
+	var dataAsOf *time.Time
+	staleResourceTypes := make(map[string][]string)
+	for integrationID, rts := range integrationResourceTypes {
+		for _, rt := range rts {
+			t, ok := succeededAt[integrationID+"|"+rt]
+			if ok && (dataAsOf == nil || t.Before(*dataAsOf)) {
+				dataAsOf = &t
+			}
+			if framework.MaxDataAgeHours == nil {
+				continue
+			}
+			if !ok || t.Before(time.Now().Add(-time.Duration(*framework.MaxDataAgeHours)*time.Hour)) {
+				staleResourceTypes[integrationID] = append(staleResourceTypes[integrationID], rt)
+			}
+		}
+	}
+	stale := len(staleResourceTypes) > 0
+
+	if stale && framework.RefreshStaleData && s.runDiscovery != nil {
+		if job.DiscoveryRequestedAt == nil {
+			for _, i := range integrations {
+				if len(staleResourceTypes[i.IntegrationID]) == 0 {
+					continue
+				}
+				err = s.runDiscovery(i, staleResourceTypes[i.IntegrationID], job.ID)
+				if err != nil {
+					s.logger.Error("failed to trigger discovery for stale data", zap.Uint("jobID", job.ID),
+						zap.String("integrationID", i.IntegrationID), zap.Error(err))
+				}
+			}
+			s.logger.Info("triggered discovery for stale data", zap.Uint("jobID", job.ID), zap.Int("integrations", len(staleResourceTypes)))
+			err = s.db.UpdateComplianceJobDiscoveryRequestedAt(job.ID, time.Now())
+			if err != nil {
+				s.logger.Error("failed to update compliance job discovery requested at", zap.Error(err))
+				return false, err
+			}
+			return false, nil
+		}
+
+		if job.DiscoveryRequestedAt.After(time.Now().Add(-MaxFreshDiscoveryWait)) {
+			jobsNotDone, err := s.db.CheckJobsDoneByParentIDAndCreatedBy(StalenessGuardCreatedBy, job.ID)
+			if err != nil {
+				s.logger.Error("failed to check staleness guard describe jobs", zap.Error(err))
+				return false, err
+			}
+			if len(jobsNotDone) > 0 {
+				s.logger.Info("waiting for fresh discovery", zap.Uint("jobID", job.ID), zap.Int("pending", len(jobsNotDone)))
+				return false, nil
+			}
+		}
+	}
+
+	if stale {
+		s.logger.Warn("compliance job is evaluated on stale data", zap.Uint("jobID", job.ID), zap.String("frameworkID", job.FrameworkID))
+	}
+	err = s.db.UpdateComplianceJobDataFreshness(job.ID, dataAsOf, stale)
+	if err != nil {
+		s.logger.Error("failed to update compliance job data freshness", zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
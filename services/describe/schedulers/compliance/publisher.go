@@ -96,6 +96,8 @@ func (s *JobScheduler) runPublisher(ctx context.Context, manuals bool) error {
 					Query:         *query,
 					IntegrationID: it.IntegrationID,
 					ProviderID:    providerID,
+
+					ResourceCollectionID: it.ResourceCollectionID,
 				},
 			}
 
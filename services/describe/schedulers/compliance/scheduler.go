@@ -63,6 +63,16 @@ func (s *JobScheduler) runScheduler() error {
 			continue
 		}
 
+		for _, rc := range assignments.ResourceCollections {
+			if !rc.Status {
+				continue
+			}
+			err = s.scheduleResourceCollectionJob(benchmark.ID, rc.ResourceCollectionID, integrationIDs)
+			if err != nil {
+				return err
+			}
+		}
+
 		complianceJob, err := s.db.GetLastComplianceJob(true, benchmark.ID)
 		if err != nil {
 			s.logger.Error("error while getting last compliance job", zap.Error(err))
@@ -73,7 +83,7 @@ func (s *JobScheduler) runScheduler() error {
 		if complianceJob == nil ||
 			complianceJob.CreatedAt.Before(timeAt) {
 
-			_, err := s.CreateComplianceReportJobs(true, benchmark.ID, complianceJob, integrationIDs, false, "system", nil)
+			_, err := s.CreateComplianceReportJobs(true, benchmark.ID, complianceJob, integrationIDs, false, "system", nil, nil)
 			if err != nil {
 				s.logger.Error("error while creating compliance job", zap.Error(err))
 				return err
@@ -85,3 +95,27 @@ func (s *JobScheduler) runScheduler() error {
 
 	return nil
 }
+
+func (s *JobScheduler) scheduleResourceCollectionJob(benchmarkID, resourceCollectionID string, integrationIDs []string) error {
+	complianceJob, err := s.db.GetLastResourceCollectionComplianceJob(true, benchmarkID, resourceCollectionID)
+	if err != nil {
+		s.logger.Error("error while getting last resource collection compliance job", zap.Error(err),
+			zap.String("resource_collection_id", resourceCollectionID))
+		return err
+	}
+
+	timeAt := time.Now().Add(-s.complianceIntervalHours)
+	if complianceJob != nil && !complianceJob.CreatedAt.Before(timeAt) {
+		return nil
+	}
+
+	_, err = s.CreateComplianceReportJobs(true, benchmarkID, complianceJob, integrationIDs, false, "system", nil, &resourceCollectionID)
+	if err != nil {
+		s.logger.Error("error while creating resource collection compliance job", zap.Error(err),
+			zap.String("resource_collection_id", resourceCollectionID))
+		return err
+	}
+
+	ComplianceJobsCount.WithLabelValues("successful").Inc()
+	return nil
+}
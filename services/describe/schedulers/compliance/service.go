@@ -14,6 +14,7 @@ import (
 	"github.com/opengovern/opencomply/services/compliance/client"
 	"github.com/opengovern/opencomply/services/describe/config"
 	"github.com/opengovern/opencomply/services/describe/db"
+	integrationapi "github.com/opengovern/opencomply/services/integration/api/models"
 	integrationClient "github.com/opengovern/opencomply/services/integration/client"
 	"go.uber.org/zap"
 )
@@ -23,6 +24,8 @@ const CleanupInterval = 10 * time.Minute
 
 type JobScheduler struct {
 	runSetupNatsStreams     func(context.Context) error
+	isLeader                func(loop string) bool
+	runDiscovery            func(integration integrationapi.Integration, resourceTypes []string, parentJobID uint) error
 	conf                    config.SchedulerConfig
 	logger                  *zap.Logger
 	complianceClient        client.ComplianceServiceClient
@@ -31,10 +34,13 @@ type JobScheduler struct {
 	jq                      *jq.JobQueue
 	esClient                opengovernance.Client
 	complianceIntervalHours time.Duration
+	frameworkCache          *frameworkCache
 }
 
 func New(
 	runSetupNatsStreams func(context.Context) error,
+	isLeader func(loop string) bool,
+	runDiscovery func(integration integrationapi.Integration, resourceTypes []string, parentJobID uint) error,
 	conf config.SchedulerConfig,
 	logger *zap.Logger,
 	complianceClient client.ComplianceServiceClient,
@@ -46,6 +52,8 @@ func New(
 ) *JobScheduler {
 	return &JobScheduler{
 		runSetupNatsStreams:     runSetupNatsStreams,
+		isLeader:                isLeader,
+		runDiscovery:            runDiscovery,
 		conf:                    conf,
 		logger:                  logger,
 		complianceClient:        complianceClient,
@@ -54,6 +62,7 @@ func New(
 		jq:                      jq,
 		esClient:                esClient,
 		complianceIntervalHours: complianceIntervalHours,
+		frameworkCache:          newFrameworkCache(logger, complianceClient),
 	}
 }
 
@@ -94,6 +103,9 @@ func (s *JobScheduler) CleanupComplianceResults(ctx context.Context) {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.isLeader("compliance-results-cleanup") {
+			continue
+		}
 		integrations, err := s.integrationClient.ListIntegrations(&httpclient.Context{UserRole: authAPI.AdminRole}, nil)
 		if err != nil {
 			s.logger.Error("Failed to list sources", zap.Error(err))
@@ -114,6 +126,9 @@ func (s *JobScheduler) RunScheduler() {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.isLeader("compliance-scheduler") {
+			continue
+		}
 		if err := s.runScheduler(); err != nil {
 			s.logger.Error("failed to run compliance scheduler", zap.Error(err))
 			ComplianceJobsCount.WithLabelValues("failure").Inc()
@@ -129,6 +144,9 @@ func (s JobScheduler) RunEnqueueRunnersCycle() {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.isLeader("compliance-enqueue-runners") {
+			continue
+		}
 		if err := s.enqueueRunnersCycle(); err != nil {
 			s.logger.Error("failed to run enqueue runners cycle", zap.Error(err))
 			continue
@@ -138,11 +156,18 @@ func (s JobScheduler) RunEnqueueRunnersCycle() {
 
 func (s *JobScheduler) RunPublisher(ctx context.Context, manuals bool) {
 	s.logger.Info("Scheduling publisher on a timer")
+	loop := "compliance-publisher"
+	if manuals {
+		loop = "compliance-publisher-manual"
+	}
 
 	t := ticker.NewTicker(JobSchedulingInterval, time.Second*10)
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.isLeader(loop) {
+			continue
+		}
 		if err := s.runPublisher(ctx, manuals); err != nil {
 			s.logger.Error("failed to run compliance publisher", zap.Error(err))
 			ComplianceJobsCount.WithLabelValues("failure").Inc()
@@ -153,11 +178,18 @@ func (s *JobScheduler) RunPublisher(ctx context.Context, manuals bool) {
 
 func (s *JobScheduler) RunSummarizer(ctx context.Context, manuals bool) {
 	s.logger.Info("Scheduling compliance summarizer on a timer")
+	loop := "compliance-summarizer"
+	if manuals {
+		loop = "compliance-summarizer-manual"
+	}
 
 	t := ticker.NewTicker(SummarizerSchedulingInterval, time.Second*10)
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.isLeader(loop) {
+			continue
+		}
 		if err := s.runSummarizer(ctx, manuals); err != nil {
 			s.logger.Error("failed to run compliance summarizer", zap.Error(err))
 			ComplianceJobsCount.WithLabelValues("failure").Inc()
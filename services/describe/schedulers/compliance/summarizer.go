@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/opengovern/og-util/pkg/api"
+	"github.com/opengovern/og-util/pkg/httpclient"
 	summarizer "github.com/opengovern/opencomply/jobs/compliance-summarizer-job"
 	types2 "github.com/opengovern/opencomply/jobs/compliance-summarizer-job/types"
 	"github.com/opengovern/opencomply/pkg/types"
@@ -219,7 +221,16 @@ func (s *JobScheduler) finishComplianceJob(job model.ComplianceJob) error {
 		return s.db.UpdateComplianceJob(job.ID, model.ComplianceJobFailed, builder.String())
 	}
 
-	return s.db.UpdateComplianceJob(job.ID, model.ComplianceJobSucceeded, "")
+	if err = s.db.UpdateComplianceJob(job.ID, model.ComplianceJobSucceeded, ""); err != nil {
+		return err
+	}
+
+	// scheduled reports are best effort, failing to trigger them does not fail the job
+	_, err = s.complianceClient.GenerateScheduledReports(&httpclient.Context{UserRole: api.AdminRole}, job.ID)
+	if err != nil {
+		s.logger.Error("failed to generate scheduled reports", zap.Uint("jobId", job.ID), zap.Error(err))
+	}
+	return nil
 }
 
 func (s *JobScheduler) CreateSummarizer(benchmarkId string, integrationIDs []string, jobId *uint, triggerType model.ComplianceTriggerType) error {
@@ -253,6 +264,16 @@ func (s *JobScheduler) triggerSummarizer(ctx context.Context, job model.Complian
 		BenchmarkID:     job.BenchmarkID,
 		CreatedAt:       job.CreatedAt,
 	}
+	complianceJob, err := s.db.GetComplianceJobByID(job.ParentJobID)
+	if err != nil {
+		s.logger.Error("failed to get compliance job", zap.Uint("jobID", job.ParentJobID), zap.Error(err))
+		return err
+	}
+	if complianceJob != nil {
+		summarizerJob.DataAsOf = complianceJob.DataAsOf
+		summarizerJob.EvaluatedOnStaleData = complianceJob.EvaluatedOnStaleData
+		summarizerJob.ResourceCollectionID = complianceJob.ResourceCollectionID
+	}
 	jobJson, err := json.Marshal(summarizerJob)
 	if err != nil {
 		_ = s.db.UpdateSummarizerJob(job.ID, summarizer.ComplianceSummarizerFailed, job.CreatedAt, err.Error())
@@ -1,6 +1,7 @@
 package compliance
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/opengovern/og-util/pkg/api"
@@ -24,13 +25,14 @@ func (s *JobScheduler) buildRunners(
 	benchmarkID string,
 	currentRunnerExistMap map[string]bool,
 	triggerType model.ComplianceTriggerType,
+	tree *complianceApi.BenchmarkTree,
 ) ([]*model.ComplianceRunner, []*model.ComplianceRunner, error) {
-	ctx := &httpclient.Context{UserRole: api.AdminRole}
 	var runners []*model.ComplianceRunner
 	var globalRunners []*model.ComplianceRunner
 
-	benchmark, err := s.complianceClient.GetBenchmark(ctx, benchmarkID)
-	if err != nil {
+	benchmark, ok := tree.Benchmarks[benchmarkID]
+	if !ok {
+		err := fmt.Errorf("benchmark %s not found in framework %s", benchmarkID, tree.RootBenchmarkID)
 		s.logger.Error("error while getting benchmark", zap.Error(err), zap.String("benchmarkID", benchmarkID))
 		return nil, nil, err
 	}
@@ -47,7 +49,7 @@ func (s *JobScheduler) buildRunners(
 	}
 
 	for _, child := range benchmark.Children {
-		childRunners, childGlobalRunners, err := s.buildRunners(parentJobID, connectionID, connector, resourceCollectionID, rootBenchmarkID, append(parentBenchmarkIDs, benchmarkID), child, currentRunnerExistMap, triggerType)
+		childRunners, childGlobalRunners, err := s.buildRunners(parentJobID, connectionID, connector, resourceCollectionID, rootBenchmarkID, append(parentBenchmarkIDs, benchmarkID), child, currentRunnerExistMap, triggerType, tree)
 		if err != nil {
 			s.logger.Error("error while building child runners", zap.Error(err))
 			return nil, nil, err
@@ -58,8 +60,9 @@ func (s *JobScheduler) buildRunners(
 	}
 
 	for _, controlID := range benchmark.Controls {
-		control, err := s.complianceClient.GetControl(ctx, controlID)
-		if err != nil {
+		control, ok := tree.Controls[controlID]
+		if !ok {
+			err := fmt.Errorf("control %s not found in framework %s", controlID, tree.RootBenchmarkID)
 			s.logger.Error("error while getting control", zap.Error(err), zap.String("controlID", controlID))
 			return nil, nil, err
 		}
@@ -100,7 +103,7 @@ func (s *JobScheduler) buildRunners(
 				FailureMessage:       "",
 				TriggerType:          triggerType,
 			}
-			err = runnerJob.SetCallers([]runner.Caller{callers})
+			err := runnerJob.SetCallers([]runner.Caller{callers})
 			if err != nil {
 				return nil, nil, err
 			}
@@ -118,7 +121,7 @@ func (s *JobScheduler) buildRunners(
 				FailureMessage:       "",
 				TriggerType:          triggerType,
 			}
-			err = runnerJob.SetCallers([]runner.Caller{callers})
+			err := runnerJob.SetCallers([]runner.Caller{callers})
 			if err != nil {
 				return nil, nil, err
 			}
@@ -198,7 +201,7 @@ func (s *JobScheduler) buildRunners(
 }
 
 func (s *JobScheduler) CreateComplianceReportJobs(withIncident bool, frameworkID string,
-	lastJob *model.ComplianceJob, integrationIDs []string, manual bool, createdBy string, parentJobID *uint) ([]model.ComplianceJob, error) {
+	lastJob *model.ComplianceJob, integrationIDs []string, manual bool, createdBy string, parentJobID *uint, resourceCollectionID *string) ([]model.ComplianceJob, error) {
 	// delete old runners
 	if lastJob != nil {
 		err := s.db.DeleteOldRunnerJob(&lastJob.ID)
@@ -221,9 +224,15 @@ func (s *JobScheduler) CreateComplianceReportJobs(withIncident bool, frameworkID
 	var jobs []model.ComplianceJob
 	var integrationsEpoch []string
 
+	// a run scoped to a resource collection is kept in a single job so its summary covers the whole collection
+	epochSize := 10
+	if resourceCollectionID != nil {
+		epochSize = len(integrationIDs)
+	}
+
 	for _, integrationID := range integrationIDs {
 		integrationsEpoch = append(integrationsEpoch, integrationID)
-		if len(integrationsEpoch) >= 10 {
+		if len(integrationsEpoch) >= epochSize {
 			job := model.ComplianceJob{
 				FrameworkID:         frameworkID,
 				WithIncidents:       withIncident,
@@ -233,6 +242,8 @@ func (s *JobScheduler) CreateComplianceReportJobs(withIncident bool, frameworkID
 				TriggerType:         triggerType,
 				CreatedBy:           createdBy,
 				ParentID:            parentJobID,
+
+				ResourceCollectionID: resourceCollectionID,
 			}
 			err := s.db.CreateComplianceJob(nil, &job)
 			if err != nil {
@@ -253,6 +264,8 @@ func (s *JobScheduler) CreateComplianceReportJobs(withIncident bool, frameworkID
 			TriggerType:         triggerType,
 			CreatedBy:           createdBy,
 			ParentID:            parentJobID,
+
+			ResourceCollectionID: resourceCollectionID,
 		}
 		err := s.db.CreateComplianceJob(nil, &job)
 		if err != nil {
@@ -268,6 +281,9 @@ func (s *JobScheduler) CreateComplianceReportJobs(withIncident bool, frameworkID
 func (s *JobScheduler) enqueueRunnersCycle() error {
 	s.logger.Info("enqueue runners cycle started")
 	var err error
+	if err = s.frameworkCache.Refresh(); err != nil {
+		return err
+	}
 	jobsWithUnqueuedRunners, err := s.db.ListComplianceJobsWithUnqueuedRunners(true)
 	if err != nil {
 		s.logger.Error("error while listing jobs with unqueued runners", zap.Error(err))
@@ -276,6 +292,11 @@ func (s *JobScheduler) enqueueRunnersCycle() error {
 	s.logger.Info("jobs with unqueued runners", zap.Int("count", len(jobsWithUnqueuedRunners)))
 	for _, job := range jobsWithUnqueuedRunners {
 		s.logger.Info("processing job with unqueued runners", zap.Uint("jobID", job.ID))
+		tree, err := s.frameworkCache.Get(job.FrameworkID)
+		if err != nil {
+			s.logger.Error("error while getting framework", zap.Error(err), zap.String("frameworkID", job.FrameworkID))
+			continue
+		}
 		var allRunners []*model.ComplianceRunner
 		var assignments *complianceApi.BenchmarkAssignedEntities
 		integrations, err := s.integrationClient.ListIntegrationsByFilters(&httpclient.Context{UserRole: api.AdminRole}, integrationapi.ListIntegrationsRequest{
@@ -285,6 +306,14 @@ func (s *JobScheduler) enqueueRunnersCycle() error {
 			s.logger.Error("error while getting integrations", zap.Error(err))
 			continue
 		}
+		fresh, err := s.checkDataFreshness(job, tree, integrations.Integrations)
+		if err != nil {
+			s.logger.Error("error while checking data freshness", zap.Error(err), zap.Uint("jobID", job.ID))
+			continue
+		}
+		if !fresh {
+			continue
+		}
 		assignments = &complianceApi.BenchmarkAssignedEntities{}
 		for _, integration := range integrations.Integrations {
 			assignment := complianceApi.BenchmarkAssignedIntegration{
@@ -304,7 +333,7 @@ func (s *JobScheduler) enqueueRunnersCycle() error {
 				continue
 			}
 			connection := it
-			runners, globalRunners, err = s.buildRunners(job.ID, &connection.IntegrationID, &connection.IntegrationType, nil, job.FrameworkID, nil, job.FrameworkID, nil, job.TriggerType)
+			runners, globalRunners, err = s.buildRunners(job.ID, &connection.IntegrationID, &connection.IntegrationType, job.ResourceCollectionID, job.FrameworkID, nil, job.FrameworkID, nil, job.TriggerType, tree)
 			if err != nil {
 				s.logger.Error("error while building runners", zap.Error(err))
 				return err
@@ -24,6 +24,9 @@ func (s *Scheduler) OldResourceDeleter(ctx context.Context) {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.isLeader("old-resource-deleter") {
+			continue
+		}
 		if err := s.runDeleter(ctx); err != nil {
 			s.logger.Error("failed to run deleter", zap.Error(err))
 			continue
@@ -12,6 +12,7 @@ import (
 )
 
 type Scheduler struct {
+	isLeader         func(loop string) bool
 	conf             config2.SchedulerConfig
 	logger           *zap.Logger
 	complianceClient client.ComplianceServiceClient
@@ -19,8 +20,9 @@ type Scheduler struct {
 	esClient         opengovernance.Client
 }
 
-func New(conf config2.SchedulerConfig, logger *zap.Logger, complianceClient client.ComplianceServiceClient, db db.Database, esClient opengovernance.Client) *Scheduler {
+func New(isLeader func(loop string) bool, conf config2.SchedulerConfig, logger *zap.Logger, complianceClient client.ComplianceServiceClient, db db.Database, esClient opengovernance.Client) *Scheduler {
 	return &Scheduler{
+		isLeader:         isLeader,
 		conf:             conf,
 		logger:           logger,
 		complianceClient: complianceClient,
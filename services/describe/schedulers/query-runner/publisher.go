@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"text/template"
 
+	"github.com/jackc/pgtype"
 	"github.com/opengovern/og-util/pkg/api"
 	"github.com/opengovern/og-util/pkg/httpclient"
 	queryrunner "github.com/opengovern/opencomply/jobs/query-runner-job"
@@ -73,6 +74,16 @@ func (s *JobScheduler) runPublisher(ctx context.Context) error {
 		for _, qp := range queryParams.Items {
 			queryParamMap[qp.Key] = qp.Value
 		}
+		if job.Parameters != nil && job.Parameters.Status == pgtype.Present {
+			var jobParams map[string]string
+			if err := json.Unmarshal(job.Parameters.Bytes, &jobParams); err != nil {
+				_ = s.db.UpdateQueryRunnerJobStatus(job.ID, queryrunner.QueryRunnerFailed, "failed to read job parameters")
+				continue
+			}
+			for k, v := range jobParams {
+				queryParamMap[k] = v
+			}
+		}
 		queryTemplate, err := template.New("query").Parse(query)
 		if err != nil {
 			return err
@@ -0,0 +1,248 @@
+package query_runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/opengovern/og-util/pkg/ticker"
+	queryrunner "github.com/opengovern/opencomply/jobs/query-runner-job"
+	"github.com/opengovern/opencomply/services/describe/api"
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	"github.com/opengovern/opencomply/services/describe/es"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// QueryScheduleResultWait is how long a succeeded run waits for its result to be ingested
+	QueryScheduleResultWait = 10 * time.Minute
+	// QueryScheduleRunsWithRows is the number of the latest succeeded runs of a schedule keeping their row
+	// snapshots, the older runs keep their counts only
+	QueryScheduleRunsWithRows = 10
+)
+
+// NextQueryScheduleRun returns the first time after from matching the cron expression of a schedule
+func NextQueryScheduleRun(cronExpression string, from time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(from), nil
+}
+
+// RunQuerySchedules creates the query runner jobs of the due schedules and snapshots the results of their
+// finished jobs
+func (s *JobScheduler) RunQuerySchedules(ctx context.Context) {
+	s.logger.Info("Scheduling query schedules on a timer")
+
+	t := ticker.NewTicker(JobSchedulingInterval, time.Second*10)
+	defer t.Stop()
+
+	for ; ; <-t.C {
+		if !s.isLeader("query-schedules") {
+			continue
+		}
+		if err := s.triggerDueQuerySchedules(); err != nil {
+			s.logger.Error("failed to trigger query schedules", zap.Error(err))
+		}
+		if err := s.completeQueryScheduleRuns(ctx); err != nil {
+			s.logger.Error("failed to complete query schedule runs", zap.Error(err))
+		}
+	}
+}
+
+func (s *JobScheduler) triggerDueQuerySchedules() error {
+	now := time.Now()
+	schedules, err := s.db.ListDueQuerySchedules(now)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		next, err := NextQueryScheduleRun(schedule.CronExpression, now)
+		if err != nil {
+			s.logger.Error("invalid query schedule cron expression", zap.Uint("scheduleId", schedule.ID), zap.Error(err))
+			continue
+		}
+
+		// runs of a schedule are serialized so every run is diffed against the one before it
+		running, err := s.db.CountRunningQueryScheduleRuns(schedule.ID)
+		if err != nil {
+			return err
+		}
+		if running > 0 {
+			s.logger.Info("skipping query schedule, previous run is still running", zap.Uint("scheduleId", schedule.ID))
+			if err = s.db.UpdateQueryScheduleNextRun(schedule.ID, next, nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		parameters := schedule.Parameters
+		job := model.QueryRunnerJob{
+			QueryId:    schedule.QueryID,
+			CreatedBy:  schedule.CreatedBy,
+			Status:     queryrunner.QueryRunnerCreated,
+			Parameters: &parameters,
+		}
+		run := model.QueryScheduleRun{
+			ScheduleID:  schedule.ID,
+			Status:      model.QueryScheduleRunRunning,
+			Rows:        pgtype.JSONB{Status: pgtype.Null},
+			AddedRows:   pgtype.JSONB{Status: pgtype.Null},
+			RemovedRows: pgtype.JSONB{Status: pgtype.Null},
+			Thresholds:  pgtype.JSONB{Status: pgtype.Null},
+		}
+		if err = s.db.CreateQueryScheduleRun(&job, &run); err != nil {
+			return err
+		}
+		s.logger.Info("query schedule triggered", zap.Uint("scheduleId", schedule.ID), zap.Uint("jobId", job.ID))
+
+		if err = s.db.UpdateQueryScheduleNextRun(schedule.ID, next, &now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JobScheduler) completeQueryScheduleRuns(ctx context.Context) error {
+	runs, err := s.db.ListRunningQueryScheduleRuns()
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		if err := s.completeQueryScheduleRun(ctx, run); err != nil {
+			s.logger.Error("failed to complete query schedule run", zap.Uint("scheduleId", run.ScheduleID),
+				zap.Uint("runId", run.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *JobScheduler) completeQueryScheduleRun(ctx context.Context, run model.QueryScheduleRun) error {
+	job, err := s.db.GetQueryRunnerJob(run.QueryRunnerJobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.failQueryScheduleRun(&run, "query runner job is missing")
+		}
+		return err
+	}
+	switch job.Status {
+	case queryrunner.QueryRunnerSucceeded:
+	case queryrunner.QueryRunnerFailed, queryrunner.QueryRunnerTimeOut, queryrunner.QueryRunnerCanceled:
+		return s.failQueryScheduleRun(&run, fmt.Sprintf("query runner job %s: %s", job.Status, job.FailureMessage))
+	default:
+		return nil
+	}
+
+	result, err := es.GetQueryRunResult(ctx, s.logger, s.esClient, strconv.FormatUint(uint64(job.ID), 10))
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		if time.Since(job.UpdatedAt) > QueryScheduleResultWait {
+			return s.failQueryScheduleRun(&run, "query result not found")
+		}
+		return nil
+	}
+
+	schedule, err := s.db.GetQuerySchedule(run.ScheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return s.failQueryScheduleRun(&run, "query schedule is deleted")
+	}
+	var thresholds []api.QueryScheduleThreshold
+	if schedule.Thresholds.Status == pgtype.Present {
+		if err = json.Unmarshal(schedule.Thresholds.Bytes, &thresholds); err != nil {
+			return s.failQueryScheduleRun(&run, "failed to read thresholds")
+		}
+	}
+
+	current := querySnapshot{columns: result.ColumnNames, rows: result.Result}
+	if current.rows == nil {
+		current.rows = make([][]string, 0)
+	}
+	currentKey, err := rowKeyFunc(current.columns, schedule.KeyColumns)
+	if err != nil {
+		return s.failQueryScheduleRun(&run, err.Error())
+	}
+
+	// the run is a new baseline if there is no previous run or the key columns are not in its result anymore
+	previousRun, err := s.db.GetLastSucceededQueryScheduleRun(run.ScheduleID, run.ID)
+	if err != nil {
+		return err
+	}
+	var previous *querySnapshot
+	added, removed := make([][]string, 0), make([][]string, 0)
+	if previousRun != nil {
+		p := querySnapshot{
+			columns:      previousRun.ColumnNames,
+			addedCount:   previousRun.AddedCount,
+			removedCount: previousRun.RemovedCount,
+		}
+		if previousRun.Rows.Status == pgtype.Present {
+			if err = json.Unmarshal(previousRun.Rows.Bytes, &p.rows); err != nil {
+				return s.failQueryScheduleRun(&run, "failed to read the previous run")
+			}
+		}
+		if previousKey, err := rowKeyFunc(p.columns, schedule.KeyColumns); err == nil {
+			added, removed = diffRows(previousKey, currentKey, p.rows, current.rows)
+			previous = &p
+			run.PreviousRunID = &previousRun.ID
+		}
+	}
+	current.addedCount, current.removedCount = len(added), len(removed)
+
+	thresholdResults, triggered := evaluateThresholds(thresholds, current, previous)
+
+	run.Status = model.QueryScheduleRunSucceeded
+	run.FailureMessage = ""
+	run.ColumnNames = current.columns
+	run.RowCount = len(current.rows)
+	run.AddedCount = len(added)
+	run.RemovedCount = len(removed)
+	run.Triggered = triggered
+	for _, field := range []struct {
+		dst *pgtype.JSONB
+		src any
+	}{
+		{&run.Rows, current.rows},
+		{&run.AddedRows, added},
+		{&run.RemovedRows, removed},
+		{&run.Thresholds, thresholdResults},
+	} {
+		jsonBytes, err := json.Marshal(field.src)
+		if err != nil {
+			return err
+		}
+		if err = field.dst.Set(jsonBytes); err != nil {
+			return err
+		}
+	}
+	if err = s.db.UpdateQueryScheduleRun(&run); err != nil {
+		return err
+	}
+	if err = s.db.PruneQueryScheduleRunRows(run.ScheduleID, QueryScheduleRunsWithRows); err != nil {
+		s.logger.Error("failed to prune query schedule run rows", zap.Uint("scheduleId", run.ScheduleID), zap.Error(err))
+	}
+
+	if triggered {
+		s.logger.Warn("query schedule threshold triggered", zap.Uint("scheduleId", schedule.ID),
+			zap.String("schedule", schedule.Name), zap.Uint("runId", run.ID))
+	}
+	return nil
+}
+
+func (s *JobScheduler) failQueryScheduleRun(run *model.QueryScheduleRun, message string) error {
+	run.Status = model.QueryScheduleRunFailed
+	run.FailureMessage = message
+	return s.db.UpdateQueryScheduleRun(run)
+}
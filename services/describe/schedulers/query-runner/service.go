@@ -21,6 +21,7 @@ const JobSchedulingInterval = 10 * time.Second
 
 type JobScheduler struct {
 	runSetupNatsStreams func(context.Context) error
+	isLeader            func(loop string) bool
 	conf                config.SchedulerConfig
 	logger              *zap.Logger
 	db                  db.Database
@@ -33,6 +34,7 @@ type JobScheduler struct {
 
 func New(
 	runSetupNatsStreams func(context.Context) error,
+	isLeader func(loop string) bool,
 	conf config.SchedulerConfig,
 	logger *zap.Logger,
 	db db.Database,
@@ -44,6 +46,7 @@ func New(
 ) *JobScheduler {
 	return &JobScheduler{
 		runSetupNatsStreams: runSetupNatsStreams,
+		isLeader:            isLeader,
 		conf:                conf,
 		logger:              logger,
 		db:                  db,
@@ -59,6 +62,9 @@ func (s *JobScheduler) Run(ctx context.Context) {
 	utils.EnsureRunGoroutine(func() {
 		s.RunPublisher(ctx)
 	})
+	utils.EnsureRunGoroutine(func() {
+		s.RunQuerySchedules(ctx)
+	})
 	utils.EnsureRunGoroutine(func() {
 		s.logger.Fatal("ComplianceReportJobResult consumer exited", zap.Error(s.RunQueryRunnerReportJobResultsConsumer(ctx)))
 	})
@@ -71,6 +77,9 @@ func (s *JobScheduler) RunPublisher(ctx context.Context) {
 	defer t.Stop()
 
 	for ; ; <-t.C {
+		if !s.isLeader("query-runner-publisher") {
+			continue
+		}
 		if err := s.runPublisher(ctx); err != nil {
 			s.logger.Error("failed to run compliance publisher", zap.Error(err))
 			continue
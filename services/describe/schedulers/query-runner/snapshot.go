@@ -0,0 +1,173 @@
+package query_runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/opengovern/opencomply/services/describe/api"
+)
+
+// querySnapshot is the result of a query schedule run
+type querySnapshot struct {
+	columns      []string
+	rows         [][]string
+	addedCount   int
+	removedCount int
+}
+
+// rowKeyFunc returns the function building the key of a row from the key columns, rows are keyed by all of their
+// values if there are no key columns
+func rowKeyFunc(columns, keyColumns []string) (func(row []string) string, error) {
+	if len(keyColumns) == 0 {
+		return func(row []string) string {
+			return strings.Join(row, "\x1f")
+		}, nil
+	}
+
+	indexes := make([]int, 0, len(keyColumns))
+	for _, keyColumn := range keyColumns {
+		idx := columnIndex(columns, keyColumn)
+		if idx < 0 {
+			return nil, fmt.Errorf("key column %s is not in the result", keyColumn)
+		}
+		indexes = append(indexes, idx)
+	}
+	return func(row []string) string {
+		values := make([]string, 0, len(indexes))
+		for _, idx := range indexes {
+			if idx < len(row) {
+				values = append(values, row[idx])
+			} else {
+				values = append(values, "")
+			}
+		}
+		return strings.Join(values, "\x1f")
+	}, nil
+}
+
+// diffRows returns the current rows whose key is not in the previous rows and the previous rows whose key
+// is not in the current rows
+func diffRows(previousKey, currentKey func(row []string) string, previous, current [][]string) (added, removed [][]string) {
+	previousKeys := make(map[string]struct{}, len(previous))
+	for _, row := range previous {
+		previousKeys[previousKey(row)] = struct{}{}
+	}
+	currentKeys := make(map[string]struct{}, len(current))
+	for _, row := range current {
+		currentKeys[currentKey(row)] = struct{}{}
+	}
+
+	added, removed = make([][]string, 0), make([][]string, 0)
+	for _, row := range current {
+		if _, ok := previousKeys[currentKey(row)]; !ok {
+			added = append(added, row)
+		}
+	}
+	for _, row := range previous {
+		if _, ok := currentKeys[previousKey(row)]; !ok {
+			removed = append(removed, row)
+		}
+	}
+	return added, removed
+}
+
+func (s querySnapshot) metric(threshold api.QueryScheduleThreshold) (float64, error) {
+	switch threshold.Metric {
+	case api.QueryScheduleThresholdMetricRowCount:
+		return float64(len(s.rows)), nil
+	case api.QueryScheduleThresholdMetricAddedCount:
+		return float64(s.addedCount), nil
+	case api.QueryScheduleThresholdMetricRemovedCount:
+		return float64(s.removedCount), nil
+	case api.QueryScheduleThresholdMetricValue:
+		idx := columnIndex(s.columns, threshold.Column)
+		if idx < 0 {
+			return 0, fmt.Errorf("column %s is not in the result", threshold.Column)
+		}
+		var sum float64
+		for _, row := range s.rows {
+			if idx >= len(row) || row[idx] == "" || row[idx] == "<nil>" {
+				continue
+			}
+			v, err := strconv.ParseFloat(row[idx], 64)
+			if err != nil {
+				return 0, fmt.Errorf("column %s has non numeric value %q", threshold.Column, row[idx])
+			}
+			sum += v
+		}
+		return sum, nil
+	default:
+		return 0, fmt.Errorf("invalid metric %s", threshold.Metric)
+	}
+}
+
+// evaluateThresholds evaluates the thresholds against the current run, previous is nil for the baseline run in
+// which case the thresholds comparing with the previous run are not triggered
+func evaluateThresholds(thresholds []api.QueryScheduleThreshold, current querySnapshot, previous *querySnapshot) ([]api.QueryScheduleThresholdResult, bool) {
+	results := make([]api.QueryScheduleThresholdResult, 0, len(thresholds))
+	anyTriggered := false
+	for _, threshold := range thresholds {
+		result := api.QueryScheduleThresholdResult{QueryScheduleThreshold: threshold}
+		triggered, err := evaluateThreshold(threshold, current, previous, &result)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		result.Triggered = triggered
+		anyTriggered = anyTriggered || triggered
+		results = append(results, result)
+	}
+	return results, anyTriggered
+}
+
+func evaluateThreshold(threshold api.QueryScheduleThreshold, current querySnapshot, previous *querySnapshot, result *api.QueryScheduleThresholdResult) (bool, error) {
+	value, err := current.metric(threshold)
+	if err != nil {
+		return false, err
+	}
+	result.Current = &value
+
+	switch threshold.Operator {
+	case api.QueryScheduleThresholdOperatorGreaterThan:
+		return value > threshold.Value, nil
+	case api.QueryScheduleThresholdOperatorGreaterThanOrEqual:
+		return value >= threshold.Value, nil
+	case api.QueryScheduleThresholdOperatorLessThan:
+		return value < threshold.Value, nil
+	case api.QueryScheduleThresholdOperatorLessThanOrEqual:
+		return value <= threshold.Value, nil
+	case api.QueryScheduleThresholdOperatorEqual:
+		return value == threshold.Value, nil
+	case api.QueryScheduleThresholdOperatorNotEqual:
+		return value != threshold.Value, nil
+	}
+
+	if previous == nil {
+		return false, nil
+	}
+	previousValue, err := previous.metric(threshold)
+	if err != nil {
+		return false, fmt.Errorf("previous run: %w", err)
+	}
+	result.Previous = &previousValue
+
+	switch threshold.Operator {
+	case api.QueryScheduleThresholdOperatorIncreased:
+		return value > previousValue, nil
+	case api.QueryScheduleThresholdOperatorDecreased:
+		return value < previousValue, nil
+	case api.QueryScheduleThresholdOperatorChanged:
+		return value != previousValue, nil
+	default:
+		return false, fmt.Errorf("invalid operator %s", threshold.Operator)
+	}
+}
+
+func columnIndex(columns []string, column string) int {
+	for i, c := range columns {
+		if c == column {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,249 @@
+package query_runner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opengovern/opencomply/services/describe/api"
+)
+
+func TestDiffRows(t *testing.T) {
+	tests := []struct {
+		name            string
+		previousColumns []string
+		currentColumns  []string
+		keyColumns      []string
+		previous        [][]string
+		current         [][]string
+		wantAdded       [][]string
+		wantRemoved     [][]string
+		wantErr         bool
+	}{
+		{
+			name:           "baseline run",
+			currentColumns: []string{"id", "name"},
+			current:        [][]string{{"i-1", "web"}},
+			wantAdded:      [][]string{{"i-1", "web"}},
+			wantRemoved:    [][]string{},
+		},
+		{
+			name:            "rows keyed by all values",
+			previousColumns: []string{"id", "name"},
+			currentColumns:  []string{"id", "name"},
+			previous:        [][]string{{"i-1", "web"}, {"i-2", "db"}},
+			current:         [][]string{{"i-1", "web"}, {"i-2", "database"}},
+			wantAdded:       [][]string{{"i-2", "database"}},
+			wantRemoved:     [][]string{{"i-2", "db"}},
+		},
+		{
+			name:            "changed values of a key are not a diff",
+			previousColumns: []string{"id", "name"},
+			currentColumns:  []string{"id", "name"},
+			keyColumns:      []string{"id"},
+			previous:        [][]string{{"i-1", "web"}, {"i-2", "db"}},
+			current:         [][]string{{"i-2", "database"}, {"i-3", "cache"}},
+			wantAdded:       [][]string{{"i-3", "cache"}},
+			wantRemoved:     [][]string{{"i-1", "web"}},
+		},
+		{
+			name:            "key columns moved between runs",
+			previousColumns: []string{"region", "id"},
+			currentColumns:  []string{"id", "state", "region"},
+			keyColumns:      []string{"region", "id"},
+			previous:        [][]string{{"eu-west-1", "i-1"}, {"us-east-1", "i-1"}},
+			current:         [][]string{{"i-1", "running", "eu-west-1"}},
+			wantAdded:       [][]string{},
+			wantRemoved:     [][]string{{"us-east-1", "i-1"}},
+		},
+		{
+			name:            "unchanged rows",
+			previousColumns: []string{"id"},
+			currentColumns:  []string{"id"},
+			keyColumns:      []string{"id"},
+			previous:        [][]string{{"i-1"}, {"i-2"}},
+			current:         [][]string{{"i-2"}, {"i-1"}},
+			wantAdded:       [][]string{},
+			wantRemoved:     [][]string{},
+		},
+		{
+			name:           "missing key column",
+			currentColumns: []string{"name"},
+			keyColumns:     []string{"id"},
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentKey, err := rowKeyFunc(tt.currentColumns, tt.keyColumns)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error for the missing key column")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("current key: %v", err)
+			}
+			previousKey := currentKey
+			if tt.previousColumns != nil {
+				if previousKey, err = rowKeyFunc(tt.previousColumns, tt.keyColumns); err != nil {
+					t.Fatalf("previous key: %v", err)
+				}
+			}
+
+			added, removed := diffRows(previousKey, currentKey, tt.previous, tt.current)
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("added %v, want %v", added, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestEvaluateThresholds(t *testing.T) {
+	current := querySnapshot{
+		columns:      []string{"bucket", "size_gb"},
+		rows:         [][]string{{"logs", "120.5"}, {"backups", "80"}, {"tmp", ""}},
+		addedCount:   2,
+		removedCount: 0,
+	}
+	previous := &querySnapshot{
+		columns:      []string{"bucket", "size_gb"},
+		rows:         [][]string{{"logs", "100"}, {"old", "3"}},
+		addedCount:   0,
+		removedCount: 1,
+	}
+
+	tests := []struct {
+		name          string
+		threshold     api.QueryScheduleThreshold
+		previous      *querySnapshot
+		wantTriggered bool
+		wantCurrent   *float64
+		wantPrevious  *float64
+		wantError     bool
+	}{
+		{
+			name:          "row count above",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricRowCount, Operator: api.QueryScheduleThresholdOperatorGreaterThan, Value: 2},
+			wantTriggered: true,
+			wantCurrent:   float64p(3),
+		},
+		{
+			name:          "row count not below",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricRowCount, Operator: api.QueryScheduleThresholdOperatorLessThan, Value: 3},
+			wantTriggered: false,
+			wantCurrent:   float64p(3),
+		},
+		{
+			name:          "added rows",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricAddedCount, Operator: api.QueryScheduleThresholdOperatorGreaterThanOrEqual, Value: 2},
+			wantTriggered: true,
+			wantCurrent:   float64p(2),
+		},
+		{
+			name:          "no removed rows",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricRemovedCount, Operator: api.QueryScheduleThresholdOperatorNotEqual, Value: 0},
+			wantTriggered: false,
+			wantCurrent:   float64p(0),
+		},
+		{
+			name:          "column sum skips empty values",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricValue, Column: "size_gb", Operator: api.QueryScheduleThresholdOperatorEqual, Value: 200.5},
+			wantTriggered: true,
+			wantCurrent:   float64p(200.5),
+		},
+		{
+			name:      "unknown column",
+			threshold: api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricValue, Column: "cost", Operator: api.QueryScheduleThresholdOperatorGreaterThan},
+			wantError: true,
+		},
+		{
+			name:      "non numeric column",
+			threshold: api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricValue, Column: "bucket", Operator: api.QueryScheduleThresholdOperatorGreaterThan},
+			wantError: true,
+		},
+		{
+			name:          "increased since the previous run",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricValue, Column: "size_gb", Operator: api.QueryScheduleThresholdOperatorIncreased},
+			previous:      previous,
+			wantTriggered: true,
+			wantCurrent:   float64p(200.5),
+			wantPrevious:  float64p(103),
+		},
+		{
+			name:          "not decreased since the previous run",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricRowCount, Operator: api.QueryScheduleThresholdOperatorDecreased},
+			previous:      previous,
+			wantTriggered: false,
+			wantCurrent:   float64p(3),
+			wantPrevious:  float64p(2),
+		},
+		{
+			name:          "changed since the previous run",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricRemovedCount, Operator: api.QueryScheduleThresholdOperatorChanged},
+			previous:      previous,
+			wantTriggered: true,
+			wantCurrent:   float64p(0),
+			wantPrevious:  float64p(1),
+		},
+		{
+			name:          "baseline run does not compare",
+			threshold:     api.QueryScheduleThreshold{Metric: api.QueryScheduleThresholdMetricRowCount, Operator: api.QueryScheduleThresholdOperatorChanged},
+			wantTriggered: false,
+			wantCurrent:   float64p(3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, anyTriggered := evaluateThresholds([]api.QueryScheduleThreshold{tt.threshold}, current, tt.previous)
+			if len(results) != 1 {
+				t.Fatalf("expected one result, got %d", len(results))
+			}
+			result := results[0]
+			if result.Triggered != tt.wantTriggered || anyTriggered != tt.wantTriggered {
+				t.Errorf("triggered %v (any %v), want %v", result.Triggered, anyTriggered, tt.wantTriggered)
+			}
+			if (result.Error != "") != tt.wantError {
+				t.Errorf("error %q, want error %v", result.Error, tt.wantError)
+			}
+			if !reflect.DeepEqual(result.Current, tt.wantCurrent) {
+				t.Errorf("current %v, want %v", deref(result.Current), deref(tt.wantCurrent))
+			}
+			if !reflect.DeepEqual(result.Previous, tt.wantPrevious) {
+				t.Errorf("previous %v, want %v", deref(result.Previous), deref(tt.wantPrevious))
+			}
+		})
+	}
+}
+
+func TestEvaluateThresholdsAnyTriggered(t *testing.T) {
+	current := querySnapshot{columns: []string{"id"}, rows: [][]string{{"a"}, {"b"}}}
+	thresholds := []api.QueryScheduleThreshold{
+		{Name: "empty", Metric: api.QueryScheduleThresholdMetricRowCount, Operator: api.QueryScheduleThresholdOperatorEqual, Value: 0},
+		{Name: "too many", Metric: api.QueryScheduleThresholdMetricRowCount, Operator: api.QueryScheduleThresholdOperatorGreaterThan, Value: 1},
+	}
+
+	results, anyTriggered := evaluateThresholds(thresholds, current, nil)
+	if !anyTriggered {
+		t.Error("expected a triggered threshold")
+	}
+	if len(results) != 2 || results[0].Name != "empty" || results[0].Triggered || results[1].Name != "too many" || !results[1].Triggered {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func float64p(v float64) *float64 {
+	return &v
+}
+
+func deref(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,233 @@
+package relationship
+
+import (
+	"context"
+	"time"
+
+	authApi "github.com/opengovern/og-util/pkg/api"
+	es2 "github.com/opengovern/og-util/pkg/es"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/og-util/pkg/ticker"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/describe/db/model"
+	"github.com/opengovern/opencomply/services/describe/es"
+	"go.uber.org/zap"
+)
+
+const (
+	ExtractorInterval = 1 * time.Minute
+
+	extractionBatchSize = 50
+	resourcePageSize    = 1000
+	lookupBatchSize     = 1000
+)
+
+// RunExtractor extracts the relationships of the resources of every integration and resource type once their
+// describe job has succeeded
+func (s *Scheduler) RunExtractor(ctx context.Context) {
+	s.logger.Info("Scheduling relationship extractor on a timer")
+
+	t := ticker.NewTicker(ExtractorInterval, time.Second*10)
+	defer t.Stop()
+
+	for ; ; <-t.C {
+		if !s.isLeader("resource-relationship-extractor") {
+			continue
+		}
+		if err := s.runExtractor(ctx); err != nil {
+			s.logger.Error("failed to run relationship extractor", zap.Error(err))
+			continue
+		}
+	}
+}
+
+func (s *Scheduler) runExtractor(ctx context.Context) error {
+	jobs, err := s.db.ListDescribeJobsPendingRelationshipExtraction(ResourceTypesWithRules(), extractionBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		extractedAt := time.Now()
+		count, err := s.extract(ctx, job.IntegrationID, job.ResourceType, extractedAt.UnixMilli())
+		if err != nil {
+			s.logger.Error("failed to extract relationships", zap.Uint("jobId", job.ID),
+				zap.String("integration_id", job.IntegrationID), zap.String("resource_type", job.ResourceType), zap.Error(err))
+			continue
+		}
+
+		err = s.db.UpsertResourceRelationshipExtraction(&model.ResourceRelationshipExtraction{
+			IntegrationID: job.IntegrationID,
+			ResourceType:  job.ResourceType,
+			DescribeJobID: job.ID,
+			EdgeCount:     count,
+			ExtractedAt:   extractedAt,
+		})
+		if err != nil {
+			return err
+		}
+		s.logger.Info("extracted relationships", zap.Uint("jobId", job.ID), zap.String("integration_id", job.IntegrationID),
+			zap.String("resource_type", job.ResourceType), zap.Int64("count", count))
+	}
+	return nil
+}
+
+type candidate struct {
+	rule     Rule
+	resource es2.Resource
+	value    string
+}
+
+// extract replaces the edges derived from the resources of a resource type in an integration and returns
+// the number of edges found
+func (s *Scheduler) extract(ctx context.Context, integrationID, resourceType string, extractedAt int64) (int64, error) {
+	rules := RulesFor(resourceType)
+
+	var count int64
+	var searchAfter []any
+	for {
+		resp, err := es.GetResourcesForIntegrationResourceTypeFromES(ctx, s.esClient, integrationID, resourceType, searchAfter, resourcePageSize)
+		if err != nil {
+			return 0, err
+		}
+		if len(resp.Hits.Hits) == 0 {
+			break
+		}
+
+		var candidates []candidate
+		for _, hit := range resp.Hits.Hits {
+			searchAfter = hit.Sort
+			for _, rule := range rules {
+				for _, value := range rule.Values(hit.Source.Description) {
+					candidates = append(candidates, candidate{rule: rule, resource: hit.Source, value: value})
+				}
+			}
+		}
+
+		edges, err := s.resolve(ctx, candidates, integrationID, resourceType, extractedAt)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.ingest(edges); err != nil {
+			return 0, err
+		}
+		count += int64(len(edges))
+	}
+
+	if err := es.DeleteStaleResourceRelationships(ctx, s.esClient, integrationID, resourceType, extractedAt); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// resolve matches the candidate values against the lookup resources of all integrations, values without a
+// matching resource do not produce an edge
+func (s *Scheduler) resolve(ctx context.Context, candidates []candidate, integrationID, resourceType string, extractedAt int64) ([]es2.Doc, error) {
+	type lookupKey struct {
+		field        string
+		resourceType string
+	}
+	values := make(map[lookupKey]map[string]bool)
+	for _, c := range candidates {
+		k := lookupKey{field: c.rule.targetField(), resourceType: c.rule.TargetResourceType}
+		if _, ok := values[k]; !ok {
+			values[k] = make(map[string]bool)
+		}
+		values[k][c.value] = true
+	}
+
+	type matchKey struct {
+		lookupKey
+		value string
+	}
+	matches := make(map[matchKey][]es2.LookupResource)
+	for k, vs := range values {
+		batch := make([]string, 0, lookupBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			resources, err := es.GetLookupResourcesByField(ctx, s.esClient, k.field, batch, k.resourceType)
+			if err != nil {
+				return err
+			}
+			for _, r := range resources {
+				value := r.ResourceID
+				if k.field == "resource_name" {
+					value = r.ResourceName
+				}
+				mk := matchKey{lookupKey: k, value: value}
+				matches[mk] = append(matches[mk], r)
+			}
+			batch = batch[:0]
+			return nil
+		}
+		for v := range vs {
+			batch = append(batch, v)
+			if len(batch) == lookupBatchSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[string]bool)
+	var docs []es2.Doc
+	for _, c := range candidates {
+		k := matchKey{lookupKey: lookupKey{field: c.rule.targetField(), resourceType: c.rule.TargetResourceType}, value: c.value}
+		for _, target := range matches[k] {
+			if target.PlatformID == c.resource.PlatformID {
+				continue
+			}
+			edge := types.ResourceRelationship{
+				Relationship: c.rule.Relationship,
+				Source: types.ResourceRelationshipEndpoint{
+					PlatformID:      c.resource.PlatformID,
+					ResourceID:      c.resource.ResourceID,
+					ResourceName:    c.resource.ResourceName,
+					ResourceType:    c.resource.ResourceType,
+					IntegrationID:   c.resource.IntegrationID,
+					IntegrationType: c.resource.IntegrationType.String(),
+				},
+				Target: types.ResourceRelationshipEndpoint{
+					PlatformID:      target.PlatformID,
+					ResourceID:      target.ResourceID,
+					ResourceName:    target.ResourceName,
+					ResourceType:    target.ResourceType,
+					IntegrationID:   target.IntegrationID,
+					IntegrationType: target.IntegrationType.String(),
+				},
+				ExtractedFromIntegrationID: integrationID,
+				ExtractedFromResourceType:  resourceType,
+				ExtractedAt:                extractedAt,
+			}
+			if c.rule.Reverse {
+				edge.Source, edge.Target = edge.Target, edge.Source
+			}
+
+			keys, idx := edge.KeysAndIndex()
+			edge.EsID = es2.HashOf(keys...)
+			edge.EsIndex = idx
+			if seen[edge.EsID] {
+				continue
+			}
+			seen[edge.EsID] = true
+			docs = append(docs, edge)
+		}
+	}
+	return docs, nil
+}
+
+func (s *Scheduler) ingest(docs []es2.Doc) error {
+	for start := 0; start < len(docs); start += resourcePageSize {
+		end := min(start+resourcePageSize, len(docs))
+		if _, err := s.sinkClient.Ingest(&httpclient.Context{UserRole: authApi.AdminRole}, docs[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
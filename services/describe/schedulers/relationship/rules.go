@@ -0,0 +1,156 @@
+package relationship
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+// Rule derives edges from the description of a resource. The values found at Path are matched against the
+// TargetField of the lookup resources, of any integration, to find the other end of the edge.
+type Rule struct {
+	Relationship types.ResourceRelationshipType
+	// Path is the dot separated path of the values in the description, arrays are flattened on the way
+	Path string
+	// Pattern optionally extracts the identifiers from the values with its first capture group,
+	// values which are not strings are matched in their JSON form
+	Pattern *regexp.Regexp
+	// TargetResourceType narrows the matched resources, any resource type matches if empty
+	TargetResourceType string
+	// TargetField is the lookup resource field holding the values, resource_id if empty
+	TargetField string
+	// Reverse makes the matched resource the source of the edge, for edges described on their target
+	Reverse bool
+}
+
+var githubOIDCSubjectPattern = regexp.MustCompile(`repo:([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)`)
+
+// Rules are the relationship extraction rules of each resource type
+var Rules = map[string][]Rule{
+	"AWS::EC2::Instance": {
+		{Relationship: types.ResourceRelationshipAttachedTo, Path: "Instance.SubnetId", TargetResourceType: "AWS::EC2::Subnet"},
+		{Relationship: types.ResourceRelationshipAttachedTo, Path: "Instance.SecurityGroups.GroupId", TargetResourceType: "AWS::EC2::SecurityGroup"},
+		{Relationship: types.ResourceRelationshipAttachedTo, Path: "Instance.BlockDeviceMappings.Ebs.VolumeId", TargetResourceType: "AWS::EC2::Volume"},
+		// the instance profile wraps the role of the instance, the role itself is not named by the instance
+		{Relationship: types.ResourceRelationshipAttachedTo, Path: "Instance.IamInstanceProfile.Arn"},
+	},
+	"AWS::EC2::Subnet": {
+		{Relationship: types.ResourceRelationshipContains, Path: "Subnet.VpcId", TargetResourceType: "AWS::EC2::VPC", Reverse: true},
+	},
+	"AWS::EC2::SecurityGroup": {
+		{Relationship: types.ResourceRelationshipContains, Path: "SecurityGroup.VpcId", TargetResourceType: "AWS::EC2::VPC", Reverse: true},
+	},
+	"AWS::ElasticLoadBalancingV2::LoadBalancer": {
+		{Relationship: types.ResourceRelationshipAttachedTo, Path: "LoadBalancer.SecurityGroups", TargetResourceType: "AWS::EC2::SecurityGroup"},
+		{Relationship: types.ResourceRelationshipContains, Path: "LoadBalancer.VpcId", TargetResourceType: "AWS::EC2::VPC", Reverse: true},
+	},
+	"AWS::ElasticLoadBalancingV2::TargetGroup": {
+		{Relationship: types.ResourceRelationshipRoutesTo, Path: "TargetGroup.LoadBalancerArns", TargetResourceType: "AWS::ElasticLoadBalancingV2::LoadBalancer", Reverse: true},
+		{Relationship: types.ResourceRelationshipRoutesTo, Path: "Health.Target.Id", TargetResourceType: "AWS::EC2::Instance"},
+	},
+	"AWS::Lambda::Function": {
+		{Relationship: types.ResourceRelationshipAssumesRole, Path: "Function.Configuration.Role", TargetResourceType: "AWS::IAM::Role"},
+	},
+	"AWS::ECS::TaskDefinition": {
+		{Relationship: types.ResourceRelationshipAssumesRole, Path: "TaskDefinition.TaskRoleArn", TargetResourceType: "AWS::IAM::Role"},
+	},
+	"AWS::IAM::Role": {
+		// GitHub Actions deploy to the account through the roles trusting the repository OIDC subject
+		{Relationship: types.ResourceRelationshipDeploysTo, Path: "AssumeRolePolicyDocument", Pattern: githubOIDCSubjectPattern,
+			TargetResourceType: "Github/Repository", TargetField: "resource_name", Reverse: true},
+	},
+	"Microsoft.Compute/virtualMachines": {
+		{Relationship: types.ResourceRelationshipAttachedTo, Path: "VirtualMachine.Properties.NetworkProfile.NetworkInterfaces.ID", TargetResourceType: "Microsoft.Network/networkInterfaces"},
+		{Relationship: types.ResourceRelationshipAttachedTo, Path: "VirtualMachine.Properties.StorageProfile.DataDisks.ManagedDisk.ID", TargetResourceType: "Microsoft.Compute/disks"},
+	},
+}
+
+var rulesByResourceType = func() map[string][]Rule {
+	rules := make(map[string][]Rule)
+	for resourceType, r := range Rules {
+		rules[strings.ToLower(resourceType)] = r
+	}
+	return rules
+}()
+
+// RulesFor returns the rules of a resource type, resource types are matched case insensitively
+func RulesFor(resourceType string) []Rule {
+	return rulesByResourceType[strings.ToLower(resourceType)]
+}
+
+// ResourceTypesWithRules returns the lower case resource types having at least one rule
+func ResourceTypesWithRules() []string {
+	resourceTypes := make([]string, 0, len(rulesByResourceType))
+	for resourceType := range rulesByResourceType {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	return resourceTypes
+}
+
+func (r Rule) targetField() string {
+	if r.TargetField == "" {
+		return "resource_id"
+	}
+	return r.TargetField
+}
+
+// Values returns the distinct identifiers the rule finds in a resource description
+func (r Rule) Values(description any) []string {
+	seen := make(map[string]bool)
+	var values []string
+	add := func(v string) {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			return
+		}
+		seen[v] = true
+		values = append(values, v)
+	}
+
+	for _, v := range valuesAt(description, strings.Split(r.Path, ".")) {
+		if r.Pattern == nil {
+			if s, ok := v.(string); ok {
+				add(s)
+			}
+			continue
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			b, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			s = string(b)
+		}
+		for _, match := range r.Pattern.FindAllStringSubmatch(s, -1) {
+			if len(match) > 1 {
+				add(match[1])
+			}
+		}
+	}
+	return values
+}
+
+func valuesAt(v any, path []string) []any {
+	if list, ok := v.([]any); ok {
+		var values []any
+		for _, item := range list {
+			values = append(values, valuesAt(item, path)...)
+		}
+		return values
+	}
+	if len(path) == 0 {
+		if v == nil {
+			return nil
+		}
+		return []any{v}
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return valuesAt(m[path[0]], path[1:])
+}
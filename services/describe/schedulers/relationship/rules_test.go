@@ -0,0 +1,174 @@
+package relationship
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/opengovern/opencomply/pkg/types"
+)
+
+const instanceDescription = `{
+	"Instance": {
+		"InstanceId": "i-0abc",
+		"SubnetId": "subnet-1",
+		"SecurityGroups": [
+			{"GroupId": "sg-1", "GroupName": "web"},
+			{"GroupId": "sg-2", "GroupName": "ssh"},
+			{"GroupId": "sg-1", "GroupName": "web-duplicate"}
+		],
+		"BlockDeviceMappings": [
+			{"DeviceName": "/dev/xvda", "Ebs": {"VolumeId": " vol-1 "}},
+			{"DeviceName": "/dev/xvdb", "Ebs": null},
+			{"DeviceName": "/dev/xvdc", "Ebs": {"VolumeId": ""}}
+		],
+		"CpuOptions": {"CoreCount": 2},
+		"Tags": [[{"Key": "team"}], [{"Key": "env"}]]
+	}
+}`
+
+const roleDescription = `{
+	"Role": {"Arn": "arn:aws:iam::123456789012:role/deploy"},
+	"AssumeRolePolicyDocument": {
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/token.actions.githubusercontent.com"},
+			"Condition": {"StringLike": {"token.actions.githubusercontent.com:sub": [
+				"repo:acme/api:ref:refs/heads/main",
+				"repo:acme/web:*",
+				"repo:acme/api:environment:prod"
+			]}}
+		}]
+	}
+}`
+
+func decode(t *testing.T, description string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(description), &v); err != nil {
+		t.Fatalf("invalid description: %v", err)
+	}
+	return v
+}
+
+func TestRuleValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		rule        Rule
+		want        []string
+	}{
+		{
+			name:        "single value",
+			description: instanceDescription,
+			rule:        Rule{Path: "Instance.SubnetId"},
+			want:        []string{"subnet-1"},
+		},
+		{
+			name:        "arrays are flattened and values deduplicated",
+			description: instanceDescription,
+			rule:        Rule{Path: "Instance.SecurityGroups.GroupId"},
+			want:        []string{"sg-1", "sg-2"},
+		},
+		{
+			name:        "null and empty values are skipped and values trimmed",
+			description: instanceDescription,
+			rule:        Rule{Path: "Instance.BlockDeviceMappings.Ebs.VolumeId"},
+			want:        []string{"vol-1"},
+		},
+		{
+			name:        "nested arrays",
+			description: instanceDescription,
+			rule:        Rule{Path: "Instance.Tags.Key"},
+			want:        []string{"team", "env"},
+		},
+		{
+			name:        "values which are not strings are ignored without a pattern",
+			description: instanceDescription,
+			rule:        Rule{Path: "Instance.CpuOptions"},
+			want:        nil,
+		},
+		{
+			name:        "missing path",
+			description: instanceDescription,
+			rule:        Rule{Path: "Instance.IamInstanceProfile.Arn"},
+			want:        nil,
+		},
+		{
+			name:        "path through a value",
+			description: instanceDescription,
+			rule:        Rule{Path: "Instance.SubnetId.Id"},
+			want:        nil,
+		},
+		{
+			name:        "pattern on a string",
+			description: roleDescription,
+			rule:        Rule{Path: "Role.Arn", Pattern: regexp.MustCompile(`:role/(.+)$`)},
+			want:        []string{"deploy"},
+		},
+		{
+			name:        "pattern on the json of an object",
+			description: roleDescription,
+			rule: Rule{Relationship: types.ResourceRelationshipDeploysTo, Path: "AssumeRolePolicyDocument",
+				Pattern: githubOIDCSubjectPattern},
+			want: []string{"acme/api", "acme/web"},
+		},
+		{
+			name:        "pattern without a capture group",
+			description: roleDescription,
+			rule:        Rule{Path: "Role.Arn", Pattern: regexp.MustCompile(`role`)},
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.Values(decode(t, tt.description))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Values(%s) = %q, want %q", tt.rule.Path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValuesAt(t *testing.T) {
+	description := decode(t, `{"a": [{"b": 1}, {"b": [2, 3]}, {"c": 4}, {"b": null}]}`)
+
+	tests := []struct {
+		path []string
+		want []any
+	}{
+		{path: []string{"a", "b"}, want: []any{float64(1), float64(2), float64(3)}},
+		{path: []string{"a", "c"}, want: []any{float64(4)}},
+		{path: []string{"a", "d"}, want: nil},
+		{path: []string{"x"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := valuesAt(description, tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("valuesAt(%v) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRulesFor(t *testing.T) {
+	if len(RulesFor("aws::ec2::instance")) == 0 {
+		t.Error("expected the rules of AWS::EC2::Instance regardless of the case")
+	}
+	if len(RulesFor("AWS::Unknown::Resource")) != 0 {
+		t.Error("expected no rules for an unknown resource type")
+	}
+	for _, resourceType := range ResourceTypesWithRules() {
+		for _, rule := range RulesFor(resourceType) {
+			if rule.Path == "" {
+				t.Errorf("%s: rule %s has no path", resourceType, rule.Relationship)
+			}
+			// roles are assumed by their source, the edge has to end on a role
+			if rule.Relationship == types.ResourceRelationshipAssumesRole && !rule.Reverse &&
+				rule.TargetResourceType != "AWS::IAM::Role" {
+				t.Errorf("%s: assumes-role rule %s does not target a role", resourceType, rule.Path)
+			}
+		}
+	}
+}
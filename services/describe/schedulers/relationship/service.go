@@ -0,0 +1,35 @@
+package relationship
+
+import (
+	"context"
+
+	esSinkClient "github.com/opengovern/og-util/pkg/es/ingest/client"
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/utils"
+	"github.com/opengovern/opencomply/services/describe/db"
+	"go.uber.org/zap"
+)
+
+type Scheduler struct {
+	isLeader   func(loop string) bool
+	logger     *zap.Logger
+	db         db.Database
+	esClient   opengovernance.Client
+	sinkClient esSinkClient.EsSinkServiceClient
+}
+
+func New(isLeader func(loop string) bool, logger *zap.Logger, db db.Database, esClient opengovernance.Client, sinkClient esSinkClient.EsSinkServiceClient) *Scheduler {
+	return &Scheduler{
+		isLeader:   isLeader,
+		logger:     logger.Named("relationship-extractor"),
+		db:         db,
+		esClient:   esClient,
+		sinkClient: sinkClient,
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	utils.EnsureRunGoroutine(func() {
+		s.RunExtractor(ctx)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/opengovern/opencomply/pkg/audit"
 	"github.com/opengovern/opencomply/pkg/types"
 	"golang.org/x/net/context"
 	"net/http"
@@ -34,16 +35,18 @@ import (
 	"github.com/opengovern/opencomply/services/describe/db"
 	model2 "github.com/opengovern/opencomply/services/describe/db/model"
 	"github.com/opengovern/opencomply/services/describe/es"
+	queryrunnerscheduler "github.com/opengovern/opencomply/services/describe/schedulers/query-runner"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type HttpServer struct {
-	Address    string
-	DB         db.Database
-	Scheduler  *Scheduler
-	kubeClient k8sclient.Client
+	Address       string
+	DB            db.Database
+	Scheduler     *Scheduler
+	kubeClient    k8sclient.Client
+	auditRecorder *audit.Recorder
 }
 
 func NewHTTPServer(
@@ -52,22 +55,25 @@ func NewHTTPServer(
 	s *Scheduler,
 ) *HttpServer {
 	return &HttpServer{
-		Address:   address,
-		DB:        db,
-		Scheduler: s,
+		Address:       address,
+		DB:            db,
+		Scheduler:     s,
+		auditRecorder: audit.NewRecorder(s.logger, "scheduler", EsSinkBaseURL),
 	}
 }
 
 func (h HttpServer) Register(e *echo.Echo) {
+	auditLog := h.auditRecorder.Middleware()
+
 	v1 := e.Group("/api/v1")
 
-	v1.PUT("/describe/trigger/:connection_id", httpserver.AuthorizeHandler(h.TriggerPerConnectionDescribeJob, apiAuth.AdminRole))
-	v1.PUT("/describe/trigger", httpserver.AuthorizeHandler(h.TriggerDescribeJob, apiAuth.AdminRole))
-	v1.PUT("/compliance/trigger", httpserver.AuthorizeHandler(h.TriggerConnectionsComplianceJobs, apiAuth.AdminRole))
-	v1.PUT("/compliance/trigger/:benchmark_id", httpserver.AuthorizeHandler(h.TriggerConnectionsComplianceJob, apiAuth.AdminRole))
-	v1.PUT("/compliance/trigger/:benchmark_id/summary", httpserver.AuthorizeHandler(h.TriggerConnectionsComplianceJobSummary, apiAuth.AdminRole))
+	v1.PUT("/describe/trigger/:connection_id", httpserver.AuthorizeHandler(h.TriggerPerConnectionDescribeJob, apiAuth.AdminRole), auditLog)
+	v1.PUT("/describe/trigger", httpserver.AuthorizeHandler(h.TriggerDescribeJob, apiAuth.AdminRole), auditLog)
+	v1.PUT("/compliance/trigger", httpserver.AuthorizeHandler(h.TriggerConnectionsComplianceJobs, apiAuth.AdminRole), auditLog)
+	v1.PUT("/compliance/trigger/:benchmark_id", httpserver.AuthorizeHandler(h.TriggerConnectionsComplianceJob, apiAuth.AdminRole), auditLog)
+	v1.PUT("/compliance/trigger/:benchmark_id/summary", httpserver.AuthorizeHandler(h.TriggerConnectionsComplianceJobSummary, apiAuth.AdminRole), auditLog)
 	v1.GET("/compliance/re-evaluate/:benchmark_id", httpserver.AuthorizeHandler(h.CheckReEvaluateComplianceJob, apiAuth.AdminRole))
-	v1.PUT("/compliance/re-evaluate/:benchmark_id", httpserver.AuthorizeHandler(h.ReEvaluateComplianceJob, apiAuth.AdminRole))
+	v1.PUT("/compliance/re-evaluate/:benchmark_id", httpserver.AuthorizeHandler(h.ReEvaluateComplianceJob, apiAuth.AdminRole), auditLog)
 	v1.GET("/compliance/status/:benchmark_id", httpserver.AuthorizeHandler(h.GetComplianceBenchmarkStatus, apiAuth.ViewerRole))
 	v1.GET("/describe/status/:resource_type", httpserver.AuthorizeHandler(h.GetDescribeStatus, apiAuth.ViewerRole))
 	v1.GET("/describe/connection/status", httpserver.AuthorizeHandler(h.GetConnectionDescribeStatus, apiAuth.ViewerRole))
@@ -83,12 +89,26 @@ func (h HttpServer) Register(e *echo.Echo) {
 	v3.POST("/jobs/discovery/connections", httpserver.AuthorizeHandler(h.GetDescribeJobsHistoryByIntegration, apiAuth.ViewerRole))
 	v3.POST("/jobs/compliance/connections", httpserver.AuthorizeHandler(h.GetComplianceJobsHistoryByIntegration, apiAuth.ViewerRole))
 
-	v3.POST("/compliance/benchmark/:benchmark_id/run", httpserver.AuthorizeHandler(h.RunBenchmarkById, apiAuth.AdminRole))
-	v3.POST("/compliance/run", httpserver.AuthorizeHandler(h.RunBenchmark, apiAuth.AdminRole))
-	v3.POST("/discovery/run", httpserver.AuthorizeHandler(h.RunDiscovery, apiAuth.AdminRole))
+	v3.POST("/compliance/benchmark/:benchmark_id/run", httpserver.AuthorizeHandler(h.RunBenchmarkById, apiAuth.AdminRole), auditLog)
+	v3.POST("/compliance/run", httpserver.AuthorizeHandler(h.RunBenchmark, apiAuth.AdminRole), auditLog)
+	v3.POST("/compliance/resource-collection/:resource_collection_id/run", httpserver.AuthorizeHandler(h.RunResourceCollectionCompliance, apiAuth.AdminRole), auditLog)
+	v3.POST("/discovery/run", httpserver.AuthorizeHandler(h.RunDiscovery, apiAuth.AdminRole), auditLog)
 	v3.POST("/discovery/status", httpserver.AuthorizeHandler(h.GetIntegrationDiscoveryProgress, apiAuth.ViewerRole))
-
-	v3.PUT("/query/:query_id/run", httpserver.AuthorizeHandler(h.RunQuery, apiAuth.AdminRole))
+	v3.GET("/discovery/rate-limits", httpserver.AuthorizeHandler(h.ListDescribeRateLimits, apiAuth.ViewerRole))
+	v3.PUT("/discovery/rate-limits", httpserver.AuthorizeHandler(h.SetDescribeRateLimit, apiAuth.AdminRole), auditLog)
+	v3.DELETE("/discovery/rate-limits/:id", httpserver.AuthorizeHandler(h.DeleteDescribeRateLimit, apiAuth.AdminRole), auditLog)
+	v3.GET("/scheduler/leaders", httpserver.AuthorizeHandler(h.GetSchedulerLeaders, apiAuth.ViewerRole))
+	v3.POST("/audit/events", httpserver.AuthorizeHandler(h.ListAuditEvents, apiAuth.AdminRole))
+
+	v3.PUT("/query/:query_id/run", httpserver.AuthorizeHandler(h.RunQuery, apiAuth.AdminRole), auditLog)
+	v3.GET("/query/schedules", httpserver.AuthorizeHandler(h.ListQuerySchedules, apiAuth.ViewerRole))
+	v3.POST("/query/schedules", httpserver.AuthorizeHandler(h.CreateQuerySchedule, apiAuth.AdminRole), auditLog)
+	v3.GET("/query/schedules/:schedule_id", httpserver.AuthorizeHandler(h.GetQuerySchedule, apiAuth.ViewerRole))
+	v3.PUT("/query/schedules/:schedule_id", httpserver.AuthorizeHandler(h.UpdateQuerySchedule, apiAuth.AdminRole), auditLog)
+	v3.DELETE("/query/schedules/:schedule_id", httpserver.AuthorizeHandler(h.DeleteQuerySchedule, apiAuth.AdminRole), auditLog)
+	v3.POST("/query/schedules/:schedule_id/run", httpserver.AuthorizeHandler(h.RunQuerySchedule, apiAuth.AdminRole), auditLog)
+	v3.GET("/query/schedules/:schedule_id/runs", httpserver.AuthorizeHandler(h.ListQueryScheduleRuns, apiAuth.ViewerRole))
+	v3.GET("/query/schedules/:schedule_id/runs/:run_id", httpserver.AuthorizeHandler(h.GetQueryScheduleRun, apiAuth.ViewerRole))
 	v3.GET("/job/discovery/:job_id", httpserver.AuthorizeHandler(h.GetDescribeJobStatus, apiAuth.ViewerRole))
 	v3.GET("/job/compliance/:job_id", httpserver.AuthorizeHandler(h.GetComplianceJobStatus, apiAuth.ViewerRole))
 	v3.GET("/job/query/:job_id", httpserver.AuthorizeHandler(h.GetAsyncQueryRunJobStatus, apiAuth.ViewerRole))
@@ -96,18 +116,20 @@ func (h HttpServer) Register(e *echo.Echo) {
 	v3.POST("/jobs/compliance", httpserver.AuthorizeHandler(h.ListComplianceJobs, apiAuth.ViewerRole))
 	v3.POST("/benchmark/:benchmark_id/run-history", httpserver.AuthorizeHandler(h.BenchmarkAuditHistory, apiAuth.ViewerRole))
 	v3.GET("/benchmark/run-history/integrations", httpserver.AuthorizeHandler(h.BenchmarkAuditHistoryIntegrations, apiAuth.ViewerRole))
-	v3.PUT("/jobs/cancel/byid", httpserver.AuthorizeHandler(h.CancelJobById, apiAuth.AdminRole))
-	v3.POST("/jobs/cancel", httpserver.AuthorizeHandler(h.CancelJob, apiAuth.AdminRole))
+	v3.PUT("/jobs/cancel/byid", httpserver.AuthorizeHandler(h.CancelJobById, apiAuth.AdminRole), auditLog)
+	v3.POST("/jobs/cancel", httpserver.AuthorizeHandler(h.CancelJob, apiAuth.AdminRole), auditLog)
 	v3.POST("/jobs", httpserver.AuthorizeHandler(h.ListJobsByType, apiAuth.ViewerRole))
 	v3.GET("/jobs/interval", httpserver.AuthorizeHandler(h.ListJobsInterval, apiAuth.ViewerRole))
 	v3.GET("/jobs/compliance/summary/jobs", httpserver.AuthorizeHandler(h.GetSummaryJobs, apiAuth.ViewerRole))
 	v3.GET("/jobs/history/compliance", httpserver.AuthorizeHandler(h.ListComplianceJobsHistory, apiAuth.ViewerRole))
+	v3.POST("/platform/jobs", httpserver.AuthorizeHandler(h.ListPlatformJobs, apiAuth.ViewerRole))
+	v3.POST("/platform/compliance-runs", httpserver.AuthorizeHandler(h.ListPlatformComplianceRuns, apiAuth.ViewerRole))
 
-	v3.PUT("/sample/purge", httpserver.AuthorizeHandler(h.PurgeSampleData, apiAuth.AdminRole))
+	v3.PUT("/sample/purge", httpserver.AuthorizeHandler(h.PurgeSampleData, apiAuth.AdminRole), auditLog)
 
 	v3.GET("/integration/discovery/last-job", httpserver.AuthorizeHandler(h.GetIntegrationLastDiscoveryJob, apiAuth.ViewerRole))
 
-	v3.POST("/compliance/quick/sequence", httpserver.AuthorizeHandler(h.CreateComplianceQuickSequence, apiAuth.EditorRole))
+	v3.POST("/compliance/quick/sequence", httpserver.AuthorizeHandler(h.CreateComplianceQuickSequence, apiAuth.EditorRole), auditLog)
 	v3.GET("/compliance/quick/sequence/:run_id", httpserver.AuthorizeHandler(h.GetComplianceQuickSequence, apiAuth.ViewerRole))
 }
 
@@ -512,7 +534,7 @@ func (h HttpServer) TriggerConnectionsComplianceJob(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusConflict, "compliance job is already running")
 	}
 
-	_, err = h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmarkID, lastJob, connectionIDs, true, userID, nil)
+	_, err = h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmarkID, lastJob, connectionIDs, true, userID, nil, nil)
 
 	return ctx.JSON(http.StatusOK, "")
 }
@@ -570,7 +592,7 @@ func (h HttpServer) TriggerConnectionsComplianceJobs(ctx echo.Context) error {
 			return echo.NewHTTPError(http.StatusConflict, "compliance job is already running")
 		}
 
-		_, err = h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmark.ID, lastJob, connectionIDs, true, userID, nil)
+		_, err = h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmark.ID, lastJob, connectionIDs, true, userID, nil, nil)
 		if err != nil {
 			return fmt.Errorf("error while creating compliance job: %v", err)
 		}
@@ -1280,7 +1302,7 @@ func (h HttpServer) RunBenchmarkById(ctx echo.Context) error {
 			return err
 		}
 
-		jobs, err := h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmarkID, lastJob, connectionIDs, true, userID, nil)
+		jobs, err := h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmarkID, lastJob, connectionIDs, true, userID, nil, nil)
 		if err != nil {
 			return fmt.Errorf("error while creating compliance job: %v", err)
 		}
@@ -1298,7 +1320,7 @@ func (h HttpServer) RunBenchmarkById(ctx echo.Context) error {
 			apiJobs = append(apiJobs, job)
 		}
 	} else {
-		jobs, err := h.Scheduler.complianceScheduler.CreateComplianceReportJobs(false, benchmarkID, nil, connectionIDs, true, userID, nil)
+		jobs, err := h.Scheduler.complianceScheduler.CreateComplianceReportJobs(false, benchmarkID, nil, connectionIDs, true, userID, nil, nil)
 		if err != nil {
 			return fmt.Errorf("error while creating compliance job: %v", err)
 		}
@@ -1322,6 +1344,125 @@ func (h HttpServer) RunBenchmarkById(ctx echo.Context) error {
 	})
 }
 
+// RunResourceCollectionCompliance godoc
+//
+//	@Summary		Triggers compliance jobs scoped to a resource collection
+//	@Description	Triggers a compliance job for each benchmark assigned to the resource collection, the results are summarized under the collection
+//	@Security		BearerToken
+//	@Tags			describe
+//	@Produce		json
+//	@Param			resource_collection_id	path		string										true	"Resource collection ID"
+//	@Param			request					body		api.RunResourceCollectionComplianceRequest	false	"Benchmarks filter"
+//	@Success		200						{object}	api.RunBenchmarkResponse
+//	@Router			/schedule/api/v3/compliance/resource-collection/{resource_collection_id}/run [post]
+func (h HttpServer) RunResourceCollectionCompliance(ctx echo.Context) error {
+	clientCtx := &httpclient.Context{UserRole: apiAuth.AdminRole}
+	userID := httpserver.GetUserID(ctx)
+	if userID == "" {
+		userID = "system"
+	}
+	resourceCollectionID := ctx.Param("resource_collection_id")
+
+	var request api.RunResourceCollectionComplianceRequest
+	if err := ctx.Bind(&request); err != nil {
+		ctx.Logger().Errorf("bind the request: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+	audit.SetAction(ctx, "compliance.resource_collection.run")
+	audit.SetTarget(ctx, "resource_collection", resourceCollectionID)
+
+	assignments, err := h.Scheduler.complianceClient.ListResourceCollectionAssignments(clientCtx, resourceCollectionID)
+	if err != nil {
+		h.Scheduler.logger.Error("failed to list resource collection assignments", zap.String("resource_collection_id", resourceCollectionID), zap.Error(err))
+		return err
+	}
+	requestedBenchmarks := make(map[string]bool)
+	for _, benchmarkID := range request.BenchmarkIDs {
+		requestedBenchmarks[strings.ToLower(benchmarkID)] = true
+	}
+
+	integrations, err := h.Scheduler.integrationClient.ListIntegrations(clientCtx, nil)
+	if err != nil {
+		h.Scheduler.logger.Error("failed to list integrations", zap.Error(err))
+		return err
+	}
+
+	var apiJobs []api.RunBenchmarkItem
+	for _, assignment := range assignments {
+		if len(requestedBenchmarks) > 0 && !requestedBenchmarks[strings.ToLower(assignment.BenchmarkId)] {
+			continue
+		}
+		benchmark, err := h.Scheduler.complianceClient.GetBenchmark(clientCtx, assignment.BenchmarkId)
+		if err != nil {
+			return fmt.Errorf("error while getting benchmarks: %v", err)
+		}
+		if benchmark == nil {
+			continue
+		}
+		validIntegrationTypes := make(map[string]bool)
+		for _, it := range benchmark.IntegrationTypes {
+			validIntegrationTypes[it] = true
+		}
+
+		connectionInfo := make(map[string]api.IntegrationInfo)
+		var connectionIDs []string
+		for _, c := range integrations.Integrations {
+			if c.State != integrationapi.IntegrationStateActive {
+				continue
+			}
+			if _, ok := validIntegrationTypes[c.IntegrationType.String()]; !ok {
+				continue
+			}
+			connectionInfo[c.IntegrationID] = api.IntegrationInfo{
+				IntegrationID:   c.IntegrationID,
+				IntegrationType: string(c.IntegrationType),
+				Name:            c.Name,
+				ProviderID:      c.ProviderID,
+			}
+			connectionIDs = append(connectionIDs, c.IntegrationID)
+		}
+		if len(connectionIDs) == 0 {
+			continue
+		}
+
+		lastJob, err := h.Scheduler.db.GetLastResourceCollectionComplianceJob(true, benchmark.ID, resourceCollectionID)
+		if err != nil {
+			return err
+		}
+		if lastJob != nil && (lastJob.Status == model2.ComplianceJobRunnersInProgress ||
+			lastJob.Status == model2.ComplianceJobSummarizerInProgress ||
+			lastJob.Status == model2.ComplianceJobCreated) {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("compliance job of %s is already running for the resource collection", benchmark.ID))
+		}
+
+		jobs, err := h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmark.ID, lastJob, connectionIDs, true, userID, nil, &resourceCollectionID)
+		if err != nil {
+			return fmt.Errorf("error while creating compliance job: %v", err)
+		}
+		for _, j := range jobs {
+			job := api.RunBenchmarkItem{
+				JobId:                j.ID,
+				WithIncident:         true,
+				BenchmarkId:          benchmark.ID,
+				ResourceCollectionID: j.ResourceCollectionID,
+			}
+			for _, integration := range j.IntegrationIDs {
+				if v, ok := connectionInfo[integration]; ok {
+					job.IntegrationInfo = append(job.IntegrationInfo, v)
+				}
+			}
+			apiJobs = append(apiJobs, job)
+		}
+	}
+	if len(apiJobs) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "no benchmark with active integrations is assigned to the resource collection")
+	}
+
+	return ctx.JSON(http.StatusOK, api.RunBenchmarkResponse{
+		Jobs: apiJobs,
+	})
+}
+
 // RunBenchmark godoc
 //
 //	@Summary		Triggers compliance job
@@ -1423,7 +1564,7 @@ func (h HttpServer) RunBenchmark(ctx echo.Context) error {
 			return err
 		}
 
-		benchmarkJobs, err := h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmark.ID, lastJob, connectionIDs, true, userID, nil)
+		benchmarkJobs, err := h.Scheduler.complianceScheduler.CreateComplianceReportJobs(true, benchmark.ID, lastJob, connectionIDs, true, userID, nil, nil)
 		if err != nil {
 			return fmt.Errorf("error while creating compliance job: %v", err)
 		}
@@ -1711,6 +1852,9 @@ func (h HttpServer) GetComplianceJobStatus(ctx echo.Context) error {
 		JobStatus:       string(j.Status),
 		CreatedAt:       j.CreatedAt,
 		UpdatedAt:       j.UpdatedAt,
+
+		DataAsOf:             j.DataAsOf,
+		EvaluatedOnStaleData: j.EvaluatedOnStaleData,
 	}
 
 	return ctx.JSON(http.StatusOK, jobsResult)
@@ -3927,3 +4071,773 @@ func (h HttpServer) PurgeSampleData(c echo.Context) error {
 
 	return c.NoContent(http.StatusOK)
 }
+
+// ListDescribeRateLimits godoc
+//
+//	@Summary		List discovery rate limits
+//	@Description	List the configured concurrency, per minute limits and fair-share weights of discovery jobs
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Produce		json
+//	@Success		200	{object}	api.ListDescribeRateLimitsResponse
+//	@Router			/schedule/api/v3/discovery/rate-limits [get]
+func (h HttpServer) ListDescribeRateLimits(c echo.Context) error {
+	limits, err := h.DB.ListDescribeRateLimits()
+	if err != nil {
+		h.Scheduler.logger.Error("failed to list rate limits", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list rate limits")
+	}
+
+	response := api.ListDescribeRateLimitsResponse{
+		RateLimits: make([]api.DescribeRateLimit, 0, len(limits)),
+	}
+	for _, limit := range limits {
+		response.RateLimits = append(response.RateLimits, limit.ToAPI())
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// SetDescribeRateLimit godoc
+//
+//	@Summary		Create or update a discovery rate limit
+//	@Description	Create or update the rate limit of a resource type, integration or integration type.
+//	@Description	Zero limits mean no limit, weight is the fair-share weight and defaults to 1.
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.SetDescribeRateLimitRequest	true	"Rate limit"
+//	@Success		200		{object}	api.DescribeRateLimit
+//	@Router			/schedule/api/v3/discovery/rate-limits [put]
+func (h HttpServer) SetDescribeRateLimit(c echo.Context) error {
+	var request api.SetDescribeRateLimitRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Errorf("bind the request: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	switch request.Scope {
+	case api.DescribeRateLimitScopeResourceType, api.DescribeRateLimitScopeIntegration, api.DescribeRateLimitScopeIntegrationType:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid scope")
+	}
+	if strings.TrimSpace(request.Key) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "key is required")
+	}
+	if request.MaxConcurrency < 0 || request.MaxPerMinute < 0 || request.Weight < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "limits and weight can not be negative")
+	}
+	if request.Weight == 0 {
+		request.Weight = 1
+	}
+
+	userID := httpserver.GetUserID(c)
+	if userID == "" {
+		userID = "system"
+	}
+
+	limit := model2.DescribeRateLimit{
+		Scope:          model2.DescribeRateLimitScope(request.Scope),
+		Key:            request.Key,
+		MaxConcurrency: request.MaxConcurrency,
+		MaxPerMinute:   request.MaxPerMinute,
+		Weight:         request.Weight,
+		UpdatedBy:      userID,
+	}
+	if err := h.DB.UpsertDescribeRateLimit(&limit); err != nil {
+		h.Scheduler.logger.Error("failed to set rate limit", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to set rate limit")
+	}
+
+	return c.JSON(http.StatusOK, limit.ToAPI())
+}
+
+// DeleteDescribeRateLimit godoc
+//
+//	@Summary		Delete a discovery rate limit
+//	@Description	Delete a discovery rate limit, resource types fall back to their built-in limit
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			id	path	string	true	"Rate limit ID"
+//	@Success		200
+//	@Router			/schedule/api/v3/discovery/rate-limits/{id} [delete]
+func (h HttpServer) DeleteDescribeRateLimit(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	}
+
+	limit, err := h.DB.GetDescribeRateLimit(uint(id))
+	if err != nil {
+		h.Scheduler.logger.Error("failed to get rate limit", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get rate limit")
+	}
+	if limit == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "rate limit not found")
+	}
+	audit.SetAction(c, "discovery.rate_limit.delete")
+	audit.SetBefore(c, limit.ToAPI())
+
+	if err = h.DB.DeleteDescribeRateLimit(limit.ID); err != nil {
+		h.Scheduler.logger.Error("failed to delete rate limit", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete rate limit")
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GetSchedulerLeaders godoc
+//
+//	@Summary		Get scheduler leaders
+//	@Description	Get the scheduler replica leading each scheduler loop and the id of the replica serving the request
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Produce		json
+//	@Success		200	{object}	api.GetSchedulerLeadersResponse
+//	@Router			/schedule/api/v3/scheduler/leaders [get]
+func (h HttpServer) GetSchedulerLeaders(c echo.Context) error {
+	leases, err := h.DB.ListSchedulerLeases()
+	if err != nil {
+		h.Scheduler.logger.Error("failed to list scheduler leases", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list scheduler leases")
+	}
+
+	response := api.GetSchedulerLeadersResponse{
+		ReplicaID: h.Scheduler.leader.HolderID(),
+		Leases:    make([]api.SchedulerLease, 0, len(leases)),
+	}
+	for _, lease := range leases {
+		response.Leases = append(response.Leases, lease.ToAPI())
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListAuditEvents godoc
+//
+//	@Summary		List audit events
+//	@Description	List the audit events of the mutating API calls of all services, newest first
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			request	body	api.ListAuditEventsRequest	true	"Request Body"
+//	@Produce		json
+//	@Success		200	{object}	api.ListAuditEventsResponse
+//	@Router			/schedule/api/v3/audit/events [post]
+func (h HttpServer) ListAuditEvents(c echo.Context) error {
+	var request api.ListAuditEventsRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	perPage := 100
+	if request.PerPage != nil && *request.PerPage > 0 {
+		perPage = *request.PerPage
+	}
+	cursor := 1
+	if request.Cursor != nil && *request.Cursor > 0 {
+		cursor = *request.Cursor
+	}
+
+	events, totalCount, err := es.ListAuditEvents(c.Request().Context(), h.Scheduler.logger, h.Scheduler.es, es.AuditEventsFilters{
+		Services:    request.Services,
+		Actions:     request.Actions,
+		ActorIDs:    request.ActorIDs,
+		TargetTypes: request.TargetTypes,
+		TargetIDs:   request.TargetIDs,
+		StartTime:   request.StartTime,
+		EndTime:     request.EndTime,
+	}, (cursor-1)*perPage, perPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list audit events")
+	}
+
+	return c.JSON(http.StatusOK, api.ListAuditEventsResponse{
+		Events:     events,
+		TotalCount: totalCount,
+	})
+}
+
+func (h HttpServer) newQueryScheduleModel(c echo.Context, request api.QueryScheduleRequest) (*model2.QuerySchedule, error) {
+	if strings.TrimSpace(request.Name) == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+	if strings.TrimSpace(request.QueryID) == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "query_id is required")
+	}
+	nextRunAt, err := queryrunnerscheduler.NextQueryScheduleRun(request.CronExpression, time.Now())
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid cron expression: %s", err.Error()))
+	}
+	for _, keyColumn := range request.KeyColumns {
+		if strings.TrimSpace(keyColumn) == "" {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "key columns can not be empty")
+		}
+	}
+	for _, threshold := range request.Thresholds {
+		if err := threshold.Validate(); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid threshold %s: %s", threshold.Name, err.Error()))
+		}
+	}
+
+	clientCtx := &httpclient.Context{UserRole: apiAuth.AdminRole, Ctx: c.Request().Context()}
+	namedQuery, err := h.Scheduler.inventoryClient.GetQuery(clientCtx, request.QueryID)
+	if err != nil || namedQuery == nil {
+		control, err := h.Scheduler.complianceClient.GetControlDetails(clientCtx, request.QueryID)
+		if err != nil || control == nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "query not found")
+		}
+	}
+
+	if request.Parameters == nil {
+		request.Parameters = make(map[string]string)
+	}
+	if request.Thresholds == nil {
+		request.Thresholds = make([]api.QueryScheduleThreshold, 0)
+	}
+	schedule := model2.QuerySchedule{
+		Name:           request.Name,
+		QueryID:        request.QueryID,
+		CronExpression: request.CronExpression,
+		KeyColumns:     request.KeyColumns,
+		Enabled:        request.Enabled == nil || *request.Enabled,
+		NextRunAt:      nextRunAt,
+	}
+	parametersJson, err := json.Marshal(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	if err = schedule.Parameters.Set(parametersJson); err != nil {
+		return nil, err
+	}
+	thresholdsJson, err := json.Marshal(request.Thresholds)
+	if err != nil {
+		return nil, err
+	}
+	if err = schedule.Thresholds.Set(thresholdsJson); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (h HttpServer) getQuerySchedule(c echo.Context) (*model2.QuerySchedule, error) {
+	id, err := strconv.ParseUint(c.Param("schedule_id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid schedule id")
+	}
+	schedule, err := h.DB.GetQuerySchedule(uint(id))
+	if err != nil {
+		h.Scheduler.logger.Error("failed to get query schedule", zap.Error(err))
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to get query schedule")
+	}
+	if schedule == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "query schedule not found")
+	}
+	return schedule, nil
+}
+
+// ListQuerySchedules godoc
+//
+//	@Summary		List query schedules
+//	@Description	List the scheduled named queries
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Produce		json
+//	@Success		200	{object}	api.ListQuerySchedulesResponse
+//	@Router			/schedule/api/v3/query/schedules [get]
+func (h HttpServer) ListQuerySchedules(c echo.Context) error {
+	schedules, err := h.DB.ListQuerySchedules()
+	if err != nil {
+		h.Scheduler.logger.Error("failed to list query schedules", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list query schedules")
+	}
+
+	response := api.ListQuerySchedulesResponse{
+		Items:      make([]api.QuerySchedule, 0, len(schedules)),
+		TotalCount: len(schedules),
+	}
+	for _, schedule := range schedules {
+		item, err := schedule.ToAPI()
+		if err != nil {
+			h.Scheduler.logger.Error("failed to read query schedule", zap.Uint("scheduleId", schedule.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to read query schedule")
+		}
+		response.Items = append(response.Items, item)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetQuerySchedule godoc
+//
+//	@Summary	Get a query schedule
+//	@Security	BearerToken
+//	@Tags		scheduler
+//	@Param		schedule_id	path	string	true	"Schedule ID"
+//	@Produce	json
+//	@Success	200	{object}	api.QuerySchedule
+//	@Router		/schedule/api/v3/query/schedules/{schedule_id} [get]
+func (h HttpServer) GetQuerySchedule(c echo.Context) error {
+	schedule, err := h.getQuerySchedule(c)
+	if err != nil {
+		return err
+	}
+	response, err := schedule.ToAPI()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read query schedule")
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateQuerySchedule godoc
+//
+//	@Summary		Create a query schedule
+//	@Description	Schedule a named query or control query with a cron expression and parameter values.
+//	@Description	Every run snapshots the result, diffs it against the previous run by the key columns and evaluates the thresholds.
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.QueryScheduleRequest	true	"Query schedule"
+//	@Success		201		{object}	api.QuerySchedule
+//	@Router			/schedule/api/v3/query/schedules [post]
+func (h HttpServer) CreateQuerySchedule(c echo.Context) error {
+	var request api.QueryScheduleRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Errorf("bind the request: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+	audit.SetAction(c, "query.schedule.create")
+	audit.SetTarget(c, "query_schedule", request.Name)
+
+	schedule, err := h.newQueryScheduleModel(c, request)
+	if err != nil {
+		return err
+	}
+	schedule.CreatedBy = httpserver.GetUserID(c)
+	if schedule.CreatedBy == "" {
+		schedule.CreatedBy = "system"
+	}
+	if err = h.DB.CreateQuerySchedule(schedule); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return echo.NewHTTPError(http.StatusConflict, "query schedule with the same name already exists")
+		}
+		h.Scheduler.logger.Error("failed to create query schedule", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create query schedule")
+	}
+
+	response, err := schedule.ToAPI()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read query schedule")
+	}
+	return c.JSON(http.StatusCreated, response)
+}
+
+// UpdateQuerySchedule godoc
+//
+//	@Summary		Update a query schedule
+//	@Description	Replace the definition of a query schedule, the run history is kept and the next run is recomputed
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Accept			json
+//	@Produce		json
+//	@Param			schedule_id	path		string						true	"Schedule ID"
+//	@Param			request		body		api.QueryScheduleRequest	true	"Query schedule"
+//	@Success		200			{object}	api.QuerySchedule
+//	@Router			/schedule/api/v3/query/schedules/{schedule_id} [put]
+func (h HttpServer) UpdateQuerySchedule(c echo.Context) error {
+	existing, err := h.getQuerySchedule(c)
+	if err != nil {
+		return err
+	}
+	var request api.QueryScheduleRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Errorf("bind the request: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+	before, err := existing.ToAPI()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read query schedule")
+	}
+	audit.SetAction(c, "query.schedule.update")
+	audit.SetTarget(c, "query_schedule", existing.Name)
+	audit.SetBefore(c, before)
+
+	schedule, err := h.newQueryScheduleModel(c, request)
+	if err != nil {
+		return err
+	}
+	if err = h.DB.UpdateQuerySchedule(existing.ID, schedule); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return echo.NewHTTPError(http.StatusConflict, "query schedule with the same name already exists")
+		}
+		h.Scheduler.logger.Error("failed to update query schedule", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update query schedule")
+	}
+
+	updated, err := h.DB.GetQuerySchedule(existing.ID)
+	if err != nil || updated == nil {
+		h.Scheduler.logger.Error("failed to get query schedule", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get query schedule")
+	}
+	response, err := updated.ToAPI()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read query schedule")
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeleteQuerySchedule godoc
+//
+//	@Summary		Delete a query schedule
+//	@Description	Delete a query schedule and its run history
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			schedule_id	path	string	true	"Schedule ID"
+//	@Success		200
+//	@Router			/schedule/api/v3/query/schedules/{schedule_id} [delete]
+func (h HttpServer) DeleteQuerySchedule(c echo.Context) error {
+	schedule, err := h.getQuerySchedule(c)
+	if err != nil {
+		return err
+	}
+	before, err := schedule.ToAPI()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read query schedule")
+	}
+	audit.SetAction(c, "query.schedule.delete")
+	audit.SetTarget(c, "query_schedule", schedule.Name)
+	audit.SetBefore(c, before)
+
+	if err = h.DB.DeleteQuerySchedule(schedule.ID); err != nil {
+		h.Scheduler.logger.Error("failed to delete query schedule", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete query schedule")
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// RunQuerySchedule godoc
+//
+//	@Summary		Run a query schedule now
+//	@Description	Trigger a run of an enabled query schedule on the next scheduling cycle
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			schedule_id	path	string	true	"Schedule ID"
+//	@Success		200
+//	@Router			/schedule/api/v3/query/schedules/{schedule_id}/run [post]
+func (h HttpServer) RunQuerySchedule(c echo.Context) error {
+	schedule, err := h.getQuerySchedule(c)
+	if err != nil {
+		return err
+	}
+	audit.SetAction(c, "query.schedule.run")
+	audit.SetTarget(c, "query_schedule", schedule.Name)
+	if !schedule.Enabled {
+		return echo.NewHTTPError(http.StatusBadRequest, "query schedule is disabled")
+	}
+
+	if err = h.DB.UpdateQueryScheduleNextRun(schedule.ID, time.Now(), nil); err != nil {
+		h.Scheduler.logger.Error("failed to trigger query schedule", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to trigger query schedule")
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// ListQueryScheduleRuns godoc
+//
+//	@Summary		List query schedule runs
+//	@Description	List the run history of a query schedule newest first, without the row snapshots
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			schedule_id	path	string	true	"Schedule ID"
+//	@Param			cursor		query	int		false	"Cursor"
+//	@Param			per_page	query	int		false	"Per page"
+//	@Produce		json
+//	@Success		200	{object}	api.ListQueryScheduleRunsResponse
+//	@Router			/schedule/api/v3/query/schedules/{schedule_id}/runs [get]
+func (h HttpServer) ListQueryScheduleRuns(c echo.Context) error {
+	schedule, err := h.getQuerySchedule(c)
+	if err != nil {
+		return err
+	}
+
+	cursor, perPage := int64(1), int64(20)
+	if cursorStr := c.QueryParam("cursor"); cursorStr != "" {
+		cursor, err = strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || cursor < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid cursor")
+		}
+	}
+	if perPageStr := c.QueryParam("per_page"); perPageStr != "" {
+		perPage, err = strconv.ParseInt(perPageStr, 10, 64)
+		if err != nil || perPage < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid per_page")
+		}
+	}
+
+	runs, total, err := h.DB.ListQueryScheduleRuns(schedule.ID, int(perPage), int((cursor-1)*perPage))
+	if err != nil {
+		h.Scheduler.logger.Error("failed to list query schedule runs", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list query schedule runs")
+	}
+
+	response := api.ListQueryScheduleRunsResponse{
+		Items:      make([]api.QueryScheduleRun, 0, len(runs)),
+		TotalCount: total,
+	}
+	for _, run := range runs {
+		item, err := run.ToAPI(false)
+		if err != nil {
+			h.Scheduler.logger.Error("failed to read query schedule run", zap.Uint("runId", run.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to read query schedule run")
+		}
+		response.Items = append(response.Items, item)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetQueryScheduleRun godoc
+//
+//	@Summary		Get a query schedule run
+//	@Description	Get a run of a query schedule with its result snapshot and the rows added and removed since the previous run
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			schedule_id	path	string	true	"Schedule ID"
+//	@Param			run_id		path	string	true	"Run ID"
+//	@Produce		json
+//	@Success		200	{object}	api.QueryScheduleRun
+//	@Router			/schedule/api/v3/query/schedules/{schedule_id}/runs/{run_id} [get]
+func (h HttpServer) GetQueryScheduleRun(c echo.Context) error {
+	schedule, err := h.getQuerySchedule(c)
+	if err != nil {
+		return err
+	}
+	runID, err := strconv.ParseUint(c.Param("run_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid run id")
+	}
+
+	run, err := h.DB.GetQueryScheduleRun(schedule.ID, uint(runID))
+	if err != nil {
+		h.Scheduler.logger.Error("failed to get query schedule run", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get query schedule run")
+	}
+	if run == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "query schedule run not found")
+	}
+
+	response, err := run.ToAPI(true)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read query schedule run")
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListPlatformJobs godoc
+//
+//	@Summary		List platform jobs
+//	@Description	List discovery, compliance, compliance runner and query jobs of the platform in a single shape, at most 1000 jobs of each type per page
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			request	body	api.ListPlatformJobsRequest	true	"Filters"
+//	@Produce		json
+//	@Success		200	{object}	[]api.PlatformJob
+//	@Router			/schedule/api/v3/platform/jobs [post]
+func (h HttpServer) ListPlatformJobs(c echo.Context) error {
+	var request api.ListPlatformJobsRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Errorf("bind the request: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	jobTypes := make(map[api.PlatformJobType]bool)
+	for _, t := range request.JobTypes {
+		jobTypes[t] = true
+	}
+	include := func(t api.PlatformJobType) bool {
+		return len(jobTypes) == 0 || jobTypes[t]
+	}
+	filter := db.PlatformJobsFilter{
+		Statuses:       request.Statuses,
+		IntegrationIDs: request.IntegrationIDs,
+		CreatedAfter:   request.CreatedAfter,
+		CreatedBefore:  request.CreatedBefore,
+		Limit:          request.Limit,
+		Offset:         request.Offset,
+	}
+
+	items := make([]api.PlatformJob, 0)
+	if include(api.PlatformJobTypeDiscovery) {
+		jobs, err := h.DB.ListPlatformDescribeJobs(filter)
+		if err != nil {
+			h.Scheduler.logger.Error("failed to list discovery jobs", zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list discovery jobs")
+		}
+		for _, j := range jobs {
+			items = append(items, api.PlatformJob{
+				JobID:           j.ID,
+				JobType:         api.PlatformJobTypeDiscovery,
+				Status:          string(j.Status),
+				IntegrationID:   j.IntegrationID,
+				IntegrationType: j.IntegrationType.String(),
+				ResourceType:    j.ResourceType,
+				ParentJobID:     j.ParentID,
+				TriggerType:     string(j.TriggerType),
+				CreatedBy:       j.CreatedBy,
+				RetryCount:      j.RetryCount,
+				FailureMessage:  j.FailureMessage,
+				CreatedAt:       j.CreatedAt,
+				UpdatedAt:       j.UpdatedAt,
+			})
+		}
+	}
+	if include(api.PlatformJobTypeCompliance) {
+		jobs, err := h.DB.ListPlatformComplianceJobs(filter)
+		if err != nil {
+			h.Scheduler.logger.Error("failed to list compliance jobs", zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list compliance jobs")
+		}
+		for _, j := range jobs {
+			var integrationID string
+			if len(j.IntegrationIDs) == 1 {
+				integrationID = j.IntegrationIDs[0]
+			}
+			items = append(items, api.PlatformJob{
+				JobID:          j.ID,
+				JobType:        api.PlatformJobTypeCompliance,
+				IntegrationID:  integrationID,
+				Status:         string(j.Status),
+				IntegrationIDs: j.IntegrationIDs,
+				FrameworkID:    j.FrameworkID,
+				ParentJobID:    j.ParentID,
+				TriggerType:    string(j.TriggerType),
+				CreatedBy:      j.CreatedBy,
+				FailureMessage: j.FailureMessage,
+				CreatedAt:      j.CreatedAt,
+				UpdatedAt:      j.UpdatedAt,
+			})
+		}
+	}
+	if include(api.PlatformJobTypeComplianceRunner) {
+		jobs, err := h.DB.ListPlatformComplianceRunners(filter)
+		if err != nil {
+			h.Scheduler.logger.Error("failed to list compliance runners", zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list compliance runners")
+		}
+		for _, j := range jobs {
+			parentJobID := j.ParentJobID
+			item := api.PlatformJob{
+				JobID:          j.ID,
+				JobType:        api.PlatformJobTypeComplianceRunner,
+				Status:         string(j.Status),
+				FrameworkID:    j.FrameworkID,
+				QueryID:        j.QueryID,
+				ParentJobID:    &parentJobID,
+				TriggerType:    string(j.TriggerType),
+				RetryCount:     j.RetryCount,
+				FailureMessage: j.FailureMessage,
+				CreatedAt:      j.CreatedAt,
+				UpdatedAt:      j.UpdatedAt,
+			}
+			if j.IntegrationID != nil {
+				item.IntegrationID = *j.IntegrationID
+			}
+			items = append(items, item)
+		}
+	}
+	// query runs are not bound to an integration
+	if include(api.PlatformJobTypeQuery) && len(request.IntegrationIDs) == 0 {
+		jobs, err := h.DB.ListPlatformQueryRunnerJobs(filter)
+		if err != nil {
+			h.Scheduler.logger.Error("failed to list query runner jobs", zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list query runner jobs")
+		}
+		for _, j := range jobs {
+			items = append(items, api.PlatformJob{
+				JobID:          j.ID,
+				JobType:        api.PlatformJobTypeQuery,
+				Status:         string(j.Status),
+				QueryID:        j.QueryId,
+				CreatedBy:      j.CreatedBy,
+				RetryCount:     j.RetryCount,
+				FailureMessage: j.FailureMessage,
+				CreatedAt:      j.CreatedAt,
+				UpdatedAt:      j.UpdatedAt,
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+// ListPlatformComplianceRuns godoc
+//
+//	@Summary		List platform compliance runs
+//	@Description	List compliance jobs with the number of their runners per status
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			request	body	api.ListPlatformComplianceRunsRequest	true	"Filters"
+//	@Produce		json
+//	@Success		200	{object}	[]api.PlatformComplianceRun
+//	@Router			/schedule/api/v3/platform/compliance-runs [post]
+func (h HttpServer) ListPlatformComplianceRuns(c echo.Context) error {
+	var request api.ListPlatformComplianceRunsRequest
+	if err := c.Bind(&request); err != nil {
+		c.Logger().Errorf("bind the request: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	jobs, err := h.DB.ListPlatformComplianceJobs(db.PlatformJobsFilter{
+		Statuses:       request.Statuses,
+		IntegrationIDs: request.IntegrationIDs,
+		FrameworkIDs:   request.FrameworkIDs,
+		CreatedAfter:   request.CreatedAfter,
+		CreatedBefore:  request.CreatedBefore,
+		Limit:          request.Limit,
+		Offset:         request.Offset,
+	})
+	if err != nil {
+		h.Scheduler.logger.Error("failed to list compliance jobs", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list compliance jobs")
+	}
+
+	jobIDs := make([]uint, 0, len(jobs))
+	for _, j := range jobs {
+		jobIDs = append(jobIDs, j.ID)
+	}
+	runnersCount := make(map[uint]map[string]int64)
+	if len(jobIDs) > 0 {
+		runnersCount, err = h.DB.CountComplianceRunnersByStatus(jobIDs)
+		if err != nil {
+			h.Scheduler.logger.Error("failed to count compliance runners", zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to count compliance runners")
+		}
+	}
+
+	items := make([]api.PlatformComplianceRun, 0, len(jobs))
+	for _, j := range jobs {
+		item := api.PlatformComplianceRun{
+			JobID:                j.ID,
+			FrameworkID:          j.FrameworkID,
+			Status:               string(j.Status),
+			IntegrationIDs:       j.IntegrationIDs,
+			ResourceCollectionID: j.ResourceCollectionID,
+			WithIncidents:        j.WithIncidents,
+			TriggerType:          string(j.TriggerType),
+			CreatedBy:            j.CreatedBy,
+			ParentJobID:          j.ParentID,
+			FailureMessage:       j.FailureMessage,
+			DataAsOf:             j.DataAsOf,
+			EvaluatedOnStaleData: j.EvaluatedOnStaleData,
+			RunnersCount:         make(map[string]int64),
+			CreatedAt:            j.CreatedAt,
+			UpdatedAt:            j.UpdatedAt,
+		}
+		for status, count := range runnersCount[j.ID] {
+			item.RunnersCount[status] = count
+			item.TotalRunnersCount += count
+		}
+		items = append(items, item)
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
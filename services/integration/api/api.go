@@ -4,11 +4,12 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/opengovern/og-util/pkg/steampipe"
 	"github.com/opengovern/og-util/pkg/vault"
+	"github.com/opengovern/opencomply/pkg/audit"
+	"github.com/opengovern/opencomply/pkg/executor"
 	"github.com/opengovern/opencomply/services/integration/api/credentials"
 	"github.com/opengovern/opencomply/services/integration/api/integrations"
 	"github.com/opengovern/opencomply/services/integration/db"
 	"go.uber.org/zap"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type API struct {
@@ -16,10 +17,11 @@ type API struct {
 	database        db.Database
 	steampipeConn   *steampipe.Database
 	vault           vault.VaultSourceConfig
-	kubeClient      client.Client
+	executor        executor.Executor
 	vaultKeyId      string
 	masterAccessKey string
 	masterSecretKey string
+	auditRecorder   *audit.Recorder
 }
 
 func New(
@@ -27,21 +29,24 @@ func New(
 	db db.Database,
 	vault vault.VaultSourceConfig,
 	steampipeConn *steampipe.Database,
-	kubeClient client.Client,
+	executor executor.Executor,
+	auditRecorder *audit.Recorder,
 ) *API {
 	return &API{
 		logger:        logger.Named("api"),
 		database:      db,
 		vault:         vault,
 		steampipeConn: steampipeConn,
-		kubeClient:    kubeClient,
+		executor:      executor,
+		auditRecorder: auditRecorder,
 	}
 }
 
 func (api *API) Register(e *echo.Echo) {
-	integrationsApi := integrations.New(api.vault, api.database, api.logger, api.steampipeConn, api.kubeClient)
+	integrationsApi := integrations.New(api.vault, api.database, api.logger, api.steampipeConn, api.executor)
 	cred := credentials.New(api.vault, api.database, api.logger)
 
-	integrationsApi.Register(e.Group("/api/v1/integrations"))
-	cred.Register(e.Group("/api/v1/credentials"))
+	auditLog := api.auditRecorder.Middleware()
+	integrationsApi.Register(e.Group("/api/v1/integrations"), auditLog)
+	cred.Register(e.Group("/api/v1/credentials"), auditLog)
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/opengovern/og-util/pkg/api"
 	"github.com/opengovern/og-util/pkg/httpserver"
 	"github.com/opengovern/og-util/pkg/vault"
+	"github.com/opengovern/opencomply/pkg/audit"
 	"github.com/opengovern/opencomply/services/integration/api/models"
 	"github.com/opengovern/opencomply/services/integration/db"
 	"go.uber.org/zap"
@@ -31,12 +32,12 @@ func New(
 	}
 }
 
-func (h API) Register(g *echo.Group) {
+func (h API) Register(g *echo.Group, auditLog echo.MiddlewareFunc) {
 	g.GET("", httpserver.AuthorizeHandler(h.List, api.ViewerRole))
 	g.POST("/list", httpserver.AuthorizeHandler(h.CredentialsFilteredList, api.ViewerRole))
-	g.DELETE("/:credentialId", httpserver.AuthorizeHandler(h.Delete, api.EditorRole))
+	g.DELETE("/:credentialId", httpserver.AuthorizeHandler(h.Delete, api.EditorRole), auditLog)
 	g.GET("/:credentialId", httpserver.AuthorizeHandler(h.Get, api.ViewerRole))
-	g.PUT("/:credentialId", httpserver.AuthorizeHandler(h.UpdateCredential, api.ViewerRole))
+	g.PUT("/:credentialId", httpserver.AuthorizeHandler(h.UpdateCredential, api.ViewerRole), auditLog)
 }
 
 // Delete godoc
@@ -152,6 +153,19 @@ func (h API) UpdateCredential(c echo.Context) error {
 		h.logger.Error("failed to get credential", zap.Error(err))
 		return echo.NewHTTPError(http.StatusNotFound, "credential not found")
 	}
+	audit.SetAction(c, "credential.update")
+	audit.SetTarget(c, "credential", credentialId)
+	if before, err := credential.ToApi(false); err == nil {
+		audit.SetBefore(c, before)
+	}
+	updatedFields := make([]string, 0, len(req.Credentials))
+	for k := range req.Credentials {
+		updatedFields = append(updatedFields, k)
+	}
+	audit.SetAfter(c, map[string]any{
+		"description":    req.Description,
+		"updated_fields": updatedFields,
+	})
 
 	mapData, err := h.vault.Decrypt(c.Request().Context(), credential.Secret)
 	if err != nil {
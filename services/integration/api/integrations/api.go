@@ -3,17 +3,16 @@ package integrations
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/goccy/go-yaml"
 	"github.com/google/uuid"
 	"github.com/jackc/pgtype"
-	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/labstack/echo/v4"
 	"github.com/opengovern/og-util/pkg/api"
 	"github.com/opengovern/og-util/pkg/httpserver"
 	"github.com/opengovern/og-util/pkg/integration"
 	"github.com/opengovern/og-util/pkg/steampipe"
 	"github.com/opengovern/og-util/pkg/vault"
+	"github.com/opengovern/opencomply/pkg/audit"
+	"github.com/opengovern/opencomply/pkg/executor"
 	"github.com/opengovern/opencomply/pkg/utils"
 	"github.com/opengovern/opencomply/services/integration/api/models"
 	"github.com/opengovern/opencomply/services/integration/db"
@@ -23,12 +22,8 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
 	"io/ioutil"
-	appsv1 "k8s.io/api/apps/v1"
-	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
 	"os"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sort"
 	"strconv"
 	"strings"
@@ -40,7 +35,7 @@ type API struct {
 	logger        *zap.Logger
 	database      db.Database
 	steampipeConn *steampipe.Database
-	kubeClient    client.Client
+	executor      executor.Executor
 }
 
 const (
@@ -56,38 +51,38 @@ func New(
 	database db.Database,
 	logger *zap.Logger,
 	steampipeConn *steampipe.Database,
-	kubeClien client.Client,
+	executor executor.Executor,
 ) API {
 	return API{
 		vault:         vault,
 		database:      database,
 		logger:        logger.Named("integrations"),
 		steampipeConn: steampipeConn,
-		kubeClient:    kubeClien,
+		executor:      executor,
 	}
 }
 
-func (h API) Register(g *echo.Group) {
+func (h API) Register(g *echo.Group, auditLog echo.MiddlewareFunc) {
 	g.GET("", httpserver.AuthorizeHandler(h.List, api.ViewerRole))
 	g.POST("/list", httpserver.AuthorizeHandler(h.ListByFilters, api.ViewerRole))
-	g.POST("/discover", httpserver.AuthorizeHandler(h.DiscoverIntegrations, api.EditorRole))
-	g.POST("/add", httpserver.AuthorizeHandler(h.AddIntegrations, api.EditorRole))
-	g.PUT("/:IntegrationID/healthcheck", httpserver.AuthorizeHandler(h.IntegrationHealthcheck, api.EditorRole))
-	g.DELETE("/:IntegrationID", httpserver.AuthorizeHandler(h.Delete, api.EditorRole))
+	g.POST("/discover", httpserver.AuthorizeHandler(h.DiscoverIntegrations, api.EditorRole), auditLog)
+	g.POST("/add", httpserver.AuthorizeHandler(h.AddIntegrations, api.EditorRole), auditLog)
+	g.PUT("/:IntegrationID/healthcheck", httpserver.AuthorizeHandler(h.IntegrationHealthcheck, api.EditorRole), auditLog)
+	g.DELETE("/:IntegrationID", httpserver.AuthorizeHandler(h.Delete, api.EditorRole), auditLog)
 	g.GET("/:IntegrationID", httpserver.AuthorizeHandler(h.Get, api.ViewerRole))
-	g.POST("/:IntegrationID", httpserver.AuthorizeHandler(h.Update, api.EditorRole))
+	g.POST("/:IntegrationID", httpserver.AuthorizeHandler(h.Update, api.EditorRole), auditLog)
 	g.GET("/integration-groups", httpserver.AuthorizeHandler(h.ListIntegrationGroups, api.ViewerRole))
 	g.GET("/integration-groups/:integrationGroupName", httpserver.AuthorizeHandler(h.GetIntegrationGroup, api.ViewerRole))
-	g.PUT("/sample/purge", httpserver.AuthorizeHandler(h.PurgeSampleData, api.EditorRole))
+	g.PUT("/sample/purge", httpserver.AuthorizeHandler(h.PurgeSampleData, api.EditorRole), auditLog)
 
 	types := g.Group("/types")
 	types.GET("", httpserver.AuthorizeHandler(h.ListIntegrationTypes, api.ViewerRole))
 	types.GET("/:integrationTypeId", httpserver.AuthorizeHandler(h.GetIntegrationType, api.ViewerRole))
 	types.GET("/:integrationTypeId/ui/spec", httpserver.AuthorizeHandler(h.GetIntegrationTypeUiSpec, api.ViewerRole))
-	types.DELETE("/:integrationTypeId", httpserver.AuthorizeHandler(h.DeleteIntegrationType, api.EditorRole))
-	types.PUT("/:integration_type/enable", httpserver.AuthorizeHandler(h.EnableIntegrationType, api.EditorRole))
-	types.PUT("/:integration_type/disable", httpserver.AuthorizeHandler(h.DisableIntegrationType, api.EditorRole))
-	types.PUT("/:integration_type/upgrade", httpserver.AuthorizeHandler(h.UpgradeIntegrationType, api.EditorRole))
+	types.DELETE("/:integrationTypeId", httpserver.AuthorizeHandler(h.DeleteIntegrationType, api.EditorRole), auditLog)
+	types.PUT("/:integration_type/enable", httpserver.AuthorizeHandler(h.EnableIntegrationType, api.EditorRole), auditLog)
+	types.PUT("/:integration_type/disable", httpserver.AuthorizeHandler(h.DisableIntegrationType, api.EditorRole), auditLog)
+	types.PUT("/:integration_type/upgrade", httpserver.AuthorizeHandler(h.UpgradeIntegrationType, api.EditorRole), auditLog)
 
 	resourceTypes := types.Group("/:integration_type/resource_types")
 	resourceTypes.GET("", httpserver.AuthorizeHandler(h.ListIntegrationTypeResourceTypes, api.ViewerRole))
@@ -533,6 +528,14 @@ func (h API) Delete(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
 	}
 
+	audit.SetAction(c, "integration.delete")
+	audit.SetTarget(c, "integration", IntegrationID.String())
+	if integration, err := h.database.GetIntegration(IntegrationID); err == nil && integration != nil {
+		if before, err := integration.ToApi(); err == nil {
+			audit.SetBefore(c, before)
+		}
+	}
+
 	err = h.database.DeleteIntegration(IntegrationID)
 	if err != nil {
 		h.logger.Error("failed to delete credential", zap.Error(err))
@@ -807,6 +810,11 @@ func (h API) Update(c echo.Context) error {
 		h.logger.Error("failed to get credential", zap.Error(err))
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get credential")
 	}
+	audit.SetAction(c, "integration.update")
+	audit.SetTarget(c, "integration", IntegrationID.String())
+	if before, err := credential.ToApi(false); err == nil {
+		audit.SetBefore(c, before)
+	}
 
 	credentials, err := h.vault.Decrypt(c.Request().Context(), credential.Secret)
 	if err != nil {
@@ -1131,7 +1139,7 @@ func (h API) GetIntegrationTypeUiSpec(c echo.Context) error {
 func (h API) EnableIntegrationType(c echo.Context) error {
 	integrationTypeName := c.Param("integration_type")
 
-	err := EnableIntegrationType(c.Request().Context(), h.logger, h.kubeClient, h.database, integrationTypeName)
+	err := EnableIntegrationType(c.Request().Context(), h.logger, h.executor, h.database, integrationTypeName)
 	if err != nil {
 		return err
 	}
@@ -1171,83 +1179,17 @@ func (h API) DisableIntegrationType(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "integration type contains integrations, you can not disable it")
 	}
 
-	currentNamespace, ok := os.LookupEnv("CURRENT_NAMESPACE")
-	if !ok {
-		return echo.NewHTTPError(http.StatusInternalServerError, "current namespace lookup failed")
-	}
 	integrationType, ok := integration_type.IntegrationTypes[integration.Type(integrationTypeName)]
 	if !ok {
 		return echo.NewHTTPError(http.StatusNotFound, "invalid integration type")
 	}
 	cnf := integrationType.GetConfiguration()
 
-	// Scheduled deployment
-	var describerDeployment appsv1.Deployment
-	err = h.kubeClient.Get(ctx, client.ObjectKey{
-		Namespace: currentNamespace,
-		Name:      cnf.DescriberDeploymentName,
-	}, &describerDeployment)
-	if err != nil {
-		h.logger.Error("failed to get manual deployment", zap.Error(err))
-	} else {
-		err = h.kubeClient.Delete(ctx, &describerDeployment)
-		if err != nil {
-			h.logger.Error("failed to delete deployment", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete deployment")
-		}
-	}
-
-	// Manual deployment
-	var describerDeploymentManuals appsv1.Deployment
-	err = h.kubeClient.Get(ctx, client.ObjectKey{
-		Namespace: currentNamespace,
-		Name:      cnf.DescriberDeploymentName + "-manuals",
-	}, &describerDeploymentManuals)
-	if err != nil {
-		h.logger.Error("failed to get manual deployment", zap.Error(err))
-	} else {
-		err = h.kubeClient.Delete(ctx, &describerDeploymentManuals)
-		if err != nil {
-			h.logger.Error("failed to delete manual deployment", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete manual deployment")
-		}
-	}
-
-	kedaEnabled, ok := os.LookupEnv("KEDA_ENABLED")
-	if !ok {
-		kedaEnabled = "false"
-	}
-	if strings.ToLower(kedaEnabled) == "true" {
-		// Scheduled ScaledObject
-		var describerScaledObject kedav1alpha1.ScaledObject
-		err = h.kubeClient.Get(ctx, client.ObjectKey{
-			Namespace: currentNamespace,
-			Name:      cnf.DescriberDeploymentName + "-scaled-object",
-		}, &describerScaledObject)
-		if err != nil {
-			h.logger.Error("failed to get scaled object", zap.Error(err))
-		} else {
-			err = h.kubeClient.Delete(ctx, &describerScaledObject)
-			if err != nil {
-				h.logger.Error("failed to delete scaled object", zap.Error(err))
-				return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete scaled object")
-			}
-		}
-
-		// Manual ScaledObject
-		var describerScaledObjectManuals kedav1alpha1.ScaledObject
-		err = h.kubeClient.Get(ctx, client.ObjectKey{
-			Namespace: currentNamespace,
-			Name:      cnf.DescriberDeploymentName + "-manuals-scaled-object",
-		}, &describerScaledObjectManuals)
+	for _, name := range []string{cnf.DescriberDeploymentName, cnf.DescriberDeploymentName + "-manuals"} {
+		err = h.executor.DeleteWorker(ctx, name)
 		if err != nil {
-			h.logger.Error("failed to get manual scaled object", zap.Error(err))
-		} else {
-			err = h.kubeClient.Delete(ctx, &describerScaledObjectManuals)
-			if err != nil {
-				h.logger.Error("failed to delete manual scaled object", zap.Error(err))
-				return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete manual scaled object")
-			}
+			h.logger.Error("failed to delete describer worker", zap.String("worker", name), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete describer worker")
 		}
 	}
 
@@ -1318,13 +1260,6 @@ func (h API) UpgradeIntegrationType(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "the integration type is not enabled")
 	}
 
-	var integrationTypes []integration.Type
-	integrationTypes = append(integrationTypes, integration.Type(integrationTypeName))
-
-	currentNamespace, ok := os.LookupEnv("CURRENT_NAMESPACE")
-	if !ok {
-		return echo.NewHTTPError(http.StatusInternalServerError, "current namespace lookup failed")
-	}
 	integrationType, ok := integration_type.IntegrationTypes[integration.Type(integrationTypeName)]
 	if !ok {
 		return echo.NewHTTPError(http.StatusNotFound, "invalid integration type")
@@ -1337,101 +1272,18 @@ func (h API) UpgradeIntegrationType(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get integration type")
 	}
 
-	// Scheduled deployment
-	var describerDeployment appsv1.Deployment
-	err = h.kubeClient.Get(ctx, client.ObjectKey{
-		Namespace: currentNamespace,
-		Name:      cnf.DescriberDeploymentName,
-	}, &describerDeployment)
-	if err != nil {
-		h.logger.Error("failed to get deployment", zap.Error(err))
-	} else {
-		err = h.kubeClient.Delete(ctx, &describerDeployment)
-		if err != nil {
-			h.logger.Error("failed to delete deployment", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete deployment")
-		}
-	}
-
-	describerDeployment.ObjectMeta.Name = cnf.DescriberDeploymentName
-	describerDeployment.Spec.Selector.MatchLabels["app"] = cnf.DescriberDeploymentName
-	describerDeployment.Spec.Template.ObjectMeta.Labels["app"] = cnf.DescriberDeploymentName
-	describerDeployment.Spec.Template.Spec.ServiceAccountName = "og-describer"
-
-	container := describerDeployment.Spec.Template.Spec.Containers[0]
-	container.Name = cnf.DescriberDeploymentName
-	container.Image = fmt.Sprintf("%s:%s", integrationTypeInfo.PackageURL, integrationTypeInfo.PackageTag)
-	container.Command = []string{cnf.DescriberRunCommand}
-	describerDeployment.Spec.Template.Spec.Containers[0] = container
-
-	newDeployment := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      cnf.DescriberDeploymentName,
-			Namespace: currentNamespace,
-			Labels: map[string]string{
-				"app": cnf.DescriberDeploymentName,
-			},
-		},
-		Spec: describerDeployment.Spec,
-	}
-
-	err = h.kubeClient.Create(ctx, &newDeployment)
-	if err != nil {
-		return err
-	}
-
-	// Manual deployment
-	var describerDeploymentManuals appsv1.Deployment
-	err = h.kubeClient.Get(ctx, client.ObjectKey{
-		Namespace: currentNamespace,
-		Name:      cnf.DescriberDeploymentName + "-manuals",
-	}, &describerDeploymentManuals)
-	if err != nil {
-		h.logger.Error("failed to get manual deployment", zap.Error(err))
-	} else {
-		err = h.kubeClient.Delete(ctx, &describerDeploymentManuals)
+	for _, worker := range describerWorkers(cnf, integrationTypeInfo) {
+		err = h.executor.EnsureWorker(ctx, worker)
 		if err != nil {
-			h.logger.Error("failed to delete manual deployment", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete manual deployment")
+			h.logger.Error("failed to upgrade describer worker", zap.String("worker", worker.Name), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to upgrade describer worker")
 		}
 	}
 
-	describerDeploymentManuals.ObjectMeta.Name = cnf.DescriberDeploymentName + "-manuals"
-	describerDeploymentManuals.Spec.Selector.MatchLabels["app"] = cnf.DescriberDeploymentName + "-manuals"
-	describerDeploymentManuals.Spec.Template.ObjectMeta.Labels["app"] = cnf.DescriberDeploymentName + "-manuals"
-	describerDeploymentManuals.Spec.Template.Spec.ServiceAccountName = "og-describer"
-
-	containerManuals := describerDeploymentManuals.Spec.Template.Spec.Containers[0]
-	containerManuals.Name = cnf.DescriberDeploymentName
-	containerManuals.Image = fmt.Sprintf("%s:%s", integrationTypeInfo.PackageURL, integrationTypeInfo.PackageTag)
-	containerManuals.Command = []string{cnf.DescriberRunCommand}
-	describerDeploymentManuals.Spec.Template.Spec.Containers[0] = containerManuals
-
-	newDeploymentManuals := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      cnf.DescriberDeploymentName + "-manuals",
-			Namespace: currentNamespace,
-			Labels: map[string]string{
-				"app": cnf.DescriberDeploymentName + "-manuals",
-			},
-		},
-		Spec: describerDeploymentManuals.Spec,
-	}
-
-	err = h.kubeClient.Create(ctx, &newDeploymentManuals)
-	if err != nil {
-		return err
-	}
-
 	return c.NoContent(http.StatusOK)
 }
 
-func EnableIntegrationType(ctx context.Context, logger *zap.Logger, kubeClient client.Client, database db.Database, integrationTypeName string) error {
-	currentNamespace, ok := os.LookupEnv("CURRENT_NAMESPACE")
-	if !ok {
-		return echo.NewHTTPError(http.StatusInternalServerError, "current namespace lookup failed")
-	}
-
+func EnableIntegrationType(ctx context.Context, logger *zap.Logger, workerExecutor executor.Executor, database db.Database, integrationTypeName string) error {
 	setup, _ := database.GetIntegrationTypeSetup(integrationTypeName)
 	if setup != nil {
 		if setup.Enabled {
@@ -1443,31 +1295,6 @@ func EnableIntegrationType(ctx context.Context, logger *zap.Logger, kubeClient c
 		logger.Error("failed to get integration type", zap.Error(err))
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get integration type")
 	}
-	kedaEnabled, ok := os.LookupEnv("KEDA_ENABLED")
-	if !ok {
-		kedaEnabled = "false"
-	}
-
-	// Scheduled deployment
-	var describerDeployment appsv1.Deployment
-	templateDeploymentFile, err := os.Open(TemplateDeploymentPath)
-	if err != nil {
-		logger.Error("failed to open template deployment file", zap.Error(err))
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to open template deployment file")
-	}
-	defer templateDeploymentFile.Close()
-
-	data, err := ioutil.ReadAll(templateDeploymentFile)
-	if err != nil {
-		logger.Error("failed to read template deployment file", zap.Error(err))
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read template deployment file")
-	}
-
-	err = yaml.Unmarshal(data, &describerDeployment)
-	if err != nil {
-		logger.Error("failed to unmarshal template deployment file", zap.Error(err))
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to unmarshal template deployment file")
-	}
 
 	integrationType, ok := integration_type.IntegrationTypes[integration.Type(integrationTypeName)]
 	if !ok {
@@ -1475,190 +1302,10 @@ func EnableIntegrationType(ctx context.Context, logger *zap.Logger, kubeClient c
 	}
 	cnf := integrationType.GetConfiguration()
 
-	describerDeployment.ObjectMeta.Name = cnf.DescriberDeploymentName
-	describerDeployment.ObjectMeta.Namespace = currentNamespace
-	if kedaEnabled == "true" {
-		describerDeployment.Spec.Replicas = aws.Int32(0)
-	} else {
-		describerDeployment.Spec.Replicas = aws.Int32(5)
-	}
-	describerDeployment.Spec.Selector.MatchLabels["app"] = cnf.DescriberDeploymentName
-	describerDeployment.Spec.Template.ObjectMeta.Labels["app"] = cnf.DescriberDeploymentName
-	describerDeployment.Spec.Template.Spec.ServiceAccountName = "og-describer"
-
-	container := describerDeployment.Spec.Template.Spec.Containers[0]
-	container.Name = cnf.DescriberDeploymentName
-	container.Image = fmt.Sprintf("%s:%s", integrationTypeInfo.PackageURL, integrationTypeInfo.PackageTag)
-	container.Command = []string{cnf.DescriberRunCommand}
-	natsUrl, ok := os.LookupEnv("NATS_URL")
-	if ok {
-		container.Env = append(container.Env, v1.EnvVar{
-			Name:  "NATS_URL",
-			Value: natsUrl,
-		})
-	}
-	describerDeployment.Spec.Template.Spec.Containers[0] = container
-
-	newDeployment := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      cnf.DescriberDeploymentName,
-			Namespace: currentNamespace,
-			Labels: map[string]string{
-				"app": cnf.DescriberDeploymentName,
-			},
-		},
-		Spec: describerDeployment.Spec,
-	}
-
-	err = kubeClient.Create(ctx, &newDeployment)
-	if err != nil {
-		return err
-	}
-
-	// Manual deployment
-	var describerDeploymentManuals appsv1.Deployment
-	templateManualsDeploymentFile, err := os.Open(TemplateManualsDeploymentPath)
-	if err != nil {
-		logger.Error("failed to open template manuals deployment file", zap.Error(err))
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to open template manuals deployment file")
-	}
-	defer templateManualsDeploymentFile.Close()
-
-	data, err = ioutil.ReadAll(templateManualsDeploymentFile)
-	if err != nil {
-		logger.Error("failed to read template manuals deployment file", zap.Error(err))
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read template manuals deployment file")
-	}
-
-	err = yaml.Unmarshal(data, &describerDeploymentManuals)
-	if err != nil {
-		logger.Error("failed to unmarshal template manuals deployment file", zap.Error(err))
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to unmarshal template manuals deployment file")
-	}
-
-	describerDeploymentManuals.ObjectMeta.Name = cnf.DescriberDeploymentName + "-manuals"
-	describerDeploymentManuals.ObjectMeta.Namespace = currentNamespace
-	if kedaEnabled == "true" {
-		describerDeploymentManuals.Spec.Replicas = aws.Int32(0)
-	} else {
-		describerDeploymentManuals.Spec.Replicas = aws.Int32(2)
-	}
-	describerDeploymentManuals.Spec.Selector.MatchLabels["app"] = cnf.DescriberDeploymentName + "-manuals"
-	describerDeploymentManuals.Spec.Template.ObjectMeta.Labels["app"] = cnf.DescriberDeploymentName + "-manuals"
-	describerDeploymentManuals.Spec.Template.Spec.ServiceAccountName = "og-describer"
-
-	containerManuals := describerDeploymentManuals.Spec.Template.Spec.Containers[0]
-	containerManuals.Name = cnf.DescriberDeploymentName
-	containerManuals.Image = fmt.Sprintf("%s:%s", integrationTypeInfo.PackageURL, integrationTypeInfo.PackageTag)
-	containerManuals.Command = []string{cnf.DescriberRunCommand}
-	natsUrl, ok = os.LookupEnv("NATS_URL")
-	if ok {
-		containerManuals.Env = append(containerManuals.Env, v1.EnvVar{
-			Name:  "NATS_URL",
-			Value: natsUrl,
-		})
-	}
-	describerDeploymentManuals.Spec.Template.Spec.Containers[0] = containerManuals
-
-	newDeploymentManuals := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      cnf.DescriberDeploymentName + "-manuals",
-			Namespace: currentNamespace,
-			Labels: map[string]string{
-				"app": cnf.DescriberDeploymentName + "-manuals",
-			},
-		},
-		Spec: describerDeploymentManuals.Spec,
-	}
-
-	err = kubeClient.Create(ctx, &newDeploymentManuals)
-	if err != nil {
-		return err
-	}
-
-	if strings.ToLower(kedaEnabled) == "true" {
-		// Scheduled ScaledObject
-		var describerScaledObject kedav1alpha1.ScaledObject
-		templateScaledObjectFile, err := os.Open(TemplateScaledObjectPath)
-		if err != nil {
-			logger.Error("failed to open template scaledobject file", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to open template scaledobject file")
-		}
-		defer templateScaledObjectFile.Close()
-
-		data, err = ioutil.ReadAll(templateScaledObjectFile)
-		if err != nil {
-			logger.Error("failed to read template manuals deployment file", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to read template scaledobject file")
-		}
-
-		err = yaml.Unmarshal(data, &describerScaledObject)
-		if err != nil {
-			logger.Error("failed to unmarshal template deployment file", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to unmarshal template deployment file")
-		}
-
-		describerScaledObject.Spec.ScaleTargetRef.Name = cnf.DescriberDeploymentName
-
-		trigger := describerScaledObject.Spec.Triggers[0]
-		trigger.Metadata["stream"] = cnf.NatsStreamName
-		soNatsUrl, _ := os.LookupEnv("SCALED_OBJECT_NATS_URL")
-		trigger.Metadata["natsServerMonitoringEndpoint"] = soNatsUrl
-		trigger.Metadata["consumer"] = cnf.NatsConsumerGroup + "-service"
-		describerScaledObject.Spec.Triggers[0] = trigger
-
-		newScaledObject := kedav1alpha1.ScaledObject{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      cnf.DescriberDeploymentName + "-scaled-object",
-				Namespace: currentNamespace,
-			},
-			Spec: describerScaledObject.Spec,
-		}
-
-		err = kubeClient.Create(ctx, &newScaledObject)
-		if err != nil {
-			return err
-		}
-
-		// Manual ScaledObject
-		var describerScaledObjectManuals kedav1alpha1.ScaledObject
-		templateManualsScaledObjectFile, err := os.Open(TemplateManualsScaledObjectPath)
-		if err != nil {
-			logger.Error("failed to open template manuals scaledobject file", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to open template manuals scaledobject file")
-		}
-		defer templateManualsScaledObjectFile.Close()
-
-		data, err = ioutil.ReadAll(templateManualsScaledObjectFile)
-		if err != nil {
-			logger.Error("failed to read template manuals deployment file", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to read template manuals scaledobject file")
-		}
-
-		err = yaml.Unmarshal(data, &describerScaledObjectManuals)
-		if err != nil {
-			logger.Error("failed to unmarshal template deployment file", zap.Error(err))
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to unmarshal template deployment file")
-		}
-
-		describerScaledObjectManuals.Spec.ScaleTargetRef.Name = cnf.DescriberDeploymentName + "-manuals"
-
-		triggerManuals := describerScaledObjectManuals.Spec.Triggers[0]
-		triggerManuals.Metadata["stream"] = cnf.NatsStreamName
-		triggerManuals.Metadata["natsServerMonitoringEndpoint"] = soNatsUrl
-		triggerManuals.Metadata["consumer"] = cnf.NatsConsumerGroupManuals + "-service"
-		describerScaledObjectManuals.Spec.Triggers[0] = triggerManuals
-
-		newScaledObjectManuals := kedav1alpha1.ScaledObject{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      cnf.DescriberDeploymentName + "-manuals-scaled-object",
-				Namespace: currentNamespace,
-			},
-			Spec: describerScaledObjectManuals.Spec,
-		}
-
-		err = kubeClient.Create(ctx, &newScaledObjectManuals)
+	for _, worker := range describerWorkers(cnf, integrationTypeInfo) {
+		err = workerExecutor.EnsureWorker(ctx, worker)
 		if err != nil {
+			logger.Error("failed to start describer worker", zap.String("worker", worker.Name), zap.Error(err))
 			return err
 		}
 	}
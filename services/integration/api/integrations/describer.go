@@ -0,0 +1,80 @@
+package integrations
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opengovern/opencomply/pkg/executor"
+	"github.com/opengovern/opencomply/services/integration/integration-type/interfaces"
+	"github.com/opengovern/opencomply/services/integration/models"
+)
+
+const (
+	describerServiceAccountName = "og-describer"
+	scheduledDescriberReplicas  = 5
+	manualDescriberReplicas     = 2
+)
+
+// describerWorkers returns the scheduled and the manual describer workers of the integration type
+func describerWorkers(cnf interfaces.IntegrationConfiguration, integrationTypeInfo *models.IntegrationType) []executor.WorkerSpec {
+	kedaEnabled := strings.ToLower(os.Getenv("KEDA_ENABLED")) == "true"
+
+	var env []executor.EnvVar
+	if natsUrl, ok := os.LookupEnv("NATS_URL"); ok {
+		env = append(env, executor.EnvVar{
+			Name:  "NATS_URL",
+			Value: natsUrl,
+		})
+	}
+
+	workers := []struct {
+		name             string
+		consumer         string
+		replicas         int32
+		deploymentPath   string
+		scaledObjectPath string
+	}{
+		{
+			name:             cnf.DescriberDeploymentName,
+			consumer:         cnf.NatsConsumerGroup,
+			replicas:         scheduledDescriberReplicas,
+			deploymentPath:   TemplateDeploymentPath,
+			scaledObjectPath: TemplateScaledObjectPath,
+		},
+		{
+			name:             cnf.DescriberDeploymentName + "-manuals",
+			consumer:         cnf.NatsConsumerGroupManuals,
+			replicas:         manualDescriberReplicas,
+			deploymentPath:   TemplateManualsDeploymentPath,
+			scaledObjectPath: TemplateManualsScaledObjectPath,
+		},
+	}
+
+	var specs []executor.WorkerSpec
+	for _, w := range workers {
+		spec := executor.WorkerSpec{
+			Name:     w.name,
+			Image:    fmt.Sprintf("%s:%s", integrationTypeInfo.PackageURL, integrationTypeInfo.PackageTag),
+			Command:  []string{cnf.DescriberRunCommand},
+			Env:      env,
+			Replicas: w.replicas,
+			Kubernetes: executor.KubernetesSpec{
+				ServiceAccountName:     describerServiceAccountName,
+				DeploymentTemplatePath: w.deploymentPath,
+			},
+		}
+
+		if kedaEnabled {
+			spec.Autoscale = &executor.ScaleSpec{
+				Stream:   cnf.NatsStreamName,
+				Consumer: w.consumer,
+			}
+			spec.Kubernetes.ScaledObjectTemplatePath = w.scaledObjectPath
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/opengovern/og-util/pkg/postgres"
 	"github.com/opengovern/og-util/pkg/steampipe"
 	"github.com/opengovern/og-util/pkg/vault"
+	"github.com/opengovern/opencomply/pkg/audit"
+	"github.com/opengovern/opencomply/pkg/executor"
 	"github.com/opengovern/opencomply/services/integration/api"
 	"github.com/opengovern/opencomply/services/integration/config"
 	"github.com/opengovern/opencomply/services/integration/db"
@@ -118,7 +120,15 @@ func Command() *cobra.Command {
 				return fmt.Errorf("new steampipe client: %w", err)
 			}
 			logger.Info("Connected to the steampipe database", zap.String("database", cnf.Steampipe.DB))
-			kubeClient, err := NewKubeClient()
+			var kubeClient client.Client
+			if !cnf.Executor.IsLocal() {
+				kubeClient, err = NewKubeClient()
+				if err != nil {
+					return err
+				}
+			}
+			currentNamespace, _ := os.LookupEnv("CURRENT_NAMESPACE")
+			workerExecutor, err := executor.New(cnf.Executor, logger, kubeClient, currentNamespace)
 			if err != nil {
 				return err
 			}
@@ -137,7 +147,7 @@ func Command() *cobra.Command {
 				}
 				//if name == integration_type.IntegrationTypeAWSAccount || name == integration_type.IntegrationTypeGithubAccount ||
 				//	name == integration_type.IntegrationTypeOpenAIIntegration {
-				//	err = integrations.EnableIntegrationType(ctx, logger, kubeClient, db, name.String())
+				//	err = integrations.EnableIntegrationType(ctx, logger, workerExecutor, db, name.String())
 				//	if err != nil {
 				//		return err
 				//	}
@@ -148,7 +158,7 @@ func Command() *cobra.Command {
 				cmd.Context(),
 				logger,
 				cnf.Http.Address,
-				api.New(logger, db, vaultSc, steampipeConn, kubeClient),
+				api.New(logger, db, vaultSc, steampipeConn, workerExecutor, audit.NewRecorder(logger, "integration", cnf.EsSink.BaseURL)),
 			)
 		},
 	}
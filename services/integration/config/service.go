@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/opengovern/og-util/pkg/koanf"
 	"github.com/opengovern/og-util/pkg/vault"
+	"github.com/opengovern/opencomply/pkg/executor"
 )
 
 type IntegrationConfig struct {
@@ -11,4 +12,6 @@ type IntegrationConfig struct {
 	Http      koanf.HttpServer            `json:"http,omitempty" koanf:"http"`
 	Vault     vault.Config                `json:"vault,omitempty" koanf:"vault"`
 	Metadata  koanf.OpenGovernanceService `json:"metadata,omitempty" koanf:"metadata"`
+	EsSink    koanf.OpenGovernanceService `json:"es_sink,omitempty" koanf:"es_sink"`
+	Executor  executor.Config             `json:"executor,omitempty" koanf:"executor"`
 }
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	// CredentialTypeAccessKey uses a static access key pair, it is the default for credentials without a type
+	// unless they only have a web identity role
+	CredentialTypeAccessKey = "access_key"
+	// CredentialTypeWebIdentity exchanges the projected service account token of the workload (IRSA) for the
+	// credentials of a role
+	CredentialTypeWebIdentity = "web_identity"
+	// CredentialTypeDefaultChain uses the ambient credentials of the workload: environment, shared config,
+	// web identity, EKS pod identity, ECS task role or EC2 instance role
+	CredentialTypeDefaultChain = "default_chain"
+)
+
+const (
+	DefaultRegion = "us-east-2"
+
+	webIdentitySessionName = "opencomply-web-identity"
+)
+
+// Credentials are the base credentials of an AWS integration, roles are assumed on top of them
+type Credentials struct {
+	Type            string
+	AccessKeyID     string
+	SecretAccessKey string
+	// WebIdentityRoleARN defaults to AWS_ROLE_ARN. The token is always read from AWS_WEB_IDENTITY_TOKEN_FILE, the
+	// file is not part of the integration so that users can not make the service read arbitrary files.
+	WebIdentityRoleARN string
+}
+
+// EffectiveType returns the type of the credentials, inferring it when it is not set
+func (c Credentials) EffectiveType() string {
+	if c.Type != "" {
+		return c.Type
+	}
+	if c.AccessKeyID == "" && c.WebIdentityRoleARN != "" {
+		return CredentialTypeWebIdentity
+	}
+	return CredentialTypeAccessKey
+}
+
+func (c Credentials) webIdentity() (string, string) {
+	roleARN := c.WebIdentityRoleARN
+	if roleARN == "" {
+		roleARN = os.Getenv("AWS_ROLE_ARN")
+	}
+	return roleARN, os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+}
+
+func (c Credentials) Validate() error {
+	switch c.EffectiveType() {
+	case CredentialTypeAccessKey:
+		if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+			return fmt.Errorf("AccessKeyID and SecretAccessKey must be provided")
+		}
+	case CredentialTypeWebIdentity:
+		roleARN, tokenFile := c.webIdentity()
+		if roleARN == "" || tokenFile == "" {
+			return fmt.Errorf("web identity role ARN and AWS_WEB_IDENTITY_TOKEN_FILE must be provided")
+		}
+	case CredentialTypeDefaultChain:
+	default:
+		return fmt.Errorf("invalid credential type %s", c.Type)
+	}
+	return nil
+}
+
+// NewConfig returns an AWS config in the region using the base credentials
+func NewConfig(ctx context.Context, creds Credentials, region string) (aws.Config, error) {
+	if region == "" {
+		region = DefaultRegion
+	}
+	if err := creds.Validate(); err != nil {
+		return aws.Config{}, err
+	}
+
+	switch creds.EffectiveType() {
+	case CredentialTypeWebIdentity:
+		roleARN, tokenFile := creds.webIdentity()
+		// AssumeRoleWithWebIdentity is not signed, the STS client does not need credentials
+		stsClient := sts.New(sts.Options{Region: region})
+		provider := stscreds.NewWebIdentityRoleProvider(stsClient, roleARN, stscreds.IdentityTokenFile(tokenFile),
+			func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = webIdentitySessionName
+			})
+		return aws.Config{
+			Region:      region,
+			Credentials: aws.NewCredentialsCache(provider),
+		}, nil
+	case CredentialTypeDefaultChain:
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+		if err != nil {
+			return aws.Config{}, fmt.Errorf("failed to load default credential chain: %w", err)
+		}
+		return cfg, nil
+	default:
+		return aws.Config{
+			Region: region,
+			Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
+				creds.AccessKeyID,
+				creds.SecretAccessKey,
+				"",
+			)),
+		}, nil
+	}
+}
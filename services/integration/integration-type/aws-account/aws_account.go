@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/jackc/pgtype"
+	"github.com/opengovern/opencomply/services/integration/integration-type/aws-account/auth"
 	awsDescriberLocal "github.com/opengovern/opencomply/services/integration/integration-type/aws-account/configs"
 	"github.com/opengovern/opencomply/services/integration/integration-type/aws-account/discovery"
 	"github.com/opengovern/opencomply/services/integration/integration-type/aws-account/healthcheck"
 	labelsPackage "github.com/opengovern/opencomply/services/integration/integration-type/aws-account/labels"
 	"github.com/opengovern/opencomply/services/integration/integration-type/interfaces"
 	"github.com/opengovern/opencomply/services/integration/models"
+	"go.uber.org/zap"
 	"golang.org/x/net/context"
 	"strconv"
 )
@@ -41,8 +43,10 @@ func (i *AwsCloudAccountIntegration) HealthCheck(jsonData []byte, providerId str
 	}
 
 	return healthcheck.AWSIntegrationHealthCheck(healthcheck.AWSConfigInput{
+		CredentialType:           credentials.CredentialType,
 		AccessKeyID:              credentials.AwsAccessKeyID,
 		SecretAccessKey:          credentials.AwsSecretAccessKey,
+		WebIdentityRoleARN:       credentials.WebIdentityRoleARN,
 		RoleNameInPrimaryAccount: credentials.RoleToAssumeInMainAccount,
 		CrossAccountRoleARN:      labels["CrossAccountRoleARN"],
 		ExternalID:               credentials.ExternalID,
@@ -57,13 +61,28 @@ func (i *AwsCloudAccountIntegration) DiscoverIntegrations(jsonData []byte) ([]mo
 		return nil, err
 	}
 
+	// the describer reads the credential type from the labels, the credentials themselves are passed as is
+	credentialType := auth.Credentials{
+		Type:               credentials.CredentialType,
+		AccessKeyID:        credentials.AwsAccessKeyID,
+		WebIdentityRoleARN: credentials.WebIdentityRoleARN,
+	}.EffectiveType()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return nil, err
+	}
+
 	var integrations []models.Integration
 	accounts := discovery.AWSIntegrationDiscovery(discovery.Config{
+		CredentialType:                credentials.CredentialType,
 		AWSAccessKeyID:                credentials.AwsAccessKeyID,
 		AWSSecretAccessKey:            credentials.AwsSecretAccessKey,
+		WebIdentityRoleARN:            credentials.WebIdentityRoleARN,
 		RoleNameToAssumeInMainAccount: credentials.RoleToAssumeInMainAccount,
 		CrossAccountRoleName:          credentials.CrossAccountRoleName,
 		ExternalID:                    credentials.ExternalID,
+		Logger:                        logger.Named("aws-account-discovery"),
 	})
 	for _, a := range accounts {
 		if a.Details.Error != "" {
@@ -71,8 +90,10 @@ func (i *AwsCloudAccountIntegration) DiscoverIntegrations(jsonData []byte) ([]mo
 		}
 
 		isOrganizationMaster, err := labelsPackage.IsOrganizationMasterAccount(ctx, labelsPackage.AWSConfigInput{
+			CredentialType:           credentials.CredentialType,
 			AccessKeyID:              credentials.AwsAccessKeyID,
 			SecretAccessKey:          credentials.AwsSecretAccessKey,
+			WebIdentityRoleARN:       credentials.WebIdentityRoleARN,
 			RoleNameInPrimaryAccount: credentials.RoleToAssumeInMainAccount,
 			CrossAccountRoleARN:      a.Labels.CrossAccountRoleARN,
 			ExternalID:               credentials.ExternalID,
@@ -84,6 +105,7 @@ func (i *AwsCloudAccountIntegration) DiscoverIntegrations(jsonData []byte) ([]mo
 			"CrossAccountRoleARN":                 a.Labels.CrossAccountRoleARN,
 			"ExternalID":                          a.Labels.ExternalID,
 			"integration/aws/organization-master": strconv.FormatBool(isOrganizationMaster),
+			"integration/aws/credential-type":     credentialType,
 		}
 		labelsJsonData, err := json.Marshal(labels)
 		if err != nil {
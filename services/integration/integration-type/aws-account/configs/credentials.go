@@ -1,8 +1,11 @@
 package configs
 
 type IntegrationCredentials struct {
+	// CredentialType is access_key (default), web_identity or default_chain
+	CredentialType            string `json:"credential_type,omitempty"`
 	AwsAccessKeyID            string `json:"aws_access_key_id"`
 	AwsSecretAccessKey        string `json:"aws_secret_access_key"`
+	WebIdentityRoleARN        string `json:"web_identity_role_arn,omitempty"`
 	CrossAccountRoleName      string `json:"cross_account_role_name"`
 	RoleToAssumeInMainAccount string `json:"role_to_assume_in_main_account"`
 	ExternalID                string `json:"external_id"`
@@ -12,6 +12,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	organizationsTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/opengovern/opencomply/services/integration/integration-type/aws-account/auth"
+	"go.uber.org/zap"
 )
 
 // DefaultMaxAccounts is the default maximum number of accounts to retrieve.
@@ -19,12 +21,33 @@ const DefaultMaxAccounts = 500
 
 // Config represents the configuration loaded from the JSON file.
 type Config struct {
+	CredentialType                string `json:"credential_type,omitempty"`
 	AWSAccessKeyID                string `json:"aws_access_key_id"`
 	AWSSecretAccessKey            string `json:"aws_secret_access_key"`
+	WebIdentityRoleARN            string `json:"web_identity_role_arn,omitempty"`
 	RoleNameToAssumeInMainAccount string `json:"role_name_to_assume_in_main_account,omitempty"`
 	CrossAccountRoleName          string `json:"cross_account_role_name,omitempty"`
 	ExternalID                    string `json:"external_id,omitempty"`
 	MaxAccounts                   int    `json:"max_accounts,omitempty"`
+
+	Logger *zap.Logger `json:"-"`
+}
+
+func (cfg Config) logger() *zap.Logger {
+	if cfg.Logger == nil {
+		return zap.NewNop()
+	}
+	return cfg.Logger
+}
+
+// baseCredentials returns the credentials the roles are assumed with
+func (cfg Config) baseCredentials() auth.Credentials {
+	return auth.Credentials{
+		Type:               cfg.CredentialType,
+		AccessKeyID:        cfg.AWSAccessKeyID,
+		SecretAccessKey:    cfg.AWSSecretAccessKey,
+		WebIdentityRoleARN: cfg.WebIdentityRoleARN,
+	}
 }
 
 // AccountLabels holds the labels associated with each account.
@@ -81,18 +104,11 @@ func AWSIntegrationDiscovery(cfg Config) []AccountResult {
 
 // GenerateAWSConfig creates an AWS configuration using the provided credentials.
 // It can assume a role if roleNameToAssume is provided.
-func GenerateAWSConfig(awsAccessKeyID string, awsSecretAccessKey string, roleNameToAssume string, externalID string, accountID string) (aws.Config, error) {
-	// Step 1: Create base credentials provider
-	credsProvider := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
-		awsAccessKeyID,
-		awsSecretAccessKey,
-		"",
-	))
-
-	// Step 2: Manually create the AWS Config struct with explicit credentials and region
-	cfg := aws.Config{
-		Region:      "us-east-2",
-		Credentials: credsProvider,
+func GenerateAWSConfig(base auth.Credentials, roleNameToAssume string, externalID string, accountID string) (aws.Config, error) {
+	// Step 1 & 2: Create the AWS Config struct with the base credentials and region
+	cfg, err := auth.NewConfig(context.TODO(), base, auth.DefaultRegion)
+	if err != nil {
+		return aws.Config{}, err
 	}
 
 	// Step 3: If a role is specified to assume, perform the AssumeRole operation
@@ -119,7 +135,7 @@ func GenerateAWSConfig(awsAccessKeyID string, awsSecretAccessKey string, roleNam
 		}
 
 		// Update credentials provider with assumed role credentials
-		credsProvider = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
+		credsProvider := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
 			*assumeRoleOutput.Credentials.AccessKeyId,
 			*assumeRoleOutput.Credentials.SecretAccessKey,
 			*assumeRoleOutput.Credentials.SessionToken,
@@ -244,17 +260,11 @@ func parsePrincipalArn(principalArn string) (entityType string, entityName strin
 func DiscoverOrganizationAccounts(cfg Config) []AccountResult {
 	var results []AccountResult
 
-	// Step 1: Create base credentials provider
-	credsProvider := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
-		cfg.AWSAccessKeyID,
-		cfg.AWSSecretAccessKey,
-		"",
-	))
-
-	// Step 2: Manually create the AWS Config struct
-	initialCfg := aws.Config{
-		Region:      "us-east-2",
-		Credentials: credsProvider,
+	// Step 1 & 2: Create the AWS Config struct with the base credentials
+	initialCfg, err := auth.NewConfig(context.TODO(), cfg.baseCredentials(), auth.DefaultRegion)
+	if err != nil {
+		cfg.logger().Error("Failed to create AWS config", zap.Error(err))
+		return results
 	}
 
 	// Step 3: Get primary account ID using initial configuration
@@ -262,7 +272,7 @@ func DiscoverOrganizationAccounts(cfg Config) []AccountResult {
 
 	identityOutput, err := stsClient.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
 	if err != nil {
-		fmt.Printf("Failed to get caller identity: %v\n", err)
+		cfg.logger().Error("Failed to get caller identity", zap.Error(err))
 		return results
 	}
 
@@ -270,14 +280,13 @@ func DiscoverOrganizationAccounts(cfg Config) []AccountResult {
 
 	// Step 4: Generate AWS Config possibly with role assumption
 	awsCfg, err := GenerateAWSConfig(
-		cfg.AWSAccessKeyID,
-		cfg.AWSSecretAccessKey,
+		cfg.baseCredentials(),
 		cfg.RoleNameToAssumeInMainAccount,
 		cfg.ExternalID,
 		mainAccountID,
 	)
 	if err != nil {
-		fmt.Printf("Failed to generate AWS config: %v\n", err)
+		cfg.logger().Error("Failed to generate AWS config", zap.Error(err))
 		return results
 	}
 
@@ -287,7 +296,7 @@ func DiscoverOrganizationAccounts(cfg Config) []AccountResult {
 	// Attempt to describe the organization
 	_, err = orgClient.DescribeOrganization(context.TODO(), &organizations.DescribeOrganizationInput{})
 	if err != nil {
-		fmt.Printf("This account is not an AWS Organizations management account or lacks permissions: %v\n", err)
+		cfg.logger().Warn("This account is not an AWS Organizations management account or lacks permissions", zap.Error(err))
 		return results
 	}
 
@@ -310,7 +319,7 @@ func DiscoverOrganizationAccounts(cfg Config) []AccountResult {
 	for paginator.HasMorePages() && len(accounts) < maxAccounts {
 		page, err := paginator.NextPage(context.TODO())
 		if err != nil {
-			fmt.Printf("Error listing accounts: %v\n", err)
+			cfg.logger().Error("Error listing accounts", zap.Error(err))
 			break
 		}
 
@@ -457,17 +466,12 @@ func DiscoverOrganizationAccounts(cfg Config) []AccountResult {
 func DiscoverSingleAccount(cfg Config) AccountResult {
 	var result AccountResult
 
-	// Step 1: Create base credentials provider
-	credsProvider := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
-		cfg.AWSAccessKeyID,
-		cfg.AWSSecretAccessKey,
-		"",
-	))
-
-	// Step 2: Manually create the AWS Config struct
-	initialCfg := aws.Config{
-		Region:      "us-east-2",
-		Credentials: credsProvider,
+	// Step 1 & 2: Create the AWS Config struct with the base credentials
+	initialCfg, err := auth.NewConfig(context.TODO(), cfg.baseCredentials(), auth.DefaultRegion)
+	if err != nil {
+		cfg.logger().Error("Failed to create AWS config", zap.Error(err))
+		result.Details.Error = fmt.Sprintf("Failed to create AWS config: %v", err)
+		return result
 	}
 
 	// Step 3: Get primary account ID using initial configuration
@@ -475,7 +479,7 @@ func DiscoverSingleAccount(cfg Config) AccountResult {
 
 	identityOutput, err := stsClient.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
 	if err != nil {
-		fmt.Printf("Failed to get caller identity: %v\n", err)
+		cfg.logger().Error("Failed to get caller identity", zap.Error(err))
 		result.Details.Error = fmt.Sprintf("Failed to get caller identity: %v", err)
 		return result
 	}
@@ -485,14 +489,13 @@ func DiscoverSingleAccount(cfg Config) AccountResult {
 
 	// Step 4: Generate AWS Config possibly with role assumption
 	awsCfg, err := GenerateAWSConfig(
-		cfg.AWSAccessKeyID,
-		cfg.AWSSecretAccessKey,
+		cfg.baseCredentials(),
 		cfg.RoleNameToAssumeInMainAccount,
 		cfg.ExternalID,
 		accountID,
 	)
 	if err != nil {
-		fmt.Printf("Failed to generate AWS config: %v\n", err)
+		cfg.logger().Error("Failed to generate AWS config", zap.Error(err))
 		result.Details.Error = fmt.Sprintf("Failed to generate AWS config: %v", err)
 		return result
 	}
@@ -541,7 +544,7 @@ func DiscoverSingleAccount(cfg Config) AccountResult {
 		stsClient = sts.NewFromConfig(awsCfg)
 		identityOutput, err := stsClient.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
 		if err != nil {
-			fmt.Printf("Failed to get caller identity: %v\n", err)
+			cfg.logger().Error("Failed to get caller identity", zap.Error(err))
 			result.Details.Error = fmt.Sprintf("Failed to get caller identity: %v", err)
 			return result
 		}
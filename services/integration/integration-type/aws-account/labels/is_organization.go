@@ -4,31 +4,42 @@ import (
 	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/smithy-go"
+	"github.com/opengovern/opencomply/services/integration/integration-type/aws-account/auth"
 	"golang.org/x/net/context"
 )
 
 // AWSConfigInput encapsulates all possible AWS credentials and role information.
 type AWSConfigInput struct {
+	CredentialType           string `json:"credential_type"`       // "access_key" (default), "web_identity" or "default_chain"
 	AccessKeyID              string `json:"aws_access_key_id"`     // Changed from "access_key_id"
 	SecretAccessKey          string `json:"aws_secret_access_key"` // Changed from "secret_access_key"
+	WebIdentityRoleARN       string `json:"web_identity_role_arn"`
 	RoleNameInPrimaryAccount string `json:"role_name_in_primary_account"`
 	CrossAccountRoleARN      string `json:"cross_account_role_arn"`
 	ExternalID               string `json:"external_id"`
 	Region                   string `json:"region"`
 }
 
+// BaseCredentials returns the credentials the roles are assumed with
+func (c AWSConfigInput) BaseCredentials() auth.Credentials {
+	return auth.Credentials{
+		Type:               c.CredentialType,
+		AccessKeyID:        c.AccessKeyID,
+		SecretAccessKey:    c.SecretAccessKey,
+		WebIdentityRoleARN: c.WebIdentityRoleARN,
+	}
+}
+
 // IsOrganizationMasterAccount checks if the current AWS account is part of an AWS Organization
 // and if it is the management (master) account of that organization.
 // Returns true if both conditions are met, otherwise false.
 func IsOrganizationMasterAccount(ctx context.Context, creds AWSConfigInput) (bool, error) {
 	cfg, err := GenerateAWSConfig(
-		creds.AccessKeyID,
-		creds.SecretAccessKey,
+		creds.BaseCredentials(),
 		creds.RoleNameInPrimaryAccount,
 		creds.CrossAccountRoleARN,
 		creds.ExternalID,
@@ -89,28 +100,16 @@ func IsOrganizationMasterAccount(ctx context.Context, creds AWSConfigInput) (boo
 // GenerateAWSConfig initializes and returns an AWS configuration based on the provided inputs.
 // It determines whether to perform single or multi-account validation based on the inputs.
 func GenerateAWSConfig(
-	accessKeyID string,
-	secretAccessKey string,
+	base auth.Credentials,
 	roleNameInPrimaryAccount string,
 	crossAccountRoleARN string,
 	externalID string,
 	region string,
 ) (*aws.Config, error) {
-	// Step 1: Set default region if not provided
-	if region == "" {
-		region = "us-east-2"
-	}
-
-	// Step 2: Initialize the base credentials provider
-	if accessKeyID == "" || secretAccessKey == "" {
-		return nil, fmt.Errorf("AccessKeyID and SecretAccessKey must be provided")
-	}
-	baseCredentials := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""))
-
-	// Step 3: Create the AWS Config manually
-	cfg := aws.Config{
-		Region:      region,
-		Credentials: baseCredentials,
+	// Step 1-3: Create the AWS Config with the base credentials, the default region is used if not provided
+	cfg, err := auth.NewConfig(context.TODO(), base, region)
+	if err != nil {
+		return nil, err
 	}
 
 	// Step 4: Determine the type of validation based on provided inputs
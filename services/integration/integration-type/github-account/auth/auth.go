@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/go-github/v66/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// CredentialTypePAT authenticates with a personal access token, it is the default for credentials without a type
+	CredentialTypePAT = "pat"
+	// CredentialTypeApp authenticates as a GitHub App installation
+	CredentialTypeApp = "app"
+)
+
+const (
+	// appJWTLifetime is below the 10 minutes GitHub accepts, leaving room for clock drift
+	appJWTLifetime = 9 * time.Minute
+	// installationTokenRefreshMargin renews installation tokens before they expire
+	installationTokenRefreshMargin = 5 * time.Minute
+)
+
+// Credentials authenticate against github.com or a GitHub Enterprise Server if BaseURL is set
+type Credentials struct {
+	Type       string
+	PatToken   string
+	AppID      string
+	PrivateKey string
+	// BaseURL is the API URL of a GitHub Enterprise Server, e.g. https://github.example.com/api/v3/
+	BaseURL string
+}
+
+// EffectiveType returns the type of the credentials, inferring it when it is not set
+func (c Credentials) EffectiveType() string {
+	if c.Type != "" {
+		return c.Type
+	}
+	if c.PatToken == "" && c.AppID != "" {
+		return CredentialTypeApp
+	}
+	return CredentialTypePAT
+}
+
+func (c Credentials) Validate() error {
+	switch c.EffectiveType() {
+	case CredentialTypePAT:
+		if c.PatToken == "" {
+			return fmt.Errorf("no token provided")
+		}
+	case CredentialTypeApp:
+		if c.AppID == "" || c.PrivateKey == "" {
+			return fmt.Errorf("app id and private key must be provided")
+		}
+	default:
+		return fmt.Errorf("invalid credential type %s", c.Type)
+	}
+	return nil
+}
+
+func newClient(httpClient *http.Client, baseURL string) (*github.Client, error) {
+	client := github.NewClient(httpClient)
+	if baseURL == "" {
+		return client, nil
+	}
+	client, err := client.WithEnterpriseURLs(baseURL, baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	return client, nil
+}
+
+// NewPATClient returns a client authenticated with the personal access token
+func NewPATClient(ctx context.Context, creds Credentials) (*github.Client, error) {
+	if creds.PatToken == "" {
+		return nil, fmt.Errorf("no token provided")
+	}
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: creds.PatToken})
+	return newClient(oauth2.NewClient(ctx, ts), creds.BaseURL)
+}
+
+// appTransport authenticates requests as the GitHub App itself with a short-lived JWT
+type appTransport struct {
+	appID string
+	key   any
+	base  http.RoundTripper
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.StandardClaims{
+		IssuedAt:  now.Add(-time.Minute).Unix(),
+		ExpiresAt: now.Add(appJWTLifetime).Unix(),
+		Issuer:    t.appID,
+	}).SignedString(t.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign app jwt: %w", err)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// NewAppClient returns a client authenticated as the GitHub App, it can only call the app endpoints
+func NewAppClient(creds Credentials) (*github.Client, error) {
+	if creds.AppID == "" || creds.PrivateKey == "" {
+		return nil, fmt.Errorf("app id and private key must be provided")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(strings.TrimSpace(creds.PrivateKey)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid app private key: %w", err)
+	}
+	return newClient(&http.Client{
+		Transport: &appTransport{appID: creds.AppID, key: key, base: http.DefaultTransport},
+		Timeout:   30 * time.Second,
+	}, creds.BaseURL)
+}
+
+// InstallationTokenSource mints installation access tokens, wrapped in an oauth2.ReuseTokenSource they are
+// refreshed shortly before they expire
+type InstallationTokenSource struct {
+	ctx            context.Context
+	appClient      *github.Client
+	installationID int64
+
+	mu          sync.Mutex
+	permissions map[string]string
+}
+
+func NewInstallationTokenSource(ctx context.Context, appClient *github.Client, installationID int64) *InstallationTokenSource {
+	return &InstallationTokenSource{
+		ctx:            ctx,
+		appClient:      appClient,
+		installationID: installationID,
+	}
+}
+
+func (s *InstallationTokenSource) Token() (*oauth2.Token, error) {
+	token, _, err := s.appClient.Apps.CreateInstallationToken(s.ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installation token: %w", err)
+	}
+	permissions, err := PermissionsMap(token.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.permissions = permissions
+	s.mu.Unlock()
+
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "token",
+		Expiry:      token.GetExpiresAt().Add(-installationTokenRefreshMargin),
+	}, nil
+}
+
+// Permissions returns the permissions granted to the last minted token
+func (s *InstallationTokenSource) Permissions() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.permissions
+}
+
+// NewInstallationClient returns a client authenticated as the installation of the GitHub App
+func NewInstallationClient(ctx context.Context, creds Credentials, installationID int64) (*github.Client, *InstallationTokenSource, error) {
+	if installationID == 0 {
+		return nil, nil, fmt.Errorf("installation id must be provided")
+	}
+	appClient, err := NewAppClient(creds)
+	if err != nil {
+		return nil, nil, err
+	}
+	ts := NewInstallationTokenSource(ctx, appClient, installationID)
+	client, err := newClient(oauth2.NewClient(ctx, oauth2.ReuseTokenSource(nil, ts)), creds.BaseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, ts, nil
+}
+
+// PermissionsMap converts the permissions of an installation to a map of permission name to access level
+func PermissionsMap(permissions *github.InstallationPermissions) (map[string]string, error) {
+	result := make(map[string]string)
+	if permissions == nil {
+		return result, nil
+	}
+	b, err := json.Marshal(permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package configs
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	AppPermissionRead  = "read"
+	AppPermissionWrite = "write"
+	AppPermissionAdmin = "admin"
+)
+
+// ResourceTypeAppPermissions are the GitHub App permissions needed to describe each resource type, every type of
+// ResourceTypesList has to be listed
+var ResourceTypeAppPermissions = map[string]map[string]string{
+	"Github/Actions/Artifact":              {"actions": AppPermissionRead},
+	"Github/Actions/Runner":                {"organization_self_hosted_runners": AppPermissionRead},
+	"Github/Actions/Secret":                {"secrets": AppPermissionRead},
+	"Github/Actions/WorkflowRun":           {"actions": AppPermissionRead},
+	"Github/Branch":                        {"contents": AppPermissionRead},
+	"Github/Branch/Protection":             {"administration": AppPermissionRead},
+	"Github/Commit":                        {"contents": AppPermissionRead},
+	"Github/Issue":                         {"issues": AppPermissionRead},
+	"Github/License":                       {"contents": AppPermissionRead},
+	"Github/Organization":                  {"metadata": AppPermissionRead},
+	"Github/Organization/Collaborator":     {"members": AppPermissionRead},
+	"Github/Organization/Dependabot/Alert": {"vulnerability_alerts": AppPermissionRead},
+	"Github/Organization/Member":           {"members": AppPermissionRead},
+	"Github/Organization/Team":             {"members": AppPermissionRead},
+	"Github/PullRequest":                   {"pull_requests": AppPermissionRead},
+	"Github/Release":                       {"contents": AppPermissionRead},
+	"Github/Repository":                    {"metadata": AppPermissionRead, "contents": AppPermissionRead},
+	"Github/Repository/Collaborator":       {"metadata": AppPermissionRead},
+	"Github/Repository/DependabotAlert":    {"vulnerability_alerts": AppPermissionRead},
+	"Github/Repository/Deployment":         {"deployments": AppPermissionRead},
+	"Github/Repository/Environment":        {"environments": AppPermissionRead},
+	"Github/Repository/Ruleset":            {"administration": AppPermissionRead},
+	"Github/Repository/SBOM":               {"contents": AppPermissionRead},
+	"Github/Repository/VulnerabilityAlert": {"vulnerability_alerts": AppPermissionRead},
+	"Github/Tag":                           {"contents": AppPermissionRead},
+	"Github/Team/Member":                   {"members": AppPermissionRead},
+	"Github/User":                          {"metadata": AppPermissionRead},
+	"Github/Workflow":                      {"actions": AppPermissionRead},
+	"Github/Container/Package":             {"packages": AppPermissionRead},
+	"Github/Package/Maven":                 {"packages": AppPermissionRead},
+	"Github/NPM/Package":                   {"packages": AppPermissionRead},
+	"Github/Nuget/Package":                 {"packages": AppPermissionRead},
+	"Github/Artifact/DockerFile":           {"contents": AppPermissionRead},
+}
+
+var appPermissionLevels = map[string]int{
+	AppPermissionRead:  1,
+	AppPermissionWrite: 2,
+	AppPermissionAdmin: 3,
+}
+
+// RequiredAppPermissions merges the app permissions needed by the resource types, keeping the highest level. Resource
+// types without known permissions are an error, an installation can't be checked against them.
+func RequiredAppPermissions(resourceTypes []string) (map[string]string, error) {
+	required := make(map[string]string)
+	for _, resourceType := range resourceTypes {
+		permissions, ok := ResourceTypeAppPermissions[resourceType]
+		if !ok {
+			return nil, fmt.Errorf("no app permissions are known for resource type %s", resourceType)
+		}
+		for permission, level := range permissions {
+			if appPermissionLevels[level] > appPermissionLevels[required[permission]] {
+				required[permission] = level
+			}
+		}
+	}
+	return required, nil
+}
+
+// MissingAppPermissions returns the required permissions that are not granted at the required level, sorted by name
+func MissingAppPermissions(required, granted map[string]string) []string {
+	var missing []string
+	for permission, level := range required {
+		if appPermissionLevels[granted[permission]] < appPermissionLevels[level] {
+			missing = append(missing, permission+":"+level)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,44 @@
+package configs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequiredAppPermissions(t *testing.T) {
+	tests := []struct {
+		name          string
+		resourceTypes []string
+		want          map[string]string
+		wantErr       bool
+	}{
+		{
+			name:          "merged across resource types",
+			resourceTypes: []string{"Github/Repository", "Github/Issue", "Github/Tag"},
+			want:          map[string]string{"metadata": AppPermissionRead, "contents": AppPermissionRead, "issues": AppPermissionRead},
+		},
+		{
+			name:          "unknown resource type",
+			resourceTypes: []string{"Github/Repository", "Github/Unknown"},
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RequiredAppPermissions(tt.resourceTypes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RequiredAppPermissions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RequiredAppPermissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceTypesHaveAppPermissions(t *testing.T) {
+	if _, err := RequiredAppPermissions(ResourceTypesList); err != nil {
+		t.Error(err)
+	}
+}
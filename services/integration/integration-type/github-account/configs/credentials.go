@@ -1,5 +1,13 @@
 package configs
 
 type IntegrationCredentials struct {
-	PatToken string `json:"pat_token"`
+	// CredentialType is pat (default) or app
+	CredentialType string `json:"credential_type,omitempty"`
+	PatToken       string `json:"pat_token"`
+	AppID          string `json:"app_id,omitempty"`
+	// InstallationID limits an app credential to a single installation, all installations are discovered if empty
+	InstallationID string `json:"installation_id,omitempty"`
+	PrivateKey     string `json:"private_key,omitempty"`
+	// BaseURL is the API URL of a GitHub Enterprise Server, github.com is used if empty
+	BaseURL string `json:"base_url,omitempty"`
 }
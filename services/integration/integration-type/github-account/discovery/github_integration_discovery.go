@@ -8,12 +8,27 @@ import (
 	"time"
 
 	"github.com/google/go-github/v66/github"
-	"golang.org/x/oauth2"
+	"github.com/opengovern/opencomply/services/integration/integration-type/github-account/auth"
 )
 
 // Config represents the JSON input configuration
 type Config struct {
-	Token string `json:"token"`
+	CredentialType string `json:"credential_type"`
+	Token          string `json:"token"`
+	AppID          string `json:"app_id"`
+	InstallationID int64  `json:"installation_id"`
+	PrivateKey     string `json:"private_key"`
+	BaseURL        string `json:"base_url"`
+}
+
+func (c Config) credentials() auth.Credentials {
+	return auth.Credentials{
+		Type:       c.CredentialType,
+		PatToken:   c.Token,
+		AppID:      c.AppID,
+		PrivateKey: c.PrivateKey,
+		BaseURL:    c.BaseURL,
+	}
 }
 
 // Output defines the structure of the JSON response.
@@ -27,6 +42,8 @@ type OrgDetail struct {
 	Login string `json:"login,omitempty"`
 	ID    int64  `json:"id,omitempty"`
 	Name  string `json:"name,omitempty"`
+	// InstallationID is the GitHub App installation covering the organization, empty for tokens
+	InstallationID int64 `json:"installation_id,omitempty"`
 }
 
 // Discover retrieves all active GitHub organizations accessible by the token.
@@ -106,26 +123,89 @@ func Discover(ctx context.Context, client *github.Client) ([]*github.Organizatio
 	return activeOrgs, nil
 }
 
+// DiscoverAppInstallations retrieves the organizations the GitHub App is installed on.
+// If installationID is set only that installation is returned.
+func DiscoverAppInstallations(ctx context.Context, appClient *github.Client, installationID int64) ([]*github.Installation, error) {
+	if installationID != 0 {
+		installation, resp, err := appClient.Apps.GetInstallation(ctx, installationID)
+		if err != nil {
+			if resp != nil && resp.StatusCode == 401 {
+				return nil, errors.New("authentication failed: invalid app id or private key")
+			}
+			return nil, err
+		}
+		if installation.GetTargetType() != "Organization" {
+			return nil, fmt.Errorf("installation %d is not installed on an organization", installationID)
+		}
+		return []*github.Installation{installation}, nil
+	}
+
+	var installations []*github.Installation
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := appClient.Apps.ListInstallations(ctx, opts)
+		if err != nil {
+			if resp != nil && resp.StatusCode == 401 {
+				return nil, errors.New("authentication failed: invalid app id or private key")
+			}
+			return nil, err
+		}
+		for _, installation := range page {
+			// Skip installations on personal accounts and suspended installations
+			if installation.GetTargetType() != "Organization" || installation.SuspendedAt != nil {
+				continue
+			}
+			installations = append(installations, installation)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if len(installations) == 0 {
+		return nil, errors.New("no organizations found. app is not installed on any organizations")
+	}
+	return installations, nil
+}
+
 func GithubIntegrationDiscovery(cfg Config) ([]OrgDetail, error) {
-	token := cfg.Token
-	if token == "" {
-		return nil, fmt.Errorf("no token provided")
+	creds := cfg.credentials()
+	if err := creds.Validate(); err != nil {
+		return nil, err
 	}
 
 	// Create a context with timeout to avoid hanging indefinitely
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Create an OAuth2 token source
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-
-	// Create an OAuth2 client
-	tc := oauth2.NewClient(ctx, ts)
+	var detailedOrgs []OrgDetail
+	if creds.EffectiveType() == auth.CredentialTypeApp {
+		appClient, err := auth.NewAppClient(creds)
+		if err != nil {
+			return nil, err
+		}
+		installations, err := DiscoverAppInstallations(ctx, appClient, cfg.InstallationID)
+		if err != nil {
+			return nil, err
+		}
+		for _, installation := range installations {
+			account := installation.GetAccount()
+			detailedOrgs = append(detailedOrgs, OrgDetail{
+				Login:          account.GetLogin(),
+				ID:             account.GetID(),
+				Name:           account.GetName(),
+				InstallationID: installation.GetID(),
+			})
+		}
+		return detailedOrgs, nil
+	}
 
-	// Create a new GitHub client
-	client := github.NewClient(tc)
+	// Create a new GitHub client authenticated with the token
+	client, err := auth.NewPATClient(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get the list of active organizations using Discover
 	orgs, err := Discover(ctx, client)
@@ -134,7 +214,6 @@ func GithubIntegrationDiscovery(cfg Config) ([]OrgDetail, error) {
 	}
 
 	// Prepare the minimal organization information
-	var detailedOrgs []OrgDetail
 	for _, org := range orgs {
 		detail := OrgDetail{
 			Login: safeString(org.Login),
@@ -2,7 +2,9 @@ package github_account
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/jackc/pgtype"
+	"github.com/opengovern/opencomply/services/integration/integration-type/github-account/auth"
 	githubDescriberLocal "github.com/opengovern/opencomply/services/integration/integration-type/github-account/configs"
 	"github.com/opengovern/opencomply/services/integration/integration-type/github-account/discovery"
 	"github.com/opengovern/opencomply/services/integration/integration-type/github-account/healthcheck"
@@ -41,8 +43,24 @@ func (i *GithubAccountIntegration) HealthCheck(jsonData []byte, providerId strin
 	if v, ok := labels["OrganizationName"]; ok {
 		name = v
 	}
+	installationID := credentials.InstallationID
+	if v, ok := labels["InstallationID"]; ok && v != "" {
+		installationID = v
+	}
+	var installationIDValue int64
+	if installationID != "" {
+		installationIDValue, err = strconv.ParseInt(installationID, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid installation id %s: %w", installationID, err)
+		}
+	}
 	isHealthy, err := healthcheck.GithubIntegrationHealthcheck(healthcheck.Config{
+		CredentialType:   credentials.CredentialType,
 		Token:            credentials.PatToken,
+		AppID:            credentials.AppID,
+		InstallationID:   installationIDValue,
+		PrivateKey:       credentials.PrivateKey,
+		BaseURL:          credentials.BaseURL,
 		OrganizationName: name,
 	})
 	return isHealthy, err
@@ -54,9 +72,26 @@ func (i *GithubAccountIntegration) DiscoverIntegrations(jsonData []byte) ([]mode
 	if err != nil {
 		return nil, err
 	}
+	var installationID int64
+	if credentials.InstallationID != "" {
+		installationID, err = strconv.ParseInt(credentials.InstallationID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid installation id %s: %w", credentials.InstallationID, err)
+		}
+	}
+	creds := auth.Credentials{
+		Type:     credentials.CredentialType,
+		PatToken: credentials.PatToken,
+		AppID:    credentials.AppID,
+	}
 	var integrations []models.Integration
 	accounts, err := discovery.GithubIntegrationDiscovery(discovery.Config{
-		Token: credentials.PatToken,
+		CredentialType: credentials.CredentialType,
+		Token:          credentials.PatToken,
+		AppID:          credentials.AppID,
+		InstallationID: installationID,
+		PrivateKey:     credentials.PrivateKey,
+		BaseURL:        credentials.BaseURL,
 	})
 	if err != nil {
 		return nil, err
@@ -64,6 +99,13 @@ func (i *GithubAccountIntegration) DiscoverIntegrations(jsonData []byte) ([]mode
 	for _, a := range accounts {
 		labels := map[string]string{
 			"OrganizationName": a.Login,
+			"CredentialType":   creds.EffectiveType(),
+		}
+		if a.InstallationID != 0 {
+			labels["InstallationID"] = strconv.FormatInt(a.InstallationID, 10)
+		}
+		if credentials.BaseURL != "" {
+			labels["BaseURL"] = credentials.BaseURL
 		}
 		labelsJsonData, err := json.Marshal(labels)
 		if err != nil {
@@ -6,19 +6,37 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/go-github/v66/github"
-	"golang.org/x/oauth2"
+	"github.com/opengovern/opencomply/services/integration/integration-type/github-account/auth"
+	"github.com/opengovern/opencomply/services/integration/integration-type/github-account/configs"
 )
 
 // Config represents the JSON input configuration
 type Config struct {
+	CredentialType   string `json:"credential_type"`
 	Token            string `json:"token"`
+	AppID            string `json:"app_id"`
+	InstallationID   int64  `json:"installation_id"`
+	PrivateKey       string `json:"private_key"`
+	BaseURL          string `json:"base_url"`
 	OrganizationName string `json:"organization_name"`
 }
 
+func (c Config) credentials() auth.Credentials {
+	return auth.Credentials{
+		Type:       c.CredentialType,
+		PatToken:   c.Token,
+		AppID:      c.AppID,
+		PrivateKey: c.PrivateKey,
+		BaseURL:    c.BaseURL,
+	}
+}
+
 // Define required permissions as constants
 const (
 	ReadPublicKey  = "read:public_key"
@@ -302,10 +320,74 @@ func isPermissionError(err error) bool {
 	return false
 }
 
+// IsAppHealthy checks if the GitHub App installation covers the organization and is granted the permissions
+// needed by every resource type
+func IsAppHealthy(ctx context.Context, creds auth.Credentials, installationID int64, org string) error {
+	appClient, err := auth.NewAppClient(creds)
+	if err != nil {
+		return err
+	}
+	installation, _, err := appClient.Apps.GetInstallation(ctx, installationID)
+	if err != nil {
+		return fmt.Errorf("failed to get installation %d: %w", installationID, err)
+	}
+	if installation.SuspendedAt != nil {
+		return fmt.Errorf("installation %d is suspended", installationID)
+	}
+	if !strings.EqualFold(installation.GetAccount().GetLogin(), org) {
+		return fmt.Errorf("installation %d is not installed on organization %s", installationID, org)
+	}
+
+	// Minting a token validates the private key can act as the installation and returns its actual permissions
+	ts := auth.NewInstallationTokenSource(ctx, appClient, installationID)
+	if _, err := ts.Token(); err != nil {
+		return err
+	}
+
+	required, err := configs.RequiredAppPermissions(configs.ResourceTypesList)
+	if err != nil {
+		return err
+	}
+	requiredPermissions := make([]string, 0, len(required))
+	for permission, level := range required {
+		requiredPermissions = append(requiredPermissions, permission+":"+level)
+	}
+	sort.Strings(requiredPermissions)
+	missingPermissions := configs.MissingAppPermissions(required, ts.Permissions())
+	if missingPermissions == nil {
+		missingPermissions = []string{}
+	}
+
+	healthy := len(missingPermissions) == 0
+
+	status := HealthStatus{
+		Organization: org,
+		Healthy:      healthy,
+		Details: Details{
+			RequiredPermissions: requiredPermissions,
+			MissingPermissions:  missingPermissions,
+		},
+	}
+
+	// Marshal to JSON and print
+	output, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal health status: %w", err)
+	}
+
+	fmt.Println(string(output))
+
+	if !healthy {
+		return errors.New("organization is not healthy due to missing app permissions")
+	}
+
+	return nil
+}
+
 func GithubIntegrationHealthcheck(cfg Config) (bool, error) {
-	token := cfg.Token
-	if token == "" {
-		return false, fmt.Errorf("no token provided")
+	creds := cfg.credentials()
+	if err := creds.Validate(); err != nil {
+		return false, err
 	}
 
 	// Read organization name
@@ -318,20 +400,26 @@ func GithubIntegrationHealthcheck(cfg Config) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	// Create an OAuth2 token source
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-
-	// Create an OAuth2 client
-	tc := oauth2.NewClient(ctx, ts)
+	if creds.EffectiveType() == auth.CredentialTypeApp {
+		if cfg.InstallationID == 0 {
+			return false, fmt.Errorf("installation id is required")
+		}
+		fmt.Printf("\nChecking App Installation for Organization: %s\n", orgName)
+		if err := IsAppHealthy(ctx, creds, cfg.InstallationID, orgName); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
 
-	// Create a new GitHub client
-	client := github.NewClient(tc)
+	// Create a new GitHub client authenticated with the token
+	client, err := auth.NewPATClient(ctx, creds)
+	if err != nil {
+		return false, err
+	}
 
 	// Now process permissions for the specified organization
 	fmt.Printf("\nChecking Access for Organization: %s\n", orgName)
-	err := IsHealthy(ctx, client, orgName)
+	err = IsHealthy(ctx, client, orgName)
 	if err != nil {
 		return false, err
 	}
@@ -23,6 +23,7 @@ type ResourceCollection struct {
 	CreatedAt   time.Time                                 `json:"created_at"`
 	Status      ResourceCollectionStatus                  `json:"status"`
 	Filters     []opengovernance.ResourceCollectionFilter `json:"filters"`
+	UserManaged bool                                      `json:"user_managed"`
 
 	IntegrationTypes []integration.Type `json:"integration_types,omitempty"`
 	LastEvaluatedAt  *time.Time         `json:"last_evaluated_at,omitempty"`
@@ -31,6 +32,32 @@ type ResourceCollection struct {
 	MetricCount      *int               `json:"metric_count,omitempty"`
 }
 
+type CreateResourceCollectionRequest struct {
+	Name        string                                    `json:"name" validate:"required"`
+	Description string                                    `json:"description"`
+	Tags        map[string][]string                       `json:"tags"`
+	Filters     []opengovernance.ResourceCollectionFilter `json:"filters" validate:"required,min=1"`
+	Status      ResourceCollectionStatus                  `json:"status"`
+}
+
+type UpdateResourceCollectionRequest struct {
+	Name        *string                                   `json:"name"`
+	Description *string                                   `json:"description"`
+	Tags        map[string][]string                       `json:"tags"`
+	Filters     []opengovernance.ResourceCollectionFilter `json:"filters"`
+	Status      *ResourceCollectionStatus                 `json:"status"`
+}
+
+type PreviewResourceCollectionRequest struct {
+	Filters []opengovernance.ResourceCollectionFilter `json:"filters" validate:"required,min=1"`
+}
+
+type ResourceCollectionPreview struct {
+	ResourceCount    int            `json:"resource_count"`
+	IntegrationCount int            `json:"integration_count"`
+	ResourceTypes    map[string]int `json:"resource_types"`
+}
+
 type ResourceCollectionLandscapeItem struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
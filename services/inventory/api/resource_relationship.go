@@ -0,0 +1,41 @@
+package api
+
+type ResourceRelationshipDirection string
+
+const (
+	ResourceRelationshipDirectionOutgoing ResourceRelationshipDirection = "outgoing"
+	ResourceRelationshipDirectionIncoming ResourceRelationshipDirection = "incoming"
+	ResourceRelationshipDirectionBoth     ResourceRelationshipDirection = "both"
+)
+
+type ResourceRelationshipEndpoint struct {
+	PlatformID      string `json:"platform_id"`
+	ResourceID      string `json:"resource_id"`
+	ResourceName    string `json:"resource_name"`
+	ResourceType    string `json:"resource_type"`
+	IntegrationID   string `json:"integration_id"`
+	IntegrationType string `json:"integration_type"`
+}
+
+type ResourceRelationship struct {
+	Relationship string                       `json:"relationship"`
+	Source       ResourceRelationshipEndpoint `json:"source"`
+	Target       ResourceRelationshipEndpoint `json:"target"`
+	ExtractedAt  int64                        `json:"extracted_at"`
+}
+
+type ListResourceNeighboursResponse struct {
+	PlatformID    string                 `json:"platform_id"`
+	Depth         int                    `json:"depth"`
+	Relationships []ResourceRelationship `json:"relationships"`
+	// Truncated is set when the neighbourhood was cut at the maximum number of resources per hop
+	Truncated bool `json:"truncated"`
+}
+
+type GetResourceRelationshipPathResponse struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Found bool   `json:"found"`
+	// Path holds the edges from the first resource to the second one, in order
+	Path []ResourceRelationship `json:"path"`
+}
@@ -56,7 +56,7 @@ func start(ctx context.Context, cnf config3.InventoryConfig) error {
 		cnf.ElasticSearch,
 		PostgreSQLHost, PostgreSQLPort, PostgreSQLDb, PostgreSQLUser, PostgreSQLPassword, PostgreSQLSSLMode,
 		SteampipeHost, SteampipePort, SteampipeDb, SteampipeUser, SteampipePassword,
-		SchedulerBaseUrl, IntegrationBaseUrl, ComplianceBaseUrl, MetadataBaseUrl,
+		SchedulerBaseUrl, IntegrationBaseUrl, ComplianceBaseUrl, MetadataBaseUrl, cnf.EsSink.BaseURL,
 		logger,
 	)
 	if err != nil {
@@ -4,4 +4,5 @@ import "github.com/opengovern/og-util/pkg/config"
 
 type InventoryConfig struct {
 	ElasticSearch config.ElasticSearch
+	EsSink        config.OpenGovernanceService
 }
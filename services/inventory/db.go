@@ -449,6 +449,51 @@ func (db Database) GetResourceCollection(collectionID string) (*ResourceCollecti
 	return &collection, nil
 }
 
+func (db Database) CreateResourceCollection(collection *ResourceCollection) error {
+	tx := db.orm.Create(collection)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// UpdateResourceCollection updates the collection fields and replaces its tags
+func (db Database) UpdateResourceCollection(collection *ResourceCollection) error {
+	return db.orm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&ResourceCollection{}).Where("id = ?", collection.ID).Updates(map[string]any{
+			"name":         collection.Name,
+			"description":  collection.Description,
+			"status":       collection.Status,
+			"filters_json": collection.FiltersJson,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("resource_collection_id = ?", collection.ID).Delete(&ResourceCollectionTag{}).Error
+		if err != nil {
+			return err
+		}
+		for _, tag := range collection.Tags {
+			tag.ResourceCollectionID = collection.ID
+			if err := tx.Create(&tag).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (db Database) DeleteResourceCollection(collectionID string) error {
+	return db.orm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("resource_collection_id = ?", collectionID).Delete(&ResourceCollectionTag{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ?", collectionID).Delete(&ResourceCollection{}).Error
+	})
+}
+
 func (db Database) ListNamedQueriesUniqueProviders() ([]string, error) {
 	var integrationTypes []string
 
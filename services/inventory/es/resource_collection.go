@@ -0,0 +1,83 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/utils"
+	"github.com/opengovern/opencomply/services/describe"
+)
+
+type ResourceCollectionMembersResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+	} `json:"hits"`
+	Aggregations struct {
+		ResourceTypeGroup struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"resource_type_group"`
+		IntegrationCount struct {
+			Value int `json:"value"`
+		} `json:"integration_count"`
+	} `json:"aggregations"`
+}
+
+type ResourceCollectionMembers struct {
+	ResourceCount    int
+	IntegrationCount int
+	ResourceTypes    map[string]int
+}
+
+// GetResourceCollectionMembers counts the resources matching any of the filters of a resource collection,
+// a resource matches a filter when it matches all the fields set on it
+func GetResourceCollectionMembers(ctx context.Context, client opengovernance.Client, filters []opengovernance.ResourceCollectionFilter) (*ResourceCollectionMembers, error) {
+	filterQuery, err := utils.ResourceCollectionQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	query := map[string]any{
+		"size": 0,
+		"aggs": map[string]any{
+			"resource_type_group": map[string]any{
+				"terms": map[string]any{
+					"field": "resource_type",
+					"size":  10000,
+				},
+			},
+			"integration_count": map[string]any{
+				"cardinality": map[string]any{
+					"field": "integration_id",
+				},
+			},
+		},
+		"query": filterQuery,
+	}
+
+	queryStr, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ResourceCollectionMembersResponse
+	err = client.SearchWithTrackTotalHits(ctx, describe.InventorySummaryIndex, string(queryStr), nil, &response, true)
+	if err != nil {
+		return nil, err
+	}
+
+	res := ResourceCollectionMembers{
+		ResourceCount:    response.Hits.Total.Value,
+		IntegrationCount: response.Aggregations.IntegrationCount.Value,
+		ResourceTypes:    make(map[string]int),
+	}
+	for _, bucket := range response.Aggregations.ResourceTypeGroup.Buckets {
+		res.ResourceTypes[bucket.Key] = bucket.DocCount
+	}
+	return &res, nil
+}
@@ -0,0 +1,73 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/types"
+	"github.com/opengovern/opencomply/services/inventory/api"
+	"go.uber.org/zap"
+)
+
+type ResourceRelationshipsQueryResponse struct {
+	Hits struct {
+		Total opengovernance.SearchTotal `json:"total"`
+		Hits  []struct {
+			ID     string                     `json:"_id"`
+			Source types.ResourceRelationship `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// ListResourceRelationships returns the edges leaving, entering or touching any of the resources, optionally
+// narrowed to some relationship types
+func ListResourceRelationships(ctx context.Context, logger *zap.Logger, client opengovernance.Client, platformIDs []string,
+	direction api.ResourceRelationshipDirection, relationships []string, size int) ([]types.ResourceRelationship, error) {
+	var should []map[string]any
+	if direction != api.ResourceRelationshipDirectionIncoming {
+		should = append(should, map[string]any{
+			"terms": map[string][]string{"source.platformID": platformIDs},
+		})
+	}
+	if direction != api.ResourceRelationshipDirectionOutgoing {
+		should = append(should, map[string]any{
+			"terms": map[string][]string{"target.platformID": platformIDs},
+		})
+	}
+
+	filter := []map[string]any{
+		{"bool": map[string]any{"should": should, "minimum_should_match": 1}},
+	}
+	if len(relationships) > 0 {
+		filter = append(filter, map[string]any{
+			"terms": map[string][]string{"relationship": relationships},
+		})
+	}
+
+	root := map[string]any{}
+	root["query"] = map[string]any{
+		"bool": map[string]any{
+			"filter": filter,
+		},
+	}
+	root["size"] = size
+
+	queryBytes, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ResourceRelationshipsQueryResponse
+	err = client.Search(ctx, types.ResourceRelationshipsIndex, string(queryBytes), &response)
+	if err != nil {
+		logger.Error("failed to query resource relationships", zap.Error(err), zap.String("query", string(queryBytes)))
+		return nil, err
+	}
+
+	edges := make([]types.ResourceRelationship, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		edges = append(edges, hit.Source)
+	}
+	return edges, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/opencomply/pkg/audit"
 	integrationClient "github.com/opengovern/opencomply/services/integration/client"
 	metadataClient "github.com/opengovern/opencomply/services/metadata/client"
 
@@ -23,6 +24,7 @@ type HttpHandler struct {
 	integrationClient integrationClient.IntegrationServiceClient
 	complianceClient  complianceClient.ComplianceServiceClient
 	metadataClient    metadataClient.MetadataServiceClient
+	auditRecorder     *audit.Recorder
 
 	logger *zap.Logger
 }
@@ -31,7 +33,7 @@ func InitializeHttpHandler(
 	esConf config.ElasticSearch,
 	postgresHost string, postgresPort string, postgresDb string, postgresUsername string, postgresPassword string, postgresSSLMode string,
 	steampipeHost string, steampipePort string, steampipeDb string, steampipeUsername string, steampipePassword string,
-	schedulerBaseUrl string, integrationBaseUrl string, complianceBaseUrl string, metadataBaseUrl string,
+	schedulerBaseUrl string, integrationBaseUrl string, complianceBaseUrl string, metadataBaseUrl string, esSinkBaseUrl string,
 	logger *zap.Logger,
 ) (h *HttpHandler, err error) {
 	h = &HttpHandler{}
@@ -92,6 +94,7 @@ func InitializeHttpHandler(
 	h.integrationClient = integrationClient.NewIntegrationServiceClient(integrationBaseUrl)
 	h.complianceClient = complianceClient.NewComplianceClient(complianceBaseUrl)
 	h.metadataClient = metadataClient.NewMetadataServiceClient(metadataBaseUrl)
+	h.auditRecorder = audit.NewRecorder(logger, "inventory", esSinkBaseUrl)
 
 	h.logger = logger
 
@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
 	"net/http"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -19,6 +21,7 @@ import (
 	"github.com/opengovern/og-util/pkg/httpserver"
 	"github.com/opengovern/og-util/pkg/integration"
 	queryrunner "github.com/opengovern/opencomply/jobs/query-runner-job"
+	"github.com/opengovern/opencomply/pkg/audit"
 	"github.com/opengovern/opencomply/pkg/types"
 	integration_type "github.com/opengovern/opencomply/services/integration/integration-type"
 	"github.com/opengovern/opencomply/services/inventory/rego_runner"
@@ -58,6 +61,8 @@ const (
 )
 
 func (h *HttpHandler) Register(e *echo.Echo) {
+	auditLog := h.auditRecorder.Middleware()
+
 	v1 := e.Group("/api/v1")
 
 	queryV1 := v1.Group("/query")
@@ -73,6 +78,10 @@ func (h *HttpHandler) Register(e *echo.Echo) {
 	resourceCollectionMetadata := metadata.Group("/resource-collection")
 	resourceCollectionMetadata.GET("", httpserver.AuthorizeHandler(h.ListResourceCollectionsMetadata, api.ViewerRole))
 	resourceCollectionMetadata.GET("/:resourceCollectionId", httpserver.AuthorizeHandler(h.GetResourceCollectionMetadata, api.ViewerRole))
+	resourceCollectionMetadata.POST("", httpserver.AuthorizeHandler(h.CreateResourceCollection, api.EditorRole), auditLog)
+	resourceCollectionMetadata.POST("/preview", httpserver.AuthorizeHandler(h.PreviewResourceCollection, api.ViewerRole))
+	resourceCollectionMetadata.PUT("/:resourceCollectionId", httpserver.AuthorizeHandler(h.UpdateResourceCollection, api.EditorRole), auditLog)
+	resourceCollectionMetadata.DELETE("/:resourceCollectionId", httpserver.AuthorizeHandler(h.DeleteResourceCollection, api.EditorRole), auditLog)
 
 	v3 := e.Group("/api/v3")
 	v3.POST("/queries", httpserver.AuthorizeHandler(h.ListQueriesV2, api.ViewerRole))
@@ -82,6 +91,8 @@ func (h *HttpHandler) Register(e *echo.Echo) {
 	v3.POST("/query/run", httpserver.AuthorizeHandler(h.RunQueryByID, api.ViewerRole))
 	v3.GET("/query/async/run/:run_id/result", httpserver.AuthorizeHandler(h.GetAsyncQueryRunResult, api.ViewerRole))
 	v3.GET("/resources/categories", httpserver.AuthorizeHandler(h.GetResourceCategories, api.ViewerRole))
+	v3.GET("/resources/relationships/path", httpserver.AuthorizeHandler(h.GetResourceRelationshipPath, api.ViewerRole))
+	v3.GET("/resources/:platform_id/neighbours", httpserver.AuthorizeHandler(h.ListResourceNeighbours, api.ViewerRole))
 	v3.GET("/queries/categories", httpserver.AuthorizeHandler(h.GetQueriesResourceCategories, api.ViewerRole))
 	v3.GET("/tables/categories", httpserver.AuthorizeHandler(h.GetTablesResourceCategories, api.ViewerRole))
 	v3.GET("/categories/queries", httpserver.AuthorizeHandler(h.GetCategoriesQueries, api.ViewerRole))
@@ -869,7 +880,7 @@ func (h *HttpHandler) ListResourceCollectionsMetadata(ctx echo.Context) error {
 		statuses = append(statuses, ResourceCollectionStatus(statusString))
 	}
 
-	resourceCollections, err := h.db.ListResourceCollections(ids, nil)
+	resourceCollections, err := h.db.ListResourceCollections(ids, statuses)
 	if err != nil {
 		return err
 	}
@@ -901,7 +912,210 @@ func (h *HttpHandler) GetResourceCollectionMetadata(ctx echo.Context) error {
 		}
 		return err
 	}
-	return ctx.JSON(http.StatusOK, resourceCollection.ToApi())
+
+	res := resourceCollection.ToApi()
+	members, err := es.GetResourceCollectionMembers(ctx.Request().Context(), h.client, resourceCollection.Filters)
+	if err != nil {
+		h.logger.Error("failed to count resource collection members", zap.String("resourceCollectionId", collectionID), zap.Error(err))
+	} else {
+		res.ResourceCount = &members.ResourceCount
+		res.IntegrationCount = &members.IntegrationCount
+	}
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func resourceCollectionStatusFromApi(status inventoryApi.ResourceCollectionStatus) (ResourceCollectionStatus, error) {
+	switch status {
+	case inventoryApi.ResourceCollectionStatusUnknown, inventoryApi.ResourceCollectionStatusActive:
+		return ResourceCollectionStatusActive, nil
+	case inventoryApi.ResourceCollectionStatusInactive:
+		return ResourceCollectionStatusInactive, nil
+	default:
+		return "", fmt.Errorf("invalid resource collection status: %s", status)
+	}
+}
+
+// CreateResourceCollection godoc
+//
+//	@Summary		Create resource collection
+//	@Description	Creating a resource collection defined by tag, integration, resource type and region filters
+//	@Security		BearerToken
+//	@Tags			resource_collection
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		inventoryApi.CreateResourceCollectionRequest	true	"Resource collection"
+//	@Success		201		{object}	inventoryApi.ResourceCollection
+//	@Router			/inventory/api/v2/metadata/resource-collection [post]
+func (h *HttpHandler) CreateResourceCollection(ctx echo.Context) error {
+	var req inventoryApi.CreateResourceCollectionRequest
+	if err := bindValidate(ctx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	status, err := resourceCollectionStatusFromApi(req.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	now := time.Now()
+	collection := ResourceCollection{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      status,
+		UserManaged: true,
+		Created:     now,
+	}
+	if err := collection.SetFilters(req.Filters); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid filters")
+	}
+	collection.SetTags(req.Tags)
+	audit.SetAction(ctx, "resource_collection.create")
+	audit.SetTarget(ctx, "resource_collection", collection.ID)
+
+	if err := h.db.CreateResourceCollection(&collection); err != nil {
+		h.logger.Error("failed to create resource collection", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create resource collection")
+	}
+	res := collection.ToApi()
+	audit.SetAfter(ctx, res)
+
+	return ctx.JSON(http.StatusCreated, res)
+}
+
+// UpdateResourceCollection godoc
+//
+//	@Summary		Update resource collection
+//	@Description	Updating a user managed resource collection, fields missing from the request are kept
+//	@Security		BearerToken
+//	@Tags			resource_collection
+//	@Accept			json
+//	@Produce		json
+//	@Param			resourceCollectionId	path		string											true	"Resource collection ID"
+//	@Param			request					body		inventoryApi.UpdateResourceCollectionRequest	true	"Resource collection changes"
+//	@Success		200						{object}	inventoryApi.ResourceCollection
+//	@Router			/inventory/api/v2/metadata/resource-collection/{resourceCollectionId} [put]
+func (h *HttpHandler) UpdateResourceCollection(ctx echo.Context) error {
+	collectionID := ctx.Param("resourceCollectionId")
+
+	var req inventoryApi.UpdateResourceCollectionRequest
+	if err := bindValidate(ctx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	collection, err := h.db.GetResourceCollection(collectionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "resource collection not found")
+		}
+		return err
+	}
+	// collections of the platform are replaced by the resource_collection migration
+	if !collection.UserManaged {
+		return echo.NewHTTPError(http.StatusConflict, "resource collection is managed by the platform")
+	}
+	audit.SetAction(ctx, "resource_collection.update")
+	audit.SetTarget(ctx, "resource_collection", collectionID)
+	audit.SetBefore(ctx, collection.ToApi())
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "name can not be empty")
+		}
+		collection.Name = *req.Name
+	}
+	if req.Description != nil {
+		collection.Description = *req.Description
+	}
+	if req.Status != nil {
+		collection.Status, err = resourceCollectionStatusFromApi(*req.Status)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+	if req.Filters != nil {
+		if len(req.Filters) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "filters can not be empty")
+		}
+		if err := collection.SetFilters(req.Filters); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid filters")
+		}
+	}
+	if req.Tags != nil {
+		collection.SetTags(req.Tags)
+	}
+
+	if err := h.db.UpdateResourceCollection(collection); err != nil {
+		h.logger.Error("failed to update resource collection", zap.String("resourceCollectionId", collectionID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update resource collection")
+	}
+	res := collection.ToApi()
+	audit.SetAfter(ctx, res)
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteResourceCollection godoc
+//
+//	@Summary		Delete resource collection
+//	@Description	Deleting a user managed resource collection
+//	@Security		BearerToken
+//	@Tags			resource_collection
+//	@Param			resourceCollectionId	path	string	true	"Resource collection ID"
+//	@Success		200
+//	@Router			/inventory/api/v2/metadata/resource-collection/{resourceCollectionId} [delete]
+func (h *HttpHandler) DeleteResourceCollection(ctx echo.Context) error {
+	collectionID := ctx.Param("resourceCollectionId")
+
+	collection, err := h.db.GetResourceCollection(collectionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "resource collection not found")
+		}
+		return err
+	}
+	if !collection.UserManaged {
+		return echo.NewHTTPError(http.StatusConflict, "resource collection is managed by the platform")
+	}
+	audit.SetAction(ctx, "resource_collection.delete")
+	audit.SetTarget(ctx, "resource_collection", collectionID)
+	audit.SetBefore(ctx, collection.ToApi())
+
+	if err := h.db.DeleteResourceCollection(collectionID); err != nil {
+		h.logger.Error("failed to delete resource collection", zap.String("resourceCollectionId", collectionID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete resource collection")
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// PreviewResourceCollection godoc
+//
+//	@Summary		Preview resource collection
+//	@Description	Counting the resources the given resource collection filters would contain
+//	@Security		BearerToken
+//	@Tags			resource_collection
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		inventoryApi.PreviewResourceCollectionRequest	true	"Resource collection filters"
+//	@Success		200		{object}	inventoryApi.ResourceCollectionPreview
+//	@Router			/inventory/api/v2/metadata/resource-collection/preview [post]
+func (h *HttpHandler) PreviewResourceCollection(ctx echo.Context) error {
+	var req inventoryApi.PreviewResourceCollectionRequest
+	if err := bindValidate(ctx, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	members, err := es.GetResourceCollectionMembers(ctx.Request().Context(), h.client, req.Filters)
+	if err != nil {
+		h.logger.Error("failed to count resource collection members", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to count resource collection members")
+	}
+
+	return ctx.JSON(http.StatusOK, inventoryApi.ResourceCollectionPreview{
+		ResourceCount:    members.ResourceCount,
+		IntegrationCount: members.IntegrationCount,
+		ResourceTypes:    members.ResourceTypes,
+	})
 }
 
 func (h *HttpHandler) connectionsFilter(filter map[string]interface{}) ([]string, error) {
@@ -1644,3 +1858,207 @@ func (h *HttpHandler) GetParametersQueries(ctx echo.Context) error {
 		ParametersQueries: parametersQueries,
 	})
 }
+
+const (
+	maxRelationshipDepth     = 6
+	maxRelationshipHopResult = 10000
+)
+
+func resourceRelationshipToApi(r types.ResourceRelationship) inventoryApi.ResourceRelationship {
+	endpoint := func(e types.ResourceRelationshipEndpoint) inventoryApi.ResourceRelationshipEndpoint {
+		return inventoryApi.ResourceRelationshipEndpoint{
+			PlatformID:      e.PlatformID,
+			ResourceID:      e.ResourceID,
+			ResourceName:    e.ResourceName,
+			ResourceType:    e.ResourceType,
+			IntegrationID:   e.IntegrationID,
+			IntegrationType: e.IntegrationType,
+		}
+	}
+	return inventoryApi.ResourceRelationship{
+		Relationship: string(r.Relationship),
+		Source:       endpoint(r.Source),
+		Target:       endpoint(r.Target),
+		ExtractedAt:  r.ExtractedAt,
+	}
+}
+
+func relationshipQueryParams(ctx echo.Context, depthParam string, defaultDepth int) (inventoryApi.ResourceRelationshipDirection, int, error) {
+	direction := inventoryApi.ResourceRelationshipDirection(ctx.QueryParam("direction"))
+	switch direction {
+	case "":
+		direction = inventoryApi.ResourceRelationshipDirectionBoth
+	case inventoryApi.ResourceRelationshipDirectionOutgoing, inventoryApi.ResourceRelationshipDirectionIncoming,
+		inventoryApi.ResourceRelationshipDirectionBoth:
+	default:
+		return "", 0, echo.NewHTTPError(http.StatusBadRequest, "direction must be one of outgoing, incoming or both")
+	}
+
+	depth := defaultDepth
+	if v := ctx.QueryParam(depthParam); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 1 || d > maxRelationshipDepth {
+			return "", 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s must be between 1 and %d", depthParam, maxRelationshipDepth))
+		}
+		depth = d
+	}
+	return direction, depth, nil
+}
+
+// otherEnd returns the resource reached by following the edge from the given resource in the given direction
+func otherEnd(edge types.ResourceRelationship, platformID string, direction inventoryApi.ResourceRelationshipDirection) (string, bool) {
+	if edge.Source.PlatformID == platformID && direction != inventoryApi.ResourceRelationshipDirectionIncoming {
+		return edge.Target.PlatformID, true
+	}
+	if edge.Target.PlatformID == platformID && direction != inventoryApi.ResourceRelationshipDirectionOutgoing {
+		return edge.Source.PlatformID, true
+	}
+	return "", false
+}
+
+// ListResourceNeighbours godoc
+//
+//	@Summary		List the neighbours of a resource
+//	@Description	Returns the relationships of a resource with other resources of any integration, up to the given number of hops
+//	@Security		BearerToken
+//	@Tags			resource_relationship
+//	@Param			platform_id		path	string		true	"Platform resource id"
+//	@Param			relationship	query	[]string	false	"Relationship types filter"
+//	@Param			direction		query	string		false	"outgoing, incoming or both, defaults to both"
+//	@Param			depth			query	int			false	"Number of hops, defaults to 1"
+//	@Produce		json
+//	@Success		200	{object}	inventoryApi.ListResourceNeighboursResponse
+//	@Router			/inventory/api/v3/resources/{platform_id}/neighbours [get]
+func (h *HttpHandler) ListResourceNeighbours(ctx echo.Context) error {
+	platformID := ctx.Param("platform_id")
+	relationships := httpserver.QueryArrayParam(ctx, "relationship")
+	direction, depth, err := relationshipQueryParams(ctx, "depth", 1)
+	if err != nil {
+		return err
+	}
+
+	resp := inventoryApi.ListResourceNeighboursResponse{
+		PlatformID:    platformID,
+		Depth:         depth,
+		Relationships: []inventoryApi.ResourceRelationship{},
+	}
+	visited := map[string]bool{platformID: true}
+	seenEdges := make(map[string]bool)
+	frontier := []string{platformID}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		edges, err := es.ListResourceRelationships(ctx.Request().Context(), h.logger, h.client, frontier, direction, relationships, maxRelationshipHopResult)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list resource relationships")
+		}
+		if len(edges) >= maxRelationshipHopResult {
+			resp.Truncated = true
+		}
+
+		inFrontier := make(map[string]bool, len(frontier))
+		for _, id := range frontier {
+			inFrontier[id] = true
+		}
+		var next []string
+		for _, edge := range edges {
+			key := edge.Source.PlatformID + "|" + string(edge.Relationship) + "|" + edge.Target.PlatformID
+			if !seenEdges[key] {
+				seenEdges[key] = true
+				resp.Relationships = append(resp.Relationships, resourceRelationshipToApi(edge))
+			}
+			for _, from := range []string{edge.Source.PlatformID, edge.Target.PlatformID} {
+				if !inFrontier[from] {
+					continue
+				}
+				if to, ok := otherEnd(edge, from, direction); ok && !visited[to] {
+					visited[to] = true
+					next = append(next, to)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetResourceRelationshipPath godoc
+//
+//	@Summary		Find a path between two resources
+//	@Description	Returns the shortest chain of relationships leading from one resource to another, across integrations
+//	@Security		BearerToken
+//	@Tags			resource_relationship
+//	@Param			from			query	string		true	"Platform resource id of the first resource"
+//	@Param			to				query	string		true	"Platform resource id of the second resource"
+//	@Param			relationship	query	[]string	false	"Relationship types filter"
+//	@Param			direction		query	string		false	"outgoing, incoming or both, defaults to both"
+//	@Param			max_depth		query	int			false	"Maximum path length, defaults to 4"
+//	@Produce		json
+//	@Success		200	{object}	inventoryApi.GetResourceRelationshipPathResponse
+//	@Router			/inventory/api/v3/resources/relationships/path [get]
+func (h *HttpHandler) GetResourceRelationshipPath(ctx echo.Context) error {
+	from, to := ctx.QueryParam("from"), ctx.QueryParam("to")
+	if from == "" || to == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "from and to are required")
+	}
+	relationships := httpserver.QueryArrayParam(ctx, "relationship")
+	direction, maxDepth, err := relationshipQueryParams(ctx, "max_depth", 4)
+	if err != nil {
+		return err
+	}
+
+	resp := inventoryApi.GetResourceRelationshipPathResponse{
+		From: from,
+		To:   to,
+		Path: []inventoryApi.ResourceRelationship{},
+	}
+	if from == to {
+		resp.Found = true
+		return ctx.JSON(http.StatusOK, resp)
+	}
+
+	type step struct {
+		previous string
+		edge     types.ResourceRelationship
+	}
+	reachedBy := map[string]*step{from: nil}
+	frontier := []string{from}
+	for hop := 0; hop < maxDepth && len(frontier) > 0; hop++ {
+		edges, err := es.ListResourceRelationships(ctx.Request().Context(), h.logger, h.client, frontier, direction, relationships, maxRelationshipHopResult)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list resource relationships")
+		}
+
+		inFrontier := make(map[string]bool, len(frontier))
+		for _, id := range frontier {
+			inFrontier[id] = true
+		}
+		var next []string
+		for _, edge := range edges {
+			for _, current := range []string{edge.Source.PlatformID, edge.Target.PlatformID} {
+				if !inFrontier[current] {
+					continue
+				}
+				reached, ok := otherEnd(edge, current, direction)
+				if !ok {
+					continue
+				}
+				if _, ok := reachedBy[reached]; ok {
+					continue
+				}
+				reachedBy[reached] = &step{previous: current, edge: edge}
+				next = append(next, reached)
+			}
+		}
+
+		if _, ok := reachedBy[to]; ok {
+			resp.Found = true
+			for id := to; reachedBy[id] != nil; id = reachedBy[id].previous {
+				resp.Path = append([]inventoryApi.ResourceRelationship{resourceRelationshipToApi(reachedBy[id].edge)}, resp.Path...)
+			}
+			break
+		}
+		frontier = next
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
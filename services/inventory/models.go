@@ -1,6 +1,7 @@
 package inventory
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/opengovern/og-util/pkg/integration"
@@ -182,6 +183,8 @@ type ResourceCollection struct {
 	FiltersJson pgtype.JSONB `gorm:"type:jsonb"`
 	Description string
 	Status      ResourceCollectionStatus
+	// UserManaged collections are created through the API, the resource_collection migration leaves them untouched
+	UserManaged bool
 
 	Tags    []ResourceCollectionTag `gorm:"foreignKey:ResourceCollectionID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	tagsMap map[string][]string     `gorm:"-:all"`
@@ -203,10 +206,37 @@ func (r ResourceCollection) ToApi() api.ResourceCollection {
 		CreatedAt:   r.Created,
 		Status:      r.Status.ToApi(),
 		Filters:     r.Filters,
+		UserManaged: r.UserManaged,
 	}
 	return apiResourceCollection
 }
 
+func (r *ResourceCollection) SetFilters(filters []opengovernance.ResourceCollectionFilter) error {
+	filtersJson, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	if err := r.FiltersJson.Set(filtersJson); err != nil {
+		return err
+	}
+	r.Filters = filters
+	return nil
+}
+
+func (r *ResourceCollection) SetTags(tags map[string][]string) {
+	r.Tags = make([]ResourceCollectionTag, 0, len(tags))
+	for key, values := range tags {
+		r.Tags = append(r.Tags, ResourceCollectionTag{
+			Tag: model.Tag{
+				Key:   key,
+				Value: values,
+			},
+			ResourceCollectionID: r.ID,
+		})
+	}
+	r.tagsMap = nil
+}
+
 func (r ResourceCollection) GetTagsMap() map[string][]string {
 	if r.tagsMap == nil {
 		tagLikeArr := make([]model.TagLike, 0, len(r.Tags))
@@ -12,6 +12,7 @@ type Config struct {
 	Scheduler   koanf.OpenGovernanceService `yaml:"scheduler" koanf:"scheduler"`
 	Compliance  koanf.OpenGovernanceService `yaml:"compliance" koanf:"compliance"`
 	Inventory   koanf.OpenGovernanceService `yaml:"inventory" koanf:"inventory"`
+	EsSink      koanf.OpenGovernanceService `yaml:"es_sink" koanf:"es_sink"`
 
 	Vault vault.Config `yaml:"vault" koanf:"vault"`
 
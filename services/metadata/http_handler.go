@@ -16,6 +16,7 @@ import (
 	"github.com/opengovern/og-util/pkg/vault"
 	db2 "github.com/opengovern/opencomply/jobs/post-install-job/db"
 	"github.com/opengovern/opencomply/jobs/post-install-job/db/model"
+	"github.com/opengovern/opencomply/pkg/audit"
 	"github.com/opengovern/opencomply/services/metadata/config"
 	"github.com/opengovern/opencomply/services/metadata/internal/database"
 	"github.com/opengovern/opencomply/services/metadata/models"
@@ -36,6 +37,7 @@ type HttpHandler struct {
 	vaultSecretHandler vault.VaultSecretHandler
 	dexClient          dexApi.DexClient
 	logger             *zap.Logger
+	auditRecorder      *audit.Recorder
 
 	viewCheckpoint time.Time
 }
@@ -118,6 +120,7 @@ func InitializeHttpHandler(
 		kubeClient:     kubeClient,
 		logger:         logger,
 		dexClient:      dexClient,
+		auditRecorder:  audit.NewRecorder(logger, "metadata", cfg.EsSink.BaseURL),
 		viewCheckpoint: time.Now().Add(-time.Hour * 2),
 	}
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/opengovern/opencomply/pkg/audit"
 	"github.com/opengovern/opencomply/pkg/utils"
 	"net/http"
 	"net/url"
@@ -50,34 +51,35 @@ import (
 
 func (h HttpHandler) Register(r *echo.Echo) {
 	v1 := r.Group("/api/v1")
+	auditLog := h.auditRecorder.Middleware()
 
 	filter := v1.Group("/filter")
-	filter.POST("", httpserver.AuthorizeHandler(h.AddFilter, api3.ViewerRole))
+	filter.POST("", httpserver.AuthorizeHandler(h.AddFilter, api3.ViewerRole), auditLog)
 	filter.GET("", httpserver.AuthorizeHandler(h.GetFilters, api3.ViewerRole))
 
 	metadata := v1.Group("/metadata")
 	metadata.GET("/:key", httpserver.AuthorizeHandler(h.GetConfigMetadata, api3.ViewerRole))
-	metadata.POST("", httpserver.AuthorizeHandler(h.SetConfigMetadata, api3.AdminRole))
+	metadata.POST("", httpserver.AuthorizeHandler(h.SetConfigMetadata, api3.AdminRole), auditLog)
 
 	queryParameter := v1.Group("/query_parameter")
-	queryParameter.POST("/set", httpserver.AuthorizeHandler(h.SetQueryParameter, api3.AdminRole))
+	queryParameter.POST("/set", httpserver.AuthorizeHandler(h.SetQueryParameter, api3.AdminRole), auditLog)
 	queryParameter.POST("", httpserver.AuthorizeHandler(h.ListQueryParameters, api3.ViewerRole))
 	queryParameter.GET("/:key", httpserver.AuthorizeHandler(h.GetQueryParameter, api3.ViewerRole))
 
 	v3 := r.Group("/api/v3")
-	v3.PUT("/sample/purge", httpserver.AuthorizeHandler(h.PurgeSampleData, api3.ViewerRole))
-	v3.PUT("/sample/sync", httpserver.AuthorizeHandler(h.SyncDemo, api3.ViewerRole))
-	v3.PUT("/sample/loaded", httpserver.AuthorizeHandler(h.WorkspaceLoadedSampleData, api3.ViewerRole))
+	v3.PUT("/sample/purge", httpserver.AuthorizeHandler(h.PurgeSampleData, api3.ViewerRole), auditLog)
+	v3.PUT("/sample/sync", httpserver.AuthorizeHandler(h.SyncDemo, api3.ViewerRole), auditLog)
+	v3.PUT("/sample/loaded", httpserver.AuthorizeHandler(h.WorkspaceLoadedSampleData, api3.ViewerRole), auditLog)
 	v3.GET("/sample/sync/status", httpserver.AuthorizeHandler(h.GetSampleSyncStatus, api3.ViewerRole))
 	v3.GET("/migration/status", httpserver.AuthorizeHandler(h.GetMigrationStatus, api3.ViewerRole))
 	v3.GET("/configured/status", httpserver.AuthorizeHandler(h.GetConfiguredStatus, api3.ViewerRole))
-	v3.PUT("/configured/set", httpserver.AuthorizeHandler(h.SetConfiguredStatus, api3.AdminRole))
-	v3.PUT("/configured/unset", httpserver.AuthorizeHandler(h.UnsetConfiguredStatus, api3.ViewerRole))
+	v3.PUT("/configured/set", httpserver.AuthorizeHandler(h.SetConfiguredStatus, api3.AdminRole), auditLog)
+	v3.PUT("/configured/unset", httpserver.AuthorizeHandler(h.UnsetConfiguredStatus, api3.ViewerRole), auditLog)
 	v3.GET("/about", httpserver.AuthorizeHandler(h.GetAbout, api3.ViewerRole))
 	v3.GET("/vault/configured", httpserver.AuthorizeHandler(h.VaultConfigured, api3.ViewerRole))
 
 	views := v3.Group("/views")
-	views.PUT("/reload", httpserver.AuthorizeHandler(h.ReloadViews, api3.AdminRole))
+	views.PUT("/reload", httpserver.AuthorizeHandler(h.ReloadViews, api3.AdminRole), auditLog)
 	views.GET("/checkpoint", httpserver.AuthorizeHandler(h.GetViewsCheckpoint, api3.AdminRole))
 	views.GET("", httpserver.AuthorizeHandler(h.GetViews, api3.ViewerRole))
 }
@@ -153,6 +155,12 @@ func (h HttpHandler) SetConfigMetadata(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
+	audit.SetAction(ctx, "metadata.set")
+	audit.SetTarget(ctx, "metadata", key.String())
+	if before, err := src.GetConfigMetadata(h.db, key.String()); err == nil && before != nil {
+		audit.SetBefore(ctx, before.GetCore())
+	}
+
 	_, span := tracer.Start(ctx.Request().Context(), "new_SetConfigMetadata", trace.WithSpanKind(trace.SpanKindServer))
 	span.SetName("new_SetConfigMetadata")
 
@@ -23,3 +23,71 @@ type RunTaskRequest struct {
 	TaskID string         `json:"task_id"`
 	Params map[string]any `json:"params"`
 }
+
+type NatsConfig struct {
+	Stream         string `json:"stream,omitempty"`
+	Topic          string `json:"topic,omitempty"`
+	Consumer       string `json:"consumer,omitempty"`
+	ResultTopic    string `json:"result_topic,omitempty"`
+	ResultConsumer string `json:"result_consumer,omitempty"`
+}
+
+type ScaleConfig struct {
+	Stream          string `json:"stream,omitempty"`
+	Consumer        string `json:"consumer,omitempty"`
+	LagThreshold    string `json:"lag_threshold" example:"1"`
+	MinReplica      int32  `json:"min_replica"`
+	MaxReplica      int32  `json:"max_replica" example:"5"`
+	PollingInterval int32  `json:"polling_interval,omitempty"`
+	CooldownPeriod  int32  `json:"cooldown_period,omitempty"`
+}
+
+// TaskDefinition is the definition of a task and its worker, the NATS config defaults to the task id
+type TaskDefinition struct {
+	ID           string            `json:"id" example:"container-vulnerability-scanner"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	ImageURL     string            `json:"image_url"`
+	Command      string            `json:"command"`
+	ResultType   string            `json:"result_type"`
+	WorkloadType string            `json:"workload_type" enums:"deployment"`
+	EnvVars      map[string]string `json:"env_vars,omitempty"`
+	Interval     uint64            `json:"interval"` // Minutes
+	Timeout      uint64            `json:"timeout"`  // Minutes
+	NatsConfig   NatsConfig        `json:"nats_config"`
+	ScaleConfig  ScaleConfig       `json:"scale_config"`
+	ParamsSchema map[string]any    `json:"params_schema,omitempty"` // JSON schema of the params of the task runs
+	ResultSchema *ResultSchema     `json:"result_schema,omitempty"` // Columns of the CloudQL table of the task results
+	Source       string            `json:"source,omitempty" enums:"file,api"`
+}
+
+type ResultSchema struct {
+	Columns []ResultColumn `json:"columns"`
+}
+
+type ResultColumn struct {
+	Name        string `json:"name" example:"image_url"`
+	Type        string `json:"type" enums:"string,int,double,bool,timestamp,json,ipaddr,cidr"`
+	Description string `json:"description,omitempty"`
+	Field       string `json:"field,omitempty" example:"description.imageUrl"` // Path of the value in the result document, defaults to description.<name>
+	KeyColumn   bool   `json:"key_column,omitempty"`                           // Pushes the equality quals on the column down to the result index
+}
+
+// TaskResultSchema is the result schema of a task, the results are stored in the index named after the result type
+type TaskResultSchema struct {
+	TaskID     string         `json:"task_id"`
+	ResultType string         `json:"result_type"`
+	Columns    []ResultColumn `json:"columns"`
+}
+
+// ReservedResultColumns are the columns of every task result table, mapped to their field in the result document
+var ReservedResultColumns = map[string]string{
+	"platform_id":   "platform_id",
+	"resource_id":   "resource_id",
+	"resource_name": "resource_name",
+	"task_type":     "task_type",
+	"result_type":   "result_type",
+	"metadata":      "metadata",
+	"described_by":  "described_by",
+	"described_at":  "described_at",
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"time"
+)
+
+// WorkflowStep runs a task once its dependencies are done. String values of the params and the condition are Go
+// templates over the workflow run params (.params) and the status and the result of the previous steps
+// (.steps.<step id>.status, .steps.<step id>.result). A param that is a single template is decoded as JSON when its
+// output is valid JSON, e.g. "{{ toJson .steps.scan.result.images }}" passes a list.
+type WorkflowStep struct {
+	ID        string         `json:"id" example:"scan"`
+	TaskID    string         `json:"task_id"`
+	DependsOn []string       `json:"depends_on,omitempty"`
+	Params    map[string]any `json:"params,omitempty"`
+	// Condition skips the step unless it renders to true
+	Condition  string `json:"condition,omitempty" example:"{{ gt (len .steps.scan.result.critical_cves) 0 }}"`
+	MaxRetries int    `json:"max_retries,omitempty"`
+	// ContinueOnFailure runs the dependent steps and does not fail the workflow run if the step fails
+	ContinueOnFailure bool `json:"continue_on_failure,omitempty"`
+}
+
+type WorkflowDefinition struct {
+	ID          string         `json:"id" example:"oci-image-cve-findings"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Steps       []WorkflowStep `json:"steps"`
+}
+
+type ListWorkflowsResponse struct {
+	Items      []WorkflowDefinition `json:"items"`
+	TotalCount int                  `json:"total_count"`
+}
+
+type RunWorkflowRequest struct {
+	Params map[string]any `json:"params"`
+}
+
+type WorkflowRunStep struct {
+	StepID         string         `json:"step_id"`
+	Status         string         `json:"status" enums:"PENDING,RUNNING,SUCCEEDED,FAILED,SKIPPED"`
+	Attempts       int            `json:"attempts"`
+	TaskRunID      *uint          `json:"task_run_id,omitempty"`
+	Result         map[string]any `json:"result,omitempty"`
+	FailureMessage string         `json:"failure_message,omitempty"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+type WorkflowRun struct {
+	ID             uint              `json:"id"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	WorkflowID     string            `json:"workflow_id"`
+	Status         string            `json:"status" enums:"RUNNING,SUCCEEDED,FAILED"`
+	Params         map[string]any    `json:"params"`
+	FailureMessage string            `json:"failure_message,omitempty"`
+	Steps          []WorkflowRunStep `json:"steps,omitempty"`
+}
+
+type ListWorkflowRunsResponse struct {
+	Items      []WorkflowRun `json:"items"`
+	TotalCount int           `json:"total_count"`
+}
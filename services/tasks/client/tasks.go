@@ -1 +1,69 @@
 package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/opengovern/og-util/pkg/httpclient"
+	"github.com/opengovern/opencomply/services/tasks/api"
+)
+
+type TasksServiceClient interface {
+	ListTaskRuns(ctx *httpclient.Context, taskIDs []string, statuses []string) (*api.ListTaskRunsResponse, error)
+	ListTaskResultSchemas(ctx *httpclient.Context) ([]api.TaskResultSchema, error)
+}
+
+type tasksClient struct {
+	baseURL string
+}
+
+func NewTasksClient(baseURL string) TasksServiceClient {
+	return &tasksClient{baseURL: baseURL}
+}
+
+func (s *tasksClient) ListTaskRuns(ctx *httpclient.Context, taskIDs []string, statuses []string) (*api.ListTaskRunsResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/tasks/run", s.baseURL)
+
+	firstParamAttached := false
+	for _, taskID := range taskIDs {
+		if !firstParamAttached {
+			url += "?"
+			firstParamAttached = true
+		} else {
+			url += "&"
+		}
+		url += fmt.Sprintf("task_id=%s", taskID)
+	}
+	for _, status := range statuses {
+		if !firstParamAttached {
+			url += "?"
+			firstParamAttached = true
+		} else {
+			url += "&"
+		}
+		url += fmt.Sprintf("status=%s", status)
+	}
+
+	var response api.ListTaskRunsResponse
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodGet, url, ctx.ToHeaders(), nil, &response); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *tasksClient) ListTaskResultSchemas(ctx *httpclient.Context) ([]api.TaskResultSchema, error) {
+	url := fmt.Sprintf("%s/api/v1/tasks/result-schemas", s.baseURL)
+
+	var schemas []api.TaskResultSchema
+	if statusCode, err := httpclient.DoRequest(ctx.Ctx, http.MethodGet, url, ctx.ToHeaders(), nil, &schemas); err != nil {
+		if 400 <= statusCode && statusCode < 500 {
+			return nil, echo.NewHTTPError(statusCode, err.Error())
+		}
+		return nil, err
+	}
+	return schemas, nil
+}
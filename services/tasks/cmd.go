@@ -2,11 +2,11 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"github.com/jackc/pgtype"
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/opengovern/og-util/pkg/koanf"
+	"github.com/opengovern/opencomply/pkg/audit"
+	"github.com/opengovern/opencomply/pkg/executor"
 	"github.com/opengovern/opencomply/services/tasks/config"
 	"github.com/opengovern/opencomply/services/tasks/db/models"
 	"github.com/opengovern/opencomply/services/tasks/scheduler"
@@ -80,12 +80,27 @@ func start(ctx context.Context) error {
 		return fmt.Errorf("new postgres client: %w", err)
 	}
 
-	kubeClient, err := NewKubeClient()
+	var kubeClient client.Client
+	if !cfg.Executor.IsLocal() {
+		kubeClient, err = NewKubeClient()
+		if err != nil {
+			return err
+		}
+	} else if cfg.Executor.Local.EmbeddedNATS {
+		cfg.NATS.URL, err = executor.StartLocalNATS(ctx, logger, cfg.Executor.Local)
+		if err != nil {
+			return err
+		}
+	}
+
+	currentNamespace, _ := os.LookupEnv("CURRENT_NAMESPACE")
+
+	workerExecutor, err := executor.New(cfg.Executor, logger, kubeClient, currentNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = setupTasks(ctx, cfg, db, kubeClient)
+	err = setupTasks(ctx, cfg, db, workerExecutor)
 	if err != nil {
 		return err
 	}
@@ -101,8 +116,12 @@ func start(ctx context.Context) error {
 	}
 
 	return httpserver.RegisterAndStart(ctx, logger, cfg.Http.Address, &httpRoutes{
-		logger: logger,
-		db:     db,
+		logger:        logger,
+		cfg:           cfg,
+		db:            db,
+		executor:      workerExecutor,
+		scheduler:     mainScheduler,
+		auditRecorder: audit.NewRecorder(logger, "tasks", cfg.EsSink.BaseURL),
 	})
 }
 
@@ -128,7 +147,7 @@ func NewKubeClient() (client.Client, error) {
 	return kubeClient, nil
 }
 
-func setupTasks(ctx context.Context, cfg config.Config, db db.Database, kubeClient client.Client) error {
+func setupTasks(ctx context.Context, cfg config.Config, db db.Database, workerExecutor executor.Executor) error {
 	err := filepath.WalkDir(TasksPath, func(path string, d fs.DirEntry, err error) error {
 		if !(strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")) {
 			return nil
@@ -147,48 +166,34 @@ func setupTasks(ctx context.Context, cfg config.Config, db db.Database, kubeClie
 
 		fillMissedConfigs(&task)
 
-		natsJsonData, err := json.Marshal(task.NatsConfig)
+		err = worker.ValidateTask(cfg, task)
 		if err != nil {
-			return err
+			return fmt.Errorf("invalid task %s: %w", path, err)
 		}
 
-		var natsJsonb pgtype.JSONB
-		err = natsJsonb.Set(natsJsonData)
+		existing, err := db.GetTask(task.ID)
 		if err != nil {
 			return err
 		}
-
-		scaleJsonData, err := json.Marshal(task.NatsConfig)
-		if err != nil {
-			return err
+		// tasks changed through the API keep their API definition
+		if existing != nil && existing.Source == models.TaskSourceAPI {
+			return nil
 		}
 
-		var scaleJsonb pgtype.JSONB
-		err = scaleJsonb.Set(scaleJsonData)
+		taskModel, err := newTaskModel(task, models.TaskSourceFile)
 		if err != nil {
 			return err
 		}
-
-		err = db.CreateTask(&models.Task{
-			ID:          task.ID,
-			Name:        task.Name,
-			ResultType:  task.ResultType,
-			Description: task.Description,
-			ImageUrl:    task.ImageURL,
-			Interval:    task.Interval,
-			Timeout:     task.Timeout,
-			NatsConfig:  natsJsonb,
-			ScaleConfig: scaleJsonb,
-		})
+		if existing == nil {
+			err = db.CreateTask(taskModel)
+		} else {
+			err = db.UpdateTask(task.ID, taskModel)
+		}
 		if err != nil {
 			return err
 		}
 
-		currentNamespace, ok := os.LookupEnv("CURRENT_NAMESPACE")
-		if !ok {
-			return fmt.Errorf("current namespace lookup failed")
-		}
-		err = worker.CreateWorker(ctx, cfg, kubeClient, &task, currentNamespace)
+		err = worker.CreateWorker(ctx, cfg, workerExecutor, &task)
 		if err != nil {
 			return err
 		}
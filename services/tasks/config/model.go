@@ -4,6 +4,7 @@ import (
 	"github.com/opengovern/og-util/pkg/config"
 	"github.com/opengovern/og-util/pkg/koanf"
 	"github.com/opengovern/og-util/pkg/vault"
+	"github.com/opengovern/opencomply/pkg/executor"
 )
 
 type Config struct {
@@ -13,5 +14,8 @@ type Config struct {
 	Vault         vault.Config     `yaml:"vault" koanf:"vault"`
 	ElasticSearch config.ElasticSearch
 
-	ESSinkEndpoint string `yaml:"essink_endpoint" koanf:"essink_endpoint"`
+	ESSinkEndpoint string                      `yaml:"essink_endpoint" koanf:"essink_endpoint"`
+	EsSink         koanf.OpenGovernanceService `yaml:"es_sink" koanf:"es_sink"`
+
+	Executor executor.Config `yaml:"executor" koanf:"executor"`
 }
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"errors"
 	"fmt"
 	"github.com/jackc/pgtype"
 	"github.com/opengovern/opencomply/services/tasks/db/models"
@@ -15,6 +16,10 @@ func (db Database) Initialize() error {
 	err := db.Orm.AutoMigrate(
 		&models.Task{},
 		&models.TaskRun{},
+		&models.Workflow{},
+		&models.WorkflowRun{},
+		&models.WorkflowRunStep{},
+		&models.SchedulerLease{},
 	)
 	if err != nil {
 		return err
@@ -32,10 +37,13 @@ func (db Database) CreateTask(task *models.Task) error {
 	return nil
 }
 
+// UpdateTask replaces the definition of the task, zero values included
 func (db Database) UpdateTask(id string, task *models.Task) error {
 	tx := db.Orm.
 		Model(&models.Task{}).
 		Where("id = ?", id).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
 		Updates(task)
 	if tx.Error != nil {
 		return tx.Error
@@ -43,12 +51,34 @@ func (db Database) UpdateTask(id string, task *models.Task) error {
 	return nil
 }
 
-// GetTask retrieves a task by Task name
+// DeleteTask removes the task and fails its pending runs
+func (db Database) DeleteTask(id string) error {
+	return db.Orm.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&models.TaskRun{}).
+			Where("task_id = ?", id).
+			Where("status IN ?", []string{string(models.TaskRunStatusCreated),
+				string(models.TaskRunStatusQueued),
+				string(models.TaskRunStatusInProgress),
+			}).
+			Updates(models.TaskRun{Status: models.TaskRunStatusFailed, FailureMessage: "task deleted"}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("id = ?", id).Delete(&models.Task{}).Error
+	})
+}
+
+// GetTask retrieves a task by Task ID, returns nil if the task does not exist
 func (db Database) GetTask(id string) (*models.Task, error) {
 	var task models.Task
 	tx := db.Orm.Where("id = ?", id).
 		First(&task)
 	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, tx.Error
 	}
 
@@ -68,10 +98,17 @@ func (db Database) GetTaskRunResult(id string) ([]models.TaskRun, error) {
 	return task, nil
 }
 
-// ListTaskRunResult retrieves a task result by Task ID
-func (db Database) ListTaskRunResult() ([]models.TaskRun, error) {
+// ListTaskRunResult retrieves the task runs, filtered by task ids and statuses when given
+func (db Database) ListTaskRunResult(taskIDs, statuses []string) ([]models.TaskRun, error) {
 	var task []models.TaskRun
-	tx := db.Orm.
+	tx := db.Orm.Model(&models.TaskRun{})
+	if len(taskIDs) > 0 {
+		tx = tx.Where("task_id IN ?", taskIDs)
+	}
+	if len(statuses) > 0 {
+		tx = tx.Where("status IN ?", statuses)
+	}
+	tx = tx.
 		Order("created_at desc").
 		Find(&task)
 	if tx.Error != nil {
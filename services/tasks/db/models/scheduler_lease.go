@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SchedulerLease is held by the tasks service replica that runs a scheduler loop. The holder renews it
+// periodically, and any replica may take it over once it has expired.
+type SchedulerLease struct {
+	Name       string `gorm:"primarykey"`
+	HolderID   string
+	AcquiredAt time.Time
+	RenewedAt  time.Time
+	ExpiresAt  time.Time
+}
@@ -5,15 +5,28 @@ import (
 	"gorm.io/gorm"
 )
 
+type TaskSource string
+
+const (
+	TaskSourceFile TaskSource = "file" // Read from the tasks directory at startup
+	TaskSourceAPI  TaskSource = "api"  // Registered or updated through the tasks API
+)
+
 type Task struct {
 	gorm.Model
-	ID          string `gorm:"primarykey"`
-	Name        string `gorm:"unique;not null"` // Enforces uniqueness and non-null constraint
-	ResultType  string
-	Description string
-	ImageUrl    string
-	Interval    uint64
-	Timeout     uint64
-	NatsConfig  pgtype.JSONB
-	ScaleConfig pgtype.JSONB
+	ID           string `gorm:"primarykey"`
+	Name         string `gorm:"unique;not null"` // Enforces uniqueness and non-null constraint
+	ResultType   string
+	Description  string
+	ImageUrl     string
+	Command      string
+	WorkloadType string
+	EnvVars      pgtype.JSONB
+	Interval     uint64
+	Timeout      uint64
+	NatsConfig   pgtype.JSONB
+	ScaleConfig  pgtype.JSONB
+	ParamsSchema pgtype.JSONB
+	ResultSchema pgtype.JSONB
+	Source       TaskSource
 }
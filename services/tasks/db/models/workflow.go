@@ -0,0 +1,53 @@
+package models
+
+import (
+	"github.com/jackc/pgtype"
+	"gorm.io/gorm"
+)
+
+type WorkflowRunStatus string
+
+const (
+	WorkflowRunStatusRunning   WorkflowRunStatus = "RUNNING"
+	WorkflowRunStatusSucceeded WorkflowRunStatus = "SUCCEEDED"
+	WorkflowRunStatusFailed    WorkflowRunStatus = "FAILED"
+)
+
+type WorkflowStepStatus string
+
+const (
+	WorkflowStepStatusPending   WorkflowStepStatus = "PENDING"
+	WorkflowStepStatusRunning   WorkflowStepStatus = "RUNNING"
+	WorkflowStepStatusSucceeded WorkflowStepStatus = "SUCCEEDED"
+	WorkflowStepStatusFailed    WorkflowStepStatus = "FAILED"
+	WorkflowStepStatusSkipped   WorkflowStepStatus = "SKIPPED"
+)
+
+type Workflow struct {
+	gorm.Model
+	ID          string `gorm:"primarykey"`
+	Name        string `gorm:"unique;not null"`
+	Description string
+	Steps       pgtype.JSONB
+}
+
+type WorkflowRun struct {
+	gorm.Model
+	WorkflowID string
+	// Steps is the snapshot of the workflow steps the run was started with
+	Steps          pgtype.JSONB
+	Params         pgtype.JSONB
+	Status         WorkflowRunStatus
+	FailureMessage string
+}
+
+type WorkflowRunStep struct {
+	gorm.Model
+	WorkflowRunID  uint `gorm:"index"`
+	StepID         string
+	Status         WorkflowStepStatus
+	Attempts       int
+	TaskRunID      *uint
+	Result         pgtype.JSONB
+	FailureMessage string
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/opengovern/opencomply/services/tasks/db/models"
+	"gorm.io/gorm"
+)
+
+func (db Database) CreateWorkflow(workflow *models.Workflow) error {
+	tx := db.Orm.Create(workflow)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	return nil
+}
+
+// UpdateWorkflow replaces the definition of the workflow, the active runs keep the steps they were started with
+func (db Database) UpdateWorkflow(id string, workflow *models.Workflow) error {
+	tx := db.Orm.
+		Model(&models.Workflow{}).
+		Where("id = ?", id).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(workflow)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// DeleteWorkflow removes the workflow and fails its active runs
+func (db Database) DeleteWorkflow(id string) error {
+	return db.Orm.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&models.WorkflowRun{}).
+			Where("workflow_id = ?", id).
+			Where("status = ?", models.WorkflowRunStatusRunning).
+			Updates(models.WorkflowRun{Status: models.WorkflowRunStatusFailed, FailureMessage: "workflow deleted"}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("id = ?", id).Delete(&models.Workflow{}).Error
+	})
+}
+
+// GetWorkflow retrieves a workflow by ID, returns nil if the workflow does not exist
+func (db Database) GetWorkflow(id string) (*models.Workflow, error) {
+	var workflow models.Workflow
+	tx := db.Orm.Where("id = ?", id).
+		First(&workflow)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+
+	return &workflow, nil
+}
+
+func (db Database) ListWorkflows() ([]models.Workflow, error) {
+	var workflows []models.Workflow
+	tx := db.Orm.Order("created_at desc").Find(&workflows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	return workflows, nil
+}
+
+// CreateWorkflowRun creates the run and a pending state for each of its steps
+func (db Database) CreateWorkflowRun(run *models.WorkflowRun, stepIDs []string) error {
+	return db.Orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		for _, stepID := range stepIDs {
+			step := models.WorkflowRunStep{
+				WorkflowRunID: run.ID,
+				StepID:        stepID,
+				Status:        models.WorkflowStepStatusPending,
+			}
+			if err := step.Result.Set([]byte("{}")); err != nil {
+				return err
+			}
+			if err := tx.Create(&step).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetWorkflowRun retrieves a workflow run by ID, returns nil if the run does not exist
+func (db Database) GetWorkflowRun(id uint) (*models.WorkflowRun, error) {
+	var run models.WorkflowRun
+	tx := db.Orm.Where("id = ?", id).
+		First(&run)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+
+	return &run, nil
+}
+
+func (db Database) ListWorkflowRuns(workflowID string) ([]models.WorkflowRun, error) {
+	var runs []models.WorkflowRun
+	tx := db.Orm.Where("workflow_id = ?", workflowID).
+		Order("created_at desc").
+		Find(&runs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	return runs, nil
+}
+
+func (db Database) ListRunningWorkflowRuns() ([]models.WorkflowRun, error) {
+	var runs []models.WorkflowRun
+	tx := db.Orm.Where("status = ?", models.WorkflowRunStatusRunning).
+		Order("created_at asc").
+		Find(&runs)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	return runs, nil
+}
+
+func (db Database) UpdateWorkflowRunStatus(id uint, status models.WorkflowRunStatus, failureMessage string) error {
+	tx := db.Orm.
+		Model(&models.WorkflowRun{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": status, "failure_message": failureMessage})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+func (db Database) ListWorkflowRunSteps(runID uint) ([]models.WorkflowRunStep, error) {
+	var steps []models.WorkflowRunStep
+	tx := db.Orm.Where("workflow_run_id = ?", runID).
+		Order("id asc").
+		Find(&steps)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	return steps, nil
+}
+
+// UpdateWorkflowRunStep stores the state of the step, zero values included
+func (db Database) UpdateWorkflowRunStep(step *models.WorkflowRunStep) error {
+	tx := db.Orm.
+		Model(&models.WorkflowRunStep{}).
+		Where("id = ?", step.ID).
+		Select("status", "attempts", "task_run_id", "result", "failure_message").
+		Updates(step)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// GetTaskRun retrieves a task run by ID, returns nil if the run does not exist
+func (db Database) GetTaskRun(id uint) (*models.TaskRun, error) {
+	var run models.TaskRun
+	tx := db.Orm.Where("id = ?", id).
+		First(&run)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, tx.Error
+	}
+
+	return &run, nil
+}
@@ -3,14 +3,21 @@ package tasks
 import (
 	"crypto/rsa"
 	"encoding/json"
+	"fmt"
 	api2 "github.com/opengovern/og-util/pkg/api"
 	"github.com/opengovern/og-util/pkg/httpserver"
+	"github.com/opengovern/opencomply/pkg/audit"
+	"github.com/opengovern/opencomply/pkg/executor"
 	"github.com/opengovern/opencomply/pkg/utils"
 	"github.com/opengovern/opencomply/services/tasks/api"
+	"github.com/opengovern/opencomply/services/tasks/config"
 	"github.com/opengovern/opencomply/services/tasks/db"
 	"github.com/opengovern/opencomply/services/tasks/db/models"
+	"github.com/opengovern/opencomply/services/tasks/scheduler"
+	"github.com/opengovern/opencomply/services/tasks/worker"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -18,24 +25,57 @@ import (
 
 type httpRoutes struct {
 	logger *zap.Logger
+	cfg    config.Config
 
 	platformPrivateKey *rsa.PrivateKey
 	db                 db.Database
+	executor           executor.Executor
+	scheduler          *scheduler.MainScheduler
+	auditRecorder      *audit.Recorder
 }
 
 func (r *httpRoutes) Register(e *echo.Echo) {
+	auditLog := r.auditRecorder.Middleware()
+
 	v1 := e.Group("/api/v1")
 	// List all tasks
 	v1.GET("/tasks", httpserver.AuthorizeHandler(r.ListTasks, api2.ViewerRole))
+	// Register a task definition
+	v1.POST("/tasks", httpserver.AuthorizeHandler(r.CreateTask, api2.AdminRole), auditLog)
+	// List the result schemas of the tasks
+	v1.GET("/tasks/result-schemas", httpserver.AuthorizeHandler(r.ListTaskResultSchemas, api2.ViewerRole))
 	// Get task
 	v1.GET("/tasks/:id", httpserver.AuthorizeHandler(r.GetTask, api2.ViewerRole))
+	// Get task definition
+	v1.GET("/tasks/:id/definition", httpserver.AuthorizeHandler(r.GetTaskDefinition, api2.ViewerRole))
+	// Update a task definition
+	v1.PUT("/tasks/:id", httpserver.AuthorizeHandler(r.UpdateTask, api2.AdminRole), auditLog)
+	// Delete a task definition
+	v1.DELETE("/tasks/:id", httpserver.AuthorizeHandler(r.DeleteTask, api2.AdminRole), auditLog)
 	// Create a new task
-	v1.POST("/tasks/run", httpserver.AuthorizeHandler(r.RunTask, api2.EditorRole))
+	v1.POST("/tasks/run", httpserver.AuthorizeHandler(r.RunTask, api2.EditorRole), auditLog)
 	// Get Task Result
 	v1.GET("/tasks/run/:id", httpserver.AuthorizeHandler(r.GetTaskRunResult, api2.ViewerRole))
 	// List Tasks Result
 	v1.GET("/tasks/run", httpserver.AuthorizeHandler(r.ListTaskRunResult, api2.ViewerRole))
 
+	// List workflows
+	v1.GET("/workflows", httpserver.AuthorizeHandler(r.ListWorkflows, api2.ViewerRole))
+	// Create a workflow
+	v1.POST("/workflows", httpserver.AuthorizeHandler(r.CreateWorkflow, api2.AdminRole), auditLog)
+	// Get workflow
+	v1.GET("/workflows/:id", httpserver.AuthorizeHandler(r.GetWorkflow, api2.ViewerRole))
+	// Update a workflow
+	v1.PUT("/workflows/:id", httpserver.AuthorizeHandler(r.UpdateWorkflow, api2.AdminRole), auditLog)
+	// Delete a workflow
+	v1.DELETE("/workflows/:id", httpserver.AuthorizeHandler(r.DeleteWorkflow, api2.AdminRole), auditLog)
+	// Run a workflow
+	v1.POST("/workflows/:id/run", httpserver.AuthorizeHandler(r.RunWorkflow, api2.EditorRole), auditLog)
+	// List workflow runs
+	v1.GET("/workflows/:id/runs", httpserver.AuthorizeHandler(r.ListWorkflowRuns, api2.ViewerRole))
+	// Get workflow run
+	v1.GET("/workflow-runs/:id", httpserver.AuthorizeHandler(r.GetWorkflowRun, api2.ViewerRole))
+
 }
 
 func bindValidate(ctx echo.Context, i interface{}) error {
@@ -50,6 +90,41 @@ func bindValidate(ctx echo.Context, i interface{}) error {
 	return nil
 }
 
+// ListTaskResultSchemas godoc
+//
+//	@Summary		List task result schemas
+//	@Description	List the result schemas declared by the tasks, CloudQL generates a table for each of them
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Produce		json
+//	@Success		200	{object}	[]api.TaskResultSchema
+//	@Router			/tasks/api/v1/tasks/result-schemas [get]
+func (r *httpRoutes) ListTaskResultSchemas(ctx echo.Context) error {
+	items, err := r.db.GetTaskList()
+	if err != nil {
+		r.logger.Error("failed to get tasks", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get tasks")
+	}
+
+	schemas := make([]api.TaskResultSchema, 0)
+	for _, item := range items {
+		task, err := taskFromModel(item)
+		if err != nil {
+			r.logger.Error("failed to read task", zap.String("task_id", item.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to read task")
+		}
+		if task.ResultSchema == nil {
+			continue
+		}
+		schemas = append(schemas, api.TaskResultSchema{
+			TaskID:     task.ID,
+			ResultType: task.ResultType,
+			Columns:    resultSchemaToApi(task.ResultSchema).Columns,
+		})
+	}
+	return ctx.JSON(http.StatusOK, schemas)
+}
+
 // ListTasks godoc
 //
 //	@Summary	List tasks
@@ -130,6 +205,9 @@ func (r *httpRoutes) GetTask(ctx echo.Context) error {
 		r.logger.Error("failed to get task results", zap.Error(err))
 		return ctx.JSON(http.StatusInternalServerError, "failed to get task results")
 	}
+	if task == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "task not found")
+	}
 	var taskResponse api.TaskResponse
 	taskResponse = api.TaskResponse{
 		ID:          task.ID,
@@ -166,6 +244,16 @@ func (r *httpRoutes) RunTask(ctx echo.Context) error {
 		r.logger.Error("failed to find task", zap.String("task", req.TaskID))
 		return ctx.JSON(http.StatusInternalServerError, "failed to find task")
 	}
+	taskDefinition, err := taskFromModel(*task)
+	if err != nil {
+		r.logger.Error("failed to read task definition", zap.String("task", req.TaskID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read task definition")
+	}
+	if err := worker.ValidateParams(taskDefinition.ParamsSchema, req.Params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid params: %s", err.Error()))
+	}
+	audit.SetAction(ctx, "task.run")
+	audit.SetTarget(ctx, "task", req.TaskID)
 
 	run := models.TaskRun{
 		TaskID: req.TaskID,
@@ -273,6 +361,8 @@ func (r *httpRoutes) GetTaskRunResult(ctx echo.Context) error {
 //	@Tags		scheduler
 //	@Param		cursor			query	int		false	"cursor"
 //	@Param		per_page		query	int		false	"per page"
+//	@Param		task_id			query	[]string	false	"task id"
+//	@Param		status			query	[]string	false	"task run status"
 //	@Produce	json
 //	@Success	200	{object}	api.ListTaskRunsResponse
 //	@Router		/tasks/api/v1/tasks/run [get]
@@ -294,7 +384,7 @@ func (r *httpRoutes) ListTaskRunResult(ctx echo.Context) error {
 		}
 	}
 
-	items, err := r.db.ListTaskRunResult()
+	items, err := r.db.ListTaskRunResult(httpserver.QueryArrayParam(ctx, "task_id"), httpserver.QueryArrayParam(ctx, "status"))
 	if err != nil {
 		r.logger.Error("failed to get task results", zap.Error(err))
 		return ctx.JSON(http.StatusInternalServerError, "failed to get task results")
@@ -338,3 +428,222 @@ func (r *httpRoutes) ListTaskRunResult(ctx echo.Context) error {
 		Items:      taskRunResponses,
 	})
 }
+
+// GetTaskDefinition godoc
+//
+//	@Summary	Get task definition
+//	@Security	BearerToken
+//	@Tags		scheduler
+//	@Param		id	path	string	true	"task id"
+//	@Produce	json
+//	@Success	200	{object}	api.TaskDefinition
+//	@Router		/tasks/api/v1/tasks/{id}/definition [get]
+func (r *httpRoutes) GetTaskDefinition(ctx echo.Context) error {
+	task, err := r.db.GetTask(ctx.Param("id"))
+	if err != nil {
+		r.logger.Error("failed to get task", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get task")
+	}
+	if task == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "task not found")
+	}
+
+	definition, err := taskFromModel(*task)
+	if err != nil {
+		r.logger.Error("failed to read task definition", zap.String("task", task.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read task definition")
+	}
+
+	return ctx.JSON(http.StatusOK, taskToDefinition(definition, task.Source))
+}
+
+// CreateTask godoc
+//
+//	@Summary		Register a task
+//	@Description	Registers a task definition and creates its worker Deployment and KEDA ScaledObject
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			request	body	api.TaskDefinition	true	"Task definition"
+//	@Produce		json
+//	@Success		201	{object}	api.TaskDefinition
+//	@Router			/tasks/api/v1/tasks [post]
+func (r *httpRoutes) CreateTask(ctx echo.Context) error {
+	var req api.TaskDefinition
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+	task := taskFromDefinition(req)
+	fillMissedConfigs(&task)
+	if err := worker.ValidateTask(r.cfg, task); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	audit.SetAction(ctx, "task.create")
+	audit.SetTarget(ctx, "task", task.ID)
+
+	existing, err := r.db.GetTask(task.ID)
+	if err != nil {
+		r.logger.Error("failed to get task", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get task")
+	}
+	if existing != nil {
+		return echo.NewHTTPError(http.StatusConflict, "task already exists")
+	}
+
+	if err := r.applyTask(ctx, task, true); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, taskToDefinition(task, models.TaskSourceAPI))
+}
+
+// UpdateTask godoc
+//
+//	@Summary		Update a task
+//	@Description	Replaces a task definition and reconciles its worker, tasks read from the tasks directory keep the new definition across restarts
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			id		path	string				true	"task id"
+//	@Param			request	body	api.TaskDefinition	true	"Task definition"
+//	@Produce		json
+//	@Success		200	{object}	api.TaskDefinition
+//	@Router			/tasks/api/v1/tasks/{id} [put]
+func (r *httpRoutes) UpdateTask(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	var req api.TaskDefinition
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+	if req.ID == "" {
+		req.ID = id
+	}
+	if req.ID != id {
+		return echo.NewHTTPError(http.StatusBadRequest, "task id can not be changed")
+	}
+	task := taskFromDefinition(req)
+	fillMissedConfigs(&task)
+	if err := worker.ValidateTask(r.cfg, task); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	existing, err := r.db.GetTask(id)
+	if err != nil {
+		r.logger.Error("failed to get task", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get task")
+	}
+	if existing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "task not found")
+	}
+	audit.SetAction(ctx, "task.update")
+	audit.SetTarget(ctx, "task", id)
+	if before, err := taskFromModel(*existing); err == nil {
+		audit.SetBefore(ctx, taskToDefinition(before, existing.Source))
+	}
+
+	if err := r.applyTask(ctx, task, false); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, taskToDefinition(task, models.TaskSourceAPI))
+}
+
+// DeleteTask godoc
+//
+//	@Summary		Delete a task
+//	@Description	Deletes an API registered task, its worker, its scheduler and its NATS stream and consumers. Pending runs of the task are failed.
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			id	path	string	true	"task id"
+//	@Success		200
+//	@Router			/tasks/api/v1/tasks/{id} [delete]
+func (r *httpRoutes) DeleteTask(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	task, err := r.db.GetTask(id)
+	if err != nil {
+		r.logger.Error("failed to get task", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get task")
+	}
+	if task == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "task not found")
+	}
+	// tasks of the tasks directory would be registered again on the next restart
+	if task.Source != models.TaskSourceAPI {
+		return echo.NewHTTPError(http.StatusConflict, "task is defined in the tasks directory, remove its file instead")
+	}
+	workflowIDs, err := r.workflowsUsingTask(id)
+	if err != nil {
+		r.logger.Error("failed to list workflows", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list workflows")
+	}
+	if len(workflowIDs) > 0 {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("task is used by workflows: %s", strings.Join(workflowIDs, ", ")))
+	}
+	audit.SetAction(ctx, "task.delete")
+	if before, err := taskFromModel(*task); err == nil {
+		audit.SetBefore(ctx, taskToDefinition(before, task.Source))
+	}
+
+	r.scheduler.StopTask(id)
+
+	if err := worker.DeleteWorker(ctx.Request().Context(), r.executor, id); err != nil {
+		r.logger.Error("failed to delete task worker", zap.String("task", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete task worker")
+	}
+
+	if err := r.scheduler.DeleteNats(ctx.Request().Context(), *task); err != nil {
+		r.logger.Error("failed to delete task streams", zap.String("task", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete task streams")
+	}
+
+	if err := r.db.DeleteTask(id); err != nil {
+		r.logger.Error("failed to delete task", zap.String("task", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete task")
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// applyTask stores the task definition, reconciles its worker and (re)starts its scheduler. The definition is stored
+// first and restored when the worker cannot be reconciled, so the worker never runs a definition missing from the
+// database.
+func (r *httpRoutes) applyTask(ctx echo.Context, task worker.Task, create bool) error {
+	taskModel, err := newTaskModel(task, models.TaskSourceAPI)
+	if err != nil {
+		r.logger.Error("failed to build task", zap.String("task", task.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to build task")
+	}
+
+	var previous *models.Task
+	if create {
+		err = r.db.CreateTask(taskModel)
+	} else {
+		previous, err = r.db.GetTask(task.ID)
+		if err == nil {
+			err = r.db.UpdateTask(task.ID, taskModel)
+		}
+	}
+	if err != nil {
+		r.logger.Error("failed to store task", zap.String("task", task.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to store task")
+	}
+
+	if err := worker.CreateWorker(ctx.Request().Context(), r.cfg, r.executor, &task); err != nil {
+		r.logger.Error("failed to reconcile task worker", zap.String("task", task.ID), zap.Error(err))
+		if previous != nil {
+			err = r.db.UpdateTask(task.ID, previous)
+		} else {
+			err = r.db.DeleteTask(task.ID)
+		}
+		if err != nil {
+			r.logger.Error("failed to roll back task", zap.String("task", task.ID), zap.Error(err))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to reconcile task worker")
+	}
+
+	if err := r.scheduler.StartTask(*taskModel); err != nil {
+		r.logger.Error("failed to start task scheduler", zap.String("task", task.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to start task scheduler")
+	}
+	return nil
+}
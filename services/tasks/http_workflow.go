@@ -0,0 +1,408 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/opengovern/opencomply/pkg/audit"
+	"github.com/opengovern/opencomply/pkg/utils"
+	"github.com/opengovern/opencomply/services/tasks/api"
+	"github.com/opengovern/opencomply/services/tasks/db/models"
+	"github.com/opengovern/opencomply/services/tasks/workflow"
+	"go.uber.org/zap"
+)
+
+// ListWorkflows godoc
+//
+//	@Summary	List workflows
+//	@Security	BearerToken
+//	@Tags		scheduler
+//	@Param		cursor		query	int	false	"cursor"
+//	@Param		per_page	query	int	false	"per page"
+//	@Produce	json
+//	@Success	200	{object}	api.ListWorkflowsResponse
+//	@Router		/tasks/api/v1/workflows [get]
+func (r *httpRoutes) ListWorkflows(ctx echo.Context) error {
+	cursor, perPage, err := parsePagination(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	items, err := r.db.ListWorkflows()
+	if err != nil {
+		r.logger.Error("failed to list workflows", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list workflows")
+	}
+
+	totalCount := len(items)
+	if perPage != 0 {
+		items = utils.Paginate(cursor, perPage, items)
+	}
+	definitions := make([]api.WorkflowDefinition, 0, len(items))
+	for _, item := range items {
+		definition, err := workflowFromModel(item)
+		if err != nil {
+			r.logger.Error("failed to read workflow", zap.String("workflow", item.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to read workflow")
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return ctx.JSON(http.StatusOK, api.ListWorkflowsResponse{
+		Items:      definitions,
+		TotalCount: totalCount,
+	})
+}
+
+// GetWorkflow godoc
+//
+//	@Summary	Get workflow
+//	@Security	BearerToken
+//	@Tags		scheduler
+//	@Param		id	path	string	true	"workflow id"
+//	@Produce	json
+//	@Success	200	{object}	api.WorkflowDefinition
+//	@Router		/tasks/api/v1/workflows/{id} [get]
+func (r *httpRoutes) GetWorkflow(ctx echo.Context) error {
+	wf, err := r.db.GetWorkflow(ctx.Param("id"))
+	if err != nil {
+		r.logger.Error("failed to get workflow", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get workflow")
+	}
+	if wf == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "workflow not found")
+	}
+
+	definition, err := workflowFromModel(*wf)
+	if err != nil {
+		r.logger.Error("failed to read workflow", zap.String("workflow", wf.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read workflow")
+	}
+
+	return ctx.JSON(http.StatusOK, definition)
+}
+
+// CreateWorkflow godoc
+//
+//	@Summary		Create a workflow
+//	@Description	Creates a workflow of task steps, the steps run once their dependencies are done
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			request	body	api.WorkflowDefinition	true	"Workflow definition"
+//	@Produce		json
+//	@Success		201	{object}	api.WorkflowDefinition
+//	@Router			/tasks/api/v1/workflows [post]
+func (r *httpRoutes) CreateWorkflow(ctx echo.Context) error {
+	var req api.WorkflowDefinition
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+	if err := workflow.Validate(req, r.taskExists); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	audit.SetAction(ctx, "workflow.create")
+	audit.SetTarget(ctx, "workflow", req.ID)
+
+	existing, err := r.db.GetWorkflow(req.ID)
+	if err != nil {
+		r.logger.Error("failed to get workflow", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get workflow")
+	}
+	if existing != nil {
+		return echo.NewHTTPError(http.StatusConflict, "workflow already exists")
+	}
+
+	wf, err := newWorkflowModel(req)
+	if err != nil {
+		r.logger.Error("failed to build workflow", zap.String("workflow", req.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to build workflow")
+	}
+	if err := r.db.CreateWorkflow(wf); err != nil {
+		r.logger.Error("failed to create workflow", zap.String("workflow", req.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create workflow")
+	}
+
+	return ctx.JSON(http.StatusCreated, req)
+}
+
+// UpdateWorkflow godoc
+//
+//	@Summary		Update a workflow
+//	@Description	Replaces a workflow definition, the running workflow runs keep the steps they were started with
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			id		path	string					true	"workflow id"
+//	@Param			request	body	api.WorkflowDefinition	true	"Workflow definition"
+//	@Produce		json
+//	@Success		200	{object}	api.WorkflowDefinition
+//	@Router			/tasks/api/v1/workflows/{id} [put]
+func (r *httpRoutes) UpdateWorkflow(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	var req api.WorkflowDefinition
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+	if req.ID == "" {
+		req.ID = id
+	}
+	if req.ID != id {
+		return echo.NewHTTPError(http.StatusBadRequest, "workflow id can not be changed")
+	}
+	if err := workflow.Validate(req, r.taskExists); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	existing, err := r.db.GetWorkflow(id)
+	if err != nil {
+		r.logger.Error("failed to get workflow", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get workflow")
+	}
+	if existing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "workflow not found")
+	}
+	audit.SetAction(ctx, "workflow.update")
+	audit.SetTarget(ctx, "workflow", id)
+	if before, err := workflowFromModel(*existing); err == nil {
+		audit.SetBefore(ctx, before)
+	}
+
+	wf, err := newWorkflowModel(req)
+	if err != nil {
+		r.logger.Error("failed to build workflow", zap.String("workflow", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to build workflow")
+	}
+	if err := r.db.UpdateWorkflow(id, wf); err != nil {
+		r.logger.Error("failed to update workflow", zap.String("workflow", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update workflow")
+	}
+
+	return ctx.JSON(http.StatusOK, req)
+}
+
+// DeleteWorkflow godoc
+//
+//	@Summary		Delete a workflow
+//	@Description	Deletes a workflow and fails its running workflow runs, the started task runs are not cancelled
+//	@Security		BearerToken
+//	@Tags			scheduler
+//	@Param			id	path	string	true	"workflow id"
+//	@Success		200
+//	@Router			/tasks/api/v1/workflows/{id} [delete]
+func (r *httpRoutes) DeleteWorkflow(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	existing, err := r.db.GetWorkflow(id)
+	if err != nil {
+		r.logger.Error("failed to get workflow", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get workflow")
+	}
+	if existing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "workflow not found")
+	}
+	audit.SetAction(ctx, "workflow.delete")
+	if before, err := workflowFromModel(*existing); err == nil {
+		audit.SetBefore(ctx, before)
+	}
+
+	if err := r.db.DeleteWorkflow(id); err != nil {
+		r.logger.Error("failed to delete workflow", zap.String("workflow", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete workflow")
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// RunWorkflow godoc
+//
+//	@Summary	Run a workflow
+//	@Security	BearerToken
+//	@Tags		scheduler
+//	@Param		id		path	string					true	"workflow id"
+//	@Param		request	body	api.RunWorkflowRequest	true	"Run workflow request"
+//	@Produce	json
+//	@Success	201	{object}	api.WorkflowRun
+//	@Router		/tasks/api/v1/workflows/{id}/run [post]
+func (r *httpRoutes) RunWorkflow(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	var req api.RunWorkflowRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+	if req.Params == nil {
+		req.Params = map[string]any{}
+	}
+
+	wf, err := r.db.GetWorkflow(id)
+	if err != nil {
+		r.logger.Error("failed to get workflow", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get workflow")
+	}
+	if wf == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "workflow not found")
+	}
+	definition, err := workflowFromModel(*wf)
+	if err != nil {
+		r.logger.Error("failed to read workflow", zap.String("workflow", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read workflow")
+	}
+	order, err := workflow.Order(definition.Steps)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	audit.SetAction(ctx, "workflow.run")
+	audit.SetTarget(ctx, "workflow", id)
+
+	paramsJsonb, err := toJSONB(req.Params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid params")
+	}
+	run := models.WorkflowRun{
+		WorkflowID: id,
+		Steps:      wf.Steps,
+		Params:     paramsJsonb,
+		Status:     models.WorkflowRunStatusRunning,
+	}
+	if err := r.db.CreateWorkflowRun(&run, order); err != nil {
+		r.logger.Error("failed to create workflow run", zap.String("workflow", id), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create workflow run")
+	}
+
+	return r.workflowRunResponse(ctx, http.StatusCreated, run)
+}
+
+// ListWorkflowRuns godoc
+//
+//	@Summary	List workflow runs
+//	@Security	BearerToken
+//	@Tags		scheduler
+//	@Param		id			path	string	true	"workflow id"
+//	@Param		cursor		query	int		false	"cursor"
+//	@Param		per_page	query	int		false	"per page"
+//	@Produce	json
+//	@Success	200	{object}	api.ListWorkflowRunsResponse
+//	@Router		/tasks/api/v1/workflows/{id}/runs [get]
+func (r *httpRoutes) ListWorkflowRuns(ctx echo.Context) error {
+	cursor, perPage, err := parsePagination(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	runs, err := r.db.ListWorkflowRuns(ctx.Param("id"))
+	if err != nil {
+		r.logger.Error("failed to list workflow runs", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list workflow runs")
+	}
+
+	totalCount := len(runs)
+	if perPage != 0 {
+		runs = utils.Paginate(cursor, perPage, runs)
+	}
+	items := make([]api.WorkflowRun, 0, len(runs))
+	for _, run := range runs {
+		item, err := workflowRunFromModel(run, nil)
+		if err != nil {
+			r.logger.Error("failed to read workflow run", zap.Uint("runId", run.ID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to read workflow run")
+		}
+		items = append(items, item)
+	}
+
+	return ctx.JSON(http.StatusOK, api.ListWorkflowRunsResponse{
+		Items:      items,
+		TotalCount: totalCount,
+	})
+}
+
+// GetWorkflowRun godoc
+//
+//	@Summary	Get workflow run with the state of its steps
+//	@Security	BearerToken
+//	@Tags		scheduler
+//	@Param		id	path	int	true	"workflow run id"
+//	@Produce	json
+//	@Success	200	{object}	api.WorkflowRun
+//	@Router		/tasks/api/v1/workflow-runs/{id} [get]
+func (r *httpRoutes) GetWorkflowRun(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid workflow run id")
+	}
+
+	run, err := r.db.GetWorkflowRun(uint(id))
+	if err != nil {
+		r.logger.Error("failed to get workflow run", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get workflow run")
+	}
+	if run == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "workflow run not found")
+	}
+
+	return r.workflowRunResponse(ctx, http.StatusOK, *run)
+}
+
+func (r *httpRoutes) workflowRunResponse(ctx echo.Context, status int, run models.WorkflowRun) error {
+	steps, err := r.db.ListWorkflowRunSteps(run.ID)
+	if err != nil {
+		r.logger.Error("failed to list workflow run steps", zap.Uint("runId", run.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list workflow run steps")
+	}
+	resp, err := workflowRunFromModel(run, steps)
+	if err != nil {
+		r.logger.Error("failed to read workflow run", zap.Uint("runId", run.ID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read workflow run")
+	}
+	return ctx.JSON(status, resp)
+}
+
+func (r *httpRoutes) taskExists(taskID string) (bool, error) {
+	task, err := r.db.GetTask(taskID)
+	if err != nil {
+		return false, err
+	}
+	return task != nil, nil
+}
+
+// workflowsUsingTask returns the ids of the workflows with a step running the task
+func (r *httpRoutes) workflowsUsingTask(taskID string) ([]string, error) {
+	workflows, err := r.db.ListWorkflows()
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, wf := range workflows {
+		var steps []api.WorkflowStep
+		if err := json.Unmarshal(wf.Steps.Bytes, &steps); err != nil {
+			return nil, fmt.Errorf("failed to read workflow %s: %w", wf.ID, err)
+		}
+		for _, step := range steps {
+			if step.TaskID == taskID {
+				ids = append(ids, wf.ID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+func parsePagination(ctx echo.Context) (int64, int64, error) {
+	var cursor, perPage int64
+	var err error
+	if cursorStr := ctx.QueryParam("cursor"); cursorStr != "" {
+		cursor, err = strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid cursor")
+		}
+	}
+	if perPageStr := ctx.QueryParam("per_page"); perPageStr != "" {
+		perPage, err = strconv.ParseInt(perPageStr, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid per_page")
+		}
+	}
+	return cursor, perPage, nil
+}
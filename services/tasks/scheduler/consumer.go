@@ -17,7 +17,7 @@ type TaskResponse struct {
 }
 
 func (s *TaskScheduler) RunTaskResponseConsumer(ctx context.Context) error {
-	if _, err := s.jq.Consume(ctx, s.NatsConfig.ResultConsumer, s.NatsConfig.Stream, []string{s.NatsConfig.ResultTopic},
+	consumeCtx, err := s.jq.Consume(ctx, s.NatsConfig.ResultConsumer, s.NatsConfig.Stream, []string{s.NatsConfig.ResultTopic},
 		s.NatsConfig.ResultConsumer, func(msg jetstream.Msg) {
 			if err := msg.Ack(); err != nil {
 				s.logger.Error("Failed committing message", zap.Error(err))
@@ -50,10 +50,12 @@ func (s *TaskScheduler) RunTaskResponseConsumer(ctx context.Context) error {
 					zap.Error(err))
 				return
 			}
-		}); err != nil {
+		})
+	if err != nil {
 		return err
 	}
 
 	<-ctx.Done()
+	consumeCtx.Stop()
 	return nil
 }
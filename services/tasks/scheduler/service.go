@@ -2,28 +2,41 @@ package scheduler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/jackc/pgtype"
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/opengovern/og-util/pkg/jq"
+	"github.com/opengovern/opencomply/pkg/leaderelection"
+	"github.com/opengovern/opencomply/pkg/utils"
 	"github.com/opengovern/opencomply/services/tasks/config"
 	"github.com/opengovern/opencomply/services/tasks/db"
+	"github.com/opengovern/opencomply/services/tasks/db/models"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
+	"os"
+	"sync"
 	"time"
 )
 
 type MainScheduler struct {
-	jq     *jq.JobQueue
+	jq *jq.JobQueue
+	// js removes the streams and consumers of the deleted tasks, the job queue only creates them
+	js     jetstream.JetStream
 	db     db.Database
 	logger *zap.Logger
 
 	cfg config.Config
 
-	Tasks []TaskScheduler
-}
+	// leader keeps the loops shared by all the replicas, e.g. the workflow scheduler, on a single replica
+	leader *leaderelection.Elector
 
-var RunningTasks = make(map[string]bool)
+	// ctx is the context of the service, task schedulers started by the API outlive the requests starting them
+	ctx            context.Context
+	runningTasksMu sync.Mutex
+	runningTasks   map[string]context.CancelFunc
+}
 
 func NewMainScheduler(cfg config.Config, logger *zap.Logger, db db.Database) (*MainScheduler, error) {
 	jq, err := jq.New(cfg.NATS.URL, logger)
@@ -31,16 +44,37 @@ func NewMainScheduler(cfg config.Config, logger *zap.Logger, db db.Database) (*M
 		logger.Error("Failed to create job queue", zap.Error(err))
 		return nil, err
 	}
+	nc, err := nats.Connect(cfg.NATS.URL)
+	if err != nil {
+		logger.Error("Failed to connect to nats", zap.Error(err))
+		return nil, err
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		logger.Error("Failed to create jetstream client", zap.Error(err))
+		return nil, err
+	}
+
+	// replicas are told apart by their pod hostname
+	holderID := "tasks"
+	if hostname, err := os.Hostname(); err == nil {
+		holderID = fmt.Sprintf("%s-%s", holderID, hostname)
+	}
 
 	return &MainScheduler{
-		jq:     jq,
-		db:     db,
-		logger: logger,
-		cfg:    cfg,
+		jq:           jq,
+		js:           js,
+		db:           db,
+		logger:       logger,
+		cfg:          cfg,
+		leader:       leaderelection.New(holderID, db, logger),
+		runningTasks: make(map[string]context.CancelFunc),
 	}, nil
 }
 
 func (s *MainScheduler) Start(ctx context.Context) error {
+	s.ctx = ctx
+
 	tasks, err := s.db.GetTaskList()
 	if err != nil {
 		s.logger.Error("failed to get task list", zap.Error(err))
@@ -48,42 +82,119 @@ func (s *MainScheduler) Start(ctx context.Context) error {
 	}
 
 	for _, task := range tasks {
-		if _, ok := RunningTasks[task.ID]; ok {
-			continue
+		if err := s.StartTask(task); err != nil {
+			return err
 		}
-		var natsConfig NatsConfig
-		if task.NatsConfig.Status != pgtype.Present {
-			return fmt.Errorf("JSONB data is not present")
+	}
+
+	utils.EnsureRunGoroutine(func() {
+		s.leader.Run(ctx)
+	})
+	utils.EnsureRunGoroutine(func() {
+		s.RunWorkflowScheduler(ctx)
+	})
+	return nil
+}
+
+// StartTask starts the publisher and the result consumer of the task, a task that is already running is restarted
+// to pick up its new definition
+func (s *MainScheduler) StartTask(task models.Task) error {
+	natsConfig, err := taskNatsConfig(task)
+	if err != nil {
+		return err
+	}
+
+	err = s.SetupNats(s.ctx, task.ID, natsConfig)
+	if err != nil {
+		s.logger.Error("Failed to setup nats streams", zap.Error(err))
+		return err
+	}
+
+	s.StopTask(task.ID)
+
+	s.runningTasksMu.Lock()
+	defer s.runningTasksMu.Unlock()
+
+	taskCtx, cancel := context.WithCancel(s.ctx)
+	taskScheduler := NewTaskScheduler(
+		func(ctx context.Context) error {
+			return s.SetupNats(ctx, task.ID, natsConfig)
+		},
+		s.logger,
+		s.db,
+		s.jq,
+		s.cfg,
+		task.ID,
+		task.ResultType,
+		natsConfig,
+		task.Interval,
+		task.Timeout)
+	taskScheduler.Run(taskCtx)
+	s.runningTasks[task.ID] = cancel
+	return nil
+}
+
+// StopTask stops the publisher and the result consumer of the task
+func (s *MainScheduler) StopTask(taskID string) {
+	s.runningTasksMu.Lock()
+	defer s.runningTasksMu.Unlock()
+
+	if cancel, ok := s.runningTasks[taskID]; ok {
+		cancel()
+		delete(s.runningTasks, taskID)
+	}
+}
+
+// DeleteNats removes the consumers of the task and its stream, a stream shared with other tasks is kept
+func (s *MainScheduler) DeleteNats(ctx context.Context, task models.Task) error {
+	natsConfig, err := taskNatsConfig(task)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := s.db.GetTaskList()
+	if err != nil {
+		return err
+	}
+	shared := false
+	for _, t := range tasks {
+		if t.ID == task.ID {
+			continue
 		}
-		if err := json.Unmarshal(task.NatsConfig.Bytes, &natsConfig); err != nil {
-			return fmt.Errorf("failed to unmarshal JSONB: %w", err)
+		if other, err := taskNatsConfig(t); err == nil && other.Stream == natsConfig.Stream {
+			shared = true
+			break
 		}
+	}
 
-		err = s.SetupNats(ctx, task.ID, natsConfig)
-		if err != nil {
-			s.logger.Error("Failed to setup nats streams", zap.Error(err))
+	if !shared {
+		s.logger.Info("Deleting stream", zap.String("task", task.ID), zap.String("stream", natsConfig.Stream))
+		if err := s.js.DeleteStream(ctx, natsConfig.Stream); err != nil && !errors.Is(err, jetstream.ErrStreamNotFound) {
+			return err
+		}
+		return nil
+	}
+	for _, consumer := range []string{natsConfig.Consumer, natsConfig.ResultConsumer} {
+		s.logger.Info("Deleting consumer", zap.String("task", task.ID), zap.String("stream", natsConfig.Stream),
+			zap.String("consumer", consumer))
+		if err := s.js.DeleteConsumer(ctx, natsConfig.Stream, consumer); err != nil && !errors.Is(err, jetstream.ErrConsumerNotFound) {
 			return err
 		}
-
-		taskScheduler := NewTaskScheduler(
-			func(ctx context.Context) error {
-				return s.SetupNats(ctx, task.ID, natsConfig)
-			},
-			s.logger,
-			s.db,
-			s.jq,
-			s.cfg,
-			task.ID,
-			task.ResultType,
-			natsConfig,
-			task.Interval,
-			task.Timeout)
-		taskScheduler.Run(ctx)
-		RunningTasks[task.ID] = true
 	}
 	return nil
 }
 
+func taskNatsConfig(task models.Task) (NatsConfig, error) {
+	var natsConfig NatsConfig
+	if task.NatsConfig.Status != pgtype.Present {
+		return NatsConfig{}, fmt.Errorf("JSONB data is not present")
+	}
+	if err := json.Unmarshal(task.NatsConfig.Bytes, &natsConfig); err != nil {
+		return NatsConfig{}, fmt.Errorf("failed to unmarshal JSONB: %w", err)
+	}
+	return natsConfig, nil
+}
+
 func (s *MainScheduler) SetupNats(ctx context.Context, taskID string, natsConfig NatsConfig) error {
 	s.logger.Info("Subscribing to stream", zap.String("task", taskID), zap.String("stream", natsConfig.Stream),
 		zap.Strings("topics", []string{natsConfig.Topic, natsConfig.ResultTopic}))
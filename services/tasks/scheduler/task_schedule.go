@@ -12,11 +12,11 @@ import (
 )
 
 type NatsConfig struct {
-	Stream         string
-	Topic          string
-	ResultTopic    string
-	Consumer       string
-	ResultConsumer string
+	Stream         string `json:"stream"`
+	Topic          string `json:"topic"`
+	ResultTopic    string `json:"result_topic"`
+	Consumer       string `json:"consumer"`
+	ResultConsumer string `json:"result_consumer"`
 }
 
 type TaskScheduler struct {
@@ -66,7 +66,12 @@ func (s *TaskScheduler) Run(ctx context.Context) {
 		s.RunPublisher(ctx)
 	})
 	utils.EnsureRunGoroutine(func() {
-		s.logger.Fatal("RunTaskResponseConsumer exited", zap.Error(s.RunTaskResponseConsumer(ctx)))
+		err := s.RunTaskResponseConsumer(ctx)
+		if ctx.Err() != nil {
+			s.logger.Info("task scheduler stopped", zap.String("task", s.TaskID))
+			return
+		}
+		s.logger.Fatal("RunTaskResponseConsumer exited", zap.Error(err))
 	})
 }
 
@@ -76,10 +81,15 @@ func (s *TaskScheduler) RunPublisher(ctx context.Context) {
 	t := ticker.NewTicker(time.Second*10, time.Second*10)
 	defer t.Stop()
 
-	for ; ; <-t.C {
+	for {
 		if err := s.runPublisher(ctx); err != nil {
 			s.logger.Error("failed to run compliance publisher", zap.Error(err))
-			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
 		}
 	}
 }
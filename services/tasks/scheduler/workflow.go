@@ -0,0 +1,254 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/opengovern/og-util/pkg/ticker"
+	"github.com/opengovern/opencomply/services/tasks/api"
+	"github.com/opengovern/opencomply/services/tasks/db/models"
+	"github.com/opengovern/opencomply/services/tasks/worker"
+	"github.com/opengovern/opencomply/services/tasks/workflow"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+const workflowSchedulerLoop = "workflow-scheduler"
+
+// RunWorkflowScheduler advances the running workflow runs, it starts the task runs of the steps whose dependencies
+// are done and retries or fails the steps whose task runs failed. Only the leader replica advances the runs.
+func (s *MainScheduler) RunWorkflowScheduler(ctx context.Context) {
+	s.logger.Info("Scheduling workflow runs on a timer")
+
+	t := ticker.NewTicker(time.Second*10, time.Second*10)
+	defer t.Stop()
+
+	for {
+		if s.leader.IsLeader(workflowSchedulerLoop) {
+			s.advanceWorkflowRuns()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *MainScheduler) advanceWorkflowRuns() {
+	runs, err := s.db.ListRunningWorkflowRuns()
+	if err != nil {
+		s.logger.Error("failed to list running workflow runs", zap.Error(err))
+		return
+	}
+	for _, run := range runs {
+		if err := s.advanceWorkflowRun(run); err != nil {
+			s.logger.Error("failed to advance workflow run", zap.String("workflow", run.WorkflowID),
+				zap.Uint("runId", run.ID), zap.Error(err))
+		}
+	}
+}
+
+func (s *MainScheduler) advanceWorkflowRun(run models.WorkflowRun) error {
+	var steps []api.WorkflowStep
+	if err := json.Unmarshal(run.Steps.Bytes, &steps); err != nil {
+		return s.db.UpdateWorkflowRunStatus(run.ID, models.WorkflowRunStatusFailed, "failed to read workflow steps")
+	}
+	order, err := workflow.Order(steps)
+	if err != nil {
+		return s.db.UpdateWorkflowRunStatus(run.ID, models.WorkflowRunStatusFailed, err.Error())
+	}
+	params, err := JSONBToMap(run.Params)
+	if err != nil {
+		return s.db.UpdateWorkflowRunStatus(run.ID, models.WorkflowRunStatusFailed, "failed to read workflow params")
+	}
+
+	states, err := s.db.ListWorkflowRunSteps(run.ID)
+	if err != nil {
+		return err
+	}
+	stepByID := make(map[string]api.WorkflowStep)
+	for _, step := range steps {
+		stepByID[step.ID] = step
+	}
+	stateByID := make(map[string]*models.WorkflowRunStep)
+	for i := range states {
+		stateByID[states[i].StepID] = &states[i]
+	}
+
+	// the steps are advanced in dependency order so the dependents of a finished step start in the same cycle
+	finished := make(map[string]workflow.StepState)
+	for _, id := range order {
+		step, state := stepByID[id], stateByID[id]
+		if state == nil {
+			return s.db.UpdateWorkflowRunStatus(run.ID, models.WorkflowRunStatusFailed,
+				fmt.Sprintf("state of step %s is missing", id))
+		}
+
+		switch state.Status {
+		case models.WorkflowStepStatusRunning:
+			err = s.checkWorkflowStep(step, state)
+		case models.WorkflowStepStatusPending:
+			err = s.startWorkflowStep(step, state, stepByID, stateByID, workflow.TemplateData(params, finished))
+		}
+		if err != nil {
+			return err
+		}
+
+		if isStepFinished(state.Status) {
+			var result any
+			if state.Result.Status == pgtype.Present {
+				_ = json.Unmarshal(state.Result.Bytes, &result)
+			}
+			finished[id] = workflow.StepState{
+				Status: string(state.Status),
+				Result: result,
+			}
+		}
+	}
+
+	var failedStep string
+	for _, id := range order {
+		state := stateByID[id]
+		if !isStepFinished(state.Status) {
+			return nil
+		}
+		if state.Status == models.WorkflowStepStatusFailed && !stepByID[id].ContinueOnFailure && failedStep == "" {
+			failedStep = id
+		}
+	}
+	if failedStep != "" {
+		return s.db.UpdateWorkflowRunStatus(run.ID, models.WorkflowRunStatusFailed,
+			fmt.Sprintf("step %s failed: %s", failedStep, stateByID[failedStep].FailureMessage))
+	}
+	return s.db.UpdateWorkflowRunStatus(run.ID, models.WorkflowRunStatusSucceeded, "")
+}
+
+// startWorkflowStep starts the task run of the step once its dependencies are done, the step is skipped if a
+// dependency was skipped or failed or if its condition is false
+func (s *MainScheduler) startWorkflowStep(step api.WorkflowStep, state *models.WorkflowRunStep,
+	stepByID map[string]api.WorkflowStep, stateByID map[string]*models.WorkflowRunStep, data map[string]any) error {
+	for _, dep := range step.DependsOn {
+		switch stateByID[dep].Status {
+		case models.WorkflowStepStatusPending, models.WorkflowStepStatusRunning:
+			return nil
+		case models.WorkflowStepStatusSkipped:
+			return s.finishWorkflowStep(state, models.WorkflowStepStatusSkipped, fmt.Sprintf("dependency %s was skipped", dep))
+		case models.WorkflowStepStatusFailed:
+			if !stepByID[dep].ContinueOnFailure {
+				return s.finishWorkflowStep(state, models.WorkflowStepStatusSkipped, fmt.Sprintf("dependency %s failed", dep))
+			}
+		}
+	}
+
+	ok, err := workflow.EvaluateCondition(step, data)
+	if err != nil {
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusFailed, err.Error())
+	}
+	if !ok {
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusSkipped, "condition is false")
+	}
+
+	params, err := workflow.RenderParams(step, data)
+	if err != nil {
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusFailed, err.Error())
+	}
+	task, err := s.db.GetTask(step.TaskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusFailed, fmt.Sprintf("task %s does not exist", step.TaskID))
+	}
+	var paramsSchema map[string]any
+	if task.ParamsSchema.Status == pgtype.Present {
+		if err := json.Unmarshal(task.ParamsSchema.Bytes, &paramsSchema); err != nil {
+			return s.finishWorkflowStep(state, models.WorkflowStepStatusFailed, "failed to read the params schema of the task")
+		}
+	}
+	if err := worker.ValidateParams(paramsSchema, params); err != nil {
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusFailed, fmt.Sprintf("invalid params: %s", err.Error()))
+	}
+
+	var paramsJsonb pgtype.JSONB
+	paramsJson, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	if err := paramsJsonb.Set(paramsJson); err != nil {
+		return err
+	}
+	return s.startWorkflowStepTaskRun(step, state, paramsJsonb)
+}
+
+// checkWorkflowStep finishes the step when its task run is done, failed task runs are retried up to the max retries
+// of the step with the same params
+func (s *MainScheduler) checkWorkflowStep(step api.WorkflowStep, state *models.WorkflowRunStep) error {
+	if state.TaskRunID == nil {
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusFailed, "task run is missing")
+	}
+	taskRun, err := s.db.GetTaskRun(*state.TaskRunID)
+	if err != nil {
+		return err
+	}
+	if taskRun == nil {
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusFailed, "task run is missing")
+	}
+
+	switch taskRun.Status {
+	case models.TaskRunStatusFinished:
+		state.Result = taskRun.Result
+		if state.Result.Status != pgtype.Present {
+			_ = state.Result.Set([]byte("{}"))
+		}
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusSucceeded, "")
+	case models.TaskRunStatusFailed, models.TaskRunStatusTimeout:
+		if workflow.ShouldRetry(step, state.Attempts) {
+			s.logger.Info("retrying workflow step", zap.String("step", step.ID), zap.Uint("taskRunId", taskRun.ID),
+				zap.Int("attempt", state.Attempts+1))
+			return s.startWorkflowStepTaskRun(step, state, taskRun.Params)
+		}
+		return s.finishWorkflowStep(state, models.WorkflowStepStatusFailed,
+			fmt.Sprintf("task run %d %s: %s", taskRun.ID, taskRun.Status, taskRun.FailureMessage))
+	default:
+		return nil
+	}
+}
+
+func (s *MainScheduler) startWorkflowStepTaskRun(step api.WorkflowStep, state *models.WorkflowRunStep, params pgtype.JSONB) error {
+	taskRun := models.TaskRun{
+		TaskID: step.TaskID,
+		Status: models.TaskRunStatusCreated,
+		Params: params,
+	}
+	if err := taskRun.Result.Set([]byte("{}")); err != nil {
+		return err
+	}
+	if err := s.db.CreateTaskRun(&taskRun); err != nil {
+		return err
+	}
+
+	state.Status = models.WorkflowStepStatusRunning
+	state.Attempts++
+	state.TaskRunID = &taskRun.ID
+	state.FailureMessage = ""
+	return s.db.UpdateWorkflowRunStep(state)
+}
+
+func (s *MainScheduler) finishWorkflowStep(state *models.WorkflowRunStep, status models.WorkflowStepStatus, message string) error {
+	state.Status = status
+	state.FailureMessage = message
+	return s.db.UpdateWorkflowRunStep(state)
+}
+
+func isStepFinished(status models.WorkflowStepStatus) bool {
+	switch status {
+	case models.WorkflowStepStatusSucceeded, models.WorkflowStepStatusFailed, models.WorkflowStepStatusSkipped:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,154 @@
+package tasks
+
+import (
+	"encoding/json"
+
+	"github.com/jackc/pgtype"
+	"github.com/opengovern/opencomply/services/tasks/api"
+	"github.com/opengovern/opencomply/services/tasks/db/models"
+	"github.com/opengovern/opencomply/services/tasks/worker"
+)
+
+func taskFromDefinition(definition api.TaskDefinition) worker.Task {
+	return worker.Task{
+		ID:           definition.ID,
+		Name:         definition.Name,
+		Description:  definition.Description,
+		ImageURL:     definition.ImageURL,
+		Command:      definition.Command,
+		ResultType:   definition.ResultType,
+		WorkloadType: worker.WorkloadType(definition.WorkloadType),
+		EnvVars:      definition.EnvVars,
+		Interval:     definition.Interval,
+		Timeout:      definition.Timeout,
+		NatsConfig:   worker.NatsConfig(definition.NatsConfig),
+		ScaleConfig:  worker.ScaleConfig(definition.ScaleConfig),
+		ParamsSchema: definition.ParamsSchema,
+		ResultSchema: resultSchemaFromApi(definition.ResultSchema),
+	}
+}
+
+func taskToDefinition(task worker.Task, source models.TaskSource) api.TaskDefinition {
+	return api.TaskDefinition{
+		ID:           task.ID,
+		Name:         task.Name,
+		Description:  task.Description,
+		ImageURL:     task.ImageURL,
+		Command:      task.Command,
+		ResultType:   task.ResultType,
+		WorkloadType: string(task.WorkloadType),
+		EnvVars:      task.EnvVars,
+		Interval:     task.Interval,
+		Timeout:      task.Timeout,
+		NatsConfig:   api.NatsConfig(task.NatsConfig),
+		ScaleConfig:  api.ScaleConfig(task.ScaleConfig),
+		ParamsSchema: task.ParamsSchema,
+		ResultSchema: resultSchemaToApi(task.ResultSchema),
+		Source:       string(source),
+	}
+}
+
+func resultSchemaFromApi(schema *api.ResultSchema) *worker.ResultSchema {
+	if schema == nil {
+		return nil
+	}
+	res := worker.ResultSchema{}
+	for _, column := range schema.Columns {
+		res.Columns = append(res.Columns, worker.ResultColumn(column))
+	}
+	return &res
+}
+
+func resultSchemaToApi(schema *worker.ResultSchema) *api.ResultSchema {
+	if schema == nil {
+		return nil
+	}
+	res := api.ResultSchema{}
+	for _, column := range schema.Columns {
+		res.Columns = append(res.Columns, api.ResultColumn(column))
+	}
+	return &res
+}
+
+func newTaskModel(task worker.Task, source models.TaskSource) (*models.Task, error) {
+	natsJsonb, err := toJSONB(task.NatsConfig)
+	if err != nil {
+		return nil, err
+	}
+	scaleJsonb, err := toJSONB(task.ScaleConfig)
+	if err != nil {
+		return nil, err
+	}
+	envVarsJsonb, err := toJSONB(task.EnvVars)
+	if err != nil {
+		return nil, err
+	}
+	paramsSchemaJsonb, err := toJSONB(task.ParamsSchema)
+	if err != nil {
+		return nil, err
+	}
+	resultSchemaJsonb, err := toJSONB(task.ResultSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Task{
+		ID:           task.ID,
+		Name:         task.Name,
+		ResultType:   task.ResultType,
+		Description:  task.Description,
+		ImageUrl:     task.ImageURL,
+		Command:      task.Command,
+		WorkloadType: string(task.WorkloadType),
+		EnvVars:      envVarsJsonb,
+		Interval:     task.Interval,
+		Timeout:      task.Timeout,
+		NatsConfig:   natsJsonb,
+		ScaleConfig:  scaleJsonb,
+		ParamsSchema: paramsSchemaJsonb,
+		ResultSchema: resultSchemaJsonb,
+		Source:       source,
+	}, nil
+}
+
+func taskFromModel(task models.Task) (worker.Task, error) {
+	t := worker.Task{
+		ID:           task.ID,
+		Name:         task.Name,
+		Description:  task.Description,
+		ImageURL:     task.ImageUrl,
+		Command:      task.Command,
+		ResultType:   task.ResultType,
+		WorkloadType: worker.WorkloadType(task.WorkloadType),
+		Interval:     task.Interval,
+		Timeout:      task.Timeout,
+	}
+	for _, field := range []struct {
+		value pgtype.JSONB
+		dest  any
+	}{
+		{task.NatsConfig, &t.NatsConfig},
+		{task.ScaleConfig, &t.ScaleConfig},
+		{task.EnvVars, &t.EnvVars},
+		{task.ParamsSchema, &t.ParamsSchema},
+		{task.ResultSchema, &t.ResultSchema},
+	} {
+		if field.value.Status != pgtype.Present {
+			continue
+		}
+		if err := json.Unmarshal(field.value.Bytes, field.dest); err != nil {
+			return worker.Task{}, err
+		}
+	}
+	return t, nil
+}
+
+func toJSONB(v any) (pgtype.JSONB, error) {
+	var jsonb pgtype.JSONB
+	data, err := json.Marshal(v)
+	if err != nil {
+		return jsonb, err
+	}
+	err = jsonb.Set(data)
+	return jsonb, err
+}
@@ -7,35 +7,53 @@ const (
 )
 
 type NatsConfig struct {
-	Stream         string `yaml:"Stream"`
-	Topic          string `yaml:"Topic"`
-	Consumer       string `yaml:"Consumer"`
-	ResultTopic    string `yaml:"ResultTopic"`
-	ResultConsumer string `yaml:"ResultConsumer"`
+	Stream         string `yaml:"Stream" json:"stream,omitempty"`
+	Topic          string `yaml:"Topic" json:"topic,omitempty"`
+	Consumer       string `yaml:"Consumer" json:"consumer,omitempty"`
+	ResultTopic    string `yaml:"ResultTopic" json:"result_topic,omitempty"`
+	ResultConsumer string `yaml:"ResultConsumer" json:"result_consumer,omitempty"`
 }
 
 type ScaleConfig struct {
-	Stream       string `yaml:"Stream"`
-	Consumer     string `yaml:"Consumer"`
-	LagThreshold string `yaml:"LagThreshold"`
-	MinReplica   int32  `yaml:"MinReplica"`
-	MaxReplica   int32  `yaml:"MaxReplica"`
-
-	PollingInterval int32 `yaml:"PollingInterval"`
-	CooldownPeriod  int32 `yaml:"CooldownPeriod"`
+	Stream       string `yaml:"Stream" json:"stream,omitempty"`
+	Consumer     string `yaml:"Consumer" json:"consumer,omitempty"`
+	LagThreshold string `yaml:"LagThreshold" json:"lag_threshold"`
+	MinReplica   int32  `yaml:"MinReplica" json:"min_replica"`
+	MaxReplica   int32  `yaml:"MaxReplica" json:"max_replica"`
+
+	PollingInterval int32 `yaml:"PollingInterval" json:"polling_interval,omitempty"`
+	CooldownPeriod  int32 `yaml:"CooldownPeriod" json:"cooldown_period,omitempty"`
 }
 
 type Task struct {
-	ID           string            `yaml:"ID"`
-	Name         string            `yaml:"Name"`
-	Description  string            `yaml:"Description"`
-	ImageURL     string            `yaml:"ImageURL"`
-	Command      string            `yaml:"Command"`
-	ResultType   string            `yaml:"ResultType"`
-	WorkloadType WorkloadType      `yaml:"WorkloadType"`
-	EnvVars      map[string]string `yaml:"EnvVars"`
-	Interval     uint64            `yaml:"Interval"`
-	Timeout      uint64            `yaml:"Timeout"`
-	NatsConfig   NatsConfig        `yaml:"NatsConfig"`
-	ScaleConfig  ScaleConfig       `yaml:"ScaleConfig"`
+	ID           string            `yaml:"ID" json:"id"`
+	Name         string            `yaml:"Name" json:"name"`
+	Description  string            `yaml:"Description" json:"description"`
+	ImageURL     string            `yaml:"ImageURL" json:"image_url"`
+	Command      string            `yaml:"Command" json:"command"`
+	ResultType   string            `yaml:"ResultType" json:"result_type"`
+	WorkloadType WorkloadType      `yaml:"WorkloadType" json:"workload_type"`
+	EnvVars      map[string]string `yaml:"EnvVars" json:"env_vars,omitempty"`
+	Interval     uint64            `yaml:"Interval" json:"interval"`
+	Timeout      uint64            `yaml:"Timeout" json:"timeout"`
+	NatsConfig   NatsConfig        `yaml:"NatsConfig" json:"nats_config"`
+	ScaleConfig  ScaleConfig       `yaml:"ScaleConfig" json:"scale_config"`
+	// ParamsSchema is the JSON schema the params of the task runs are validated against
+	ParamsSchema map[string]any `yaml:"ParamsSchema" json:"params_schema,omitempty"`
+	// ResultSchema describes the results of the task, CloudQL exposes them as the platform_task_<result_type> table
+	ResultSchema *ResultSchema `yaml:"ResultSchema" json:"result_schema,omitempty"`
+}
+
+type ResultSchema struct {
+	Columns []ResultColumn `yaml:"Columns" json:"columns"`
+}
+
+type ResultColumn struct {
+	Name        string `yaml:"Name" json:"name"`
+	Type        string `yaml:"Type" json:"type"`
+	Description string `yaml:"Description" json:"description,omitempty"`
+	// Field is the path of the value in the result document, defaults to description.<name>
+	Field string `yaml:"Field" json:"field,omitempty"`
+	// KeyColumn pushes the equality quals on the column down to the result index
+	KeyColumn bool `yaml:"KeyColumn" json:"key_column,omitempty"`
 }
@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opengovern/opencomply/services/tasks/api"
+	"github.com/opengovern/opencomply/services/tasks/config"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// taskSchema is the JSON schema of the task definitions, the names and the ids of the streams, consumers and topics
+// are used as Kubernetes resource and NATS subject names
+const taskSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["id", "name", "image_url", "command", "result_type", "workload_type", "scale_config"],
+  "properties": {
+    "id": {"type": "string", "pattern": "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$", "maxLength": 63},
+    "name": {"type": "string", "minLength": 1},
+    "description": {"type": "string"},
+    "image_url": {"type": "string", "pattern": "^[^\\s]+$"},
+    "command": {"type": "string", "minLength": 1},
+    "result_type": {"type": "string", "pattern": "^[a-z0-9_]+$"},
+    "workload_type": {"type": "string", "enum": ["deployment"]},
+    "env_vars": {
+      "type": "object",
+      "propertyNames": {"pattern": "^[A-Za-z_][A-Za-z0-9_]*$"},
+      "additionalProperties": {"type": "string"}
+    },
+    "interval": {"type": "integer", "minimum": 0},
+    "timeout": {"type": "integer", "minimum": 1},
+    "nats_config": {
+      "type": "object",
+      "properties": {
+        "stream": {"$ref": "#/definitions/natsName"},
+        "topic": {"$ref": "#/definitions/natsName"},
+        "consumer": {"$ref": "#/definitions/natsName"},
+        "result_topic": {"$ref": "#/definitions/natsName"},
+        "result_consumer": {"$ref": "#/definitions/natsName"}
+      }
+    },
+    "scale_config": {
+      "type": "object",
+      "required": ["lag_threshold", "max_replica"],
+      "properties": {
+        "stream": {"$ref": "#/definitions/natsName"},
+        "consumer": {"$ref": "#/definitions/natsName"},
+        "lag_threshold": {"type": "string", "pattern": "^[1-9][0-9]*$"},
+        "min_replica": {"type": "integer", "minimum": 0},
+        "max_replica": {"type": "integer", "minimum": 1},
+        "polling_interval": {"type": "integer", "minimum": 0},
+        "cooldown_period": {"type": "integer", "minimum": 0}
+      }
+    },
+    "params_schema": {"type": "object"},
+    "result_schema": {
+      "type": "object",
+      "required": ["columns"],
+      "properties": {
+        "columns": {
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "type": "object",
+            "required": ["name", "type"],
+            "properties": {
+              "name": {"type": "string", "pattern": "^[a-z][a-z0-9_]*$"},
+              "type": {"type": "string", "enum": ["string", "int", "double", "bool", "timestamp", "json", "ipaddr", "cidr"]},
+              "description": {"type": "string"},
+              "field": {"type": "string", "minLength": 1},
+              "key_column": {"type": "boolean"}
+            }
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "natsName": {"type": "string", "pattern": "^[A-Za-z0-9_-]+$"}
+  }
+}`
+
+var taskSchemaLoader = gojsonschema.NewStringLoader(taskSchema)
+
+// ValidateTask validates the task definition against the task schema and its params schema against the JSON
+// schema specification
+func ValidateTask(cfg config.Config, task Task) error {
+	taskJson, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	result, err := gojsonschema.Validate(taskSchemaLoader, gojsonschema.NewBytesLoader(taskJson))
+	if err != nil {
+		return fmt.Errorf("failed to validate task: %w", err)
+	}
+	if !result.Valid() {
+		return schemaErrors(result)
+	}
+
+	if task.ScaleConfig.MinReplica > task.ScaleConfig.MaxReplica {
+		return errors.New("scale_config.min_replica must not be greater than scale_config.max_replica")
+	}
+	for _, env := range defaultEnvs(cfg, &task) {
+		if _, ok := task.EnvVars[env.Name]; ok {
+			return fmt.Errorf("env_vars: %s is reserved", env.Name)
+		}
+	}
+	if task.ParamsSchema != nil {
+		if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(task.ParamsSchema)); err != nil {
+			return fmt.Errorf("params_schema: %w", err)
+		}
+	}
+	if task.ResultSchema != nil {
+		columns := make(map[string]bool)
+		for _, column := range task.ResultSchema.Columns {
+			if _, ok := api.ReservedResultColumns[column.Name]; ok {
+				return fmt.Errorf("result_schema: column %s is reserved", column.Name)
+			}
+			if columns[column.Name] {
+				return fmt.Errorf("result_schema: column %s is defined more than once", column.Name)
+			}
+			columns[column.Name] = true
+		}
+	}
+	return nil
+}
+
+// ValidateParams validates the params of a task run against the params schema of the task
+func ValidateParams(paramsSchema map[string]any, params map[string]any) error {
+	if paramsSchema == nil {
+		return nil
+	}
+	if params == nil {
+		params = map[string]any{}
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(paramsSchema), gojsonschema.NewGoLoader(params))
+	if err != nil {
+		return fmt.Errorf("failed to validate params: %w", err)
+	}
+	if !result.Valid() {
+		return schemaErrors(result)
+	}
+	return nil
+}
+
+func schemaErrors(result *gojsonschema.Result) error {
+	var msgs []string
+	for _, e := range result.Errors() {
+		msgs = append(msgs, e.String())
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
@@ -0,0 +1,70 @@
+package tasks
+
+import (
+	"encoding/json"
+
+	"github.com/jackc/pgtype"
+	"github.com/opengovern/opencomply/services/tasks/api"
+	"github.com/opengovern/opencomply/services/tasks/db/models"
+)
+
+func newWorkflowModel(definition api.WorkflowDefinition) (*models.Workflow, error) {
+	stepsJsonb, err := toJSONB(definition.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Workflow{
+		ID:          definition.ID,
+		Name:        definition.Name,
+		Description: definition.Description,
+		Steps:       stepsJsonb,
+	}, nil
+}
+
+func workflowFromModel(workflow models.Workflow) (api.WorkflowDefinition, error) {
+	definition := api.WorkflowDefinition{
+		ID:          workflow.ID,
+		Name:        workflow.Name,
+		Description: workflow.Description,
+	}
+	if workflow.Steps.Status == pgtype.Present {
+		if err := json.Unmarshal(workflow.Steps.Bytes, &definition.Steps); err != nil {
+			return api.WorkflowDefinition{}, err
+		}
+	}
+	return definition, nil
+}
+
+func workflowRunFromModel(run models.WorkflowRun, steps []models.WorkflowRunStep) (api.WorkflowRun, error) {
+	r := api.WorkflowRun{
+		ID:             run.ID,
+		CreatedAt:      run.CreatedAt,
+		UpdatedAt:      run.UpdatedAt,
+		WorkflowID:     run.WorkflowID,
+		Status:         string(run.Status),
+		FailureMessage: run.FailureMessage,
+	}
+	if run.Params.Status == pgtype.Present {
+		if err := json.Unmarshal(run.Params.Bytes, &r.Params); err != nil {
+			return api.WorkflowRun{}, err
+		}
+	}
+
+	for _, step := range steps {
+		s := api.WorkflowRunStep{
+			StepID:         step.StepID,
+			Status:         string(step.Status),
+			Attempts:       step.Attempts,
+			TaskRunID:      step.TaskRunID,
+			FailureMessage: step.FailureMessage,
+			UpdatedAt:      step.UpdatedAt,
+		}
+		// results that are not objects are left out
+		if step.Result.Status == pgtype.Present {
+			_ = json.Unmarshal(step.Result.Bytes, &s.Result)
+		}
+		r.Steps = append(r.Steps, s)
+	}
+	return r, nil
+}
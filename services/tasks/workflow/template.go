@@ -0,0 +1,164 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/opengovern/opencomply/services/tasks/api"
+)
+
+var templateFuncs = template.FuncMap{
+	"toJson": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// StepState is the state of a finished step exposed to the templates of the steps depending on it
+type StepState struct {
+	Status string `json:"status"`
+	Result any    `json:"result"`
+}
+
+// TemplateData is the data the params and the conditions of the steps are rendered with
+func TemplateData(params map[string]any, steps map[string]StepState) map[string]any {
+	stepsData := make(map[string]any, len(steps))
+	for id, state := range steps {
+		stepsData[id] = map[string]any{
+			"status": state.Status,
+			"result": state.Result,
+		}
+	}
+	return map[string]any{
+		"params": params,
+		"steps":  stepsData,
+	}
+}
+
+// RenderParams renders the templates of the step params
+func RenderParams(step api.WorkflowStep, data map[string]any) (map[string]any, error) {
+	params := make(map[string]any, len(step.Params))
+	for k, v := range step.Params {
+		rendered, err := renderValue(v, data)
+		if err != nil {
+			return nil, fmt.Errorf("params.%s: %w", k, err)
+		}
+		params[k] = rendered
+	}
+	return params, nil
+}
+
+// EvaluateCondition reports whether the step should run, steps without a condition always run
+func EvaluateCondition(step api.WorkflowStep, data map[string]any) (bool, error) {
+	if step.Condition == "" {
+		return true, nil
+	}
+	out, err := render(step.Condition, data)
+	if err != nil {
+		return false, fmt.Errorf("condition: %w", err)
+	}
+	return strings.TrimSpace(out) == "true", nil
+}
+
+func renderValue(v any, data map[string]any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		out, err := render(v, data)
+		if err != nil {
+			return nil, err
+		}
+		if isSingleAction(v) {
+			var decoded any
+			if json.Unmarshal([]byte(out), &decoded) == nil {
+				return decoded, nil
+			}
+		}
+		return out, nil
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			rendered, err := renderValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = rendered
+		}
+		return m, nil
+	case []any:
+		l := make([]any, 0, len(v))
+		for _, item := range v {
+			rendered, err := renderValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, rendered)
+		}
+		return l, nil
+	default:
+		return v, nil
+	}
+}
+
+func render(text string, data map[string]any) (string, error) {
+	tmpl, err := parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func parse(text string) (*template.Template, error) {
+	// missing results fail the step instead of rendering "<no value>"
+	return template.New("").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+func isSingleAction(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.HasPrefix(text, "{{") && strings.HasSuffix(text, "}}") && strings.Count(text, "{{") == 1
+}
+
+func parseTemplates(step api.WorkflowStep) error {
+	if step.Condition != "" {
+		if _, err := parse(step.Condition); err != nil {
+			return fmt.Errorf("condition: %w", err)
+		}
+	}
+	var walk func(path string, v any) error
+	walk = func(path string, v any) error {
+		switch v := v.(type) {
+		case string:
+			if _, err := parse(v); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		case map[string]any:
+			for k, item := range v {
+				if err := walk(path+"."+k, item); err != nil {
+					return err
+				}
+			}
+		case []any:
+			for i, item := range v {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for k, v := range step.Params {
+		if err := walk("params."+k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package workflow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opengovern/opencomply/services/tasks/api"
+)
+
+func testTemplateData() map[string]any {
+	return TemplateData(
+		map[string]any{"image": "nginx:1.27", "severity": "critical"},
+		map[string]StepState{
+			"scan": {
+				Status: "succeeded",
+				Result: map[string]any{
+					"cves":  []any{"CVE-2024-0001", "CVE-2024-0002"},
+					"count": 2,
+				},
+			},
+		},
+	)
+}
+
+func TestRenderParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]any
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name:   "plain values",
+			params: map[string]any{"image": "alpine", "limit": 10, "enabled": true},
+			want:   map[string]any{"image": "alpine", "limit": 10, "enabled": true},
+		},
+		{
+			name:   "workflow param",
+			params: map[string]any{"image": "{{ .params.image }}"},
+			want:   map[string]any{"image": "nginx:1.27"},
+		},
+		{
+			name:   "text around the template",
+			params: map[string]any{"title": "CVEs of {{ .params.image }}"},
+			want:   map[string]any{"title": "CVEs of nginx:1.27"},
+		},
+		{
+			name:   "single action decodes json",
+			params: map[string]any{"count": "{{ .steps.scan.result.count }}", "cves": "{{ toJson .steps.scan.result.cves }}"},
+			want:   map[string]any{"count": float64(2), "cves": []any{"CVE-2024-0001", "CVE-2024-0002"}},
+		},
+		{
+			name:   "single action keeps strings that are not json",
+			params: map[string]any{"severity": "{{ .params.severity }}"},
+			want:   map[string]any{"severity": "critical"},
+		},
+		{
+			name: "nested maps and lists",
+			params: map[string]any{
+				"filter": map[string]any{"images": []any{"{{ .params.image }}", "alpine"}},
+				"status": []any{"{{ .steps.scan.status }}"},
+			},
+			want: map[string]any{
+				"filter": map[string]any{"images": []any{"nginx:1.27", "alpine"}},
+				"status": []any{"succeeded"},
+			},
+		},
+		{
+			name:    "missing step result",
+			params:  map[string]any{"cves": "{{ .steps.scan.result.missing }}"},
+			wantErr: "params.cves",
+		},
+		{
+			name:    "missing param",
+			params:  map[string]any{"region": "{{ .params.region }}"},
+			wantErr: "params.region",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderParams(api.WorkflowStep{ID: "report", Params: tt.params}, testTemplateData())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+		wantErr   bool
+	}{
+		{name: "no condition", condition: "", want: true},
+		{name: "true", condition: "{{ gt (len .steps.scan.result.cves) 0 }}", want: true},
+		{name: "false", condition: "{{ eq .steps.scan.status \"failed\" }}", want: false},
+		{name: "surrounding whitespace", condition: "  {{ eq .params.severity \"critical\" }}\n", want: true},
+		{name: "not a boolean", condition: "{{ .params.image }}", want: false},
+		{name: "missing step", condition: "{{ .steps.fetch.status }}", wantErr: true},
+		{name: "invalid template", condition: "{{ if }}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateCondition(api.WorkflowStep{ID: "report", Condition: tt.condition}, testTemplateData())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/opengovern/opencomply/pkg/cloudql/utils/dag"
+	"github.com/opengovern/opencomply/services/tasks/api"
+)
+
+var (
+	// workflow ids are used in urls, step ids in templates (.steps.<step id>)
+	idPattern     = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	stepIDPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Validate validates the workflow definition, taskExists reports whether the task of a step is registered
+func Validate(definition api.WorkflowDefinition, taskExists func(taskID string) (bool, error)) error {
+	if !idPattern.MatchString(definition.ID) {
+		return fmt.Errorf("id: must match %s", idPattern.String())
+	}
+	if definition.Name == "" {
+		return errors.New("name: is required")
+	}
+	if len(definition.Steps) == 0 {
+		return errors.New("steps: at least one step is required")
+	}
+
+	steps := make(map[string]api.WorkflowStep)
+	for _, step := range definition.Steps {
+		if !stepIDPattern.MatchString(step.ID) {
+			return fmt.Errorf("steps.%s: id must match %s", step.ID, stepIDPattern.String())
+		}
+		if _, ok := steps[step.ID]; ok {
+			return fmt.Errorf("steps.%s: duplicate step id", step.ID)
+		}
+		steps[step.ID] = step
+	}
+
+	for _, step := range definition.Steps {
+		ok, err := taskExists(step.TaskID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("steps.%s: task %s does not exist", step.ID, step.TaskID)
+		}
+		for _, dep := range step.DependsOn {
+			if _, ok := steps[dep]; !ok {
+				return fmt.Errorf("steps.%s: depends on unknown step %s", step.ID, dep)
+			}
+		}
+		if step.MaxRetries < 0 {
+			return fmt.Errorf("steps.%s: max_retries must not be negative", step.ID)
+		}
+		if err := parseTemplates(step); err != nil {
+			return fmt.Errorf("steps.%s: %w", step.ID, err)
+		}
+	}
+
+	if _, err := Order(definition.Steps); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Order returns the ids of the steps in an order that runs every step after its dependencies
+func Order(steps []api.WorkflowStep) ([]string, error) {
+	graph := dag.NewDirectedAcyclicGraph()
+	for _, step := range steps {
+		graph.AddNodeIdempotent(step.ID)
+		// edges point to the dependencies so they are sorted before the steps depending on them
+		for _, dep := range step.DependsOn {
+			if dep == step.ID {
+				return nil, fmt.Errorf("steps.%s: depends on itself", step.ID)
+			}
+			graph.AddEdge(step.ID, dep)
+		}
+	}
+
+	order, err := graph.TopologicalSort()
+	if err != nil {
+		return nil, errors.New("steps: dependencies must not contain cycles")
+	}
+	// cycles that are not reachable from any step are left out of the sort
+	if len(order) != len(steps) {
+		return nil, errors.New("steps: dependencies must not contain cycles")
+	}
+	return order, nil
+}
+
+// ShouldRetry reports whether the step is retried after its given number of failed attempts, steps are attempted
+// once plus their max retries
+func ShouldRetry(step api.WorkflowStep, attempts int) bool {
+	return attempts <= step.MaxRetries
+}
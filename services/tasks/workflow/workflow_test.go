@@ -0,0 +1,222 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opengovern/opencomply/services/tasks/api"
+)
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []api.WorkflowStep
+		wantErr string
+	}{
+		{
+			name:  "single step",
+			steps: []api.WorkflowStep{{ID: "a"}},
+		},
+		{
+			name: "chain",
+			steps: []api.WorkflowStep{
+				{ID: "c", DependsOn: []string{"b"}},
+				{ID: "b", DependsOn: []string{"a"}},
+				{ID: "a"},
+			},
+		},
+		{
+			name: "diamond",
+			steps: []api.WorkflowStep{
+				{ID: "report", DependsOn: []string{"scan", "inventory"}},
+				{ID: "scan", DependsOn: []string{"fetch"}},
+				{ID: "inventory", DependsOn: []string{"fetch"}},
+				{ID: "fetch"},
+			},
+		},
+		{
+			name:  "independent steps",
+			steps: []api.WorkflowStep{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+		},
+		{
+			name:    "self dependency",
+			steps:   []api.WorkflowStep{{ID: "a", DependsOn: []string{"a"}}},
+			wantErr: "depends on itself",
+		},
+		{
+			name: "cycle",
+			steps: []api.WorkflowStep{
+				{ID: "a", DependsOn: []string{"c"}},
+				{ID: "b", DependsOn: []string{"a"}},
+				{ID: "c", DependsOn: []string{"b"}},
+			},
+			wantErr: "cycles",
+		},
+		{
+			name: "cycle unreachable from other steps",
+			steps: []api.WorkflowStep{
+				{ID: "a"},
+				{ID: "b", DependsOn: []string{"c"}},
+				{ID: "c", DependsOn: []string{"b"}},
+			},
+			wantErr: "cycles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := Order(tt.steps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(order) != len(tt.steps) {
+				t.Fatalf("expected %d steps, got %v", len(tt.steps), order)
+			}
+
+			position := make(map[string]int)
+			for i, id := range order {
+				position[id] = i
+			}
+			for _, step := range tt.steps {
+				for _, dep := range step.DependsOn {
+					if position[dep] > position[step.ID] {
+						t.Errorf("step %s is ordered before its dependency %s: %v", step.ID, dep, order)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	taskExists := func(taskID string) (bool, error) {
+		return taskID == "scan-task" || taskID == "report-task", nil
+	}
+	valid := func() api.WorkflowDefinition {
+		return api.WorkflowDefinition{
+			ID:   "oci-image-cve-findings",
+			Name: "OCI image CVE findings",
+			Steps: []api.WorkflowStep{
+				{ID: "scan", TaskID: "scan-task", Params: map[string]any{"image": "{{ .params.image }}"}},
+				{
+					ID:        "report",
+					TaskID:    "report-task",
+					DependsOn: []string{"scan"},
+					Condition: "{{ gt (len .steps.scan.result.cves) 0 }}",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(d *api.WorkflowDefinition)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(d *api.WorkflowDefinition) {},
+		},
+		{
+			name:    "invalid id",
+			modify:  func(d *api.WorkflowDefinition) { d.ID = "Invalid_ID" },
+			wantErr: "id: must match",
+		},
+		{
+			name:    "missing name",
+			modify:  func(d *api.WorkflowDefinition) { d.Name = "" },
+			wantErr: "name: is required",
+		},
+		{
+			name:    "no steps",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps = nil },
+			wantErr: "at least one step",
+		},
+		{
+			name:    "invalid step id",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps[0].ID = "scan-image" },
+			wantErr: "steps.scan-image: id must match",
+		},
+		{
+			name:    "duplicate step id",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps[1].ID = "scan" },
+			wantErr: "duplicate step id",
+		},
+		{
+			name:    "unknown task",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps[0].TaskID = "missing-task" },
+			wantErr: "task missing-task does not exist",
+		},
+		{
+			name:    "unknown dependency",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps[1].DependsOn = []string{"fetch"} },
+			wantErr: "depends on unknown step fetch",
+		},
+		{
+			name:    "negative max retries",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps[0].MaxRetries = -1 },
+			wantErr: "max_retries must not be negative",
+		},
+		{
+			name:    "invalid condition template",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps[1].Condition = "{{ .steps.scan" },
+			wantErr: "steps.report: condition",
+		},
+		{
+			name:    "invalid param template",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps[0].Params["tags"] = []any{"{{ end }}"} },
+			wantErr: "steps.scan: params.tags[0]",
+		},
+		{
+			name:    "cycle",
+			modify:  func(d *api.WorkflowDefinition) { d.Steps[0].DependsOn = []string{"report"} },
+			wantErr: "cycles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := valid()
+			tt.modify(&definition)
+			err := Validate(definition, taskExists)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		attempts   int
+		want       bool
+	}{
+		{name: "no retries after first attempt", maxRetries: 0, attempts: 1, want: false},
+		{name: "retry after first attempt", maxRetries: 2, attempts: 1, want: true},
+		{name: "retry after second attempt", maxRetries: 2, attempts: 2, want: true},
+		{name: "retries exhausted", maxRetries: 2, attempts: 3, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := api.WorkflowStep{ID: "scan", MaxRetries: tt.maxRetries}
+			if got := ShouldRetry(step, tt.attempts); got != tt.want {
+				t.Errorf("ShouldRetry(max_retries=%d, attempts=%d) = %v, want %v", tt.maxRetries, tt.attempts, got, tt.want)
+			}
+		})
+	}
+}